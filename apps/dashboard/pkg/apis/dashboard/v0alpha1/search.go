@@ -84,6 +84,8 @@ type FacetResult struct {
 	Missing int64 `json:"missing,omitempty"`
 	// Term facets
 	Terms []TermFacet `json:"terms,omitempty"`
+	// Range facets (for example the created/updated time buckets)
+	Ranges []RangeFacet `json:"ranges,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -91,3 +93,9 @@ type TermFacet struct {
 	Term  string `json:"term,omitempty"`
 	Count int64  `json:"count,omitempty"`
 }
+
+// +k8s:deepcopy-gen=true
+type RangeFacet struct {
+	Name  string `json:"name,omitempty"`
+	Count int64  `json:"count,omitempty"`
+}
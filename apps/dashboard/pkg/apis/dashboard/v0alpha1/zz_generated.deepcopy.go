@@ -178,6 +178,11 @@ func (in *FacetResult) DeepCopyInto(out *FacetResult) {
 		*out = make([]TermFacet, len(*in))
 		copy(*out, *in)
 	}
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]RangeFacet, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -332,6 +337,22 @@ func (in *LibraryPanelStatus) DeepCopy() *LibraryPanelStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RangeFacet) DeepCopyInto(out *RangeFacet) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RangeFacet.
+func (in *RangeFacet) DeepCopy() *RangeFacet {
+	if in == nil {
+		return nil
+	}
+	out := new(RangeFacet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SearchResults) DeepCopyInto(out *SearchResults) {
 	*out = *in
@@ -33,6 +33,9 @@ repository: {
 					// Whether we should show dashboard previews for pull requests.
 					// By default, this is false (i.e. we will not create previews).
 					generateDashboardPreviews?: bool
+					// Whether we should create a read-only preview folder with the dashboards of each pull request.
+					// The folder is removed when the pull request is merged or closed, when this is disabled or when the repository is deleted.
+					generatePreviewEnvironments?: bool
 					// Path is the subdirectory for the Grafana data. If specified, Grafana will ignore anything that is outside this directory in the repository.
 					path?: string
 				}
@@ -76,6 +79,38 @@ repository: {
 					// Path is the subdirectory for the Grafana data. If specified, Grafana will ignore anything that is outside this directory in the repository.
 					path?: string
 				}
+				#ValidationPolicies: {
+					// Tags that every dashboard must have
+					requiredTags?: [...string]
+					// The datasources (by uid or by type) the dashboards may query.
+					// When empty, any datasource is allowed.
+					allowedDatasources?: [...string]
+					// Panel types (plugin ids) the dashboards may not use
+					forbiddenPanelTypes?: [...string]
+					// The oldest dashboard schema version accepted
+					minSchemaVersion?: int
+					// The maximum number of panels in a dashboard, including the panels in collapsed rows
+					maxPanels?: int
+					// Regular expression the resource names must match
+					namePattern?: string
+				}
+				#CommitVerificationConfig: {
+					// Refuse to apply the changes of the commits without a verified GPG, SSH or S/MIME signature
+					requireSignedCommits: bool
+					// When not empty, the commits must also be committed by one of these GitHub users.
+					// GitHub checks the signature against the keys of the committer account, so any key added to the account is trusted
+					trustedSigners?: [...string]
+				}
+				#SignatureVerification: {
+					// The verified commit
+					ref?: string
+					// The commit has a valid signature, from a trusted signer when the signers are restricted
+					verified: bool
+					// The user who signed the commit
+					signer?: string
+					// Why the signature is not verified (e.g. unsigned, unknown_key, untrusted_signer)
+					reason?: string
+				}
 				#SyncOptions: {
 					// Enabled must be saved as true before any sync job will run
 					enabled: bool
@@ -110,6 +145,8 @@ repository: {
 					lastRef?: string
 					// Incremental synchronization for versioned repositories
 					incremental?: bool
+					// The signature verification of the synced commits, when the repository requires signed commits
+					verification?: #SignatureVerification
 				}
 				#ResourceCount: {
 					group:    string
@@ -152,6 +189,12 @@ repository: {
 					// The repository on GitLab.
 					// Mutually exclusive with local | github | git.
 					gitlab?: #GitLabRepositoryConfig
+					// Validation policies checked before the resources are written.
+					// The files that break a policy are not applied.
+					policies?: #ValidationPolicies
+					// Verification of the commit signatures before the changes are applied.
+					// Only supported by github repositories.
+					verification?: #CommitVerificationConfig
 				}
 				status: {
 					// The generation of the spec last time reconciliation ran
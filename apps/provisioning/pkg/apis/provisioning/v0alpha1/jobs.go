@@ -48,6 +48,9 @@ const (
 
 	// JobActionMove moves files in the remote repository
 	JobActionMove JobAction = "move"
+
+	// JobActionDrift compares the repository with the resources in grafana, without applying any change.
+	JobActionDrift JobAction = "drift"
 )
 
 // +enum
@@ -98,6 +101,9 @@ type JobSpec struct {
 
 	// Move when the action is `move`
 	Move *MoveJobOptions `json:"move,omitempty"`
+
+	// Drift when the action is `drift`
+	Drift *DriftJobOptions `json:"drift,omitempty"`
 }
 
 type PullRequestJobOptions struct {
@@ -112,6 +118,9 @@ type PullRequestJobOptions struct {
 
 	// URL to the originator (eg, PR URL)
 	URL string `json:"url,omitempty"`
+
+	// The pull request was merged or closed, so its preview environment is removed
+	Closed bool `json:"closed,omitempty"`
 }
 
 type SyncJobOptions struct {
@@ -191,6 +200,11 @@ type MoveJobOptions struct {
 	Resources []ResourceRef `json:"resources,omitempty"`
 }
 
+type DriftJobOptions struct {
+	// Ref to the branch or commit hash to compare (defaults to the configured branch)
+	Ref string `json:"ref,omitempty"`
+}
+
 // The job status
 type JobStatus struct {
 	State    JobState `json:"state,omitempty"`
@@ -47,6 +47,8 @@ var RepositoryResourceInfo = utils.NewResourceInfo(GROUP, VERSION,
 				target = m.Spec.Bitbucket.URL
 			case GitLabRepositoryType:
 				target = m.Spec.GitLab.URL
+			case BucketRepositoryType:
+				target = m.Spec.Bucket.URL
 			}
 
 			return []interface{}{
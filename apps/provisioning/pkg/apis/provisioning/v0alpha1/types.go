@@ -59,6 +59,11 @@ type GitHubRepositoryConfig struct {
 	// By default, this is false (i.e. we will not create previews).
 	GenerateDashboardPreviews bool `json:"generateDashboardPreviews,omitempty"`
 
+	// Whether we should create a read-only preview folder with the dashboards of each pull request.
+	// The folder is removed when the pull request is merged or closed, when this is disabled or when the repository is deleted.
+	// By default, this is false (i.e. we will not create preview environments).
+	GeneratePreviewEnvironments bool `json:"generatePreviewEnvironments,omitempty"`
+
 	// Path is the subdirectory for the Grafana data. If specified, Grafana will ignore anything that is outside this directory in the repository.
 	// This is usually something like `grafana/`. Trailing and leading slash are not required. They are always added when needed.
 	// The path is relative to the root of the repository, regardless of the leading slash.
@@ -110,6 +115,19 @@ type GitLabRepositoryConfig struct {
 	Path string `json:"path,omitempty"`
 }
 
+type BucketRepositoryConfig struct {
+	// The bucket URL, using the gocloud blob URL format (e.g. `s3://bucket?region=us-east-1`).
+	// S3 URLs require the `region`, and only accept the `endpoint` and `use_path_style` query parameters besides it.
+	// The `endpoint` of S3-compatible stores must be one of the permitted bucket endpoints of the server.
+	// Local buckets (`file:///path/to/bucket`) must be inside the permitted provisioning paths.
+	URL string `json:"url"`
+	// Path is the key prefix for the Grafana data. If specified, Grafana will ignore any object outside this prefix in the bucket.
+	// Trailing and leading slash are not required. They are always added when needed.
+	Path string `json:"path,omitempty"`
+	// The access key ID used to connect to S3. The secret access key is saved in the secure token.
+	AccessKeyID string `json:"accessKeyId,omitempty"`
+}
+
 // RepositoryType defines the types of Repository
 // +enum
 type RepositoryType string
@@ -121,6 +139,7 @@ const (
 	GitRepositoryType       RepositoryType = "git"
 	BitbucketRepositoryType RepositoryType = "bitbucket"
 	GitLabRepositoryType    RepositoryType = "gitlab"
+	BucketRepositoryType    RepositoryType = "bucket"
 )
 
 // IsGit returns true if the repository type is git or github
@@ -165,6 +184,66 @@ type RepositorySpec struct {
 	// The repository on GitLab.
 	// Mutually exclusive with local | github | git.
 	GitLab *GitLabRepositoryConfig `json:"gitlab,omitempty"`
+
+	// The repository in an object storage bucket (e.g. S3).
+	// Mutually exclusive with local | github | git.
+	Bucket *BucketRepositoryConfig `json:"bucket,omitempty"`
+
+	// Validation policies checked before the resources are written.
+	// The files that break a policy are not applied.
+	Policies *ValidationPolicies `json:"policies,omitempty"`
+
+	// Verification of the commit signatures before the changes are applied.
+	// Only supported by github repositories.
+	Verification *CommitVerificationConfig `json:"verification,omitempty"`
+}
+
+// CommitVerificationConfig defines which commits can be applied by sync
+type CommitVerificationConfig struct {
+	// Refuse to apply the changes of the commits without a verified GPG, SSH or S/MIME signature
+	RequireSignedCommits bool `json:"requireSignedCommits"`
+
+	// When not empty, the commits must also be committed by one of these GitHub users.
+	// GitHub checks the signature against the keys of the committer account, so any key added to the account is trusted
+	TrustedSigners []string `json:"trustedSigners,omitempty"`
+}
+
+// SignatureVerification is the result of the verification of a commit signature
+type SignatureVerification struct {
+	// The verified commit
+	Ref string `json:"ref,omitempty"`
+
+	// The commit has a valid signature, from a trusted signer when the signers are restricted
+	Verified bool `json:"verified"`
+
+	// The user who signed the commit
+	Signer string `json:"signer,omitempty"`
+
+	// Why the signature is not verified (e.g. unsigned, unknown_key, untrusted_signer)
+	Reason string `json:"reason,omitempty"`
+}
+
+// ValidationPolicies are checked on every resource before it is written by sync or by the files API,
+// and when a pull request is evaluated. Empty values are not checked.
+type ValidationPolicies struct {
+	// Tags that every dashboard must have
+	RequiredTags []string `json:"requiredTags,omitempty"`
+
+	// The datasources (by uid or by type) the dashboards may query.
+	// When empty, any datasource is allowed.
+	AllowedDatasources []string `json:"allowedDatasources,omitempty"`
+
+	// Panel types (plugin ids) the dashboards may not use
+	ForbiddenPanelTypes []string `json:"forbiddenPanelTypes,omitempty"`
+
+	// The oldest dashboard schema version accepted
+	MinSchemaVersion int64 `json:"minSchemaVersion,omitempty"`
+
+	// The maximum number of panels in a dashboard, including the panels in collapsed rows
+	MaxPanels int64 `json:"maxPanels,omitempty"`
+
+	// Regular expression the resource names must match (e.g. `^team-[a-z0-9-]+$`)
+	NamePattern string `json:"namePattern,omitempty"`
 }
 
 // SyncTargetType defines where we want all values to resolve
@@ -219,6 +298,9 @@ type RepositoryStatus struct {
 
 	// Webhook Information (if applicable)
 	Webhook *WebhookStatus `json:"webhook"`
+
+	// The differences found when the drift job last ran
+	Drift *DriftStatus `json:"drift,omitempty"`
 }
 
 // HealthFailureType represents different types of repository failures
@@ -271,6 +353,70 @@ type SyncStatus struct {
 
 	// Incremental synchronization for versioned repositories
 	Incremental bool `json:"incremental,omitempty"`
+
+	// The signature verification of the synced commits, when the repository requires signed commits
+	Verification *SignatureVerification `json:"verification,omitempty"`
+}
+
+// The kind of difference between the repository and grafana
+// +enum
+type DriftType string
+
+// DriftType values
+const (
+	// The file and the resource in grafana are different
+	DriftTypeModified DriftType = "modified"
+	// The file exists in the repository, but the resource is not in grafana
+	DriftTypeMissing DriftType = "missing"
+	// The resource is managed by the repository in grafana, but the file does not exist
+	DriftTypeOrphaned DriftType = "orphaned"
+)
+
+type DriftStatus struct {
+	// The state of the last drift job
+	State JobState `json:"state"`
+
+	// The ID for the job that produced this report
+	JobID string `json:"job,omitempty"`
+
+	// When the repository was compared
+	Checked int64 `json:"checked,omitempty"`
+
+	// The repository ref that was compared
+	Ref string `json:"ref,omitempty"`
+
+	// The number of modified resources
+	Modified int64 `json:"modified"`
+
+	// The number of missing resources
+	Missing int64 `json:"missing"`
+
+	// The number of orphaned resources
+	Orphaned int64 `json:"orphaned"`
+
+	// The differences found (this may be truncated, the counts are always complete)
+	// +listType=atomic
+	Items []DriftItem `json:"items,omitempty"`
+
+	// Summary messages (will be shown to users)
+	// +listType=atomic
+	Message []string `json:"message,omitempty"`
+}
+
+type DriftItem struct {
+	// The kind of difference
+	Type DriftType `json:"type"`
+
+	// Path to the file in the repository
+	Path string `json:"path"`
+
+	// The resource in grafana (not set when missing)
+	Group    string `json:"group,omitempty"`
+	Resource string `json:"resource,omitempty"`
+	Name     string `json:"name,omitempty"`
+
+	// Why the resource is considered modified
+	Reason string `json:"reason,omitempty"`
 }
 
 type WebhookStatus struct {
@@ -512,6 +658,8 @@ type HistoryItem struct {
 	// +listType=atomic
 	Authors   []Author `json:"authors"`
 	CreatedAt int64    `json:"createdAt"`
+	// The signature verification of the commit, when the repository can verify it
+	Verification *SignatureVerification `json:"verification,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -43,6 +43,43 @@ func (in *BitbucketRepositoryConfig) DeepCopy() *BitbucketRepositoryConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketRepositoryConfig) DeepCopyInto(out *BucketRepositoryConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketRepositoryConfig.
+func (in *BucketRepositoryConfig) DeepCopy() *BucketRepositoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketRepositoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitVerificationConfig) DeepCopyInto(out *CommitVerificationConfig) {
+	*out = *in
+	if in.TrustedSigners != nil {
+		in, out := &in.TrustedSigners, &out.TrustedSigners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitVerificationConfig.
+func (in *CommitVerificationConfig) DeepCopy() *CommitVerificationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitVerificationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeleteJobOptions) DeepCopyInto(out *DeleteJobOptions) {
 	*out = *in
@@ -69,6 +106,64 @@ func (in *DeleteJobOptions) DeepCopy() *DeleteJobOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftItem) DeepCopyInto(out *DriftItem) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftItem.
+func (in *DriftItem) DeepCopy() *DriftItem {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftJobOptions) DeepCopyInto(out *DriftJobOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftJobOptions.
+func (in *DriftJobOptions) DeepCopy() *DriftJobOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftJobOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftStatus) DeepCopyInto(out *DriftStatus) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DriftItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Message != nil {
+		in, out := &in.Message, &out.Message
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftStatus.
+func (in *DriftStatus) DeepCopy() *DriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ErrorDetails) DeepCopyInto(out *ErrorDetails) {
 	*out = *in
@@ -286,6 +381,11 @@ func (in *HistoryItem) DeepCopyInto(out *HistoryItem) {
 		*out = make([]Author, len(*in))
 		copy(*out, *in)
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(SignatureVerification)
+		**out = **in
+	}
 	return
 }
 
@@ -447,6 +547,11 @@ func (in *JobSpec) DeepCopyInto(out *JobSpec) {
 		*out = new(MoveJobOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(DriftJobOptions)
+		**out = **in
+	}
 	return
 }
 
@@ -735,6 +840,21 @@ func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
 		*out = new(GitLabRepositoryConfig)
 		**out = **in
 	}
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(BucketRepositoryConfig)
+		**out = **in
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = new(ValidationPolicies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(CommitVerificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -763,6 +883,11 @@ func (in *RepositoryStatus) DeepCopyInto(out *RepositoryStatus) {
 		*out = new(WebhookStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(DriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1084,6 +1209,22 @@ func (in *SecureValues) DeepCopy() *SecureValues {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SignatureVerification) DeepCopyInto(out *SignatureVerification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SignatureVerification.
+func (in *SignatureVerification) DeepCopy() *SignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(SignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncJobOptions) DeepCopyInto(out *SyncJobOptions) {
 	*out = *in
@@ -1124,6 +1265,11 @@ func (in *SyncStatus) DeepCopyInto(out *SyncStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(SignatureVerification)
+		**out = **in
+	}
 	return
 }
 
@@ -1167,6 +1313,37 @@ func (in *TestResults) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationPolicies) DeepCopyInto(out *ValidationPolicies) {
+	*out = *in
+	if in.RequiredTags != nil {
+		in, out := &in.RequiredTags, &out.RequiredTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedDatasources != nil {
+		in, out := &in.AllowedDatasources, &out.AllowedDatasources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenPanelTypes != nil {
+		in, out := &in.ForbiddenPanelTypes, &out.ForbiddenPanelTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationPolicies.
+func (in *ValidationPolicies) DeepCopy() *ValidationPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookResponse) DeepCopyInto(out *WebhookResponse) {
 	*out = *in
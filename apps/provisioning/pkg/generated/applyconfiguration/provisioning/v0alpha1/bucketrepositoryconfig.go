@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v0alpha1
+
+// BucketRepositoryConfigApplyConfiguration represents a declarative configuration of the BucketRepositoryConfig type for use
+// with apply.
+type BucketRepositoryConfigApplyConfiguration struct {
+	URL         *string `json:"url,omitempty"`
+	Path        *string `json:"path,omitempty"`
+	AccessKeyID *string `json:"accessKeyId,omitempty"`
+}
+
+// BucketRepositoryConfigApplyConfiguration constructs a declarative configuration of the BucketRepositoryConfig type for use with
+// apply.
+func BucketRepositoryConfig() *BucketRepositoryConfigApplyConfiguration {
+	return &BucketRepositoryConfigApplyConfiguration{}
+}
+
+// WithURL sets the URL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the URL field is set to the value of the last call.
+func (b *BucketRepositoryConfigApplyConfiguration) WithURL(value string) *BucketRepositoryConfigApplyConfiguration {
+	b.URL = &value
+	return b
+}
+
+// WithPath sets the Path field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Path field is set to the value of the last call.
+func (b *BucketRepositoryConfigApplyConfiguration) WithPath(value string) *BucketRepositoryConfigApplyConfiguration {
+	b.Path = &value
+	return b
+}
+
+// WithAccessKeyID sets the AccessKeyID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AccessKeyID field is set to the value of the last call.
+func (b *BucketRepositoryConfigApplyConfiguration) WithAccessKeyID(value string) *BucketRepositoryConfigApplyConfiguration {
+	b.AccessKeyID = &value
+	return b
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v0alpha1
+
+// CommitVerificationConfigApplyConfiguration represents a declarative configuration of the CommitVerificationConfig type for use
+// with apply.
+type CommitVerificationConfigApplyConfiguration struct {
+	RequireSignedCommits *bool    `json:"requireSignedCommits,omitempty"`
+	TrustedSigners       []string `json:"trustedSigners,omitempty"`
+}
+
+// CommitVerificationConfigApplyConfiguration constructs a declarative configuration of the CommitVerificationConfig type for use with
+// apply.
+func CommitVerificationConfig() *CommitVerificationConfigApplyConfiguration {
+	return &CommitVerificationConfigApplyConfiguration{}
+}
+
+// WithRequireSignedCommits sets the RequireSignedCommits field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequireSignedCommits field is set to the value of the last call.
+func (b *CommitVerificationConfigApplyConfiguration) WithRequireSignedCommits(value bool) *CommitVerificationConfigApplyConfiguration {
+	b.RequireSignedCommits = &value
+	return b
+}
+
+// WithTrustedSigners adds the given value to the TrustedSigners field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the TrustedSigners field.
+func (b *CommitVerificationConfigApplyConfiguration) WithTrustedSigners(values ...string) *CommitVerificationConfigApplyConfiguration {
+	for i := range values {
+		b.TrustedSigners = append(b.TrustedSigners, values[i])
+	}
+	return b
+}
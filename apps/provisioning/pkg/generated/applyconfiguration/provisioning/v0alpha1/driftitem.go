@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v0alpha1
+
+import (
+	provisioningv0alpha1 "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+)
+
+// DriftItemApplyConfiguration represents a declarative configuration of the DriftItem type for use
+// with apply.
+type DriftItemApplyConfiguration struct {
+	Type     *provisioningv0alpha1.DriftType `json:"type,omitempty"`
+	Path     *string                         `json:"path,omitempty"`
+	Group    *string                         `json:"group,omitempty"`
+	Resource *string                         `json:"resource,omitempty"`
+	Name     *string                         `json:"name,omitempty"`
+	Reason   *string                         `json:"reason,omitempty"`
+}
+
+// DriftItemApplyConfiguration constructs a declarative configuration of the DriftItem type for use with
+// apply.
+func DriftItem() *DriftItemApplyConfiguration {
+	return &DriftItemApplyConfiguration{}
+}
+
+// WithType sets the Type field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Type field is set to the value of the last call.
+func (b *DriftItemApplyConfiguration) WithType(value provisioningv0alpha1.DriftType) *DriftItemApplyConfiguration {
+	b.Type = &value
+	return b
+}
+
+// WithPath sets the Path field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Path field is set to the value of the last call.
+func (b *DriftItemApplyConfiguration) WithPath(value string) *DriftItemApplyConfiguration {
+	b.Path = &value
+	return b
+}
+
+// WithGroup sets the Group field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Group field is set to the value of the last call.
+func (b *DriftItemApplyConfiguration) WithGroup(value string) *DriftItemApplyConfiguration {
+	b.Group = &value
+	return b
+}
+
+// WithResource sets the Resource field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Resource field is set to the value of the last call.
+func (b *DriftItemApplyConfiguration) WithResource(value string) *DriftItemApplyConfiguration {
+	b.Resource = &value
+	return b
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *DriftItemApplyConfiguration) WithName(value string) *DriftItemApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithReason sets the Reason field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Reason field is set to the value of the last call.
+func (b *DriftItemApplyConfiguration) WithReason(value string) *DriftItemApplyConfiguration {
+	b.Reason = &value
+	return b
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v0alpha1
+
+// DriftJobOptionsApplyConfiguration represents a declarative configuration of the DriftJobOptions type for use
+// with apply.
+type DriftJobOptionsApplyConfiguration struct {
+	Ref *string `json:"ref,omitempty"`
+}
+
+// DriftJobOptionsApplyConfiguration constructs a declarative configuration of the DriftJobOptions type for use with
+// apply.
+func DriftJobOptions() *DriftJobOptionsApplyConfiguration {
+	return &DriftJobOptionsApplyConfiguration{}
+}
+
+// WithRef sets the Ref field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Ref field is set to the value of the last call.
+func (b *DriftJobOptionsApplyConfiguration) WithRef(value string) *DriftJobOptionsApplyConfiguration {
+	b.Ref = &value
+	return b
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v0alpha1
+
+import (
+	provisioningv0alpha1 "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+)
+
+// DriftStatusApplyConfiguration represents a declarative configuration of the DriftStatus type for use
+// with apply.
+type DriftStatusApplyConfiguration struct {
+	State    *provisioningv0alpha1.JobState `json:"state,omitempty"`
+	JobID    *string                        `json:"job,omitempty"`
+	Checked  *int64                         `json:"checked,omitempty"`
+	Ref      *string                        `json:"ref,omitempty"`
+	Modified *int64                         `json:"modified,omitempty"`
+	Missing  *int64                         `json:"missing,omitempty"`
+	Orphaned *int64                         `json:"orphaned,omitempty"`
+	Items    []DriftItemApplyConfiguration  `json:"items,omitempty"`
+	Message  []string                       `json:"message,omitempty"`
+}
+
+// DriftStatusApplyConfiguration constructs a declarative configuration of the DriftStatus type for use with
+// apply.
+func DriftStatus() *DriftStatusApplyConfiguration {
+	return &DriftStatusApplyConfiguration{}
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *DriftStatusApplyConfiguration) WithState(value provisioningv0alpha1.JobState) *DriftStatusApplyConfiguration {
+	b.State = &value
+	return b
+}
+
+// WithJobID sets the JobID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the JobID field is set to the value of the last call.
+func (b *DriftStatusApplyConfiguration) WithJobID(value string) *DriftStatusApplyConfiguration {
+	b.JobID = &value
+	return b
+}
+
+// WithChecked sets the Checked field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Checked field is set to the value of the last call.
+func (b *DriftStatusApplyConfiguration) WithChecked(value int64) *DriftStatusApplyConfiguration {
+	b.Checked = &value
+	return b
+}
+
+// WithRef sets the Ref field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Ref field is set to the value of the last call.
+func (b *DriftStatusApplyConfiguration) WithRef(value string) *DriftStatusApplyConfiguration {
+	b.Ref = &value
+	return b
+}
+
+// WithModified sets the Modified field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Modified field is set to the value of the last call.
+func (b *DriftStatusApplyConfiguration) WithModified(value int64) *DriftStatusApplyConfiguration {
+	b.Modified = &value
+	return b
+}
+
+// WithMissing sets the Missing field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Missing field is set to the value of the last call.
+func (b *DriftStatusApplyConfiguration) WithMissing(value int64) *DriftStatusApplyConfiguration {
+	b.Missing = &value
+	return b
+}
+
+// WithOrphaned sets the Orphaned field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Orphaned field is set to the value of the last call.
+func (b *DriftStatusApplyConfiguration) WithOrphaned(value int64) *DriftStatusApplyConfiguration {
+	b.Orphaned = &value
+	return b
+}
+
+// WithItems adds the given value to the Items field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Items field.
+func (b *DriftStatusApplyConfiguration) WithItems(values ...*DriftItemApplyConfiguration) *DriftStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithItems")
+		}
+		b.Items = append(b.Items, *values[i])
+	}
+	return b
+}
+
+// WithMessage adds the given value to the Message field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Message field.
+func (b *DriftStatusApplyConfiguration) WithMessage(values ...string) *DriftStatusApplyConfiguration {
+	for i := range values {
+		b.Message = append(b.Message, values[i])
+	}
+	return b
+}
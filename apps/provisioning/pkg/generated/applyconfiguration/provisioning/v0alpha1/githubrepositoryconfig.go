@@ -7,10 +7,11 @@ package v0alpha1
 // GitHubRepositoryConfigApplyConfiguration represents a declarative configuration of the GitHubRepositoryConfig type for use
 // with apply.
 type GitHubRepositoryConfigApplyConfiguration struct {
-	URL                       *string `json:"url,omitempty"`
-	Branch                    *string `json:"branch,omitempty"`
-	GenerateDashboardPreviews *bool   `json:"generateDashboardPreviews,omitempty"`
-	Path                      *string `json:"path,omitempty"`
+	URL                         *string `json:"url,omitempty"`
+	Branch                      *string `json:"branch,omitempty"`
+	GenerateDashboardPreviews   *bool   `json:"generateDashboardPreviews,omitempty"`
+	GeneratePreviewEnvironments *bool   `json:"generatePreviewEnvironments,omitempty"`
+	Path                        *string `json:"path,omitempty"`
 }
 
 // GitHubRepositoryConfigApplyConfiguration constructs a declarative configuration of the GitHubRepositoryConfig type for use with
@@ -43,6 +44,14 @@ func (b *GitHubRepositoryConfigApplyConfiguration) WithGenerateDashboardPreviews
 	return b
 }
 
+// WithGeneratePreviewEnvironments sets the GeneratePreviewEnvironments field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the GeneratePreviewEnvironments field is set to the value of the last call.
+func (b *GitHubRepositoryConfigApplyConfiguration) WithGeneratePreviewEnvironments(value bool) *GitHubRepositoryConfigApplyConfiguration {
+	b.GeneratePreviewEnvironments = &value
+	return b
+}
+
 // WithPath sets the Path field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Path field is set to the value of the last call.
@@ -19,6 +19,7 @@ type JobSpecApplyConfiguration struct {
 	Migrate     *MigrateJobOptionsApplyConfiguration     `json:"migrate,omitempty"`
 	Delete      *DeleteJobOptionsApplyConfiguration      `json:"delete,omitempty"`
 	Move        *MoveJobOptionsApplyConfiguration        `json:"move,omitempty"`
+	Drift       *DriftJobOptionsApplyConfiguration       `json:"drift,omitempty"`
 }
 
 // JobSpecApplyConfiguration constructs a declarative configuration of the JobSpec type for use with
@@ -90,3 +91,11 @@ func (b *JobSpecApplyConfiguration) WithMove(value *MoveJobOptionsApplyConfigura
 	b.Move = value
 	return b
 }
+
+// WithDrift sets the Drift field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Drift field is set to the value of the last call.
+func (b *JobSpecApplyConfiguration) WithDrift(value *DriftJobOptionsApplyConfiguration) *JobSpecApplyConfiguration {
+	b.Drift = value
+	return b
+}
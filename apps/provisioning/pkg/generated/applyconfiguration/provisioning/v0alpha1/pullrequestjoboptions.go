@@ -7,10 +7,11 @@ package v0alpha1
 // PullRequestJobOptionsApplyConfiguration represents a declarative configuration of the PullRequestJobOptions type for use
 // with apply.
 type PullRequestJobOptionsApplyConfiguration struct {
-	Ref  *string `json:"ref,omitempty"`
-	PR   *int    `json:"pr,omitempty"`
-	Hash *string `json:"hash,omitempty"`
-	URL  *string `json:"url,omitempty"`
+	Ref    *string `json:"ref,omitempty"`
+	PR     *int    `json:"pr,omitempty"`
+	Hash   *string `json:"hash,omitempty"`
+	URL    *string `json:"url,omitempty"`
+	Closed *bool   `json:"closed,omitempty"`
 }
 
 // PullRequestJobOptionsApplyConfiguration constructs a declarative configuration of the PullRequestJobOptions type for use with
@@ -50,3 +51,11 @@ func (b *PullRequestJobOptionsApplyConfiguration) WithURL(value string) *PullReq
 	b.URL = &value
 	return b
 }
+
+// WithClosed sets the Closed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Closed field is set to the value of the last call.
+func (b *PullRequestJobOptionsApplyConfiguration) WithClosed(value bool) *PullRequestJobOptionsApplyConfiguration {
+	b.Closed = &value
+	return b
+}
@@ -11,16 +11,19 @@ import (
 // RepositorySpecApplyConfiguration represents a declarative configuration of the RepositorySpec type for use
 // with apply.
 type RepositorySpecApplyConfiguration struct {
-	Title       *string                                      `json:"title,omitempty"`
-	Description *string                                      `json:"description,omitempty"`
-	Workflows   []provisioningv0alpha1.Workflow              `json:"workflows,omitempty"`
-	Sync        *SyncOptionsApplyConfiguration               `json:"sync,omitempty"`
-	Type        *provisioningv0alpha1.RepositoryType         `json:"type,omitempty"`
-	Local       *LocalRepositoryConfigApplyConfiguration     `json:"local,omitempty"`
-	GitHub      *GitHubRepositoryConfigApplyConfiguration    `json:"github,omitempty"`
-	Git         *GitRepositoryConfigApplyConfiguration       `json:"git,omitempty"`
-	Bitbucket   *BitbucketRepositoryConfigApplyConfiguration `json:"bitbucket,omitempty"`
-	GitLab      *GitLabRepositoryConfigApplyConfiguration    `json:"gitlab,omitempty"`
+	Title        *string                                      `json:"title,omitempty"`
+	Description  *string                                      `json:"description,omitempty"`
+	Workflows    []provisioningv0alpha1.Workflow              `json:"workflows,omitempty"`
+	Sync         *SyncOptionsApplyConfiguration               `json:"sync,omitempty"`
+	Type         *provisioningv0alpha1.RepositoryType         `json:"type,omitempty"`
+	Local        *LocalRepositoryConfigApplyConfiguration     `json:"local,omitempty"`
+	GitHub       *GitHubRepositoryConfigApplyConfiguration    `json:"github,omitempty"`
+	Git          *GitRepositoryConfigApplyConfiguration       `json:"git,omitempty"`
+	Bitbucket    *BitbucketRepositoryConfigApplyConfiguration `json:"bitbucket,omitempty"`
+	GitLab       *GitLabRepositoryConfigApplyConfiguration    `json:"gitlab,omitempty"`
+	Bucket       *BucketRepositoryConfigApplyConfiguration    `json:"bucket,omitempty"`
+	Policies     *ValidationPoliciesApplyConfiguration        `json:"policies,omitempty"`
+	Verification *CommitVerificationConfigApplyConfiguration  `json:"verification,omitempty"`
 }
 
 // RepositorySpecApplyConfiguration constructs a declarative configuration of the RepositorySpec type for use with
@@ -110,3 +113,27 @@ func (b *RepositorySpecApplyConfiguration) WithGitLab(value *GitLabRepositoryCon
 	b.GitLab = value
 	return b
 }
+
+// WithBucket sets the Bucket field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Bucket field is set to the value of the last call.
+func (b *RepositorySpecApplyConfiguration) WithBucket(value *BucketRepositoryConfigApplyConfiguration) *RepositorySpecApplyConfiguration {
+	b.Bucket = value
+	return b
+}
+
+// WithPolicies sets the Policies field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Policies field is set to the value of the last call.
+func (b *RepositorySpecApplyConfiguration) WithPolicies(value *ValidationPoliciesApplyConfiguration) *RepositorySpecApplyConfiguration {
+	b.Policies = value
+	return b
+}
+
+// WithVerification sets the Verification field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Verification field is set to the value of the last call.
+func (b *RepositorySpecApplyConfiguration) WithVerification(value *CommitVerificationConfigApplyConfiguration) *RepositorySpecApplyConfiguration {
+	b.Verification = value
+	return b
+}
@@ -12,6 +12,7 @@ type RepositoryStatusApplyConfiguration struct {
 	Sync               *SyncStatusApplyConfiguration     `json:"sync,omitempty"`
 	Stats              []ResourceCountApplyConfiguration `json:"stats,omitempty"`
 	Webhook            *WebhookStatusApplyConfiguration  `json:"webhook,omitempty"`
+	Drift              *DriftStatusApplyConfiguration    `json:"drift,omitempty"`
 }
 
 // RepositoryStatusApplyConfiguration constructs a declarative configuration of the RepositoryStatus type for use with
@@ -64,3 +65,11 @@ func (b *RepositoryStatusApplyConfiguration) WithWebhook(value *WebhookStatusApp
 	b.Webhook = value
 	return b
 }
+
+// WithDrift sets the Drift field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Drift field is set to the value of the last call.
+func (b *RepositoryStatusApplyConfiguration) WithDrift(value *DriftStatusApplyConfiguration) *RepositoryStatusApplyConfiguration {
+	b.Drift = value
+	return b
+}
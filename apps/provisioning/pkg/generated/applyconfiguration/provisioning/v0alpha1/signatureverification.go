@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v0alpha1
+
+// SignatureVerificationApplyConfiguration represents a declarative configuration of the SignatureVerification type for use
+// with apply.
+type SignatureVerificationApplyConfiguration struct {
+	Ref      *string `json:"ref,omitempty"`
+	Verified *bool   `json:"verified,omitempty"`
+	Signer   *string `json:"signer,omitempty"`
+	Reason   *string `json:"reason,omitempty"`
+}
+
+// SignatureVerificationApplyConfiguration constructs a declarative configuration of the SignatureVerification type for use with
+// apply.
+func SignatureVerification() *SignatureVerificationApplyConfiguration {
+	return &SignatureVerificationApplyConfiguration{}
+}
+
+// WithRef sets the Ref field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Ref field is set to the value of the last call.
+func (b *SignatureVerificationApplyConfiguration) WithRef(value string) *SignatureVerificationApplyConfiguration {
+	b.Ref = &value
+	return b
+}
+
+// WithVerified sets the Verified field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Verified field is set to the value of the last call.
+func (b *SignatureVerificationApplyConfiguration) WithVerified(value bool) *SignatureVerificationApplyConfiguration {
+	b.Verified = &value
+	return b
+}
+
+// WithSigner sets the Signer field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Signer field is set to the value of the last call.
+func (b *SignatureVerificationApplyConfiguration) WithSigner(value string) *SignatureVerificationApplyConfiguration {
+	b.Signer = &value
+	return b
+}
+
+// WithReason sets the Reason field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Reason field is set to the value of the last call.
+func (b *SignatureVerificationApplyConfiguration) WithReason(value string) *SignatureVerificationApplyConfiguration {
+	b.Reason = &value
+	return b
+}
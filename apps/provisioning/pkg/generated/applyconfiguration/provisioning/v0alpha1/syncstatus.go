@@ -11,14 +11,15 @@ import (
 // SyncStatusApplyConfiguration represents a declarative configuration of the SyncStatus type for use
 // with apply.
 type SyncStatusApplyConfiguration struct {
-	State       *provisioningv0alpha1.JobState `json:"state,omitempty"`
-	JobID       *string                        `json:"job,omitempty"`
-	Started     *int64                         `json:"started,omitempty"`
-	Finished    *int64                         `json:"finished,omitempty"`
-	Scheduled   *int64                         `json:"scheduled,omitempty"`
-	Message     []string                       `json:"message,omitempty"`
-	LastRef     *string                        `json:"lastRef,omitempty"`
-	Incremental *bool                          `json:"incremental,omitempty"`
+	State        *provisioningv0alpha1.JobState           `json:"state,omitempty"`
+	JobID        *string                                  `json:"job,omitempty"`
+	Started      *int64                                   `json:"started,omitempty"`
+	Finished     *int64                                   `json:"finished,omitempty"`
+	Scheduled    *int64                                   `json:"scheduled,omitempty"`
+	Message      []string                                 `json:"message,omitempty"`
+	LastRef      *string                                  `json:"lastRef,omitempty"`
+	Incremental  *bool                                    `json:"incremental,omitempty"`
+	Verification *SignatureVerificationApplyConfiguration `json:"verification,omitempty"`
 }
 
 // SyncStatusApplyConfiguration constructs a declarative configuration of the SyncStatus type for use with
@@ -92,3 +93,11 @@ func (b *SyncStatusApplyConfiguration) WithIncremental(value bool) *SyncStatusAp
 	b.Incremental = &value
 	return b
 }
+
+// WithVerification sets the Verification field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Verification field is set to the value of the last call.
+func (b *SyncStatusApplyConfiguration) WithVerification(value *SignatureVerificationApplyConfiguration) *SyncStatusApplyConfiguration {
+	b.Verification = value
+	return b
+}
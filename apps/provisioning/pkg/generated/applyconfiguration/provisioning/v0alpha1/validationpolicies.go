@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v0alpha1
+
+// ValidationPoliciesApplyConfiguration represents a declarative configuration of the ValidationPolicies type for use
+// with apply.
+type ValidationPoliciesApplyConfiguration struct {
+	RequiredTags        []string `json:"requiredTags,omitempty"`
+	AllowedDatasources  []string `json:"allowedDatasources,omitempty"`
+	ForbiddenPanelTypes []string `json:"forbiddenPanelTypes,omitempty"`
+	MinSchemaVersion    *int64   `json:"minSchemaVersion,omitempty"`
+	MaxPanels           *int64   `json:"maxPanels,omitempty"`
+	NamePattern         *string  `json:"namePattern,omitempty"`
+}
+
+// ValidationPoliciesApplyConfiguration constructs a declarative configuration of the ValidationPolicies type for use with
+// apply.
+func ValidationPolicies() *ValidationPoliciesApplyConfiguration {
+	return &ValidationPoliciesApplyConfiguration{}
+}
+
+// WithRequiredTags adds the given value to the RequiredTags field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the RequiredTags field.
+func (b *ValidationPoliciesApplyConfiguration) WithRequiredTags(values ...string) *ValidationPoliciesApplyConfiguration {
+	for i := range values {
+		b.RequiredTags = append(b.RequiredTags, values[i])
+	}
+	return b
+}
+
+// WithAllowedDatasources adds the given value to the AllowedDatasources field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the AllowedDatasources field.
+func (b *ValidationPoliciesApplyConfiguration) WithAllowedDatasources(values ...string) *ValidationPoliciesApplyConfiguration {
+	for i := range values {
+		b.AllowedDatasources = append(b.AllowedDatasources, values[i])
+	}
+	return b
+}
+
+// WithForbiddenPanelTypes adds the given value to the ForbiddenPanelTypes field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ForbiddenPanelTypes field.
+func (b *ValidationPoliciesApplyConfiguration) WithForbiddenPanelTypes(values ...string) *ValidationPoliciesApplyConfiguration {
+	for i := range values {
+		b.ForbiddenPanelTypes = append(b.ForbiddenPanelTypes, values[i])
+	}
+	return b
+}
+
+// WithMinSchemaVersion sets the MinSchemaVersion field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MinSchemaVersion field is set to the value of the last call.
+func (b *ValidationPoliciesApplyConfiguration) WithMinSchemaVersion(value int64) *ValidationPoliciesApplyConfiguration {
+	b.MinSchemaVersion = &value
+	return b
+}
+
+// WithMaxPanels sets the MaxPanels field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxPanels field is set to the value of the last call.
+func (b *ValidationPoliciesApplyConfiguration) WithMaxPanels(value int64) *ValidationPoliciesApplyConfiguration {
+	b.MaxPanels = &value
+	return b
+}
+
+// WithNamePattern sets the NamePattern field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NamePattern field is set to the value of the last call.
+func (b *ValidationPoliciesApplyConfiguration) WithNamePattern(value string) *ValidationPoliciesApplyConfiguration {
+	b.NamePattern = &value
+	return b
+}
@@ -20,8 +20,18 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 	// Group=provisioning.grafana.app, Version=v0alpha1
 	case v0alpha1.SchemeGroupVersion.WithKind("BitbucketRepositoryConfig"):
 		return &provisioningv0alpha1.BitbucketRepositoryConfigApplyConfiguration{}
+	case v0alpha1.SchemeGroupVersion.WithKind("BucketRepositoryConfig"):
+		return &provisioningv0alpha1.BucketRepositoryConfigApplyConfiguration{}
+	case v0alpha1.SchemeGroupVersion.WithKind("CommitVerificationConfig"):
+		return &provisioningv0alpha1.CommitVerificationConfigApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("DeleteJobOptions"):
 		return &provisioningv0alpha1.DeleteJobOptionsApplyConfiguration{}
+	case v0alpha1.SchemeGroupVersion.WithKind("DriftItem"):
+		return &provisioningv0alpha1.DriftItemApplyConfiguration{}
+	case v0alpha1.SchemeGroupVersion.WithKind("DriftJobOptions"):
+		return &provisioningv0alpha1.DriftJobOptionsApplyConfiguration{}
+	case v0alpha1.SchemeGroupVersion.WithKind("DriftStatus"):
+		return &provisioningv0alpha1.DriftStatusApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("ExportJobOptions"):
 		return &provisioningv0alpha1.ExportJobOptionsApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("GitHubRepositoryConfig"):
@@ -64,12 +74,16 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &provisioningv0alpha1.ResourceRefApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("SecureValues"):
 		return &provisioningv0alpha1.SecureValuesApplyConfiguration{}
+	case v0alpha1.SchemeGroupVersion.WithKind("SignatureVerification"):
+		return &provisioningv0alpha1.SignatureVerificationApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("SyncJobOptions"):
 		return &provisioningv0alpha1.SyncJobOptionsApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("SyncOptions"):
 		return &provisioningv0alpha1.SyncOptionsApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("SyncStatus"):
 		return &provisioningv0alpha1.SyncStatusApplyConfiguration{}
+	case v0alpha1.SchemeGroupVersion.WithKind("ValidationPolicies"):
+		return &provisioningv0alpha1.ValidationPoliciesApplyConfiguration{}
 	case v0alpha1.SchemeGroupVersion.WithKind("WebhookStatus"):
 		return &provisioningv0alpha1.WebhookStatusApplyConfiguration{}
 
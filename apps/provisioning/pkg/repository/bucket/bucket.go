@@ -0,0 +1,665 @@
+package bucket
+
+import (
+	"context"
+	// The MD5 is what most object stores report for the content, so we can read it from the listing
+	//nolint:gosec
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/grafana/grafana-app-sdk/logging"
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/fileblob"
+	"gocloud.dev/blob/s3blob"
+	"gocloud.dev/gcerrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository/local"
+	"github.com/grafana/grafana/apps/provisioning/pkg/safepath"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+)
+
+// Objects under this prefix are used by grafana to track the repository state.
+// They are never shown as part of the repository tree.
+const metadataPrefix = ".grafana/"
+
+// The schemes we can open, everything else is rejected on validation
+var supportedSchemes = []string{"s3", "file"}
+
+// The query parameters of the s3 URLs. The others can change how the credentials are loaded
+// or where the requests are sent, so they are rejected on validation
+var s3Params = []string{"region", "endpoint", "use_path_style"}
+
+// The AWS regions (e.g. us-east-1 or us-gov-west-1). The region is part of the endpoint host name.
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+
+var (
+	_ repository.Repository   = (*bucketRepository)(nil)
+	_ repository.ReaderWriter = (*bucketRepository)(nil)
+	_ repository.Versioned    = (*bucketRepository)(nil)
+)
+
+type bucketRepository struct {
+	config   *provisioning.Repository
+	resolver *local.LocalFolderResolver
+
+	// The key prefix (with a trailing slash) or empty for the whole bucket
+	prefix string
+
+	// The endpoints (scheme and host) of the S3-compatible stores that can be set in the URL
+	permittedEndpoints []string
+
+	// The S3 secret access key, used with the configured access key ID
+	secretKey common.RawSecureValue
+}
+
+func NewRepository(config *provisioning.Repository, resolver *local.LocalFolderResolver, permittedEndpoints []string, secretKey common.RawSecureValue) *bucketRepository {
+	r := &bucketRepository{
+		config:             config,
+		resolver:           resolver,
+		permittedEndpoints: permittedEndpoints,
+		secretKey:          secretKey,
+	}
+
+	if config.Spec.Bucket != nil {
+		r.prefix = strings.Trim(safepath.Clean(config.Spec.Bucket.Path), "/")
+		if r.prefix != "" {
+			r.prefix += "/"
+		}
+	}
+
+	return r
+}
+
+func (r *bucketRepository) Config() *provisioning.Repository {
+	return r.config
+}
+
+// Validate implements provisioning.Repository.
+func (r *bucketRepository) Validate() field.ErrorList {
+	cfg := r.config.Spec.Bucket
+	if cfg == nil {
+		return field.ErrorList{&field.Error{
+			Type:  field.ErrorTypeRequired,
+			Field: "spec.bucket",
+		}}
+	}
+
+	var list field.ErrorList
+	if cfg.URL == "" {
+		list = append(list, field.Required(field.NewPath("spec", "bucket", "url"), "a bucket URL is required"))
+	} else if err := r.checkURL(); err != nil {
+		list = append(list, field.Invalid(field.NewPath("spec", "bucket", "url"), cfg.URL, err.Error()))
+	}
+
+	if cfg.Path != "" {
+		if err := safepath.IsSafe(cfg.Path); err != nil {
+			list = append(list, field.Invalid(field.NewPath("spec", "bucket", "path"), cfg.Path, err.Error()))
+		}
+	}
+
+	// The server credentials are never used, so S3 buckets need their own
+	if strings.HasPrefix(cfg.URL, "s3:") {
+		if cfg.AccessKeyID == "" {
+			list = append(list, field.Required(field.NewPath("spec", "bucket", "accessKeyId"), "an access key ID is required"))
+		}
+		if r.secretKey.IsZero() && r.config.Secure.Token.IsZero() {
+			list = append(list, field.Required(field.NewPath("secure", "token"), "a secret access key is required"))
+		}
+	}
+
+	return list
+}
+
+// Test implements provisioning.Repository.
+// NOTE: Validate has been called (and passed) before this function should be called
+func (r *bucketRepository) Test(ctx context.Context) (*provisioning.TestResults, error) {
+	path := field.NewPath("spec", "bucket", "url")
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return repository.FromFieldError(field.Invalid(path, r.config.Spec.Bucket.URL, err.Error())), nil
+	}
+	defer r.close(ctx, b)
+
+	ok, err := b.IsAccessible(ctx)
+	if err != nil {
+		return repository.FromFieldError(field.Invalid(path, r.config.Spec.Bucket.URL,
+			fmt.Sprintf("failed to check if bucket is accessible: %v", err))), nil
+	}
+	if !ok {
+		return repository.FromFieldError(field.NotFound(path, r.config.Spec.Bucket.URL)), nil
+	}
+
+	return &provisioning.TestResults{
+		Code:    http.StatusOK,
+		Success: true,
+	}, nil
+}
+
+// checkURL makes sure we only open buckets we are allowed to.
+// Local buckets are restricted to the same paths as local repositories,
+// and S3 buckets to the AWS endpoints and the permitted ones.
+func (r *bucketRepository) checkURL() error {
+	u, err := url.Parse(r.config.Spec.Bucket.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		if u.Host == "" {
+			return errors.New("missing bucket name")
+		}
+		return r.checkS3Params(u.Query())
+	case "file":
+		if u.RawQuery != "" {
+			return errors.New("local buckets do not support query parameters")
+		}
+		if _, err := r.resolver.LocalPath(u.Path); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported scheme %q (supported: %s)", u.Scheme, strings.Join(supportedSchemes, ", "))
+	}
+
+	return nil
+}
+
+func (r *bucketRepository) checkS3Params(query url.Values) error {
+	for key, values := range query {
+		if !slices.Contains(s3Params, key) {
+			return fmt.Errorf("unsupported query parameter %q (supported: %s)", key, strings.Join(s3Params, ", "))
+		}
+		if len(values) != 1 {
+			return fmt.Errorf("query parameter %q must be set once", key)
+		}
+	}
+
+	if !regionPattern.MatchString(query.Get("region")) {
+		return fmt.Errorf("invalid region %q", query.Get("region"))
+	}
+
+	if endpoint := query.Get("endpoint"); endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q", endpoint)
+		}
+		if u.User != nil || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("the endpoint %q must only have a scheme and a host", endpoint)
+		}
+		if !slices.Contains(r.permittedEndpoints, u.Scheme+"://"+u.Host) {
+			return fmt.Errorf("the endpoint %q is not permitted", endpoint)
+		}
+	}
+
+	if usePathStyle := query.Get("use_path_style"); usePathStyle != "" && usePathStyle != "true" && usePathStyle != "false" {
+		return fmt.Errorf("invalid use_path_style %q", usePathStyle)
+	}
+
+	return nil
+}
+
+// open the configured bucket. The returned bucket only sees the objects under the configured path.
+func (r *bucketRepository) open(ctx context.Context) (*blob.Bucket, error) {
+	cfg := r.config.Spec.Bucket
+	if cfg == nil {
+		return nil, apierrors.NewBadRequest("missing bucket configuration")
+	}
+	if err := r.checkURL(); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	var b *blob.Bucket
+	u, err := url.Parse(cfg.URL)
+	if err == nil && u.Scheme == "s3" {
+		b, err = s3blob.OpenBucket(ctx, r.s3Client(u.Query()), u.Host, nil)
+	} else {
+		b, err = blob.OpenBucket(ctx, cfg.URL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open bucket: %w", err)
+	}
+
+	if r.prefix != "" {
+		b = blob.PrefixedBucket(b, r.prefix)
+	}
+
+	return b, nil
+}
+
+// s3Client uses the credentials of the repository. Unlike the s3 URL opener, it never loads the credentials
+// or the configuration of the server (environment, shared files or instance metadata).
+func (r *bucketRepository) s3Client(query url.Values) *s3.Client {
+	opts := s3.Options{
+		Region:       query.Get("region"),
+		UsePathStyle: query.Get("use_path_style") == "true",
+		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+			r.config.Spec.Bucket.AccessKeyID, string(r.secretKey), "",
+		)),
+	}
+	if endpoint := query.Get("endpoint"); endpoint != "" {
+		opts.BaseEndpoint = aws.String(endpoint)
+	}
+
+	return s3.New(opts)
+}
+
+func (r *bucketRepository) close(ctx context.Context, b *blob.Bucket) {
+	if err := b.Close(); err != nil {
+		logging.FromContext(ctx).Warn("failed to close bucket", "error", err)
+	}
+}
+
+func (r *bucketRepository) validateRequest(ref string) error {
+	if ref != "" {
+		return apierrors.NewBadRequest("bucket repository does not support writing to a ref")
+	}
+
+	return nil
+}
+
+// Read implements provisioning.Repository.
+// The ref is either empty, a tree reference (see LatestRef) or an object version (see History).
+// Tree references always read the latest content, as the bucket does not keep the older trees.
+func (r *bucketRepository) Read(ctx context.Context, filePath, ref string) (*repository.FileInfo, error) {
+	if isReserved(filePath) {
+		return nil, repository.ErrFileNotFound
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close(ctx, b)
+
+	if filePath == "" || safepath.IsDir(filePath) {
+		return r.readDir(ctx, b, filePath, ref)
+	}
+
+	opts := &blob.ReaderOptions{}
+	if ref != "" && !isTreeRef(ref) {
+		opts.BeforeRead = readVersion(ref)
+	}
+
+	reader, err := b.NewReader(ctx, filePath, opts)
+	switch {
+	case errors.Is(err, ErrVersionsNotSupported):
+		return nil, ErrVersionsNotSupported
+	case gcerrors.Code(err) == gcerrors.NotFound:
+		// It may be a directory without the trailing slash
+		return r.readDir(ctx, b, filePath+"/", ref)
+	case err != nil:
+		return nil, fmt.Errorf("open object: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read object: %w", err)
+	}
+
+	return &repository.FileInfo{
+		Path: filePath,
+		Data: data,
+		Ref:  ref,
+		Hash: hashOf(data),
+		Modified: &metav1.Time{
+			Time: reader.ModTime(),
+		},
+	}, nil
+}
+
+func (r *bucketRepository) readDir(ctx context.Context, b *blob.Bucket, dir, ref string) (*repository.FileInfo, error) {
+	if dir != "" {
+		exists, err := dirExists(ctx, b, dir)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, repository.ErrFileNotFound
+		}
+	}
+
+	return &repository.FileInfo{
+		Path: dir,
+		Ref:  ref,
+	}, nil
+}
+
+// ReadTree implements provisioning.Repository.
+// Buckets have no directories, so they are derived from the object keys.
+func (r *bucketRepository) ReadTree(ctx context.Context, ref string) ([]repository.FileTreeEntry, error) {
+	if ref != "" && !isTreeRef(ref) {
+		return nil, apierrors.NewBadRequest("the tree can only be read for the latest version")
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close(ctx, b)
+
+	return listTree(ctx, b)
+}
+
+func listTree(ctx context.Context, b *blob.Bucket) ([]repository.FileTreeEntry, error) {
+	entries := make([]repository.FileTreeEntry, 0, 100)
+	dirs := make(map[string]bool)
+
+	iter := b.List(nil)
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list objects: %w", err)
+		}
+		if obj.IsDir || isReserved(obj.Key) {
+			continue
+		}
+
+		for dir := safepath.Dir(obj.Key); dir != "" && !dirs[dir]; dir = safepath.Dir(dir) {
+			dirs[dir] = true
+			entries = append(entries, repository.FileTreeEntry{Path: dir})
+		}
+
+		hash := hex.EncodeToString(obj.MD5)
+		if hash == "" {
+			// Objects not written through gocloud (or uploaded in parts) may not report the MD5
+			data, err := b.ReadAll(ctx, obj.Key)
+			if err != nil {
+				return nil, fmt.Errorf("read object %s: %w", obj.Key, err)
+			}
+			hash = hashOf(data)
+		}
+
+		entries = append(entries, repository.FileTreeEntry{
+			Path: obj.Key,
+			Hash: hash,
+			Size: obj.Size,
+			Blob: true,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries, nil
+}
+
+func (r *bucketRepository) Create(ctx context.Context, filePath, ref string, data []byte, comment string) error {
+	if err := r.validateRequest(ref); err != nil {
+		return err
+	}
+	if isReserved(filePath) {
+		return apierrors.NewBadRequest("the path is reserved")
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.close(ctx, b)
+
+	if safepath.IsDir(filePath) {
+		if data != nil {
+			return apierrors.NewBadRequest("data cannot be provided for a directory")
+		}
+
+		exists, err := dirExists(ctx, b, filePath)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return apierrors.NewAlreadyExists(schema.GroupResource{}, filePath)
+		}
+
+		// Directories only exist if they contain an object
+		return writeObject(ctx, b, safepath.Join(filePath, ".keep"), []byte{})
+	}
+
+	exists, err := b.Exists(ctx, filePath)
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to check if file exists: %w", err))
+	}
+	if exists {
+		return apierrors.NewAlreadyExists(schema.GroupResource{}, filePath)
+	}
+
+	return writeObject(ctx, b, filePath, data)
+}
+
+func (r *bucketRepository) Update(ctx context.Context, filePath, ref string, data []byte, comment string) error {
+	if err := r.validateRequest(ref); err != nil {
+		return err
+	}
+	if safepath.IsDir(filePath) {
+		return apierrors.NewBadRequest("cannot update a directory")
+	}
+	if isReserved(filePath) {
+		return apierrors.NewBadRequest("the path is reserved")
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.close(ctx, b)
+
+	exists, err := b.Exists(ctx, filePath)
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to check if file exists: %w", err))
+	}
+	if !exists {
+		return repository.ErrFileNotFound
+	}
+
+	return writeObject(ctx, b, filePath, data)
+}
+
+func (r *bucketRepository) Write(ctx context.Context, filePath, ref string, data []byte, comment string) error {
+	if err := r.validateRequest(ref); err != nil {
+		return err
+	}
+	if isReserved(filePath) {
+		return apierrors.NewBadRequest("the path is reserved")
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.close(ctx, b)
+
+	if safepath.IsDir(filePath) {
+		exists, err := dirExists(ctx, b, filePath)
+		if err != nil || exists {
+			return err
+		}
+		return writeObject(ctx, b, safepath.Join(filePath, ".keep"), []byte{})
+	}
+
+	return writeObject(ctx, b, filePath, data)
+}
+
+func (r *bucketRepository) Delete(ctx context.Context, filePath, ref, comment string) error {
+	if err := r.validateRequest(ref); err != nil {
+		return err
+	}
+	if isReserved(filePath) {
+		return apierrors.NewBadRequest("the path is reserved")
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.close(ctx, b)
+
+	if !safepath.IsDir(filePath) {
+		err := b.Delete(ctx, filePath)
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return repository.ErrFileNotFound
+		}
+		return err
+	}
+
+	// if it is a folder, delete all of its contents
+	keys, err := listKeys(ctx, b, filePath)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return repository.ErrFileNotFound
+	}
+	for _, key := range keys {
+		if err := b.Delete(ctx, key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Move copies the objects to the new path, and deletes the old ones.
+// This is not atomic: a failure may leave a copy in both locations.
+func (r *bucketRepository) Move(ctx context.Context, oldPath, newPath, ref, comment string) error {
+	if err := r.validateRequest(ref); err != nil {
+		return err
+	}
+	if isReserved(oldPath) || isReserved(newPath) {
+		return apierrors.NewBadRequest("the path is reserved")
+	}
+
+	sourceIsDir := safepath.IsDir(oldPath)
+	if sourceIsDir != safepath.IsDir(newPath) {
+		return apierrors.NewBadRequest("cannot move between file and directory types")
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.close(ctx, b)
+
+	var keys []string
+	if sourceIsDir {
+		keys, err = listKeys(ctx, b, oldPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		exists, err := b.Exists(ctx, oldPath)
+		if err != nil {
+			return fmt.Errorf("check source: %w", err)
+		}
+		if exists {
+			keys = []string{oldPath}
+		}
+	}
+	if len(keys) == 0 {
+		return repository.ErrFileNotFound
+	}
+
+	// Check if destination already exists
+	if sourceIsDir {
+		exists, err := dirExists(ctx, b, newPath)
+		if err != nil {
+			return fmt.Errorf("check destination: %w", err)
+		}
+		if exists {
+			return repository.ErrFileAlreadyExists
+		}
+	} else {
+		exists, err := b.Exists(ctx, newPath)
+		if err != nil {
+			return fmt.Errorf("check destination: %w", err)
+		}
+		if exists {
+			return repository.ErrFileAlreadyExists
+		}
+	}
+
+	for _, key := range keys {
+		target := newPath + strings.TrimPrefix(key, oldPath)
+		if err := b.Copy(ctx, target, key, nil); err != nil {
+			return fmt.Errorf("copy %s: %w", key, err)
+		}
+	}
+	for _, key := range keys {
+		if err := b.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func writeObject(ctx context.Context, b *blob.Bucket, key string, data []byte) error {
+	if err := b.WriteAll(ctx, key, data, nil); err != nil {
+		return fmt.Errorf("write object %s: %w", key, err)
+	}
+	return nil
+}
+
+// listKeys returns all the object keys under a directory
+func listKeys(ctx context.Context, b *blob.Bucket, dir string) ([]string, error) {
+	var keys []string
+	iter := b.List(&blob.ListOptions{Prefix: dir})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list objects: %w", err)
+		}
+		if !isReserved(obj.Key) {
+			keys = append(keys, obj.Key)
+		}
+	}
+}
+
+func dirExists(ctx context.Context, b *blob.Bucket, dir string) (bool, error) {
+	iter := b.List(&blob.ListOptions{Prefix: dir})
+	_, err := iter.Next(ctx)
+	switch {
+	case errors.Is(err, io.EOF):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("list objects: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+func isReserved(key string) bool {
+	return key+"/" == metadataPrefix || strings.HasPrefix(key, metadataPrefix)
+}
+
+func hashOf(data []byte) string {
+	//nolint:gosec
+	sum := md5.Sum(data)
+	// NOTE: EncodeToString (& hex.Encode for that matter) return lower-case hex.
+	return hex.EncodeToString(sum[:])
+}
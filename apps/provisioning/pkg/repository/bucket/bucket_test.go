@@ -0,0 +1,377 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository/local"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+)
+
+// newTestRepository returns a repository backed by a local file:// bucket
+func newTestRepository(t *testing.T, prefix string) (*bucketRepository, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo := NewRepository(&provisioning.Repository{
+		Spec: provisioning.RepositorySpec{
+			Title: "bucket",
+			Type:  provisioning.BucketRepositoryType,
+			Bucket: &provisioning.BucketRepositoryConfig{
+				URL:  "file://" + dir,
+				Path: prefix,
+			},
+		},
+	}, &local.LocalFolderResolver{PermittedPrefixes: []string{dir}}, nil, "")
+
+	return repo, dir
+}
+
+func TestBucketRepository_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *provisioning.BucketRepositoryConfig
+		secret   common.RawSecureValue // the secret access key, "-" for none
+		expected field.ErrorList
+	}{
+		{
+			name: "missing config",
+			expected: field.ErrorList{&field.Error{
+				Type:  field.ErrorTypeRequired,
+				Field: "spec.bucket",
+			}},
+		},
+		{
+			name:   "missing url",
+			config: &provisioning.BucketRepositoryConfig{},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("spec", "bucket", "url"), "a bucket URL is required"),
+			},
+		},
+		{
+			name:   "s3 bucket",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=us-east-1", Path: "grafana", AccessKeyID: "key"},
+		},
+		{
+			name:   "s3 bucket with permitted endpoint",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=us-east-1&endpoint=https://minio.example.com:9000&use_path_style=true", AccessKeyID: "key"},
+		},
+		{
+			name:   "s3 without bucket name",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3:///", AccessKeyID: "key"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "s3:///", "missing bucket name"),
+			},
+		},
+		{
+			name:   "s3 without region",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards", AccessKeyID: "key"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "s3://dashboards", `invalid region ""`),
+			},
+		},
+		{
+			name:   "s3 with region in a host name",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=internal.example.com%23", AccessKeyID: "key"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "s3://dashboards?region=internal.example.com%23", `invalid region "internal.example.com#"`),
+			},
+		},
+		{
+			name:   "s3 with endpoint that is not permitted",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=us-east-1&endpoint=http://169.254.169.254", AccessKeyID: "key"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "s3://dashboards?region=us-east-1&endpoint=http://169.254.169.254",
+					`the endpoint "http://169.254.169.254" is not permitted`),
+			},
+		},
+		{
+			name:   "s3 with endpoint path",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=us-east-1&endpoint=https://minio.example.com:9000/internal", AccessKeyID: "key"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "s3://dashboards?region=us-east-1&endpoint=https://minio.example.com:9000/internal",
+					`the endpoint "https://minio.example.com:9000/internal" must only have a scheme and a host`),
+			},
+		},
+		{
+			name:   "s3 with unsupported parameter",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=us-east-1&profile=admin", AccessKeyID: "key"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "s3://dashboards?region=us-east-1&profile=admin",
+					`unsupported query parameter "profile" (supported: region, endpoint, use_path_style)`),
+			},
+		},
+		{
+			name:   "s3 without credentials",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=us-east-1"},
+			secret: "-",
+			expected: field.ErrorList{
+				field.Required(field.NewPath("spec", "bucket", "accessKeyId"), "an access key ID is required"),
+				field.Required(field.NewPath("secure", "token"), "a secret access key is required"),
+			},
+		},
+		{
+			name:   "unsupported scheme",
+			config: &provisioning.BucketRepositoryConfig{URL: "mem://"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "mem://", `unsupported scheme "mem" (supported: s3, file)`),
+			},
+		},
+		{
+			name:   "local bucket in permitted path",
+			config: &provisioning.BucketRepositoryConfig{URL: "file:///var/lib/grafana/bucket"},
+		},
+		{
+			name:   "local bucket with parameters",
+			config: &provisioning.BucketRepositoryConfig{URL: "file:///var/lib/grafana/bucket?secret_key_path=/etc/passwd"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "file:///var/lib/grafana/bucket?secret_key_path=/etc/passwd",
+					"local buckets do not support query parameters"),
+			},
+		},
+		{
+			name:   "local bucket outside permitted path",
+			config: &provisioning.BucketRepositoryConfig{URL: "file:///etc"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "url"), "file:///etc",
+					"the path given ('/etc') is invalid for a local repository (the path matches no permitted prefix)"),
+			},
+		},
+		{
+			name:   "unsafe path",
+			config: &provisioning.BucketRepositoryConfig{URL: "s3://dashboards?region=us-east-1", Path: "../secrets", AccessKeyID: "key"},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "bucket", "path"), "../secrets", "path contains traversal attempt (./ or ../)"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := tt.secret
+			switch secret {
+			case "":
+				secret = "secret"
+			case "-":
+				secret = ""
+			}
+			repo := NewRepository(&provisioning.Repository{
+				Spec: provisioning.RepositorySpec{
+					Type:   provisioning.BucketRepositoryType,
+					Bucket: tt.config,
+				},
+			}, &local.LocalFolderResolver{PermittedPrefixes: []string{"/var/lib/grafana"}}, []string{"https://minio.example.com:9000"}, secret)
+
+			require.Equal(t, tt.expected, repo.Validate())
+		})
+	}
+}
+
+func TestBucketRepository_Test(t *testing.T) {
+	repo, dir := newTestRepository(t, "")
+
+	results, err := repo.Test(context.Background())
+	require.NoError(t, err)
+	require.True(t, results.Success)
+	require.Equal(t, http.StatusOK, results.Code)
+
+	require.NoError(t, os.RemoveAll(dir))
+	results, err = repo.Test(context.Background())
+	require.NoError(t, err)
+	require.False(t, results.Success)
+}
+
+func TestBucketRepository_ReadWrite(t *testing.T) {
+	ctx := context.Background()
+	repo, dir := newTestRepository(t, "grafana")
+
+	require.NoError(t, repo.Create(ctx, "dashboards/a.json", "", []byte(`{"a":1}`), "create"))
+	err := repo.Create(ctx, "dashboards/a.json", "", []byte(`{"a":1}`), "create")
+	require.True(t, apierrors.IsAlreadyExists(err))
+
+	// Objects are stored under the prefix
+	_, err = os.Stat(filepath.Join(dir, "grafana", "dashboards", "a.json"))
+	require.NoError(t, err)
+
+	info, err := repo.Read(ctx, "dashboards/a.json", "")
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`, string(info.Data))
+	require.Equal(t, hashOf([]byte(`{"a":1}`)), info.Hash)
+	require.NotNil(t, info.Modified)
+
+	require.NoError(t, repo.Update(ctx, "dashboards/a.json", "", []byte(`{"a":2}`), "update"))
+	info, err = repo.Read(ctx, "dashboards/a.json", "")
+	require.NoError(t, err)
+	require.Equal(t, `{"a":2}`, string(info.Data))
+
+	err = repo.Update(ctx, "dashboards/missing.json", "", []byte(`{}`), "update")
+	require.ErrorIs(t, err, repository.ErrFileNotFound)
+
+	// Directories exist when they contain objects
+	info, err = repo.Read(ctx, "dashboards/", "")
+	require.NoError(t, err)
+	require.Nil(t, info.Data)
+	_, err = repo.Read(ctx, "dashboards", "")
+	require.NoError(t, err)
+	_, err = repo.Read(ctx, "other/", "")
+	require.ErrorIs(t, err, repository.ErrFileNotFound)
+
+	require.NoError(t, repo.Create(ctx, "empty/", "", nil, "create folder"))
+	_, err = repo.Read(ctx, "empty/", "")
+	require.NoError(t, err)
+
+	// Writes to a ref are not supported
+	err = repo.Write(ctx, "dashboards/a.json", "main", []byte(`{}`), "write")
+	require.True(t, apierrors.IsBadRequest(err))
+
+	// Reading an old version needs a bucket with object versions
+	_, err = repo.Read(ctx, "dashboards/a.json", "some-version")
+	require.ErrorIs(t, err, ErrVersionsNotSupported)
+
+	require.NoError(t, repo.Move(ctx, "dashboards/", "moved/", "", "move"))
+	_, err = repo.Read(ctx, "dashboards/a.json", "")
+	require.ErrorIs(t, err, repository.ErrFileNotFound)
+	_, err = repo.Read(ctx, "moved/a.json", "")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(ctx, "moved/a.json", "", "delete"))
+	_, err = repo.Read(ctx, "moved/a.json", "")
+	require.ErrorIs(t, err, repository.ErrFileNotFound)
+
+	require.NoError(t, repo.Delete(ctx, "empty/", "", "delete folder"))
+	_, err = repo.Read(ctx, "empty/", "")
+	require.ErrorIs(t, err, repository.ErrFileNotFound)
+}
+
+func TestBucketRepository_ReadTree(t *testing.T) {
+	ctx := context.Background()
+	repo, dir := newTestRepository(t, "")
+
+	require.NoError(t, repo.Write(ctx, "a/b/c.json", "", []byte(`{"c":1}`), ""))
+	require.NoError(t, repo.Write(ctx, "root.json", "", []byte(`{"root":1}`), ""))
+
+	// Files copied into the bucket (not written by gocloud) have no stored MD5
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "copied.yaml"), []byte("kind: Dashboard"), 0600))
+
+	// The snapshots are not part of the tree
+	_, err := repo.LatestRef(ctx)
+	require.NoError(t, err)
+
+	entries, err := repo.ReadTree(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, []repository.FileTreeEntry{
+		{Path: "a/"},
+		{Path: "a/b/"},
+		{Path: "a/b/c.json", Hash: hashOf([]byte(`{"c":1}`)), Size: 7, Blob: true},
+		{Path: "copied.yaml", Hash: hashOf([]byte("kind: Dashboard")), Size: 15, Blob: true},
+		{Path: "root.json", Hash: hashOf([]byte(`{"root":1}`)), Size: 10, Blob: true},
+	}, entries)
+
+	_, err = repo.Read(ctx, ".grafana/", "")
+	require.ErrorIs(t, err, repository.ErrFileNotFound)
+	err = repo.Write(ctx, ".grafana/refs/x.json", "", []byte(`{}`), "")
+	require.True(t, apierrors.IsBadRequest(err))
+}
+
+func TestBucketRepository_Versions(t *testing.T) {
+	ctx := context.Background()
+	repo, dir := newTestRepository(t, "")
+
+	require.NoError(t, repo.Write(ctx, "keep.json", "", []byte(`{"keep":1}`), ""))
+	require.NoError(t, repo.Write(ctx, "change.json", "", []byte(`{"change":1}`), ""))
+	require.NoError(t, repo.Write(ctx, "remove.json", "", []byte(`{"remove":1}`), ""))
+
+	base, err := repo.LatestRef(ctx)
+	require.NoError(t, err)
+	require.True(t, isTreeRef(base))
+
+	// Polling returns the same ref while nothing changes
+	again, err := repo.LatestRef(ctx)
+	require.NoError(t, err)
+	require.Equal(t, base, again)
+
+	require.NoError(t, repo.Write(ctx, "change.json", "", []byte(`{"change":2}`), ""))
+	require.NoError(t, repo.Delete(ctx, "remove.json", "", ""))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "added.json"), []byte(`{"added":1}`), 0600))
+
+	latest, err := repo.LatestRef(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, base, latest)
+
+	// The tree refs can be used to read the latest content
+	info, err := repo.Read(ctx, "change.json", latest)
+	require.NoError(t, err)
+	require.Equal(t, `{"change":2}`, string(info.Data))
+
+	changes, err := repo.CompareFiles(ctx, base, latest)
+	require.NoError(t, err)
+	require.Equal(t, []repository.VersionedFileChange{
+		{Action: repository.FileActionCreated, Path: "added.json", Ref: latest},
+		{Action: repository.FileActionUpdated, Path: "change.json", Ref: latest, PreviousRef: base},
+		{Action: repository.FileActionDeleted, Path: "remove.json", Ref: latest, PreviousRef: base},
+	}, changes)
+
+	_, err = repo.CompareFiles(ctx, "0000000000000000000000000000000000000000", latest)
+	require.ErrorIs(t, err, repository.ErrRefNotFound)
+
+	refs, err := repo.ListRefs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []provisioning.RefItem{{Name: latest, Hash: latest}}, refs)
+
+	// Without object versions, only the current version is known
+	history, err := repo.History(ctx, "change.json", "")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "current version", history[0].Message)
+	assert.NotZero(t, history[0].CreatedAt)
+
+	_, err = repo.History(ctx, "remove.json", "")
+	require.ErrorIs(t, err, repository.ErrFileNotFound)
+}
+
+func TestBucketRepository_PruneSnapshots(t *testing.T) {
+	ctx := context.Background()
+	repo, dir := newTestRepository(t, "")
+
+	refs := make([]string, 0, maxSnapshots+2)
+	for i := 0; i < maxSnapshots+2; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "file.json"), []byte(fmt.Sprintf(`{"version":%d}`, i)), 0600))
+		ref, err := repo.LatestRef(ctx)
+		require.NoError(t, err)
+		refs = append(refs, ref)
+
+		// The modification time decides which snapshots are the oldest ones
+		modified := time.Now().Add(time.Duration(i-maxSnapshots-2) * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, refsPrefix, ref+".json"), modified, modified))
+	}
+
+	snapshots, err := filepath.Glob(filepath.Join(dir, refsPrefix, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, snapshots, maxSnapshots)
+
+	_, err = repo.CompareFiles(ctx, refs[0], refs[len(refs)-1])
+	require.ErrorIs(t, err, repository.ErrRefNotFound)
+
+	changes, err := repo.CompareFiles(ctx, refs[len(refs)-2], refs[len(refs)-1])
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+}
+
+func TestIsTreeRef(t *testing.T) {
+	require.True(t, isTreeRef(treeRef(map[string]string{"a.json": "hash"})))
+	require.False(t, isTreeRef(""))
+	require.False(t, isTreeRef("3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY+MTRCxf3vjVBH40Nr8X8gdRQBpUMLUo"))
+	require.False(t, isTreeRef("ABCDEF0123456789ABCDEF0123456789ABCDEF01"))
+}
@@ -0,0 +1,52 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository/local"
+	"github.com/grafana/grafana/apps/provisioning/pkg/safepath"
+)
+
+type extra struct {
+	resolver           *local.LocalFolderResolver
+	permittedEndpoints []string
+	decrypter          repository.Decrypter
+}
+
+// Extra registers the bucket repository type.
+// Local buckets (file://) are only allowed in the permitted prefixes, just like local repositories,
+// and S3 buckets can only use the AWS endpoints or the permitted ones.
+func Extra(homePath string, permittedPrefixes []string, permittedEndpoints []string, decrypter repository.Decrypter) repository.Extra {
+	resolver := &local.LocalFolderResolver{
+		PermittedPrefixes: permittedPrefixes,
+		HomePath:          safepath.Clean(homePath),
+	}
+
+	return &extra{
+		resolver:           resolver,
+		permittedEndpoints: permittedEndpoints,
+		decrypter:          decrypter,
+	}
+}
+
+func (e *extra) Type() provisioning.RepositoryType {
+	return provisioning.BucketRepositoryType
+}
+
+func (e *extra) Build(ctx context.Context, r *provisioning.Repository) (repository.Repository, error) {
+	secretKey, err := e.decrypter(r).Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt token: %w", err)
+	}
+
+	return NewRepository(r, e.resolver, e.permittedEndpoints, secretKey), nil
+}
+
+func (e *extra) Mutate(_ context.Context, _ runtime.Object) error {
+	return nil
+}
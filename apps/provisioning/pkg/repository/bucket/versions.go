@@ -0,0 +1,368 @@
+package bucket
+
+import (
+	"context"
+	// Refs use the same length as git commits, so they are easy to tell apart from object versions
+	//nolint:gosec
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/grafana/grafana-app-sdk/logging"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+)
+
+// ErrVersionsNotSupported is returned when reading an older version from a bucket without object versions.
+var ErrVersionsNotSupported = apierrors.NewBadRequest("the bucket does not keep object versions")
+
+// Snapshots of the tree (path -> hash) for each ref we have returned, so we can compare them later
+const refsPrefix = metadataPrefix + "refs/"
+
+// The number of snapshots kept in the bucket. A sync from an older ref does not find it, and compares everything.
+const maxSnapshots = 10
+
+// LatestRef returns a digest of the current tree.
+// Buckets have no commits, so this changes whenever any object is added, changed or removed.
+// This is what the controller uses to poll for changes.
+func (r *bucketRepository) LatestRef(ctx context.Context) (string, error) {
+	b, err := r.open(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer r.close(ctx, b)
+
+	entries, err := listTree(ctx, b)
+	if err != nil {
+		return "", err
+	}
+
+	files := toManifest(entries)
+	ref := treeRef(files)
+
+	// Keep a snapshot so the next sync can compare against it.
+	// This is best effort: a read-only bucket will still work, but will always need a full sync.
+	key := refsPrefix + ref + ".json"
+	exists, err := b.Exists(ctx, key)
+	if err == nil && !exists {
+		var data []byte
+		data, err = json.Marshal(files)
+		if err == nil {
+			err = b.WriteAll(ctx, key, data, nil)
+		}
+		// Only a new snapshot can go over the limit
+		if err == nil {
+			err = pruneSnapshots(ctx, b, key)
+		}
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to save the tree snapshot", "ref", ref, "error", err)
+	}
+
+	return ref, nil
+}
+
+// ListRefs implements repository.Versioned.
+// Buckets have no branches, so this only returns the latest ref.
+func (r *bucketRepository) ListRefs(ctx context.Context) ([]provisioning.RefItem, error) {
+	ref, err := r.LatestRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []provisioning.RefItem{{
+		Name: ref,
+		Hash: ref,
+	}}, nil
+}
+
+// CompareFiles implements repository.Versioned.
+// It returns ErrRefNotFound when there is no snapshot for one of the refs.
+func (r *bucketRepository) CompareFiles(ctx context.Context, base, ref string) ([]repository.VersionedFileChange, error) {
+	b, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close(ctx, b)
+
+	before, err := readManifest(ctx, b, base)
+	if err != nil {
+		return nil, err
+	}
+
+	var after map[string]string
+	if ref == "" {
+		entries, err := listTree(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		after = toManifest(entries)
+	} else {
+		after, err = readManifest(ctx, b, ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make([]repository.VersionedFileChange, 0)
+	for path, hash := range after {
+		previous, ok := before[path]
+		switch {
+		case !ok:
+			changes = append(changes, repository.VersionedFileChange{
+				Action: repository.FileActionCreated,
+				Path:   path,
+				Ref:    ref,
+			})
+		case previous != hash:
+			changes = append(changes, repository.VersionedFileChange{
+				Action:      repository.FileActionUpdated,
+				Path:        path,
+				Ref:         ref,
+				PreviousRef: base,
+			})
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changes = append(changes, repository.VersionedFileChange{
+				Action:      repository.FileActionDeleted,
+				Path:        path,
+				Ref:         ref,
+				PreviousRef: base,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// History returns the object versions when the bucket keeps them (S3 buckets with versioning enabled).
+// Otherwise, only the current object is returned.
+func (r *bucketRepository) History(ctx context.Context, filePath, ref string) ([]provisioning.HistoryItem, error) {
+	if isReserved(filePath) {
+		return nil, repository.ErrFileNotFound
+	}
+
+	b, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close(ctx, b)
+
+	var client *s3.Client
+	if b.As(&client) {
+		return r.objectVersions(ctx, client, filePath)
+	}
+
+	attrs, err := b.Attributes(ctx, filePath)
+	if gcerrors.Code(err) == gcerrors.NotFound {
+		return nil, repository.ErrFileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read attributes: %w", err)
+	}
+
+	return []provisioning.HistoryItem{{
+		Message:   "current version",
+		Authors:   []provisioning.Author{},
+		CreatedAt: attrs.ModTime.UnixMilli(),
+	}}, nil
+}
+
+func (r *bucketRepository) objectVersions(ctx context.Context, client *s3.Client, filePath string) ([]provisioning.HistoryItem, error) {
+	u, err := url.Parse(r.config.Spec.Bucket.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %w", err)
+	}
+
+	key := r.prefix + filePath
+	history := make([]provisioning.HistoryItem, 0)
+	pages := s3.NewListObjectVersionsPaginator(client, &s3.ListObjectVersionsInput{
+		Bucket: aws.String(u.Host),
+		Prefix: aws.String(key),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list object versions: %w", err)
+		}
+
+		for _, v := range page.Versions {
+			// The prefix also matches longer keys
+			if aws.ToString(v.Key) != key {
+				continue
+			}
+
+			item := provisioning.HistoryItem{
+				Ref:     aws.ToString(v.VersionId),
+				Message: "updated",
+				Authors: []provisioning.Author{},
+			}
+			if v.LastModified != nil {
+				item.CreatedAt = v.LastModified.UnixMilli()
+			}
+			if v.Owner != nil && v.Owner.DisplayName != nil {
+				item.Authors = append(item.Authors, provisioning.Author{
+					Name:     aws.ToString(v.Owner.DisplayName),
+					Username: aws.ToString(v.Owner.ID),
+				})
+			}
+			history = append(history, item)
+		}
+
+		for _, m := range page.DeleteMarkers {
+			if aws.ToString(m.Key) != key {
+				continue
+			}
+
+			item := provisioning.HistoryItem{
+				Ref:     aws.ToString(m.VersionId),
+				Message: "deleted",
+				Authors: []provisioning.Author{},
+			}
+			if m.LastModified != nil {
+				item.CreatedAt = m.LastModified.UnixMilli()
+			}
+			history = append(history, item)
+		}
+	}
+
+	if len(history) == 0 {
+		return nil, repository.ErrFileNotFound
+	}
+
+	// Newest first, like the git history
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt > history[j].CreatedAt
+	})
+
+	return history, nil
+}
+
+// readVersion sets the object version when reading from S3
+func readVersion(version string) func(func(any) bool) error {
+	return func(as func(any) bool) error {
+		var input *s3.GetObjectInput
+		if !as(&input) {
+			return ErrVersionsNotSupported
+		}
+		input.VersionId = aws.String(version)
+		return nil
+	}
+}
+
+// pruneSnapshots removes the oldest snapshots, keeping the given one
+func pruneSnapshots(ctx context.Context, b *blob.Bucket, keep string) error {
+	type snapshot struct {
+		key      string
+		modified time.Time
+	}
+
+	snapshots := make([]snapshot, 0, maxSnapshots+1)
+	iter := b.List(&blob.ListOptions{Prefix: refsPrefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("list snapshots: %w", err)
+		}
+		if !obj.IsDir {
+			snapshots = append(snapshots, snapshot{key: obj.Key, modified: obj.ModTime})
+		}
+	}
+	if len(snapshots) <= maxSnapshots {
+		return nil
+	}
+
+	// Newest first
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].modified.After(snapshots[j].modified)
+	})
+	for _, s := range snapshots[maxSnapshots:] {
+		if s.key == keep {
+			continue
+		}
+		if err := b.Delete(ctx, s.key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return fmt.Errorf("delete snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func readManifest(ctx context.Context, b *blob.Bucket, ref string) (map[string]string, error) {
+	if !isTreeRef(ref) {
+		return nil, repository.ErrRefNotFound
+	}
+
+	data, err := b.ReadAll(ctx, refsPrefix+ref+".json")
+	if gcerrors.Code(err) == gcerrors.NotFound {
+		return nil, repository.ErrRefNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+
+	files := make(map[string]string)
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("parse snapshot: %w", err)
+	}
+
+	return files, nil
+}
+
+// toManifest returns the hash of every file in the tree
+func toManifest(entries []repository.FileTreeEntry) map[string]string {
+	files := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.Blob {
+			files[entry.Path] = entry.Hash
+		}
+	}
+	return files
+}
+
+func treeRef(files map[string]string) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	//nolint:gosec
+	hasher := sha1.New()
+	for _, path := range paths {
+		_, _ = fmt.Fprintf(hasher, "%s\x00%s\n", path, files[path])
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// isTreeRef checks if the ref was returned by LatestRef (otherwise, it is an object version)
+func isTreeRef(ref string) bool {
+	if len(ref) != sha1.Size*2 || strings.ToLower(ref) != ref {
+		return false
+	}
+	_, err := hex.DecodeString(ref)
+	return err == nil
+}
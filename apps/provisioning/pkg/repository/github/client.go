@@ -22,6 +22,8 @@ var (
 type Client interface {
 	// Commits
 	Commits(ctx context.Context, owner, repository, path, branch string) ([]Commit, error)
+	GetCommit(ctx context.Context, owner, repository, ref string) (Commit, error)
+	CompareCommits(ctx context.Context, owner, repository, base, head string) ([]Commit, error)
 
 	// Webhooks
 	ListWebhooks(ctx context.Context, owner, repository string) ([]WebhookConfig, error)
@@ -41,12 +43,20 @@ type CommitAuthor struct {
 	AvatarURL string
 }
 
+// CommitVerification is the signature verification of a commit, as reported by GitHub.
+type CommitVerification struct {
+	Verified bool
+	// The reason is one of the GitHub verification reasons, e.g. "valid", "unsigned" or "unknown_key".
+	Reason string
+}
+
 type Commit struct {
-	Ref       string
-	Message   string
-	Author    *CommitAuthor
-	Committer *CommitAuthor
-	CreatedAt time.Time
+	Ref          string
+	Message      string
+	Author       *CommitAuthor
+	Committer    *CommitAuthor
+	CreatedAt    time.Time
+	Verification *CommitVerification
 }
 
 //go:generate mockery --name CommitFile --structname MockCommitFile --inpackage --filename mock_commit_file.go --with-expecter
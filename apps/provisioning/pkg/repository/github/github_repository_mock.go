@@ -1044,6 +1044,66 @@ func (_c *MockGithubRepository_Validate_Call) RunAndReturn(run func() field.Erro
 	return _c
 }
 
+// VerifyCommits provides a mock function with given fields: ctx, base, ref
+func (_m *MockGithubRepository) VerifyCommits(ctx context.Context, base string, ref string) ([]v0alpha1.SignatureVerification, error) {
+	ret := _m.Called(ctx, base, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyCommits")
+	}
+
+	var r0 []v0alpha1.SignatureVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]v0alpha1.SignatureVerification, error)); ok {
+		return rf(ctx, base, ref)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []v0alpha1.SignatureVerification); ok {
+		r0 = rf(ctx, base, ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v0alpha1.SignatureVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, base, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGithubRepository_VerifyCommits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyCommits'
+type MockGithubRepository_VerifyCommits_Call struct {
+	*mock.Call
+}
+
+// VerifyCommits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - base string
+//   - ref string
+func (_e *MockGithubRepository_Expecter) VerifyCommits(ctx interface{}, base interface{}, ref interface{}) *MockGithubRepository_VerifyCommits_Call {
+	return &MockGithubRepository_VerifyCommits_Call{Call: _e.mock.On("VerifyCommits", ctx, base, ref)}
+}
+
+func (_c *MockGithubRepository_VerifyCommits_Call) Run(run func(ctx context.Context, base string, ref string)) *MockGithubRepository_VerifyCommits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGithubRepository_VerifyCommits_Call) Return(_a0 []v0alpha1.SignatureVerification, _a1 error) *MockGithubRepository_VerifyCommits_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGithubRepository_VerifyCommits_Call) RunAndReturn(run func(context.Context, string, string) ([]v0alpha1.SignatureVerification, error)) *MockGithubRepository_VerifyCommits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Write provides a mock function with given fields: ctx, path, ref, data, message
 func (_m *MockGithubRepository) Write(ctx context.Context, path string, ref string, data []byte, message string) error {
 	ret := _m.Called(ctx, path, ref, data, message)
@@ -48,41 +48,104 @@ func (r *githubClient) Commits(ctx context.Context, owner, repository, path, bra
 
 	ret := make([]Commit, 0, len(commits))
 	for _, c := range commits {
-		// FIXME: This code is a mess. I am pretty sure that we have issue in
-		// some situations
-		var createdAt time.Time
-		var author *CommitAuthor
-		if c.GetCommit().GetAuthor() != nil {
-			author = &CommitAuthor{
-				Name:      c.GetCommit().GetAuthor().GetName(),
-				Username:  c.GetAuthor().GetLogin(),
-				AvatarURL: c.GetAuthor().GetAvatarURL(),
-			}
+		ret = append(ret, toCommit(c))
+	}
+
+	return ret, nil
+}
 
-			createdAt = c.GetCommit().GetAuthor().GetDate().Time
+// GetCommit returns a single commit of the repository.
+func (r *githubClient) GetCommit(ctx context.Context, owner, repository, ref string) (Commit, error) {
+	commit, _, err := r.gh.Repositories.GetCommit(ctx, owner, repository, ref, nil)
+	if err != nil {
+		var ghErr *github.ErrorResponse
+		if !errors.As(err, &ghErr) {
+			return Commit{}, err
+		}
+		if ghErr.Response.StatusCode == http.StatusServiceUnavailable {
+			return Commit{}, ErrServiceUnavailable
+		}
+		if ghErr.Response.StatusCode == http.StatusNotFound || ghErr.Response.StatusCode == http.StatusUnprocessableEntity {
+			return Commit{}, ErrResourceNotFound
 		}
+		return Commit{}, err
+	}
 
-		var committer *CommitAuthor
-		if c.GetCommitter() != nil {
-			committer = &CommitAuthor{
-				Name:      c.GetCommit().GetCommitter().GetName(),
-				Username:  c.GetCommitter().GetLogin(),
-				AvatarURL: c.GetCommitter().GetAvatarURL(),
-			}
+	return toCommit(commit), nil
+}
+
+// CompareCommits returns the commits after base, up to and including head, from the oldest to the newest.
+func (r *githubClient) CompareCommits(ctx context.Context, owner, repository, base, head string) ([]Commit, error) {
+	listFn := func(ctx context.Context, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+		comparison, resp, err := r.gh.Repositories.CompareCommits(ctx, owner, repository, base, head, opts)
+		if err != nil {
+			return nil, resp, err
 		}
+		return comparison.Commits, resp, nil
+	}
 
-		ret = append(ret, Commit{
-			Ref:       c.GetSHA(),
-			Message:   c.GetCommit().GetMessage(),
-			Author:    author,
-			Committer: committer,
-			CreatedAt: createdAt,
-		})
+	commits, err := paginatedList(
+		ctx,
+		listFn,
+		defaultListOptions(maxCommits),
+	)
+	if errors.Is(err, ErrTooManyItems) {
+		return nil, fmt.Errorf("too many commits to compare (more than %d)", maxCommits)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]Commit, 0, len(commits))
+	for _, c := range commits {
+		ret = append(ret, toCommit(c))
 	}
 
 	return ret, nil
 }
 
+func toCommit(c *github.RepositoryCommit) Commit {
+	// FIXME: This code is a mess. I am pretty sure that we have issue in
+	// some situations
+	var createdAt time.Time
+	var author *CommitAuthor
+	if c.GetCommit().GetAuthor() != nil {
+		author = &CommitAuthor{
+			Name:      c.GetCommit().GetAuthor().GetName(),
+			Username:  c.GetAuthor().GetLogin(),
+			AvatarURL: c.GetAuthor().GetAvatarURL(),
+		}
+
+		createdAt = c.GetCommit().GetAuthor().GetDate().Time
+	}
+
+	var committer *CommitAuthor
+	if c.GetCommitter() != nil {
+		committer = &CommitAuthor{
+			Name:      c.GetCommit().GetCommitter().GetName(),
+			Username:  c.GetCommitter().GetLogin(),
+			AvatarURL: c.GetCommitter().GetAvatarURL(),
+		}
+	}
+
+	var verification *CommitVerification
+	if v := c.GetCommit().GetVerification(); v != nil {
+		verification = &CommitVerification{
+			Verified: v.GetVerified(),
+			Reason:   v.GetReason(),
+		}
+	}
+
+	return Commit{
+		Ref:          c.GetSHA(),
+		Message:      c.GetCommit().GetMessage(),
+		Author:       author,
+		Committer:    committer,
+		CreatedAt:    createdAt,
+		Verification: verification,
+	}
+}
+
 func (r *githubClient) ListWebhooks(ctx context.Context, owner, repository string) ([]WebhookConfig, error) {
 	listFn := func(ctx context.Context, opts *github.ListOptions) ([]*github.Hook, *github.Response, error) {
 		return r.gh.Repositories.ListHooks(ctx, owner, repository, opts)
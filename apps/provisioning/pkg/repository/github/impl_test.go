@@ -359,6 +359,175 @@ func TestGithubClient_GetCommits(t *testing.T) {
 	}
 }
 
+func TestGithubClient_GetCommit(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockHandler *http.Client
+		wantCommit  Commit
+		wantErr     error
+	}{
+		{
+			name: "get commit with its verification",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposCommitsByOwnerByRepoByRef,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusOK)
+						require.NoError(t, json.NewEncoder(w).Encode(&github.RepositoryCommit{
+							SHA: github.Ptr("abc123"),
+							Commit: &github.Commit{
+								Message: github.Ptr("Signed commit"),
+								Committer: &github.CommitAuthor{
+									Name: github.Ptr("Test User"),
+								},
+								Verification: &github.SignatureVerification{
+									Verified: github.Ptr(true),
+									Reason:   github.Ptr("valid"),
+								},
+							},
+							Committer: &github.User{
+								Login: github.Ptr("test-user"),
+							},
+						}))
+					}),
+				),
+			),
+			wantCommit: Commit{
+				Ref:     "abc123",
+				Message: "Signed commit",
+				Committer: &CommitAuthor{
+					Name:     "Test User",
+					Username: "test-user",
+				},
+				Verification: &CommitVerification{
+					Verified: true,
+					Reason:   "valid",
+				},
+			},
+		},
+		{
+			name: "commit not found",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposCommitsByOwnerByRepoByRef,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusUnprocessableEntity)
+						require.NoError(t, json.NewEncoder(w).Encode(github.ErrorResponse{
+							Message: "No commit found for SHA: abc123",
+						}))
+					}),
+				),
+			),
+			wantErr: ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := ProvideFactory()
+			factory.Client = tt.mockHandler
+			client := factory.New(context.Background(), "")
+
+			commit, err := client.GetCommit(context.Background(), "test-owner", "test-repo", "abc123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCommit, commit)
+		})
+	}
+}
+
+func TestGithubClient_CompareCommits(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockHandler *http.Client
+		wantCommits []Commit
+		wantErr     error
+	}{
+		{
+			name: "compare commits successfully",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposCompareByOwnerByRepoByBasehead,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusOK)
+						require.NoError(t, json.NewEncoder(w).Encode(&github.CommitsComparison{
+							Commits: []*github.RepositoryCommit{
+								{
+									SHA: github.Ptr("abc123"),
+									Commit: &github.Commit{
+										Message: github.Ptr("Unsigned commit"),
+										Verification: &github.SignatureVerification{
+											Verified: github.Ptr(false),
+											Reason:   github.Ptr("unsigned"),
+										},
+									},
+								},
+								{
+									SHA: github.Ptr("def456"),
+									Commit: &github.Commit{
+										Message: github.Ptr("Signed commit"),
+										Verification: &github.SignatureVerification{
+											Verified: github.Ptr(true),
+											Reason:   github.Ptr("valid"),
+										},
+									},
+								},
+							},
+						}))
+					}),
+				),
+			),
+			wantCommits: []Commit{
+				{
+					Ref:          "abc123",
+					Message:      "Unsigned commit",
+					Verification: &CommitVerification{Verified: false, Reason: "unsigned"},
+				},
+				{
+					Ref:          "def456",
+					Message:      "Signed commit",
+					Verification: &CommitVerification{Verified: true, Reason: "valid"},
+				},
+			},
+		},
+		{
+			name: "base not found",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposCompareByOwnerByRepoByBasehead,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusNotFound)
+						require.NoError(t, json.NewEncoder(w).Encode(github.ErrorResponse{
+							Message: "Not Found",
+						}))
+					}),
+				),
+			),
+			wantErr: ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := ProvideFactory()
+			factory.Client = tt.mockHandler
+			client := factory.New(context.Background(), "")
+
+			commits, err := client.CompareCommits(context.Background(), "test-owner", "test-repo", "old-ref", "new-ref")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, commits)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCommits, commits)
+		})
+	}
+}
+
 func TestGithubClient_ListWebhooks(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -83,6 +83,68 @@ func (_c *MockClient_Commits_Call) RunAndReturn(run func(context.Context, string
 	return _c
 }
 
+// CompareCommits provides a mock function with given fields: ctx, owner, repository, base, head
+func (_m *MockClient) CompareCommits(ctx context.Context, owner string, repository string, base string, head string) ([]Commit, error) {
+	ret := _m.Called(ctx, owner, repository, base, head)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareCommits")
+	}
+
+	var r0 []Commit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) ([]Commit, error)); ok {
+		return rf(ctx, owner, repository, base, head)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []Commit); ok {
+		r0 = rf(ctx, owner, repository, base, head)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Commit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repository, base, head)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_CompareCommits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareCommits'
+type MockClient_CompareCommits_Call struct {
+	*mock.Call
+}
+
+// CompareCommits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repository string
+//   - base string
+//   - head string
+func (_e *MockClient_Expecter) CompareCommits(ctx interface{}, owner interface{}, repository interface{}, base interface{}, head interface{}) *MockClient_CompareCommits_Call {
+	return &MockClient_CompareCommits_Call{Call: _e.mock.On("CompareCommits", ctx, owner, repository, base, head)}
+}
+
+func (_c *MockClient_CompareCommits_Call) Run(run func(ctx context.Context, owner string, repository string, base string, head string)) *MockClient_CompareCommits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockClient_CompareCommits_Call) Return(_a0 []Commit, _a1 error) *MockClient_CompareCommits_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_CompareCommits_Call) RunAndReturn(run func(context.Context, string, string, string, string) ([]Commit, error)) *MockClient_CompareCommits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreatePullRequestComment provides a mock function with given fields: ctx, owner, repository, number, body
 func (_m *MockClient) CreatePullRequestComment(ctx context.Context, owner string, repository string, number int, body string) error {
 	ret := _m.Called(ctx, owner, repository, number, body)
@@ -290,6 +352,65 @@ func (_c *MockClient_EditWebhook_Call) RunAndReturn(run func(context.Context, st
 	return _c
 }
 
+// GetCommit provides a mock function with given fields: ctx, owner, repository, ref
+func (_m *MockClient) GetCommit(ctx context.Context, owner string, repository string, ref string) (Commit, error) {
+	ret := _m.Called(ctx, owner, repository, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommit")
+	}
+
+	var r0 Commit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (Commit, error)); ok {
+		return rf(ctx, owner, repository, ref)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) Commit); ok {
+		r0 = rf(ctx, owner, repository, ref)
+	} else {
+		r0 = ret.Get(0).(Commit)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repository, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_GetCommit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCommit'
+type MockClient_GetCommit_Call struct {
+	*mock.Call
+}
+
+// GetCommit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repository string
+//   - ref string
+func (_e *MockClient_Expecter) GetCommit(ctx interface{}, owner interface{}, repository interface{}, ref interface{}) *MockClient_GetCommit_Call {
+	return &MockClient_GetCommit_Call{Call: _e.mock.On("GetCommit", ctx, owner, repository, ref)}
+}
+
+func (_c *MockClient_GetCommit_Call) Run(run func(ctx context.Context, owner string, repository string, ref string)) *MockClient_GetCommit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockClient_GetCommit_Call) Return(_a0 Commit, _a1 error) *MockClient_GetCommit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_GetCommit_Call) RunAndReturn(run func(context.Context, string, string, string) (Commit, error)) *MockClient_GetCommit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetWebhook provides a mock function with given fields: ctx, owner, repository, webhookID
 func (_m *MockClient) GetWebhook(ctx context.Context, owner string, repository string, webhookID int64) (WebhookConfig, error) {
 	ret := _m.Called(ctx, owner, repository, webhookID)
@@ -37,6 +37,7 @@ type GithubRepository interface {
 	repository.Reader
 	repository.RepositoryWithURLs
 	repository.StageableRepository
+	repository.CommitVerifier
 	Owner() string
 	Repo() string
 	Client() Client
@@ -164,17 +165,73 @@ func (r *githubRepository) History(ctx context.Context, path, ref string) ([]pro
 			})
 		}
 
-		ret = append(ret, provisioning.HistoryItem{
+		item := provisioning.HistoryItem{
 			Ref:       commit.Ref,
 			Message:   commit.Message,
 			Authors:   authors,
 			CreatedAt: commit.CreatedAt.UnixMilli(),
-		})
+		}
+		if commit.Verification != nil {
+			verification := r.signatureVerification(commit)
+			item.Verification = &verification
+		}
+		ret = append(ret, item)
+	}
+
+	return ret, nil
+}
+
+// VerifyCommits returns the signature verification of the commits after base, up to and including ref.
+// The signer is the GitHub user of the committer, which GitHub checks the signature against.
+func (r *githubRepository) VerifyCommits(ctx context.Context, base, ref string) ([]provisioning.SignatureVerification, error) {
+	var commits []Commit
+	if base == "" {
+		commit, err := r.gh.GetCommit(ctx, r.owner, r.repo, ref)
+		if err != nil {
+			if errors.Is(err, ErrResourceNotFound) {
+				return nil, repository.ErrRefNotFound
+			}
+			return nil, fmt.Errorf("get commit: %w", err)
+		}
+		commits = []Commit{commit}
+	} else {
+		var err error
+		commits, err = r.gh.CompareCommits(ctx, r.owner, r.repo, base, ref)
+		if err != nil {
+			if errors.Is(err, ErrResourceNotFound) {
+				return nil, repository.ErrRefNotFound
+			}
+			return nil, fmt.Errorf("compare commits: %w", err)
+		}
+	}
+
+	ret := make([]provisioning.SignatureVerification, 0, len(commits))
+	for _, commit := range commits {
+		ret = append(ret, r.signatureVerification(commit))
 	}
 
 	return ret, nil
 }
 
+func (r *githubRepository) signatureVerification(commit Commit) provisioning.SignatureVerification {
+	verification := provisioning.SignatureVerification{
+		Ref:    commit.Ref,
+		Reason: "unsigned",
+	}
+	if commit.Verification != nil {
+		verification.Verified = commit.Verification.Verified
+		verification.Reason = commit.Verification.Reason
+	}
+	if commit.Committer != nil {
+		verification.Signer = commit.Committer.Username
+	}
+	if verification.Verified {
+		verification.Reason = ""
+	}
+
+	return repository.TrustSignature(r.config.Spec.Verification, verification)
+}
+
 // ListRefs list refs from the git repository and add the ref URL to the ref item
 func (r *githubRepository) ListRefs(ctx context.Context) ([]provisioning.RefItem, error) {
 	refs, err := r.GitRepository.ListRefs(ctx)
@@ -516,6 +516,73 @@ func TestGitHubRepositoryHistory(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "history with signature verification",
+			config: &provisioning.Repository{
+				Spec: provisioning.RepositorySpec{
+					GitHub: &provisioning.GitHubRepositoryConfig{
+						Branch: "main",
+						Path:   "dashboards",
+					},
+					Verification: &provisioning.CommitVerificationConfig{
+						RequireSignedCommits: true,
+						TrustedSigners:       []string{"johndoe"},
+					},
+				},
+			},
+			path: "dashboard.json",
+			ref:  "main",
+			mockSetup: func(m *MockClient) {
+				commits := []Commit{
+					{
+						Ref:          "abc123",
+						Message:      "Update dashboard",
+						Committer:    &CommitAuthor{Name: "John Doe", Username: "johndoe"},
+						CreatedAt:    time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+						Verification: &CommitVerification{Verified: true, Reason: "valid"},
+					},
+					{
+						Ref:          "def456",
+						Message:      "Signed by someone else",
+						Committer:    &CommitAuthor{Name: "Jane Smith", Username: "janesmith"},
+						CreatedAt:    time.Date(2023, 1, 1, 11, 0, 0, 0, time.UTC),
+						Verification: &CommitVerification{Verified: true, Reason: "valid"},
+					},
+					{
+						Ref:          "ghi789",
+						Message:      "Unsigned",
+						Committer:    &CommitAuthor{Name: "John Doe", Username: "johndoe"},
+						CreatedAt:    time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC),
+						Verification: &CommitVerification{Verified: false, Reason: "unsigned"},
+					},
+				}
+				m.On("Commits", mock.Anything, "grafana", "grafana", "dashboards/dashboard.json", "main").
+					Return(commits, nil)
+			},
+			expectedResult: []provisioning.HistoryItem{
+				{
+					Ref:          "abc123",
+					Message:      "Update dashboard",
+					Authors:      []provisioning.Author{},
+					CreatedAt:    time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC).UnixMilli(),
+					Verification: &provisioning.SignatureVerification{Ref: "abc123", Verified: true, Signer: "johndoe"},
+				},
+				{
+					Ref:          "def456",
+					Message:      "Signed by someone else",
+					Authors:      []provisioning.Author{},
+					CreatedAt:    time.Date(2023, 1, 1, 11, 0, 0, 0, time.UTC).UnixMilli(),
+					Verification: &provisioning.SignatureVerification{Ref: "def456", Signer: "janesmith", Reason: repository.ReasonUntrustedSigner},
+				},
+				{
+					Ref:          "ghi789",
+					Message:      "Unsigned",
+					Authors:      []provisioning.Author{},
+					CreatedAt:    time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC).UnixMilli(),
+					Verification: &provisioning.SignatureVerification{Ref: "ghi789", Signer: "johndoe", Reason: "unsigned"},
+				},
+			},
+		},
 		{
 			name: "file not found",
 			config: &provisioning.Repository{
@@ -720,6 +787,92 @@ func TestGitHubRepositoryHistory(t *testing.T) {
 	}
 }
 
+func TestGitHubRepositoryVerifyCommits(t *testing.T) {
+	signed := func(ref, username string) Commit {
+		return Commit{
+			Ref:          ref,
+			Committer:    &CommitAuthor{Username: username},
+			Verification: &CommitVerification{Verified: true, Reason: "valid"},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		base           string
+		mockSetup      func(m *MockClient)
+		expectedResult []provisioning.SignatureVerification
+		expectedError  error
+	}{
+		{
+			name: "only the ref is verified without a base",
+			mockSetup: func(m *MockClient) {
+				m.EXPECT().GetCommit(mock.Anything, "grafana", "grafana", "new-ref").Return(signed("new-ref", "johndoe"), nil)
+			},
+			expectedResult: []provisioning.SignatureVerification{
+				{Ref: "new-ref", Verified: true, Signer: "johndoe"},
+			},
+		},
+		{
+			name: "the commits after the base are verified",
+			base: "old-ref",
+			mockSetup: func(m *MockClient) {
+				m.EXPECT().CompareCommits(mock.Anything, "grafana", "grafana", "old-ref", "new-ref").Return([]Commit{
+					{Ref: "middle-ref", Committer: &CommitAuthor{Username: "johndoe"}},
+					signed("new-ref", "janesmith"),
+				}, nil)
+			},
+			expectedResult: []provisioning.SignatureVerification{
+				{Ref: "middle-ref", Signer: "johndoe", Reason: "unsigned"},
+				{Ref: "new-ref", Signer: "janesmith", Reason: repository.ReasonUntrustedSigner},
+			},
+		},
+		{
+			name: "base not found",
+			base: "old-ref",
+			mockSetup: func(m *MockClient) {
+				m.EXPECT().CompareCommits(mock.Anything, "grafana", "grafana", "old-ref", "new-ref").Return(nil, ErrResourceNotFound)
+			},
+			expectedError: repository.ErrRefNotFound,
+		},
+		{
+			name: "get commit error",
+			mockSetup: func(m *MockClient) {
+				m.EXPECT().GetCommit(mock.Anything, "grafana", "grafana", "new-ref").Return(Commit{}, ErrServiceUnavailable)
+			},
+			expectedError: ErrServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := NewMockClient(t)
+			tt.mockSetup(mockClient)
+
+			repo := &githubRepository{
+				config: &provisioning.Repository{
+					Spec: provisioning.RepositorySpec{
+						Verification: &provisioning.CommitVerificationConfig{
+							RequireSignedCommits: true,
+							TrustedSigners:       []string{"johndoe"},
+						},
+					},
+				},
+				gh:    mockClient,
+				owner: "grafana",
+				repo:  "grafana",
+			}
+
+			verifications, err := repo.VerifyCommits(context.Background(), tt.base, "new-ref")
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResult, verifications)
+		})
+	}
+}
+
 func TestGitHubRepositoryResourceURLs(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -156,6 +156,26 @@ func (r *githubWebhookRepository) parsePullRequestEvent(event *github.PullReques
 	}
 
 	action := event.GetAction()
+
+	// The preview environment of a merged or closed pull request is removed
+	if action == "closed" && cfg.GeneratePreviewEnvironments {
+		return &provisioning.WebhookResponse{
+			Code:    http.StatusAccepted,
+			Message: fmt.Sprintf("pull request: %s", action),
+			Job: &provisioning.JobSpec{
+				Repository: r.config.GetName(),
+				Action:     provisioning.JobActionPullRequest,
+				PullRequest: &provisioning.PullRequestJobOptions{
+					URL:    pr.GetHTMLURL(),
+					PR:     pr.GetNumber(),
+					Ref:    pr.GetHead().GetRef(),
+					Hash:   pr.GetHead().GetSHA(),
+					Closed: true,
+				},
+			},
+		}, nil
+	}
+
 	if action != "opened" && action != "reopened" && action != "synchronize" {
 		return &provisioning.WebhookResponse{
 			Code:    http.StatusOK, // Nothing needed
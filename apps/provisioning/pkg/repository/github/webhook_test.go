@@ -589,6 +589,69 @@ func TestGitHubRepository_Webhook(t *testing.T) {
 				Message: "ignore pull request event: closed",
 			},
 		},
+		{
+			name: "pull request event - closed with preview environments",
+			config: &provisioning.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-repo",
+				},
+				Spec: provisioning.RepositorySpec{
+					GitHub: &provisioning.GitHubRepositoryConfig{
+						Branch:                      "main",
+						GeneratePreviewEnvironments: true,
+					},
+				},
+				Status: provisioning.RepositoryStatus{
+					Webhook: &provisioning.WebhookStatus{},
+				},
+			},
+			setupRequest: func() *http.Request {
+				payload := `{
+					"action": "closed",
+					"pull_request": {
+						"html_url": "https://github.com/grafana/grafana/pull/123",
+						"number": 123,
+						"merged": true,
+						"head": {
+							"ref": "feature-branch",
+							"sha": "abcdef1234567890"
+						},
+						"base": {
+							"ref": "main"
+						}
+					},
+					"repository": {
+						"full_name": "grafana/grafana"
+					}
+				}`
+				req, _ := http.NewRequest("POST", "/webhook", strings.NewReader(payload))
+				req.Header.Set("X-GitHub-Event", "pull_request")
+				req.Header.Set("Content-Type", "application/json")
+
+				// Create a valid signature
+				mac := hmac.New(sha256.New, []byte("webhook-secret"))
+				mac.Write([]byte(payload))
+				signature := hex.EncodeToString(mac.Sum(nil))
+				req.Header.Set("X-Hub-Signature-256", "sha256="+signature)
+
+				return req
+			},
+			expected: &provisioning.WebhookResponse{
+				Code:    http.StatusAccepted,
+				Message: "pull request: closed",
+				Job: &provisioning.JobSpec{
+					Repository: "test-repo",
+					Action:     provisioning.JobActionPullRequest,
+					PullRequest: &provisioning.PullRequestJobOptions{
+						URL:    "https://github.com/grafana/grafana/pull/123",
+						PR:     123,
+						Ref:    "feature-branch",
+						Hash:   "abcdef1234567890",
+						Closed: true,
+					},
+				},
+			},
+		},
 		{
 			name: "pull request event missing repository",
 			config: &provisioning.Repository{
@@ -0,0 +1,10 @@
+package repository
+
+// PreviewIdentityPrefix starts the manager identity of the pull request preview environments.
+// The repository names can not start with it, so a preview never shares the identity of a repository.
+const PreviewIdentityPrefix = "preview:"
+
+// PreviewIdentity is the manager identity of the resources in the pull request preview environments of the repository
+func PreviewIdentity(repository string) string {
+	return PreviewIdentityPrefix + repository
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
 	"slices"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -65,6 +67,9 @@ func ValidateRepository(repo Repository) field.ErrorList {
 	if slices.Contains(reserved, cfg.Name) {
 		list = append(list, field.Invalid(field.NewPath("metadata", "name"), cfg.Name, "Name is reserved, choose a different identifier"))
 	}
+	if strings.HasPrefix(cfg.Name, PreviewIdentityPrefix) {
+		list = append(list, field.Invalid(field.NewPath("metadata", "name"), cfg.Name, "Name prefix is reserved for the pull request previews, choose a different identifier"))
+	}
 
 	if cfg.Spec.Type != provisioning.LocalRepositoryType && cfg.Spec.Local != nil {
 		list = append(list, field.Invalid(field.NewPath("spec", "local"),
@@ -81,6 +86,33 @@ func ValidateRepository(repo Repository) field.ErrorList {
 			cfg.Spec.Git, "Git config only valid when type is git"))
 	}
 
+	if cfg.Spec.Type != provisioning.BucketRepositoryType && cfg.Spec.Bucket != nil {
+		list = append(list, field.Invalid(field.NewPath("spec", "bucket"),
+			cfg.Spec.Bucket, "Bucket config only valid when type is bucket"))
+	}
+
+	if policies := cfg.Spec.Policies; policies != nil {
+		if policies.NamePattern != "" {
+			if _, err := regexp.Compile(policies.NamePattern); err != nil {
+				list = append(list, field.Invalid(field.NewPath("spec", "policies", "namePattern"),
+					policies.NamePattern, fmt.Sprintf("invalid regular expression: %s", err)))
+			}
+		}
+		if policies.MinSchemaVersion < 0 {
+			list = append(list, field.Invalid(field.NewPath("spec", "policies", "minSchemaVersion"),
+				policies.MinSchemaVersion, "must not be negative"))
+		}
+		if policies.MaxPanels < 0 {
+			list = append(list, field.Invalid(field.NewPath("spec", "policies", "maxPanels"),
+				policies.MaxPanels, "must not be negative"))
+		}
+	}
+
+	if cfg.Spec.Verification != nil && cfg.Spec.Verification.RequireSignedCommits && cfg.Spec.Type != provisioning.GitHubRepositoryType {
+		list = append(list, field.Invalid(field.NewPath("spec", "verification", "requireSignedCommits"),
+			cfg.Spec.Verification.RequireSignedCommits, "signed commits can only be verified on github repositories"))
+	}
+
 	for _, w := range cfg.Spec.Workflows {
 		switch w {
 		case provisioning.WriteWorkflow: // valid; no fall thru
@@ -93,6 +93,49 @@ func TestValidateRepository(t *testing.T) {
 				require.Contains(t, errors.ToAggregate().Error(), "spec.sync.intervalSeconds: Invalid value")
 			},
 		},
+		{
+			name: "invalid policies",
+			repository: func() *MockRepository {
+				m := NewMockRepository(t)
+				m.On("Config").Return(&provisioning.Repository{
+					Spec: provisioning.RepositorySpec{
+						Title: "Test Repo",
+						Policies: &provisioning.ValidationPolicies{
+							NamePattern: "team-(",
+							MaxPanels:   -1,
+						},
+					},
+				})
+				m.On("Validate").Return(field.ErrorList{})
+				return m
+			}(),
+			expectedErrs: 2,
+			validateError: func(t *testing.T, errors field.ErrorList) {
+				require.Contains(t, errors.ToAggregate().Error(), "spec.policies.namePattern: Invalid value")
+				require.Contains(t, errors.ToAggregate().Error(), "spec.policies.maxPanels: Invalid value")
+			},
+		},
+		{
+			name: "signed commits on a local repository",
+			repository: func() *MockRepository {
+				m := NewMockRepository(t)
+				m.On("Config").Return(&provisioning.Repository{
+					Spec: provisioning.RepositorySpec{
+						Title: "Test Repo",
+						Type:  provisioning.LocalRepositoryType,
+						Verification: &provisioning.CommitVerificationConfig{
+							RequireSignedCommits: true,
+						},
+					},
+				})
+				m.On("Validate").Return(field.ErrorList{})
+				return m
+			}(),
+			expectedErrs: 1,
+			validateError: func(t *testing.T, errors field.ErrorList) {
+				require.Contains(t, errors.ToAggregate().Error(), "spec.verification.requireSignedCommits: Invalid value")
+			},
+		},
 		{
 			name: "reserved name",
 			repository: func() *MockRepository {
@@ -113,6 +156,26 @@ func TestValidateRepository(t *testing.T) {
 				require.Contains(t, errors.ToAggregate().Error(), "metadata.name: Invalid value")
 			},
 		},
+		{
+			name: "reserved preview prefix",
+			repository: func() *MockRepository {
+				m := NewMockRepository(t)
+				m.On("Config").Return(&provisioning.Repository{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "preview:test-repo",
+					},
+					Spec: provisioning.RepositorySpec{
+						Title: "Test Repo",
+					},
+				})
+				m.On("Validate").Return(field.ErrorList{})
+				return m
+			}(),
+			expectedErrs: 1,
+			validateError: func(t *testing.T, errors field.ErrorList) {
+				require.Contains(t, errors.ToAggregate().Error(), "Name prefix is reserved for the pull request previews")
+			},
+		},
 		{
 			name: "mismatched local config",
 			repository: func() *MockRepository {
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+)
+
+// Reason reported for the commits signed by a user who is not a trusted signer
+const ReasonUntrustedSigner = "untrusted_signer"
+
+// ErrVerificationNotSupported is returned when the repository requires signed commits, but it can't verify them.
+// Only the github repositories verify the commit signatures, with the verification reported by GitHub.
+var ErrVerificationNotSupported = errors.New("signed commits can only be verified on github repositories")
+
+// ErrUnknownCommitRange is returned when the commits since the last synced ref can't be listed,
+// e.g. after a force push, so they can't be verified
+var ErrUnknownCommitRange = errors.New("the commits since the last synced ref are unknown and can not be verified")
+
+// CommitVerifier is a repository that can verify the signature of its commits
+//
+//go:generate mockery --name CommitVerifier --structname MockCommitVerifier --inpackage --filename commit_verifier_mock.go --with-expecter
+type CommitVerifier interface {
+	// VerifyCommits returns the signature verification of the commits after base, up to and including ref.
+	// When base is empty, only the ref commit is verified.
+	VerifyCommits(ctx context.Context, base, ref string) ([]provisioning.SignatureVerification, error)
+}
+
+// UntrustedCommitError is returned when a commit to apply is not signed by a trusted signer
+type UntrustedCommitError struct {
+	Verification provisioning.SignatureVerification
+}
+
+func (e *UntrustedCommitError) Error() string {
+	return fmt.Sprintf("commit %s is not signed by a trusted signer: %s", e.Verification.Ref, e.Verification.Reason)
+}
+
+// TrustSignature refuses the verified signatures of the users who are not trusted signers
+func TrustSignature(cfg *provisioning.CommitVerificationConfig, verification provisioning.SignatureVerification) provisioning.SignatureVerification {
+	if !verification.Verified || cfg == nil || len(cfg.TrustedSigners) == 0 {
+		return verification
+	}
+	if !slices.Contains(cfg.TrustedSigners, verification.Signer) {
+		verification.Verified = false
+		verification.Reason = ReasonUntrustedSigner
+	}
+	return verification
+}
+
+// VerifyCommits checks the commits after base, up to and including ref, are signed by trusted signers,
+// when the repository requires signed commits. It returns the verification of the ref commit.
+// The base must be the last synced ref, only a repository that was never synced verifies the ref alone.
+func VerifyCommits(ctx context.Context, repo Repository, base, ref string) (*provisioning.SignatureVerification, error) {
+	cfg := repo.Config().Spec.Verification
+	if cfg == nil || !cfg.RequireSignedCommits || base == ref {
+		return nil, nil
+	}
+
+	verifier, ok := repo.(CommitVerifier)
+	if !ok {
+		return nil, ErrVerificationNotSupported
+	}
+
+	verifications, err := verifier.VerifyCommits(ctx, base, ref)
+	if err != nil {
+		if base != "" && errors.Is(err, ErrRefNotFound) {
+			return nil, fmt.Errorf("%w: %s is no longer in the repository", ErrUnknownCommitRange, base)
+		}
+		return nil, fmt.Errorf("verify commits: %w", err)
+	}
+
+	var last *provisioning.SignatureVerification
+	for _, verification := range verifications {
+		if !verification.Verified {
+			return &verification, &UntrustedCommitError{Verification: verification}
+		}
+		if verification.Ref == ref {
+			last = &verification
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("commit %s was not verified", ref)
+	}
+
+	return last, nil
+}
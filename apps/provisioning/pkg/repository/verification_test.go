@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+)
+
+type mockVerifiedRepo struct {
+	*MockConfigRepository
+	*MockCommitVerifier
+}
+
+func TestVerifyCommits(t *testing.T) {
+	required := &provisioning.CommitVerificationConfig{RequireSignedCommits: true}
+
+	tests := []struct {
+		name          string
+		verification  *provisioning.CommitVerificationConfig
+		base          string
+		verifications []provisioning.SignatureVerification
+		verifyErr     error
+		expected      *provisioning.SignatureVerification
+		expectedError string
+		untrusted     bool
+	}{
+		{
+			name: "signed commits not required",
+		},
+		{
+			name:         "nothing to verify",
+			verification: required,
+			base:         "new-ref",
+		},
+		{
+			name:         "all commits verified",
+			verification: required,
+			base:         "old-ref",
+			verifications: []provisioning.SignatureVerification{
+				{Ref: "middle-ref", Verified: true, Signer: "alice"},
+				{Ref: "new-ref", Verified: true, Signer: "bob"},
+			},
+			expected: &provisioning.SignatureVerification{Ref: "new-ref", Verified: true, Signer: "bob"},
+		},
+		{
+			name:         "unsigned commit",
+			verification: required,
+			base:         "old-ref",
+			verifications: []provisioning.SignatureVerification{
+				{Ref: "middle-ref", Verified: false, Reason: "unsigned"},
+				{Ref: "new-ref", Verified: true, Signer: "bob"},
+			},
+			expected:      &provisioning.SignatureVerification{Ref: "middle-ref", Verified: false, Reason: "unsigned"},
+			expectedError: "commit middle-ref is not signed by a trusted signer: unsigned",
+			untrusted:     true,
+		},
+		{
+			name:          "verification error",
+			verification:  required,
+			verifyErr:     errors.New("rate limited"),
+			expectedError: "verify commits: rate limited",
+		},
+		{
+			name:          "base no longer in the repository",
+			verification:  required,
+			base:          "old-ref",
+			verifyErr:     ErrRefNotFound,
+			expectedError: "the commits since the last synced ref are unknown and can not be verified: old-ref is no longer in the repository",
+		},
+		{
+			name:          "ref not found without a base",
+			verification:  required,
+			verifyErr:     ErrRefNotFound,
+			expectedError: "verify commits: " + ErrRefNotFound.Error(),
+		},
+		{
+			name:          "ref not verified",
+			verification:  required,
+			verifications: []provisioning.SignatureVerification{},
+			expectedError: "commit new-ref was not verified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVerifiedRepo{
+				MockConfigRepository: NewMockConfigRepository(t),
+				MockCommitVerifier:   NewMockCommitVerifier(t),
+			}
+			repo.MockConfigRepository.EXPECT().Config().Return(&provisioning.Repository{
+				Spec: provisioning.RepositorySpec{Verification: tt.verification},
+			})
+			if tt.verifications != nil || tt.verifyErr != nil {
+				repo.MockCommitVerifier.EXPECT().VerifyCommits(mock.Anything, tt.base, "new-ref").Return(tt.verifications, tt.verifyErr)
+			}
+
+			verification, err := VerifyCommits(context.Background(), repo, tt.base, "new-ref")
+			require.Equal(t, tt.expected, verification)
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedError)
+
+			var untrustedErr *UntrustedCommitError
+			require.Equal(t, tt.untrusted, errors.As(err, &untrustedErr))
+		})
+	}
+
+	t.Run("repository without verification", func(t *testing.T) {
+		repo := NewMockConfigRepository(t)
+		repo.EXPECT().Config().Return(&provisioning.Repository{
+			Spec: provisioning.RepositorySpec{Verification: required},
+		})
+
+		_, err := VerifyCommits(context.Background(), repo, "", "new-ref")
+		require.ErrorIs(t, err, ErrVerificationNotSupported)
+	})
+}
+
+func TestTrustSignature(t *testing.T) {
+	trusted := &provisioning.CommitVerificationConfig{
+		RequireSignedCommits: true,
+		TrustedSigners:       []string{"alice", "web-flow"},
+	}
+
+	tests := []struct {
+		name         string
+		cfg          *provisioning.CommitVerificationConfig
+		verification provisioning.SignatureVerification
+		expected     provisioning.SignatureVerification
+	}{
+		{
+			name:         "any signer is trusted without a list",
+			cfg:          &provisioning.CommitVerificationConfig{RequireSignedCommits: true},
+			verification: provisioning.SignatureVerification{Ref: "abc", Verified: true, Signer: "mallory"},
+			expected:     provisioning.SignatureVerification{Ref: "abc", Verified: true, Signer: "mallory"},
+		},
+		{
+			name:         "trusted signer",
+			cfg:          trusted,
+			verification: provisioning.SignatureVerification{Ref: "abc", Verified: true, Signer: "alice"},
+			expected:     provisioning.SignatureVerification{Ref: "abc", Verified: true, Signer: "alice"},
+		},
+		{
+			name:         "untrusted signer",
+			cfg:          trusted,
+			verification: provisioning.SignatureVerification{Ref: "abc", Verified: true, Signer: "mallory"},
+			expected:     provisioning.SignatureVerification{Ref: "abc", Verified: false, Signer: "mallory", Reason: ReasonUntrustedSigner},
+		},
+		{
+			name:         "unverified signature keeps its reason",
+			cfg:          trusted,
+			verification: provisioning.SignatureVerification{Ref: "abc", Verified: false, Reason: "unsigned"},
+			expected:     provisioning.SignatureVerification{Ref: "abc", Verified: false, Reason: "unsigned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, TrustSignature(tt.cfg, tt.verification))
+		})
+	}
+}
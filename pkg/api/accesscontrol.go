@@ -29,6 +29,8 @@ var (
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
 	ScopeProvisionersAlertRules    = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersIAM           = ac.Scope("provisioners", "iam")
+	ScopeProvisionersLibraryPanels = ac.Scope("provisioners", "library-panels")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
@@ -85,3 +85,49 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// swagger:route POST /admin/provisioning/iam/reload admin_provisioning adminProvisioningReloadIAM
+//
+// Reload iam provisioning configurations.
+//
+// Reloads the provisioning config files for org members, teams, service accounts and folder permissions again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:iam`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningReloadIAM(c *contextmodel.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionIAM(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reload iam config", err)
+	}
+	return response.Success("IAM config reloaded")
+}
+
+// swagger:route POST /admin/provisioning/library-panels/reload admin_provisioning adminProvisioningReloadLibraryPanels
+//
+// Reload library panel provisioning configurations.
+//
+// Reloads the provisioning config files for library panels again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:library-panels`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningReloadLibraryPanels(c *contextmodel.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionLibraryPanels(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reload library panels config", err)
+	}
+	return response.Success("Library panels config reloaded")
+}
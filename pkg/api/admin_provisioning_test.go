@@ -136,6 +136,46 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			expectedCode: http.StatusForbidden,
 			url:          "/api/admin/provisioning/alerting/reload",
 		},
+		{
+			desc:         "should work for iam with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"IAM config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersIAM,
+				},
+			},
+			url: "/api/admin/provisioning/iam/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionIAM, 1)
+			},
+		},
+		{
+			desc:         "should fail for iam with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/iam/reload",
+		},
+		{
+			desc:         "should work for library panels with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"Library panels config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersLibraryPanels,
+				},
+			},
+			url: "/api/admin/provisioning/library-panels/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionLibraryPanels, 1)
+			},
+		},
+		{
+			desc:         "should fail for library panels with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/library-panels/reload",
+		},
 	}
 
 	for _, tt := range tests {
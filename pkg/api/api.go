@@ -569,6 +569,8 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/provisioning/plugins/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/alerting/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+		adminRoute.Post("/provisioning/iam/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersIAM)), routing.Wrap(hs.AdminProvisioningReloadIAM))
+		adminRoute.Post("/provisioning/library-panels/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersLibraryPanels)), routing.Wrap(hs.AdminProvisioningReloadLibraryPanels))
 	}, reqSignedIn)
 
 	// Administering users
@@ -0,0 +1,99 @@
+package v0alpha1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+)
+
+const (
+	GROUP      = "live.grafana.app"
+	VERSION    = "v0alpha1"
+	APIVERSION = GROUP + "/" + VERSION
+)
+
+var ChannelRuleResourceInfo = utils.NewResourceInfo(GROUP, VERSION,
+	"channelrules", "channelrule", "ChannelRule",
+	func() runtime.Object { return &ChannelRule{} },
+	func() runtime.Object { return &ChannelRuleList{} },
+	utils.TableColumns{
+		Definition: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Pattern", Type: "string", Format: "string", Description: "The channel pattern the rule applies to"},
+			{Name: "Created At", Type: "date"},
+		},
+		Reader: func(obj any) ([]interface{}, error) {
+			r, ok := obj.(*ChannelRule)
+			if !ok {
+				return nil, fmt.Errorf("expected channel rule")
+			}
+			return []interface{}{
+				r.Name,
+				r.Spec.Pattern,
+				r.CreationTimestamp.UTC().Format(time.RFC3339),
+			}, nil
+		},
+	},
+)
+
+var WriteConfigResourceInfo = utils.NewResourceInfo(GROUP, VERSION,
+	"writeconfigs", "writeconfig", "WriteConfig",
+	func() runtime.Object { return &WriteConfig{} },
+	func() runtime.Object { return &WriteConfigList{} },
+	utils.TableColumns{
+		Definition: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Endpoint", Type: "string", Format: "string", Description: "Where the data is written"},
+			{Name: "Created At", Type: "date"},
+		},
+		Reader: func(obj any) ([]interface{}, error) {
+			r, ok := obj.(*WriteConfig)
+			if !ok {
+				return nil, fmt.Errorf("expected write config")
+			}
+			return []interface{}{
+				r.Name,
+				r.Spec.Endpoint,
+				r.CreationTimestamp.UTC().Format(time.RFC3339),
+			}, nil
+		},
+	},
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: GROUP, Version: VERSION}
+
+	// SchemeBuilder is used by standard codegen
+	SchemeBuilder      runtime.SchemeBuilder
+	localSchemeBuilder = &SchemeBuilder
+	AddToScheme        = localSchemeBuilder.AddToScheme
+)
+
+func init() {
+	localSchemeBuilder.Register(func(s *runtime.Scheme) error {
+		return AddKnownTypes(s, SchemeGroupVersion)
+	})
+}
+
+// AddKnownTypes adds the list of known types to the given scheme.
+func AddKnownTypes(scheme *runtime.Scheme, gv schema.GroupVersion) error {
+	scheme.AddKnownTypes(gv,
+		&ChannelRule{},
+		&ChannelRuleList{},
+		&WriteConfig{},
+		&WriteConfigList{},
+		&DryRunResults{},
+	)
+	return nil
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
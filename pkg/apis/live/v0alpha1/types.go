@@ -0,0 +1,118 @@
+package v0alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+)
+
+// ChannelRule describes how data published into matching Live channels
+// is converted, processed and written to outputs.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ChannelRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ChannelRuleSpec `json:"spec,omitempty"`
+}
+
+type ChannelRuleSpec struct {
+	// Pattern of the channels this rule applies to, for example: stream/telegraf/:metric
+	Pattern string `json:"pattern"`
+
+	// Settings of the pipeline stages (auth, converter, frameProcessors, frameOutputs, etc).
+	// The structure matches the channel rule settings used by the Live pipeline.
+	Settings common.Unstructured `json:"settings"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ChannelRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ChannelRule `json:"items"`
+}
+
+// WriteConfig holds the connection settings used by remote write and Loki outputs.
+// Channel rules reference write configs by name.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WriteConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WriteConfigSpec `json:"spec,omitempty"`
+
+	// Secure values, for example: basicAuthPassword
+	Secure common.InlineSecureValues `json:"secure,omitzero,omitempty"`
+}
+
+type WriteConfigSpec struct {
+	// Endpoint to send streaming frames to.
+	Endpoint string `json:"endpoint"`
+
+	// BasicAuth is an optional basic auth configuration.
+	// The password is configured with the basicAuthPassword secure value.
+	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
+
+	// HTTPHeaderNames are names of headers sent with requests.
+	// Header values are configured with httpHeaderValue1, httpHeaderValue2, etc secure values.
+	// +listType=atomic
+	HTTPHeaderNames []string `json:"httpHeaderNames,omitempty"`
+}
+
+type BasicAuth struct {
+	// User is a user for remote write request.
+	User string `json:"user,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WriteConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []WriteConfig `json:"items"`
+}
+
+// DryRunRequest is the body sent to the channel rule test endpoint.
+type DryRunRequest struct {
+	// Channel the sample data is published into.
+	// When empty, the rule pattern is used as the channel name.
+	Channel string `json:"channel,omitempty"`
+
+	// Data is the sample payload to process.
+	Data string `json:"data"`
+
+	// Spec allows testing unsaved changes.
+	// When not set, the saved rule spec is used.
+	Spec *ChannelRuleSpec `json:"spec,omitempty"`
+}
+
+// DryRunResults show the frames each pipeline stage produced for a sample payload.
+// Outputs are never executed in a dry run, the frames they would receive are reported instead.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DryRunResults struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Channel the sample data was published into.
+	Channel string `json:"channel"`
+
+	// Stages in the order they were executed.
+	Stages []DryRunStage `json:"stages"`
+}
+
+type DryRunStage struct {
+	// Stage kind: dataOutput, converter, frameProcessor or frameOutput.
+	Stage string `json:"stage"`
+
+	// Type of the pipeline entity, for example: jsonAuto or remoteWrite.
+	Type string `json:"type"`
+
+	// Channel whose rule the stage belongs to.
+	Channel string `json:"channel"`
+
+	// Frames produced by (or passed to, for outputs) the stage.
+	Frames []common.Unstructured `json:"frames,omitempty"`
+
+	// Error reported by the stage, if any.
+	Error string `json:"error,omitempty"`
+}
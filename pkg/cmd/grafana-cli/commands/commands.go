@@ -163,6 +163,63 @@ var adminCommands = []*cli.Command{
 					},
 				},
 			},
+			{
+				Name:   "backup-unified-storage",
+				Usage:  "Writes every resource in a unified storage namespace to a parquet archive",
+				Action: runDbCommand(datamigrations.BackupUnifiedStorage),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "namespace",
+						Usage: "The Unified Storage Namespace to backup.",
+						Value: "default",
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "Path of the parquet archive. Defaults to a timestamped file in the current directory.",
+					},
+					&cli.BoolFlag{
+						Name:  "history",
+						Usage: "Include the full history of each resource.",
+						Value: false,
+					},
+					&cli.StringSliceFlag{
+						Name:  "resource",
+						Usage: "Only backup these resources, as resource.group (e.g. dashboards.dashboard.grafana.app).",
+					},
+				},
+			},
+			{
+				Name:   "restore-unified-storage",
+				Usage:  "Replaces the resources in a unified storage namespace with the contents of a parquet archive",
+				Action: runDbCommand(datamigrations.RestoreUnifiedStorage),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "input",
+						Usage: "Path of the parquet archive.",
+					},
+					&cli.StringFlag{
+						Name:  "namespace",
+						Usage: "Restore into this namespace. Defaults to the namespace of the backup.",
+					},
+					&cli.BoolFlag{
+						Name:  "non-interactive",
+						Usage: "Non interactive mode. Just run the restore.",
+						Value: false,
+					},
+				},
+			},
+			{
+				Name:   "compact-unified-storage-history",
+				Usage:  "Applies the unified storage history retention policies once and prints a report",
+				Action: runDbCommand(datamigrations.CompactUnifiedStorageHistory),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only report what would be removed.",
+						Value: false,
+					},
+				},
+			},
 		},
 	},
 	{
@@ -44,6 +44,16 @@ func (s *stubProvisioning) ProvisionAlerting(ctx context.Context) error {
 	panic("unimplemented")
 }
 
+// ProvisionIAM implements provisioning.ProvisioningService.
+func (s *stubProvisioning) ProvisionIAM(ctx context.Context) error {
+	panic("unimplemented")
+}
+
+// ProvisionLibraryPanels implements provisioning.ProvisioningService.
+func (s *stubProvisioning) ProvisionLibraryPanels(ctx context.Context) error {
+	panic("unimplemented")
+}
+
 // ProvisionDashboards implements provisioning.ProvisioningService.
 func (s *stubProvisioning) ProvisionDashboards(ctx context.Context) error {
 	panic("unimplemented")
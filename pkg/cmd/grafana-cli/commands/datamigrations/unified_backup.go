@@ -0,0 +1,211 @@
+package datamigrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	authlib "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/parquet"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/sql"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db/dbimpl"
+)
+
+// BackupUnifiedStorage writes every resource in a namespace to a parquet archive
+func BackupUnifiedStorage(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	namespace := c.String("namespace")
+	ns, err := authlib.ParseNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	ctx := identity.WithServiceIdentityContext(context.Background(), ns.OrgID)
+
+	opts := parquet.BackupOptions{
+		Namespace:   namespace,
+		WithHistory: c.Bool("history"),
+		Progress:    progressLogger(),
+	}
+	for _, v := range c.StringSlice("resource") {
+		gr := schema.ParseGroupResource(v)
+		if gr.Group == "" || gr.Resource == "" {
+			return fmt.Errorf("expected resource.group, found: %s", v)
+		}
+		opts.Resources = append(opts.Resources, gr)
+	}
+
+	output := c.String("output")
+	if output == "" {
+		output = fmt.Sprintf("grafana-backup-%s-%s.parquet", namespace, time.Now().Format("20060102-150405"))
+	}
+
+	tracer := tracing.NewNoopTracerService()
+	eDB, err := dbimpl.ProvideResourceDB(sqlStore, cfg, tracer)
+	if err != nil {
+		return err
+	}
+	backend, err := sql.NewBackend(sql.BackendOptions{
+		DBProvider: eDB,
+		Tracer:     tracer,
+	})
+	if err != nil {
+		return err
+	}
+	if err = backend.Init(ctx); err != nil {
+		return err
+	}
+	defer func() { _ = backend.Stop(ctx) }()
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	manifest, err := parquet.Backup(ctx, backend, file, opts)
+	if err != nil {
+		_ = os.Remove(output)
+		return err
+	}
+
+	logger.Info("Backup DONE:", time.Since(start))
+	jj, _ := json.MarshalIndent(manifest, "", "  ")
+	logger.Info(string(jj))
+	logger.Info("File:", output)
+	return nil
+}
+
+// RestoreUnifiedStorage replaces the resources in a namespace with the contents of a backup archive
+func RestoreUnifiedStorage(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	input := c.String("input")
+	if input == "" {
+		return fmt.Errorf("missing input file")
+	}
+	manifest, err := parquet.ReadBackupManifest(input)
+	if err != nil {
+		return err
+	}
+
+	namespace := c.String("namespace")
+	if namespace == "" {
+		namespace = manifest.Namespace
+	}
+	ns, err := authlib.ParseNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	ctx := identity.WithServiceIdentityContext(context.Background(), ns.OrgID)
+
+	if !c.Bool("non-interactive") {
+		jj, _ := json.MarshalIndent(manifest, "", "  ")
+		fmt.Printf("%s\n", string(jj))
+		yes, err := promptYesNo(fmt.Sprintf("Replace these resources in namespace: %s?", namespace))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return nil
+		}
+	}
+
+	featureManager, err := featuremgmt.ProvideManagerService(cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newUnifiedClient(cfg, sqlStore, featuremgmt.ProvideToggles(featureManager))
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	rsp, err := parquet.Restore(ctx, client, input, parquet.RestoreOptions{
+		Namespace: namespace,
+		Progress:  progressLogger(),
+	})
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Failed to restore backup: %+v", err), 1)
+	}
+	if rsp.Error != nil {
+		return cli.Exit(fmt.Sprintf("Failed to restore backup: %s", rsp.Error.Message), 1)
+	}
+
+	logger.Info("Restore DONE:", time.Since(start))
+	jj, _ := json.MarshalIndent(rsp, "", "  ")
+	logger.Info(string(jj))
+	return nil
+}
+
+func progressLogger() func(count int, msg string) {
+	last := time.Now()
+	return func(count int, msg string) {
+		if time.Since(last) > time.Second {
+			logger.Info(fmt.Sprintf("[%6d] %s", count, msg))
+			last = time.Now()
+		}
+	}
+}
+
+// CompactUnifiedStorageHistory applies the configured history retention and blob garbage collection policies once.
+// The policies apply to every namespace, and the backend is used directly, so no org identity is needed
+func CompactUnifiedStorageHistory(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	ctx := context.Background()
+	policies := sql.RetentionPolicies(cfg.UnifiedStorage)
+	if len(policies) == 0 {
+		return fmt.Errorf("no history retention policies are configured")
+	}
+
+	tracer := tracing.NewNoopTracerService()
+	eDB, err := dbimpl.ProvideResourceDB(sqlStore, cfg, tracer)
+	if err != nil {
+		return err
+	}
+	backend, err := sql.NewBackend(sql.BackendOptions{
+		DBProvider: eDB,
+		Tracer:     tracer,
+	})
+	if err != nil {
+		return err
+	}
+	if err = backend.Init(ctx); err != nil {
+		return err
+	}
+	defer func() { _ = backend.Stop(ctx) }()
+
+	compactionBackend, ok := backend.(resource.HistoryCompactionBackend)
+	if !ok {
+		return fmt.Errorf("the storage backend does not support history compaction")
+	}
+	blobs, _ := backend.(resource.BlobGarbageCollector)
+	compactor, err := resource.NewHistoryCompactor(resource.HistoryCompactorOptions{
+		RetentionOptions: resource.RetentionOptions{
+			Policies: policies,
+			DryRun:   c.Bool("dry-run"),
+		},
+		Backend: compactionBackend,
+		Blobs:   blobs,
+	})
+	if err != nil {
+		return err
+	}
+
+	report, err := compactor.Compact(ctx)
+	if err != nil {
+		return err
+	}
+	jj, _ := json.MarshalIndent(report, "", "  ")
+	logger.Info(string(jj))
+	return nil
+}
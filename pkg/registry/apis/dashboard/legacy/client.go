@@ -76,6 +76,26 @@ func (d *directResourceClient) Search(ctx context.Context, in *resourcepb.Resour
 	return d.server.Search(ctx, in)
 }
 
+// QueryAudit implements ResourceClient.
+func (d *directResourceClient) QueryAudit(ctx context.Context, in *resourcepb.AuditQueryRequest, opts ...grpc.CallOption) (*resourcepb.AuditQueryResponse, error) {
+	return d.server.QueryAudit(ctx, in)
+}
+
+// GetQuotas implements ResourceClient.
+func (d *directResourceClient) GetQuotas(ctx context.Context, in *resourcepb.GetQuotasRequest, opts ...grpc.CallOption) (*resourcepb.GetQuotasResponse, error) {
+	return d.server.GetQuotas(ctx, in)
+}
+
+// SetQuota implements ResourceClient.
+func (d *directResourceClient) SetQuota(ctx context.Context, in *resourcepb.SetQuotaRequest, opts ...grpc.CallOption) (*resourcepb.SetQuotaResponse, error) {
+	return d.server.SetQuota(ctx, in)
+}
+
+// Transaction implements ResourceClient.
+func (d *directResourceClient) Transaction(ctx context.Context, in *resourcepb.TransactionRequest, opts ...grpc.CallOption) (*resourcepb.TransactionResponse, error) {
+	return d.server.Transaction(ctx, in)
+}
+
 // Update implements ResourceClient.
 func (d *directResourceClient) Update(ctx context.Context, in *resourcepb.UpdateRequest, opts ...grpc.CallOption) (*resourcepb.UpdateResponse, error) {
 	return d.server.Update(ctx, in)
@@ -90,3 +110,8 @@ func (d *directResourceClient) Watch(ctx context.Context, in *resourcepb.WatchRe
 func (d *directResourceClient) BulkProcess(ctx context.Context, opts ...grpc.CallOption) (resourcepb.BulkStore_BulkProcessClient, error) {
 	return nil, fmt.Errorf("BulkProcess not supported with direct resource client")
 }
+
+// Backup implements resource.ResourceClient.
+func (d *directResourceClient) Backup(ctx context.Context, in *resourcepb.BackupRequest, opts ...grpc.CallOption) (resourcepb.BackupStore_BackupClient, error) {
+	return nil, fmt.Errorf("backup not supported with direct resource client")
+}
@@ -229,6 +229,12 @@ func (c *DashboardSearchClient) Search(ctx context.Context, req *resourcepb.Reso
 				return nil, fmt.Errorf("only one repo name is supported")
 			}
 			query.ManagerIdentity = vals[0]
+		case resource.SEARCH_FIELD_PREFIX + unisearch.DASHBOARD_METRIC_NAMES,
+			resource.SEARCH_FIELD_PREFIX + unisearch.DASHBOARD_PANEL_QUERIES,
+			unisearch.DASHBOARD_DATASOURCE_REFERENCE:
+			// the legacy store does not index the panel queries, so these filters are
+			// not applied and the results are only narrowed by the other fields
+			continue
 		case unisearch.DASHBOARD_LIBRARY_PANEL_REFERENCE:
 			if len(vals) != 1 {
 				return nil, fmt.Errorf("only one library panel uid is supported")
@@ -309,6 +309,33 @@ func TestDashboardSearchClient_Search(t *testing.T) {
 		}
 	})
 
+	t.Run("Query content filters should be ignored", func(t *testing.T) {
+		mockStore.On("FindDashboards", mock.Anything, &dashboards.FindPersistedDashboardsQuery{
+			Title:        "test",
+			SignedInUser: user,
+			Type:         "dash-db",
+		}).Return([]dashboards.DashboardSearchProjection{
+			{UID: "uid", Title: "Test Dashboard", FolderUID: "folder1"},
+		}, nil).Once()
+
+		req := &resourcepb.ResourceSearchRequest{
+			Options: &resourcepb.ListOptions{
+				Key: dashboardKey,
+				Fields: []*resourcepb.Requirement{
+					{Key: resource.SEARCH_FIELD_PREFIX + unisearch.DASHBOARD_METRIC_NAMES, Operator: "in", Values: []string{"up"}},
+					{Key: resource.SEARCH_FIELD_PREFIX + unisearch.DASHBOARD_PANEL_QUERIES, Operator: "~", Values: []string{"rate("}},
+					{Key: unisearch.DASHBOARD_DATASOURCE_REFERENCE, Operator: "in", Values: []string{"prometheus"}},
+				},
+			},
+			Query: "test",
+		}
+		resp, err := client.Search(ctx, req)
+
+		require.NoError(t, err)
+		require.Len(t, resp.Results.Rows, 1)
+		mockStore.AssertExpectations(t)
+	})
+
 	t.Run("When searching for SEARCH_FIELD_TITLE_PHRASE, value should be set as title, and ExactMatch should be enabled", func(t *testing.T) {
 		mockStore.On("FindDashboards", mock.Anything, &dashboards.FindPersistedDashboardsQuery{
 			Title:           "test",
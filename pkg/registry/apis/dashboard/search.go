@@ -10,10 +10,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/kube-openapi/pkg/common"
 	"k8s.io/kube-openapi/pkg/spec3"
 	"k8s.io/kube-openapi/pkg/validation/spec"
@@ -27,6 +29,7 @@ import (
 	dashboardsearch "github.com/grafana/grafana/pkg/services/dashboards/service/search"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	foldermodel "github.com/grafana/grafana/pkg/services/folder"
+	dashboardkind "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
 	"github.com/grafana/grafana/pkg/storage/legacysql/dualwrite"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
@@ -85,6 +88,33 @@ func (s *SearchHandler) GetAPIRoutes(defs map[string]common.OpenAPIDefinition) *
 										Schema:      spec.StringProperty(),
 									},
 								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "advanced",
+										In:          "query",
+										Description: "parse the query for \"quoted phrases\", AND/OR operators, -excluded words and field scoped terms (eg tag:prod)",
+										Required:    false,
+										Schema:      spec.BooleanProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "fuzziness",
+										In:          "query",
+										Description: "allow up to this many typos for each word in the query (max 2, defaults to 1)",
+										Required:    false,
+										Schema:      spec.Int64Property(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "boost",
+										In:          "query",
+										Description: "numeric fields that boost the score of popular results (eg views_last_7_days)",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
 								{
 									ParameterProps: spec3.ParameterProps{
 										Name:        "folder",
@@ -94,6 +124,51 @@ func (s *SearchHandler) GetAPIRoutes(defs map[string]common.OpenAPIDefinition) *
 										Schema:      spec.StringProperty(),
 									},
 								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "metric",
+										In:          "query",
+										Description: "dashboards with queries that reference this metric name",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "expr",
+										In:          "query",
+										Description: "dashboards with a query expression that contains this text",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "datasource",
+										In:          "query",
+										Description: "dashboards that use this datasource uid",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "facet",
+										In:          "query",
+										Description: "count the values of a field (tags, folder, owner, creator, updater, panel_type, datasource, datasource_type) or the time buckets of created/updated",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "facetLimit",
+										In:          "query",
+										Description: "maximum number of terms returned for each facet",
+										Required:    false,
+										Schema:      spec.Int64Property(),
+									},
+								},
 								{
 									ParameterProps: spec3.ParameterProps{
 										Name:        "sort",
@@ -246,6 +321,15 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 		Page:    int64(page), // for modes 0-2 (legacy)
 		Explain: queryParams.Has("explain") && queryParams.Get("explain") != "false",
 	}
+
+	// The query syntax and relevance
+	searchRequest.AdvancedQuery = queryParams.Has("advanced") && queryParams.Get("advanced") != "false"
+	searchRequest.Boost = queryParams["boost"]
+	if queryParams.Has("fuzziness") {
+		if fuzziness, err := strconv.ParseInt(queryParams.Get("fuzziness"), 10, 64); err == nil {
+			searchRequest.Fuzziness = &fuzziness
+		}
+	}
 	fields := []string{"title", "folder", "tags"}
 	if queryParams.Has("field") {
 		// add fields to search and exclude duplicates
@@ -300,13 +384,17 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// The facet term fields
+	// The facet term fields (or time buckets for created/updated)
 	if facets, ok := queryParams["facet"]; ok {
+		facetLimit := int64(50)
+		if queryParams.Has("facetLimit") {
+			facetLimit, _ = strconv.ParseInt(queryParams.Get("facetLimit"), 10, 64)
+		}
 		searchRequest.Facet = make(map[string]*resourcepb.ResourceSearchRequest_Facet)
+		now := time.Now()
 		for _, v := range facets {
-			searchRequest.Facet[v] = &resourcepb.ResourceSearchRequest_Facet{
-				Field: v,
-				Limit: 50,
+			if v != "" {
+				searchRequest.Facet[v] = asFacetRequest(v, facetLimit, now)
 			}
 		}
 	}
@@ -363,6 +451,13 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 		searchRequest.Options.Fields = append(searchRequest.Options.Fields, namesFilter...)
 	}
 
+	// Filter by the query content
+	searchRequest.Options.Fields = append(searchRequest.Options.Fields, queryContentFilters(queryParams)...)
+	matchPanels := queryParams.Has("metric") || queryParams.Has("expr")
+	if matchPanels && !slices.Contains(searchRequest.Fields, search.DASHBOARD_PANEL_QUERIES) {
+		searchRequest.Fields = append(searchRequest.Fields, search.DASHBOARD_PANEL_QUERIES)
+	}
+
 	result, err := s.client.Search(ctx, searchRequest)
 	if err != nil {
 		errhttp.Write(ctx, err, w)
@@ -379,6 +474,10 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if matchPanels {
+		addMatchedPanels(parsedResults.Hits, queryParams["metric"], queryParams["expr"])
+	}
+
 	if len(searchRequest.SortBy) == 0 {
 		// default sort by resource descending ( folders then dashboards ) then title
 		sort.Slice(parsedResults.Hits, func(i, j int) bool {
@@ -390,6 +489,107 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 	s.write(w, parsedResults)
 }
 
+// queryContentFilters converts the metric, expr and datasource params into search requirements
+func queryContentFilters(queryParams url.Values) []*resourcepb.Requirement {
+	var filters []*resourcepb.Requirement
+	if metrics := queryParams["metric"]; len(metrics) > 0 {
+		filters = append(filters, &resourcepb.Requirement{
+			Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_METRIC_NAMES,
+			Operator: string(selection.In),
+			Values:   metrics,
+		})
+	}
+	if exprs := queryParams["expr"]; len(exprs) > 0 {
+		filters = append(filters, &resourcepb.Requirement{
+			Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_PANEL_QUERIES,
+			Operator: string(search.OperatorContains),
+			Values:   exprs,
+		})
+	}
+	if datasources := queryParams["datasource"]; len(datasources) > 0 {
+		filters = append(filters, &resourcepb.Requirement{
+			Key:      search.DASHBOARD_DATASOURCE_REFERENCE,
+			Operator: string(selection.In),
+			Values:   datasources,
+		})
+	}
+	return filters
+}
+
+// Friendly names for the facet fields
+var facetFields = map[string]string{
+	"owner":           resource.SEARCH_FIELD_OWNERS,
+	"creator":         resource.SEARCH_FIELD_CREATED_BY,
+	"updater":         resource.SEARCH_FIELD_UPDATED_BY,
+	"panel_type":      search.DASHBOARD_PANEL_TYPES,
+	"datasource":      search.DASHBOARD_DATASOURCE_REFERENCE,
+	"datasource_type": search.DASHBOARD_DS_TYPES,
+}
+
+// asFacetRequest counts the terms of a field, or the resources created/updated within each time bucket
+func asFacetRequest(name string, limit int64, now time.Time) *resourcepb.ResourceSearchRequest_Facet {
+	field := name
+	if f, ok := facetFields[name]; ok {
+		field = f
+	}
+	facet := &resourcepb.ResourceSearchRequest_Facet{
+		Field: field,
+		Limit: limit,
+	}
+	if field == resource.SEARCH_FIELD_CREATED || field == resource.SEARCH_FIELD_UPDATED {
+		facet.Ranges = timeBuckets(now)
+	}
+	return facet
+}
+
+// timeBuckets splits the timeline into non overlapping ranges (unix milliseconds)
+func timeBuckets(now time.Time) []*resourcepb.ResourceSearchRequest_FacetRange {
+	day := now.AddDate(0, 0, -1).UnixMilli()
+	week := now.AddDate(0, 0, -7).UnixMilli()
+	month := now.AddDate(0, -1, 0).UnixMilli()
+	year := now.AddDate(-1, 0, 0).UnixMilli()
+	unset := int64(1) // zero when the time is not known
+	return []*resourcepb.ResourceSearchRequest_FacetRange{
+		{Name: "day", Min: &day},
+		{Name: "week", Min: &week, Max: &day},
+		{Name: "month", Min: &month, Max: &week},
+		{Name: "year", Min: &year, Max: &month},
+		{Name: "older", Min: &unset, Max: &year},
+	}
+}
+
+// addMatchedPanels sets the ids of the panels with matching queries in each hit
+func addMatchedPanels(hits []dashboardv0alpha1.DashboardHit, metrics []string, exprs []string) {
+	for _, hit := range hits {
+		if hit.Field == nil {
+			continue
+		}
+		values, ok := hit.Field.Object[search.DASHBOARD_PANEL_QUERIES].([]any)
+		if !ok {
+			continue
+		}
+		panels := []any{}
+		for _, v := range values {
+			str, _ := v.(string)
+			panelID, expr, ok := search.ParsePanelQuery(str)
+			if !ok || slices.Contains(panels, any(panelID)) {
+				continue
+			}
+			matches := false
+			for _, m := range dashboardkind.MetricNames(expr) {
+				matches = matches || slices.Contains(metrics, m)
+			}
+			for _, e := range exprs {
+				matches = matches || strings.Contains(expr, e)
+			}
+			if matches {
+				panels = append(panels, panelID)
+			}
+		}
+		hit.Field.Set("matched_panels", panels)
+	}
+}
+
 func (s *SearchHandler) write(w http.ResponseWriter, obj any) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(obj)
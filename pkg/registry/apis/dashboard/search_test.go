@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/storage/legacysql/dualwrite"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/search"
 )
 
 func TestSearchFallback(t *testing.T) {
@@ -338,6 +339,125 @@ func TestSearchHandler(t *testing.T) {
 		assert.Equal(t, mockResults[2].Value, p.Hits[0].Title)
 		assert.Equal(t, mockResults[1].Value, p.Hits[3].Title)
 	})
+	t.Run("Query content filters return the matching panels", func(t *testing.T) {
+		queries, err := json.Marshal([]string{
+			search.PanelQuery(1, "sum(rate(node_cpu_seconds_total[5m]))"),
+			search.PanelQuery(2, "node_load1"),
+			search.PanelQuery(3, "up"),
+		})
+		require.NoError(t, err)
+		mockClient := &MockClient{
+			MockResponses: []*resourcepb.ResourceSearchResponse{{
+				Results: &resourcepb.ResourceTable{
+					Columns: []*resourcepb.ResourceTableColumnDefinition{
+						{Name: resource.SEARCH_FIELD_TITLE, Type: resourcepb.ResourceTableColumnDefinition_STRING},
+						{Name: search.DASHBOARD_PANEL_QUERIES, Type: resourcepb.ResourceTableColumnDefinition_STRING, IsArray: true},
+					},
+					Rows: []*resourcepb.ResourceTableRow{{
+						Key:   &resourcepb.ResourceKey{Name: "node", Resource: "dashboards"},
+						Cells: [][]byte{[]byte("Node"), queries},
+					}},
+				},
+			}},
+		}
+		searchHandler := SearchHandler{
+			log:      log.New("test", "test"),
+			client:   mockClient,
+			tracer:   tracing.NewNoopTracerService(),
+			features: featuremgmt.WithFeatures(),
+		}
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/search?metric=node_cpu_seconds_total&expr=load&datasource=prom", nil)
+		req = req.WithContext(identity.WithRequester(req.Context(), &user.SignedInUser{Namespace: "test"}))
+		searchHandler.DoSearch(rr, req)
+
+		require.NotNil(t, mockClient.LastSearchRequest)
+		require.Contains(t, mockClient.LastSearchRequest.Fields, search.DASHBOARD_PANEL_QUERIES)
+		require.Equal(t, []*resourcepb.Requirement{
+			{Key: "fields." + search.DASHBOARD_METRIC_NAMES, Operator: "in", Values: []string{"node_cpu_seconds_total"}},
+			{Key: "fields." + search.DASHBOARD_PANEL_QUERIES, Operator: "~", Values: []string{"load"}},
+			{Key: search.DASHBOARD_DATASOURCE_REFERENCE, Operator: "in", Values: []string{"prom"}},
+		}, mockClient.LastSearchRequest.Options.Fields)
+
+		p := &v0alpha1.SearchResults{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(p))
+		require.Len(t, p.Hits, 1)
+		require.Equal(t, []any{float64(1), float64(2)}, p.Hits[0].Field.Object["matched_panels"])
+	})
+	t.Run("Query syntax and relevance options are passed to the search", func(t *testing.T) {
+		mockClient := &MockClient{}
+		searchHandler := SearchHandler{
+			log:      log.New("test", "test"),
+			client:   mockClient,
+			tracer:   tracing.NewNoopTracerService(),
+			features: featuremgmt.WithFeatures(),
+		}
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/search?query=tag:prod%20-dev&advanced=true&fuzziness=0&boost=views_last_7_days", nil)
+		req = req.WithContext(identity.WithRequester(req.Context(), &user.SignedInUser{Namespace: "test"}))
+		searchHandler.DoSearch(rr, req)
+
+		require.NotNil(t, mockClient.LastSearchRequest)
+		require.Equal(t, "tag:prod -dev", mockClient.LastSearchRequest.Query)
+		require.True(t, mockClient.LastSearchRequest.AdvancedQuery)
+		require.NotNil(t, mockClient.LastSearchRequest.Fuzziness)
+		require.Equal(t, int64(0), *mockClient.LastSearchRequest.Fuzziness)
+		require.Equal(t, []string{search.DASHBOARD_VIEWS_LAST_7_DAYS}, mockClient.LastSearchRequest.Boost)
+	})
+	t.Run("Facets use the field names and time buckets", func(t *testing.T) {
+		mockClient := &MockClient{
+			MockResponses: []*resourcepb.ResourceSearchResponse{{
+				Results: &resourcepb.ResourceTable{},
+				Facet: map[string]*resourcepb.ResourceSearchResponse_Facet{
+					"datasource_type": {
+						Field: search.DASHBOARD_DS_TYPES,
+						Total: 1,
+						Terms: []*resourcepb.ResourceSearchResponse_TermFacet{{Term: "prometheus", Count: 2}},
+					},
+					"updated": {
+						Field:  resource.SEARCH_FIELD_UPDATED,
+						Ranges: []*resourcepb.ResourceSearchResponse_RangeFacet{{Name: "day", Count: 1}, {Name: "week", Count: 0}},
+					},
+				},
+			}},
+		}
+		searchHandler := SearchHandler{
+			log:      log.New("test", "test"),
+			client:   mockClient,
+			tracer:   tracing.NewNoopTracerService(),
+			features: featuremgmt.WithFeatures(),
+		}
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/search?type=alertrules&facet=tags&facet=owner&facet=datasource_type&facet=updated&facetLimit=5", nil)
+		req = req.WithContext(identity.WithRequester(req.Context(), &user.SignedInUser{Namespace: "test"}))
+		searchHandler.DoSearch(rr, req)
+
+		searchRequest := mockClient.LastSearchRequest
+		require.NotNil(t, searchRequest)
+		require.Equal(t, "alertrules", searchRequest.Options.Key.Resource)
+		require.Len(t, searchRequest.Facet, 4)
+		require.Equal(t, "tags", searchRequest.Facet["tags"].Field)
+		require.Equal(t, resource.SEARCH_FIELD_OWNERS, searchRequest.Facet["owner"].Field)
+		require.Equal(t, search.DASHBOARD_DS_TYPES, searchRequest.Facet["datasource_type"].Field)
+		require.Equal(t, int64(5), searchRequest.Facet["tags"].Limit)
+		require.Empty(t, searchRequest.Facet["tags"].Ranges)
+
+		buckets := searchRequest.Facet["updated"].Ranges
+		require.Len(t, buckets, 5)
+		require.Nil(t, buckets[0].Max)
+		require.Equal(t, int64(1), *buckets[4].Min)
+		for i := 1; i < len(buckets); i++ {
+			require.Equal(t, *buckets[i-1].Min, *buckets[i].Max)
+		}
+
+		p := &v0alpha1.SearchResults{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(p))
+		require.Equal(t, []v0alpha1.TermFacet{{Term: "prometheus", Count: 2}}, p.Facets["datasource_type"].Terms)
+		require.Equal(t, []v0alpha1.RangeFacet{{Name: "day", Count: 1}, {Name: "week"}}, p.Facets["updated"].Ranges)
+	})
 }
 
 func TestSearchHandlerSharedDashboards(t *testing.T) {
@@ -670,6 +790,21 @@ func (m *MockClient) Create(ctx context.Context, in *resourcepb.CreateRequest, o
 func (m *MockClient) Update(ctx context.Context, in *resourcepb.UpdateRequest, opts ...grpc.CallOption) (*resourcepb.UpdateResponse, error) {
 	return nil, nil
 }
+func (m *MockClient) Transaction(ctx context.Context, in *resourcepb.TransactionRequest, opts ...grpc.CallOption) (*resourcepb.TransactionResponse, error) {
+	return nil, nil
+}
+func (m *MockClient) QueryAudit(ctx context.Context, in *resourcepb.AuditQueryRequest, opts ...grpc.CallOption) (*resourcepb.AuditQueryResponse, error) {
+	return nil, nil
+}
+func (m *MockClient) GetQuotas(ctx context.Context, in *resourcepb.GetQuotasRequest, opts ...grpc.CallOption) (*resourcepb.GetQuotasResponse, error) {
+	return nil, nil
+}
+func (m *MockClient) SetQuota(ctx context.Context, in *resourcepb.SetQuotaRequest, opts ...grpc.CallOption) (*resourcepb.SetQuotaResponse, error) {
+	return nil, nil
+}
+func (m *MockClient) Backup(ctx context.Context, in *resourcepb.BackupRequest, opts ...grpc.CallOption) (resourcepb.BackupStore_BackupClient, error) {
+	return nil, nil
+}
 func (m *MockClient) Read(ctx context.Context, in *resourcepb.ReadRequest, opts ...grpc.CallOption) (*resourcepb.ReadResponse, error) {
 	return nil, nil
 }
@@ -0,0 +1,241 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/kube-openapi/pkg/common"
+
+	"github.com/grafana/grafana/apps/secret/pkg/decrypt"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	live "github.com/grafana/grafana/pkg/apis/live/v0alpha1"
+	grafanaregistry "github.com/grafana/grafana/pkg/apiserver/registry/generic"
+	"github.com/grafana/grafana/pkg/services/apiserver/builder"
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	livesvc "github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var (
+	_ builder.APIGroupBuilder    = (*APIBuilder)(nil)
+	_ builder.APIGroupValidation = (*APIBuilder)(nil)
+)
+
+// APIBuilder exposes Live pipeline channel rules and write configs as resources
+type APIBuilder struct {
+	cfg         *setting.Cfg
+	liveService *livesvc.GrafanaLive
+	decrypter   decrypt.DecryptService
+	secrets     secrets.Service
+}
+
+func RegisterAPIService(
+	cfg *setting.Cfg,
+	features featuremgmt.FeatureToggles,
+	apiregistration builder.APIRegistrar,
+	liveService *livesvc.GrafanaLive,
+	decrypter decrypt.DecryptService,
+	secretsService secrets.Service,
+) *APIBuilder {
+	// Requires development settings and clearly experimental
+	if !features.IsEnabledGlobally(featuremgmt.FlagGrafanaAPIServerWithExperimentalAPIs) {
+		return nil
+	}
+
+	builder := &APIBuilder{
+		cfg:         cfg,
+		liveService: liveService,
+		decrypter:   decrypter,
+		secrets:     secretsService,
+	}
+	apiregistration.RegisterAPI(builder)
+	return builder
+}
+
+func (b *APIBuilder) GetGroupVersion() schema.GroupVersion {
+	return live.SchemeGroupVersion
+}
+
+func (b *APIBuilder) InstallSchema(scheme *runtime.Scheme) error {
+	gv := live.SchemeGroupVersion
+	if err := live.AddKnownTypes(scheme, gv); err != nil {
+		return err
+	}
+
+	// Link this version to the internal representation.
+	// This is used for server-side-apply (PATCH), and avoids the error:
+	//   "no kind is registered for the type"
+	if err := live.AddKnownTypes(scheme, schema.GroupVersion{
+		Group:   live.GROUP,
+		Version: runtime.APIVersionInternal,
+	}); err != nil {
+		return err
+	}
+
+	metav1.AddToGroupVersion(scheme, gv)
+	return scheme.SetVersionPriority(gv)
+}
+
+func (b *APIBuilder) AllowedV0Alpha1Resources() []string {
+	return []string{builder.AllResourcesAllowed}
+}
+
+func (b *APIBuilder) UpdateAPIGroupInfo(apiGroupInfo *genericapiserver.APIGroupInfo, opts builder.APIGroupOptions) error {
+	rules, err := grafanaregistry.NewRegistryStore(opts.Scheme, live.ChannelRuleResourceInfo, opts.OptsGetter)
+	if err != nil {
+		return err
+	}
+	writeConfigs, err := grafanaregistry.NewRegistryStore(opts.Scheme, live.WriteConfigResourceInfo, opts.OptsGetter)
+	if err != nil {
+		return err
+	}
+
+	storage := map[string]rest.Storage{}
+	storage[live.ChannelRuleResourceInfo.StoragePath()] = rules
+	storage[live.ChannelRuleResourceInfo.StoragePath("test")] = &dryRunConnector{
+		rules:        rules,
+		writeConfigs: writeConfigs,
+	}
+	storage[live.WriteConfigResourceInfo.StoragePath()] = writeConfigs
+
+	// The pipeline runs with the rules and write configs saved as resources
+	if b.liveService != nil {
+		b.liveService.UsePipelineStorage(&pipelineStorage{
+			rules:        rules,
+			writeConfigs: writeConfigs,
+			namespacer:   request.GetNamespaceMapper(b.cfg),
+			decrypter:    b.decrypter,
+			secrets:      b.secrets,
+		})
+	}
+
+	apiGroupInfo.VersionedResourcesStorageMap[live.VERSION] = storage
+	return nil
+}
+
+func (b *APIBuilder) GetOpenAPIDefinitions() common.GetOpenAPIDefinitions {
+	return live.GetOpenAPIDefinitions
+}
+
+// GetAuthorizer requires an org admin, the same as the legacy pipeline endpoints
+func (b *APIBuilder) GetAuthorizer() authorizer.Authorizer {
+	return authorizer.AuthorizerFunc(
+		func(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+			if !attr.IsResourceRequest() {
+				return authorizer.DecisionNoOpinion, "", nil
+			}
+
+			u, err := identity.GetRequester(ctx)
+			if err != nil {
+				return authorizer.DecisionDeny, "valid user is required", err
+			}
+
+			if u.GetIsGrafanaAdmin() || u.GetOrgRole().Includes(identity.RoleAdmin) {
+				return authorizer.DecisionAllow, "", nil
+			}
+			return authorizer.DecisionDeny, "admin role is required", nil
+		})
+}
+
+func (b *APIBuilder) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) (err error) {
+	obj := a.GetObject()
+	if obj == nil || a.GetOperation() == admission.Connect || a.GetOperation() == admission.Delete {
+		return nil // This is normal for sub-resource
+	}
+
+	switch v := obj.(type) {
+	case *live.ChannelRule:
+		if list := validateChannelRule(v); len(list) > 0 {
+			return apierrors.NewInvalid(live.ChannelRuleResourceInfo.GroupVersionKind().GroupKind(), a.GetName(), list)
+		}
+	case *live.WriteConfig:
+		if list := validateWriteConfig(v); len(list) > 0 {
+			return apierrors.NewInvalid(live.WriteConfigResourceInfo.GroupVersionKind().GroupKind(), a.GetName(), list)
+		}
+	}
+	return nil
+}
+
+func validateChannelRule(obj *live.ChannelRule) field.ErrorList {
+	var list field.ErrorList
+	rule, err := toPipelineChannelRule(0, &obj.Spec)
+	if err != nil {
+		return append(list, field.Invalid(field.NewPath("spec", "settings"), obj.Spec.Settings, err.Error()))
+	}
+	if ok, reason := rule.Valid(); !ok {
+		list = append(list, field.Invalid(field.NewPath("spec"), obj.Spec.Pattern, reason))
+	}
+	return list
+}
+
+func validateWriteConfig(obj *live.WriteConfig) field.ErrorList {
+	var list field.ErrorList
+	if obj.Spec.Endpoint == "" {
+		list = append(list, field.Required(field.NewPath("spec", "endpoint"), "endpoint is required"))
+	}
+	allowed := []string{"basicAuthPassword", "bearerToken"}
+	for i, name := range obj.Spec.HTTPHeaderNames {
+		if name == "" {
+			list = append(list, field.Required(field.NewPath("spec", "httpHeaderNames").Index(i), "header name is required"))
+		}
+		allowed = append(allowed, fmt.Sprintf("httpHeaderValue%d", i+1))
+	}
+	for k := range obj.Secure {
+		if !slices.Contains(allowed, k) {
+			list = append(list, field.NotSupported(field.NewPath("secure", k), k, allowed))
+		}
+	}
+	return list
+}
+
+func toPipelineChannelRule(orgID int64, spec *live.ChannelRuleSpec) (pipeline.ChannelRule, error) {
+	rule := pipeline.ChannelRule{
+		OrgId:   orgID,
+		Pattern: spec.Pattern,
+	}
+	if spec.Settings.Object == nil {
+		return rule, nil
+	}
+	body, err := json.Marshal(spec.Settings.Object)
+	if err != nil {
+		return rule, err
+	}
+	if err = json.Unmarshal(body, &rule.Settings); err != nil {
+		return rule, fmt.Errorf("invalid settings: %w", err)
+	}
+	return rule, nil
+}
+
+// toPipelineWriteConfig converts the write config, secureSettings are the secure values
+// encrypted with the secrets service
+func toPipelineWriteConfig(orgID int64, obj *live.WriteConfig, secureSettings map[string][]byte) pipeline.WriteConfig {
+	cfg := pipeline.WriteConfig{
+		OrgId:          orgID,
+		UID:            obj.Name,
+		SecureSettings: secureSettings,
+		Settings: pipeline.WriteSettings{
+			Endpoint:        obj.Spec.Endpoint,
+			HTTPHeaderNames: obj.Spec.HTTPHeaderNames,
+		},
+	}
+	if obj.Spec.BasicAuth != nil {
+		cfg.Settings.BasicAuth = &pipeline.BasicAuth{
+			User: obj.Spec.BasicAuth.User,
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,206 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretv1beta1 "github.com/grafana/grafana/apps/secret/pkg/apis/secret/v1beta1"
+	"github.com/grafana/grafana/apps/secret/pkg/decrypt"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	live "github.com/grafana/grafana/pkg/apis/live/v0alpha1"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+func TestValidateChannelRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     live.ChannelRuleSpec
+		expected int
+	}{
+		{
+			name: "valid rule",
+			spec: live.ChannelRuleSpec{
+				Pattern: "stream/test/:metric",
+				Settings: common.Unstructured{Object: map[string]any{
+					"converter": map[string]any{"type": pipeline.ConverterTypeJsonAuto},
+				}},
+			},
+		},
+		{
+			name:     "invalid pattern",
+			spec:     live.ChannelRuleSpec{Pattern: "/stream/test"},
+			expected: 1,
+		},
+		{
+			name: "unknown converter",
+			spec: live.ChannelRuleSpec{
+				Pattern: "stream/test/xxx",
+				Settings: common.Unstructured{Object: map[string]any{
+					"converter": map[string]any{"type": "unknown"},
+				}},
+			},
+			expected: 1,
+		},
+		{
+			name: "invalid settings",
+			spec: live.ChannelRuleSpec{
+				Pattern: "stream/test/xxx",
+				Settings: common.Unstructured{Object: map[string]any{
+					"frameOutputs": "not a list",
+				}},
+			},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := validateChannelRule(&live.ChannelRule{Spec: tt.spec})
+			require.Len(t, list, tt.expected, "%v", list)
+		})
+	}
+}
+
+func TestValidateWriteConfig(t *testing.T) {
+	list := validateWriteConfig(&live.WriteConfig{
+		Spec: live.WriteConfigSpec{Endpoint: "http://localhost:9090/api/v1/write"},
+		Secure: common.InlineSecureValues{
+			"basicAuthPassword": {Create: common.NewSecretValue("secret")},
+		},
+	})
+	require.Empty(t, list)
+
+	list = validateWriteConfig(&live.WriteConfig{
+		Secure: common.InlineSecureValues{
+			"token": {Create: common.NewSecretValue("secret")},
+		},
+	})
+	require.Len(t, list, 2)
+
+	list = validateWriteConfig(&live.WriteConfig{
+		Spec: live.WriteConfigSpec{
+			Endpoint:        "http://localhost:8080/hook",
+			HTTPHeaderNames: []string{"X-Api-Key"},
+		},
+		Secure: common.InlineSecureValues{
+			"bearerToken":      {Create: common.NewSecretValue("token")},
+			"httpHeaderValue1": {Create: common.NewSecretValue("key")},
+			"httpHeaderValue2": {Create: common.NewSecretValue("key")},
+		},
+	})
+	require.Len(t, list, 1)
+}
+
+func TestDryRun(t *testing.T) {
+	storage := &dryRunStorage{
+		writeConfigs: []pipeline.WriteConfig{
+			toPipelineWriteConfig(1, &live.WriteConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "prom"},
+				Spec:       live.WriteConfigSpec{Endpoint: "http://localhost:9090/api/v1/write"},
+			}, nil),
+		},
+	}
+	err := storage.replace(1, "test", &live.ChannelRuleSpec{
+		Pattern: "stream/test/xxx",
+		Settings: common.Unstructured{Object: map[string]any{
+			"converter": map[string]any{"type": pipeline.ConverterTypeJsonAuto},
+			"frameOutputs": []any{
+				map[string]any{
+					"type":        pipeline.FrameOutputTypeRemoteWrite,
+					"remoteWrite": map[string]any{"uid": "prom"},
+				},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	rsp, err := dryRun(context.Background(), storage, 1, "stream/test/xxx", []byte(`{"value": 1}`))
+	require.NoError(t, err)
+	require.Equal(t, "stream/test/xxx", rsp.Channel)
+	require.Len(t, rsp.Stages, 2)
+	require.Equal(t, pipeline.DryRunStageConverter, rsp.Stages[0].Stage)
+	require.Len(t, rsp.Stages[0].Frames, 1)
+	require.Equal(t, pipeline.DryRunStageFrameOutput, rsp.Stages[1].Stage)
+	require.Equal(t, pipeline.FrameOutputTypeRemoteWrite, rsp.Stages[1].Type)
+	require.Len(t, rsp.Stages[1].Frames, 1)
+}
+
+func TestDryRunStorageReplace(t *testing.T) {
+	storage := &dryRunStorage{
+		channelRules: []pipeline.ChannelRule{
+			{OrgId: 1, Pattern: "stream/test/xxx"},
+			{OrgId: 1, Pattern: "stream/test/yyy"},
+		},
+		names: []string{"first", "second"},
+	}
+
+	// The pattern of a saved rule can be changed
+	err := storage.replace(1, "first", &live.ChannelRuleSpec{Pattern: "stream/test/zzz"})
+	require.NoError(t, err)
+	require.Equal(t, []pipeline.ChannelRule{
+		{OrgId: 1, Pattern: "stream/test/zzz"},
+		{OrgId: 1, Pattern: "stream/test/yyy"},
+	}, storage.channelRules)
+
+	// Unsaved rules are added, even when another rule uses the same pattern
+	err = storage.replace(1, "third", &live.ChannelRuleSpec{Pattern: "stream/test/yyy"})
+	require.NoError(t, err)
+	require.Len(t, storage.channelRules, 3)
+	require.Equal(t, []string{"first", "second", "third"}, storage.names)
+}
+
+type fakeDecrypter map[string]string
+
+func (f fakeDecrypter) Decrypt(_ context.Context, _ string, _ string, names ...string) (map[string]decrypt.DecryptResult, error) {
+	results := make(map[string]decrypt.DecryptResult, len(names))
+	for _, name := range names {
+		value, ok := f[name]
+		if !ok {
+			results[name] = decrypt.NewDecryptResultErr(errors.New("not found"))
+			continue
+		}
+		exposed := secretv1beta1.NewExposedSecureValue(value)
+		results[name] = decrypt.NewDecryptResultValue(&exposed)
+	}
+	return results, nil
+}
+
+func TestPipelineStorageWriteConfigSecureValues(t *testing.T) {
+	storage := &pipelineStorage{
+		decrypter: fakeDecrypter{"saved-password": "secret"},
+		secrets:   fakes.NewFakeSecretsService(),
+	}
+
+	cfg, err := storage.toWriteConfig(context.Background(), 1, &live.WriteConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "prom", Namespace: "default"},
+		Spec: live.WriteConfigSpec{
+			Endpoint:        "http://localhost:9090/api/v1/write",
+			BasicAuth:       &live.BasicAuth{User: "admin"},
+			HTTPHeaderNames: []string{"X-Scope-OrgID"},
+		},
+		Secure: common.InlineSecureValues{
+			"basicAuthPassword": {Name: "saved-password"},
+			"httpHeaderValue1":  {Create: common.NewSecretValue("tenant")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "prom", cfg.UID)
+	require.Equal(t, "admin", cfg.Settings.BasicAuth.User)
+	require.Equal(t, map[string][]byte{
+		"basicAuthPassword": []byte("secret"),
+		"httpHeaderValue1":  []byte("tenant"),
+	}, cfg.SecureSettings)
+
+	_, err = storage.toWriteConfig(context.Background(), 1, &live.WriteConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "prom", Namespace: "default"},
+		Secure: common.InlineSecureValues{
+			"bearerToken": {Name: "missing"},
+		},
+	})
+	require.ErrorContains(t, err, "secure value bearerToken: not found")
+}
@@ -0,0 +1,173 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8srequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/grafana/grafana/apps/secret/pkg/decrypt"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	live "github.com/grafana/grafana/pkg/apis/live/v0alpha1"
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+var errManagedByAPI = errors.New("channel rules and write configs are managed with the " + live.GROUP + " API")
+
+// pipelineStorage reads the channel rules and write configs the Live pipeline
+// runs with from the API resources. Changes are only accepted through the API.
+type pipelineStorage struct {
+	rules        rest.Lister
+	writeConfigs ruleStore
+	namespacer   request.NamespaceMapper
+	decrypter    decrypt.DecryptService
+	secrets      secrets.Service
+}
+
+var _ pipeline.Storage = (*pipelineStorage)(nil)
+
+// withOrg returns a context to read the resources of an org as the Live service
+func (s *pipelineStorage) withOrg(ctx context.Context, orgID int64) context.Context {
+	ctx = identity.WithServiceIdentityContext(ctx, orgID)
+	return k8srequest.WithNamespace(ctx, s.namespacer(orgID))
+}
+
+func (s *pipelineStorage) ListChannelRules(ctx context.Context, orgID int64) ([]pipeline.ChannelRule, error) {
+	obj, err := s.rules.List(s.withOrg(ctx, orgID), &internalversion.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(*live.ChannelRuleList)
+	if !ok {
+		return nil, fmt.Errorf("expected channel rule list, found %T", obj)
+	}
+	rules := make([]pipeline.ChannelRule, 0, len(list.Items))
+	for i := range list.Items {
+		rule, err := toPipelineChannelRule(orgID, &list.Items[i].Spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel rule %s: %w", list.Items[i].Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (s *pipelineStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]pipeline.WriteConfig, error) {
+	ctx = s.withOrg(ctx, orgID)
+	obj, err := s.writeConfigs.List(ctx, &internalversion.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(*live.WriteConfigList)
+	if !ok {
+		return nil, fmt.Errorf("expected write config list, found %T", obj)
+	}
+	configs := make([]pipeline.WriteConfig, 0, len(list.Items))
+	for i := range list.Items {
+		cfg, err := s.toWriteConfig(ctx, orgID, &list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+func (s *pipelineStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigGetCmd) (pipeline.WriteConfig, bool, error) {
+	ctx = s.withOrg(ctx, orgID)
+	obj, err := s.writeConfigs.Get(ctx, cmd.UID, &metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return pipeline.WriteConfig{}, false, nil
+		}
+		return pipeline.WriteConfig{}, false, err
+	}
+	writeConfig, ok := obj.(*live.WriteConfig)
+	if !ok {
+		return pipeline.WriteConfig{}, false, fmt.Errorf("expected write config, found %T", obj)
+	}
+	cfg, err := s.toWriteConfig(ctx, orgID, writeConfig)
+	if err != nil {
+		return pipeline.WriteConfig{}, false, err
+	}
+	return cfg, true, nil
+}
+
+// toWriteConfig resolves the secure values of the write config and encrypts them
+// the same way the file storage does, so the rule builder can read them.
+func (s *pipelineStorage) toWriteConfig(ctx context.Context, orgID int64, obj *live.WriteConfig) (pipeline.WriteConfig, error) {
+	values := make(map[string]string, len(obj.Secure))
+	var names []string
+	for key, sv := range obj.Secure {
+		switch {
+		case !sv.Create.IsZero():
+			values[key] = string(sv.Create)
+		case sv.Name != "":
+			names = append(names, sv.Name)
+		}
+	}
+
+	if len(names) > 0 {
+		if s.decrypter == nil {
+			return pipeline.WriteConfig{}, fmt.Errorf("write config %s: secure values can not be decrypted", obj.Name)
+		}
+		results, err := s.decrypter.Decrypt(ctx, live.GROUP, obj.Namespace, names...)
+		if err != nil {
+			return pipeline.WriteConfig{}, fmt.Errorf("write config %s: failed to call decrypt service: %w", obj.Name, err)
+		}
+		for key, sv := range obj.Secure {
+			if sv.Name == "" || !sv.Create.IsZero() {
+				continue
+			}
+			result, ok := results[sv.Name]
+			if !ok {
+				return pipeline.WriteConfig{}, fmt.Errorf("write config %s: secure value %s not found", obj.Name, key)
+			}
+			if result.Error() != nil {
+				return pipeline.WriteConfig{}, fmt.Errorf("write config %s: secure value %s: %w", obj.Name, key, result.Error())
+			}
+			values[key] = result.Value().DangerouslyExposeAndConsumeValue()
+		}
+	}
+
+	var secureSettings map[string][]byte
+	if len(values) > 0 {
+		var err error
+		secureSettings, err = s.secrets.EncryptJsonData(ctx, values, secrets.WithoutScope())
+		if err != nil {
+			return pipeline.WriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+		}
+	}
+	return toPipelineWriteConfig(orgID, obj, secureSettings), nil
+}
+
+func (s *pipelineStorage) CreateWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigCreateCmd) (pipeline.WriteConfig, error) {
+	return pipeline.WriteConfig{}, errManagedByAPI
+}
+
+func (s *pipelineStorage) UpdateWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigUpdateCmd) (pipeline.WriteConfig, error) {
+	return pipeline.WriteConfig{}, errManagedByAPI
+}
+
+func (s *pipelineStorage) DeleteWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigDeleteCmd) error {
+	return errManagedByAPI
+}
+
+func (s *pipelineStorage) CreateChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleCreateCmd) (pipeline.ChannelRule, error) {
+	return pipeline.ChannelRule{}, errManagedByAPI
+}
+
+func (s *pipelineStorage) UpdateChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleUpdateCmd) (pipeline.ChannelRule, error) {
+	return pipeline.ChannelRule{}, errManagedByAPI
+}
+
+func (s *pipelineStorage) DeleteChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleDeleteCmd) error {
+	return errManagedByAPI
+}
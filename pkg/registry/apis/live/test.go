@@ -0,0 +1,268 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	live "github.com/grafana/grafana/pkg/apis/live/v0alpha1"
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+// maxSampleSize limits the sample payload accepted by the test endpoint
+const maxSampleSize = 1024 * 1024
+
+type ruleStore interface {
+	rest.Getter
+	rest.Lister
+}
+
+// dryRunConnector runs a sample payload through a channel rule without executing any outputs
+type dryRunConnector struct {
+	rules        ruleStore
+	writeConfigs rest.Lister
+}
+
+var (
+	_ = rest.Connecter(&dryRunConnector{})
+	_ = rest.StorageMetadata(&dryRunConnector{})
+)
+
+func (*dryRunConnector) New() runtime.Object {
+	return &live.DryRunResults{}
+}
+
+func (*dryRunConnector) Destroy() {}
+
+func (*dryRunConnector) ProducesMIMETypes(verb string) []string {
+	return []string{"application/json"}
+}
+
+func (*dryRunConnector) ProducesObject(verb string) any {
+	return &live.DryRunResults{}
+}
+
+func (*dryRunConnector) ConnectMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (*dryRunConnector) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+func (c *dryRunConnector) Connect(ctx context.Context, name string, _ runtime.Object, responder rest.Responder) (http.Handler, error) {
+	ns, err := request.NamespaceInfoFrom(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSampleSize))
+		if err != nil {
+			responder.Error(apierrors.NewBadRequest(fmt.Sprintf("error reading request body: %s", err)))
+			return
+		}
+		var req live.DryRunRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			responder.Error(apierrors.NewBadRequest(fmt.Sprintf("error decoding request: %s", err)))
+			return
+		}
+
+		storage, err := c.loadStorage(ctx, ns.OrgID)
+		if err != nil {
+			responder.Error(err)
+			return
+		}
+
+		spec := req.Spec
+		if spec == nil {
+			obj, err := c.rules.Get(ctx, name, &metav1.GetOptions{})
+			if err != nil {
+				responder.Error(err)
+				return
+			}
+			rule, ok := obj.(*live.ChannelRule)
+			if !ok {
+				responder.Error(fmt.Errorf("expected channel rule, found %T", obj))
+				return
+			}
+			spec = &rule.Spec
+		}
+		if err := storage.replace(ns.OrgID, name, spec); err != nil {
+			responder.Error(apierrors.NewBadRequest(err.Error()))
+			return
+		}
+
+		channel := req.Channel
+		if channel == "" {
+			channel = spec.Pattern
+		}
+
+		rsp, err := dryRun(ctx, storage, ns.OrgID, channel, []byte(req.Data))
+		if err != nil {
+			responder.Error(apierrors.NewBadRequest(err.Error()))
+			return
+		}
+		responder.Object(http.StatusOK, rsp)
+	}), nil
+}
+
+// loadStorage reads all channel rules and write configs in the namespace so
+// redirects to other channels are resolved the same way the pipeline does it.
+func (c *dryRunConnector) loadStorage(ctx context.Context, orgID int64) (*dryRunStorage, error) {
+	storage := &dryRunStorage{}
+
+	obj, err := c.rules.List(ctx, &internalversion.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rules, ok := obj.(*live.ChannelRuleList)
+	if !ok {
+		return nil, fmt.Errorf("expected channel rule list, found %T", obj)
+	}
+	for i := range rules.Items {
+		rule, err := toPipelineChannelRule(orgID, &rules.Items[i].Spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel rule %s: %w", rules.Items[i].Name, err)
+		}
+		storage.channelRules = append(storage.channelRules, rule)
+		storage.names = append(storage.names, rules.Items[i].Name)
+	}
+
+	obj, err = c.writeConfigs.List(ctx, &internalversion.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	writeConfigs, ok := obj.(*live.WriteConfigList)
+	if !ok {
+		return nil, fmt.Errorf("expected write config list, found %T", obj)
+	}
+	for i := range writeConfigs.Items {
+		// Outputs are not executed in a dry run, so the secure values are not needed
+		storage.writeConfigs = append(storage.writeConfigs, toPipelineWriteConfig(orgID, &writeConfigs.Items[i], nil))
+	}
+	return storage, nil
+}
+
+func dryRun(ctx context.Context, storage pipeline.Storage, orgID int64, channel string, body []byte) (*live.DryRunResults, error) {
+	builder := &pipeline.StorageRuleBuilder{
+		FrameStorage: pipeline.NewFrameStorage(),
+		Storage:      storage,
+	}
+	pipe, err := pipeline.New(pipeline.NewStaticSegmentedTree(builder))
+	if err != nil {
+		return nil, err
+	}
+	stages, err := pipe.DryRun(ctx, orgID, channel, body)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &live.DryRunResults{
+		Channel: channel,
+		Stages:  make([]live.DryRunStage, 0, len(stages)),
+	}
+	for _, s := range stages {
+		stage := live.DryRunStage{
+			Stage:   s.Stage,
+			Type:    s.Type,
+			Channel: s.Channel,
+			Error:   s.Error,
+		}
+		for _, frame := range s.Frames {
+			// Frames are returned in the same JSON format the Live channels use
+			raw, err := json.Marshal(frame)
+			if err != nil {
+				return nil, err
+			}
+			u := common.Unstructured{}
+			if err := json.Unmarshal(raw, &u.Object); err != nil {
+				return nil, err
+			}
+			stage.Frames = append(stage.Frames, u)
+		}
+		rsp.Stages = append(rsp.Stages, stage)
+	}
+	return rsp, nil
+}
+
+var errDryRunReadOnly = errors.New("not supported by dry run storage")
+
+// dryRunStorage is an in-memory pipeline.Storage used to build rules for a dry run
+type dryRunStorage struct {
+	channelRules []pipeline.ChannelRule
+	// names of the channel rules resources, in the same order as channelRules
+	names        []string
+	writeConfigs []pipeline.WriteConfig
+}
+
+var _ pipeline.Storage = (*dryRunStorage)(nil)
+
+// replace swaps the saved rule with the given name, so unsaved changes can be tested
+func (s *dryRunStorage) replace(orgID int64, name string, spec *live.ChannelRuleSpec) error {
+	rule, err := toPipelineChannelRule(orgID, spec)
+	if err != nil {
+		return err
+	}
+	for i, n := range s.names {
+		if n == name {
+			s.channelRules[i] = rule
+			return nil
+		}
+	}
+	s.channelRules = append(s.channelRules, rule)
+	s.names = append(s.names, name)
+	return nil
+}
+
+func (s *dryRunStorage) ListWriteConfigs(_ context.Context, _ int64) ([]pipeline.WriteConfig, error) {
+	return s.writeConfigs, nil
+}
+
+func (s *dryRunStorage) GetWriteConfig(_ context.Context, _ int64, cmd pipeline.WriteConfigGetCmd) (pipeline.WriteConfig, bool, error) {
+	for _, cfg := range s.writeConfigs {
+		if cfg.UID == cmd.UID {
+			return cfg, true, nil
+		}
+	}
+	return pipeline.WriteConfig{}, false, nil
+}
+
+func (s *dryRunStorage) CreateWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigCreateCmd) (pipeline.WriteConfig, error) {
+	return pipeline.WriteConfig{}, errDryRunReadOnly
+}
+
+func (s *dryRunStorage) UpdateWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigUpdateCmd) (pipeline.WriteConfig, error) {
+	return pipeline.WriteConfig{}, errDryRunReadOnly
+}
+
+func (s *dryRunStorage) DeleteWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigDeleteCmd) error {
+	return errDryRunReadOnly
+}
+
+func (s *dryRunStorage) ListChannelRules(_ context.Context, _ int64) ([]pipeline.ChannelRule, error) {
+	return s.channelRules, nil
+}
+
+func (s *dryRunStorage) CreateChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleCreateCmd) (pipeline.ChannelRule, error) {
+	return pipeline.ChannelRule{}, errDryRunReadOnly
+}
+
+func (s *dryRunStorage) UpdateChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleUpdateCmd) (pipeline.ChannelRule, error) {
+	return pipeline.ChannelRule{}, errDryRunReadOnly
+}
+
+func (s *dryRunStorage) DeleteChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleDeleteCmd) error {
+	return errDryRunReadOnly
+}
@@ -39,6 +39,11 @@ func (f *finalizer) process(ctx context.Context,
 ) error {
 	logger := logging.FromContext(ctx)
 
+	// The pull request previews are copies made by the repository, they are removed whatever the finalizers
+	if err := f.removePreviews(ctx, repo.Config()); err != nil {
+		logger.Warn("error removing the pull request previews", "err", err)
+	}
+
 	for _, finalizer := range finalizers {
 		switch finalizer {
 		case CleanFinalizer:
@@ -51,7 +56,7 @@ func (f *finalizer) process(ctx context.Context,
 			}
 
 		case ReleaseOrphanResourcesFinalizer:
-			err := f.processExistingItems(ctx, repo.Config(),
+			err := f.processExistingItems(ctx, repo.Config().Namespace, repo.Config().Name,
 				func(client dynamic.ResourceInterface, item *provisioning.ResourceListItem) error {
 					patchAnnotations, err := getPatchedAnnotations(item)
 					if err != nil {
@@ -68,7 +73,7 @@ func (f *finalizer) process(ctx context.Context,
 			}
 
 		case RemoveOrphanResourcesFinalizer:
-			err := f.processExistingItems(ctx, repo.Config(),
+			err := f.processExistingItems(ctx, repo.Config().Namespace, repo.Config().Name,
 				func(client dynamic.ResourceInterface, item *provisioning.ResourceListItem) error {
 					return client.Delete(ctx, item.Name, v1.DeleteOptions{})
 				})
@@ -83,19 +88,27 @@ func (f *finalizer) process(ctx context.Context,
 	return nil
 }
 
-// internal iterator to walk the existing items
+// removePreviews deletes the resources of the pull request preview environments of the repository
+func (f *finalizer) removePreviews(ctx context.Context, repo *provisioning.Repository) error {
+	return f.processExistingItems(ctx, repo.Namespace, repository.PreviewIdentity(repo.Name),
+		func(client dynamic.ResourceInterface, item *provisioning.ResourceListItem) error {
+			return client.Delete(ctx, item.Name, v1.DeleteOptions{})
+		})
+}
+
+// internal iterator to walk the existing items of the manager
 func (f *finalizer) processExistingItems(
 	ctx context.Context,
-	repo *provisioning.Repository,
+	namespace, managerID string,
 	cb func(client dynamic.ResourceInterface, item *provisioning.ResourceListItem) error,
 ) error {
 	logger := logging.FromContext(ctx)
-	clients, err := f.clientFactory.Clients(ctx, repo.Namespace)
+	clients, err := f.clientFactory.Clients(ctx, namespace)
 	if err != nil {
 		return err
 	}
 
-	items, err := f.lister.List(ctx, repo.Namespace, repo.Name)
+	items, err := f.lister.List(ctx, namespace, managerID)
 	if err != nil {
 		logger.Warn("error listing resources", "error", err)
 		return err
@@ -39,6 +39,11 @@ type queueItem struct {
 	attempts int
 }
 
+// RepositoryMetrics are the metrics reported for each repository, removed when the repository is deleted
+type RepositoryMetrics interface {
+	Delete(namespace, repository string)
+}
+
 // RepositoryController controls how and when CRD is established.
 type RepositoryController struct {
 	client         client.ProvisioningV0alpha1Interface
@@ -55,6 +60,7 @@ type RepositoryController struct {
 
 	repoFactory   repository.Factory
 	healthChecker *HealthChecker
+	metrics       RepositoryMetrics
 	// To allow injection for testing.
 	processFn         func(item *queueItem) error
 	enqueueRepository func(obj any)
@@ -76,6 +82,7 @@ func NewRepositoryController(
 	dualwrite dualwrite.Service,
 	healthChecker *HealthChecker,
 	statusPatcher StatusPatcher,
+	metrics RepositoryMetrics,
 ) (*RepositoryController, error) {
 	rc := &RepositoryController{
 		client:         provisioningClient,
@@ -91,6 +98,7 @@ func NewRepositoryController(
 		repoFactory:   repoFactory,
 		healthChecker: healthChecker,
 		statusPatcher: statusPatcher,
+		metrics:       metrics,
 		parsers:       parsers,
 		finalizer: &finalizer{
 			lister:        resourceLister,
@@ -225,6 +233,11 @@ func (rc *RepositoryController) handleDelete(ctx context.Context, obj *provision
 			}
 		}
 
+		// The repository is gone once the finalizers are removed
+		if rc.metrics != nil {
+			rc.metrics.Delete(obj.GetNamespace(), obj.GetName())
+		}
+
 		// remove the finalizers
 		_, err = rc.client.Repositories(obj.GetNamespace()).
 			Patch(ctx, obj.Name, types.JSONPatchType, []byte(`[
@@ -430,6 +443,13 @@ func (rc *RepositoryController) process(item *queueItem) error {
 		return nil
 	}
 
+	// The pull request previews are removed once they are disabled
+	if hasSpecChanged && (obj.Spec.GitHub == nil || !obj.Spec.GitHub.GeneratePreviewEnvironments) {
+		if err := rc.finalizer.removePreviews(ctx, obj); err != nil {
+			logger.Warn("failed to remove the pull request previews", "err", err)
+		}
+	}
+
 	repo, err := rc.repoFactory.Build(ctx, obj)
 	if err != nil {
 		return fmt.Errorf("unable to create repository from configuration: %w", err)
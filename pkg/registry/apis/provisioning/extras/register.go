@@ -2,6 +2,7 @@ package extras
 
 import (
 	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository/bucket"
 	"github.com/grafana/grafana/apps/provisioning/pkg/repository/github"
 	"github.com/grafana/grafana/apps/provisioning/pkg/repository/local"
 	"github.com/grafana/grafana/apps/secret/pkg/decrypt"
@@ -28,6 +29,12 @@ func ProvideProvisioningOSSRepositoryExtras(
 			cfg.HomePath,
 			cfg.PermittedProvisioningPaths,
 		),
+		bucket.Extra(
+			cfg.HomePath,
+			cfg.PermittedProvisioningPaths,
+			cfg.PermittedBucketEndpoints,
+			repository.ProvideDecrypter(decryptSvc),
+		),
 		github.Extra(
 			repository.ProvideDecrypter(decryptSvc),
 			ghFactory,
@@ -0,0 +1,277 @@
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	dashboard "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/apps/provisioning/pkg/safepath"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/sync"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+)
+
+// The reasons for a resource to be reported as modified
+const (
+	ReasonFileChanged     = "the file changed since the last sync"
+	ReasonResourceChanged = "the resource was changed in grafana"
+	ReasonFolderChanged   = "the resource was moved to another folder in grafana"
+)
+
+// Detect compares the repository tree with the resources managed by the repository. Files with a different
+// hash than the last synced one are modified, and the unchanged files are compared with the live objects to find
+// the changes made in grafana. Nothing is written, neither in grafana nor in the repository.
+func Detect(
+	ctx context.Context,
+	repo repository.Reader,
+	target *provisioning.ResourceList,
+	parser resources.Parser,
+	ref string,
+	progress jobs.JobProgressRecorder,
+) ([]provisioning.DriftItem, error) {
+	source, err := repo.ReadTree(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tree: %w", err)
+	}
+
+	changes, err := sync.Changes(source, target)
+	if err != nil {
+		return nil, fmt.Errorf("calculate changes: %w", err)
+	}
+
+	progress.SetTotal(ctx, len(target.Items)+len(changes))
+
+	var items []provisioning.DriftItem
+	changed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		changed[change.Path] = true
+
+		item, err := changeToDrift(change)
+		result := jobs.JobResourceResult{
+			Path:   change.Path,
+			Action: repository.FileActionIgnored, // nothing is applied
+			Error:  err,
+		}
+		if item != nil {
+			result.Name = item.Name
+			result.Resource = item.Resource
+			result.Group = item.Group
+			items = append(items, *item)
+		}
+		progress.Record(ctx, result)
+	}
+
+	// The unchanged files can still differ from what was saved in grafana
+	for _, existing := range target.Items {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if existing.Path == "" || existing.Group == resources.FolderResource.Group || changed[existing.Path] {
+			continue
+		}
+		if resources.IsProvisioningFileItem(existing) {
+			continue // the provisioning files define many objects, they are only compared by hash
+		}
+		if err := progress.TooManyErrors(); err != nil {
+			return nil, err
+		}
+
+		item, err := compareLive(ctx, repo, parser, existing, ref)
+		progress.Record(ctx, jobs.JobResourceResult{
+			Name:     existing.Name,
+			Resource: existing.Resource,
+			Group:    existing.Group,
+			Path:     existing.Path,
+			Action:   repository.FileActionIgnored, // nothing is applied
+			Error:    err,
+		})
+		if item != nil {
+			items = append(items, *item)
+		}
+	}
+
+	return items, nil
+}
+
+func changeToDrift(change sync.ResourceFileChange) (*provisioning.DriftItem, error) {
+	switch change.Action {
+	case repository.FileActionCreated:
+		if safepath.IsDir(change.Path) {
+			return &provisioning.DriftItem{
+				Type:     provisioning.DriftTypeMissing,
+				Path:     change.Path,
+				Group:    resources.FolderResource.Group,
+				Resource: resources.FolderResource.Resource,
+			}, nil
+		}
+		return &provisioning.DriftItem{
+			Type: provisioning.DriftTypeMissing,
+			Path: change.Path,
+		}, nil
+	case repository.FileActionUpdated:
+		return &provisioning.DriftItem{
+			Type:     provisioning.DriftTypeModified,
+			Path:     change.Path,
+			Group:    change.Existing.Group,
+			Resource: change.Existing.Resource,
+			Name:     change.Existing.Name,
+			Reason:   ReasonFileChanged,
+		}, nil
+	case repository.FileActionDeleted:
+		if change.Existing == nil {
+			return nil, errors.New("missing existing reference")
+		}
+		return &provisioning.DriftItem{
+			Type:     provisioning.DriftTypeOrphaned,
+			Path:     change.Path,
+			Group:    change.Existing.Group,
+			Resource: change.Existing.Resource,
+			Name:     change.Existing.Name,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unexpected action %s", change.Action)
+	}
+}
+
+// compareLive reads the file and compares it with the object saved in grafana
+func compareLive(ctx context.Context, repo repository.Reader, parser resources.Parser, existing provisioning.ResourceListItem, ref string) (*provisioning.DriftItem, error) {
+	item := &provisioning.DriftItem{
+		Type:     provisioning.DriftTypeModified,
+		Path:     existing.Path,
+		Group:    existing.Group,
+		Resource: existing.Resource,
+		Name:     existing.Name,
+	}
+
+	info, err := repo.Read(ctx, existing.Path, ref)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	// Templates can render many resources
+	all, err := parser.ParseAll(ctx, info)
+	if err != nil {
+		return nil, fmt.Errorf("parse file: %w", err)
+	}
+	var parsed *resources.ParsedResource
+	for _, p := range all {
+		if p.Obj.GetName() == existing.Name && p.Obj.GroupVersionKind().Group == existing.Group {
+			parsed = p
+			break
+		}
+	}
+	if parsed == nil {
+		item.Type = provisioning.DriftTypeOrphaned
+		return item, nil
+	}
+
+	live, err := parsed.Client.Get(ctx, existing.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			item.Type = provisioning.DriftTypeMissing
+			return item, nil
+		}
+		return nil, fmt.Errorf("get resource: %w", err)
+	}
+
+	meta, err := utils.MetaAccessor(live)
+	if err != nil {
+		return nil, fmt.Errorf("get meta accessor: %w", err)
+	}
+	if meta.GetFolder() != parsed.Meta.GetFolder() {
+		item.Reason = ReasonFolderChanged
+		return item, nil
+	}
+
+	expected, err := specOf(parsed.Obj)
+	if err != nil {
+		return nil, fmt.Errorf("normalize file spec: %w", err)
+	}
+	actual, err := specOf(live)
+	if err != nil {
+		return nil, fmt.Errorf("normalize resource spec: %w", err)
+	}
+	if !apiequality.Semantic.DeepEqual(expected, actual) {
+		item.Reason = ReasonResourceChanged
+		return item, nil
+	}
+
+	return nil, nil
+}
+
+// specOf returns the normalized spec, without the values that are set by grafana when the object is saved
+func specOf(obj *unstructured.Unstructured) (any, error) {
+	obj = obj.DeepCopy()
+	if obj.GroupVersionKind().Group == dashboard.GROUP {
+		unstructured.RemoveNestedField(obj.Object, "spec", "uid")
+		unstructured.RemoveNestedField(obj.Object, "spec", "version")
+		unstructured.RemoveNestedField(obj.Object, "spec", "id")
+	}
+	spec, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+
+	// The file is decoded with int64 numbers while the saved object can have float64 ones,
+	// so both are converted to their JSON representation before comparing them
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	normalized = withoutEmpty(normalized)
+	if isEmpty(normalized) {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// withoutEmpty removes the null values and the empty objects and lists, which are dropped
+// or added by the storage when the object is saved
+func withoutEmpty(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			value = withoutEmpty(value)
+			if !isEmpty(value) {
+				out[key] = value
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, value := range v {
+			out[i] = withoutEmpty(value)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
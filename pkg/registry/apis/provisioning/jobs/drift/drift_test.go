@@ -0,0 +1,135 @@
+package drift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+)
+
+func testDashboard(name, title, folder string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "dashboard.grafana.app/v1beta1",
+		"kind":       "Dashboard",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "default",
+		},
+		"spec": map[string]any{
+			"title": title,
+		},
+	}}
+	if folder != "" {
+		obj.SetAnnotations(map[string]string{utils.AnnoKeyFolder: folder})
+	}
+	return obj
+}
+
+func TestDetect(t *testing.T) {
+	ctx := context.Background()
+	dashboard := func(path, name, hash string) provisioning.ResourceListItem {
+		return provisioning.ResourceListItem{
+			Path:     path,
+			Group:    resources.DashboardResource.Group,
+			Resource: resources.DashboardResource.Resource,
+			Name:     name,
+			Hash:     hash,
+		}
+	}
+
+	target := &provisioning.ResourceList{
+		Items: []provisioning.ResourceListItem{
+			dashboard("same.json", "same", "h1"),
+			dashboard("file-changed.json", "file-changed", "h1"),
+			dashboard("live-changed.json", "live-changed", "h1"),
+			dashboard("moved.json", "moved", "h1"),
+			dashboard("not-found.json", "not-found", "h1"),
+			dashboard("gone.json", "gone", "h1"),
+			{Path: "alerting/rules.yaml", Group: resources.AlertingFileKind.Group, Resource: resources.AlertingFileKind.Kind, Name: "alerting/rules.yaml", Hash: "h1"},
+		},
+	}
+
+	repo := repository.NewMockReader(t)
+	repo.EXPECT().ReadTree(ctx, "main").Return([]repository.FileTreeEntry{
+		{Path: "same.json", Hash: "h1", Blob: true},
+		{Path: "file-changed.json", Hash: "h2", Blob: true},
+		{Path: "live-changed.json", Hash: "h1", Blob: true},
+		{Path: "moved.json", Hash: "h1", Blob: true},
+		{Path: "not-found.json", Hash: "h1", Blob: true},
+		{Path: "new.json", Hash: "h1", Blob: true},
+		{Path: "alerting/rules.yaml", Hash: "h1", Blob: true},
+	}, nil)
+	for _, path := range []string{"same.json", "live-changed.json", "moved.json", "not-found.json"} {
+		repo.EXPECT().Read(ctx, path, "main").Return(&repository.FileInfo{Path: path, Data: []byte("{}")}, nil)
+	}
+
+	fakeDynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		resources.DashboardResource: "DashboardList",
+	},
+		testDashboard("same", "same", ""),
+		testDashboard("live-changed", "edited in grafana", ""),
+		testDashboard("moved", "moved", "other-folder"),
+	)
+	client := fakeDynamicClient.Resource(resources.DashboardResource).Namespace("default")
+
+	parser := resources.NewMockParser(t)
+	parser.EXPECT().ParseAll(ctx, mock.Anything).RunAndReturn(func(_ context.Context, info *repository.FileInfo) ([]*resources.ParsedResource, error) {
+		name := info.Path[:len(info.Path)-len(".json")]
+		obj := testDashboard(name, name, "")
+		meta, err := utils.MetaAccessor(obj)
+		require.NoError(t, err)
+		return []*resources.ParsedResource{{Info: info, Obj: obj, Meta: meta, Client: client}}, nil
+	})
+
+	progress := jobs.NewMockJobProgressRecorder(t)
+	progress.EXPECT().SetTotal(ctx, 10).Return()
+	progress.EXPECT().Record(ctx, mock.Anything).Return()
+	progress.EXPECT().TooManyErrors().Return(nil)
+
+	items, err := Detect(ctx, repo, target, parser, "main", progress)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []provisioning.DriftItem{
+		{Type: provisioning.DriftTypeModified, Path: "file-changed.json", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "file-changed", Reason: ReasonFileChanged},
+		{Type: provisioning.DriftTypeModified, Path: "live-changed.json", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "live-changed", Reason: ReasonResourceChanged},
+		{Type: provisioning.DriftTypeModified, Path: "moved.json", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "moved", Reason: ReasonFolderChanged},
+		{Type: provisioning.DriftTypeMissing, Path: "not-found.json", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "not-found"},
+		{Type: provisioning.DriftTypeMissing, Path: "new.json"},
+		{Type: provisioning.DriftTypeOrphaned, Path: "gone.json", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "gone"},
+	}, items)
+}
+
+func TestSpecOf(t *testing.T) {
+	file := testDashboard("a", "title", "")
+	require.NoError(t, unstructured.SetNestedField(file.Object, int64(39), "spec", "schemaVersion"))
+	require.NoError(t, unstructured.SetNestedSlice(file.Object, []any{}, "spec", "tags"))
+	require.NoError(t, unstructured.SetNestedField(file.Object, nil, "spec", "description"))
+	require.NoError(t, unstructured.SetNestedField(file.Object, "a", "spec", "uid"))
+
+	live := testDashboard("a", "title", "")
+	require.NoError(t, unstructured.SetNestedField(live.Object, float64(39), "spec", "schemaVersion"))
+	require.NoError(t, unstructured.SetNestedMap(live.Object, map[string]any{}, "spec", "links"))
+	require.NoError(t, unstructured.SetNestedField(live.Object, int64(3), "spec", "version"))
+
+	expected, err := specOf(file)
+	require.NoError(t, err)
+	actual, err := specOf(live)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+	require.Equal(t, map[string]any{"title": "title", "schemaVersion": float64(39)}, actual)
+
+	require.NoError(t, unstructured.SetNestedField(live.Object, float64(40), "spec", "schemaVersion"))
+	actual, err = specOf(live)
+	require.NoError(t, err)
+	require.NotEqual(t, expected, actual)
+}
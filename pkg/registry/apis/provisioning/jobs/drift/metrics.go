@@ -0,0 +1,71 @@
+package drift
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+)
+
+const (
+	namespace = "grafana"
+	subsystem = "provisioning_drift"
+)
+
+// Metrics reports the result of the last drift job of each repository
+type Metrics struct {
+	Resources *prometheus.GaugeVec
+	Checked   *prometheus.GaugeVec
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{
+		Resources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "resources",
+			Help:      "Number of resources that differ between the repository and grafana",
+		}, []string{"namespace", "repository", "type"}),
+		Checked: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "last_check_timestamp_seconds",
+			Help:      "When the repository was last compared with grafana",
+		}, []string{"namespace", "repository"}),
+	}
+}
+
+// NewMetrics creates the drift metrics, registered when a registerer is given
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := newMetrics()
+
+	if reg != nil {
+		reg.MustRegister(
+			m.Resources,
+			m.Checked,
+		)
+	}
+
+	return m
+}
+
+// Record sets the metrics of the repository from the drift report
+func (m *Metrics) Record(ns, repository string, status provisioning.DriftStatus) {
+	if m == nil {
+		return
+	}
+	m.Resources.WithLabelValues(ns, repository, string(provisioning.DriftTypeModified)).Set(float64(status.Modified))
+	m.Resources.WithLabelValues(ns, repository, string(provisioning.DriftTypeMissing)).Set(float64(status.Missing))
+	m.Resources.WithLabelValues(ns, repository, string(provisioning.DriftTypeOrphaned)).Set(float64(status.Orphaned))
+	m.Checked.WithLabelValues(ns, repository).Set(float64(time.UnixMilli(status.Checked).Unix()))
+}
+
+// Delete removes the metrics of a deleted repository
+func (m *Metrics) Delete(ns, repository string) {
+	if m == nil {
+		return
+	}
+	m.Resources.DeletePartialMatch(prometheus.Labels{"namespace": ns, "repository": repository})
+	m.Checked.DeleteLabelValues(ns, repository)
+}
@@ -0,0 +1,175 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-app-sdk/logging"
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/sync"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+	"github.com/grafana/grafana/pkg/storage/legacysql/dualwrite"
+)
+
+// maxItems limits the differences saved in the repository status, the counts are always complete
+const maxItems = 100
+
+// DriftWorker compares the repository with the grafana database, and reports the differences
+// in the repository status and the metrics without applying them
+type DriftWorker struct {
+	// List the resources managed by the repository
+	lister resources.ResourceLister
+
+	// List the applied alerting files, nil when they are not supported
+	alerting resources.AlertingProvisioner
+
+	// List the applied library panel files, nil when they are not supported
+	libraryPanels resources.LibraryPanelProvisioner
+
+	// Parse the repository files
+	parsers resources.ParserFactory
+
+	// Check if the system is using unified storage
+	storageStatus dualwrite.Service
+
+	// Patch status for the repository
+	patchStatus sync.RepositoryPatchFn
+
+	metrics *Metrics
+}
+
+func NewDriftWorker(
+	lister resources.ResourceLister,
+	alerting resources.AlertingProvisioner,
+	libraryPanels resources.LibraryPanelProvisioner,
+	parsers resources.ParserFactory,
+	storageStatus dualwrite.Service,
+	patchStatus sync.RepositoryPatchFn,
+	metrics *Metrics,
+) *DriftWorker {
+	return &DriftWorker{
+		lister:        lister,
+		alerting:      alerting,
+		libraryPanels: libraryPanels,
+		parsers:       parsers,
+		storageStatus: storageStatus,
+		patchStatus:   patchStatus,
+		metrics:       metrics,
+	}
+}
+
+func (w *DriftWorker) IsSupported(ctx context.Context, job provisioning.Job) bool {
+	return job.Spec.Action == provisioning.JobActionDrift
+}
+
+func (w *DriftWorker) Process(ctx context.Context, repo repository.Repository, job provisioning.Job, progress jobs.JobProgressRecorder) error {
+	cfg := repo.Config()
+	logger := logging.FromContext(ctx).With("job", job.GetName(), "namespace", job.GetNamespace())
+	if dualwrite.IsReadingLegacyDashboardsAndFolders(ctx, w.storageStatus) {
+		return fmt.Errorf("drift detection not supported until storage has migrated")
+	}
+
+	// Nothing is written, so the read-only repositories can be compared too
+	reader, ok := repo.(repository.Reader)
+	if !ok {
+		return fmt.Errorf("drift job submitted for repository that does not support read -- this is a bug")
+	}
+
+	var ref string
+	if job.Spec.Drift != nil {
+		ref = job.Spec.Drift.Ref
+	}
+	if versioned, ok := repo.(repository.Versioned); ok && ref == "" {
+		var err error
+		ref, err = versioned.LatestRef(ctx)
+		if err != nil {
+			return fmt.Errorf("get latest ref: %w", err)
+		}
+	}
+
+	// The status may not have a drift report yet, so it is added rather than replaced
+	progress.SetMessage(ctx, "update drift status at start")
+	if err := w.patchStatus(ctx, cfg, map[string]interface{}{
+		"op":   "add",
+		"path": "/status/drift",
+		"value": provisioning.DriftStatus{
+			State: provisioning.JobStateWorking,
+			JobID: job.Name,
+			Ref:   ref,
+		},
+	}); err != nil {
+		return fmt.Errorf("update repo with drift status at start: %w", err)
+	}
+
+	target, err := resources.ListRepositoryResources(ctx, w.lister, w.alerting, w.libraryPanels, cfg.Namespace, cfg.Name)
+	if err != nil {
+		return fmt.Errorf("error listing current: %w", err)
+	}
+
+	parser, err := w.parsers.GetParser(ctx, reader)
+	if err != nil {
+		return fmt.Errorf("failed to get parser for %s: %w", cfg.Name, err)
+	}
+
+	progress.SetMessage(ctx, "compare repository and grafana")
+	progress.StrictMaxErrors(20) // make it stop after 20 errors
+
+	items, detectErr := Detect(ctx, reader, target, parser, ref, progress)
+	status := NewDriftStatus(items)
+	if detectErr == nil {
+		if len(items) == 0 {
+			progress.SetFinalMessage(ctx, "no drift detected")
+		} else {
+			progress.SetFinalMessage(ctx, fmt.Sprintf("drift detected: %d modified, %d missing, %d orphaned", status.Modified, status.Missing, status.Orphaned))
+		}
+	}
+
+	jobStatus := progress.Complete(ctx, detectErr)
+	status.State = jobStatus.State
+	status.JobID = job.Name
+	status.Checked = time.Now().UnixMilli()
+	status.Ref = ref
+	status.Message = append(status.Message, jobStatus.Errors...)
+
+	// Keep the metrics of the last complete report
+	if detectErr == nil {
+		w.metrics.Record(cfg.Namespace, cfg.Name, status)
+	}
+
+	progress.SetMessage(ctx, "update drift status")
+	if err := w.patchStatus(ctx, cfg, map[string]interface{}{
+		"op":    "add",
+		"path":  "/status/drift",
+		"value": status,
+	}); err != nil {
+		logger.Error("failed to update drift status", "error", err)
+		return fmt.Errorf("update repo with drift status: %w", err)
+	}
+
+	return detectErr
+}
+
+// NewDriftStatus counts the differences by type, and keeps the first items for the status
+func NewDriftStatus(items []provisioning.DriftItem) provisioning.DriftStatus {
+	status := provisioning.DriftStatus{}
+	for _, item := range items {
+		switch item.Type {
+		case provisioning.DriftTypeModified:
+			status.Modified++
+		case provisioning.DriftTypeMissing:
+			status.Missing++
+		case provisioning.DriftTypeOrphaned:
+			status.Orphaned++
+		}
+	}
+
+	status.Items = items
+	if len(items) > maxItems {
+		status.Items = items[:maxItems]
+		status.Message = []string{fmt.Sprintf("only the first %d of %d differences are listed", maxItems, len(items))}
+	}
+	return status
+}
@@ -0,0 +1,156 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/sync"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+	"github.com/grafana/grafana/pkg/storage/legacysql/dualwrite"
+)
+
+func TestDriftWorker_IsSupported(t *testing.T) {
+	worker := NewDriftWorker(nil, nil, nil, nil, nil, nil, nil)
+	require.True(t, worker.IsSupported(context.Background(), provisioning.Job{
+		Spec: provisioning.JobSpec{Action: provisioning.JobActionDrift},
+	}))
+	require.False(t, worker.IsSupported(context.Background(), provisioning.Job{
+		Spec: provisioning.JobSpec{Action: provisioning.JobActionPull},
+	}))
+}
+
+func TestDriftWorker_ProcessNotReader(t *testing.T) {
+	repo := repository.NewMockRepository(t)
+	repo.On("Config").Return(&provisioning.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-repo",
+		},
+	})
+	fakeDualwrite := dualwrite.NewMockService(t)
+	fakeDualwrite.On("ReadFromUnified", mock.Anything, mock.Anything).Return(true, nil).Twice()
+	worker := NewDriftWorker(nil, nil, nil, nil, fakeDualwrite, nil, nil)
+	// only the repository methods, without the reader ones
+	notReader := struct{ repository.Repository }{repo}
+	err := worker.Process(context.Background(), notReader, provisioning.Job{}, jobs.NewMockJobProgressRecorder(t))
+	require.EqualError(t, err, "drift job submitted for repository that does not support read -- this is a bug")
+}
+
+func TestDriftWorker_Process(t *testing.T) {
+	repoConfig := &provisioning.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-repo",
+			Namespace: "default",
+		},
+	}
+	repo := repository.NewMockReader(t)
+	repo.On("Config").Return(repoConfig)
+	repo.On("ReadTree", mock.Anything, "my-branch").Return([]repository.FileTreeEntry{
+		{Path: "new.json", Hash: "h1", Blob: true},
+	}, nil)
+
+	fakeDualwrite := dualwrite.NewMockService(t)
+	fakeDualwrite.On("ReadFromUnified", mock.Anything, mock.Anything).Return(true, nil).Twice()
+
+	lister := resources.NewMockResourceLister(t)
+	lister.On("List", mock.Anything, "default", "test-repo").Return(&provisioning.ResourceList{
+		Items: []provisioning.ResourceListItem{
+			{Path: "gone.json", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "gone", Hash: "h1"},
+		},
+	}, nil)
+	alerting := resources.NewMockAlertingProvisioner(t)
+	alerting.On("List", mock.Anything, "default", "test-repo").Return(map[string]string{}, nil)
+	libraryPanels := resources.NewMockLibraryPanelProvisioner(t)
+	libraryPanels.On("List", mock.Anything, "default", "test-repo").Return(map[string]string{}, nil)
+
+	parsers := resources.NewMockParserFactory(t)
+	parsers.On("GetParser", mock.Anything, repo).Return(resources.NewMockParser(t), nil)
+
+	patchStatus := sync.NewMockRepositoryPatchFn(t)
+	patchStatus.On("Execute", mock.Anything, repoConfig, map[string]interface{}{
+		"op":   "add",
+		"path": "/status/drift",
+		"value": provisioning.DriftStatus{
+			State: provisioning.JobStateWorking,
+			JobID: "test-job",
+			Ref:   "my-branch",
+		},
+	}).Return(nil).Once()
+	patchStatus.On("Execute", mock.Anything, repoConfig, mock.MatchedBy(func(patch map[string]interface{}) bool {
+		status, ok := patch["value"].(provisioning.DriftStatus)
+		return ok && patch["op"] == "add" && patch["path"] == "/status/drift" &&
+			status.State == provisioning.JobStateSuccess &&
+			status.JobID == "test-job" && status.Ref == "my-branch" && status.Checked > 0 &&
+			status.Missing == 1 && status.Orphaned == 1 && status.Modified == 0 && len(status.Items) == 2
+	})).Return(nil).Once()
+
+	progress := jobs.NewMockJobProgressRecorder(t)
+	progress.On("SetMessage", mock.Anything, mock.Anything).Return()
+	progress.On("StrictMaxErrors", 20).Return()
+	progress.On("SetTotal", mock.Anything, 3).Return()
+	progress.On("Record", mock.Anything, mock.Anything).Return()
+	progress.On("SetFinalMessage", mock.Anything, "drift detected: 0 modified, 1 missing, 1 orphaned").Return()
+	progress.On("Complete", mock.Anything, nil).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
+
+	metrics := NewMetrics(nil)
+	worker := NewDriftWorker(lister, alerting, libraryPanels, parsers, fakeDualwrite, patchStatus.Execute, metrics)
+	err := worker.Process(context.Background(), repo, provisioning.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-job"},
+		Spec: provisioning.JobSpec{
+			Action: provisioning.JobActionDrift,
+			Drift:  &provisioning.DriftJobOptions{Ref: "my-branch"},
+		},
+	}, progress)
+	require.NoError(t, err)
+}
+
+func TestMetrics_Delete(t *testing.T) {
+	metrics := NewMetrics(nil)
+	metrics.Record("default", "test-repo", provisioning.DriftStatus{Modified: 1})
+	metrics.Record("default", "other-repo", provisioning.DriftStatus{Missing: 1})
+	require.Equal(t, 6, testutil.CollectAndCount(metrics.Resources))
+	require.Equal(t, 2, testutil.CollectAndCount(metrics.Checked))
+
+	metrics.Delete("default", "test-repo")
+	require.Equal(t, 3, testutil.CollectAndCount(metrics.Resources))
+	require.Equal(t, 1, testutil.CollectAndCount(metrics.Checked))
+	require.Equal(t, float64(1), testutil.ToFloat64(metrics.Resources.WithLabelValues("default", "other-repo", string(provisioning.DriftTypeMissing))))
+
+	var nilMetrics *Metrics
+	nilMetrics.Delete("default", "test-repo")
+}
+
+func TestNewDriftStatus(t *testing.T) {
+	t.Run("counts by type", func(t *testing.T) {
+		status := NewDriftStatus([]provisioning.DriftItem{
+			{Type: provisioning.DriftTypeModified, Path: "a.json"},
+			{Type: provisioning.DriftTypeModified, Path: "b.json"},
+			{Type: provisioning.DriftTypeMissing, Path: "c.json"},
+			{Type: provisioning.DriftTypeOrphaned, Path: "d.json"},
+		})
+		require.Equal(t, int64(2), status.Modified)
+		require.Equal(t, int64(1), status.Missing)
+		require.Equal(t, int64(1), status.Orphaned)
+		require.Len(t, status.Items, 4)
+		require.Empty(t, status.Message)
+	})
+
+	t.Run("truncates the items", func(t *testing.T) {
+		items := make([]provisioning.DriftItem, 0, maxItems+10)
+		for i := 0; i < maxItems+10; i++ {
+			items = append(items, provisioning.DriftItem{Type: provisioning.DriftTypeMissing, Path: fmt.Sprintf("%d.json", i)})
+		}
+		status := NewDriftStatus(items)
+		require.Equal(t, int64(maxItems+10), status.Missing)
+		require.Len(t, status.Items, maxItems)
+		require.Equal(t, []string{"only the first 100 of 110 differences are listed"}, status.Message)
+	})
+}
@@ -0,0 +1,33 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+)
+
+// ExportAlerting writes the alerting configuration that is not provisioned yet as alerting provisioning files
+func ExportAlerting(ctx context.Context, options provisioning.ExportJobOptions, repositoryResources resources.RepositoryResources, progress jobs.JobProgressRecorder) error {
+	progress.SetMessage(ctx, "export alerting")
+	paths, err := repositoryResources.WriteAlertingFiles(ctx, resources.WriteOptions{
+		Path: options.Path,
+		Ref:  options.Branch,
+	})
+	for _, path := range paths {
+		progress.Record(ctx, jobs.JobResourceResult{
+			Name:     path,
+			Path:     path,
+			Resource: resources.AlertingFileKind.Kind,
+			Group:    resources.AlertingFileKind.Group,
+			Action:   repository.FileActionCreated,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("write alerting files: %w", err)
+	}
+	return nil
+}
@@ -24,5 +24,9 @@ func ExportAll(ctx context.Context, repoName string, options provisioning.Export
 		return err
 	}
 
+	if err := ExportAlerting(ctx, options, repositoryResources, progress); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -419,6 +419,10 @@ func mutateJobAction(job *provisioning.Job) error {
 		job.Spec.Action = provisioning.JobActionPullRequest
 		kinds[provisioning.JobActionPullRequest] = spec.PullRequest
 	}
+	if spec.Drift != nil {
+		job.Spec.Action = provisioning.JobActionDrift
+		kinds[provisioning.JobActionDrift] = spec.Drift
+	}
 	if len(kinds) > 1 {
 		return apierrors.NewBadRequest("multiple job types found")
 	}
@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/grafana/grafana-app-sdk/logging"
 	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
 	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
 )
 
 // maybeNotifyProgress will only notify if a certain amount of time has passed
@@ -75,9 +77,17 @@ func (r *jobProgressRecorder) Record(ctx context.Context, result JobResourceResu
 
 	logger := logging.FromContext(ctx).With("path", result.Path, "resource", result.Resource, "group", result.Group, "action", result.Action, "name", result.Name)
 	if result.Error != nil {
-		logger.Error("job resource operation failed", "err", result.Error)
+		message := result.Error.Error()
+		var policyErr *resources.PolicyViolationError
+		if errors.As(result.Error, &policyErr) {
+			// Only the file and the violations, so they can be fixed in the repository
+			message = fmt.Sprintf("%s: %s", result.Path, policyErr.Error())
+			logger.Warn("job resource blocked by the repository policies", "violations", policyErr.Violations)
+		} else {
+			logger.Error("job resource operation failed", "err", result.Error)
+		}
 		if len(r.errors) < 20 {
-			r.errors = append(r.errors, result.Error.Error())
+			r.errors = append(r.errors, message)
 		}
 		r.errorCount++
 	} else {
@@ -2,9 +2,12 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -83,3 +86,28 @@ func TestJobProgressRecorderCompleteIncludesRefURLs(t *testing.T) {
 	assert.Equal(t, provisioning.JobStateSuccess, finalStatus.State)
 	assert.Equal(t, "completed successfully", finalStatus.Message)
 }
+
+func TestJobProgressRecorderPolicyViolations(t *testing.T) {
+	ctx := context.Background()
+	recorder := newJobProgressRecorder(func(ctx context.Context, status provisioning.JobStatus) error {
+		return nil
+	}).(*jobProgressRecorder)
+
+	violation := &resources.PolicyViolationError{Violations: []string{"missing required tags: prod", "forbidden panel types: text"}}
+	recorder.Record(ctx, JobResourceResult{
+		Path:     "dashboards/test.json",
+		Action:   repository.FileActionCreated,
+		Name:     "test",
+		Resource: "Dashboard",
+		Group:    "dashboard.grafana.app",
+		Error:    fmt.Errorf("writing resource from file dashboards/test.json: %w", violation),
+	})
+
+	status := recorder.Complete(ctx, nil)
+	require.Equal(t, provisioning.JobStateWarning, status.State)
+	require.Equal(t, []string{
+		"dashboards/test.json: breaks the repository policies: missing required tags: prod; forbidden panel types: text",
+	}, status.Errors)
+	require.Len(t, status.Summary, 1)
+	require.Equal(t, int64(1), status.Summary[0].Error)
+}
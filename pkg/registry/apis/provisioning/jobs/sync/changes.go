@@ -47,6 +47,7 @@ func Compare(ctx context.Context, repo repository.Reader, repositoryResources re
 
 func Changes(source []repository.FileTreeEntry, target *provisioning.ResourceList) ([]ResourceFileChange, error) {
 	lookup := make(map[string]*provisioning.ResourceListItem, len(target.Items))
+	others := make(map[string][]*provisioning.ResourceListItem) // templates can render many resources
 	for _, item := range target.Items {
 		if item.Path == "" {
 			if item.Group != resources.FolderResource.Group {
@@ -60,9 +61,15 @@ func Changes(source []repository.FileTreeEntry, target *provisioning.ResourceLis
 			item.Path = item.Path + "/"
 		}
 
+		if previous, ok := lookup[item.Path]; ok && item.Group != resources.FolderResource.Group {
+			others[item.Path] = append(others[item.Path], previous)
+		}
 		lookup[item.Path] = &item
 	}
 
+	// Rendered templates also depend on the libraries
+	libraries := resources.JsonnetLibrariesHash(source)
+
 	keep := safepath.NewTrie()
 	changes := make([]ResourceFileChange, 0, len(source))
 	for _, file := range source {
@@ -73,7 +80,12 @@ func Changes(source []repository.FileTreeEntry, target *provisioning.ResourceLis
 
 		check, ok := lookup[file.Path]
 		if ok {
-			if check.Hash != file.Hash && check.Resource != resources.FolderResource.Resource {
+			hash := file.Hash
+			if resources.IsJsonnetFile(file.Path) {
+				hash = resources.JsonnetChecksum(file.Hash, libraries)
+			}
+
+			if check.Hash != hash && check.Resource != resources.FolderResource.Resource {
 				changes = append(changes, ResourceFileChange{
 					Action:   repository.FileActionUpdated,
 					Path:     check.Path,
@@ -139,6 +151,13 @@ func Changes(source []repository.FileTreeEntry, target *provisioning.ResourceLis
 			Path:     v.Path,
 			Existing: v,
 		})
+		for _, other := range others[v.Path] {
+			changes = append(changes, ResourceFileChange{
+				Action:   repository.FileActionDeleted,
+				Path:     other.Path,
+				Existing: other,
+			})
+		}
 	}
 
 	// Deepest first (stable sort order)
@@ -219,6 +219,43 @@ func TestChanges(t *testing.T) {
 		}, changes[0])
 	})
 
+	t.Run("jsonnet templates change with their libraries", func(t *testing.T) {
+		source := []repository.FileTreeEntry{
+			{Path: "dashboard.jsonnet", Hash: "template", Blob: true},
+			{Path: "vendor/", Blob: false},
+			{Path: "vendor/grafonnet.libsonnet", Hash: "library", Blob: true},
+		}
+		libraries := resources.JsonnetLibrariesHash(source)
+		target := &provisioning.ResourceList{
+			Items: []provisioning.ResourceListItem{
+				{
+					Path:     "dashboard.jsonnet",
+					Group:    "dashboard.grafana.app",
+					Resource: "dashboards",
+					Name:     "from-template",
+					Hash:     resources.JsonnetChecksum("template", libraries),
+				},
+				{
+					Path:     "vendor/",
+					Group:    resources.FolderResource.Group,
+					Resource: resources.FolderResource.Resource,
+					Name:     "vendor",
+				},
+			},
+		}
+
+		changes, err := Changes(source, target)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+
+		source[2].Hash = "updated library"
+		changes, err = Changes(source, target)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, repository.FileActionUpdated, changes[0].Action)
+		require.Equal(t, "dashboard.jsonnet", changes[0].Path)
+	})
+
 	t.Run("keep folder with hidden files", func(t *testing.T) {
 		source := []repository.FileTreeEntry{
 			{Path: "folder/.hidden.json", Hash: "xyz", Blob: true},
@@ -451,6 +488,26 @@ func TestChanges(t *testing.T) {
 			Path:   "folder2/",
 		}, changes[2])
 	})
+
+	t.Run("delete every resource rendered by a template", func(t *testing.T) {
+		source := []repository.FileTreeEntry{}
+		target := &provisioning.ResourceList{
+			Items: []provisioning.ResourceListItem{
+				{Path: "dashboards.jsonnet", Name: "a", Resource: "dashboards", Group: "dashboard.grafana.app", Hash: "xyz"},
+				{Path: "dashboards.jsonnet", Name: "b", Resource: "dashboards", Group: "dashboard.grafana.app", Hash: "xyz"},
+			},
+		}
+
+		changes, err := Changes(source, target)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		names := []string{changes[0].Existing.Name, changes[1].Existing.Name}
+		require.ElementsMatch(t, []string{"a", "b"}, names)
+		for _, change := range changes {
+			require.Equal(t, repository.FileActionDeleted, change.Action)
+			require.Equal(t, "dashboards.jsonnet", change.Path)
+		}
+	})
 }
 
 func TestCompare(t *testing.T) {
@@ -45,6 +45,8 @@ func FullSync(
 		return nil
 	}
 
+	changes = libraryPanelsFirst(ctx, repo, currentRef, changes)
+
 	return applyChanges(ctx, changes, clients, repositoryResources, progress)
 }
 
@@ -76,6 +78,24 @@ func applyChanges(ctx context.Context, changes []ResourceFileChange, clients res
 			result.Resource = change.Existing.Resource
 			result.Group = change.Existing.Group
 
+			// The alerting files are removed with the objects applied from them
+			if change.Existing.Group == resources.AlertingFileKind.Group {
+				if err := repositoryResources.RemoveAlertingFile(ctx, change.Path); err != nil {
+					result.Error = fmt.Errorf("removing alerting file %s: %w", change.Path, err)
+				}
+				progress.Record(ctx, result)
+				continue
+			}
+
+			// The library panel files are removed with the library panels applied from them
+			if change.Existing.Group == resources.LibraryPanelsFileKind.Group && change.Existing.Resource == resources.LibraryPanelsFileKind.Kind {
+				if err := repositoryResources.RemoveLibraryPanelsFile(ctx, change.Path); err != nil {
+					result.Error = fmt.Errorf("removing library panel file %s: %w", change.Path, err)
+				}
+				progress.Record(ctx, result)
+				continue
+			}
+
 			versionlessGVR := schema.GroupVersionResource{
 				Group:    change.Existing.Group,
 				Resource: change.Existing.Resource,
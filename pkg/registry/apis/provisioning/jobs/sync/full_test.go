@@ -427,6 +427,60 @@ func TestFullSync_ApplyChanges(t *testing.T) { //nolint:gocyclo
 				}).Return()
 			},
 		},
+		{
+			name:        "successful apply with alerting file deletion",
+			description: "Should remove the alerting objects applied from a deleted alerting file",
+			changes: []ResourceFileChange{
+				{
+					Action: repository.FileActionDeleted,
+					Path:   "alerting/rules.yaml",
+					Existing: &provisioning.ResourceListItem{
+						Path:     "alerting/rules.yaml",
+						Name:     "alerting/rules.yaml",
+						Resource: resources.AlertingFileKind.Kind,
+						Group:    resources.AlertingFileKind.Group,
+					},
+				},
+			},
+			setupMocks: func(repo *repository.MockRepository, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn) {
+				progress.On("TooManyErrors").Return(nil)
+				repoResources.On("RemoveAlertingFile", mock.Anything, "alerting/rules.yaml").Return(nil)
+				progress.On("Record", mock.Anything, jobs.JobResourceResult{
+					Action:   repository.FileActionDeleted,
+					Path:     "alerting/rules.yaml",
+					Name:     "alerting/rules.yaml",
+					Resource: resources.AlertingFileKind.Kind,
+					Group:    resources.AlertingFileKind.Group,
+				}).Return()
+			},
+		},
+		{
+			name:        "successful apply with library panel file deletion",
+			description: "Should remove the library panels applied from a deleted library panel file",
+			changes: []ResourceFileChange{
+				{
+					Action: repository.FileActionDeleted,
+					Path:   "panels/requests.yaml",
+					Existing: &provisioning.ResourceListItem{
+						Path:     "panels/requests.yaml",
+						Name:     "panels/requests.yaml",
+						Resource: resources.LibraryPanelsFileKind.Kind,
+						Group:    resources.LibraryPanelsFileKind.Group,
+					},
+				},
+			},
+			setupMocks: func(repo *repository.MockRepository, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn) {
+				progress.On("TooManyErrors").Return(nil)
+				repoResources.On("RemoveLibraryPanelsFile", mock.Anything, "panels/requests.yaml").Return(nil)
+				progress.On("Record", mock.Anything, jobs.JobResourceResult{
+					Action:   repository.FileActionDeleted,
+					Path:     "panels/requests.yaml",
+					Name:     "panels/requests.yaml",
+					Resource: resources.LibraryPanelsFileKind.Kind,
+					Group:    resources.LibraryPanelsFileKind.Group,
+				}).Return()
+			},
+		},
 		{
 			name:        "file delete error",
 			description: "Should return an error when deleting a file",
@@ -699,6 +753,9 @@ func TestFullSync_ApplyChanges(t *testing.T) { //nolint:gocyclo
 				},
 			})
 
+			// The new files are read to find the library panel files
+			repo.On("Read", mock.Anything, mock.Anything, "current-ref").
+				Return(&repository.FileInfo{Data: []byte(`{"kind": "Dashboard"}`)}, nil).Maybe()
 			progress.On("SetTotal", mock.Anything, len(tt.changes)).Return()
 			err := FullSync(context.Background(), repo, compareFn.Execute, clients, "current-ref", repoResources, progress)
 			if tt.expectedError != "" {
@@ -709,3 +766,43 @@ func TestFullSync_ApplyChanges(t *testing.T) { //nolint:gocyclo
 		})
 	}
 }
+
+func TestFullSync_LibraryPanelsOrder(t *testing.T) {
+	repo := repository.NewMockRepository(t)
+	panels := &provisioning.ResourceListItem{
+		Name:     "panels/updated.yaml",
+		Resource: resources.LibraryPanelsFileKind.Kind,
+		Group:    resources.LibraryPanelsFileKind.Group,
+	}
+	dashboard := &provisioning.ResourceListItem{Name: "dashboard", Resource: "dashboards", Group: "dashboard.grafana.app"}
+
+	// Only the new files are read, the applied files are known from the listed resources
+	repo.On("Read", mock.Anything, "dashboards/new.json", "current-ref").
+		Return(&repository.FileInfo{Data: []byte(`{"kind": "Dashboard"}`)}, nil)
+	repo.On("Read", mock.Anything, "panels/new.json", "current-ref").
+		Return(&repository.FileInfo{Data: []byte(`{"apiVersion": 1, "libraryPanels": [{"uid": "new"}]}`)}, nil)
+
+	changes := libraryPanelsFirst(context.Background(), repo, "current-ref", []ResourceFileChange{
+		{Action: repository.FileActionDeleted, Path: "panels/deleted.yaml", Existing: &provisioning.ResourceListItem{
+			Name:     "panels/deleted.yaml",
+			Resource: resources.LibraryPanelsFileKind.Kind,
+			Group:    resources.LibraryPanelsFileKind.Group,
+		}},
+		{Action: repository.FileActionCreated, Path: "dashboards/new.json"},
+		{Action: repository.FileActionUpdated, Path: "dashboards/dashboard.yaml", Existing: dashboard},
+		{Action: repository.FileActionUpdated, Path: "panels/updated.yaml", Existing: panels},
+		{Action: repository.FileActionCreated, Path: "panels/new.json"},
+	})
+
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	require.Equal(t, []string{
+		"panels/updated.yaml",
+		"panels/new.json",
+		"dashboards/new.json",
+		"dashboards/dashboard.yaml",
+		"panels/deleted.yaml",
+	}, paths)
+}
@@ -22,11 +22,18 @@ func IncrementalSync(ctx context.Context, repo repository.Versioned, previousRef
 		return fmt.Errorf("compare files error: %w", err)
 	}
 
+	diff, err = withJsonnetTemplates(ctx, repo, currentRef, diff)
+	if err != nil {
+		return fmt.Errorf("list jsonnet templates: %w", err)
+	}
+
 	if len(diff) < 1 {
 		progress.SetFinalMessage(ctx, "no changes detected between commits")
 		return nil
 	}
 
+	diff = withLibraryPanelsOrder(ctx, repo, diff)
+
 	progress.SetTotal(ctx, len(diff))
 	progress.SetMessage(ctx, "replicating versioned changes")
 
@@ -109,3 +116,41 @@ func IncrementalSync(ctx context.Context, repo repository.Versioned, previousRef
 
 	return nil
 }
+
+// withJsonnetTemplates adds every template to the changes when a jsonnet library changed,
+// since any of them may now render a different resource
+func withJsonnetTemplates(ctx context.Context, repo repository.Versioned, ref string, diff []repository.VersionedFileChange) ([]repository.VersionedFileChange, error) {
+	libraryChanged := false
+	changed := make(map[string]bool, len(diff))
+	for _, change := range diff {
+		changed[change.Path] = true
+		if resources.IsJsonnetLibrary(change.Path) || resources.IsJsonnetLibrary(change.PreviousPath) {
+			libraryChanged = true
+		}
+	}
+	if !libraryChanged {
+		return diff, nil
+	}
+
+	reader, ok := repo.(repository.Reader)
+	if !ok {
+		return diff, nil
+	}
+
+	tree, err := reader.ReadTree(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range tree {
+		if entry.Blob && !changed[entry.Path] && resources.IsJsonnetFile(entry.Path) && resources.IsPathSupported(entry.Path) == nil {
+			diff = append(diff, repository.VersionedFileChange{
+				Action: repository.FileActionUpdated,
+				Path:   entry.Path,
+				Ref:    ref,
+			})
+		}
+	}
+
+	return diff, nil
+}
@@ -396,3 +396,106 @@ func TestIncrementalSync(t *testing.T) {
 		})
 	}
 }
+
+type mockVersionedReader struct {
+	*repository.MockReader
+	*repository.MockVersioned
+}
+
+func TestIncrementalSync_JsonnetLibraries(t *testing.T) {
+	repo := &mockVersionedReader{
+		MockReader:    repository.NewMockReader(t),
+		MockVersioned: repository.NewMockVersioned(t),
+	}
+	repoResources := resources.NewMockRepositoryResources(t)
+	progress := jobs.NewMockJobProgressRecorder(t)
+
+	repo.MockVersioned.On("CompareFiles", mock.Anything, "old-ref", "new-ref").Return([]repository.VersionedFileChange{
+		{Action: repository.FileActionUpdated, Path: "lib/panels.libsonnet", Ref: "new-ref"},
+		{Action: repository.FileActionUpdated, Path: "dashboards/changed.jsonnet", Ref: "new-ref"},
+	}, nil)
+	repo.MockReader.On("ReadTree", mock.Anything, "new-ref").Return([]repository.FileTreeEntry{
+		{Path: "dashboards/", Blob: false},
+		{Path: "dashboards/changed.jsonnet", Hash: "a", Blob: true},
+		{Path: "dashboards/other.jsonnet", Hash: "b", Blob: true},
+		{Path: "dashboards/plain.json", Hash: "c", Blob: true},
+		{Path: "lib/", Blob: false},
+		{Path: "lib/panels.libsonnet", Hash: "d", Blob: true},
+	}, nil)
+
+	// The library is not a resource, but both templates are rendered again
+	progress.On("SetTotal", mock.Anything, 3).Return()
+	progress.On("SetMessage", mock.Anything, "replicating versioned changes").Return()
+	progress.On("SetMessage", mock.Anything, "versioned changes replicated").Return()
+	progress.On("TooManyErrors").Return(nil)
+	repoResources.On("EnsureFolderPathExist", mock.Anything, "lib/").Return("lib", nil)
+	progress.On("Record", mock.Anything, mock.MatchedBy(func(result jobs.JobResourceResult) bool {
+		return result.Path == "lib/" && result.Action == repository.FileActionCreated
+	})).Return()
+	for _, path := range []string{"dashboards/changed.jsonnet", "dashboards/other.jsonnet"} {
+		repoResources.On("WriteResourceFromFile", mock.Anything, path, "new-ref").
+			Return("dashboard", schema.GroupVersionKind{Kind: "Dashboard", Group: "dashboard.grafana.app"}, nil)
+		progress.On("Record", mock.Anything, mock.MatchedBy(func(result jobs.JobResourceResult) bool {
+			return result.Path == path && result.Action == repository.FileActionUpdated && result.Error == nil
+		})).Return()
+	}
+
+	err := IncrementalSync(context.Background(), repo, "old-ref", "new-ref", repoResources, progress)
+	require.NoError(t, err)
+}
+
+func TestIncrementalSync_LibraryPanels(t *testing.T) {
+	repo := &mockVersionedReader{
+		MockReader:    repository.NewMockReader(t),
+		MockVersioned: repository.NewMockVersioned(t),
+	}
+	repoResources := resources.NewMockRepositoryResources(t)
+	progress := jobs.NewMockJobProgressRecorder(t)
+
+	repo.MockVersioned.On("CompareFiles", mock.Anything, "old-ref", "new-ref").Return([]repository.VersionedFileChange{
+		{Action: repository.FileActionDeleted, Path: "panels/old.yaml", PreviousRef: "old-ref"},
+		{Action: repository.FileActionCreated, Path: "dashboards/uses-panels.json", Ref: "new-ref"},
+		{Action: repository.FileActionCreated, Path: "panels/new.yaml", Ref: "new-ref"},
+		{Action: repository.FileActionUpdated, Path: "dashboards/dashboard.yaml", Ref: "new-ref"},
+	}, nil)
+	repo.MockReader.On("Read", mock.Anything, "panels/old.yaml", "old-ref").
+		Return(&repository.FileInfo{Data: []byte("apiVersion: 1\nlibraryPanels:\n  - uid: old\n")}, nil)
+	repo.MockReader.On("Read", mock.Anything, "panels/new.yaml", "new-ref").
+		Return(&repository.FileInfo{Data: []byte("apiVersion: 1\nlibraryPanels:\n  - uid: new\n")}, nil)
+	repo.MockReader.On("Read", mock.Anything, "dashboards/uses-panels.json", "new-ref").
+		Return(&repository.FileInfo{Data: []byte(`{"apiVersion": "dashboard.grafana.app/v1beta1", "kind": "Dashboard"}`)}, nil)
+	repo.MockReader.On("Read", mock.Anything, "dashboards/dashboard.yaml", "new-ref").
+		Return(&repository.FileInfo{Data: []byte("apiVersion: dashboard.grafana.app/v1beta1\nkind: Dashboard\n")}, nil)
+
+	progress.On("SetTotal", mock.Anything, 4).Return()
+	progress.On("SetMessage", mock.Anything, "replicating versioned changes").Return()
+	progress.On("SetMessage", mock.Anything, "versioned changes replicated").Return()
+	progress.On("TooManyErrors").Return(nil)
+
+	var applied []string
+	record := func(args mock.Arguments) {
+		applied = append(applied, args.Get(1).(string))
+	}
+	repoResources.On("WriteResourceFromFile", mock.Anything, "panels/new.yaml", "new-ref").Run(record).
+		Return("panels/new.yaml", resources.LibraryPanelsFileKind, nil)
+	repoResources.On("WriteResourceFromFile", mock.Anything, "dashboards/uses-panels.json", "new-ref").Run(record).
+		Return("uses-panels", schema.GroupVersionKind{Kind: "Dashboard", Group: "dashboard.grafana.app"}, nil)
+	repoResources.On("WriteResourceFromFile", mock.Anything, "dashboards/dashboard.yaml", "new-ref").Run(record).
+		Return("dashboard", schema.GroupVersionKind{Kind: "Dashboard", Group: "dashboard.grafana.app"}, nil)
+	repoResources.On("RemoveResourceFromFile", mock.Anything, "panels/old.yaml", "old-ref").Run(record).
+		Return("panels/old.yaml", resources.LibraryPanelsFileKind, nil)
+	progress.On("Record", mock.Anything, mock.MatchedBy(func(result jobs.JobResourceResult) bool {
+		return result.Error == nil
+	})).Return()
+
+	err := IncrementalSync(context.Background(), repo, "old-ref", "new-ref", repoResources, progress)
+	require.NoError(t, err)
+
+	// The library panels are applied before the dashboards and removed after them
+	require.Equal(t, []string{
+		"panels/new.yaml",
+		"dashboards/uses-panels.json",
+		"dashboards/dashboard.yaml",
+		"panels/old.yaml",
+	}, applied)
+}
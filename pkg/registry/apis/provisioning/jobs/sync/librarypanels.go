@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"context"
+	"path"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/apps/provisioning/pkg/safepath"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+)
+
+// isLibraryPanelsFile reads the file to check if it defines library panels.
+// Only the YAML and JSON files are read, the library panel files are written in one of them.
+func isLibraryPanelsFile(ctx context.Context, repo repository.Reader, filePath, ref string) bool {
+	if safepath.IsDir(filePath) {
+		return false
+	}
+	if ext := path.Ext(filePath); ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return false
+	}
+
+	info, err := repo.Read(ctx, filePath, ref)
+	if err != nil {
+		// The error is reported when the file is applied
+		return false
+	}
+	return resources.IsLibraryPanelsFile(info.Data)
+}
+
+// isLibraryPanelsItem checks if the listed resource is an applied library panel file
+func isLibraryPanelsItem(item *provisioning.ResourceListItem) bool {
+	return item != nil && item.Group == resources.LibraryPanelsFileKind.Group && item.Resource == resources.LibraryPanelsFileKind.Kind
+}
+
+// libraryPanelsFirst moves the created and updated library panel files before the other changes,
+// so the library panels exist when the dashboards referencing them are saved, and the deleted library
+// panel files after them, once the dashboards no longer use them. The applied files are known from
+// the listed resources, only the new files are read.
+func libraryPanelsFirst(ctx context.Context, repo repository.Reader, ref string, changes []ResourceFileChange) []ResourceFileChange {
+	first := make([]ResourceFileChange, 0, len(changes))
+	rest := make([]ResourceFileChange, 0, len(changes))
+	last := make([]ResourceFileChange, 0, len(changes))
+	for _, change := range changes {
+		switch {
+		case change.Action == repository.FileActionDeleted && isLibraryPanelsItem(change.Existing):
+			last = append(last, change)
+		case change.Action == repository.FileActionDeleted:
+			rest = append(rest, change)
+		case isLibraryPanelsItem(change.Existing):
+			first = append(first, change)
+		case change.Existing == nil && isLibraryPanelsFile(ctx, repo, change.Path, ref):
+			first = append(first, change)
+		default:
+			rest = append(rest, change)
+		}
+	}
+	return append(append(first, rest...), last...)
+}
+
+// withLibraryPanelsOrder applies the created and updated library panel files before the other changes,
+// and removes the deleted library panel files last, once the dashboards no longer use them
+func withLibraryPanelsOrder(ctx context.Context, repo repository.Versioned, diff []repository.VersionedFileChange) []repository.VersionedFileChange {
+	reader, ok := repo.(repository.Reader)
+	if !ok {
+		return diff
+	}
+
+	first := make([]repository.VersionedFileChange, 0, len(diff))
+	rest := make([]repository.VersionedFileChange, 0, len(diff))
+	last := make([]repository.VersionedFileChange, 0, len(diff))
+	for _, change := range diff {
+		switch change.Action {
+		case repository.FileActionCreated, repository.FileActionUpdated:
+			if isLibraryPanelsFile(ctx, reader, change.Path, change.Ref) {
+				first = append(first, change)
+				continue
+			}
+		case repository.FileActionDeleted:
+			if isLibraryPanelsFile(ctx, reader, change.Path, change.PreviousRef) {
+				last = append(last, change)
+				continue
+			}
+		}
+		rest = append(rest, change)
+	}
+
+	return append(append(first, rest...), last...)
+}
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
@@ -21,7 +22,9 @@ type IncrementalSyncFn func(ctx context.Context, repo repository.Versioned, prev
 
 //go:generate mockery --name Syncer --structname MockSyncer --inpackage --filename syncer_mock.go --with-expecter
 type Syncer interface {
-	Sync(ctx context.Context, repo repository.ReaderWriter, options provisioning.SyncJobOptions, repositoryResources resources.RepositoryResources, clients resources.ResourceClients, progress jobs.JobProgressRecorder) (string, error)
+	// Sync returns the synced ref, and its signature verification when the repository requires signed commits
+	// and new commits were verified
+	Sync(ctx context.Context, repo repository.ReaderWriter, options provisioning.SyncJobOptions, repositoryResources resources.RepositoryResources, clients resources.ResourceClients, progress jobs.JobProgressRecorder) (string, *provisioning.SignatureVerification, error)
 }
 
 type syncer struct {
@@ -38,25 +41,38 @@ func NewSyncer(compare CompareFn, fullSync FullSyncFn, incrementalSync Increment
 	}
 }
 
-func (r *syncer) Sync(ctx context.Context, repo repository.ReaderWriter, options provisioning.SyncJobOptions, repositoryResources resources.RepositoryResources, clients resources.ResourceClients, progress jobs.JobProgressRecorder) (string, error) {
+func (r *syncer) Sync(ctx context.Context, repo repository.ReaderWriter, options provisioning.SyncJobOptions, repositoryResources resources.RepositoryResources, clients resources.ResourceClients, progress jobs.JobProgressRecorder) (string, *provisioning.SignatureVerification, error) {
 	cfg := repo.Config()
 
 	var currentRef string
+	var verification *provisioning.SignatureVerification
 	versionedRepo, ok := repo.(repository.Versioned)
 	if ok && versionedRepo != nil {
 		var err error
 		currentRef, err = versionedRepo.LatestRef(ctx)
 		if err != nil {
-			return "", fmt.Errorf("get latest ref: %w", err)
+			return "", nil, fmt.Errorf("get latest ref: %w", err)
+		}
+
+		// Every commit since the last synced ref is verified, whatever the sync. When the last synced ref
+		// is gone (e.g. force push), the commits can't be verified and the sync is refused.
+		verification, err = repository.VerifyCommits(ctx, repo, cfg.Status.Sync.LastRef, currentRef)
+		if err != nil {
+			return currentRef, verification, err
 		}
 
 		if cfg.Status.Sync.LastRef != "" && options.Incremental {
 			progress.SetMessage(ctx, "incremental sync")
-			return currentRef, r.incrementalSync(ctx, versionedRepo, cfg.Status.Sync.LastRef, currentRef, repositoryResources, progress)
+			err = r.incrementalSync(ctx, versionedRepo, cfg.Status.Sync.LastRef, currentRef, repositoryResources, progress)
+			// The previous ref may be gone (e.g. force push), so we can only compare everything
+			if !errors.Is(err, repository.ErrRefNotFound) {
+				return currentRef, verification, err
+			}
+			progress.SetMessage(ctx, "previous ref not found")
 		}
 	}
 
 	progress.SetMessage(ctx, "full sync")
 
-	return currentRef, r.fullSync(ctx, repo, r.compare, clients, currentRef, repositoryResources, progress)
+	return currentRef, verification, r.fullSync(ctx, repo, r.compare, clients, currentRef, repositoryResources, progress)
 }
@@ -17,6 +17,7 @@ import (
 type mockReaderWriter struct {
 	*repository.MockRepository
 	*repository.MockVersioned
+	*repository.MockCommitVerifier
 }
 
 // FIXME: understand how the MockRepository was generated as it seems
@@ -40,13 +41,14 @@ func (m *mockReaderWriter) Move(ctx context.Context, oldPath, newPath, ref, mess
 
 func TestSyncer_Sync(t *testing.T) {
 	tests := []struct {
-		name             string
-		options          provisioning.SyncJobOptions
-		setupMocks       func(*mockReaderWriter, *resources.MockRepositoryResources, *resources.MockResourceClients, *jobs.MockJobProgressRecorder, *MockCompareFn, *MockFullSyncFn, *MockIncrementalSyncFn)
-		expectedRef      string
-		expectedError    string
-		expectedMessages []string
-		expectedFinalMsg string
+		name                 string
+		options              provisioning.SyncJobOptions
+		setupMocks           func(*mockReaderWriter, *resources.MockRepositoryResources, *resources.MockResourceClients, *jobs.MockJobProgressRecorder, *MockCompareFn, *MockFullSyncFn, *MockIncrementalSyncFn)
+		expectedRef          string
+		expectedVerification *provisioning.SignatureVerification
+		expectedError        string
+		expectedMessages     []string
+		expectedFinalMsg     string
 	}{
 		{
 			name: "successful full sync",
@@ -136,6 +138,115 @@ func TestSyncer_Sync(t *testing.T) {
 			expectedMessages: []string{"incremental sync"},
 			expectedError:    "incremental sync failed",
 		},
+		{
+			name: "incremental sync falls back to full sync when the previous ref is not found",
+			options: provisioning.SyncJobOptions{
+				Incremental: true,
+			},
+			setupMocks: func(repo *mockReaderWriter, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn, fullSyncFn *MockFullSyncFn, incrementalSyncFn *MockIncrementalSyncFn) {
+				repo.MockRepository.On("Config").Return(&provisioning.Repository{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-repo",
+					},
+					Status: provisioning.RepositoryStatus{
+						Sync: provisioning.SyncStatus{
+							LastRef: "old-ref",
+						},
+					},
+				})
+				repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+				progress.On("SetMessage", mock.Anything, "incremental sync").Return()
+				incrementalSyncFn.EXPECT().Execute(mock.Anything, mock.Anything, "old-ref", "new-ref", mock.Anything, mock.Anything).Return(fmt.Errorf("compare files error: %w", repository.ErrRefNotFound))
+				progress.On("SetMessage", mock.Anything, "previous ref not found").Return()
+				progress.On("SetMessage", mock.Anything, "full sync").Return()
+				fullSyncFn.EXPECT().Execute(mock.Anything, mock.Anything, mock.Anything, mock.Anything, "new-ref", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedRef:      "new-ref",
+			expectedMessages: []string{"incremental sync", "previous ref not found", "full sync"},
+		},
+		{
+			name: "incremental sync verifies the new commits",
+			options: provisioning.SyncJobOptions{
+				Incremental: true,
+			},
+			setupMocks: func(repo *mockReaderWriter, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn, fullSyncFn *MockFullSyncFn, incrementalSyncFn *MockIncrementalSyncFn) {
+				repo.MockRepository.On("Config").Return(signedRepoConfig("old-ref"))
+				repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+				progress.On("SetMessage", mock.Anything, "incremental sync").Return()
+				repo.MockCommitVerifier.EXPECT().VerifyCommits(mock.Anything, "old-ref", "new-ref").Return([]provisioning.SignatureVerification{
+					{Ref: "new-ref", Verified: true, Signer: "johndoe"},
+				}, nil)
+				incrementalSyncFn.EXPECT().Execute(mock.Anything, mock.Anything, "old-ref", "new-ref", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedRef:          "new-ref",
+			expectedVerification: &provisioning.SignatureVerification{Ref: "new-ref", Verified: true, Signer: "johndoe"},
+			expectedMessages:     []string{"incremental sync"},
+		},
+		{
+			name: "incremental sync refuses an untrusted commit",
+			options: provisioning.SyncJobOptions{
+				Incremental: true,
+			},
+			setupMocks: func(repo *mockReaderWriter, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn, fullSyncFn *MockFullSyncFn, incrementalSyncFn *MockIncrementalSyncFn) {
+				repo.MockRepository.On("Config").Return(signedRepoConfig("old-ref"))
+				repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+				repo.MockCommitVerifier.EXPECT().VerifyCommits(mock.Anything, "old-ref", "new-ref").Return([]provisioning.SignatureVerification{
+					{Ref: "middle-ref", Verified: false, Reason: "unsigned"},
+					{Ref: "new-ref", Verified: true, Signer: "johndoe"},
+				}, nil)
+			},
+			expectedRef:          "new-ref",
+			expectedVerification: &provisioning.SignatureVerification{Ref: "middle-ref", Verified: false, Reason: "unsigned"},
+			expectedError:        "commit middle-ref is not signed by a trusted signer: unsigned",
+		},
+		{
+			name: "sync is refused when the previous ref is not found",
+			options: provisioning.SyncJobOptions{
+				Incremental: true,
+			},
+			setupMocks: func(repo *mockReaderWriter, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn, fullSyncFn *MockFullSyncFn, incrementalSyncFn *MockIncrementalSyncFn) {
+				repo.MockRepository.On("Config").Return(signedRepoConfig("old-ref"))
+				repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+				repo.MockCommitVerifier.EXPECT().VerifyCommits(mock.Anything, "old-ref", "new-ref").Return(nil, repository.ErrRefNotFound)
+			},
+			expectedRef:   "new-ref",
+			expectedError: "the commits since the last synced ref are unknown and can not be verified: old-ref is no longer in the repository",
+		},
+		{
+			name: "full sync verifies the commits since the last synced ref",
+			options: provisioning.SyncJobOptions{
+				Incremental: false,
+			},
+			setupMocks: func(repo *mockReaderWriter, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn, fullSyncFn *MockFullSyncFn, incrementalSyncFn *MockIncrementalSyncFn) {
+				repo.MockRepository.On("Config").Return(signedRepoConfig("old-ref"))
+				repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+				repo.MockCommitVerifier.EXPECT().VerifyCommits(mock.Anything, "old-ref", "new-ref").Return([]provisioning.SignatureVerification{
+					{Ref: "middle-ref", Verified: true, Signer: "johndoe"},
+					{Ref: "new-ref", Verified: true, Signer: "johndoe"},
+				}, nil)
+				progress.On("SetMessage", mock.Anything, "full sync").Return()
+				fullSyncFn.EXPECT().Execute(mock.Anything, mock.Anything, mock.Anything, mock.Anything, "new-ref", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedRef:          "new-ref",
+			expectedVerification: &provisioning.SignatureVerification{Ref: "new-ref", Verified: true, Signer: "johndoe"},
+			expectedMessages:     []string{"full sync"},
+		},
+		{
+			name: "first sync refuses an unsigned latest commit",
+			options: provisioning.SyncJobOptions{
+				Incremental: false,
+			},
+			setupMocks: func(repo *mockReaderWriter, repoResources *resources.MockRepositoryResources, clients *resources.MockResourceClients, progress *jobs.MockJobProgressRecorder, compareFn *MockCompareFn, fullSyncFn *MockFullSyncFn, incrementalSyncFn *MockIncrementalSyncFn) {
+				repo.MockRepository.On("Config").Return(signedRepoConfig(""))
+				repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+				repo.MockCommitVerifier.EXPECT().VerifyCommits(mock.Anything, "", "new-ref").Return([]provisioning.SignatureVerification{
+					{Ref: "new-ref", Verified: false, Reason: "unsigned"},
+				}, nil)
+			},
+			expectedRef:          "new-ref",
+			expectedVerification: &provisioning.SignatureVerification{Ref: "new-ref", Verified: false, Reason: "unsigned"},
+			expectedError:        "commit new-ref is not signed by a trusted signer: unsigned",
+		},
 	}
 
 	for _, tt := range tests {
@@ -148,8 +259,9 @@ func TestSyncer_Sync(t *testing.T) {
 			incrementalSyncFn := NewMockIncrementalSyncFn(t)
 
 			repo := &mockReaderWriter{
-				MockRepository: repository.NewMockRepository(t),
-				MockVersioned:  repository.NewMockVersioned(t),
+				MockRepository:     repository.NewMockRepository(t),
+				MockVersioned:      repository.NewMockVersioned(t),
+				MockCommitVerifier: repository.NewMockCommitVerifier(t),
 			}
 
 			tt.setupMocks(repo, repoResources, clients, progress, compareFn, fullSyncFn, incrementalSyncFn)
@@ -160,7 +272,8 @@ func TestSyncer_Sync(t *testing.T) {
 				incrementalSyncFn.Execute,
 			)
 
-			ref, err := syncer.Sync(context.Background(), repo, tt.options, repoResources, clients, progress)
+			ref, verification, err := syncer.Sync(context.Background(), repo, tt.options, repoResources, clients, progress)
+			require.Equal(t, tt.expectedVerification, verification)
 			if tt.expectedError != "" {
 				require.EqualError(t, err, tt.expectedError)
 			} else {
@@ -183,3 +296,21 @@ func TestSyncer_Sync(t *testing.T) {
 		})
 	}
 }
+
+func signedRepoConfig(lastRef string) *provisioning.Repository {
+	return &provisioning.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-repo",
+		},
+		Spec: provisioning.RepositorySpec{
+			Verification: &provisioning.CommitVerificationConfig{
+				RequireSignedCommits: true,
+			},
+		},
+		Status: provisioning.RepositoryStatus{
+			Sync: provisioning.SyncStatus{
+				LastRef: lastRef,
+			},
+		},
+	}
+}
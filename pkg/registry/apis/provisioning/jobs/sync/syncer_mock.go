@@ -29,7 +29,7 @@ func (_m *MockSyncer) EXPECT() *MockSyncer_Expecter {
 }
 
 // Sync provides a mock function with given fields: ctx, repo, options, repositoryResources, clients, progress
-func (_m *MockSyncer) Sync(ctx context.Context, repo repository.ReaderWriter, options v0alpha1.SyncJobOptions, repositoryResources resources.RepositoryResources, clients resources.ResourceClients, progress jobs.JobProgressRecorder) (string, error) {
+func (_m *MockSyncer) Sync(ctx context.Context, repo repository.ReaderWriter, options v0alpha1.SyncJobOptions, repositoryResources resources.RepositoryResources, clients resources.ResourceClients, progress jobs.JobProgressRecorder) (string, *v0alpha1.SignatureVerification, error) {
 	ret := _m.Called(ctx, repo, options, repositoryResources, clients, progress)
 
 	if len(ret) == 0 {
@@ -37,8 +37,9 @@ func (_m *MockSyncer) Sync(ctx context.Context, repo repository.ReaderWriter, op
 	}
 
 	var r0 string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) (string, error)); ok {
+	var r1 *v0alpha1.SignatureVerification
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) (string, *v0alpha1.SignatureVerification, error)); ok {
 		return rf(ctx, repo, options, repositoryResources, clients, progress)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) string); ok {
@@ -47,13 +48,21 @@ func (_m *MockSyncer) Sync(ctx context.Context, repo repository.ReaderWriter, op
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) *v0alpha1.SignatureVerification); ok {
 		r1 = rf(ctx, repo, options, repositoryResources, clients, progress)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*v0alpha1.SignatureVerification)
+		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) error); ok {
+		r2 = rf(ctx, repo, options, repositoryResources, clients, progress)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // MockSyncer_Sync_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Sync'
@@ -79,12 +88,12 @@ func (_c *MockSyncer_Sync_Call) Run(run func(ctx context.Context, repo repositor
 	return _c
 }
 
-func (_c *MockSyncer_Sync_Call) Return(_a0 string, _a1 error) *MockSyncer_Sync_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockSyncer_Sync_Call) Return(_a0 string, _a1 *v0alpha1.SignatureVerification, _a2 error) *MockSyncer_Sync_Call {
+	_c.Call.Return(_a0, _a1, _a2)
 	return _c
 }
 
-func (_c *MockSyncer_Sync_Call) RunAndReturn(run func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) (string, error)) *MockSyncer_Sync_Call {
+func (_c *MockSyncer_Sync_Call) RunAndReturn(run func(context.Context, repository.ReaderWriter, v0alpha1.SyncJobOptions, resources.RepositoryResources, resources.ResourceClients, jobs.JobProgressRecorder) (string, *v0alpha1.SignatureVerification, error)) *MockSyncer_Sync_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/grafana/grafana-app-sdk/logging"
@@ -68,9 +69,11 @@ func (r *SyncWorker) Process(ctx context.Context, repo repository.Repository, jo
 	}
 
 	syncStatus := job.Status.ToSyncStatus(job.Name)
-	// Preserve last ref as we use replace operation
+	// Preserve last ref and verification as we use replace operation
 	lastRef := repo.Config().Status.Sync.LastRef
+	lastVerification := repo.Config().Status.Sync.Verification
 	syncStatus.LastRef = lastRef
+	syncStatus.Verification = lastVerification
 
 	// Update sync status at start using JSON patch
 	patchOperations := []map[string]interface{}{
@@ -99,7 +102,7 @@ func (r *SyncWorker) Process(ctx context.Context, repo repository.Repository, jo
 	progress.SetMessage(ctx, "execute sync job")
 	progress.StrictMaxErrors(20) // make it stop after 20 errors
 
-	currentRef, syncError := r.syncer.Sync(ctx, rw, *job.Spec.Pull, repositoryResources, clients, progress)
+	currentRef, commitVerification, syncError := r.syncer.Sync(ctx, rw, *job.Spec.Pull, repositoryResources, clients, progress)
 	jobStatus := progress.Complete(ctx, syncError)
 	syncStatus = jobStatus.ToSyncStatus(job.Name)
 
@@ -110,6 +113,21 @@ func (r *SyncWorker) Process(ctx context.Context, repo repository.Repository, jo
 		syncStatus.LastRef = lastRef
 	}
 
+	// Report the commit that was refused, or the verified commit that was applied
+	var untrustedErr *repository.UntrustedCommitError
+	verification := cfg.Spec.Verification
+	switch {
+	case errors.As(syncError, &untrustedErr):
+		syncStatus.Verification = &untrustedErr.Verification
+	case verification == nil || !verification.RequireSignedCommits:
+		syncStatus.Verification = nil
+	case syncStatus.State == provisioning.JobStateSuccess && commitVerification != nil:
+		syncStatus.Verification = commitVerification
+	default:
+		// No new commit was verified, or the sync failed
+		syncStatus.Verification = lastVerification
+	}
+
 	// Update final status using JSON patch
 	progress.SetMessage(ctx, "update status and stats")
 	patchOperations = []map[string]interface{}{
@@ -228,7 +228,7 @@ func TestSyncWorker_Process(t *testing.T) {
 				pr.On("StrictMaxErrors", 20).Return()
 				s.On("Sync", mock.Anything, rw, mock.MatchedBy(func(opts provisioning.SyncJobOptions) bool {
 					return true // Add specific sync options validation if needed
-				}), mockRepoResources, mock.Anything, pr).Return("new-ref", nil)
+				}), mockRepoResources, mock.Anything, pr).Return("new-ref", nil, nil)
 
 				// Final status updates
 				pr.On("Complete", mock.Anything, nil).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
@@ -282,7 +282,7 @@ func TestSyncWorker_Process(t *testing.T) {
 				syncError := errors.New("sync operation failed")
 				s.On("Sync", mock.Anything, rw, mock.MatchedBy(func(opts provisioning.SyncJobOptions) bool {
 					return true // Add specific sync options validation if needed
-				}), mockRepoResources, mock.Anything, pr).Return("", syncError)
+				}), mockRepoResources, mock.Anything, pr).Return("", nil, syncError)
 
 				// Final status updates
 				pr.On("Complete", mock.Anything, syncError).Return(provisioning.JobStatus{State: provisioning.JobStateError})
@@ -299,6 +299,118 @@ func TestSyncWorker_Process(t *testing.T) {
 			},
 			expectedError: "sync operation failed",
 		},
+		{
+			name: "sync refused an untrusted commit",
+			setupMocks: func(cf *resources.MockClientFactory, rrf *resources.MockRepositoryResourcesFactory, ds *dualwrite.MockService, rpf *MockRepositoryPatchFn, s *MockSyncer, rw *mockReaderWriter, pr *jobs.MockJobProgressRecorder) {
+				repoConfig := &provisioning.Repository{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-repo",
+						Namespace: "test-namespace",
+					},
+					Spec: provisioning.RepositorySpec{
+						Verification: &provisioning.CommitVerificationConfig{
+							RequireSignedCommits: true,
+						},
+					},
+					Status: provisioning.RepositoryStatus{
+						Sync: provisioning.SyncStatus{
+							LastRef:      "existing-ref",
+							Verification: &provisioning.SignatureVerification{Ref: "existing-ref", Verified: true},
+						},
+					},
+				}
+				rw.MockRepository.On("Config").Return(repoConfig)
+				ds.On("ReadFromUnified", mock.Anything, mock.Anything).Return(true, nil).Twice()
+
+				// Initial status update keeps the previous verification
+				pr.On("SetMessage", mock.Anything, "update sync status at start").Return()
+				rpf.On("Execute", mock.Anything, repoConfig, mock.MatchedBy(func(patch map[string]interface{}) bool {
+					syncStatus := patch["value"].(provisioning.SyncStatus)
+					return syncStatus.State == "" && syncStatus.Verification != nil && syncStatus.Verification.Ref == "existing-ref"
+				})).Return(nil).Once()
+
+				mockRepoResources := resources.NewMockRepositoryResources(t)
+				mockRepoResources.On("Stats", mock.Anything).Return(nil, nil)
+				rrf.On("Client", mock.Anything, mock.Anything).Return(mockRepoResources, nil)
+
+				mockClients := resources.NewMockResourceClients(t)
+				cf.On("Clients", mock.Anything, "test-namespace").Return(mockClients, nil)
+
+				pr.On("SetMessage", mock.Anything, "execute sync job").Return()
+				pr.On("StrictMaxErrors", 20).Return()
+				syncError := &repository.UntrustedCommitError{
+					Verification: provisioning.SignatureVerification{Ref: "new-ref", Reason: "unsigned"},
+				}
+				s.On("Sync", mock.Anything, rw, mock.Anything, mockRepoResources, mock.Anything, pr).Return("new-ref", &syncError.Verification, syncError)
+
+				pr.On("Complete", mock.Anything, syncError).Return(provisioning.JobStatus{State: provisioning.JobStateError})
+				pr.On("SetMessage", mock.Anything, "update status and stats").Return()
+
+				// Final patch reports the refused commit
+				rpf.On("Execute", mock.Anything, repoConfig, mock.MatchedBy(func(patch map[string]interface{}) bool {
+					syncStatus := patch["value"].(provisioning.SyncStatus)
+					return syncStatus.State == provisioning.JobStateError &&
+						syncStatus.LastRef == "existing-ref" &&
+						syncStatus.Verification != nil &&
+						*syncStatus.Verification == provisioning.SignatureVerification{Ref: "new-ref", Reason: "unsigned"}
+				})).Return(nil).Once()
+			},
+			expectedError: "commit new-ref is not signed by a trusted signer: unsigned",
+		},
+		{
+			name: "sync stores the verification of the synced commit",
+			setupMocks: func(cf *resources.MockClientFactory, rrf *resources.MockRepositoryResourcesFactory, ds *dualwrite.MockService, rpf *MockRepositoryPatchFn, s *MockSyncer, rw *mockReaderWriter, pr *jobs.MockJobProgressRecorder) {
+				repoConfig := &provisioning.Repository{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-repo",
+						Namespace: "test-namespace",
+					},
+					Spec: provisioning.RepositorySpec{
+						Verification: &provisioning.CommitVerificationConfig{
+							RequireSignedCommits: true,
+						},
+					},
+					Status: provisioning.RepositoryStatus{
+						Sync: provisioning.SyncStatus{
+							LastRef:      "existing-ref",
+							Verification: &provisioning.SignatureVerification{Ref: "existing-ref", Verified: true},
+						},
+					},
+				}
+				rw.MockRepository.On("Config").Return(repoConfig)
+				ds.On("ReadFromUnified", mock.Anything, mock.Anything).Return(true, nil).Twice()
+
+				// Initial status update keeps the previous verification
+				pr.On("SetMessage", mock.Anything, "update sync status at start").Return()
+				rpf.On("Execute", mock.Anything, repoConfig, mock.MatchedBy(func(patch map[string]interface{}) bool {
+					syncStatus := patch["value"].(provisioning.SyncStatus)
+					return syncStatus.State == "" && syncStatus.Verification != nil && syncStatus.Verification.Ref == "existing-ref"
+				})).Return(nil).Once()
+
+				mockRepoResources := resources.NewMockRepositoryResources(t)
+				mockRepoResources.On("Stats", mock.Anything).Return(nil, nil)
+				rrf.On("Client", mock.Anything, mock.Anything).Return(mockRepoResources, nil)
+
+				mockClients := resources.NewMockResourceClients(t)
+				cf.On("Clients", mock.Anything, "test-namespace").Return(mockClients, nil)
+
+				pr.On("SetMessage", mock.Anything, "execute sync job").Return()
+				pr.On("StrictMaxErrors", 20).Return()
+				verification := &provisioning.SignatureVerification{Ref: "new-ref", Verified: true, Signer: "johndoe"}
+				s.On("Sync", mock.Anything, rw, mock.Anything, mockRepoResources, mock.Anything, pr).Return("new-ref", verification, nil)
+
+				pr.On("Complete", mock.Anything, nil).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
+				pr.On("SetMessage", mock.Anything, "update status and stats").Return()
+
+				// Final patch reports the verification as returned by the sync
+				rpf.On("Execute", mock.Anything, repoConfig, mock.MatchedBy(func(patch map[string]interface{}) bool {
+					syncStatus := patch["value"].(provisioning.SyncStatus)
+					return syncStatus.State == provisioning.JobStateSuccess &&
+						syncStatus.LastRef == "new-ref" &&
+						syncStatus.Verification == verification
+				})).Return(nil).Once()
+			},
+		},
 		{
 			name: "stats call fails",
 			setupMocks: func(cf *resources.MockClientFactory, rrf *resources.MockRepositoryResourcesFactory, ds *dualwrite.MockService, rpf *MockRepositoryPatchFn, s *MockSyncer, rw *mockReaderWriter, pr *jobs.MockJobProgressRecorder) {
@@ -322,7 +434,7 @@ func TestSyncWorker_Process(t *testing.T) {
 				pr.On("StrictMaxErrors", 20).Return()
 				pr.On("Complete", mock.Anything, mock.Anything).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
 				rpf.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil)
+				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil, nil)
 			},
 			expectedError: "",
 		},
@@ -353,7 +465,7 @@ func TestSyncWorker_Process(t *testing.T) {
 				pr.On("SetMessage", mock.Anything, mock.Anything).Return()
 				pr.On("StrictMaxErrors", 20).Return()
 				pr.On("Complete", mock.Anything, mock.Anything).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
-				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil)
+				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil, nil)
 			},
 			expectedError: "",
 		},
@@ -413,7 +525,7 @@ func TestSyncWorker_Process(t *testing.T) {
 				pr.On("SetMessage", mock.Anything, mock.Anything).Return()
 				pr.On("StrictMaxErrors", 20).Return()
 				pr.On("Complete", mock.Anything, mock.Anything).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
-				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil)
+				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil, nil)
 			},
 			expectedError: "",
 		},
@@ -466,7 +578,7 @@ func TestSyncWorker_Process(t *testing.T) {
 				pr.On("SetMessage", mock.Anything, mock.Anything).Return()
 				pr.On("StrictMaxErrors", 20).Return()
 				pr.On("Complete", mock.Anything, mock.Anything).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
-				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil)
+				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil, nil)
 			},
 			expectedError: "",
 		},
@@ -496,7 +608,7 @@ func TestSyncWorker_Process(t *testing.T) {
 				// Sync succeeds
 				pr.On("SetMessage", mock.Anything, mock.Anything).Return()
 				pr.On("StrictMaxErrors", 20).Return()
-				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil)
+				s.On("Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("new-ref", nil, nil)
 				pr.On("Complete", mock.Anything, nil).Return(provisioning.JobStatus{State: provisioning.JobStateSuccess})
 
 				// Final status patch fails
@@ -515,8 +627,9 @@ func TestSyncWorker_Process(t *testing.T) {
 			repositoryPatchFn := NewMockRepositoryPatchFn(t)
 			syncer := NewMockSyncer(t)
 			readerWriter := &mockReaderWriter{
-				MockRepository: repository.NewMockRepository(t),
-				MockVersioned:  repository.NewMockVersioned(t),
+				MockRepository:     repository.NewMockRepository(t),
+				MockVersioned:      repository.NewMockVersioned(t),
+				MockCommitVerifier: repository.NewMockCommitVerifier(t),
 			}
 			progressRecorder := jobs.NewMockJobProgressRecorder(t)
 
@@ -46,6 +46,7 @@ import (
 	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs"
 	deletepkg "github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/delete"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/drift"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/export"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/migrate"
 	movepkg "github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs/move"
@@ -107,6 +108,9 @@ type APIBuilder struct {
 	access           authlib.AccessChecker
 	statusPatcher    *controller.RepositoryStatusPatcher
 	healthChecker    *controller.HealthChecker
+	alerting         resources.AlertingProvisioner
+	libraryPanels    resources.LibraryPanelProvisioner
+	driftMetrics     *drift.Metrics
 	// Extras provides additional functionality to the API.
 	extras []Extra
 }
@@ -127,6 +131,8 @@ func NewAPIBuilder(
 	tracer tracing.Tracer,
 	extraBuilders []ExtraBuilder,
 	jobHistoryConfig *JobHistoryConfig,
+	alerting resources.AlertingProvisioner,
+	libraryPanels resources.LibraryPanelProvisioner,
 ) *APIBuilder {
 	clients := resources.NewClientFactory(configProvider)
 	parsers := resources.NewParserFactory(clients)
@@ -140,13 +146,15 @@ func NewAPIBuilder(
 		repoFactory:         repoFactory,
 		clients:             clients,
 		parsers:             parsers,
-		repositoryResources: resources.NewRepositoryResourcesFactory(parsers, clients, resourceLister),
+		repositoryResources: resources.NewRepositoryResourcesFactory(parsers, clients, resourceLister, alerting, libraryPanels),
 		resourceLister:      resourceLister,
 		legacyMigrator:      legacyMigrator,
 		storageStatus:       storageStatus,
 		unified:             unified,
 		access:              access,
 		jobHistoryConfig:    jobHistoryConfig,
+		alerting:            alerting,
+		libraryPanels:       libraryPanels,
 	}
 
 	for _, builder := range extraBuilders {
@@ -205,6 +213,8 @@ func RegisterAPIService(
 	tracer tracing.Tracer,
 	extraBuilders []ExtraBuilder,
 	repoFactory repository.Factory,
+	alerting resources.AlertingProvisioner,
+	libraryPanels resources.LibraryPanelProvisioner,
 ) (*APIBuilder, error) {
 	if !features.IsEnabledGlobally(featuremgmt.FlagProvisioning) {
 		return nil, nil
@@ -222,7 +232,10 @@ func RegisterAPIService(
 		tracer,
 		extraBuilders,
 		createJobHistoryConfigFromSettings(cfg),
+		alerting,
+		libraryPanels,
 	)
+	builder.driftMetrics = drift.NewMetrics(reg)
 	apiregistration.RegisterAPI(builder)
 	return builder, nil
 }
@@ -371,6 +384,20 @@ func (b *APIBuilder) GetHealthChecker() *controller.HealthChecker {
 	return b.healthChecker
 }
 
+func (b *APIBuilder) GetResourceLister() resources.ResourceLister {
+	return b.resourceLister
+}
+
+// GetAlertingProvisioner returns the provisioner of the alerting files, or nil if they are not supported
+func (b *APIBuilder) GetAlertingProvisioner() resources.AlertingProvisioner {
+	return b.alerting
+}
+
+// GetLibraryPanelProvisioner returns the provisioner of the library panel files, or nil if they are not supported
+func (b *APIBuilder) GetLibraryPanelProvisioner() resources.LibraryPanelProvisioner {
+	return b.libraryPanels
+}
+
 func (b *APIBuilder) InstallSchema(scheme *runtime.Scheme) error {
 	err := provisioning.AddToScheme(scheme)
 	if err != nil {
@@ -705,8 +732,18 @@ func (b *APIBuilder) GetPostStartHooks() (map[string]genericapiserver.PostStartH
 
 			deleteWorker := deletepkg.NewWorker(syncWorker, stageIfPossible, b.repositoryResources)
 			moveWorker := movepkg.NewWorker(syncWorker, stageIfPossible, b.repositoryResources)
+			driftWorker := drift.NewDriftWorker(
+				b.resourceLister,
+				b.alerting,
+				b.libraryPanels,
+				b.parsers,
+				b.storageStatus,
+				b.statusPatcher.Patch,
+				b.driftMetrics,
+			)
 			workers := []jobs.Worker{
 				deleteWorker,
+				driftWorker,
 				exportWorker,
 				migrationWorker,
 				moveWorker,
@@ -764,6 +801,7 @@ func (b *APIBuilder) GetPostStartHooks() (map[string]genericapiserver.PostStartH
 				b.storageStatus,
 				b.GetHealthChecker(),
 				b.statusPatcher,
+				b.driftMetrics,
 			)
 			if err != nil {
 				return err
@@ -0,0 +1,66 @@
+package resources
+
+import (
+	"context"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AlertingFileKind is reported for the alerting provisioning files,
+// which define many alerting objects rather than a single resource
+var AlertingFileKind = schema.GroupVersionKind{
+	Group:   "alerting.grafana.app",
+	Version: "v1",
+	Kind:    "AlertingFile",
+}
+
+// AlertingFolder is the repository folder where the alerting configuration is exported
+const AlertingFolder = "alerting"
+
+// The top level keys of the alerting provisioning file format
+var alertingFileKeys = []string{
+	"groups", "deleteRules",
+	"contactPoints", "deleteContactPoints",
+	"policies", "resetPolicies",
+	"muteTimes", "deleteMuteTimes",
+	"templates", "deleteTemplates",
+}
+
+// AlertingProvisioner applies the alerting provisioning files stored in a repository: alert rule groups,
+// contact points, notification policy trees, mute timings and templates
+//
+//go:generate mockery --name AlertingProvisioner --structname MockAlertingProvisioner --inpackage --filename alerting_provisioner_mock.go --with-expecter
+type AlertingProvisioner interface {
+	// Validate checks the file can be applied by the repository, without changing anything
+	Validate(ctx context.Context, namespace string, repository string, path string, data []byte) error
+	// Apply creates or updates the objects defined in the file, and deletes the ones removed from the file
+	Apply(ctx context.Context, namespace string, repository string, path string, hash string, data []byte) error
+	// Remove deletes the objects applied from the file
+	Remove(ctx context.Context, namespace string, repository string, path string) error
+	// List returns the hash of the files applied from the repository, by path
+	List(ctx context.Context, namespace string, repository string) (map[string]string, error)
+	// Export returns the alerting configuration that is not provisioned yet, by file name
+	Export(ctx context.Context, namespace string) (map[string][]byte, error)
+}
+
+// IsAlertingFile checks if the file uses the alerting provisioning file format,
+// which has a numeric apiVersion and no kind
+func IsAlertingFile(data []byte) bool {
+	var value map[string]any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return false
+	}
+	if _, ok := value["kind"]; ok {
+		return false
+	}
+	if _, ok := value["apiVersion"].(int); !ok {
+		return false
+	}
+	for _, key := range alertingFileKeys {
+		if _, ok := value[key]; ok {
+			return true
+		}
+	}
+	return false
+}
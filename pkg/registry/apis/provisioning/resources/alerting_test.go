@@ -0,0 +1,61 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAlertingFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected bool
+	}{
+		{
+			name:     "alert rules",
+			data:     "apiVersion: 1\ngroups:\n  - name: my_group\n",
+			expected: true,
+		},
+		{
+			name:     "contact point deletion",
+			data:     "apiVersion: 1\ndeleteContactPoints:\n  - uid: first_uid\n",
+			expected: true,
+		},
+		{
+			name:     "json templates",
+			data:     `{"apiVersion": 1, "templates": []}`,
+			expected: true,
+		},
+		{
+			name:     "kubernetes resource",
+			data:     "apiVersion: dashboard.grafana.app/v1\nkind: Dashboard\n",
+			expected: false,
+		},
+		{
+			name:     "kind with alerting keys",
+			data:     "apiVersion: 1\nkind: Something\ngroups: []\n",
+			expected: false,
+		},
+		{
+			name:     "no alerting keys",
+			data:     "apiVersion: 1\ndatasources: []\n",
+			expected: false,
+		},
+		{
+			name:     "classic dashboard",
+			data:     `{"title": "hello", "panels": []}`,
+			expected: false,
+		},
+		{
+			name:     "invalid yaml",
+			data:     "apiVersion: [",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, IsAlertingFile([]byte(tt.data)))
+		})
+	}
+}
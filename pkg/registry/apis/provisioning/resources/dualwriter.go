@@ -230,6 +230,13 @@ func (r *DualReadWriter) createOrUpdate(ctx context.Context, create bool, opts D
 		return nil, fmt.Errorf("errors while parsing file [%v]", parsed.Errors)
 	}
 
+	// The changes on a branch are checked when the pull request is evaluated
+	if opts.Ref == "" {
+		if err := parsed.CheckPolicies(r.repo.Config().Spec.Policies); err != nil {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+	}
+
 	// Verify that we can create (or update) the referenced resource
 	verb := utils.VerbUpdate
 	if parsed.Action == provisioning.ResourceActionCreate {
@@ -422,6 +429,12 @@ func (r *DualReadWriter) moveFile(ctx context.Context, opts DualWriteOptions) (*
 		return nil, fmt.Errorf("errors while parsing moved file [%v]", newParsed.Errors)
 	}
 
+	if opts.Ref == "" {
+		if err := newParsed.CheckPolicies(r.repo.Config().Spec.Policies); err != nil {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+	}
+
 	// Authorize create on the new path
 	verb := utils.VerbCreate
 	if newParsed.Action == provisioning.ResourceActionUpdate {
@@ -33,9 +33,13 @@ func IsPathSupported(filePath string) error {
 
 	// Only check file extension if it's not a folder path
 	if !safepath.IsDir(filePath) {
-		if ext := path.Ext(filePath); ext != ".yml" && ext != ".yaml" && ext != ".json" {
+		if ext := path.Ext(filePath); ext != ".yml" && ext != ".yaml" && ext != ".json" && ext != JsonnetExt {
 			return ErrUnsupportedFileExtension
 		}
+
+		if IsJsonnetLibrary(filePath) {
+			return ErrJsonnetLibrary
+		}
 	}
 
 	return nil
@@ -33,6 +33,20 @@ func TestIsPathSupported(t *testing.T) {
 			name: "valid directory path",
 			path: "dashboards/folder1/",
 		},
+		{
+			name: "valid jsonnet template",
+			path: "dashboards/my-dashboard.jsonnet",
+		},
+		{
+			name:        "jsonnet library",
+			path:        "lib/panels.libsonnet",
+			expectedErr: ErrUnsupportedFileExtension,
+		},
+		{
+			name:        "vendored jsonnet file",
+			path:        "vendor/github.com/grafana/grafonnet/main.jsonnet",
+			expectedErr: ErrJsonnetLibrary,
+		},
 		{
 			name:        "unsupported file extension",
 			path:        "dashboards/my-dashboard.txt",
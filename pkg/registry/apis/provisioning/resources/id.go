@@ -114,6 +114,12 @@ func ParseFolder(dirPath, repositoryName string) Folder {
 	}
 }
 
+// PreviewName returns the name of the copy of a resource in a preview environment.
+// The original name is kept readable, with a hash suffix so the copy never replaces the original resource.
+func PreviewName(name, previewID string) string {
+	return appendHashSuffix(name, previewID)(sanitiseKubeName(name))
+}
+
 func RootFolder(repository *provisioning.Repository) string {
 	if repository.Spec.Sync.Target == provisioning.SyncTargetTypeFolder {
 		return repository.Name // a folder with the same identifier as the repository
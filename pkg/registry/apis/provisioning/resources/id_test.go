@@ -93,3 +93,12 @@ func TestParseFolderID(t *testing.T) {
 		})
 	}
 }
+
+func TestPreviewName(t *testing.T) {
+	name := PreviewName("my-dashboard", "repo-pr-1")
+	assert.True(t, strings.HasPrefix(name, "my-dashboard-"), "the original name is kept")
+	assert.LessOrEqual(t, len(name), 40)
+	assert.Equal(t, name, PreviewName("my-dashboard", "repo-pr-1"), "the name is deterministic")
+	assert.NotEqual(t, name, PreviewName("my-dashboard", "repo-pr-2"), "each preview has its own copy")
+	assert.LessOrEqual(t, len(PreviewName(strings.Repeat("a", 40), "repo-pr-1")), 40)
+}
@@ -0,0 +1,344 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	// Git still uses sha1 for the most part, the checksum uses the same format as the file hash
+	//nolint:gosec
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/google/go-jsonnet/formatter"
+	"github.com/google/go-jsonnet/toolutils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/apps/provisioning/pkg/safepath"
+)
+
+// Jsonnet files are evaluated and the output is synced like any other resource file.
+// Imports are resolved relative to the importing file, then in the library paths.
+const (
+	JsonnetExt        = ".jsonnet"
+	JsonnetLibraryExt = ".libsonnet"
+)
+
+// JsonnetLibraryPaths are the folders (in the repository root) searched for imports.
+// This is where jsonnet-bundler vendors the dependencies (e.g. grafonnet).
+var JsonnetLibraryPaths = []string{"vendor/", "lib/"}
+
+// Limit the evaluation, so a broken template can not take down the sync.
+// The VM can not be interrupted, so every function of the templates checks that the
+// evaluation was not abandoned: a template that never ends fails at its next call and
+// releases its worker. The workers bound the evaluations running at the same time.
+// Tail calls (tailstrict) do not count in the jsonnet stack but grow the Go stack,
+// which is checked every few calls so a template can not crash the server.
+const (
+	jsonnetMaxStack    = 500
+	jsonnetMaxGoFrames = 50_000
+	jsonnetTimeout     = 10 * time.Second
+	jsonnetMaxWorkers  = 4
+)
+
+var jsonnetWorkers = make(chan struct{}, jsonnetMaxWorkers)
+
+// jsonnetCheckFunction is the native function called by every function of the templates
+const jsonnetCheckFunction = "grafanaCheckEvaluation"
+
+var ErrJsonnetLibrary = errors.New("jsonnet libraries are not resources")
+
+// IsJsonnetFile checks if the file is a template that should be evaluated
+func IsJsonnetFile(filePath string) bool {
+	return path.Ext(filePath) == JsonnetExt
+}
+
+// IsJsonnetLibrary checks if the file can only be imported by other templates
+func IsJsonnetLibrary(filePath string) bool {
+	if path.Ext(filePath) == JsonnetLibraryExt {
+		return true
+	}
+	if !IsJsonnetFile(filePath) {
+		return false
+	}
+	for _, lib := range JsonnetLibraryPaths {
+		if safepath.InDir(filePath, lib) {
+			return true
+		}
+	}
+	return false
+}
+
+// JsonnetLibrariesHash returns a hash of every library in the tree, or an empty string when there are none
+func JsonnetLibrariesHash(tree []repository.FileTreeEntry) string {
+	libraries := make([]string, 0)
+	for _, entry := range tree {
+		if entry.Blob && IsJsonnetLibrary(entry.Path) {
+			libraries = append(libraries, entry.Path+"\x00"+entry.Hash)
+		}
+	}
+	if len(libraries) == 0 {
+		return ""
+	}
+	sort.Strings(libraries)
+
+	//nolint:gosec
+	hasher := sha1.New()
+	for _, lib := range libraries {
+		_, _ = hasher.Write([]byte(lib + "\n"))
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// JsonnetChecksum combines the template hash with the libraries hash,
+// so the resources are updated when a library changes, even if the template did not.
+// This is saved as the source checksum of the rendered resource.
+func JsonnetChecksum(fileHash, librariesHash string) string {
+	if librariesHash == "" {
+		return fileHash
+	}
+
+	//nolint:gosec
+	sum := sha1.Sum([]byte(fileHash + "\n" + librariesHash))
+	return hex.EncodeToString(sum[:])
+}
+
+// EvaluateJsonnet renders the template, reading the imports from the same repository ref.
+// It fails when the context is done or the evaluation takes longer than the timeout.
+func EvaluateJsonnet(ctx context.Context, repo repository.Reader, info *repository.FileInfo) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, jsonnetTimeout)
+	defer cancel()
+
+	select {
+	case jsonnetWorkers <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for a jsonnet worker: %w", ctx.Err())
+	}
+
+	// The template itself is the first import, so the relative imports are resolved from its path
+	contents := jsonnet.MakeContentsRaw(instrumentJsonnet(info.Path, info.Data))
+	vm := jsonnet.MakeVM()
+	vm.MaxStack = jsonnetMaxStack
+	calls := 0
+	vm.NativeFunction(&jsonnet.NativeFunction{
+		Name:   jsonnetCheckFunction,
+		Params: ast.Identifiers{},
+		Func: func([]any) (any, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("evaluation stopped: %w", err)
+			}
+			calls++
+			if calls%64 == 0 && runtime.Callers(jsonnetMaxGoFrames, make([]uintptr, 1)) > 0 {
+				return nil, errors.New("max stack frames exceeded (tail calls)")
+			}
+			return true, nil
+		},
+	})
+	vm.Importer(&jsonnetImporter{
+		ctx:   ctx,
+		repo:  repo,
+		ref:   info.Ref,
+		cache: map[string]*jsonnet.Contents{info.Path: &contents},
+	})
+
+	type result struct {
+		out string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-jsonnetWorkers }()
+		out, err := vm.EvaluateFile(info.Path)
+		done <- result{out: out, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return []byte(res.out), nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("evaluation stopped: %w", ctx.Err())
+	}
+}
+
+// instrumentJsonnet adds the evaluation check to the body of every function.
+// The check is added on the same line as the body, so the errors keep their line numbers.
+// Files that can not be parsed are not changed, the VM reports the error.
+func instrumentJsonnet(filePath string, data []byte) []byte {
+	node, _, err := formatter.SnippetToRawAST(filePath, string(data))
+	if err != nil {
+		return data
+	}
+
+	bodies := make([]ast.Location, 0)
+	var walk func(node ast.Node)
+	walk = func(node ast.Node) {
+		switch n := node.(type) {
+		case nil:
+			return
+		case *ast.Function:
+			bodies = append(bodies, n.Body.Loc().Begin)
+		case *ast.Local:
+			// The body of a local function is both in Fun and Body
+			for _, bind := range n.Binds {
+				if bind.Fun != nil {
+					walk(bind.Fun)
+				} else {
+					walk(bind.Body)
+				}
+			}
+			walk(n.Body)
+			return
+		case *ast.Index:
+			// The target of a field access (a.b) is not a child of the node before desugaring
+			if n.Id != nil {
+				walk(n.Target)
+			}
+		}
+		for _, child := range toolutils.Children(node) {
+			walk(child)
+		}
+	}
+	walk(node)
+	if len(bodies) == 0 {
+		return data
+	}
+
+	lineStarts := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	offsets := make([]int, 0, len(bodies))
+	for _, loc := range bodies {
+		if loc.IsSet() && loc.Line <= len(lineStarts) {
+			offsets = append(offsets, lineStarts[loc.Line-1]+loc.Column-1)
+		}
+	}
+	sort.Ints(offsets)
+	offsets = slices.Compact(offsets)
+
+	check := fmt.Sprintf("assert std.native(%q)(); ", jsonnetCheckFunction)
+	out := make([]byte, 0, len(data)+len(offsets)*len(check))
+	last := 0
+	for _, offset := range offsets {
+		if offset > len(data) {
+			continue
+		}
+		out = append(out, data[last:offset]...)
+		out = append(out, check...)
+		last = offset
+	}
+	return append(out, data[last:]...)
+}
+
+// SplitJsonnetOutput returns each resource of the rendered template.
+// A template renders a single resource, or an array of resources.
+func SplitJsonnetOutput(rendered []byte) ([][]byte, error) {
+	trimmed := bytes.TrimSpace(rendered)
+	if !bytes.HasPrefix(trimmed, []byte("[")) {
+		return [][]byte{rendered}, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, fmt.Errorf("read the rendered resources: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("the template renders no resources")
+	}
+
+	resources := make([][]byte, 0, len(items))
+	for _, item := range items {
+		resources = append(resources, item)
+	}
+	return resources, nil
+}
+
+// jsonnetImporter reads the imported files from the repository.
+// Imports can never leave the repository.
+type jsonnetImporter struct {
+	ctx  context.Context
+	repo repository.Reader
+	ref  string
+
+	// The importer must return the same value for the same path
+	cache map[string]*jsonnet.Contents
+}
+
+func (i *jsonnetImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	// Stop the abandoned evaluations at the next import
+	if err := i.ctx.Err(); err != nil {
+		return jsonnet.Contents{}, "", fmt.Errorf("import %q: %w", importedPath, err)
+	}
+	if path.IsAbs(importedPath) {
+		return jsonnet.Contents{}, "", fmt.Errorf("import %q: only relative imports are supported", importedPath)
+	}
+
+	candidates := make([]string, 0, len(JsonnetLibraryPaths)+1)
+	candidates = append(candidates, safepath.Join(safepath.Dir(importedFrom), importedPath))
+	for _, lib := range JsonnetLibraryPaths {
+		candidates = append(candidates, safepath.Join(lib, importedPath))
+	}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, "../") || safepath.IsSafe(candidate) != nil {
+			return jsonnet.Contents{}, "", fmt.Errorf("import %q: the path is outside the repository", importedPath)
+		}
+
+		contents, err := i.read(candidate)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return jsonnet.Contents{}, "", fmt.Errorf("import %q: %w", importedPath, err)
+		}
+		return *contents, candidate, nil
+	}
+
+	return jsonnet.Contents{}, "", fmt.Errorf("import %q: not found in the repository or the library paths (%s)",
+		importedPath, strings.Join(JsonnetLibraryPaths, ", "))
+}
+
+func (i *jsonnetImporter) read(filePath string) (*jsonnet.Contents, error) {
+	if contents, ok := i.cache[filePath]; ok {
+		if contents == nil {
+			return nil, repository.ErrFileNotFound
+		}
+		return contents, nil
+	}
+	info, err := i.repo.Read(i.ctx, filePath, i.ref)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			i.cache[filePath] = nil
+		}
+		return nil, err
+	}
+	// Folders are not files
+	if info.Data == nil {
+		i.cache[filePath] = nil
+		return nil, repository.ErrFileNotFound
+	}
+
+	// Imported templates are checked too, importstr of a template reads the checked code
+	data := info.Data
+	if path.Ext(filePath) == JsonnetExt || path.Ext(filePath) == JsonnetLibraryExt {
+		data = instrumentJsonnet(filePath, data)
+	}
+	contents := jsonnet.MakeContentsRaw(data)
+	i.cache[filePath] = &contents
+	return &contents, nil
+}
@@ -0,0 +1,182 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+)
+
+func TestIsJsonnetLibrary(t *testing.T) {
+	require.True(t, IsJsonnetLibrary("panels.libsonnet"))
+	require.True(t, IsJsonnetLibrary("dashboards/panels.libsonnet"))
+	require.True(t, IsJsonnetLibrary("vendor/github.com/grafana/grafonnet/main.jsonnet"))
+	require.True(t, IsJsonnetLibrary("lib/common.jsonnet"))
+	require.False(t, IsJsonnetLibrary("dashboards/vendor/test.jsonnet"))
+	require.False(t, IsJsonnetLibrary("dashboards/test.jsonnet"))
+	require.False(t, IsJsonnetLibrary("vendor/dashboard.json"))
+	require.False(t, IsJsonnetLibrary(""))
+}
+
+func TestJsonnetChecksum(t *testing.T) {
+	require.Empty(t, JsonnetLibrariesHash([]repository.FileTreeEntry{
+		{Path: "dashboards/", Blob: false},
+		{Path: "dashboards/test.jsonnet", Hash: "abc", Blob: true},
+	}))
+	require.Equal(t, "abc", JsonnetChecksum("abc", ""))
+
+	libraries := JsonnetLibrariesHash([]repository.FileTreeEntry{
+		{Path: "lib/a.libsonnet", Hash: "1", Blob: true},
+		{Path: "vendor/b.jsonnet", Hash: "2", Blob: true},
+	})
+	require.NotEmpty(t, libraries)
+	require.NotEqual(t, "abc", JsonnetChecksum("abc", libraries))
+
+	// The order in the tree does not matter, the content does
+	require.Equal(t, libraries, JsonnetLibrariesHash([]repository.FileTreeEntry{
+		{Path: "vendor/b.jsonnet", Hash: "2", Blob: true},
+		{Path: "lib/a.libsonnet", Hash: "1", Blob: true},
+	}))
+	require.NotEqual(t, libraries, JsonnetLibrariesHash([]repository.FileTreeEntry{
+		{Path: "lib/a.libsonnet", Hash: "changed", Blob: true},
+		{Path: "vendor/b.jsonnet", Hash: "2", Blob: true},
+	}))
+}
+
+func TestEvaluateJsonnet(t *testing.T) {
+	t.Run("imports relative files and vendored libraries", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		reader.EXPECT().Read(mock.Anything, "dashboards/common.libsonnet", "ref").
+			Return(nil, repository.ErrFileNotFound)
+		reader.EXPECT().Read(mock.Anything, "vendor/common.libsonnet", "ref").
+			Return(&repository.FileInfo{Data: []byte(`{ title: "vendored" }`)}, nil)
+		reader.EXPECT().Read(mock.Anything, "shared/tags.json", "ref").
+			Return(&repository.FileInfo{Data: []byte(`["a", "b"]`)}, nil)
+
+		out, err := EvaluateJsonnet(context.Background(), reader, &repository.FileInfo{
+			Path: "dashboards/test.jsonnet",
+			Ref:  "ref",
+			Data: []byte(`local common = import 'common.libsonnet';
+local common2 = import 'common.libsonnet';
+{ title: common.title, same: common2.title, tags: import '../shared/tags.json' }`),
+		})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"title":"vendored","same":"vendored","tags":["a","b"]}`, string(out))
+	})
+
+	t.Run("imports can not leave the repository", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		_, err := EvaluateJsonnet(context.Background(), reader, &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`import '../../etc/passwd'`),
+		})
+		require.ErrorContains(t, err, "the path is outside the repository")
+
+		_, err = EvaluateJsonnet(context.Background(), reader, &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`import '/etc/passwd'`),
+		})
+		require.ErrorContains(t, err, "only relative imports are supported")
+	})
+
+	t.Run("missing import", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		reader.EXPECT().Read(mock.Anything, mock.Anything, "").Return(nil, repository.ErrFileNotFound)
+
+		_, err := EvaluateJsonnet(context.Background(), reader, &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`import 'missing.libsonnet'`),
+		})
+		require.ErrorContains(t, err, `import "missing.libsonnet": not found in the repository or the library paths (vendor/, lib/)`)
+	})
+
+	t.Run("evaluation is stopped with the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := EvaluateJsonnet(ctx, repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`import 'common.libsonnet'`),
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("infinite recursion is stopped", func(t *testing.T) {
+		_, err := EvaluateJsonnet(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`local f(x) = f(x + 1); f(0)`),
+		})
+		require.ErrorContains(t, err, "max stack frames exceeded")
+	})
+
+	t.Run("endless evaluation releases its worker", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		reader.EXPECT().Read(mock.Anything, "loop.libsonnet", "").
+			Return(&repository.FileInfo{Data: []byte(`{ loop(x): self.loop(x + 1) tailstrict }`)}, nil)
+
+		templates := []string{
+			// Tail calls do not grow the stack
+			`local f(x) = f(x + 1) tailstrict; f(0)`,
+			`{ loop(x):: if x >= 0 then self.loop(x + 1) tailstrict else x }.loop(0)`,
+			`(import 'loop.libsonnet').loop(0)`,
+		}
+		for _, template := range templates {
+			// More evaluations than workers, each one must release its worker
+			for range jsonnetMaxWorkers + 1 {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				_, err := EvaluateJsonnet(ctx, reader, &repository.FileInfo{
+					Path: "test.jsonnet",
+					Data: []byte(template),
+				})
+				cancel()
+				require.ErrorIs(t, err, context.DeadlineExceeded, template)
+			}
+		}
+		require.Eventually(t, func() bool { return len(jsonnetWorkers) == 0 }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("tail calls are limited", func(t *testing.T) {
+		_, err := EvaluateJsonnet(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`local f(x) = f(x + 1) tailstrict; f(0)`),
+		})
+		require.ErrorContains(t, err, "max stack frames exceeded (tail calls)")
+
+		// Deep tail calls within the limit still work
+		out, err := EvaluateJsonnet(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`local f(x) = if x == 1000 then x else f(x + 1) tailstrict; f(0)`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "1000", strings.TrimSpace(string(out)))
+	})
+
+	t.Run("errors keep the line numbers", func(t *testing.T) {
+		_, err := EvaluateJsonnet(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte("local f(x) =\n  x +\n  error 'broken';\nf(1)"),
+		})
+		require.ErrorContains(t, err, "test.jsonnet:3:3")
+	})
+}
+
+func TestSplitJsonnetOutput(t *testing.T) {
+	single, err := SplitJsonnetOutput([]byte(`{"kind":"Dashboard"}`))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte(`{"kind":"Dashboard"}`)}, single)
+
+	many, err := SplitJsonnetOutput([]byte(`
+[{"kind":"Dashboard"}, {"kind":"Folder"}]`))
+	require.NoError(t, err)
+	require.Len(t, many, 2)
+	require.JSONEq(t, `{"kind":"Dashboard"}`, string(many[0]))
+	require.JSONEq(t, `{"kind":"Folder"}`, string(many[1]))
+
+	_, err = SplitJsonnetOutput([]byte(`[]`))
+	require.EqualError(t, err, "the template renders no resources")
+}
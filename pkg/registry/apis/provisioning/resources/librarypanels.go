@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"context"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// LibraryPanelsFileKind is reported for the library panel provisioning files,
+// which define many library panels rather than a single resource
+var LibraryPanelsFileKind = schema.GroupVersionKind{
+	Group:   "dashboard.grafana.app",
+	Version: "v0alpha1",
+	Kind:    "LibraryPanelsFile",
+}
+
+// The top level keys of the library panel provisioning file format
+var libraryPanelsFileKeys = []string{"libraryPanels", "deleteLibraryPanels"}
+
+// LibraryPanelProvisioner applies the library panel provisioning files stored in a repository.
+// The library panels are saved in the folder of the file, and recorded as managed by the file.
+//
+//go:generate mockery --name LibraryPanelProvisioner --structname MockLibraryPanelProvisioner --inpackage --filename librarypanel_provisioner_mock.go --with-expecter
+type LibraryPanelProvisioner interface {
+	// Validate checks the file can be applied by the repository, without changing anything
+	Validate(ctx context.Context, namespace string, repository string, path string, data []byte) error
+	// Apply creates or updates the library panels defined in the file in the given folder,
+	// and deletes the ones removed from the file
+	Apply(ctx context.Context, namespace string, repository string, folder string, path string, hash string, data []byte) error
+	// Remove deletes the library panels applied from the file
+	Remove(ctx context.Context, namespace string, repository string, path string) error
+	// List returns the hash of the files applied from the repository, by path
+	List(ctx context.Context, namespace string, repository string) (map[string]string, error)
+}
+
+// IsLibraryPanelsFile checks if the file uses the library panel provisioning file format,
+// which has a numeric apiVersion and no kind
+func IsLibraryPanelsFile(data []byte) bool {
+	var value map[string]any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return false
+	}
+	if _, ok := value["kind"]; ok {
+		return false
+	}
+	if _, ok := value["apiVersion"].(int); !ok {
+		return false
+	}
+	for _, key := range libraryPanelsFileKeys {
+		if _, ok := value[key]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLibraryPanelsFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected bool
+	}{
+		{
+			name:     "library panels",
+			data:     "apiVersion: 1\nlibraryPanels:\n  - uid: cpu-usage\n",
+			expected: true,
+		},
+		{
+			name:     "library panel deletion",
+			data:     "apiVersion: 1\ndeleteLibraryPanels:\n  - uid: cpu-usage\n",
+			expected: true,
+		},
+		{
+			name:     "json library panels",
+			data:     `{"apiVersion": 1, "libraryPanels": []}`,
+			expected: true,
+		},
+		{
+			name:     "kubernetes resource",
+			data:     "apiVersion: dashboard.grafana.app/v1\nkind: Dashboard\n",
+			expected: false,
+		},
+		{
+			name:     "kind with library panel keys",
+			data:     "apiVersion: 1\nkind: Something\nlibraryPanels: []\n",
+			expected: false,
+		},
+		{
+			name:     "alerting file",
+			data:     "apiVersion: 1\ngroups:\n  - name: my_group\n",
+			expected: false,
+		},
+		{
+			name:     "invalid yaml",
+			data:     "apiVersion: [",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, IsLibraryPanelsFile([]byte(tt.data)))
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -37,6 +38,8 @@ type ParserFactory interface {
 //go:generate mockery --name Parser --structname MockParser --inpackage --filename parser_mock.go --with-expecter
 type Parser interface {
 	Parse(ctx context.Context, info *repository.FileInfo) (parsed *ParsedResource, err error)
+	// ParseAll also reads the templates rendering many resources
+	ParseAll(ctx context.Context, info *repository.FileInfo) ([]*ParsedResource, error)
 }
 
 type parserFactory struct {
@@ -66,6 +69,7 @@ func (f *parserFactory) GetParser(ctx context.Context, repo repository.Reader) (
 		urls:    urls,
 		clients: clients,
 		config:  config,
+		reader:  repo,
 	}, nil
 }
 
@@ -80,6 +84,13 @@ type parser struct {
 
 	// ResourceClients give access to k8s apis
 	clients ResourceClients
+
+	// Used to read the jsonnet imports
+	reader repository.Reader
+
+	// The jsonnet checksums depend on all the libraries in the tree (by ref)
+	librariesMutex sync.Mutex
+	libraries      map[string]string
 }
 
 type ParsedResource struct {
@@ -125,21 +136,71 @@ type ParsedResource struct {
 }
 
 func (r *parser) Parse(ctx context.Context, info *repository.FileInfo) (parsed *ParsedResource, err error) {
-	logger := logging.FromContext(ctx).With("path", info.Path)
-	parsed = &ParsedResource{
-		Info: info,
-		Repo: r.repo,
+	if !IsJsonnetFile(info.Path) {
+		if err := IsPathSupported(info.Path); err != nil {
+			return nil, err
+		}
+		return r.parse(ctx, info, info, info.Hash)
 	}
 
+	all, err := r.ParseAll(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if len(all) != 1 {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("the template renders %d resources, only the sync can read it", len(all)))
+	}
+	return all[0], nil
+}
+
+func (r *parser) ParseAll(ctx context.Context, info *repository.FileInfo) ([]*ParsedResource, error) {
 	if err := IsPathSupported(info.Path); err != nil {
 		return nil, err
 	}
+	if !IsJsonnetFile(info.Path) {
+		parsed, err := r.parse(ctx, info, info, info.Hash)
+		if err != nil {
+			return nil, err
+		}
+		return []*ParsedResource{parsed}, nil
+	}
+
+	// Templates are evaluated, and each rendered resource is read like any other file
+	rendered, checksum, err := r.evaluateJsonnet(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := SplitJsonnetOutput(rendered.Data)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("evaluate jsonnet: %s", err))
+	}
+
+	all := make([]*ParsedResource, 0, len(outputs))
+	for _, output := range outputs {
+		content := *rendered
+		content.Data = output
+		parsed, err := r.parse(ctx, info, &content, checksum)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, parsed)
+	}
+	return all, nil
+}
+
+// parse reads the resource from the content, which is the rendered template or the file itself
+func (r *parser) parse(ctx context.Context, info *repository.FileInfo, content *repository.FileInfo, checksum string) (parsed *ParsedResource, err error) {
+	logger := logging.FromContext(ctx).With("path", info.Path)
+	parsed = &ParsedResource{
+		Info: info,
+		Repo: r.repo,
+	}
 
 	var gvk *schema.GroupVersionKind
-	parsed.Obj, gvk, err = DecodeYAMLObject(bytes.NewBuffer(info.Data))
+	parsed.Obj, gvk, err = DecodeYAMLObject(bytes.NewBuffer(content.Data))
 	if err != nil || gvk == nil {
 		logger.Debug("failed to find GVK of the input data, trying fallback loader", "error", err)
-		parsed.Obj, gvk, parsed.Classic, err = ReadClassicResource(ctx, info)
+		parsed.Obj, gvk, parsed.Classic, err = ReadClassicResource(ctx, content)
 		if err != nil || gvk == nil {
 			return nil, apierrors.NewBadRequest("unable to read file as a resource")
 		}
@@ -179,7 +240,7 @@ func (r *parser) Parse(ctx context.Context, info *repository.FileInfo) (parsed *
 	})
 	parsed.Meta.SetSourceProperties(utils.SourceProperties{
 		Path:     info.Path, // joinPathWithRef(info.Path, info.Ref),
-		Checksum: info.Hash,
+		Checksum: checksum,
 	})
 
 	if obj.GetName() == "" {
@@ -216,6 +277,47 @@ func (r *parser) Parse(ctx context.Context, info *repository.FileInfo) (parsed *
 	return parsed, nil
 }
 
+// evaluateJsonnet renders the template, and returns the rendered file with the checksum for the resource
+func (r *parser) evaluateJsonnet(ctx context.Context, info *repository.FileInfo) (*repository.FileInfo, string, error) {
+	if r.reader == nil {
+		return nil, "", fmt.Errorf("no repository configured to evaluate templates")
+	}
+
+	rendered, err := EvaluateJsonnet(ctx, r.reader, info)
+	if err != nil {
+		return nil, "", apierrors.NewBadRequest(fmt.Sprintf("evaluate jsonnet: %s", err))
+	}
+
+	libraries, err := r.librariesHash(ctx, info.Ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("read tree: %w", err)
+	}
+
+	content := *info
+	content.Data = rendered
+	return &content, JsonnetChecksum(info.Hash, libraries), nil
+}
+
+func (r *parser) librariesHash(ctx context.Context, ref string) (string, error) {
+	r.librariesMutex.Lock()
+	defer r.librariesMutex.Unlock()
+
+	if hash, ok := r.libraries[ref]; ok {
+		return hash, nil
+	}
+
+	tree, err := r.reader.ReadTree(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	if r.libraries == nil {
+		r.libraries = make(map[string]string)
+	}
+	r.libraries[ref] = JsonnetLibrariesHash(tree)
+
+	return r.libraries[ref], nil
+}
+
 func (f *ParsedResource) DryRun(ctx context.Context) error {
 	if f.DryRunResponse != nil {
 		return nil // this already ran (and helpful for testing)
@@ -405,6 +507,10 @@ func (f *ParsedResource) ToSaveBytes() ([]byte, error) {
 	case ".yaml", ".yml":
 		return yaml.Marshal(obj)
 
+	// The output of a template can not be saved back to the template
+	case JsonnetExt:
+		return nil, apierrors.NewBadRequest("jsonnet files are generated from a template, edit the template in the repository")
+
 	default:
 		return nil, fmt.Errorf("unexpected format")
 	}
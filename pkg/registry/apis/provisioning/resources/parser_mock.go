@@ -81,6 +81,65 @@ func (_c *MockParser_Parse_Call) RunAndReturn(run func(context.Context, *reposit
 	return _c
 }
 
+// ParseAll provides a mock function with given fields: ctx, info
+func (_m *MockParser) ParseAll(ctx context.Context, info *repository.FileInfo) ([]*ParsedResource, error) {
+	ret := _m.Called(ctx, info)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseAll")
+	}
+
+	var r0 []*ParsedResource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.FileInfo) ([]*ParsedResource, error)); ok {
+		return rf(ctx, info)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.FileInfo) []*ParsedResource); ok {
+		r0 = rf(ctx, info)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ParsedResource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *repository.FileInfo) error); ok {
+		r1 = rf(ctx, info)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockParser_ParseAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ParseAll'
+type MockParser_ParseAll_Call struct {
+	*mock.Call
+}
+
+// ParseAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - info *repository.FileInfo
+func (_e *MockParser_Expecter) ParseAll(ctx interface{}, info interface{}) *MockParser_ParseAll_Call {
+	return &MockParser_ParseAll_Call{Call: _e.mock.On("ParseAll", ctx, info)}
+}
+
+func (_c *MockParser_ParseAll_Call) Run(run func(ctx context.Context, info *repository.FileInfo)) *MockParser_ParseAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*repository.FileInfo))
+	})
+	return _c
+}
+
+func (_c *MockParser_ParseAll_Call) Return(_a0 []*ParsedResource, _a1 error) *MockParser_ParseAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockParser_ParseAll_Call) RunAndReturn(run func(context.Context, *repository.FileInfo) ([]*ParsedResource, error)) *MockParser_ParseAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockParser creates a new instance of MockParser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockParser(t interface {
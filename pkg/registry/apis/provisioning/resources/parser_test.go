@@ -5,7 +5,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	dashboardV0 "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
 	dashboardV1 "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v1beta1"
@@ -105,6 +108,77 @@ spec:
 		require.Equal(t, "v0alpha1", dash.GVR.Version)
 	})
 
+	t.Run("jsonnet template", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		reader.EXPECT().Read(mock.Anything, "dashboards/panels.libsonnet", "main").Return(&repository.FileInfo{
+			Data: []byte(`{ stat(title):: { type: "stat", title: title } }`),
+		}, nil)
+		reader.EXPECT().ReadTree(mock.Anything, "main").Return([]repository.FileTreeEntry{
+			{Path: "dashboards/", Blob: false},
+			{Path: "dashboards/panels.libsonnet", Hash: "lib", Blob: true},
+			{Path: "dashboards/test.jsonnet", Hash: "abc", Blob: true},
+		}, nil).Once()
+		parser.reader = reader
+
+		dash, err := parser.Parse(context.Background(), &repository.FileInfo{
+			Path: "dashboards/test.jsonnet",
+			Ref:  "main",
+			Hash: "abc",
+			Data: []byte(`local panels = import 'panels.libsonnet';
+{ uid: "from-jsonnet", schemaVersion: 30, tags: [], panels: [panels.stat("CPU")] }`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "from-jsonnet", dash.Obj.GetName())
+		require.Equal(t, provisioning.ClassicDashboard, dash.Classic)
+		panels, _, _ := unstructured.NestedSlice(dash.Obj.Object, "spec", "panels")
+		require.Equal(t, []any{map[string]any{"type": "stat", "title": "CPU"}}, panels)
+
+		// The checksum includes the libraries
+		source, _ := dash.Meta.GetSourceProperties()
+		require.Equal(t, JsonnetChecksum("abc", JsonnetLibrariesHash([]repository.FileTreeEntry{
+			{Path: "dashboards/panels.libsonnet", Hash: "lib", Blob: true},
+		})), source.Checksum)
+
+		// Evaluation errors are reported for the file
+		_, err = parser.Parse(context.Background(), &repository.FileInfo{
+			Path: "dashboards/broken.jsonnet",
+			Ref:  "main",
+			Data: []byte(`{ uid: error "broken template" }`),
+		})
+		require.True(t, apierrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "broken template")
+	})
+
+	t.Run("jsonnet template rendering many resources", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		reader.EXPECT().ReadTree(mock.Anything, "many").Return([]repository.FileTreeEntry{
+			{Path: "dashboards/many.jsonnet", Hash: "abc", Blob: true},
+		}, nil).Once()
+		parser.reader = reader
+
+		info := &repository.FileInfo{
+			Path: "dashboards/many.jsonnet",
+			Ref:  "many",
+			Hash: "abc",
+			Data: []byte(`[{ uid: name, schemaVersion: 30, tags: [], panels: [] } for name in ["first", "second"]]`),
+		}
+		all, err := parser.ParseAll(context.Background(), info)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, "first", all[0].Obj.GetName())
+		require.Equal(t, "second", all[1].Obj.GetName())
+		for _, dash := range all {
+			source, _ := dash.Meta.GetSourceProperties()
+			require.Equal(t, "dashboards/many.jsonnet", source.Path)
+			require.Equal(t, "abc", source.Checksum)
+		}
+
+		// A single resource is expected outside the sync
+		_, err = parser.Parse(context.Background(), info)
+		require.True(t, apierrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "the template renders 2 resources")
+	})
+
 	t.Run("validate proper folder metadata is set", func(t *testing.T) {
 		testCases := []struct {
 			name           string
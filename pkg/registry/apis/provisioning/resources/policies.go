@@ -0,0 +1,234 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	dashboard "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/apps/dashboard/pkg/migration/schemaversion"
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	dashboardkind "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
+)
+
+// PolicyViolationError is returned when a resource breaks the validation policies of the repository
+type PolicyViolationError struct {
+	Violations []string
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("breaks the repository policies: %s", strings.Join(e.Violations, "; "))
+}
+
+// CheckPolicies returns a PolicyViolationError listing the validation policies the resource breaks.
+// The panel, datasource and schema version policies read the classic dashboard JSON model,
+// or the elements of the dashboards in the v2 format.
+func (f *ParsedResource) CheckPolicies(policies *provisioning.ValidationPolicies) error {
+	if policies == nil || f.Obj == nil {
+		return nil
+	}
+
+	var violations []string
+	if policies.NamePattern != "" {
+		pattern, err := regexp.Compile(policies.NamePattern)
+		if err != nil {
+			return fmt.Errorf("invalid name pattern: %w", err)
+		}
+		if !pattern.MatchString(f.Obj.GetName()) {
+			violations = append(violations, fmt.Sprintf("name %q does not match the pattern %q", f.Obj.GetName(), policies.NamePattern))
+		}
+	}
+
+	if f.GVK.Group == dashboard.GROUP && f.GVK.Kind == "Dashboard" {
+		dashboardViolations, err := checkDashboardPolicies(policies, f.Obj, strings.HasPrefix(f.GVK.Version, "v2"))
+		if err != nil {
+			return fmt.Errorf("read dashboard: %w", err)
+		}
+		violations = append(violations, dashboardViolations...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &PolicyViolationError{Violations: violations}
+}
+
+func checkDashboardPolicies(policies *provisioning.ValidationPolicies, obj *unstructured.Unstructured, v2 bool) ([]string, error) {
+	var summary *dashboardkind.DashboardSummaryInfo
+	if v2 {
+		summary = readV2Dashboard(obj)
+	} else {
+		body, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		summary, err = dashboardkind.ReadDashboard(bytes.NewReader(body), policyDatasourceLookup{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	violations := missingTags(policies.RequiredTags, summary.Tags)
+
+	if policies.MinSchemaVersion > 0 && summary.SchemaVersion < policies.MinSchemaVersion {
+		violations = append(violations, fmt.Sprintf("schema version %d is older than %d", summary.SchemaVersion, policies.MinSchemaVersion))
+	}
+
+	panels := dashboardPanels(summary.Panels)
+	if policies.MaxPanels > 0 && int64(len(panels)) > policies.MaxPanels {
+		violations = append(violations, fmt.Sprintf("%d panels is more than the %d allowed", len(panels), policies.MaxPanels))
+	}
+
+	var forbidden, datasources []string
+	for _, panel := range panels {
+		if panel.Type != "" && slices.Contains(policies.ForbiddenPanelTypes, panel.Type) && !slices.Contains(forbidden, panel.Type) {
+			forbidden = append(forbidden, panel.Type)
+		}
+		if len(policies.AllowedDatasources) == 0 {
+			continue
+		}
+		for _, ds := range panel.Datasource {
+			if isAllowedDatasource(policies.AllowedDatasources, ds) {
+				continue
+			}
+			name := ds.UID
+			if name == "" {
+				name = ds.Type
+			}
+			if !slices.Contains(datasources, name) {
+				datasources = append(datasources, name)
+			}
+		}
+	}
+	if len(forbidden) > 0 {
+		violations = append(violations, fmt.Sprintf("forbidden panel types: %s", strings.Join(forbidden, ", ")))
+	}
+	if len(datasources) > 0 {
+		violations = append(violations, fmt.Sprintf("datasources not allowed: %s", strings.Join(datasources, ", ")))
+	}
+
+	return violations, nil
+}
+
+// readV2Dashboard reads the tags, panels and datasources of a dashboard in the v2 format.
+// The v2 format has no schema version, it is always converted from the latest one.
+// Library panels are counted, but their type and datasources are not in the dashboard.
+func readV2Dashboard(obj *unstructured.Unstructured) *dashboardkind.DashboardSummaryInfo {
+	summary := &dashboardkind.DashboardSummaryInfo{SchemaVersion: schemaversion.LATEST_VERSION}
+	summary.Tags, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "tags")
+
+	elements, _, _ := unstructured.NestedMap(obj.Object, "spec", "elements")
+	names := make([]string, 0, len(elements))
+	for name := range elements {
+		names = append(names, name)
+	}
+	// The violations are listed in the same order every time
+	slices.Sort(names)
+
+	for _, name := range names {
+		element, ok := elements[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		switch element["kind"] {
+		case "LibraryPanel":
+			summary.Panels = append(summary.Panels, dashboardkind.PanelSummaryInfo{})
+		case "Panel":
+			panel := dashboardkind.PanelSummaryInfo{}
+			// v2alpha1 uses the plugin id as kind, v2beta1 as group
+			vizKind, _, _ := unstructured.NestedString(element, "spec", "vizConfig", "kind")
+			vizGroup, _, _ := unstructured.NestedString(element, "spec", "vizConfig", "group")
+			panel.Type = vizKind
+			if vizKind == "VizConfig" {
+				panel.Type = vizGroup
+			}
+
+			queries, _, _ := unstructured.NestedSlice(element, "spec", "data", "spec", "queries")
+			for _, q := range queries {
+				query, ok := q.(map[string]any)
+				if !ok {
+					continue
+				}
+				panel.Datasource = append(panel.Datasource, v2QueryDatasource(query))
+			}
+			summary.Panels = append(summary.Panels, panel)
+		}
+	}
+	return summary
+}
+
+// v2QueryDatasource returns the datasource of a panel query.
+// The type is the kind of the query in v2alpha1, and its group in v2beta1.
+func v2QueryDatasource(query map[string]any) dashboardkind.DataSourceRef {
+	kind, _, _ := unstructured.NestedString(query, "spec", "query", "kind")
+	if kind == "DataQuery" {
+		group, _, _ := unstructured.NestedString(query, "spec", "query", "group")
+		uid, _, _ := unstructured.NestedString(query, "spec", "query", "datasource", "name")
+		return dashboardkind.DataSourceRef{UID: uid, Type: group}
+	}
+
+	ref := dashboardkind.DataSourceRef{Type: kind}
+	if uid, ok, _ := unstructured.NestedString(query, "spec", "datasource", "uid"); ok {
+		ref.UID = uid
+	}
+	if dsType, ok, _ := unstructured.NestedString(query, "spec", "datasource", "type"); ok && dsType != "" {
+		ref.Type = dsType
+	}
+	return ref
+}
+
+func missingTags(required, tags []string) []string {
+	var missing []string
+	for _, tag := range required {
+		if !slices.Contains(tags, tag) {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("missing required tags: %s", strings.Join(missing, ", "))}
+}
+
+// dashboardPanels flattens the panels, including the panels in collapsed rows, without the rows
+func dashboardPanels(panels []dashboardkind.PanelSummaryInfo) []dashboardkind.PanelSummaryInfo {
+	flat := make([]dashboardkind.PanelSummaryInfo, 0, len(panels))
+	for _, panel := range panels {
+		if panel.Type != "row" {
+			flat = append(flat, panel)
+		}
+		flat = append(flat, dashboardPanels(panel.Collapsed)...)
+	}
+	return flat
+}
+
+// isAllowedDatasource checks the reference by uid or by type.
+// The built-in datasources, and the variables that can't be resolved, are always allowed.
+func isAllowedDatasource(allowed []string, ds dashboardkind.DataSourceRef) bool {
+	switch {
+	case ds.Type == "datasource",
+		ds.UID == "-- Mixed --", ds.UID == "-- Dashboard --", ds.UID == "-- Grafana --",
+		strings.HasPrefix(ds.UID, "$"):
+		return true
+	case ds.UID == "" && ds.Type == "":
+		return true
+	}
+	return slices.Contains(allowed, ds.UID) || (ds.Type != "" && slices.Contains(allowed, ds.Type))
+}
+
+// policyDatasourceLookup keeps the datasource references as written in the dashboard,
+// the default datasource is not resolved
+type policyDatasourceLookup struct{}
+
+func (policyDatasourceLookup) ByRef(ref *dashboardkind.DataSourceRef) *dashboardkind.DataSourceRef {
+	return ref
+}
+
+func (policyDatasourceLookup) ByType(dsType string) []dashboardkind.DataSourceRef {
+	return []dashboardkind.DataSourceRef{{Type: dsType}}
+}
@@ -0,0 +1,206 @@
+package resources
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/grafana/grafana/apps/dashboard/pkg/migration/schemaversion"
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+)
+
+func TestParsedResource_CheckPolicies(t *testing.T) {
+	dashboardGVK := schema.GroupVersionKind{Group: "dashboard.grafana.app", Version: "v1beta1", Kind: "Dashboard"}
+	dashboard := func(name string, spec map[string]any) *ParsedResource {
+		return &ParsedResource{
+			Info: &repository.FileInfo{Path: "dashboards/" + name + ".json"},
+			GVK:  dashboardGVK,
+			Obj: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "dashboard.grafana.app/v1beta1",
+				"kind":       "Dashboard",
+				"metadata":   map[string]any{"name": name},
+				"spec":       spec,
+			}},
+		}
+	}
+	spec := map[string]any{
+		"title":         "Test",
+		"tags":          []any{"prod", "team-a"},
+		"schemaVersion": int64(39),
+		"panels": []any{
+			map[string]any{
+				"id":         int64(1),
+				"type":       "timeseries",
+				"datasource": map[string]any{"uid": "prom-uid", "type": "prometheus"},
+				"targets": []any{
+					map[string]any{"refId": "A", "datasource": map[string]any{"uid": "prom-uid", "type": "prometheus"}},
+				},
+			},
+			map[string]any{
+				"id":        int64(2),
+				"type":      "row",
+				"collapsed": true,
+				"panels": []any{
+					map[string]any{
+						"id":         int64(3),
+						"type":       "text",
+						"datasource": map[string]any{"uid": "-- Grafana --", "type": "datasource"},
+					},
+					map[string]any{
+						"id":         int64(4),
+						"type":       "table",
+						"datasource": map[string]any{"uid": "loki-uid", "type": "loki"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		parsed     *ParsedResource
+		policies   *provisioning.ValidationPolicies
+		violations []string
+	}{
+		{
+			name:   "no policies",
+			parsed: dashboard("test", spec),
+		},
+		{
+			name:   "all policies respected",
+			parsed: dashboard("team-a-test", spec),
+			policies: &provisioning.ValidationPolicies{
+				RequiredTags:        []string{"prod"},
+				AllowedDatasources:  []string{"prom-uid", "loki"},
+				ForbiddenPanelTypes: []string{"news"},
+				MinSchemaVersion:    36,
+				MaxPanels:           3,
+				NamePattern:         "^team-a-",
+			},
+		},
+		{
+			name:   "all policies broken",
+			parsed: dashboard("test", spec),
+			policies: &provisioning.ValidationPolicies{
+				RequiredTags:        []string{"prod", "reviewed", "critical"},
+				AllowedDatasources:  []string{"prom-uid"},
+				ForbiddenPanelTypes: []string{"text", "news"},
+				MinSchemaVersion:    40,
+				MaxPanels:           2,
+				NamePattern:         "^team-a-",
+			},
+			violations: []string{
+				`name "test" does not match the pattern "^team-a-"`,
+				"missing required tags: reviewed, critical",
+				"schema version 39 is older than 40",
+				"3 panels is more than the 2 allowed",
+				"forbidden panel types: text",
+				"datasources not allowed: loki-uid",
+			},
+		},
+		{
+			name: "v2alpha1 dashboards are checked",
+			parsed: v2Dashboard("v2alpha1", map[string]any{
+				"text": map[string]any{"kind": "Panel", "spec": map[string]any{
+					"vizConfig": map[string]any{"kind": "text"},
+				}},
+				"graph": map[string]any{"kind": "Panel", "spec": map[string]any{
+					"vizConfig": map[string]any{"kind": "timeseries"},
+					"data": map[string]any{"spec": map[string]any{"queries": []any{
+						map[string]any{"spec": map[string]any{
+							"query":      map[string]any{"kind": "prometheus"},
+							"datasource": map[string]any{"type": "prometheus", "uid": "prom-uid"},
+						}},
+						map[string]any{"spec": map[string]any{
+							"query":      map[string]any{"kind": "loki"},
+							"datasource": map[string]any{"uid": "loki-uid"},
+						}},
+					}}},
+				}},
+				"shared": map[string]any{"kind": "LibraryPanel", "spec": map[string]any{"title": "Shared"}},
+			}),
+			policies: &provisioning.ValidationPolicies{
+				RequiredTags:        []string{"prod"},
+				AllowedDatasources:  []string{"prom-uid"},
+				ForbiddenPanelTypes: []string{"text", "news"},
+				MinSchemaVersion:    100,
+				MaxPanels:           2,
+			},
+			violations: []string{
+				"missing required tags: prod",
+				fmt.Sprintf("schema version %d is older than 100", schemaversion.LATEST_VERSION),
+				"3 panels is more than the 2 allowed",
+				"forbidden panel types: text",
+				"datasources not allowed: loki-uid",
+			},
+		},
+		{
+			name: "v2beta1 dashboards are checked",
+			parsed: v2Dashboard("v2beta1", map[string]any{
+				"graph": map[string]any{"kind": "Panel", "spec": map[string]any{
+					"vizConfig": map[string]any{"kind": "VizConfig", "group": "news"},
+					"data": map[string]any{"spec": map[string]any{"queries": []any{
+						map[string]any{"spec": map[string]any{
+							"query": map[string]any{"kind": "DataQuery", "group": "prometheus", "datasource": map[string]any{"name": "prom-uid"}},
+						}},
+						map[string]any{"spec": map[string]any{
+							"query": map[string]any{"kind": "DataQuery", "group": "elasticsearch"},
+						}},
+					}}},
+				}},
+			}),
+			policies: &provisioning.ValidationPolicies{
+				AllowedDatasources:  []string{"prom-uid", "loki"},
+				ForbiddenPanelTypes: []string{"news"},
+				MinSchemaVersion:    40,
+				MaxPanels:           1,
+			},
+			violations: []string{
+				"forbidden panel types: news",
+				"datasources not allowed: elasticsearch",
+			},
+		},
+		{
+			name: "only the name is checked on other resources",
+			parsed: &ParsedResource{
+				GVK: schema.GroupVersionKind{Group: "folder.grafana.app", Version: "v1beta1", Kind: "Folder"},
+				Obj: &unstructured.Unstructured{Object: map[string]any{
+					"metadata": map[string]any{"name": "other"},
+				}},
+			},
+			policies: &provisioning.ValidationPolicies{
+				RequiredTags: []string{"prod"},
+				NamePattern:  "^team-a-",
+			},
+			violations: []string{`name "other" does not match the pattern "^team-a-"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parsed.CheckPolicies(tt.policies)
+			if len(tt.violations) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var policyErr *PolicyViolationError
+			require.ErrorAs(t, err, &policyErr)
+			require.Equal(t, tt.violations, policyErr.Violations)
+		})
+	}
+}
+
+func v2Dashboard(version string, elements map[string]any) *ParsedResource {
+	return &ParsedResource{
+		GVK: schema.GroupVersionKind{Group: "dashboard.grafana.app", Version: version, Kind: "Dashboard"},
+		Obj: &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": "test"},
+			"spec":     map[string]any{"title": "Test", "tags": []any{"team-a"}, "elements": elements},
+		}},
+	}
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,15 +35,22 @@ type RepositoryResources interface {
 	RemoveResourceFromFile(ctx context.Context, path, ref string) (string, schema.GroupVersionKind, error)
 	FindResourcePath(ctx context.Context, name string, gvk schema.GroupVersionKind) (string, error)
 	RenameResourceFile(ctx context.Context, path, previousRef, newPath, newRef string) (string, schema.GroupVersionKind, error)
+	// Alerting files from the alerting configuration
+	WriteAlertingFiles(ctx context.Context, options WriteOptions) ([]string, error)
+	RemoveAlertingFile(ctx context.Context, path string) error
+	// Library panel files
+	RemoveLibraryPanelsFile(ctx context.Context, path string) error
 	// Stats
 	Stats(ctx context.Context) (*provisioning.ResourceStats, error)
 	List(ctx context.Context) (*provisioning.ResourceList, error)
 }
 
 type repositoryResourcesFactory struct {
-	parsers ParserFactory
-	clients ClientFactory
-	lister  ResourceLister
+	parsers       ParserFactory
+	clients       ClientFactory
+	lister        ResourceLister
+	alerting      AlertingProvisioner
+	libraryPanels LibraryPanelProvisioner
 }
 type repositoryResources struct {
 	*FolderManager
@@ -57,7 +65,61 @@ func (r *repositoryResources) Stats(ctx context.Context) (*provisioning.Resource
 }
 
 func (r *repositoryResources) List(ctx context.Context) (*provisioning.ResourceList, error) {
-	return r.lister.List(ctx, r.namespace, r.repoName)
+	return ListRepositoryResources(ctx, r.lister, r.alerting, r.libraryPanels, r.namespace, r.repoName)
+}
+
+// ListRepositoryResources lists the resources managed by the repository, including the applied alerting
+// and library panel files. It only needs to read the repository, so it can be used when the repository is read-only
+func ListRepositoryResources(ctx context.Context, lister ResourceLister, alerting AlertingProvisioner, libraryPanels LibraryPanelProvisioner, namespace, repoName string) (*provisioning.ResourceList, error) {
+	list, err := lister.List(ctx, namespace, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The provisioning files are listed with the hash of the applied version, so the full sync
+	// only applies the changed files and removes the deleted ones
+	if alerting != nil {
+		files, err := alerting.List(ctx, namespace, repoName)
+		if err != nil {
+			return nil, fmt.Errorf("list alerting files: %w", err)
+		}
+		list.Items = append(list.Items, provisioningFileItems(files, AlertingFileKind)...)
+	}
+	if libraryPanels != nil {
+		files, err := libraryPanels.List(ctx, namespace, repoName)
+		if err != nil {
+			return nil, fmt.Errorf("list library panel files: %w", err)
+		}
+		list.Items = append(list.Items, provisioningFileItems(files, LibraryPanelsFileKind)...)
+	}
+	return list, nil
+}
+
+// IsProvisioningFileItem checks if the listed resource is an applied alerting or library panel file
+func IsProvisioningFileItem(item provisioning.ResourceListItem) bool {
+	return (item.Group == AlertingFileKind.Group && item.Resource == AlertingFileKind.Kind) ||
+		(item.Group == LibraryPanelsFileKind.Group && item.Resource == LibraryPanelsFileKind.Kind)
+}
+
+// provisioningFileItems returns the applied provisioning files as resources named after their path
+func provisioningFileItems(files map[string]string, kind schema.GroupVersionKind) []provisioning.ResourceListItem {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	items := make([]provisioning.ResourceListItem, 0, len(paths))
+	for _, path := range paths {
+		items = append(items, provisioning.ResourceListItem{
+			Path:     path,
+			Group:    kind.Group,
+			Resource: kind.Kind,
+			Name:     path,
+			Hash:     files[path],
+		})
+	}
+	return items
 }
 
 // FindResourcePath finds the repository file path for a resource by its name and GroupVersionKind
@@ -96,8 +158,8 @@ func (r *repositoryResources) FindResourcePath(ctx context.Context, name string,
 	return sourcePath, nil
 }
 
-func NewRepositoryResourcesFactory(parsers ParserFactory, clients ClientFactory, lister ResourceLister) RepositoryResourcesFactory {
-	return &repositoryResourcesFactory{parsers, clients, lister}
+func NewRepositoryResourcesFactory(parsers ParserFactory, clients ClientFactory, lister ResourceLister, alerting AlertingProvisioner, libraryPanels LibraryPanelProvisioner) RepositoryResourcesFactory {
+	return &repositoryResourcesFactory{parsers, clients, lister, alerting, libraryPanels}
 }
 
 func (r *repositoryResourcesFactory) Client(ctx context.Context, repo repository.ReaderWriter) (RepositoryResources, error) {
@@ -116,7 +178,7 @@ func (r *repositoryResourcesFactory) Client(ctx context.Context, repo repository
 	}
 
 	folders := NewFolderManager(repo, folderClient, NewEmptyFolderTree())
-	resources := NewResourcesManager(repo, folders, parser, clients)
+	resources := NewResourcesManager(repo, folders, parser, clients, r.lister, r.alerting, r.libraryPanels)
 
 	return &repositoryResources{
 		FolderManager:    folders,
@@ -297,6 +297,100 @@ func (_c *MockRepositoryResources_List_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// RemoveAlertingFile provides a mock function with given fields: ctx, path
+func (_m *MockRepositoryResources) RemoveAlertingFile(ctx context.Context, path string) error {
+	ret := _m.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAlertingFile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRepositoryResources_RemoveAlertingFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveAlertingFile'
+type MockRepositoryResources_RemoveAlertingFile_Call struct {
+	*mock.Call
+}
+
+// RemoveAlertingFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockRepositoryResources_Expecter) RemoveAlertingFile(ctx interface{}, path interface{}) *MockRepositoryResources_RemoveAlertingFile_Call {
+	return &MockRepositoryResources_RemoveAlertingFile_Call{Call: _e.mock.On("RemoveAlertingFile", ctx, path)}
+}
+
+func (_c *MockRepositoryResources_RemoveAlertingFile_Call) Run(run func(ctx context.Context, path string)) *MockRepositoryResources_RemoveAlertingFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRepositoryResources_RemoveAlertingFile_Call) Return(_a0 error) *MockRepositoryResources_RemoveAlertingFile_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRepositoryResources_RemoveAlertingFile_Call) RunAndReturn(run func(context.Context, string) error) *MockRepositoryResources_RemoveAlertingFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveLibraryPanelsFile provides a mock function with given fields: ctx, path
+func (_m *MockRepositoryResources) RemoveLibraryPanelsFile(ctx context.Context, path string) error {
+	ret := _m.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveLibraryPanelsFile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRepositoryResources_RemoveLibraryPanelsFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveLibraryPanelsFile'
+type MockRepositoryResources_RemoveLibraryPanelsFile_Call struct {
+	*mock.Call
+}
+
+// RemoveLibraryPanelsFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockRepositoryResources_Expecter) RemoveLibraryPanelsFile(ctx interface{}, path interface{}) *MockRepositoryResources_RemoveLibraryPanelsFile_Call {
+	return &MockRepositoryResources_RemoveLibraryPanelsFile_Call{Call: _e.mock.On("RemoveLibraryPanelsFile", ctx, path)}
+}
+
+func (_c *MockRepositoryResources_RemoveLibraryPanelsFile_Call) Run(run func(ctx context.Context, path string)) *MockRepositoryResources_RemoveLibraryPanelsFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRepositoryResources_RemoveLibraryPanelsFile_Call) Return(_a0 error) *MockRepositoryResources_RemoveLibraryPanelsFile_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRepositoryResources_RemoveLibraryPanelsFile_Call) RunAndReturn(run func(context.Context, string) error) *MockRepositoryResources_RemoveLibraryPanelsFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RemoveResourceFromFile provides a mock function with given fields: ctx, path, ref
 func (_m *MockRepositoryResources) RemoveResourceFromFile(ctx context.Context, path string, ref string) (string, schema.GroupVersionKind, error) {
 	ret := _m.Called(ctx, path, ref)
@@ -520,6 +614,65 @@ func (_c *MockRepositoryResources_Stats_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// WriteAlertingFiles provides a mock function with given fields: ctx, options
+func (_m *MockRepositoryResources) WriteAlertingFiles(ctx context.Context, options WriteOptions) ([]string, error) {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteAlertingFiles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, WriteOptions) ([]string, error)); ok {
+		return rf(ctx, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, WriteOptions) []string); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, WriteOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepositoryResources_WriteAlertingFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WriteAlertingFiles'
+type MockRepositoryResources_WriteAlertingFiles_Call struct {
+	*mock.Call
+}
+
+// WriteAlertingFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - options WriteOptions
+func (_e *MockRepositoryResources_Expecter) WriteAlertingFiles(ctx interface{}, options interface{}) *MockRepositoryResources_WriteAlertingFiles_Call {
+	return &MockRepositoryResources_WriteAlertingFiles_Call{Call: _e.mock.On("WriteAlertingFiles", ctx, options)}
+}
+
+func (_c *MockRepositoryResources_WriteAlertingFiles_Call) Run(run func(ctx context.Context, options WriteOptions)) *MockRepositoryResources_WriteAlertingFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(WriteOptions))
+	})
+	return _c
+}
+
+func (_c *MockRepositoryResources_WriteAlertingFiles_Call) Return(_a0 []string, _a1 error) *MockRepositoryResources_WriteAlertingFiles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepositoryResources_WriteAlertingFiles_Call) RunAndReturn(run func(context.Context, WriteOptions) ([]string, error)) *MockRepositoryResources_WriteAlertingFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // WriteResourceFileFromObject provides a mock function with given fields: ctx, obj, options
 func (_m *MockRepositoryResources) WriteResourceFileFromObject(ctx context.Context, obj *unstructured.Unstructured, options WriteOptions) (string, error) {
 	ret := _m.Called(ctx, obj, options)
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
 	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
 	"github.com/grafana/grafana/apps/provisioning/pkg/safepath"
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
@@ -54,15 +55,24 @@ type ResourcesManager struct {
 	folders         *FolderManager
 	parser          Parser
 	clients         ResourceClients
-	resourcesLookup map[resourceID]string // the path with this k8s name
+	lister          ResourceLister          // optional, the resources a template stops rendering are kept without it
+	alerting        AlertingProvisioner     // optional, the alerting files are not supported without it
+	libraryPanels   LibraryPanelProvisioner // optional, the library panel files are not supported without it
+	resourcesLookup map[resourceID]string   // the path with this k8s name
+
+	// The resources saved from each template, listed on first use
+	rendered map[string][]provisioning.ResourceListItem
 }
 
-func NewResourcesManager(repo repository.ReaderWriter, folders *FolderManager, parser Parser, clients ResourceClients) *ResourcesManager {
+func NewResourcesManager(repo repository.ReaderWriter, folders *FolderManager, parser Parser, clients ResourceClients, lister ResourceLister, alerting AlertingProvisioner, libraryPanels LibraryPanelProvisioner) *ResourcesManager {
 	return &ResourcesManager{
 		repo:            repo,
 		folders:         folders,
 		parser:          parser,
 		clients:         clients,
+		lister:          lister,
+		alerting:        alerting,
+		libraryPanels:   libraryPanels,
 		resourcesLookup: map[resourceID]string{},
 	}
 }
@@ -198,15 +208,94 @@ func (r *ResourcesManager) WriteResourceFromFile(ctx context.Context, path strin
 		return "", schema.GroupVersionKind{}, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	parsed, err := r.parser.Parse(ctx, fileInfo)
+	// Alerting files are applied by the alerting provisioning
+	if r.alerting != nil && IsAlertingFile(fileInfo.Data) {
+		cfg := r.repo.Config()
+		return path, AlertingFileKind, r.alerting.Apply(ctx, cfg.Namespace, cfg.Name, path, fileInfo.Hash, fileInfo.Data)
+	}
+
+	// Library panel files are saved in the folder of the file
+	if r.libraryPanels != nil && IsLibraryPanelsFile(fileInfo.Data) {
+		folder, err := r.folders.EnsureFolderPathExist(ctx, path)
+		if err != nil {
+			return path, LibraryPanelsFileKind, fmt.Errorf("failed to ensure folder path exists: %w", err)
+		}
+		cfg := r.repo.Config()
+		return path, LibraryPanelsFileKind, r.libraryPanels.Apply(ctx, cfg.Namespace, cfg.Name, folder, path, fileInfo.Hash, fileInfo.Data)
+	}
+
+	all, err := r.parser.ParseAll(ctx, fileInfo)
 	if err != nil {
 		return "", schema.GroupVersionKind{}, fmt.Errorf("failed to parse file: %w", err)
 	}
+	if len(all) == 1 && !IsJsonnetFile(path) {
+		return r.writeParsed(ctx, path, all[0])
+	}
 
+	// Templates can render many resources, the first one is reported
+	var errs []error
+	rendered := make(map[resourceID]bool, len(all))
+	for _, parsed := range all {
+		rendered[resourceID{Name: parsed.Obj.GetName(), Resource: parsed.GVR.Resource, Group: parsed.GVK.Group}] = true
+		if _, _, err := r.writeParsed(ctx, path, parsed); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := r.removeNotRendered(ctx, path, rendered); err != nil {
+		errs = append(errs, err)
+	}
+
+	return all[0].Obj.GetName(), all[0].GVK, errors.Join(errs...)
+}
+
+// removeNotRendered deletes the resources saved from the template, that it does not render anymore
+func (r *ResourcesManager) removeNotRendered(ctx context.Context, path string, rendered map[resourceID]bool) error {
+	if r.lister == nil {
+		return nil
+	}
+
+	if r.rendered == nil {
+		cfg := r.repo.Config()
+		list, err := r.lister.List(ctx, cfg.Namespace, cfg.Name)
+		if err != nil {
+			return fmt.Errorf("list the rendered resources: %w", err)
+		}
+		r.rendered = make(map[string][]provisioning.ResourceListItem)
+		for _, item := range list.Items {
+			if IsJsonnetFile(item.Path) {
+				r.rendered[item.Path] = append(r.rendered[item.Path], item)
+			}
+		}
+	}
+
+	for _, item := range r.rendered[path] {
+		if rendered[resourceID{Name: item.Name, Resource: item.Resource, Group: item.Group}] {
+			continue
+		}
+
+		client, _, err := r.clients.ForResource(schema.GroupVersionResource{Group: item.Group, Resource: item.Resource})
+		if err != nil {
+			return fmt.Errorf("get client for %s/%s: %w", item.Group, item.Resource, err)
+		}
+		if err := client.Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("delete %s/%s %s no longer rendered by the template: %w", item.Group, item.Resource, item.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// writeParsed saves a single resource read from the file
+func (r *ResourcesManager) writeParsed(ctx context.Context, path string, parsed *ParsedResource) (string, schema.GroupVersionKind, error) {
 	if parsed.Obj.GetName() == "" {
 		return "", schema.GroupVersionKind{}, ErrMissingName
 	}
 
+	// The resources breaking the repository policies are not written
+	if err := parsed.CheckPolicies(r.repo.Config().Spec.Policies); err != nil {
+		return parsed.Obj.GetName(), parsed.GVK, err
+	}
+
 	// Check if the resource already exists
 	id := resourceID{
 		Name:     parsed.Obj.GetName(),
@@ -233,7 +322,7 @@ func (r *ResourcesManager) WriteResourceFromFile(ctx context.Context, path strin
 	parsed.Meta.SetUID("")
 	parsed.Meta.SetResourceVersion("")
 
-	err = parsed.Run(ctx)
+	err := parsed.Run(ctx)
 
 	return parsed.Obj.GetName(), parsed.GVK, err
 }
@@ -253,29 +342,104 @@ func (r *ResourcesManager) RemoveResourceFromFile(ctx context.Context, path stri
 		return "", schema.GroupVersionKind{}, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	if r.alerting != nil && IsAlertingFile(info.Data) {
+		return path, AlertingFileKind, r.RemoveAlertingFile(ctx, path)
+	}
+	if r.libraryPanels != nil && IsLibraryPanelsFile(info.Data) {
+		return path, LibraryPanelsFileKind, r.RemoveLibraryPanelsFile(ctx, path)
+	}
+
+	if IsJsonnetFile(path) {
+		// Templates must be evaluated to know which resources they created
+		all, err := r.parser.ParseAll(ctx, info)
+		if err != nil {
+			return "", schema.GroupVersionKind{}, fmt.Errorf("failed to parse file: %w", err)
+		}
+		var errs []error
+		for _, parsed := range all {
+			if _, err := r.removeObject(ctx, parsed.Obj, &parsed.GVK); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return all[0].Obj.GetName(), schema.GroupVersionKind{}, errors.Join(errs...)
+	}
+
 	obj, gvk, _ := DecodeYAMLObject(bytes.NewBuffer(info.Data))
+	name, err := r.removeObject(ctx, obj, gvk)
+	return name, schema.GroupVersionKind{}, err
+}
+
+// removeObject deletes the resource read from the file
+func (r *ResourcesManager) removeObject(ctx context.Context, obj *unstructured.Unstructured, gvk *schema.GroupVersionKind) (string, error) {
 	if obj == nil {
-		return "", schema.GroupVersionKind{}, fmt.Errorf("no object found")
+		return "", fmt.Errorf("no object found")
 	}
 
 	objName := obj.GetName()
 	if objName == "" {
-		return "", schema.GroupVersionKind{}, ErrMissingName
+		return "", ErrMissingName
 	}
 
 	client, _, err := r.clients.ForKind(*gvk)
 	if err != nil {
-		return "", schema.GroupVersionKind{}, fmt.Errorf("unable to get client for deleted object: %w", err)
+		return "", fmt.Errorf("unable to get client for deleted object: %w", err)
 	}
 
 	err = client.Delete(ctx, objName, metav1.DeleteOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return objName, schema.GroupVersionKind{}, nil // Already deleted or simply non-existing, nothing to do
+			return objName, nil // Already deleted or simply non-existing, nothing to do
 		}
 
-		return "", schema.GroupVersionKind{}, fmt.Errorf("failed to delete: %w", err)
+		return "", fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return objName, nil
+}
+
+// RemoveAlertingFile deletes the alerting objects applied from the file
+func (r *ResourcesManager) RemoveAlertingFile(ctx context.Context, path string) error {
+	if r.alerting == nil {
+		return nil
+	}
+	cfg := r.repo.Config()
+	return r.alerting.Remove(ctx, cfg.Namespace, cfg.Name, path)
+}
+
+// RemoveLibraryPanelsFile deletes the library panels applied from the file
+func (r *ResourcesManager) RemoveLibraryPanelsFile(ctx context.Context, path string) error {
+	if r.libraryPanels == nil {
+		return nil
+	}
+	cfg := r.repo.Config()
+	return r.libraryPanels.Remove(ctx, cfg.Namespace, cfg.Name, path)
+}
+
+// WriteAlertingFiles exports the alerting configuration that is not provisioned yet to the alerting folder of the repository
+func (r *ResourcesManager) WriteAlertingFiles(ctx context.Context, options WriteOptions) ([]string, error) {
+	if r.alerting == nil {
+		return nil, nil
+	}
+
+	files, err := r.alerting.Export(ctx, r.repo.Config().Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("export alerting: %w", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		fileName := safepath.Join(options.Path, AlertingFolder, name)
+		if err := r.repo.Write(ctx, fileName, options.Ref, files[name], "exported alerting from grafana"); err != nil {
+			return paths, fmt.Errorf("failed to write file: %s, %w", fileName, err)
+		}
+		paths = append(paths, fileName)
 	}
 
-	return objName, schema.GroupVersionKind{}, nil
+	return paths, nil
 }
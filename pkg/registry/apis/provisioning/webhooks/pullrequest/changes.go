@@ -2,11 +2,14 @@ package pullrequest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"path"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
 	"github.com/grafana/grafana-app-sdk/logging"
 	dashboard "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v1beta1"
 	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
@@ -28,6 +31,9 @@ type changeInfo struct {
 
 	// Requested image render, but it is not available
 	MissingImageRenderer bool
+
+	// URL of the read-only folder with the dashboards of the pull request
+	PreviewEnvironmentURL string
 }
 
 type fileChangeInfo struct {
@@ -47,19 +53,29 @@ type fileChangeInfo struct {
 	// URL where we can see a preview of this particular change
 	PreviewURL           string
 	PreviewScreenshotURL string
+
+	// URL of the copy of the dashboard in the preview environment
+	PreviewEnvironmentURL string
+
+	// The repository policies the file breaks
+	PolicyViolations []string
 }
 
 type evaluator struct {
-	render      ScreenshotRenderer
-	parsers     resources.ParserFactory
-	urlProvider func(namespace string) string
+	render        ScreenshotRenderer
+	parsers       resources.ParserFactory
+	alerting      resources.AlertingProvisioner
+	libraryPanels resources.LibraryPanelProvisioner
+	urlProvider   func(namespace string) string
 }
 
-func NewEvaluator(render ScreenshotRenderer, parsers resources.ParserFactory, urlProvider func(namespace string) string) Evaluator {
+func NewEvaluator(render ScreenshotRenderer, parsers resources.ParserFactory, alerting resources.AlertingProvisioner, libraryPanels resources.LibraryPanelProvisioner, urlProvider func(namespace string) string) Evaluator {
 	return &evaluator{
-		render:      render,
-		parsers:     parsers,
-		urlProvider: urlProvider,
+		render:        render,
+		parsers:       parsers,
+		alerting:      alerting,
+		libraryPanels: libraryPanels,
+		urlProvider:   urlProvider,
 	}
 }
 
@@ -112,6 +128,18 @@ func (e *evaluator) evaluateFile(ctx context.Context, repo repository.Reader, ba
 		return info
 	}
 
+	// Alerting files are only validated, they have no preview
+	if e.alerting != nil && resources.IsAlertingFile(fileInfo.Data) {
+		return evaluateProvisioningFile(info, fileInfo, resources.AlertingFileKind,
+			e.alerting.Validate(ctx, repo.Config().Namespace, repo.Config().Name, fileInfo.Path, fileInfo.Data))
+	}
+
+	// Library panel files are only validated, the dashboards using them have the preview
+	if e.libraryPanels != nil && resources.IsLibraryPanelsFile(fileInfo.Data) {
+		return evaluateProvisioningFile(info, fileInfo, resources.LibraryPanelsFileKind,
+			e.libraryPanels.Validate(ctx, repo.Config().Namespace, repo.Config().Name, fileInfo.Path, fileInfo.Data))
+	}
+
 	// Read the file as a resource
 	info.Parsed, err = parser.Parse(ctx, fileInfo)
 	if err != nil {
@@ -131,6 +159,16 @@ func (e *evaluator) evaluateFile(ctx context.Context, repo repository.Reader, ba
 		return info
 	}
 
+	// The resources breaking the repository policies would not be applied when merged
+	if err = info.Parsed.CheckPolicies(repo.Config().Spec.Policies); err != nil {
+		var policyErr *resources.PolicyViolationError
+		if errors.As(err, &policyErr) {
+			info.PolicyViolations = policyErr.Violations
+		}
+		info.Error = err.Error()
+		return info
+	}
+
 	// Dashboards get special handling
 	if info.Parsed.GVK.Kind == dashboardKind {
 		// FIXME: extract the logic out of a dashboard URL builder/injector or similar
@@ -170,6 +208,25 @@ func (e *evaluator) evaluateFile(ctx context.Context, repo repository.Reader, ba
 	return info
 }
 
+// evaluateProvisioningFile reports the result of validating a file that defines many objects rather than a single resource
+func evaluateProvisioningFile(info fileChangeInfo, fileInfo *repository.FileInfo, gvk schema.GroupVersionKind, validationErr error) fileChangeInfo {
+	action := provisioning.ResourceActionUpdate
+	if info.Change.Action == repository.FileActionCreated {
+		action = provisioning.ResourceActionCreate
+	}
+	info.Title = path.Base(fileInfo.Path)
+	info.Parsed = &resources.ParsedResource{
+		Info:   fileInfo,
+		GVK:    gvk,
+		Action: action,
+	}
+
+	if validationErr != nil {
+		info.Error = validationErr.Error()
+	}
+	return info
+}
+
 func renderScreenshotFromGrafanaURL(ctx context.Context,
 	baseURL string,
 	renderer ScreenshotRenderer,
@@ -416,6 +416,73 @@ func TestCalculateChanges(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name: "policy violation",
+			setupMocks: func(parser *resources.MockParser, reader *repository.MockReader, progress *jobs.MockJobProgressRecorder, renderer *MockScreenshotRenderer, parserFactory *resources.MockParserFactory) {
+				reader.On("Config").Return(&provisioning.Repository{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-repo",
+						Namespace: "x",
+					},
+					Spec: provisioning.RepositorySpec{
+						Policies: &provisioning.ValidationPolicies{
+							RequiredTags: []string{"prod"},
+						},
+					},
+				})
+				parserFactory.On("GetParser", mock.Anything, mock.Anything).Return(parser, nil)
+				progress.On("SetMessage", mock.Anything, "process path/to/file.json").Return()
+				renderer.On("IsAvailable", mock.Anything, mock.Anything).Return(false)
+
+				finfo := &repository.FileInfo{
+					Path: "path/to/file.json",
+					Ref:  "ref",
+					Data: []byte("xxxx"),
+				}
+				obj := &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": resources.DashboardResource.GroupVersion().String(),
+						"kind":       dashboardKind,
+						"metadata": map[string]interface{}{
+							"name": "the-uid",
+						},
+						"spec": map[string]interface{}{
+							"title": "hello world",
+						},
+					},
+				}
+				meta, _ := utils.MetaAccessor(obj)
+
+				reader.On("Read", mock.Anything, "path/to/file.json", "ref").Return(finfo, nil)
+				parser.On("Parse", mock.Anything, finfo).Return(&resources.ParsedResource{
+					Info: finfo,
+					Repo: provisioning.ResourceRepositoryInfo{
+						Namespace: "x",
+						Name:      "y",
+					},
+					GVK:            resources.DashboardResource.GroupVersion().WithKind(dashboardKind),
+					Obj:            obj,
+					Meta:           meta,
+					DryRunResponse: obj,
+				}, nil)
+			},
+			changes: []repository.VersionedFileChange{{
+				Action: repository.FileActionCreated,
+				Path:   "path/to/file.json",
+				Ref:    "ref",
+			}},
+			expectedInfo: changeInfo{
+				Changes: []fileChangeInfo{{
+					Change: repository.VersionedFileChange{
+						Action: repository.FileActionCreated,
+						Path:   "path/to/file.json",
+						Ref:    "ref",
+					},
+					Error:            "breaks the repository policies: missing required tags: prod",
+					PolicyViolations: []string{"missing required tags: prod"},
+				}},
+			},
+		},
 		{
 			name: "screenshot render error",
 			setupMocks: func(parser *resources.MockParser, reader *repository.MockReader, progress *jobs.MockJobProgressRecorder, renderer *MockScreenshotRenderer, parserFactory *resources.MockParserFactory) {
@@ -753,7 +820,7 @@ func TestCalculateChanges(t *testing.T) {
 
 			tt.setupMocks(parser, reader, progress, renderer, parserFactory)
 
-			evaluator := NewEvaluator(renderer, parserFactory, func(_ string) string {
+			evaluator := NewEvaluator(renderer, parserFactory, nil, nil, func(_ string) string {
 				if tt.grafanaBaseURL != "" {
 					return tt.grafanaBaseURL
 				}
@@ -784,6 +851,7 @@ func TestCalculateChanges(t *testing.T) {
 				require.Equal(t, tt.expectedInfo.Changes[i].GrafanaScreenshotURL, change.GrafanaScreenshotURL)
 				require.Equal(t, tt.expectedInfo.Changes[i].PreviewScreenshotURL, change.PreviewScreenshotURL)
 				require.Equal(t, tt.expectedInfo.Changes[i].Error, change.Error)
+				require.Equal(t, tt.expectedInfo.Changes[i].PolicyViolations, change.PolicyViolations)
 			}
 		})
 	}
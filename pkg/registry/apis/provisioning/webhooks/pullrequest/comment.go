@@ -6,20 +6,25 @@ import (
 	"fmt"
 	"html/template"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
 type commenter struct {
-	templateDashboard  *template.Template
-	templateTable      *template.Template
-	templateRenderInfo *template.Template
+	templateDashboard   *template.Template
+	templateTable       *template.Template
+	templateRenderInfo  *template.Template
+	templatePreviewInfo *template.Template
+	templatePolicies    *template.Template
 }
 
 func NewCommenter() Commenter {
 	return &commenter{
-		templateDashboard:  template.Must(template.New("dashboard").Parse(commentTemplateSingleDashboard)),
-		templateTable:      template.Must(template.New("table").Parse(commentTemplateTable)),
-		templateRenderInfo: template.Must(template.New("setup").Parse(commentTemplateMissingImageRenderer)),
+		templateDashboard:   template.Must(template.New("dashboard").Parse(commentTemplateSingleDashboard)),
+		templateTable:       template.Must(template.New("table").Parse(commentTemplateTable)),
+		templateRenderInfo:  template.Must(template.New("setup").Parse(commentTemplateMissingImageRenderer)),
+		templatePreviewInfo: template.Must(template.New("preview").Parse(commentTemplatePreviewEnvironment)),
+		templatePolicies:    template.Must(template.New("policies").Parse(commentTemplatePolicyViolations)),
 	}
 }
 
@@ -53,6 +58,18 @@ func (c *commenter) generateComment(_ context.Context, info changeInfo) (string,
 		}
 	}
 
+	if slices.ContainsFunc(info.Changes, func(change fileChangeInfo) bool { return len(change.PolicyViolations) > 0 }) {
+		if err := c.templatePolicies.Execute(&buf, info); err != nil {
+			return "", fmt.Errorf("unable to execute template: %w", err)
+		}
+	}
+
+	if info.PreviewEnvironmentURL != "" {
+		if err := c.templatePreviewInfo.Execute(&buf, info); err != nil {
+			return "", fmt.Errorf("unable to execute template: %w", err)
+		}
+	}
+
 	if info.MissingImageRenderer {
 		if err := c.templateRenderInfo.Execute(&buf, info); err != nil {
 			return "", fmt.Errorf("unable to execute template: %w", err)
@@ -101,6 +118,27 @@ and {{ .SkippedFiles }} more files.
 {{ end}}
 `
 
+const commentTemplatePolicyViolations = `
+### Policy violations
+These files break the repository policies, and they will not be applied when the pull request is merged:
+{{- range .Changes}}{{ if .PolicyViolations }}
+- {{.Change.Path}}
+{{- range .PolicyViolations}}
+  - {{.}}
+{{- end}}
+{{- end}}{{ end}}
+`
+
+const commentTemplatePreviewEnvironment = `
+### Preview environment
+Explore the dashboards of this pull request with live data in the [preview folder]({{.PreviewEnvironmentURL}}).
+{{- range .Changes}}{{ if .PreviewEnvironmentURL }}
+- [{{.Title}}]({{.PreviewEnvironmentURL}})
+{{- end}}{{ end}}
+
+The preview folder is read-only, and it is removed when the pull request is merged or closed.
+`
+
 // TODO: this should expand and show links to setup docs
 const commentTemplateMissingImageRenderer = `
 NOTE: The image renderer is not configured
@@ -87,6 +87,64 @@ func TestGenerateComment(t *testing.T) {
 			},
 			MissingImageRenderer: true,
 		}},
+		{"preview environment", changeInfo{
+			GrafanaBaseURL:        "http://host/",
+			PreviewEnvironmentURL: "http://host/dashboards/f/pr-1-abc/",
+			Changes: []fileChangeInfo{
+				{
+					Parsed: &resources.ParsedResource{
+						Info: &repository.FileInfo{
+							Path: "aaa.json",
+						},
+						Action: v0alpha1.ResourceActionCreate,
+						GVK:    schema.GroupVersionKind{Kind: "Dashboard"},
+					},
+					Title:                 "Dash A",
+					PreviewURL:            "http://grafana/admin/preview",
+					PreviewEnvironmentURL: "http://host/d/aaa-abc/dash-a",
+				},
+				{
+					Parsed: &resources.ParsedResource{
+						Info: &repository.FileInfo{
+							Path: "bbb.json",
+						},
+						Action: v0alpha1.ResourceActionCreate,
+						GVK:    schema.GroupVersionKind{Kind: "Playlist"},
+					},
+					Title: "My Playlist",
+				},
+			},
+		}},
+		{"policy violations", changeInfo{
+			GrafanaBaseURL: "http://host/",
+			Changes: []fileChangeInfo{
+				{
+					Change: repository.VersionedFileChange{Path: "aaa.json"},
+					Parsed: &resources.ParsedResource{
+						Info: &repository.FileInfo{
+							Path: "aaa.json",
+						},
+						GVK: schema.GroupVersionKind{Kind: "Dashboard"},
+					},
+					Title:            "Dash A",
+					Error:            "breaks the repository policies: missing required tags: prod; forbidden panel types: text",
+					PolicyViolations: []string{"missing required tags: prod", "forbidden panel types: text"},
+				},
+				{
+					Change: repository.VersionedFileChange{Path: "bbb.json"},
+					Parsed: &resources.ParsedResource{
+						Info: &repository.FileInfo{
+							Path: "bbb.json",
+						},
+						Action: v0alpha1.ResourceActionUpdate,
+						GVK:    schema.GroupVersionKind{Kind: "Dashboard"},
+					},
+					Title:      "Dash B",
+					GrafanaURL: "http://grafana/d/bbb",
+					PreviewURL: "http://grafana/admin/preview",
+				},
+			},
+		}},
 		{"multiple files", changeInfo{
 			GrafanaBaseURL: "http://host/",
 			SkippedFiles:   5,
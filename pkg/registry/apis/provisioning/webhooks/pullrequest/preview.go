@@ -0,0 +1,231 @@
+package pullrequest
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/grafana/grafana-app-sdk/logging"
+	folders "github.com/grafana/grafana/apps/folder/pkg/apis/folder/v1beta1"
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/infra/slugify"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+)
+
+// PreviewEnvironment keeps a read-only folder with the dashboards changed in a pull request,
+// so the reviewers can explore them with live data before the pull request is merged
+//
+//go:generate mockery --name=PreviewEnvironment --structname=MockPreviewEnvironment --inpackage --filename=mock_preview_environment.go --with-expecter
+type PreviewEnvironment interface {
+	// Update saves the evaluated dashboards in the preview folder of the pull request, and sets the preview URLs
+	Update(ctx context.Context, repo *provisioning.Repository, pr int, info *changeInfo) error
+	// Remove deletes the preview folder of the pull request and its dashboards
+	Remove(ctx context.Context, repo *provisioning.Repository, pr int) error
+}
+
+type previewEnvironment struct {
+	clients resources.ClientFactory
+	lister  resources.ResourceLister
+}
+
+func NewPreviewEnvironment(clients resources.ClientFactory, lister resources.ResourceLister) PreviewEnvironment {
+	return &previewEnvironment{
+		clients: clients,
+		lister:  lister,
+	}
+}
+
+// previewIdentity is the manager identity of the resources in the preview environments of the repository.
+// It starts with a prefix the repository names can not have, so the previews are never synced, nor written
+// back to a repository. The preview environment of each pull request is its own folder.
+func previewIdentity(repo *provisioning.Repository) string {
+	return repository.PreviewIdentity(repo.GetName())
+}
+
+func previewFolder(repo *provisioning.Repository, pr int) resources.Folder {
+	return resources.Folder{
+		ID:    resources.PreviewName(fmt.Sprintf("pr-%d", pr), repo.GetName()),
+		Title: fmt.Sprintf("%s: pull request #%d", repo.Spec.Title, pr),
+	}
+}
+
+func (p *previewEnvironment) Update(ctx context.Context, repo *provisioning.Repository, pr int, info *changeInfo) error {
+	clients, err := p.clients.Clients(ctx, repo.GetNamespace())
+	if err != nil {
+		return fmt.Errorf("create clients: %w", err)
+	}
+	folderClient, err := clients.Folder()
+	if err != nil {
+		return fmt.Errorf("create folder client: %w", err)
+	}
+
+	id := previewIdentity(repo)
+	folder := previewFolder(repo, pr)
+	if err := ensurePreviewFolder(ctx, folderClient, repo.GetNamespace(), folder, id); err != nil {
+		return err
+	}
+
+	keep := map[string]bool{folder.ID: true}
+	for i := range info.Changes {
+		change := &info.Changes[i]
+		if change.Error != "" || change.Parsed == nil || change.Parsed.GVK.Kind != dashboardKind {
+			continue
+		}
+
+		name, err := writePreviewDashboard(ctx, change.Parsed, folder.ID, id)
+		if err != nil {
+			change.Error = fmt.Sprintf("failed to write the dashboard in the preview environment: %s", err)
+			continue
+		}
+		keep[name] = true
+		change.PreviewEnvironmentURL = fmt.Sprintf("%sd/%s/%s", info.GrafanaBaseURL, name, slugify.Slugify(change.Title))
+	}
+
+	// The dashboards that are no longer changed in the pull request are removed
+	if err := p.removeResources(ctx, repo.GetNamespace(), id, folder.ID, clients, keep); err != nil {
+		return err
+	}
+
+	info.PreviewEnvironmentURL = fmt.Sprintf("%sdashboards/f/%s/", info.GrafanaBaseURL, folder.ID)
+	return nil
+}
+
+func (p *previewEnvironment) Remove(ctx context.Context, repo *provisioning.Repository, pr int) error {
+	clients, err := p.clients.Clients(ctx, repo.GetNamespace())
+	if err != nil {
+		return fmt.Errorf("create clients: %w", err)
+	}
+
+	return p.removeResources(ctx, repo.GetNamespace(), previewIdentity(repo), previewFolder(repo, pr).ID, clients, nil)
+}
+
+// removeResources deletes the resources of the preview folder that are not kept, the folders last
+func (p *previewEnvironment) removeResources(ctx context.Context, namespace, id, folder string, clients resources.ResourceClients, keep map[string]bool) error {
+	list, err := p.lister.List(ctx, namespace, id)
+	if err != nil {
+		return fmt.Errorf("list preview resources: %w", err)
+	}
+
+	var previewFolders []provisioning.ResourceListItem
+	for _, item := range list.Items {
+		if keep[item.Name] {
+			continue
+		}
+		if item.Name != folder && item.Folder != folder {
+			continue // another pull request
+		}
+		if item.Group == resources.FolderResource.Group {
+			previewFolders = append(previewFolders, item)
+			continue
+		}
+		if err := deletePreviewResource(ctx, clients, item); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range previewFolders {
+		if err := deletePreviewResource(ctx, clients, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deletePreviewResource(ctx context.Context, clients resources.ResourceClients, item provisioning.ResourceListItem) error {
+	client, _, err := clients.ForResource(schema.GroupVersionResource{
+		Group:    item.Group,
+		Resource: item.Resource,
+	})
+	if err != nil {
+		return fmt.Errorf("get client for %s/%s: %w", item.Group, item.Resource, err)
+	}
+
+	if err := client.Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("delete preview resource %s/%s %s: %w", item.Group, item.Resource, item.Name, err)
+	}
+	logging.FromContext(ctx).Debug("removed preview resource", "group", item.Group, "resource", item.Resource, "name", item.Name)
+	return nil
+}
+
+// ensurePreviewFolder creates the preview folder, at the root, if it doesn't exist
+func ensurePreviewFolder(ctx context.Context, client dynamic.ResourceInterface, namespace string, folder resources.Folder, id string) error {
+	obj, err := client.Get(ctx, folder.ID, metav1.GetOptions{})
+	if err == nil {
+		if current := obj.GetAnnotations()[utils.AnnoKeyManagerIdentity]; current != id {
+			return fmt.Errorf("preview folder %s is not managed by the preview environment", folder.ID)
+		}
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to check if preview folder exists: %w", err)
+	}
+
+	obj = &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]any{
+				"title": folder.Title,
+			},
+		},
+	}
+	obj.SetAPIVersion(folders.APIVERSION)
+	obj.SetKind(folders.FolderResourceInfo.GroupVersionKind().Kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(folder.ID)
+
+	meta, err := utils.MetaAccessor(obj)
+	if err != nil {
+		return fmt.Errorf("create meta accessor for the object: %w", err)
+	}
+	meta.SetManagerProperties(utils.ManagerProperties{
+		Kind:     utils.ManagerKindRepo,
+		Identity: id,
+	})
+
+	if _, err := client.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create preview folder: %w", err)
+	}
+	return nil
+}
+
+// writePreviewDashboard saves a copy of the dashboard of the pull request in the preview folder.
+// The copy is managed by the preview environment, so it can't be edited.
+func writePreviewDashboard(ctx context.Context, parsed *resources.ParsedResource, folder, id string) (string, error) {
+	obj := parsed.Obj.DeepCopy()
+	meta, err := utils.MetaAccessor(obj)
+	if err != nil {
+		return "", fmt.Errorf("create meta accessor for the object: %w", err)
+	}
+
+	name := resources.PreviewName(obj.GetName(), folder)
+	obj.SetName(name)
+	meta.SetFolder(folder)
+	meta.SetUID("")
+	meta.SetResourceVersion("")
+	meta.SetManagerProperties(utils.ManagerProperties{
+		Kind:     utils.ManagerKindRepo,
+		Identity: id,
+	})
+	meta.SetSourceProperties(utils.SourceProperties{
+		Path:     parsed.Info.Path,
+		Checksum: parsed.Info.Hash,
+	})
+
+	existing, err := parsed.Client.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		_, err = parsed.Client.Update(ctx, obj, metav1.UpdateOptions{})
+	case apierrors.IsNotFound(err):
+		_, err = parsed.Client.Create(ctx, obj, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,164 @@
+package pullrequest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	provisioning "github.com/grafana/grafana/apps/provisioning/pkg/apis/provisioning/v0alpha1"
+	"github.com/grafana/grafana/apps/provisioning/pkg/repository"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
+)
+
+func TestPreviewEnvironment(t *testing.T) {
+	ctx := context.Background()
+	repo := &provisioning.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-repo", Namespace: "default"},
+		Spec:       provisioning.RepositorySpec{Title: "Test repo"},
+	}
+	folder := previewFolder(repo, 123)
+	dashboardName := resources.PreviewName("changed", folder.ID)
+	otherFolder := previewFolder(repo, 456)
+
+	setup := func(t *testing.T, objects ...runtime.Object) (*previewEnvironment, *dynamicfake.FakeDynamicClient, *resources.MockResourceLister) {
+		fakeClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			resources.DashboardResource: "DashboardList",
+			resources.FolderResource:    "FolderList",
+		}, objects...)
+
+		clients := resources.NewMockResourceClients(t)
+		clients.EXPECT().Folder().Return(fakeClient.Resource(resources.FolderResource).Namespace("default"), nil).Maybe()
+		clients.EXPECT().ForResource(schema.GroupVersionResource{Group: resources.DashboardResource.Group, Resource: resources.DashboardResource.Resource}).
+			Return(fakeClient.Resource(resources.DashboardResource).Namespace("default"), schema.GroupVersionKind{}, nil).Maybe()
+		clients.EXPECT().ForResource(schema.GroupVersionResource{Group: resources.FolderResource.Group, Resource: resources.FolderResource.Resource}).
+			Return(fakeClient.Resource(resources.FolderResource).Namespace("default"), schema.GroupVersionKind{}, nil).Maybe()
+		factory := resources.NewMockClientFactory(t)
+		factory.EXPECT().Clients(ctx, "default").Return(clients, nil)
+
+		lister := resources.NewMockResourceLister(t)
+		return &previewEnvironment{clients: factory, lister: lister}, fakeClient, lister
+	}
+
+	t.Run("the changed dashboards are saved in the preview folder", func(t *testing.T) {
+		p, fakeClient, lister := setup(t, previewDashboard("stale"), previewDashboard("other"))
+		lister.EXPECT().List(ctx, "default", "preview:test-repo").Return(&provisioning.ResourceList{Items: []provisioning.ResourceListItem{
+			{Group: resources.FolderResource.Group, Resource: resources.FolderResource.Resource, Name: folder.ID},
+			{Group: resources.DashboardResource.Group, Resource: resources.DashboardResource.Resource, Name: dashboardName, Folder: folder.ID},
+			{Group: resources.DashboardResource.Group, Resource: resources.DashboardResource.Resource, Name: "stale", Folder: folder.ID},
+			{Group: resources.FolderResource.Group, Resource: resources.FolderResource.Resource, Name: otherFolder.ID},
+			{Group: resources.DashboardResource.Group, Resource: resources.DashboardResource.Resource, Name: "other", Folder: otherFolder.ID},
+		}}, nil)
+
+		dashboards := fakeClient.Resource(resources.DashboardResource).Namespace("default")
+		info := &changeInfo{
+			GrafanaBaseURL: "http://host/",
+			Changes: []fileChangeInfo{
+				{Title: "Changed", Parsed: parsedDashboard(t, "changed", dashboards)},
+				{Title: "Invalid", Parsed: parsedDashboard(t, "invalid", dashboards), Error: "invalid dashboard"},
+			},
+		}
+		require.NoError(t, p.Update(ctx, repo, 123, info))
+
+		require.Equal(t, "http://host/dashboards/f/"+folder.ID+"/", info.PreviewEnvironmentURL)
+		require.Equal(t, "http://host/d/"+dashboardName+"/changed", info.Changes[0].PreviewEnvironmentURL)
+		require.Empty(t, info.Changes[1].PreviewEnvironmentURL)
+
+		created, err := fakeClient.Resource(resources.FolderResource).Namespace("default").Get(ctx, folder.ID, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "preview:test-repo", created.GetAnnotations()[utils.AnnoKeyManagerIdentity])
+
+		dashboard, err := dashboards.Get(ctx, dashboardName, metav1.GetOptions{})
+		require.NoError(t, err)
+		meta, err := utils.MetaAccessor(dashboard)
+		require.NoError(t, err)
+		require.Equal(t, folder.ID, meta.GetFolder())
+		manager, ok := meta.GetManagerProperties()
+		require.True(t, ok)
+		require.Equal(t, utils.ManagerProperties{Kind: utils.ManagerKindRepo, Identity: "preview:test-repo"}, manager)
+
+		_, err = dashboards.Get(ctx, "stale", metav1.GetOptions{})
+		require.Error(t, err, "the dashboards no longer changed are removed")
+		_, err = dashboards.Get(ctx, "changed", metav1.GetOptions{})
+		require.Error(t, err, "the original dashboard is not written")
+		_, err = dashboards.Get(ctx, "other", metav1.GetOptions{})
+		require.NoError(t, err, "the previews of the other pull requests are kept")
+	})
+
+	t.Run("the preview folder and its dashboards are removed", func(t *testing.T) {
+		p, fakeClient, lister := setup(t, previewDashboard(dashboardName), previewFolderObject(folder.ID), previewDashboard("other"), previewFolderObject(otherFolder.ID))
+		lister.EXPECT().List(ctx, "default", "preview:test-repo").Return(&provisioning.ResourceList{Items: []provisioning.ResourceListItem{
+			{Group: resources.FolderResource.Group, Resource: resources.FolderResource.Resource, Name: folder.ID},
+			{Group: resources.DashboardResource.Group, Resource: resources.DashboardResource.Resource, Name: dashboardName, Folder: folder.ID},
+			{Group: resources.DashboardResource.Group, Resource: resources.DashboardResource.Resource, Name: "already-deleted", Folder: folder.ID},
+			{Group: resources.FolderResource.Group, Resource: resources.FolderResource.Resource, Name: otherFolder.ID},
+			{Group: resources.DashboardResource.Group, Resource: resources.DashboardResource.Resource, Name: "other", Folder: otherFolder.ID},
+		}}, nil)
+
+		require.NoError(t, p.Remove(ctx, repo, 123))
+
+		_, err := fakeClient.Resource(resources.DashboardResource).Namespace("default").Get(ctx, dashboardName, metav1.GetOptions{})
+		require.Error(t, err)
+		_, err = fakeClient.Resource(resources.FolderResource).Namespace("default").Get(ctx, folder.ID, metav1.GetOptions{})
+		require.Error(t, err)
+		_, err = fakeClient.Resource(resources.DashboardResource).Namespace("default").Get(ctx, "other", metav1.GetOptions{})
+		require.NoError(t, err, "the previews of the other pull requests are kept")
+		_, err = fakeClient.Resource(resources.FolderResource).Namespace("default").Get(ctx, otherFolder.ID, metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func parsedDashboard(t *testing.T, name string, client dynamic.ResourceInterface) *resources.ParsedResource {
+	t.Helper()
+	obj := previewDashboard(name)
+	meta, err := utils.MetaAccessor(obj)
+	require.NoError(t, err)
+	meta.SetManagerProperties(utils.ManagerProperties{Kind: utils.ManagerKindRepo, Identity: "test-repo"})
+
+	return &resources.ParsedResource{
+		Info:   &repository.FileInfo{Path: name + ".json", Hash: "abc"},
+		Obj:    obj,
+		Meta:   meta,
+		GVK:    schema.GroupVersionKind{Group: resources.DashboardResource.Group, Kind: dashboardKind},
+		Client: client,
+	}
+}
+
+func previewDashboard(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": resources.DashboardResource.GroupVersion().String(),
+		"kind":       dashboardKind,
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "default",
+		},
+		"spec": map[string]any{
+			"title": name,
+		},
+	}}
+}
+
+func previewFolderObject(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": resources.FolderResource.GroupVersion().String(),
+		"kind":       "Folder",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "default",
+			"annotations": map[string]any{
+				utils.AnnoKeyManagerKind:     string(utils.ManagerKindRepo),
+				utils.AnnoKeyManagerIdentity: "preview:test-repo",
+			},
+		},
+		"spec": map[string]any{
+			"title": name,
+		},
+	}}
+}
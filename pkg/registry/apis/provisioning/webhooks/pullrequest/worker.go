@@ -35,12 +35,14 @@ type Commenter interface {
 type PullRequestWorker struct {
 	evaluator Evaluator
 	commenter Commenter
+	previews  PreviewEnvironment // optional, the preview environments are not created without it
 }
 
-func NewPullRequestWorker(evaluator Evaluator, commenter Commenter) *PullRequestWorker {
+func NewPullRequestWorker(evaluator Evaluator, commenter Commenter, previews PreviewEnvironment) *PullRequestWorker {
 	return &PullRequestWorker{
 		evaluator: evaluator,
 		commenter: commenter,
+		previews:  previews,
 	}
 }
 
@@ -68,6 +70,10 @@ func (c *PullRequestWorker) Process(ctx context.Context,
 		return apierrors.NewBadRequest("expecting github configuration")
 	}
 
+	if opts.Closed {
+		return c.removePreview(ctx, repo.Config(), opts.PR, progress)
+	}
+
 	reader, ok := repo.(repository.Reader)
 	if !ok {
 		return errors.New("pull request job submitted targeting repository that is not a Reader")
@@ -92,6 +98,13 @@ func (c *PullRequestWorker) Process(ctx context.Context,
 
 	files = onlySupportedFiles(files)
 	if len(files) == 0 {
+		// The resources previewed for an earlier push are no longer in the pull request
+		if c.previews != nil && cfg.GitHub.GeneratePreviewEnvironments {
+			progress.SetMessage(ctx, "removing preview environment")
+			if err := c.previews.Remove(ctx, repo.Config(), opts.PR); err != nil {
+				logger.Warn("failed to remove preview environment", "error", err)
+			}
+		}
 		progress.SetFinalMessage(ctx, "no files to process")
 		return nil
 	}
@@ -101,6 +114,14 @@ func (c *PullRequestWorker) Process(ctx context.Context,
 		return fmt.Errorf("calculate changes: %w", err)
 	}
 
+	if c.previews != nil && cfg.GitHub.GeneratePreviewEnvironments {
+		progress.SetMessage(ctx, "updating preview environment")
+		// The pull request is still commented when the preview environment fails
+		if err := c.previews.Update(ctx, repo.Config(), opts.PR, &changeInfo); err != nil {
+			logger.Warn("failed to update preview environment", "error", err)
+		}
+	}
+
 	if err := c.commenter.Comment(ctx, prRepo, opts.PR, changeInfo); err != nil {
 		return fmt.Errorf("comment pull request: %w", err)
 	}
@@ -109,6 +130,21 @@ func (c *PullRequestWorker) Process(ctx context.Context,
 	return nil
 }
 
+// removePreview deletes the preview environment of a merged or closed pull request
+func (c *PullRequestWorker) removePreview(ctx context.Context, cfg *provisioning.Repository, pr int, progress jobs.JobProgressRecorder) error {
+	if c.previews == nil || !cfg.Spec.GitHub.GeneratePreviewEnvironments {
+		progress.SetFinalMessage(ctx, "preview environments are not enabled")
+		return nil
+	}
+
+	progress.SetMessage(ctx, "removing preview environment")
+	if err := c.previews.Remove(ctx, cfg, pr); err != nil {
+		return fmt.Errorf("remove preview environment: %w", err)
+	}
+	progress.SetFinalMessage(ctx, "preview environment removed")
+	return nil
+}
+
 // Remove files we should not try to process
 func onlySupportedFiles(files []repository.VersionedFileChange) (ret []repository.VersionedFileChange) {
 	for _, file := range files {
@@ -44,7 +44,7 @@ func TestPullRequestWorker_IsSupported(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			evaluator := NewMockEvaluator(t)
 			commenter := NewMockCommenter(t)
-			worker := NewPullRequestWorker(evaluator, commenter)
+			worker := NewPullRequestWorker(evaluator, commenter, nil)
 			result := worker.IsSupported(context.Background(), tt.job)
 			require.Equal(t, tt.expected, result)
 		})
@@ -68,7 +68,7 @@ func TestPullRequestWorker_Process_NotPullRequestRepository(t *testing.T) {
 		},
 	})
 
-	worker := NewPullRequestWorker(evaluator, commenter)
+	worker := NewPullRequestWorker(evaluator, commenter, nil)
 	job := provisioning.Job{
 		Spec: provisioning.JobSpec{
 			Action: provisioning.JobActionPullRequest,
@@ -106,7 +106,7 @@ func TestPullRequestWorker_Process_NotReaderRepository(t *testing.T) {
 		},
 	})
 
-	worker := NewPullRequestWorker(evaluator, commenter)
+	worker := NewPullRequestWorker(evaluator, commenter, nil)
 	job := provisioning.Job{
 		Spec: provisioning.JobSpec{
 			Action: provisioning.JobActionPullRequest,
@@ -392,7 +392,7 @@ func TestPullRequestWorker_Process(t *testing.T) {
 			progress := jobs.NewMockJobProgressRecorder(t)
 			tt.setupMocks(evaluator, commenter, &repo, progress)
 
-			worker := NewPullRequestWorker(evaluator, commenter)
+			worker := NewPullRequestWorker(evaluator, commenter, nil)
 			job := provisioning.Job{
 				Spec: provisioning.JobSpec{
 					Action:      provisioning.JobActionPullRequest,
@@ -415,6 +415,103 @@ func TestPullRequestWorker_Process(t *testing.T) {
 	}
 }
 
+func TestPullRequestWorker_PreviewEnvironment(t *testing.T) {
+	config := func(enabled bool) *provisioning.Repository {
+		return &provisioning.Repository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-repo",
+			},
+			Spec: provisioning.RepositorySpec{
+				Title:  "test-repo",
+				GitHub: &provisioning.GitHubRepositoryConfig{Branch: "main", GeneratePreviewEnvironments: enabled},
+			},
+		}
+	}
+	setup := func(t *testing.T) (*MockEvaluator, *MockCommenter, *MockPreviewEnvironment, mockPullRequestRepo, *jobs.MockJobProgressRecorder) {
+		repo := mockPullRequestRepo{
+			MockRepository:      repository.NewMockRepository(t),
+			MockPullRequestRepo: NewMockPullRequestRepo(t),
+		}
+		return NewMockEvaluator(t), NewMockCommenter(t), NewMockPreviewEnvironment(t), repo, jobs.NewMockJobProgressRecorder(t)
+	}
+	job := func(closed bool) provisioning.Job {
+		return provisioning.Job{
+			Spec: provisioning.JobSpec{
+				Action:      provisioning.JobActionPullRequest,
+				PullRequest: &provisioning.PullRequestJobOptions{PR: 123, Ref: "test-ref", Closed: closed},
+			},
+		}
+	}
+
+	t.Run("the preview environment is updated before commenting", func(t *testing.T) {
+		evaluator, commenter, previews, repo, progress := setup(t)
+		cfg := config(true)
+		repo.MockRepository.On("Config").Return(cfg)
+		progress.On("SetMessage", mock.Anything, "listing pull request files").Return()
+		progress.On("SetMessage", mock.Anything, "updating preview environment").Return()
+		repo.MockPullRequestRepo.On("CompareFiles", mock.Anything, "main", "test-ref").
+			Return([]repository.VersionedFileChange{{Path: "dashboard.json"}}, nil)
+		evaluator.On("Evaluate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(changeInfo{}, nil)
+		previews.On("Update", mock.Anything, cfg, 123, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(3).(*changeInfo).PreviewEnvironmentURL = "http://host/dashboards/f/preview/"
+		}).Return(nil)
+		commenter.On("Comment", mock.Anything, mock.Anything, 123, changeInfo{PreviewEnvironmentURL: "http://host/dashboards/f/preview/"}).Return(nil)
+
+		err := NewPullRequestWorker(evaluator, commenter, previews).Process(context.Background(), repo, job(false), progress)
+		require.NoError(t, err)
+	})
+
+	t.Run("the pull request is commented when the preview environment fails", func(t *testing.T) {
+		evaluator, commenter, previews, repo, progress := setup(t)
+		repo.MockRepository.On("Config").Return(config(true))
+		progress.On("SetMessage", mock.Anything, mock.Anything).Return()
+		repo.MockPullRequestRepo.On("CompareFiles", mock.Anything, "main", "test-ref").
+			Return([]repository.VersionedFileChange{{Path: "dashboard.json"}}, nil)
+		evaluator.On("Evaluate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(changeInfo{}, nil)
+		previews.On("Update", mock.Anything, mock.Anything, 123, mock.Anything).Return(errors.New("failed"))
+		commenter.On("Comment", mock.Anything, mock.Anything, 123, changeInfo{}).Return(nil)
+
+		err := NewPullRequestWorker(evaluator, commenter, previews).Process(context.Background(), repo, job(false), progress)
+		require.NoError(t, err)
+	})
+
+	t.Run("the preview environment is removed when no supported files are left", func(t *testing.T) {
+		evaluator, commenter, previews, repo, progress := setup(t)
+		cfg := config(true)
+		repo.MockRepository.On("Config").Return(cfg)
+		progress.On("SetMessage", mock.Anything, "listing pull request files").Return()
+		progress.On("SetMessage", mock.Anything, "removing preview environment").Return()
+		progress.On("SetFinalMessage", mock.Anything, "no files to process").Return()
+		repo.MockPullRequestRepo.On("CompareFiles", mock.Anything, "main", "test-ref").
+			Return([]repository.VersionedFileChange{{Path: "README.md"}}, nil)
+		previews.On("Remove", mock.Anything, cfg, 123).Return(nil)
+
+		err := NewPullRequestWorker(evaluator, commenter, previews).Process(context.Background(), repo, job(false), progress)
+		require.NoError(t, err)
+	})
+
+	t.Run("the preview environment is removed when the pull request is closed", func(t *testing.T) {
+		evaluator, commenter, previews, repo, progress := setup(t)
+		cfg := config(true)
+		repo.MockRepository.On("Config").Return(cfg)
+		progress.On("SetMessage", mock.Anything, "removing preview environment").Return()
+		progress.On("SetFinalMessage", mock.Anything, "preview environment removed").Return()
+		previews.On("Remove", mock.Anything, cfg, 123).Return(nil)
+
+		err := NewPullRequestWorker(evaluator, commenter, previews).Process(context.Background(), repo, job(true), progress)
+		require.NoError(t, err)
+	})
+
+	t.Run("nothing is removed when the preview environments are disabled", func(t *testing.T) {
+		evaluator, commenter, previews, repo, progress := setup(t)
+		repo.MockRepository.On("Config").Return(config(false))
+		progress.On("SetFinalMessage", mock.Anything, "preview environments are not enabled").Return()
+
+		err := NewPullRequestWorker(evaluator, commenter, previews).Process(context.Background(), repo, job(true), progress)
+		require.NoError(t, err)
+	})
+}
+
 type mockPullRequestRepo struct {
 	*repository.MockRepository
 	*MockPullRequestRepo
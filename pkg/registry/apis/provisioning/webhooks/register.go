@@ -83,9 +83,10 @@ func ProvideWebhooks(
 				screenshotRenderer,
 			)
 
-			evaluator := pullrequest.NewEvaluator(screenshotRenderer, parsers, urlProvider)
+			evaluator := pullrequest.NewEvaluator(screenshotRenderer, parsers, b.GetAlertingProvisioner(), b.GetLibraryPanelProvisioner(), urlProvider)
 			commenter := pullrequest.NewCommenter()
-			pullRequestWorker := pullrequest.NewPullRequestWorker(evaluator, commenter)
+			previews := pullrequest.NewPreviewEnvironment(clients, b.GetResourceLister())
+			pullRequestWorker := pullrequest.NewPullRequestWorker(evaluator, commenter, previews)
 
 			return NewWebhookExtra(
 				render,
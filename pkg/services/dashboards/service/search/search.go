@@ -130,18 +130,25 @@ func ParseResults(result *resourcepb.ResourceSearchResponse, offset int64) (v0al
 	if result.Facet != nil {
 		sr.Facets = make(map[string]v0alpha1.FacetResult)
 		for k, v := range result.Facet {
-			sr.Facets[k] = v0alpha1.FacetResult{
+			f := v0alpha1.FacetResult{
 				Field:   v.Field,
 				Total:   v.Total,
 				Missing: v.Missing,
 				Terms:   make([]v0alpha1.TermFacet, len(v.Terms)),
 			}
 			for j, t := range v.Terms {
-				sr.Facets[k].Terms[j] = v0alpha1.TermFacet{
+				f.Terms[j] = v0alpha1.TermFacet{
 					Term:  t.Term,
 					Count: t.Count,
 				}
 			}
+			for _, r := range v.Ranges {
+				f.Ranges = append(f.Ranges, v0alpha1.RangeFacet{
+					Name:  r.Name,
+					Count: r.Count,
+				})
+			}
+			sr.Facets[k] = f
 		}
 	}
 
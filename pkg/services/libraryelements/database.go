@@ -618,6 +618,7 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 			ID:          elementInDB.ID,
 			OrgID:       signedInUser.GetOrgID(),
 			FolderID:    cmd.FolderID, // nolint:staticcheck
+			FolderUID:   elementInDB.FolderUID,
 			UID:         updateUID,
 			Name:        cmd.Name,
 			Kind:        elementInDB.Kind,
@@ -637,6 +638,12 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		if cmd.Model == nil {
 			libraryElement.Model = elementInDB.Model
 		}
+		if cmd.FolderUID != nil {
+			libraryElement.FolderUID = *cmd.FolderUID
+			if libraryElement.FolderUID == "" {
+				libraryElement.FolderUID = ac.GeneralFolderUID
+			}
+		}
 		metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
 		// nolint:staticcheck
 		if err := l.handleFolderIDPatches(c, &libraryElement, elementInDB.FolderID, cmd.FolderID, signedInUser); err != nil {
@@ -645,7 +652,8 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		if err := syncFieldsWithModel(&libraryElement); err != nil {
 			return err
 		}
-		if rowsAffected, err := session.ID(elementInDB.ID).Update(&libraryElement); err != nil {
+		// the folder columns are always written, so the element can be moved to the General folder
+		if rowsAffected, err := session.ID(elementInDB.ID).MustCols("folder_id", "folder_uid").Update(&libraryElement); err != nil {
 			if l.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
 				return model.ErrLibraryElementAlreadyExists
 			}
@@ -80,6 +80,49 @@ func (l *LibraryElementService) GetElement(c context.Context, signedInUser ident
 	return libraryElement, nil
 }
 
+func (l *LibraryElementService) PatchElement(c context.Context, signedInUser identity.Requester, cmd model.PatchLibraryElementCommand, uid string) (model.LibraryElementDTO, error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	libraryElement, exists := l.elements[uid]
+	if !exists {
+		return model.LibraryElementDTO{}, model.ErrLibraryElementNotFound
+	}
+	if libraryElement.Version != cmd.Version {
+		return model.LibraryElementDTO{}, model.ErrLibraryElementVersionMismatch
+	}
+
+	if cmd.Name != "" {
+		libraryElement.Name = cmd.Name
+	}
+	if cmd.Model != nil {
+		libraryElement.Model = cmd.Model
+	}
+	if cmd.FolderUID != nil {
+		libraryElement.FolderUID = *cmd.FolderUID
+	}
+	if cmd.FolderID != -1 { //nolint: staticcheck
+		libraryElement.FolderID = cmd.FolderID //nolint: staticcheck
+	}
+	libraryElement.Version++
+
+	l.elements[uid] = libraryElement
+
+	return libraryElement, nil
+}
+
+func (l *LibraryElementService) DeleteElement(c context.Context, signedInUser identity.Requester, uid string) error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if _, exists := l.elements[uid]; !exists {
+		return model.ErrLibraryElementNotFound
+	}
+	delete(l.elements, uid)
+
+	return nil
+}
+
 func (l *LibraryElementService) GetElementsForDashboard(c context.Context, dashboardID int64) (map[string]model.LibraryElementDTO, error) {
 	return map[string]model.LibraryElementDTO{}, nil
 }
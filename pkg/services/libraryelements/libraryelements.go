@@ -41,6 +41,8 @@ func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.Rout
 type Service interface {
 	CreateElement(c context.Context, signedInUser identity.Requester, cmd model.CreateLibraryElementCommand) (model.LibraryElementDTO, error)
 	GetElement(c context.Context, signedInUser identity.Requester, cmd model.GetLibraryElementCommand) (model.LibraryElementDTO, error)
+	PatchElement(c context.Context, signedInUser identity.Requester, cmd model.PatchLibraryElementCommand, uid string) (model.LibraryElementDTO, error)
+	DeleteElement(c context.Context, signedInUser identity.Requester, uid string) error
 	GetElementsForDashboard(c context.Context, dashboardID int64) (map[string]model.LibraryElementDTO, error)
 	ConnectElementsToDashboard(c context.Context, signedInUser identity.Requester, elementUIDs []string, dashboardID int64) error
 	DisconnectElementsFromDashboard(c context.Context, dashboardID int64) error
@@ -73,6 +75,17 @@ func (l *LibraryElementService) GetElement(c context.Context, signedInUser ident
 	return l.getLibraryElementByUid(c, signedInUser, cmd)
 }
 
+// PatchElement updates a Library Element.
+func (l *LibraryElementService) PatchElement(c context.Context, signedInUser identity.Requester, cmd model.PatchLibraryElementCommand, uid string) (model.LibraryElementDTO, error) {
+	return l.patchLibraryElement(c, signedInUser, cmd, uid)
+}
+
+// DeleteElement deletes a Library Element, it fails if the element is connected to any dashboard.
+func (l *LibraryElementService) DeleteElement(c context.Context, signedInUser identity.Requester, uid string) error {
+	_, err := l.deleteLibraryElement(c, signedInUser, uid)
+	return err
+}
+
 // GetElementsForDashboard gets all connected elements for a specific dashboard.
 func (l *LibraryElementService) GetElementsForDashboard(c context.Context, dashboardID int64) (map[string]model.LibraryElementDTO, error) {
 	return l.getElementsForDashboardID(c, dashboardID)
@@ -0,0 +1,84 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// expressionStateHandler creates annotations and Alertmanager alerts for
+// state changes of pipeline expression outputs.
+type expressionStateHandler struct {
+	annotationsRepo annotations.Repository
+	alertNG         *ngalert.AlertNG
+}
+
+var _ pipeline.ExpressionStateHandler = (*expressionStateHandler)(nil)
+
+func (h *expressionStateHandler) Annotate(ctx context.Context, config pipeline.ExpressionAnnotationConfig, change pipeline.ExpressionStateChange) error {
+	if h.annotationsRepo == nil {
+		return errors.New("annotations are not available")
+	}
+	text := fmt.Sprintf("%s: %s", change.Name, change.State)
+	if change.Value != nil {
+		text = fmt.Sprintf("%s (value %g)", text, *change.Value)
+	}
+	if len(change.Labels) > 0 {
+		text = fmt.Sprintf("%s {%s}", text, change.Labels.String())
+	}
+	item := &annotations.Item{
+		OrgID:        change.OrgID,
+		DashboardUID: config.DashboardUID,
+		PanelID:      config.PanelID,
+		Epoch:        change.Time.UnixMilli(),
+		EpochEnd:     change.Time.UnixMilli(),
+		Text:         text,
+		PrevState:    change.PrevState,
+		NewState:     change.State,
+		Tags:         config.Tags,
+	}
+	return h.annotationsRepo.Save(ctx, item)
+}
+
+func (h *expressionStateHandler) Notify(ctx context.Context, change pipeline.ExpressionStateChange, endsAt time.Time) error {
+	if h.alertNG == nil || h.alertNG.MultiOrgAlertmanager == nil {
+		return errors.New("alerting is not available")
+	}
+	am, err := h.alertNG.MultiOrgAlertmanager.AlertmanagerFor(change.OrgID)
+	if err != nil {
+		return err
+	}
+
+	labels := amv2.LabelSet{}
+	for k, v := range change.Labels {
+		labels[k] = v
+	}
+	labels[model.AlertNameLabel] = change.Name
+	labels["grafana_live_channel"] = change.Channel
+
+	annotations := amv2.LabelSet{}
+	if change.Value != nil {
+		annotations["value"] = fmt.Sprintf("%g", *change.Value)
+	}
+
+	return am.PutAlerts(ctx, apimodels.PostableAlerts{
+		PostableAlerts: []amv2.PostableAlert{{
+			Annotations: annotations,
+			StartsAt:    strfmt.DateTime(change.Time),
+			EndsAt:      strfmt.DateTime(endsAt),
+			Alert: amv2.Alert{
+				Labels: labels,
+			},
+		}},
+	})
+}
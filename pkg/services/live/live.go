@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -53,9 +54,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/live/model"
 	"github.com/grafana/grafana/pkg/services/live/orgchannel"
 	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/live/pushlistener"
 	"github.com/grafana/grafana/pkg/services/live/pushws"
 	"github.com/grafana/grafana/pkg/services/live/runstream"
 	"github.com/grafana/grafana/pkg/services/live/survey"
+	"github.com/grafana/grafana/pkg/services/ngalert"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
@@ -85,7 +88,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	dataSourceCache datasources.CacheService, sqlStore db.DB, secretsService secrets.Service,
 	usageStatsService usagestats.Service, queryDataService query.Service, toggles featuremgmt.FeatureToggles,
 	accessControl accesscontrol.AccessControl, dashboardService dashboards.DashboardService, annotationsRepo annotations.Repository,
-	orgService org.Service, configProvider apiserver.RestConfigProvider) (*GrafanaLive, error) {
+	orgService org.Service, configProvider apiserver.RestConfigProvider, alertNG *ngalert.AlertNG) (*GrafanaLive, error) {
 	g := &GrafanaLive{
 		Cfg:                   cfg,
 		Features:              toggles,
@@ -105,6 +108,14 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		usageStatsService: usageStatsService,
 		orgService:        orgService,
 		keyPrefix:         "gf_live",
+		pipelineStorage: newSwitchableStorage(&pipeline.FileStorage{
+			DataPath:       cfg.DataPath,
+			SecretsService: secretsService,
+		}),
+		expressionStateHandler: &expressionStateHandler{
+			annotationsRepo: annotationsRepo,
+			alertNG:         alertNG,
+		},
 	}
 
 	if cfg.LiveHAPrefix != "" {
@@ -179,6 +190,23 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	listenerCfg := pushlistener.Config{
+		GraphiteAddress: cfg.LiveGraphiteListenAddress,
+		GraphiteChannel: cfg.LiveGraphiteChannel,
+		StatsDAddress:   cfg.LiveStatsDListenAddress,
+		StatsDChannel:   cfg.LiveStatsDChannel,
+		FlushInterval:   cfg.LiveStatsDFlushInterval,
+		OrgID:           cfg.LiveListenerOrgID,
+	}
+	if cfg.LivePipelineEnabled {
+		if err := g.initPipeline(node); err != nil {
+			return nil, err
+		}
+		if listenerCfg.Enabled() {
+			g.pushListener = pushlistener.New(listenerCfg, g.Pipeline)
+		}
+	}
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
@@ -502,7 +530,11 @@ type GrafanaLive struct {
 
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
-	pipelineStorage     pipeline.Storage
+	pipelineStorage     *switchableStorage
+	pipelineRuleBuilder *pipeline.StorageRuleBuilder
+	pushListener        *pushlistener.Listener
+
+	expressionStateHandler pipeline.ExpressionStateHandler
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -539,6 +571,30 @@ func (g *GrafanaLive) getStreamPlugin(ctx context.Context, pluginID string) (bac
 	return nil, fmt.Errorf("%s plugin does not implement StreamHandler: %#v", pluginID, plugin)
 }
 
+// initPipeline sets up the Live pipeline with channel rules and write
+// configs read from the data directory, or from the API resources once
+// they are registered.
+func (g *GrafanaLive) initPipeline(node *centrifuge.Node) error {
+	builder := &pipeline.StorageRuleBuilder{
+		Node:                   node,
+		ManagedStream:          g.ManagedStreamRunner,
+		FrameStorage:           pipeline.NewFrameStorage(),
+		Storage:                g.pipelineStorage,
+		ChannelHandlerGetter:   g,
+		SecretsService:         g.SecretsService,
+		RemoteWriteSpoolPath:   filepath.Join(g.Cfg.DataPath, "live", "remote_write"),
+		ExpressionStateHandler: g.expressionStateHandler,
+		ExpressionStates:       pipeline.NewExpressionStates(),
+	}
+	p, err := pipeline.New(pipeline.NewCacheSegmentedTree(builder))
+	if err != nil {
+		return err
+	}
+	g.Pipeline = p
+	g.pipelineRuleBuilder = builder
+	return nil
+}
+
 func (g *GrafanaLive) Run(ctx context.Context) error {
 	eGroup, eCtx := errgroup.WithContext(ctx)
 
@@ -563,6 +619,18 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		})
 	}
 
+	if g.pushListener != nil {
+		eGroup.Go(func() error {
+			return g.pushListener.Run(eCtx)
+		})
+	}
+
+	if g.pipelineRuleBuilder != nil {
+		eGroup.Go(func() error {
+			return g.pipelineRuleBuilder.Run(eCtx)
+		})
+	}
+
 	return eGroup.Wait()
 }
 
@@ -1272,7 +1340,7 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 		Storage:              storage,
 		ChannelHandlerGetter: g,
 	}
-	channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
+	channelRuleGetter := pipeline.NewStaticSegmentedTree(builder)
 	pipe, err := pipeline.New(channelRuleGetter)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Error creating pipeline", err)
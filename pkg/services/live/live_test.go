@@ -351,7 +351,7 @@ func setupLiveService(cfg *setting.Cfg, t *testing.T) (*GrafanaLive, error) {
 		acimpl.ProvideAccessControl(featuremgmt.WithFeatures()),
 		&dashboards.FakeDashboardService{},
 		annotationstest.NewFakeAnnotationsRepo(),
-		nil, nil)
+		nil, nil, nil)
 }
 
 type dummyTransport struct {
@@ -71,14 +71,51 @@ type ConditionalOutputConfig struct {
 }
 
 type RemoteWriteOutputConfig struct {
-	UID                string `json:"uid"`
-	SampleMilliseconds int64  `json:"sampleMilliseconds"`
+	UID                string                  `json:"uid"`
+	SampleMilliseconds int64                   `json:"sampleMilliseconds"`
+	Queue              *RemoteWriteQueueConfig `json:"queue,omitempty"`
+}
+
+// RemoteWriteQueueConfig tunes batching and retries of remote write requests.
+// Zero values mean defaults.
+type RemoteWriteQueueConfig struct {
+	// Shards is a number of concurrent senders. Default 4.
+	Shards int `json:"shards,omitempty"`
+	// MaxSamplesPerSend is a maximum number of samples in a single request. Default 500.
+	MaxSamplesPerSend int `json:"maxSamplesPerSend,omitempty"`
+	// BatchSendDeadlineMilliseconds is the longest time samples wait before being sent. Default 5000.
+	BatchSendDeadlineMilliseconds int64 `json:"batchSendDeadlineMilliseconds,omitempty"`
+	// Capacity is a maximum number of samples pending in memory per shard. Default 10000.
+	Capacity int `json:"capacity,omitempty"`
+	// MaxRetries of a request failed with a recoverable error. Default 5.
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// Spool samples which could not be sent to disk and replay them later.
+	Spool bool `json:"spool,omitempty"`
+	// SpoolMaxBytes limits spool size. Default 256MB.
+	SpoolMaxBytes int64 `json:"spoolMaxBytes,omitempty"`
 }
 
 type LokiOutputConfig struct {
 	UID string `json:"uid"`
 }
 
+type WebhookOutputConfig struct {
+	// UID of write config with webhook endpoint and auth settings.
+	UID string `json:"uid"`
+	// Format of frames in request body: json (default) or arrow.
+	// Data outputs always use json.
+	Format string `json:"format,omitempty"`
+	// BatchSize is a maximum number of entries sent in one request. Default 100.
+	BatchSize int `json:"batchSize,omitempty"`
+	// FlushIntervalMilliseconds is how often a non-full batch is sent. Default 1000.
+	FlushIntervalMilliseconds int64 `json:"flushIntervalMilliseconds,omitempty"`
+	// QueueSize is a maximum number of entries waiting to be sent, entries
+	// are dropped when the queue is full. Default 1000.
+	QueueSize int `json:"queueSize,omitempty"`
+	// MaxRetries of a failed request before the batch is dropped. Default 3.
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
 type MultipleSubscriberConfig struct {
 	Subscribers []SubscriberConfig `json:"subscribers"`
 }
@@ -97,6 +134,7 @@ type DataOutputterConfig struct {
 	Type                     string                    `json:"type" ts_type:"Omit<keyof DataOutputterConfig, 'type'>"`
 	RedirectDataOutputConfig *RedirectDataOutputConfig `json:"redirect,omitempty"`
 	LokiOutputConfig         *LokiOutputConfig         `json:"loki,omitempty"`
+	WebhookOutputConfig      *WebhookOutputConfig      `json:"webhook,omitempty"`
 }
 
 type FrameOutputterConfig struct {
@@ -109,6 +147,8 @@ type FrameOutputterConfig struct {
 	RemoteWriteOutputConfig *RemoteWriteOutputConfig   `json:"remoteWrite,omitempty"`
 	LokiOutputConfig        *LokiOutputConfig          `json:"loki,omitempty"`
 	ChangeLogOutputConfig   *ChangeLogOutputConfig     `json:"changeLog,omitempty"`
+	WebhookOutputConfig     *WebhookOutputConfig       `json:"webhook,omitempty"`
+	ExpressionOutputConfig  *ExpressionOutputConfig    `json:"expression,omitempty"`
 }
 
 type MultipleFrameConditionCheckerConfig struct {
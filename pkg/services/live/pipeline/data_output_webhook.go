@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"context"
+)
+
+// WebhookDataOutput POSTs batched raw data to an HTTP endpoint.
+type WebhookDataOutput struct {
+	writer *webhookWriter
+}
+
+func NewWebhookDataOutput(settings WebhookSettings, config WebhookOutputConfig) *WebhookDataOutput {
+	// Raw data can't be encoded as Arrow.
+	config.Format = WebhookFormatJSON
+	return &WebhookDataOutput{
+		writer: newWebhookWriter(settings, config),
+	}
+}
+
+const DataOutputTypeWebhook = "webhook"
+
+func (out *WebhookDataOutput) Type() string {
+	return DataOutputTypeWebhook
+}
+
+func (out *WebhookDataOutput) OutputData(_ context.Context, vars Vars, data []byte) ([]*ChannelData, error) {
+	if out.writer.settings.Endpoint == "" {
+		logger.Debug("Skip sending to webhook: no url")
+		return nil, nil
+	}
+	// Copy data since it's sent asynchronously.
+	out.writer.enqueue(webhookEntry{channel: vars.Channel, data: append([]byte(nil), data...)})
+	return nil, nil
+}
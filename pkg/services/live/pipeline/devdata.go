@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/util/httpclient"
 
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
 )
 
 type Data struct {
@@ -96,6 +97,26 @@ type DevRuleBuilder struct {
 	ManagedStream        *managedstream.Runner
 	FrameStorage         *FrameStorage
 	ChannelHandlerGetter ChannelHandlerGetter
+
+	remoteWriteQueues remoteWriteQueues
+}
+
+func (f *DevRuleBuilder) remoteWriteQueue() *remotewrite.Queue {
+	endpoint := os.Getenv("GF_LIVE_REMOTE_WRITE_ENDPOINT")
+	if endpoint == "" {
+		return nil
+	}
+	queue, err := f.remoteWriteQueues.get(remotewrite.QueueConfig{
+		Name:     "dev",
+		Endpoint: endpoint,
+		User:     os.Getenv("GF_LIVE_REMOTE_WRITE_USER"),
+		Password: os.Getenv("GF_LIVE_REMOTE_WRITE_PASSWORD"),
+	})
+	if err != nil {
+		logger.Error("Error creating remote write queue", "error", err)
+		return nil
+	}
+	return queue
 }
 
 func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelRule, error) {
@@ -115,11 +136,7 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 			FrameOutputters: []FrameOutputter{
 				NewManagedStreamFrameOutput(f.ManagedStream),
 				NewRemoteWriteFrameOutput(
-					os.Getenv("GF_LIVE_REMOTE_WRITE_ENDPOINT"),
-					&BasicAuth{
-						User:     os.Getenv("GF_LIVE_REMOTE_WRITE_USER"),
-						Password: os.Getenv("GF_LIVE_REMOTE_WRITE_PASSWORD"),
-					},
+					f.remoteWriteQueue(),
 					1000,
 				),
 			},
@@ -216,11 +233,7 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 			FrameOutputters: []FrameOutputter{
 				NewManagedStreamFrameOutput(f.ManagedStream),
 				NewRemoteWriteFrameOutput(
-					os.Getenv("GF_LIVE_REMOTE_WRITE_ENDPOINT"),
-					&BasicAuth{
-						User:     os.Getenv("GF_LIVE_REMOTE_WRITE_USER"),
-						Password: os.Getenv("GF_LIVE_REMOTE_WRITE_PASSWORD"),
-					},
+					f.remoteWriteQueue(),
 					0,
 				),
 			},
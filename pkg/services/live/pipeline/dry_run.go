@@ -0,0 +1,172 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+)
+
+const (
+	DryRunStageDataOutput     = "dataOutput"
+	DryRunStageConverter      = "converter"
+	DryRunStageFrameProcessor = "frameProcessor"
+	DryRunStageFrameOutput    = "frameOutput"
+)
+
+// DryRunStage is a result of a single pipeline stage executed during DryRun.
+type DryRunStage struct {
+	// Stage is one of DryRunStage* constants.
+	Stage string
+	// Type of the pipeline entity.
+	Type string
+	// Channel whose rule the stage belongs to.
+	Channel string
+	// Frames produced by the stage. For outputs these are the frames
+	// the output would receive.
+	Frames []*data.Frame
+	// Error returned by the stage.
+	Error string
+}
+
+// DryRun processes body the same way ProcessInput does and records frames
+// produced on every step. Outputs are never executed since most of them have
+// side effects (remote write, Loki, publishing to subscribers), frames they
+// would receive are recorded instead. Redirect outputs are the exception: they
+// only pass control to another channel rule, so the dry run follows them.
+func (p *Pipeline) DryRun(ctx context.Context, orgID int64, channelID string, body []byte) ([]DryRunStage, error) {
+	rule, ok, err := p.ruleGetter.Get(orgID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no rule found for channel %s", channelID)
+	}
+
+	var stages []DryRunStage
+
+	for _, out := range rule.DataOutputters {
+		stages = append(stages, DryRunStage{
+			Stage:   DryRunStageDataOutput,
+			Type:    out.Type(),
+			Channel: channelID,
+		})
+	}
+
+	if rule.Converter == nil {
+		return stages, nil
+	}
+
+	channelFrames, err := p.DataToChannelFrames(ctx, *rule, orgID, channelID, body)
+	if err != nil {
+		stages = append(stages, DryRunStage{
+			Stage:   DryRunStageConverter,
+			Type:    rule.Converter.Type(),
+			Channel: channelID,
+			Error:   err.Error(),
+		})
+		return stages, nil
+	}
+
+	converted := DryRunStage{
+		Stage:   DryRunStageConverter,
+		Type:    rule.Converter.Type(),
+		Channel: channelID,
+	}
+	for _, cf := range channelFrames {
+		converted.Frames = append(converted.Frames, cf.Frame)
+	}
+	stages = append(stages, converted)
+
+	return p.dryRunChannelFrames(ctx, orgID, channelID, channelFrames, map[string]struct{}{}, stages)
+}
+
+func (p *Pipeline) dryRunChannelFrames(ctx context.Context, orgID int64, channelID string, channelFrames []*ChannelFrame, visitedChannels map[string]struct{}, stages []DryRunStage) ([]DryRunStage, error) {
+	for _, channelFrame := range channelFrames {
+		var processorChannel = channelID
+		if channelFrame.Channel != "" {
+			processorChannel = channelFrame.Channel
+		}
+		if _, ok := visitedChannels[processorChannel]; ok {
+			return nil, fmt.Errorf("%w: %s", errChannelRecursion, processorChannel)
+		}
+		visitedChannels[processorChannel] = struct{}{}
+
+		var (
+			frames []*ChannelFrame
+			err    error
+		)
+		frames, stages, err = p.dryRunFrame(ctx, orgID, processorChannel, channelFrame.Frame, stages)
+		if err != nil {
+			return nil, err
+		}
+		if len(frames) > 0 {
+			stages, err = p.dryRunChannelFrames(ctx, orgID, processorChannel, frames, visitedChannels, stages)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stages, nil
+}
+
+func (p *Pipeline) dryRunFrame(ctx context.Context, orgID int64, channelID string, frame *data.Frame, stages []DryRunStage) ([]*ChannelFrame, []DryRunStage, error) {
+	rule, ok, err := p.ruleGetter.Get(orgID, channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, stages, nil
+	}
+
+	ch, err := live.ParseChannel(channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vars := Vars{
+		OrgID:     orgID,
+		Channel:   channelID,
+		Scope:     ch.Scope,
+		Namespace: ch.Namespace,
+		Path:      ch.Path,
+	}
+
+	for _, proc := range rule.FrameProcessors {
+		stage := DryRunStage{
+			Stage:   DryRunStageFrameProcessor,
+			Type:    proc.Type(),
+			Channel: channelID,
+		}
+		frame, err = proc.ProcessFrame(ctx, vars, frame)
+		if err != nil {
+			stage.Error = err.Error()
+			return nil, append(stages, stage), nil
+		}
+		if frame == nil {
+			return nil, append(stages, stage), nil
+		}
+		stage.Frames = []*data.Frame{frame}
+		stages = append(stages, stage)
+	}
+
+	var resultingFrames []*ChannelFrame
+	for _, out := range rule.FrameOutputters {
+		stage := DryRunStage{
+			Stage:   DryRunStageFrameOutput,
+			Type:    out.Type(),
+			Channel: channelID,
+			Frames:  []*data.Frame{frame},
+		}
+		if out.Type() == FrameOutputTypeRedirect {
+			frames, err := out.OutputFrame(ctx, vars, frame)
+			if err != nil {
+				stage.Error = err.Error()
+			}
+			resultingFrames = append(resultingFrames, frames...)
+		}
+		stages = append(stages, stage)
+	}
+	return resultingFrames, stages, nil
+}
@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline_DryRun(t *testing.T) {
+	outputter := &testOutputter{}
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/test/xxx": {
+				Converter:       &testConverter{"", data.NewFrame("test")},
+				FrameProcessors: []FrameProcessor{&testProcessor{}},
+				FrameOutputters: []FrameOutputter{
+					outputter,
+					NewRedirectFrameOutput(RedirectOutputConfig{
+						Channel: "stream/test/yyy",
+					}),
+				},
+			},
+			"stream/test/yyy": {
+				FrameOutputters: []FrameOutputter{outputter},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	stages, err := p.DryRun(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
+	require.NoError(t, err)
+	require.Nil(t, outputter.frame, "outputs must not be executed")
+
+	type stageKey struct {
+		stage, typ, channel string
+	}
+	var keys []stageKey
+	for _, s := range stages {
+		require.Empty(t, s.Error)
+		require.Len(t, s.Frames, 1)
+		keys = append(keys, stageKey{s.Stage, s.Type, s.Channel})
+	}
+	require.Equal(t, []stageKey{
+		{DryRunStageConverter, "test", "stream/test/xxx"},
+		{DryRunStageFrameProcessor, "test", "stream/test/xxx"},
+		{DryRunStageFrameOutput, "test", "stream/test/xxx"},
+		{DryRunStageFrameOutput, FrameOutputTypeRedirect, "stream/test/xxx"},
+		{DryRunStageFrameOutput, "test", "stream/test/yyy"},
+	}, keys)
+}
+
+func TestPipeline_DryRunNoRule(t *testing.T) {
+	p, err := New(&testRuleGetter{})
+	require.NoError(t, err)
+	_, err = p.DryRun(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
+	require.Error(t, err)
+}
+
+func TestPipeline_DryRunRecursion(t *testing.T) {
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/test/xxx": {
+				Converter: &testConverter{"", data.NewFrame("test")},
+				FrameOutputters: []FrameOutputter{
+					NewRedirectFrameOutput(RedirectOutputConfig{
+						Channel: "stream/test/yyy",
+					}),
+				},
+			},
+			"stream/test/yyy": {
+				FrameOutputters: []FrameOutputter{
+					NewRedirectFrameOutput(RedirectOutputConfig{
+						Channel: "stream/test/xxx",
+					}),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = p.DryRun(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
+	require.ErrorIs(t, err, errChannelRecursion)
+}
@@ -0,0 +1,454 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const (
+	ExpressionStateNormal   = "Normal"
+	ExpressionStateAlerting = "Alerting"
+)
+
+const (
+	defaultExpressionWindow = time.Second
+	// expressionResendDelay is how often an alert which is still firing is
+	// sent to Alertmanager again, so it does not get resolved by timeout.
+	expressionResendDelay = time.Minute
+	// expressionWindowTTL is how long the state of a channel which does not
+	// receive data anymore is kept.
+	expressionWindowTTL = 10 * time.Minute
+)
+
+type ExpressionOutputConfig struct {
+	// Expression in server-side expressions math syntax. Numeric fields of
+	// incoming frames reduced over the window are available as variables
+	// named by field, e.g. "$temperature > 80". Non-zero result means alerting.
+	Expression string `json:"expression"`
+	// Reducer applied to fields over the window: sum, mean, min, max, count,
+	// last or median. Default last.
+	Reducer string `json:"reducer,omitempty"`
+	// WindowMilliseconds is the size of sliding window. Default 1000.
+	WindowMilliseconds int64 `json:"windowMilliseconds,omitempty"`
+	// Name of the alert. Defaults to channel.
+	Name string `json:"name,omitempty"`
+	// Labels added to alert notifications.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Channel to output state changes to, optional.
+	Channel string `json:"channel,omitempty"`
+	// Annotation creates annotations on state changes when set.
+	Annotation *ExpressionAnnotationConfig `json:"annotation,omitempty"`
+	// Notify sends alerts to Grafana Alertmanager of the organization.
+	Notify bool `json:"notify,omitempty"`
+}
+
+type ExpressionAnnotationConfig struct {
+	// DashboardUID to attach annotations to, organization wide annotations are created if empty.
+	DashboardUID string `json:"dashboardUID,omitempty"`
+	PanelID      int64  `json:"panelId,omitempty"`
+	// Tags added to annotations.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ExpressionStateChange is emitted when expression result changes state
+// for a set of labels.
+type ExpressionStateChange struct {
+	OrgID   int64
+	Channel string
+	Name    string
+	// State is one of ExpressionState* constants.
+	State     string
+	PrevState string
+	Value     *float64
+	Labels    data.Labels
+	Time      time.Time
+	// Resend is set when notification is repeated for a firing alert.
+	Resend bool
+}
+
+// ExpressionStateHandler handles expression state changes outside of Live.
+type ExpressionStateHandler interface {
+	Annotate(ctx context.Context, config ExpressionAnnotationConfig, change ExpressionStateChange) error
+	Notify(ctx context.Context, change ExpressionStateChange, endsAt time.Time) error
+}
+
+// ExpressionOutput evaluates a math expression over a sliding window of frames
+// and reports state changes.
+type ExpressionOutput struct {
+	config  ExpressionOutputConfig
+	key     string
+	expr    *mathexp.Expr
+	reducer mathexp.ReducerID
+	window  time.Duration
+	handler ExpressionStateHandler
+	states  *ExpressionStates
+	tracer  tracing.Tracer
+	now     func() time.Time
+}
+
+// ExpressionStates keeps the windows and alert states of expression outputs
+// by org, channel and output. Channel rules are rebuilt periodically, keeping
+// the state outside of outputs makes sure an alert is not reported again and
+// its recovery is not missed after a rebuild.
+type ExpressionStates struct {
+	mu        sync.Mutex
+	windows   map[expressionWindowKey]*expressionWindow
+	lastPrune time.Time
+}
+
+type expressionWindowKey struct {
+	orgID   int64
+	channel string
+	// output is the configuration of the output, so changing the expression starts over.
+	output string
+}
+
+// expressionWindow is the state of an expression output for a single channel.
+type expressionWindow struct {
+	mu       sync.Mutex
+	series   map[string]*expressionSeries
+	states   map[string]*expressionState
+	lastUsed time.Time
+}
+
+func NewExpressionStates() *ExpressionStates {
+	return &ExpressionStates{
+		windows: map[expressionWindowKey]*expressionWindow{},
+	}
+}
+
+func (s *ExpressionStates) get(key expressionWindowKey, now time.Time) *expressionWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastPrune) >= expressionResendDelay {
+		for k, w := range s.windows {
+			if now.Sub(w.lastUsed) >= expressionWindowTTL {
+				delete(s.windows, k)
+			}
+		}
+		s.lastPrune = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok {
+		w = &expressionWindow{
+			series: map[string]*expressionSeries{},
+			states: map[string]*expressionState{},
+		}
+		s.windows[key] = w
+	}
+	w.lastUsed = now
+	return w
+}
+
+type expressionSeries struct {
+	name   string
+	labels data.Labels
+	times  []time.Time
+	values []*float64
+}
+
+type expressionState struct {
+	state    string
+	lastSent time.Time
+}
+
+// NewExpressionOutput creates an expression output keeping its state in states.
+// A new state store is used when states is nil.
+func NewExpressionOutput(handler ExpressionStateHandler, states *ExpressionStates, config ExpressionOutputConfig) (*ExpressionOutput, error) {
+	if config.Expression == "" {
+		return nil, errors.New("expression is required")
+	}
+	key, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if states == nil {
+		states = NewExpressionStates()
+	}
+	e, err := mathexp.New(config.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	reducer := mathexp.ReducerLast
+	if config.Reducer != "" {
+		reducer = mathexp.ReducerID(config.Reducer)
+	}
+	if _, err := mathexp.GetReduceFunc(reducer); err != nil {
+		return nil, err
+	}
+	window := defaultExpressionWindow
+	if config.WindowMilliseconds > 0 {
+		window = time.Duration(config.WindowMilliseconds) * time.Millisecond
+	}
+	return &ExpressionOutput{
+		config:  config,
+		key:     string(key),
+		expr:    e,
+		reducer: reducer,
+		window:  window,
+		handler: handler,
+		states:  states,
+		tracer:  tracing.NewNoopTracerService(),
+		now:     time.Now,
+	}, nil
+}
+
+const FrameOutputTypeExpression = "expression"
+
+func (out *ExpressionOutput) Type() string {
+	return FrameOutputTypeExpression
+}
+
+func (out *ExpressionOutput) OutputFrame(ctx context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	if frame == nil {
+		return nil, nil
+	}
+	now := out.now()
+
+	w := out.states.get(expressionWindowKey{orgID: vars.OrgID, channel: vars.Channel, output: out.key}, now)
+	w.mu.Lock()
+	latest := w.appendFrame(frame, now)
+	// Window follows data time, so frames with delayed timestamps are
+	// evaluated the same way as real-time ones.
+	w.trim(latest.Add(-out.window))
+	results, err := out.evaluate(w)
+	if err != nil {
+		w.mu.Unlock()
+		return nil, err
+	}
+	changes := out.updateStates(w, vars, results, now)
+	w.mu.Unlock()
+
+	for _, change := range changes {
+		if out.handler == nil {
+			// Rules built for a dry run have no handler.
+			break
+		}
+		if out.config.Annotation != nil && !change.Resend {
+			if err := out.handler.Annotate(ctx, *out.config.Annotation, change); err != nil {
+				logger.Error("Error creating expression annotation", "channel", vars.Channel, "error", err)
+			}
+		}
+		if out.config.Notify {
+			endsAt := change.Time
+			if change.State == ExpressionStateAlerting {
+				endsAt = change.Time.Add(4 * expressionResendDelay)
+			}
+			if err := out.handler.Notify(ctx, change, endsAt); err != nil {
+				logger.Error("Error sending expression notification", "channel", vars.Channel, "error", err)
+			}
+		}
+	}
+
+	if out.config.Channel == "" {
+		return nil, nil
+	}
+	var stateChanges []ExpressionStateChange
+	for _, change := range changes {
+		if !change.Resend {
+			stateChanges = append(stateChanges, change)
+		}
+	}
+	if len(stateChanges) == 0 {
+		return nil, nil
+	}
+	return []*ChannelFrame{{Channel: out.config.Channel, Frame: expressionStateFrame(stateChanges)}}, nil
+}
+
+// appendFrame adds numeric fields of frame to the window and returns the
+// latest point time. Frames without time field are considered to be produced now.
+func (w *expressionWindow) appendFrame(frame *data.Frame, now time.Time) time.Time {
+	var latest time.Time
+	timeIndex, hasTime := -1, false
+	for i, f := range frame.Fields {
+		if f.Type().Time() {
+			timeIndex, hasTime = i, true
+			break
+		}
+	}
+	var rowLabels []data.Labels
+	if len(frame.Fields) > 0 && frame.Fields[0].Name == "labels" && frame.Fields[0].Type() == data.FieldTypeString {
+		rowLabels = make([]data.Labels, frame.Fields[0].Len())
+		for i := range rowLabels {
+			rowLabels[i] = parseLabelsColumn(frame.Fields[0].At(i).(string))
+		}
+	}
+
+	for _, f := range frame.Fields {
+		if !f.Type().Numeric() {
+			continue
+		}
+		for i := 0; i < f.Len(); i++ {
+			value, err := f.NullableFloatAt(i)
+			if err != nil {
+				continue
+			}
+			t := now
+			if hasTime {
+				if tm, ok := frame.Fields[timeIndex].ConcreteAt(i); ok {
+					t = tm.(time.Time)
+				}
+			}
+			labels := f.Labels
+			if rowLabels != nil {
+				labels = rowLabels[i]
+			}
+			key := f.Name + "{" + labels.String() + "}"
+			s, ok := w.series[key]
+			if !ok {
+				s = &expressionSeries{name: f.Name, labels: labels.Copy()}
+				w.series[key] = s
+			}
+			s.times = append(s.times, t)
+			s.values = append(s.values, value)
+			if t.After(latest) {
+				latest = t
+			}
+		}
+	}
+	if latest.IsZero() {
+		return now
+	}
+	return latest
+}
+
+func parseLabelsColumn(s string) data.Labels {
+	labels := data.Labels{}
+	for _, part := range strings.Split(s, ", ") {
+		k, v, ok := strings.Cut(part, "=")
+		if ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// trim removes the points before from.
+func (w *expressionWindow) trim(from time.Time) {
+	for key, s := range w.series {
+		n := 0
+		for n < len(s.times) && s.times[n].Before(from) {
+			n++
+		}
+		s.times = s.times[n:]
+		s.values = s.values[n:]
+		if len(s.times) == 0 {
+			delete(w.series, key)
+		}
+	}
+}
+
+func (out *ExpressionOutput) evaluate(w *expressionWindow) (mathexp.Results, error) {
+	vars := mathexp.Vars{}
+	for _, s := range w.series {
+		series := mathexp.NewSeries(s.name, s.labels, len(s.times))
+		for i := range s.times {
+			series.SetPoint(i, s.times[i], s.values[i])
+		}
+		number, err := series.Reduce(s.name, out.reducer, nil)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		results := vars[s.name]
+		results.Values = append(results.Values, number)
+		vars[s.name] = results
+	}
+	if len(vars) == 0 {
+		return mathexp.Results{}, nil
+	}
+	return out.expr.Execute("", vars, out.tracer)
+}
+
+// updateStates compares results with known states. Label sets missing from
+// results, e.g. because their points left the window, keep their state.
+func (out *ExpressionOutput) updateStates(w *expressionWindow, vars Vars, results mathexp.Results, now time.Time) []ExpressionStateChange {
+	name := out.config.Name
+	if name == "" {
+		name = vars.Channel
+	}
+
+	var changes []ExpressionStateChange
+	for _, value := range results.Values {
+		var v *float64
+		switch r := value.(type) {
+		case mathexp.Number:
+			v = r.GetFloat64Value()
+		case mathexp.Scalar:
+			v = r.GetFloat64Value()
+		default:
+			continue
+		}
+		state := ExpressionStateNormal
+		if v != nil && *v != 0 {
+			state = ExpressionStateAlerting
+		}
+
+		labels := data.Labels{}
+		for k, lv := range out.config.Labels {
+			labels[k] = lv
+		}
+		for k, lv := range value.GetLabels() {
+			labels[k] = lv
+		}
+		key := labels.String()
+
+		prev, ok := w.states[key]
+		if !ok {
+			// Start in Normal, so the first alerting result is reported
+			// but the first normal one is not.
+			prev = &expressionState{state: ExpressionStateNormal}
+			w.states[key] = prev
+		}
+		change := ExpressionStateChange{
+			OrgID:     vars.OrgID,
+			Channel:   vars.Channel,
+			Name:      name,
+			State:     state,
+			PrevState: prev.state,
+			Value:     v,
+			Labels:    labels,
+			Time:      now,
+		}
+		switch {
+		case prev.state != state:
+		case state == ExpressionStateAlerting && now.Sub(prev.lastSent) >= expressionResendDelay:
+			change.Resend = true
+		default:
+			continue
+		}
+		prev.state = state
+		prev.lastSent = now
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func expressionStateFrame(changes []ExpressionStateChange) *data.Frame {
+	fTime := data.NewFieldFromFieldType(data.FieldTypeTime, 0)
+	fTime.Name = "time"
+	fValue := data.NewFieldFromFieldType(data.FieldTypeNullableFloat64, 0)
+	fValue.Name = "value"
+	fState := data.NewFieldFromFieldType(data.FieldTypeString, 0)
+	fState.Name = "state"
+	fLabels := data.NewFieldFromFieldType(data.FieldTypeString, 0)
+	fLabels.Name = "labels"
+	for _, change := range changes {
+		fTime.Append(change.Time)
+		fValue.Append(change.Value)
+		fState.Append(change.State)
+		fLabels.Append(change.Labels.String())
+	}
+	return data.NewFrame("state", fTime, fValue, fState, fLabels)
+}
@@ -0,0 +1,176 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+type testExpressionHandler struct {
+	annotations   []ExpressionStateChange
+	notifications []ExpressionStateChange
+}
+
+func (h *testExpressionHandler) Annotate(_ context.Context, _ ExpressionAnnotationConfig, change ExpressionStateChange) error {
+	h.annotations = append(h.annotations, change)
+	return nil
+}
+
+func (h *testExpressionHandler) Notify(_ context.Context, change ExpressionStateChange, _ time.Time) error {
+	h.notifications = append(h.notifications, change)
+	return nil
+}
+
+func testExpressionFrame(t time.Time, value float64) *data.Frame {
+	return data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{t}),
+		data.NewField("value", nil, []float64{value}),
+	)
+}
+
+func TestNewExpressionOutput_Invalid(t *testing.T) {
+	_, err := NewExpressionOutput(nil, nil, ExpressionOutputConfig{})
+	require.Error(t, err)
+	_, err = NewExpressionOutput(nil, nil, ExpressionOutputConfig{Expression: "$value >"})
+	require.Error(t, err)
+	_, err = NewExpressionOutput(nil, nil, ExpressionOutputConfig{Expression: "$value > 1", Reducer: "unknown"})
+	require.Error(t, err)
+}
+
+func TestExpressionOutput_StateChanges(t *testing.T) {
+	handler := &testExpressionHandler{}
+	out, err := NewExpressionOutput(handler, nil, ExpressionOutputConfig{
+		Expression:         "$value > 80",
+		Reducer:            "mean",
+		WindowMilliseconds: 2000,
+		Channel:            "stream/test/state",
+		Annotation:         &ExpressionAnnotationConfig{},
+		Notify:             true,
+		Labels:             map[string]string{"severity": "critical"},
+	})
+	require.NoError(t, err)
+	now := time.Unix(100, 0)
+	out.now = func() time.Time { return now }
+	vars := Vars{OrgID: 1, Channel: "stream/test/x"}
+
+	// Initial normal result is not reported.
+	frames, err := out.OutputFrame(context.Background(), vars, testExpressionFrame(now, 50))
+	require.NoError(t, err)
+	require.Empty(t, frames)
+
+	// Mean over the window is (50+150)/2 = 100.
+	now = now.Add(time.Second)
+	frames, err = out.OutputFrame(context.Background(), vars, testExpressionFrame(now, 150))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	require.Equal(t, "stream/test/state", frames[0].Channel)
+	require.Equal(t, ExpressionStateAlerting, frames[0].Frame.Fields[2].At(0))
+	require.Len(t, handler.annotations, 1)
+	require.Len(t, handler.notifications, 1)
+	change := handler.notifications[0]
+	require.Equal(t, ExpressionStateNormal, change.PrevState)
+	require.Equal(t, "stream/test/x", change.Name)
+	require.Equal(t, int64(1), change.OrgID)
+	require.Equal(t, "critical", change.Labels["severity"])
+	require.Equal(t, float64(1), *change.Value)
+
+	// Still alerting: no new state frame and no resend before the delay.
+	now = now.Add(time.Second)
+	frames, err = out.OutputFrame(context.Background(), vars, testExpressionFrame(now, 100))
+	require.NoError(t, err)
+	require.Empty(t, frames)
+	require.Len(t, handler.notifications, 1)
+
+	// Firing alert is re-sent to Alertmanager but not annotated again.
+	now = now.Add(expressionResendDelay)
+	_, err = out.OutputFrame(context.Background(), vars, testExpressionFrame(now, 100))
+	require.NoError(t, err)
+	require.Len(t, handler.notifications, 2)
+	require.True(t, handler.notifications[1].Resend)
+	require.Len(t, handler.annotations, 1)
+
+	// Old points left the window, so only the last value counts.
+	now = now.Add(time.Second)
+	frames, err = out.OutputFrame(context.Background(), vars, testExpressionFrame(now, 10))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	require.Equal(t, ExpressionStateNormal, frames[0].Frame.Fields[2].At(0))
+	require.Len(t, handler.annotations, 2)
+	require.Equal(t, ExpressionStateAlerting, handler.annotations[1].PrevState)
+}
+
+func TestExpressionOutput_LabelsColumn(t *testing.T) {
+	handler := &testExpressionHandler{}
+	out, err := NewExpressionOutput(handler, nil, ExpressionOutputConfig{
+		Expression: "$value > 80",
+		Notify:     true,
+	})
+	require.NoError(t, err)
+	now := time.Unix(100, 0)
+	out.now = func() time.Time { return now }
+
+	frame := data.NewFrame("test",
+		data.NewField("labels", nil, []string{"host=a", "host=b"}),
+		data.NewField("time", nil, []time.Time{now, now}),
+		data.NewField("value", nil, []float64{90, 10}),
+	)
+	_, err = out.OutputFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/x"}, frame)
+	require.NoError(t, err)
+	require.Len(t, handler.notifications, 1)
+	require.Equal(t, "a", handler.notifications[0].Labels["host"])
+}
+
+func TestExpressionOutput_NoHandler(t *testing.T) {
+	out, err := NewExpressionOutput(nil, nil, ExpressionOutputConfig{
+		Expression: "$value > 80",
+		Channel:    "stream/test/state",
+		Notify:     true,
+	})
+	require.NoError(t, err)
+	frames, err := out.OutputFrame(context.Background(), Vars{Channel: "stream/test/x"}, testExpressionFrame(time.Now(), 100))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+}
+
+func TestExpressionOutput_StateKeptAcrossRebuilds(t *testing.T) {
+	handler := &testExpressionHandler{}
+	states := NewExpressionStates()
+	config := ExpressionOutputConfig{
+		Expression: "$value > 80",
+		Annotation: &ExpressionAnnotationConfig{},
+	}
+	now := time.Unix(100, 0)
+	vars := Vars{OrgID: 1, Channel: "stream/test/x"}
+	output := func() *ExpressionOutput {
+		out, err := NewExpressionOutput(handler, states, config)
+		require.NoError(t, err)
+		out.now = func() time.Time { return now }
+		return out
+	}
+
+	_, err := output().OutputFrame(context.Background(), vars, testExpressionFrame(now, 90))
+	require.NoError(t, err)
+	require.Len(t, handler.annotations, 1)
+
+	// The rule was rebuilt, the alert is still firing and is not annotated again
+	now = now.Add(time.Second)
+	_, err = output().OutputFrame(context.Background(), vars, testExpressionFrame(now, 95))
+	require.NoError(t, err)
+	require.Len(t, handler.annotations, 1)
+
+	// The recovery is reported after another rebuild
+	now = now.Add(time.Second)
+	_, err = output().OutputFrame(context.Background(), vars, testExpressionFrame(now, 10))
+	require.NoError(t, err)
+	require.Len(t, handler.annotations, 2)
+	require.Equal(t, ExpressionStateNormal, handler.annotations[1].State)
+
+	// Other channels matching the same rule have their own state
+	_, err = output().OutputFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/y"}, testExpressionFrame(now, 90))
+	require.NoError(t, err)
+	require.Len(t, handler.annotations, 3)
+	require.Equal(t, "stream/test/y", handler.annotations[2].Channel)
+}
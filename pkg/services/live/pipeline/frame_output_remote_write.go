@@ -1,11 +1,7 @@
 package pipeline
 
 import (
-	"bytes"
 	"context"
-	"errors"
-	"fmt"
-	"net/http"
 	"sync"
 	"time"
 
@@ -20,12 +16,6 @@ const flushInterval = 15 * time.Second
 type RemoteWriteFrameOutput struct {
 	mu sync.Mutex
 
-	// Endpoint to send streaming frames to.
-	Endpoint string
-
-	// BasicAuth is an optional basic auth params.
-	BasicAuth *BasicAuth
-
 	// SampleMilliseconds allow defining an interval to sample points inside a channel
 	// when outputting to remote write endpoint (on __name__ label basis). For example
 	// when having a 20Hz stream and SampleMilliseconds 1000 then only one point in a
@@ -35,18 +25,19 @@ type RemoteWriteFrameOutput struct {
 	// track of timestamps in terms of each individual flush at the moment.
 	SampleMilliseconds int64
 
-	httpClient *http.Client
-	buffer     []prompb.TimeSeries
+	// queue batches, retries and optionally spools samples sent to endpoint.
+	queue  *remotewrite.Queue
+	buffer []prompb.TimeSeries
 }
 
-func NewRemoteWriteFrameOutput(endpoint string, basicAuth *BasicAuth, sampleMilliseconds int64) *RemoteWriteFrameOutput {
+func NewRemoteWriteFrameOutput(queue *remotewrite.Queue, sampleMilliseconds int64) *RemoteWriteFrameOutput {
 	out := &RemoteWriteFrameOutput{
-		Endpoint:           endpoint,
-		BasicAuth:          basicAuth,
 		SampleMilliseconds: sampleMilliseconds,
-		httpClient:         &http.Client{Timeout: 2 * time.Second},
+		queue:              queue,
 	}
-	if out.Endpoint != "" {
+	if out.queue != nil && out.SampleMilliseconds > 0 {
+		// Down-sampling needs points of several frames, so they are buffered
+		// before being passed to the queue.
 		go out.flushPeriodically()
 	}
 	return out
@@ -65,19 +56,13 @@ func (out *RemoteWriteFrameOutput) flushPeriodically() {
 			out.mu.Unlock()
 			continue
 		}
-		tmpBuffer := make([]prompb.TimeSeries, len(out.buffer))
-		copy(tmpBuffer, out.buffer)
+		tmpBuffer := out.buffer
 		out.buffer = nil
 		out.mu.Unlock()
 
-		err := out.flush(tmpBuffer)
-		if err != nil {
-			logger.Error("Error flush to remote write", "error", err)
-			out.mu.Lock()
-			// TODO: drop in case of large buffer size? Make several attempts only?
-			out.buffer = append(tmpBuffer, out.buffer...)
-			out.mu.Unlock()
-		}
+		timeSeries := out.sample(tmpBuffer)
+		logger.Debug("Remote write down-sampling", "numTimeSeries", len(tmpBuffer), "numSampledTimeSeries", len(timeSeries))
+		out.queue.Append(timeSeries)
 	}
 }
 
@@ -126,59 +111,18 @@ func (out *RemoteWriteFrameOutput) sample(timeSeries []prompb.TimeSeries) []prom
 	return toReturn
 }
 
-func (out *RemoteWriteFrameOutput) flush(timeSeries []prompb.TimeSeries) error {
-	numSamples := 0
-	for _, ts := range timeSeries {
-		numSamples += len(ts.Samples)
-	}
-	logger.Debug("Remote write flush", "numTimeSeries", len(timeSeries), "numSamples", numSamples)
-
-	if out.SampleMilliseconds > 0 {
-		timeSeries = out.sample(timeSeries)
-		numSamples = 0
-		for _, ts := range timeSeries {
-			numSamples += len(ts.Samples)
-		}
-		logger.Debug("After down-sampling", "numTimeSeries", len(timeSeries), "numSamples", numSamples)
-	}
-	remoteWriteData, err := remotewrite.TimeSeriesToBytes(timeSeries)
-	if err != nil {
-		return fmt.Errorf("error converting time series to bytes: %v", err)
-	}
-	logger.Debug("Sending to remote write endpoint", "url", out.Endpoint, "bodyLength", len(remoteWriteData))
-	req, err := http.NewRequest(http.MethodPost, out.Endpoint, bytes.NewReader(remoteWriteData))
-	if err != nil {
-		return fmt.Errorf("error constructing remote write request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/x-protobuf")
-	req.Header.Set("Content-Encoding", "snappy")
-	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
-	if out.BasicAuth != nil {
-		req.SetBasicAuth(out.BasicAuth.User, out.BasicAuth.Password)
-	}
-
-	started := time.Now()
-	resp, err := out.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error sending remote write request: %w", err)
-	}
-	_ = resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		logger.Error("Unexpected response code from remote write endpoint", "code", resp.StatusCode)
-		return errors.New("unexpected response code from remote write endpoint")
-	}
-	logger.Debug("Successfully sent to remote write endpoint", "url", out.Endpoint, "elapsed", time.Since(started))
-	return nil
-}
-
 func (out *RemoteWriteFrameOutput) OutputFrame(_ context.Context, _ Vars, frame *data.Frame) ([]*ChannelFrame, error) {
-	if out.Endpoint == "" {
+	if out.queue == nil {
 		logger.Debug("Skip sending to remote write: no url")
 		return nil, nil
 	}
 	ts := remotewrite.TimeSeriesFromFramesLabelsColumn(frame)
-	out.mu.Lock()
-	out.buffer = append(out.buffer, ts...)
-	out.mu.Unlock()
+	if out.SampleMilliseconds > 0 {
+		out.mu.Lock()
+		out.buffer = append(out.buffer, ts...)
+		out.mu.Unlock()
+		return nil, nil
+	}
+	out.queue.Append(ts)
 	return nil, nil
 }
@@ -50,7 +50,7 @@ func TestRemoteWriteFrameOutput_sample(t *testing.T) {
 			},
 		},
 	}
-	out := NewRemoteWriteFrameOutput("", nil, 500)
+	out := NewRemoteWriteFrameOutput(nil, 500)
 	sampledTimeSeries := out.sample(timeSeries)
 	require.Len(t, sampledTimeSeries, 2)
 
@@ -122,7 +122,7 @@ func TestRemoteWriteFrameOutput_sample_merge(t *testing.T) {
 			},
 		},
 	}
-	out := NewRemoteWriteFrameOutput("", nil, 50)
+	out := NewRemoteWriteFrameOutput(nil, 50)
 	sampledTimeSeries := out.sample(timeSeries)
 	require.Len(t, sampledTimeSeries, 2)
 
@@ -143,3 +143,26 @@ func TestRemoteWriteFrameOutput_sample_merge(t *testing.T) {
 	require.Equal(t, expectedSamples[sampledTimeSeries[0].Labels[0].Value], sampledTimeSeries[0].Samples)
 	require.Equal(t, expectedSamples[sampledTimeSeries[1].Labels[0].Value], sampledTimeSeries[1].Samples)
 }
+
+func TestStorageRuleBuilder_RemoteWriteQueue(t *testing.T) {
+	builder := &StorageRuleBuilder{RemoteWriteSpoolPath: t.TempDir()}
+	writeConfig := WriteConfig{OrgId: 1, UID: "prom", Settings: WriteSettings{Endpoint: "http://localhost:9090/api/v1/write"}}
+
+	q, err := builder.remoteWriteQueue(writeConfig, nil, &RemoteWriteQueueConfig{Shards: 1, Spool: true})
+	require.NoError(t, err)
+	same, err := builder.remoteWriteQueue(writeConfig, nil, &RemoteWriteQueueConfig{Shards: 1, Spool: true})
+	require.NoError(t, err)
+	require.Same(t, q, same)
+
+	// Outputs with other queue settings don't replace the queue
+	other, err := builder.remoteWriteQueue(writeConfig, nil, &RemoteWriteQueueConfig{Shards: 2, Spool: true})
+	require.NoError(t, err)
+	require.NotSame(t, q, other)
+	same, err = builder.remoteWriteQueue(writeConfig, nil, &RemoteWriteQueueConfig{Shards: 1, Spool: true})
+	require.NoError(t, err)
+	require.Same(t, q, same)
+	require.Len(t, builder.remoteWriteQueues.queues, 2)
+
+	builder.remoteWriteQueues.stop()
+	require.Empty(t, builder.remoteWriteQueues.queues)
+}
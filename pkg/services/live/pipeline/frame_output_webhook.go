@@ -0,0 +1,33 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// WebhookFrameOutput POSTs batched frames to an HTTP endpoint.
+type WebhookFrameOutput struct {
+	writer *webhookWriter
+}
+
+func NewWebhookFrameOutput(settings WebhookSettings, config WebhookOutputConfig) *WebhookFrameOutput {
+	return &WebhookFrameOutput{
+		writer: newWebhookWriter(settings, config),
+	}
+}
+
+const FrameOutputTypeWebhook = "webhook"
+
+func (out *WebhookFrameOutput) Type() string {
+	return FrameOutputTypeWebhook
+}
+
+func (out *WebhookFrameOutput) OutputFrame(_ context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	if out.writer.settings.Endpoint == "" {
+		logger.Debug("Skip sending to webhook: no url")
+		return nil, nil
+	}
+	out.writer.enqueue(webhookEntry{channel: vars.Channel, frame: frame})
+	return nil, nil
+}
@@ -0,0 +1,215 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func testWebhookFrame() *data.Frame {
+	return data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+		data.NewField("value", nil, []float64{1}),
+	)
+}
+
+func TestWebhookFrameOutput_JSON(t *testing.T) {
+	received := make(chan webhookBody, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.Equal(t, "key", r.Header.Get("X-Api-Key"))
+		var body webhookBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received <- body
+	}))
+	defer server.Close()
+
+	out := NewWebhookFrameOutput(WebhookSettings{
+		Endpoint: server.URL,
+		Headers:  map[string]string{"Authorization": "Bearer token", "X-Api-Key": "key"},
+	}, WebhookOutputConfig{BatchSize: 2, FlushIntervalMilliseconds: 60000})
+
+	for i := 0; i < 2; i++ {
+		_, err := out.OutputFrame(context.Background(), Vars{Channel: "stream/test/x"}, testWebhookFrame())
+		require.NoError(t, err)
+	}
+
+	select {
+	case body := <-received:
+		require.Len(t, body.Frames, 2)
+		require.Equal(t, "stream/test/x", body.Frames[0].Channel)
+		var frame data.Frame
+		require.NoError(t, json.Unmarshal(body.Frames[0].Frame, &frame))
+		require.Equal(t, "test", frame.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook request not received")
+	}
+}
+
+func TestWebhookDataOutput(t *testing.T) {
+	received := make(chan webhookBody, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body webhookBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received <- body
+	}))
+	defer server.Close()
+
+	out := NewWebhookDataOutput(WebhookSettings{Endpoint: server.URL}, WebhookOutputConfig{FlushIntervalMilliseconds: 10})
+	_, err := out.OutputData(context.Background(), Vars{Channel: "stream/test/x"}, []byte(`{"value":1}`))
+	require.NoError(t, err)
+
+	select {
+	case body := <-received:
+		require.Equal(t, []webhookDataJSON{{Channel: "stream/test/x", Data: `{"value":1}`}}, body.Data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook request not received")
+	}
+}
+
+func TestWebhookWriter_Arrow(t *testing.T) {
+	body, contentType, err := encodeWebhookArrow([]webhookEntry{
+		{channel: "stream/test/a", frame: testWebhookFrame()},
+		{channel: "stream/test/b", frame: testWebhookFrame()},
+	})
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	var channels []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		raw, err := io.ReadAll(part)
+		require.NoError(t, err)
+		frame, err := data.UnmarshalArrowFrame(raw)
+		require.NoError(t, err)
+		require.Equal(t, "test", frame.Name)
+		channels = append(channels, part.Header.Get("X-Grafana-Live-Channel"))
+	}
+	require.Equal(t, []string{"stream/test/a", "stream/test/b"}, channels)
+
+	_, _, err = encodeWebhookArrow([]webhookEntry{{channel: "stream/test/a", data: []byte("x")}})
+	require.Error(t, err)
+}
+
+func TestWebhookWriter_Retries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	w := newWebhookWriter(WebhookSettings{Endpoint: server.URL}, WebhookOutputConfig{MaxRetries: 3})
+	var backoffs []time.Duration
+	w.sleep = func(_ context.Context, d time.Duration) bool {
+		backoffs = append(backoffs, d)
+		return true
+	}
+	w.send(context.Background(), []webhookEntry{{channel: "stream/test/x", frame: testWebhookFrame()}})
+	require.Equal(t, int32(3), calls.Load())
+	require.Equal(t, []time.Duration{webhookInitialBackoff, 2 * webhookInitialBackoff}, backoffs)
+}
+
+func TestWebhookWriter_NoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	w := newWebhookWriter(WebhookSettings{Endpoint: server.URL}, WebhookOutputConfig{MaxRetries: 3})
+	w.sleep = func(_ context.Context, _ time.Duration) bool { return true }
+	w.send(context.Background(), []webhookEntry{{channel: "stream/test/x", frame: testWebhookFrame()}})
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookWriter_QueueFull(t *testing.T) {
+	w := newWebhookWriter(WebhookSettings{Endpoint: "http://localhost"}, WebhookOutputConfig{QueueSize: 1})
+	// Pretend the sending goroutine is already running so the queue is not drained.
+	w.startOnce.Do(func() {})
+	w.enqueue(webhookEntry{channel: "a"})
+	w.enqueue(webhookEntry{channel: "b"})
+	require.Len(t, w.queue, 1)
+	require.Equal(t, "a", (<-w.queue).channel)
+}
+
+func TestWebhookWriter_Stopped(t *testing.T) {
+	w := newWebhookWriter(WebhookSettings{Endpoint: "http://localhost"}, WebhookOutputConfig{QueueSize: 10})
+	// Pretend the sending goroutine is already running so the queue is not drained.
+	w.startOnce.Do(func() {})
+	w.enqueue(webhookEntry{channel: "a"})
+
+	dropped := testutil.ToFloat64(webhookDroppedTotal.WithLabelValues(webhookDropReasonStopped))
+	w.stop()
+	require.Empty(t, w.queue)
+	require.Equal(t, dropped+1, testutil.ToFloat64(webhookDroppedTotal.WithLabelValues(webhookDropReasonStopped)))
+
+	// Rules built before the writer was stopped may still use it
+	w.enqueue(webhookEntry{channel: "b"})
+	require.Empty(t, w.queue)
+	require.Equal(t, dropped+2, testutil.ToFloat64(webhookDroppedTotal.WithLabelValues(webhookDropReasonStopped)))
+}
+
+func TestWebhookWriters(t *testing.T) {
+	var writers webhookWriters
+	settings := WebhookSettings{Endpoint: "http://localhost/a"}
+
+	w := writers.get(1, "1/hook", settings, WebhookOutputConfig{})
+	require.Same(t, w, writers.get(1, "1/hook", settings, WebhookOutputConfig{Format: WebhookFormatJSON}))
+	require.NotSame(t, w, writers.get(1, "1/hook", settings, WebhookOutputConfig{Format: WebhookFormatArrow}))
+	require.NotSame(t, w, writers.get(2, "2/hook", settings, WebhookOutputConfig{}))
+
+	// A changed write config replaces the writer
+	changed := writers.get(1, "1/hook", WebhookSettings{Endpoint: "http://localhost/b"}, WebhookOutputConfig{})
+	require.NotSame(t, w, changed)
+	require.Error(t, w.ctx.Err())
+	require.NoError(t, changed.ctx.Err())
+
+	writers.stop()
+	require.Error(t, changed.ctx.Err())
+}
+
+func TestWebhookWriters_Release(t *testing.T) {
+	var writers webhookWriters
+	settings := WebhookSettings{Endpoint: "http://localhost/a"}
+	kept := writers.get(1, "1/kept", settings, WebhookOutputConfig{})
+	deleted := writers.get(1, "1/deleted", settings, WebhookOutputConfig{})
+	otherOrg := writers.get(2, "2/hook", settings, WebhookOutputConfig{})
+
+	// The rules of org 1 are rebuilt without the deleted write config
+	writers.begin(1)
+	require.Same(t, kept, writers.get(1, "1/kept", settings, WebhookOutputConfig{}))
+	writers.release(1)
+
+	require.NoError(t, kept.ctx.Err())
+	require.Error(t, deleted.ctx.Err())
+	require.NoError(t, otherOrg.ctx.Err(), "the writers of other orgs are kept")
+	require.NotSame(t, deleted, writers.get(1, "1/deleted", settings, WebhookOutputConfig{}))
+
+	writers.stop()
+}
@@ -119,6 +119,9 @@ type WriteSettings struct {
 	Endpoint string `json:"endpoint"`
 	// BasicAuth is an optional basic auth settings.
 	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
+	// HTTPHeaderNames are names of headers sent with requests. Header values
+	// are kept in secure settings as httpHeaderValue1, httpHeaderValue2 etc.
+	HTTPHeaderNames []string `json:"httpHeaderNames,omitempty"`
 }
 
 type WriteConfigs struct {
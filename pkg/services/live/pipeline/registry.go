@@ -48,6 +48,21 @@ var FrameOutputsRegistry = []EntityInfo{
 		Type:        FrameOutputTypeLoki,
 		Description: "output frame as JSON to Loki",
 	},
+	{
+		Type:        FrameOutputTypeExpression,
+		Description: "evaluate expression over a sliding window and report state changes",
+		Example: ExpressionOutputConfig{
+			Expression: "$value > 80",
+			Reducer:    "mean",
+		},
+	},
+	{
+		Type:        FrameOutputTypeWebhook,
+		Description: "POST batched frames to HTTP endpoint",
+		Example: WebhookOutputConfig{
+			Format: WebhookFormatJSON,
+		},
+	},
 }
 
 var ConvertersRegistry = []EntityInfo{
@@ -94,4 +109,8 @@ var DataOutputsRegistry = []EntityInfo{
 		Type:        DataOutputTypeLoki,
 		Description: "output data to Loki as logs",
 	},
+	{
+		Type:        DataOutputTypeWebhook,
+		Description: "POST batched data to HTTP endpoint",
+	},
 }
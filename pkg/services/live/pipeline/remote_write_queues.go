@@ -0,0 +1,110 @@
+package pipeline
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+)
+
+// remoteWriteQueues keeps a single queue per write config and queue settings.
+// Channel rules are rebuilt on every change, reusing queues keeps pending samples
+// and makes sure a spool directory is never replayed by two queues at once.
+type remoteWriteQueues struct {
+	mu     sync.Mutex
+	queues map[string]*remotewrite.Queue
+	// configs the queues were created with.
+	configs map[string]remotewrite.QueueConfig
+}
+
+func (r *remoteWriteQueues) get(cfg remotewrite.QueueConfig) (*remotewrite.Queue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.queues == nil {
+		r.queues = map[string]*remotewrite.Queue{}
+		r.configs = map[string]remotewrite.QueueConfig{}
+	}
+	if q, ok := r.queues[cfg.Name]; ok {
+		if r.configs[cfg.Name] == cfg {
+			return q, nil
+		}
+		q.Stop()
+		delete(r.queues, cfg.Name)
+		delete(r.configs, cfg.Name)
+	}
+
+	q, err := remotewrite.NewQueue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SpoolDir != "" {
+		q.Start()
+	}
+	r.queues[cfg.Name] = q
+	r.configs[cfg.Name] = cfg
+	return q, nil
+}
+
+// stop ends all queues, pending samples are spooled when spooling is enabled.
+func (r *remoteWriteQueues) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, q := range r.queues {
+		q.Stop()
+		delete(r.queues, name)
+		delete(r.configs, name)
+	}
+}
+
+// writeConfigName identifies a write config across orgs.
+func writeConfigName(writeConfig WriteConfig) string {
+	return fmt.Sprintf("%d/%s", writeConfig.OrgId, writeConfig.UID)
+}
+
+func (f *StorageRuleBuilder) remoteWriteQueue(writeConfig WriteConfig, basicAuth *BasicAuth, config *RemoteWriteQueueConfig) (*remotewrite.Queue, error) {
+	if writeConfig.Settings.Endpoint == "" {
+		return nil, nil
+	}
+	cfg := remotewrite.QueueConfig{
+		Name:     writeConfigName(writeConfig),
+		Endpoint: writeConfig.Settings.Endpoint,
+	}
+	if config != nil {
+		// Outputs using the same write config with different queue settings get separate queues
+		cfg.Name += "/" + queueConfigHash(config)
+	}
+	if basicAuth != nil {
+		cfg.User = basicAuth.User
+		cfg.Password = basicAuth.Password
+	}
+	if config != nil {
+		cfg.Shards = config.Shards
+		cfg.MaxSamplesPerSend = config.MaxSamplesPerSend
+		cfg.BatchSendDeadline = time.Duration(config.BatchSendDeadlineMilliseconds) * time.Millisecond
+		cfg.Capacity = config.Capacity
+		cfg.MaxRetries = config.MaxRetries
+		if config.Spool {
+			if f.RemoteWriteSpoolPath == "" {
+				logger.Warn("Remote write spool path is not configured, samples won't be spooled", "uid", writeConfig.UID)
+			} else {
+				cfg.SpoolDir = filepath.Join(f.RemoteWriteSpoolPath, strconv.FormatInt(writeConfig.OrgId, 10), writeConfig.UID, queueConfigHash(config))
+				cfg.SpoolMaxBytes = config.SpoolMaxBytes
+			}
+		}
+	}
+	return f.remoteWriteQueues.get(cfg)
+}
+
+// queueConfigHash is stable across restarts, so spooled samples are replayed
+// by the queue created with the same settings.
+func queueConfigHash(config *RemoteWriteQueueConfig) string {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%+v", *config)
+	return strconv.FormatUint(uint64(h.Sum32()), 16)
+}
@@ -17,6 +17,26 @@ type StorageRuleBuilder struct {
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
+	// RemoteWriteSpoolPath is a directory for remote write outputs with
+	// spooling enabled. Spooling is disabled when empty.
+	RemoteWriteSpoolPath string
+	// ExpressionStateHandler creates annotations and alert notifications
+	// for expression outputs.
+	ExpressionStateHandler ExpressionStateHandler
+	// ExpressionStates keeps the state of expression outputs across rule rebuilds.
+	ExpressionStates *ExpressionStates
+
+	remoteWriteQueues remoteWriteQueues
+	webhookWriters    webhookWriters
+}
+
+// Run blocks until ctx is done and then stops the writers and remote write
+// queues shared by the rules the builder created.
+func (f *StorageRuleBuilder) Run(ctx context.Context) error {
+	<-ctx.Done()
+	f.webhookWriters.stop()
+	f.remoteWriteQueues.stop()
+	return nil
 }
 
 func (f *StorageRuleBuilder) extractSubscriber(config *SubscriberConfig) (Subscriber, error) {
@@ -164,6 +184,37 @@ func (f *StorageRuleBuilder) constructBasicAuth(writeConfig WriteConfig) (*Basic
 	}, nil
 }
 
+func (f *StorageRuleBuilder) constructWebhookSettings(writeConfig WriteConfig) (WebhookSettings, error) {
+	basicAuth, err := f.constructBasicAuth(writeConfig)
+	if err != nil {
+		return WebhookSettings{}, fmt.Errorf("error getting password: %w", err)
+	}
+	settings := WebhookSettings{
+		Endpoint:  writeConfig.Settings.Endpoint,
+		BasicAuth: basicAuth,
+		Headers:   map[string]string{},
+	}
+	if len(writeConfig.SecureSettings["bearerToken"]) > 0 {
+		token, err := f.SecretsService.Decrypt(context.Background(), writeConfig.SecureSettings["bearerToken"])
+		if err != nil {
+			return WebhookSettings{}, fmt.Errorf("bearerToken can't be decrypted: %w", err)
+		}
+		settings.Headers["Authorization"] = "Bearer " + string(token)
+	}
+	for i, name := range writeConfig.Settings.HTTPHeaderNames {
+		key := fmt.Sprintf("httpHeaderValue%d", i+1)
+		if len(writeConfig.SecureSettings[key]) == 0 {
+			continue
+		}
+		value, err := f.SecretsService.Decrypt(context.Background(), writeConfig.SecureSettings[key])
+		if err != nil {
+			return WebhookSettings{}, fmt.Errorf("%s can't be decrypted: %w", key, err)
+		}
+		settings.Headers[name] = string(value)
+	}
+	return settings, nil
+}
+
 func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig, writeConfigs []WriteConfig) (FrameOutputter, error) {
 	if config == nil {
 		return nil, nil
@@ -223,9 +274,12 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 		if err != nil {
 			return nil, fmt.Errorf("error getting password: %w", err)
 		}
+		queue, err := f.remoteWriteQueue(writeConfig, basicAuth, config.RemoteWriteOutputConfig.Queue)
+		if err != nil {
+			return nil, fmt.Errorf("error creating remote write queue: %w", err)
+		}
 		return NewRemoteWriteFrameOutput(
-			writeConfig.Settings.Endpoint,
-			basicAuth,
+			queue,
 			config.RemoteWriteOutputConfig.SampleMilliseconds,
 		), nil
 	case FrameOutputTypeLoki:
@@ -249,6 +303,31 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 			return nil, missingConfiguration
 		}
 		return NewChangeLogFrameOutput(f.FrameStorage, *config.ChangeLogOutputConfig), nil
+	case FrameOutputTypeExpression:
+		if config.ExpressionOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewExpressionOutput(f.ExpressionStateHandler, f.ExpressionStates, *config.ExpressionOutputConfig)
+	case FrameOutputTypeWebhook:
+		if config.WebhookOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		switch config.WebhookOutputConfig.Format {
+		case "", WebhookFormatJSON, WebhookFormatArrow:
+		default:
+			return nil, fmt.Errorf("unknown webhook format: %s", config.WebhookOutputConfig.Format)
+		}
+		writeConfig, ok := f.getWriteConfig(config.WebhookOutputConfig.UID, writeConfigs)
+		if !ok {
+			return nil, fmt.Errorf("unknown write config uid: %s", config.WebhookOutputConfig.UID)
+		}
+		settings, err := f.constructWebhookSettings(writeConfig)
+		if err != nil {
+			return nil, err
+		}
+		return &WebhookFrameOutput{
+			writer: f.webhookWriters.get(writeConfig.OrgId, writeConfigName(writeConfig), settings, *config.WebhookOutputConfig),
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown output type: %s", config.Type)
 	}
@@ -281,6 +360,24 @@ func (f *StorageRuleBuilder) extractDataOutputter(config *DataOutputterConfig, w
 			writeConfig.Settings.Endpoint,
 			basicAuth,
 		), nil
+	case DataOutputTypeWebhook:
+		if config.WebhookOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		writeConfig, ok := f.getWriteConfig(config.WebhookOutputConfig.UID, writeConfigs)
+		if !ok {
+			return nil, fmt.Errorf("unknown write config uid: %s", config.WebhookOutputConfig.UID)
+		}
+		settings, err := f.constructWebhookSettings(writeConfig)
+		if err != nil {
+			return nil, err
+		}
+		webhookConfig := *config.WebhookOutputConfig
+		// Raw data can't be encoded as Arrow.
+		webhookConfig.Format = WebhookFormatJSON
+		return &WebhookDataOutput{
+			writer: f.webhookWriters.get(writeConfig.OrgId, writeConfigName(writeConfig), settings, webhookConfig),
+		}, nil
 	case DataOutputTypeBuiltin:
 		return NewBuiltinDataOutput(f.ChannelHandlerGetter), nil
 	case DataOutputTypeLocalSubscribers:
@@ -312,6 +409,9 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 
 	rules := make([]*LiveChannelRule, 0, len(channelRules))
 
+	// The webhook writers no longer used by the rules are stopped once the rules are built
+	f.webhookWriters.begin(orgID)
+
 	for _, ruleConfig := range channelRules {
 		rule := &LiveChannelRule{
 			OrgId:   orgID,
@@ -376,5 +476,6 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 		rules = append(rules, rule)
 	}
 
+	f.webhookWriters.release(orgID)
 	return rules, nil
 }
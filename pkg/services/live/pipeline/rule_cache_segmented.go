@@ -25,6 +25,15 @@ func NewCacheSegmentedTree(storage RuleBuilder) *CacheSegmentedTree {
 	return s
 }
 
+// NewStaticSegmentedTree builds the rules of an org when they are first requested and
+// never refreshes them. It is meant for short-lived pipelines, like dry runs.
+func NewStaticSegmentedTree(storage RuleBuilder) *CacheSegmentedTree {
+	return &CacheSegmentedTree{
+		radix:       map[int64]*tree.Node{},
+		ruleBuilder: storage,
+	}
+}
+
 func (s *CacheSegmentedTree) updatePeriodically() {
 	for {
 		var orgIDs []int64
@@ -53,6 +53,18 @@ func TestStorage_Get(t *testing.T) {
 	require.Equal(t, "stream/boom:er", rule.Pattern)
 }
 
+func TestStaticStorage_Get(t *testing.T) {
+	s := NewStaticSegmentedTree(&testBuilder{})
+	rule, ok, err := s.Get(1, "stream/telegraf/mem")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "stream/telegraf/:metric", rule.Pattern)
+
+	_, ok, err = s.Get(1, "stream/other")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 func BenchmarkRuleGet(b *testing.B) {
 	s := NewCacheSegmentedTree(&testBuilder{})
 	for i := 0; i < b.N; i++ {
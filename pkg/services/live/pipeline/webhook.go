@@ -0,0 +1,357 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	WebhookFormatJSON  = "json"
+	WebhookFormatArrow = "arrow"
+)
+
+const (
+	defaultWebhookBatchSize     = 100
+	defaultWebhookFlushInterval = time.Second
+	defaultWebhookQueueSize     = 1000
+	defaultWebhookMaxRetries    = 3
+	webhookInitialBackoff       = 500 * time.Millisecond
+	webhookMaxBackoff           = 30 * time.Second
+)
+
+const (
+	webhookDropReasonQueueFull  = "queue_full"
+	webhookDropReasonSendFailed = "send_failed"
+	webhookDropReasonEncode     = "encode_failed"
+	webhookDropReasonStopped    = "writer_stopped"
+)
+
+var (
+	webhookSentTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "pipeline_webhook_sent_total",
+		Help:      "Number of entries successfully sent by Live pipeline webhook outputs.",
+	})
+	webhookDroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "pipeline_webhook_dropped_total",
+		Help:      "Number of entries dropped by Live pipeline webhook outputs.",
+	}, []string{"reason"})
+)
+
+// webhookEntry is a single frame or data payload queued for sending.
+type webhookEntry struct {
+	channel string
+	frame   *data.Frame
+	data    []byte
+}
+
+type webhookFrameJSON struct {
+	Channel string          `json:"channel"`
+	Frame   json.RawMessage `json:"frame"`
+}
+
+type webhookDataJSON struct {
+	Channel string `json:"channel"`
+	Data    string `json:"data"`
+}
+
+type webhookBody struct {
+	Frames []webhookFrameJSON `json:"frames,omitempty"`
+	Data   []webhookDataJSON  `json:"data,omitempty"`
+}
+
+// WebhookSettings describe webhook endpoint and its authentication.
+type WebhookSettings struct {
+	Endpoint  string
+	BasicAuth *BasicAuth
+	// Headers are added to every request, this is where bearer token and
+	// custom auth headers from write config secure settings end up.
+	Headers map[string]string
+}
+
+// webhookWriter batches entries in a bounded queue and POSTs them to the
+// endpoint. Entries are dropped when the queue is full or when sending fails
+// after all retries, so a slow endpoint never blocks the pipeline.
+type webhookWriter struct {
+	settings   WebhookSettings
+	config     WebhookOutputConfig
+	httpClient *http.Client
+	queue      chan webhookEntry
+	startOnce  sync.Once
+	// mu guards stopped, so no entry is queued once the writer is stopped.
+	mu      sync.RWMutex
+	stopped bool
+	// ctx is cancelled when the writer is stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// sleep is replaced in tests.
+	sleep func(context.Context, time.Duration) bool
+}
+
+func newWebhookWriter(settings WebhookSettings, config WebhookOutputConfig) *webhookWriter {
+	config = webhookConfigWithDefaults(config)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhookWriter{
+		settings:   settings,
+		config:     config,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		queue:      make(chan webhookEntry, config.QueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+		sleep:      sleepContext,
+	}
+}
+
+func webhookConfigWithDefaults(config WebhookOutputConfig) WebhookOutputConfig {
+	if config.Format == "" {
+		config.Format = WebhookFormatJSON
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultWebhookBatchSize
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultWebhookQueueSize
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaultWebhookMaxRetries
+	}
+	return config
+}
+
+// stop ends the sending goroutine, entries still in the queue are dropped.
+func (w *webhookWriter) stop() {
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+	w.cancel()
+
+	dropped := 0
+drain:
+	for {
+		select {
+		case <-w.queue:
+			dropped++
+		default:
+			break drain
+		}
+	}
+	if dropped > 0 {
+		webhookDroppedTotal.WithLabelValues(webhookDropReasonStopped).Add(float64(dropped))
+		logger.Warn("Webhook writer stopped, dropping queued entries", "url", w.settings.Endpoint, "numEntries", dropped)
+	}
+}
+
+func (w *webhookWriter) flushInterval() time.Duration {
+	if w.config.FlushIntervalMilliseconds > 0 {
+		return time.Duration(w.config.FlushIntervalMilliseconds) * time.Millisecond
+	}
+	return defaultWebhookFlushInterval
+}
+
+// enqueue adds entry to the queue without blocking. The sending goroutine is
+// started on first use so rules which never receive data do not spawn it.
+// Entries of rules still using a stopped writer are dropped.
+func (w *webhookWriter) enqueue(entry webhookEntry) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.stopped {
+		webhookDroppedTotal.WithLabelValues(webhookDropReasonStopped).Inc()
+		logger.Debug("Webhook writer is stopped, dropping entry", "url", w.settings.Endpoint, "channel", entry.channel)
+		return
+	}
+
+	w.startOnce.Do(func() {
+		go w.run(w.ctx)
+	})
+	select {
+	case w.queue <- entry:
+	default:
+		webhookDroppedTotal.WithLabelValues(webhookDropReasonQueueFull).Inc()
+		logger.Warn("Webhook queue is full, dropping entry", "url", w.settings.Endpoint, "channel", entry.channel)
+	}
+}
+
+func (w *webhookWriter) run(ctx context.Context) {
+	ticker := time.NewTicker(w.flushInterval())
+	defer ticker.Stop()
+
+	batch := make([]webhookEntry, 0, w.config.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			if len(batch) > 0 {
+				webhookDroppedTotal.WithLabelValues(webhookDropReasonStopped).Add(float64(len(batch)))
+			}
+			return
+		case entry := <-w.queue:
+			batch = append(batch, entry)
+			if len(batch) < w.config.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		w.send(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+func (w *webhookWriter) send(ctx context.Context, batch []webhookEntry) {
+	body, contentType, err := w.encode(batch)
+	if err != nil {
+		webhookDroppedTotal.WithLabelValues(webhookDropReasonEncode).Add(float64(len(batch)))
+		logger.Error("Error encoding webhook request", "url", w.settings.Endpoint, "error", err)
+		return
+	}
+
+	backoff := webhookInitialBackoff
+	for attempt := 0; ; attempt++ {
+		err = w.post(ctx, body, contentType)
+		if err == nil {
+			webhookSentTotal.Add(float64(len(batch)))
+			return
+		}
+		var permanent *webhookPermanentError
+		if errors.As(err, &permanent) || attempt >= w.config.MaxRetries {
+			break
+		}
+		logger.Debug("Retrying webhook request", "url", w.settings.Endpoint, "attempt", attempt+1, "error", err)
+		if !w.sleep(ctx, backoff) {
+			break
+		}
+		backoff *= 2
+		if backoff > webhookMaxBackoff {
+			backoff = webhookMaxBackoff
+		}
+	}
+	webhookDroppedTotal.WithLabelValues(webhookDropReasonSendFailed).Add(float64(len(batch)))
+	logger.Error("Error sending to webhook", "url", w.settings.Endpoint, "numEntries", len(batch), "error", err)
+}
+
+// webhookPermanentError is returned for requests which will not succeed
+// on retry, like responses with 4xx status other than 429.
+type webhookPermanentError struct {
+	err error
+}
+
+func (e *webhookPermanentError) Error() string {
+	return e.err.Error()
+}
+
+func (w *webhookWriter) post(ctx context.Context, body []byte, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.settings.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return &webhookPermanentError{err: fmt.Errorf("error constructing webhook request: %w", err)}
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range w.settings.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.settings.BasicAuth != nil {
+		req.SetBasicAuth(w.settings.BasicAuth.User, w.settings.BasicAuth.Password)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending webhook request: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("unexpected response code from webhook endpoint: %d", resp.StatusCode)
+	default:
+		return &webhookPermanentError{err: fmt.Errorf("unexpected response code from webhook endpoint: %d", resp.StatusCode)}
+	}
+}
+
+func (w *webhookWriter) encode(batch []webhookEntry) ([]byte, string, error) {
+	if w.config.Format == WebhookFormatArrow {
+		return encodeWebhookArrow(batch)
+	}
+	return encodeWebhookJSON(batch)
+}
+
+func encodeWebhookJSON(batch []webhookEntry) ([]byte, string, error) {
+	var body webhookBody
+	for _, entry := range batch {
+		if entry.frame != nil {
+			frameJSON, err := data.FrameToJSON(entry.frame, data.IncludeAll)
+			if err != nil {
+				return nil, "", err
+			}
+			body.Frames = append(body.Frames, webhookFrameJSON{Channel: entry.channel, Frame: frameJSON})
+			continue
+		}
+		body.Data = append(body.Data, webhookDataJSON{Channel: entry.channel, Data: string(entry.data)})
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, "application/json", nil
+}
+
+// encodeWebhookArrow sends every frame as a separate part of a multipart
+// body, since an Arrow file can only hold a single schema.
+func encodeWebhookArrow(batch []webhookEntry) ([]byte, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, entry := range batch {
+		if entry.frame == nil {
+			return nil, "", errors.New("arrow format supports frames only")
+		}
+		arrow, err := entry.frame.MarshalArrow()
+		if err != nil {
+			return nil, "", err
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "application/vnd.apache.arrow.file")
+		header.Set("X-Grafana-Live-Channel", entry.channel)
+		header.Set("Content-Length", strconv.Itoa(len(arrow)))
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(arrow); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "multipart/mixed; boundary=" + mw.Boundary(), nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"reflect"
+	"sync"
+)
+
+type webhookWriterKey struct {
+	orgID int64
+	// name of the write config, including the org.
+	name   string
+	config WebhookOutputConfig
+}
+
+// webhookWriters keeps a single writer per write config and output settings.
+// Channel rules are rebuilt periodically, reusing writers keeps queued entries
+// and makes sure a sending goroutine is not started on every rebuild.
+type webhookWriters struct {
+	mu      sync.Mutex
+	writers map[webhookWriterKey]*webhookWriter
+	// used records the writers referenced by the rules being built, by org.
+	used map[int64]map[webhookWriterKey]struct{}
+}
+
+func (r *webhookWriters) get(orgID int64, name string, settings WebhookSettings, config WebhookOutputConfig) *webhookWriter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writers == nil {
+		r.writers = map[webhookWriterKey]*webhookWriter{}
+	}
+	key := webhookWriterKey{orgID: orgID, name: name, config: webhookConfigWithDefaults(config)}
+	if used, ok := r.used[orgID]; ok {
+		used[key] = struct{}{}
+	}
+	if w, ok := r.writers[key]; ok {
+		if reflect.DeepEqual(w.settings, settings) {
+			return w
+		}
+		// The write config was changed, the old endpoint or credentials must not be used anymore.
+		w.stop()
+	}
+
+	w := newWebhookWriter(settings, config)
+	r.writers[key] = w
+	return w
+}
+
+// begin starts recording the writers used by the rules built for the org.
+func (r *webhookWriters) begin(orgID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.used == nil {
+		r.used = map[int64]map[webhookWriterKey]struct{}{}
+	}
+	r.used[orgID] = map[webhookWriterKey]struct{}{}
+}
+
+// release stops the writers of the org which are not used by the rules built
+// since begin, like the ones of deleted write configs or outputs.
+func (r *webhookWriters) release(orgID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, ok := r.used[orgID]
+	if !ok {
+		return
+	}
+	delete(r.used, orgID)
+	for key, w := range r.writers {
+		if key.orgID != orgID {
+			continue
+		}
+		if _, ok := used[key]; !ok {
+			w.stop()
+			delete(r.writers, key)
+		}
+	}
+}
+
+// stop ends all writers, used when the pipeline is shut down.
+func (r *webhookWriters) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, w := range r.writers {
+		w.stop()
+		delete(r.writers, key)
+	}
+}
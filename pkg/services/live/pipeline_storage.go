@@ -0,0 +1,75 @@
+package live
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+// UsePipelineStorage replaces the storage the pipeline reads channel rules and
+// write configs from. Rules are rebuilt from the new storage on the next refresh.
+func (g *GrafanaLive) UsePipelineStorage(storage pipeline.Storage) {
+	g.pipelineStorage.set(storage)
+}
+
+// switchableStorage delegates to a storage that can be replaced after the
+// pipeline is created, for example once the API server registers the resources.
+type switchableStorage struct {
+	mu      sync.RWMutex
+	storage pipeline.Storage
+}
+
+var _ pipeline.Storage = (*switchableStorage)(nil)
+
+func newSwitchableStorage(storage pipeline.Storage) *switchableStorage {
+	return &switchableStorage{storage: storage}
+}
+
+func (s *switchableStorage) set(storage pipeline.Storage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storage = storage
+}
+
+func (s *switchableStorage) get() pipeline.Storage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.storage
+}
+
+func (s *switchableStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]pipeline.WriteConfig, error) {
+	return s.get().ListWriteConfigs(ctx, orgID)
+}
+
+func (s *switchableStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigGetCmd) (pipeline.WriteConfig, bool, error) {
+	return s.get().GetWriteConfig(ctx, orgID, cmd)
+}
+
+func (s *switchableStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigCreateCmd) (pipeline.WriteConfig, error) {
+	return s.get().CreateWriteConfig(ctx, orgID, cmd)
+}
+
+func (s *switchableStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigUpdateCmd) (pipeline.WriteConfig, error) {
+	return s.get().UpdateWriteConfig(ctx, orgID, cmd)
+}
+
+func (s *switchableStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigDeleteCmd) error {
+	return s.get().DeleteWriteConfig(ctx, orgID, cmd)
+}
+
+func (s *switchableStorage) ListChannelRules(ctx context.Context, orgID int64) ([]pipeline.ChannelRule, error) {
+	return s.get().ListChannelRules(ctx, orgID)
+}
+
+func (s *switchableStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd pipeline.ChannelRuleCreateCmd) (pipeline.ChannelRule, error) {
+	return s.get().CreateChannelRule(ctx, orgID, cmd)
+}
+
+func (s *switchableStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd pipeline.ChannelRuleUpdateCmd) (pipeline.ChannelRule, error) {
+	return s.get().UpdateChannelRule(ctx, orgID, cmd)
+}
+
+func (s *switchableStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd pipeline.ChannelRuleDeleteCmd) error {
+	return s.get().DeleteChannelRule(ctx, orgID, cmd)
+}
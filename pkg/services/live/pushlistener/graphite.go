@@ -0,0 +1,66 @@
+package pushlistener
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errEmptyLine = errors.New("empty line")
+
+// parseGraphiteLine parses a single line of the Graphite plaintext protocol:
+//
+//	<metric path>[;tag=value...] <value> [<timestamp>]
+//
+// If the timestamp is missing or -1 the now time is used.
+func parseGraphiteLine(line string, now time.Time) (Metric, error) {
+	parts := strings.Fields(line)
+	switch len(parts) {
+	case 0:
+		return Metric{}, errEmptyLine
+	case 2, 3:
+	default:
+		return Metric{}, fmt.Errorf("invalid graphite line: %q", line)
+	}
+
+	path := strings.Split(parts[0], ";")
+	if path[0] == "" {
+		return Metric{}, fmt.Errorf("missing metric path: %q", line)
+	}
+	m := Metric{
+		Name: path[0],
+		Time: now,
+	}
+	for _, tag := range path[1:] {
+		k, v, ok := strings.Cut(tag, "=")
+		if !ok || k == "" || v == "" {
+			return Metric{}, fmt.Errorf("invalid graphite tag %q: %q", tag, line)
+		}
+		if m.Tags == nil {
+			m.Tags = map[string]string{}
+		}
+		m.Tags[k] = v
+	}
+
+	value, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return Metric{}, fmt.Errorf("invalid graphite value %q: %w", parts[1], err)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Metric{}, fmt.Errorf("unsupported graphite value: %q", parts[1])
+	}
+	m.Fields = map[string]float64{"value": value}
+
+	if len(parts) == 3 && parts[2] != "-1" {
+		ts, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return Metric{}, fmt.Errorf("invalid graphite timestamp %q: %w", parts[2], err)
+		}
+		sec, frac := math.Modf(ts)
+		m.Time = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+	}
+	return m, nil
+}
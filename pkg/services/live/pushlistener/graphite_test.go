@@ -0,0 +1,104 @@
+package pushlistener
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGraphiteLine(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	testCases := []struct {
+		name     string
+		line     string
+		expected Metric
+		err      bool
+	}{
+		{
+			name: "with timestamp",
+			line: "servers.host1.cpu 12.5 1600000000\n",
+			expected: Metric{
+				Name:   "servers.host1.cpu",
+				Fields: map[string]float64{"value": 12.5},
+				Time:   time.Unix(1600000000, 0),
+			},
+		},
+		{
+			name: "without timestamp",
+			line: "servers.host1.cpu 1",
+			expected: Metric{
+				Name:   "servers.host1.cpu",
+				Fields: map[string]float64{"value": 1},
+				Time:   now,
+			},
+		},
+		{
+			name: "negative timestamp",
+			line: "servers.host1.cpu 1 -1",
+			expected: Metric{
+				Name:   "servers.host1.cpu",
+				Fields: map[string]float64{"value": 1},
+				Time:   now,
+			},
+		},
+		{
+			name: "with tags",
+			line: "cpu;host=host1;dc=eu 3 1600000000",
+			expected: Metric{
+				Name:   "cpu",
+				Tags:   map[string]string{"host": "host1", "dc": "eu"},
+				Fields: map[string]float64{"value": 3},
+				Time:   time.Unix(1600000000, 0),
+			},
+		},
+		{
+			name: "invalid value",
+			line: "cpu abc 1600000000",
+			err:  true,
+		},
+		{
+			name: "invalid tag",
+			line: "cpu;host 1",
+			err:  true,
+		},
+		{
+			name: "too many parts",
+			line: "cpu 1 2 3",
+			err:  true,
+		},
+		{
+			name: "NaN",
+			line: "cpu NaN",
+			err:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := parseGraphiteLine(tc.line, now)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected.Name, m.Name)
+			require.Equal(t, tc.expected.Tags, m.Tags)
+			require.Equal(t, tc.expected.Fields, m.Fields)
+			require.True(t, tc.expected.Time.Equal(m.Time))
+		})
+	}
+}
+
+func TestAppendLine(t *testing.T) {
+	var buf bytes.Buffer
+	appendLine(&buf, Metric{
+		Name:   "my metric,x",
+		Tags:   map[string]string{"b": "2", "a": "x=1"},
+		Fields: map[string]float64{"value": 1.5, "count": 2},
+		Time:   time.Unix(1, 0),
+	})
+	require.Equal(t, "my\\ metric\\,x,a=x\\=1,b=2 count=2,value=1.5 1000000000\n", buf.String())
+}
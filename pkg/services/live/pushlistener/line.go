@@ -0,0 +1,64 @@
+package pushlistener
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metric is a single parsed sample. Both Graphite and StatsD metrics are
+// converted to Influx line protocol before being pushed into the Live pipeline,
+// so channel rules can use the influxAuto converter to get frames.
+type Metric struct {
+	Name   string
+	Tags   map[string]string
+	Fields map[string]float64
+	Time   time.Time
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+)
+
+// appendLine appends m to buf in Influx line protocol format.
+func appendLine(buf *bytes.Buffer, m Metric) {
+	buf.WriteString(measurementEscaper.Replace(m.Name))
+
+	tagKeys := make([]string, 0, len(m.Tags))
+	for k := range m.Tags {
+		tagKeys = append(tagKeys, k)
+	}
+	sort.Strings(tagKeys)
+	for _, k := range tagKeys {
+		if k == "" || m.Tags[k] == "" {
+			continue
+		}
+		buf.WriteByte(',')
+		buf.WriteString(tagEscaper.Replace(k))
+		buf.WriteByte('=')
+		buf.WriteString(tagEscaper.Replace(m.Tags[k]))
+	}
+
+	fieldKeys := make([]string, 0, len(m.Fields))
+	for k := range m.Fields {
+		fieldKeys = append(fieldKeys, k)
+	}
+	sort.Strings(fieldKeys)
+	for i, k := range fieldKeys {
+		if i == 0 {
+			buf.WriteByte(' ')
+		} else {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(tagEscaper.Replace(k))
+		buf.WriteByte('=')
+		buf.WriteString(strconv.FormatFloat(m.Fields[k], 'f', -1, 64))
+	}
+
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(m.Time.UnixNano(), 10))
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,273 @@
+package pushlistener
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+var (
+	logger = log.New("live.push_listener")
+)
+
+const (
+	// maxPacketSize is the largest UDP datagram accepted.
+	maxPacketSize = 65535
+	// maxLineSize limits a single TCP line, longer lines are dropped.
+	maxLineSize = 64 * 1024
+	// maxBatchLines limits the number of Graphite lines published at once
+	// when reading from a TCP connection.
+	maxBatchLines = 1000
+)
+
+// Publisher receives converted metrics in Influx line protocol format.
+// It is satisfied by *pipeline.Pipeline.
+type Publisher interface {
+	ProcessInput(ctx context.Context, orgID int64, channelID string, body []byte) (bool, error)
+}
+
+// Config of Listener.
+type Config struct {
+	// GraphiteAddress to accept Graphite plaintext protocol on, both TCP and UDP.
+	// Empty value disables Graphite listener.
+	GraphiteAddress string
+	// GraphiteChannel is a Live channel Graphite metrics are pushed to.
+	GraphiteChannel string
+	// StatsDAddress to accept StatsD protocol on, both TCP and UDP.
+	// Empty value disables StatsD listener.
+	StatsDAddress string
+	// StatsDChannel is a Live channel aggregated StatsD metrics are pushed to.
+	StatsDChannel string
+	// FlushInterval is how often aggregated StatsD metrics are pushed.
+	FlushInterval time.Duration
+	// OrgID is an organization channel rules are looked up in.
+	OrgID int64
+}
+
+// Enabled returns true if at least one of listeners is configured.
+func (c Config) Enabled() bool {
+	return c.GraphiteAddress != "" || c.StatsDAddress != ""
+}
+
+// Listener accepts Graphite and StatsD metrics over the network and pushes
+// them into the Live pipeline as Influx line protocol.
+type Listener struct {
+	cfg        Config
+	publisher  Publisher
+	aggregator *statsdAggregator
+}
+
+// New creates Listener.
+func New(cfg Config, publisher Publisher) *Listener {
+	return &Listener{
+		cfg:        cfg,
+		publisher:  publisher,
+		aggregator: newStatsdAggregator(),
+	}
+}
+
+// Run starts configured listeners and blocks until ctx is done.
+func (l *Listener) Run(ctx context.Context) error {
+	eGroup, eCtx := errgroup.WithContext(ctx)
+
+	if l.cfg.GraphiteAddress != "" {
+		logger.Info("Starting Graphite listener", "address", l.cfg.GraphiteAddress, "channel", l.cfg.GraphiteChannel)
+		if err := l.serve(eCtx, eGroup, l.cfg.GraphiteAddress, l.handleGraphite); err != nil {
+			return err
+		}
+	}
+
+	if l.cfg.StatsDAddress != "" {
+		logger.Info("Starting StatsD listener", "address", l.cfg.StatsDAddress, "channel", l.cfg.StatsDChannel)
+		if err := l.serve(eCtx, eGroup, l.cfg.StatsDAddress, l.handleStatsD); err != nil {
+			return err
+		}
+		eGroup.Go(func() error {
+			return l.runStatsDFlush(eCtx)
+		})
+	}
+
+	return eGroup.Wait()
+}
+
+// serve listens on address with both TCP and UDP. Every TCP line batch and
+// every UDP packet is passed to handle.
+func (l *Listener) serve(ctx context.Context, eGroup *errgroup.Group, address string, handle func(context.Context, []string)) error {
+	var lc net.ListenConfig
+
+	tcpListener, err := lc.Listen(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	udpConn, err := lc.ListenPacket(ctx, "udp", address)
+	if err != nil {
+		_ = tcpListener.Close()
+		return err
+	}
+
+	eGroup.Go(func() error {
+		<-ctx.Done()
+		_ = tcpListener.Close()
+		_ = udpConn.Close()
+		return nil
+	})
+
+	eGroup.Go(func() error {
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.serveConn(ctx, conn, handle)
+			}()
+		}
+	})
+
+	eGroup.Go(func() error {
+		buf := make([]byte, maxPacketSize)
+		for {
+			n, _, err := udpConn.ReadFrom(buf)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if errors.Is(err, net.ErrClosed) {
+					return nil
+				}
+				logger.Warn("Error reading UDP packet", "address", address, "error", err)
+				continue
+			}
+			handle(ctx, strings.Split(string(buf[:n]), "\n"))
+		}
+	})
+	return nil
+}
+
+func (l *Listener) serveConn(ctx context.Context, conn net.Conn, handle func(context.Context, []string)) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+	defer func() { _ = conn.Close() }()
+
+	reader := bufio.NewReaderSize(conn, maxLineSize)
+	lines := make([]string, 0, maxBatchLines)
+	// dropping is set while the rest of a too long line is skipped.
+	dropping := false
+	for {
+		slice, err := reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			if !dropping {
+				logger.Debug("Dropping too long line", "remote", conn.RemoteAddr(), "maxLineSize", maxLineSize)
+			}
+			dropping = true
+			continue
+		}
+		if dropping {
+			// The end of the dropped line.
+			dropping = false
+		} else if len(slice) > 0 {
+			lines = append(lines, string(slice))
+		}
+		// Publish what was read so far if there is no more buffered data, so
+		// a slow sender is not delayed until the batch is full.
+		if len(lines) > 0 && (err != nil || len(lines) >= maxBatchLines || reader.Buffered() == 0) {
+			handle(ctx, lines)
+			lines = lines[:0]
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) && ctx.Err() == nil {
+				logger.Debug("Error reading connection", "remote", conn.RemoteAddr(), "error", err)
+			}
+			return
+		}
+	}
+}
+
+func (l *Listener) handleGraphite(ctx context.Context, lines []string) {
+	now := time.Now()
+	metrics := make([]Metric, 0, len(lines))
+	for _, line := range lines {
+		m, err := parseGraphiteLine(line, now)
+		if err != nil {
+			if !errors.Is(err, errEmptyLine) {
+				logger.Debug("Invalid Graphite line", "error", err)
+			}
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	l.publish(ctx, l.cfg.GraphiteChannel, metrics)
+}
+
+func (l *Listener) handleStatsD(_ context.Context, lines []string) {
+	for _, line := range lines {
+		s, err := parseStatsDLine(line)
+		if err != nil {
+			if !errors.Is(err, errEmptyLine) {
+				logger.Debug("Invalid StatsD line", "error", err)
+			}
+			continue
+		}
+		l.aggregator.add(s)
+	}
+}
+
+func (l *Listener) runStatsDFlush(ctx context.Context) error {
+	interval := l.cfg.FlushInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case t := <-ticker.C:
+			l.publish(ctx, l.cfg.StatsDChannel, l.aggregator.flush(t, interval))
+		}
+	}
+}
+
+func (l *Listener) publish(ctx context.Context, channel string, metrics []Metric) {
+	if len(metrics) == 0 {
+		return
+	}
+	var buf bytes.Buffer
+	for _, m := range metrics {
+		appendLine(&buf, m)
+	}
+	ok, err := l.publisher.ProcessInput(ctx, l.cfg.OrgID, channel, buf.Bytes())
+	if err != nil {
+		logger.Error("Error processing metrics", "channel", channel, "error", err)
+		return
+	}
+	if !ok {
+		logger.Warn("No channel rule found for metrics", "channel", channel, "orgId", l.cfg.OrgID)
+	}
+}
@@ -0,0 +1,57 @@
+package pushlistener
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestServeConn(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	server, client := net.Pipe()
+	var received []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l := &Listener{}
+		l.serveConn(context.Background(), server, func(_ context.Context, lines []string) {
+			received = append(received, lines...)
+		})
+	}()
+
+	_, err := client.Write([]byte("servers.host1.cpu 12.5\n"))
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+	<-done
+
+	require.Equal(t, []string{"servers.host1.cpu 12.5\n"}, received)
+}
+
+func TestServeConnDropsTooLongLines(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	server, client := net.Pipe()
+	var received []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l := &Listener{}
+		l.serveConn(context.Background(), server, func(_ context.Context, lines []string) {
+			received = append(received, lines...)
+		})
+	}()
+
+	_, err := client.Write([]byte(strings.Repeat("a", 3*maxLineSize) + " 1\n"))
+	require.NoError(t, err)
+	_, err = client.Write([]byte("servers.host1.cpu 12.5\n"))
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+	<-done
+
+	require.Equal(t, []string{"servers.host1.cpu 12.5\n"}, received)
+}
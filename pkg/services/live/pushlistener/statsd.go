@@ -0,0 +1,329 @@
+package pushlistener
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	statsdCounter   = "c"
+	statsdGauge     = "g"
+	statsdTimer     = "ms"
+	statsdHistogram = "h"
+	statsdDistrib   = "d"
+	statsdSet       = "s"
+)
+
+const (
+	// maxStatsdKeys limits the number of distinct metrics of each type
+	// aggregated at once.
+	maxStatsdKeys = 10000
+	// maxStatsdValues limits the timer samples and the set values kept for
+	// a single metric in a flush interval.
+	maxStatsdValues = 10000
+	// statsdGaugeExpiryIntervals is the number of flush intervals a gauge is
+	// kept without being updated.
+	statsdGaugeExpiryIntervals = 10
+)
+
+const (
+	statsdDropReasonTooManyKeys   = "too_many_keys"
+	statsdDropReasonTooManyValues = "too_many_values"
+)
+
+var statsdDroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "grafana",
+	Subsystem: "live",
+	Name:      "push_listener_statsd_dropped_total",
+	Help:      "Number of StatsD samples dropped by the Live push listener because of aggregation limits.",
+}, []string{"reason"})
+
+// statsdSample is a single parsed StatsD sample.
+type statsdSample struct {
+	name       string
+	tags       map[string]string
+	value      float64
+	setValue   string
+	metricType string
+	sampleRate float64
+	// relative is set for gauges sent with an explicit sign (+N or -N)
+	// which modify the current gauge value instead of replacing it.
+	relative bool
+}
+
+// parseStatsDLine parses a single line of the StatsD protocol:
+//
+//	<name>:<value>|<type>[|@<sample rate>][|#tag:value,...]
+func parseStatsDLine(line string) (statsdSample, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return statsdSample{}, errEmptyLine
+	}
+
+	name, rest, ok := strings.Cut(line, ":")
+	if !ok || name == "" {
+		return statsdSample{}, fmt.Errorf("invalid statsd line: %q", line)
+	}
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 {
+		return statsdSample{}, fmt.Errorf("missing statsd metric type: %q", line)
+	}
+
+	s := statsdSample{
+		name:       name,
+		metricType: parts[1],
+		sampleRate: 1,
+	}
+	switch s.metricType {
+	case statsdCounter, statsdGauge, statsdTimer, statsdHistogram, statsdDistrib:
+		value, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return statsdSample{}, fmt.Errorf("invalid statsd value %q: %w", parts[0], err)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return statsdSample{}, fmt.Errorf("unsupported statsd value: %q", parts[0])
+		}
+		s.value = value
+		s.relative = s.metricType == statsdGauge && (strings.HasPrefix(parts[0], "+") || strings.HasPrefix(parts[0], "-"))
+	case statsdSet:
+		s.setValue = parts[0]
+	default:
+		return statsdSample{}, fmt.Errorf("unsupported statsd metric type %q: %q", s.metricType, line)
+	}
+
+	for _, p := range parts[2:] {
+		switch {
+		case strings.HasPrefix(p, "@"):
+			rate, err := strconv.ParseFloat(p[1:], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return statsdSample{}, fmt.Errorf("invalid statsd sample rate %q: %q", p, line)
+			}
+			s.sampleRate = rate
+		case strings.HasPrefix(p, "#"):
+			for _, tag := range strings.Split(p[1:], ",") {
+				if tag == "" {
+					continue
+				}
+				k, v, _ := strings.Cut(tag, ":")
+				if s.tags == nil {
+					s.tags = map[string]string{}
+				}
+				s.tags[k] = v
+			}
+		default:
+			return statsdSample{}, fmt.Errorf("invalid statsd line section %q: %q", p, line)
+		}
+	}
+	return s, nil
+}
+
+type statsdKey struct {
+	name string
+	tags string
+}
+
+func newStatsdKey(name string, tags map[string]string) statsdKey {
+	if len(tags) == 0 {
+		return statsdKey{name: name}
+	}
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return statsdKey{name: name, tags: strings.Join(pairs, ",")}
+}
+
+type statsdCounterState struct {
+	tags  map[string]string
+	value float64
+}
+
+type statsdGaugeState struct {
+	tags    map[string]string
+	value   float64
+	updated time.Time
+}
+
+type statsdTimerState struct {
+	tags   map[string]string
+	values []float64
+	// count respects sample rate, so it may differ from len(values).
+	count float64
+}
+
+type statsdSetState struct {
+	tags   map[string]string
+	values map[string]struct{}
+}
+
+// statsdAggregator accumulates StatsD samples between flushes, the same way
+// the reference StatsD daemon does: counters are summed, gauges keep the last
+// value, timers are summarized with percentiles and sets count unique values.
+// The number of metrics and values is limited, the samples over the limits
+// are dropped.
+type statsdAggregator struct {
+	mu       sync.Mutex
+	counters map[statsdKey]*statsdCounterState
+	gauges   map[statsdKey]*statsdGaugeState
+	timers   map[statsdKey]*statsdTimerState
+	sets     map[statsdKey]*statsdSetState
+	// now returns the time gauges are updated at.
+	now func() time.Time
+}
+
+func newStatsdAggregator() *statsdAggregator {
+	return &statsdAggregator{
+		now:      time.Now,
+		counters: map[statsdKey]*statsdCounterState{},
+		gauges:   map[statsdKey]*statsdGaugeState{},
+		timers:   map[statsdKey]*statsdTimerState{},
+		sets:     map[statsdKey]*statsdSetState{},
+	}
+}
+
+func (a *statsdAggregator) add(s statsdSample) {
+	key := newStatsdKey(s.name, s.tags)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch s.metricType {
+	case statsdCounter:
+		c, ok := a.counters[key]
+		if !ok {
+			if len(a.counters) >= maxStatsdKeys {
+				statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyKeys).Inc()
+				return
+			}
+			c = &statsdCounterState{tags: s.tags}
+			a.counters[key] = c
+		}
+		c.value += s.value / s.sampleRate
+	case statsdGauge:
+		g, ok := a.gauges[key]
+		if !ok {
+			if len(a.gauges) >= maxStatsdKeys {
+				statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyKeys).Inc()
+				return
+			}
+			g = &statsdGaugeState{tags: s.tags}
+			a.gauges[key] = g
+		}
+		if s.relative {
+			g.value += s.value
+		} else {
+			g.value = s.value
+		}
+		g.updated = a.now()
+	case statsdTimer, statsdHistogram, statsdDistrib:
+		t, ok := a.timers[key]
+		if !ok {
+			if len(a.timers) >= maxStatsdKeys {
+				statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyKeys).Inc()
+				return
+			}
+			t = &statsdTimerState{tags: s.tags}
+			a.timers[key] = t
+		}
+		if len(t.values) >= maxStatsdValues {
+			statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyValues).Inc()
+			return
+		}
+		t.values = append(t.values, s.value)
+		t.count += 1 / s.sampleRate
+	case statsdSet:
+		st, ok := a.sets[key]
+		if !ok {
+			if len(a.sets) >= maxStatsdKeys {
+				statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyKeys).Inc()
+				return
+			}
+			st = &statsdSetState{tags: s.tags, values: map[string]struct{}{}}
+			a.sets[key] = st
+		}
+		if _, ok := st.values[s.setValue]; !ok && len(st.values) >= maxStatsdValues {
+			statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyValues).Inc()
+			return
+		}
+		st.values[s.setValue] = struct{}{}
+	}
+}
+
+// flush returns aggregated metrics and resets state. Gauges keep their last
+// value between flushes, until they are not updated for
+// statsdGaugeExpiryIntervals intervals. All other metric types are reset.
+func (a *statsdAggregator) flush(now time.Time, interval time.Duration) []Metric {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	metrics := make([]Metric, 0, len(a.counters)+len(a.gauges)+len(a.timers)+len(a.sets))
+
+	for key, c := range a.counters {
+		fields := map[string]float64{"count": c.value}
+		if interval > 0 {
+			fields["rate"] = c.value / interval.Seconds()
+		}
+		metrics = append(metrics, Metric{Name: key.name, Tags: c.tags, Fields: fields, Time: now})
+	}
+	for key, g := range a.gauges {
+		if interval > 0 && now.Sub(g.updated) > statsdGaugeExpiryIntervals*interval {
+			delete(a.gauges, key)
+			continue
+		}
+		metrics = append(metrics, Metric{Name: key.name, Tags: g.tags, Fields: map[string]float64{"value": g.value}, Time: now})
+	}
+	for key, t := range a.timers {
+		metrics = append(metrics, Metric{Name: key.name, Tags: t.tags, Fields: timerFields(t), Time: now})
+	}
+	for key, st := range a.sets {
+		metrics = append(metrics, Metric{Name: key.name, Tags: st.tags, Fields: map[string]float64{"count": float64(len(st.values))}, Time: now})
+	}
+
+	a.counters = map[statsdKey]*statsdCounterState{}
+	a.timers = map[statsdKey]*statsdTimerState{}
+	a.sets = map[statsdKey]*statsdSetState{}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics
+}
+
+func timerFields(t *statsdTimerState) map[string]float64 {
+	values := t.values
+	sort.Float64s(values)
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return map[string]float64{
+		"count":  t.count,
+		"sum":    sum,
+		"min":    values[0],
+		"max":    values[len(values)-1],
+		"mean":   sum / float64(len(values)),
+		"median": percentile(values, 50),
+		"p90":    percentile(values, 90),
+		"p95":    percentile(values, 95),
+		"p99":    percentile(values, 99),
+	}
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,131 @@
+package pushlistener
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatsDLine(t *testing.T) {
+	s, err := parseStatsDLine("requests:3|c|@0.5|#service:api,env:prod")
+	require.NoError(t, err)
+	require.Equal(t, "requests", s.name)
+	require.Equal(t, statsdCounter, s.metricType)
+	require.Equal(t, 3.0, s.value)
+	require.Equal(t, 0.5, s.sampleRate)
+	require.Equal(t, map[string]string{"service": "api", "env": "prod"}, s.tags)
+
+	s, err = parseStatsDLine("temperature:-2|g")
+	require.NoError(t, err)
+	require.True(t, s.relative)
+
+	s, err = parseStatsDLine("users:alice|s")
+	require.NoError(t, err)
+	require.Equal(t, "alice", s.setValue)
+
+	for _, line := range []string{
+		"requests",
+		"requests:1",
+		"requests:abc|c",
+		"requests:1|x",
+		"requests:1|c|@2",
+		"requests:1|c|foo",
+	} {
+		_, err := parseStatsDLine(line)
+		require.Error(t, err, line)
+	}
+}
+
+func TestStatsDAggregator(t *testing.T) {
+	a := newStatsdAggregator()
+	add := func(line string) {
+		s, err := parseStatsDLine(line)
+		require.NoError(t, err)
+		a.add(s)
+	}
+
+	add("requests:1|c")
+	add("requests:1|c|@0.5")
+	add("temperature:20|g")
+	add("temperature:+2|g")
+	for i := 1; i <= 10; i++ {
+		add("latency:" + string(rune('0'+i%10)) + "|ms")
+	}
+	add("users:alice|s")
+	add("users:bob|s")
+	add("users:alice|s")
+
+	now := time.Unix(1700000000, 0)
+	metrics := a.flush(now, 10*time.Second)
+	require.Len(t, metrics, 4)
+
+	byName := map[string]Metric{}
+	for _, m := range metrics {
+		byName[m.Name] = m
+	}
+	require.Equal(t, map[string]float64{"count": 3, "rate": 0.3}, byName["requests"].Fields)
+	require.Equal(t, map[string]float64{"value": 22}, byName["temperature"].Fields)
+	require.Equal(t, map[string]float64{"count": 2}, byName["users"].Fields)
+
+	latency := byName["latency"].Fields
+	require.Equal(t, 10.0, latency["count"])
+	require.Equal(t, 0.0, latency["min"])
+	require.Equal(t, 9.0, latency["max"])
+	require.Equal(t, 45.0, latency["sum"])
+	require.Equal(t, 4.5, latency["mean"])
+	require.Equal(t, 4.0, latency["median"])
+	require.Equal(t, 8.0, latency["p90"])
+	require.Equal(t, 9.0, latency["p99"])
+
+	// Gauges keep their value between flushes, other metrics are reset.
+	metrics = a.flush(now, 10*time.Second)
+	require.Len(t, metrics, 1)
+	require.Equal(t, "temperature", metrics[0].Name)
+}
+
+func TestStatsDAggregatorLimits(t *testing.T) {
+	add := func(a *statsdAggregator, line string) {
+		s, err := parseStatsDLine(line)
+		require.NoError(t, err)
+		a.add(s)
+	}
+
+	t.Run("metrics over the key limit are dropped", func(t *testing.T) {
+		a := newStatsdAggregator()
+		dropped := testutil.ToFloat64(statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyKeys))
+		for i := 0; i <= maxStatsdKeys; i++ {
+			add(a, fmt.Sprintf("requests.%d:1|c", i))
+		}
+		require.Len(t, a.counters, maxStatsdKeys)
+		require.Equal(t, dropped+1, testutil.ToFloat64(statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyKeys)))
+
+		// The existing metrics are still updated
+		add(a, "requests.0:1|c")
+		require.Equal(t, 2.0, a.counters[newStatsdKey("requests.0", nil)].value)
+	})
+
+	t.Run("timer samples over the value limit are dropped", func(t *testing.T) {
+		a := newStatsdAggregator()
+		dropped := testutil.ToFloat64(statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyValues))
+		for i := 0; i < maxStatsdValues+10; i++ {
+			add(a, "latency:1|ms")
+		}
+		require.Len(t, a.timers[newStatsdKey("latency", nil)].values, maxStatsdValues)
+		require.Equal(t, dropped+10, testutil.ToFloat64(statsdDroppedTotal.WithLabelValues(statsdDropReasonTooManyValues)))
+	})
+
+	t.Run("gauges expire when they are not updated", func(t *testing.T) {
+		a := newStatsdAggregator()
+		updated := time.Unix(1700000000, 0)
+		a.now = func() time.Time { return updated }
+		add(a, "temperature:20|g")
+
+		interval := 10 * time.Second
+		require.Len(t, a.flush(updated.Add(statsdGaugeExpiryIntervals*interval), interval), 1)
+		require.Empty(t, a.flush(updated.Add((statsdGaugeExpiryIntervals+1)*interval), interval))
+		require.Empty(t, a.gauges)
+	})
+}
@@ -0,0 +1,514 @@
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+var logger = log.New("live.remote_write")
+
+const (
+	defaultShards            = 4
+	defaultMaxSamplesPerSend = 500
+	defaultBatchSendDeadline = 5 * time.Second
+	defaultCapacity          = 10000
+	defaultMaxRetries        = 5
+	defaultMinBackoff        = 100 * time.Millisecond
+	defaultMaxBackoff        = 10 * time.Second
+	defaultTimeout           = 5 * time.Second
+	defaultSpoolMaxBytes     = 256 * 1024 * 1024
+)
+
+const (
+	dropReasonQueueFull      = "queue_full"
+	dropReasonSendFailed     = "send_failed"
+	dropReasonNonRecoverable = "non_recoverable"
+	dropReasonSpoolFull      = "spool_full"
+	dropReasonEncode         = "encode_failed"
+)
+
+var (
+	pendingSamples = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "remote_write_pending_samples",
+		Help:      "Number of samples waiting in memory to be sent to remote write endpoint.",
+	}, []string{"queue"})
+	sentSamplesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "remote_write_sent_samples_total",
+		Help:      "Number of samples successfully sent to remote write endpoint.",
+	}, []string{"queue"})
+	droppedSamplesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "remote_write_dropped_samples_total",
+		Help:      "Number of samples dropped without being sent to remote write endpoint.",
+	}, []string{"queue", "reason"})
+	retriedRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "remote_write_retried_requests_total",
+		Help:      "Number of remote write requests retried after a recoverable error.",
+	}, []string{"queue"})
+	spooledSamplesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "remote_write_spooled_samples_total",
+		Help:      "Number of samples written to the on-disk spool.",
+	}, []string{"queue"})
+	spoolBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Subsystem: "live",
+		Name:      "remote_write_spool_bytes",
+		Help:      "Size of the on-disk spool in bytes.",
+	}, []string{"queue"})
+)
+
+// QueueConfig of remote write Queue. Zero values are replaced with defaults.
+type QueueConfig struct {
+	// Name identifies the queue in metrics.
+	Name string
+	// Endpoint to send samples to.
+	Endpoint string
+	// User and Password for optional basic auth.
+	User     string
+	Password string
+	// Shards is a number of concurrent senders. Series are assigned to shards
+	// by labels, so samples of a single series are sent in order.
+	Shards int
+	// MaxSamplesPerSend is a maximum number of samples in a single request.
+	MaxSamplesPerSend int
+	// BatchSendDeadline is the longest time samples wait in a shard before being sent.
+	BatchSendDeadline time.Duration
+	// Capacity is a maximum number of samples pending in a shard.
+	Capacity int
+	// MaxRetries of a request failed with a recoverable error.
+	MaxRetries int
+	// MinBackoff and MaxBackoff limit exponential backoff between retries.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Timeout of a single request.
+	Timeout time.Duration
+	// SpoolDir enables on-disk spooling when set. Batches which could not be
+	// sent after all retries or did not fit into shard capacity are written to
+	// disk and replayed when the endpoint recovers, also after restart.
+	SpoolDir string
+	// SpoolMaxBytes limits spool size, oldest segments are removed first.
+	SpoolMaxBytes int64
+}
+
+func (cfg QueueConfig) withDefaults() QueueConfig {
+	if cfg.Shards <= 0 {
+		cfg.Shards = defaultShards
+	}
+	if cfg.MaxSamplesPerSend <= 0 {
+		cfg.MaxSamplesPerSend = defaultMaxSamplesPerSend
+	}
+	if cfg.BatchSendDeadline <= 0 {
+		cfg.BatchSendDeadline = defaultBatchSendDeadline
+	}
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = defaultCapacity
+	}
+	if cfg.Capacity < cfg.MaxSamplesPerSend {
+		cfg.Capacity = cfg.MaxSamplesPerSend
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.SpoolMaxBytes <= 0 {
+		cfg.SpoolMaxBytes = defaultSpoolMaxBytes
+	}
+	return cfg
+}
+
+// Queue batches time series and sends them to remote write endpoint from
+// several shards with retries and exponential backoff. Unlike Prometheus
+// the queue never blocks producers: when a shard is full samples are spooled
+// to disk if spooling is enabled, and dropped otherwise.
+type Queue struct {
+	cfg    QueueConfig
+	client *http.Client
+	shards []*shard
+	spool  *spool
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	startOnce sync.Once
+	wg        sync.WaitGroup
+	// sleep is replaced in tests.
+	sleep func(context.Context, time.Duration) bool
+}
+
+type shard struct {
+	mu         sync.Mutex
+	pending    []prompb.TimeSeries
+	numSamples int
+	ready      chan struct{}
+}
+
+// NewQueue creates Queue. Sending starts on first Append or explicit Start,
+// so queues of rules which never receive data don't run any goroutines.
+func NewQueue(cfg QueueConfig) (*Queue, error) {
+	cfg = cfg.withDefaults()
+	q := &Queue{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		shards: make([]*shard, cfg.Shards),
+		sleep:  sleepContext,
+	}
+	for i := range q.shards {
+		q.shards[i] = &shard{ready: make(chan struct{}, 1)}
+	}
+	if cfg.SpoolDir != "" {
+		s, err := openSpool(cfg.SpoolDir, cfg.SpoolMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+		q.spool = s
+		spoolBytes.WithLabelValues(cfg.Name).Set(float64(s.size()))
+	}
+
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	return q, nil
+}
+
+// Start sending. It's safe to call Start several times. Queues with spool
+// should be started right away to replay samples left from previous runs.
+func (q *Queue) Start() {
+	q.startOnce.Do(func() {
+		for _, s := range q.shards {
+			q.wg.Add(1)
+			go func(s *shard) {
+				defer q.wg.Done()
+				q.runShard(q.ctx, s)
+			}(s)
+		}
+		if q.spool != nil {
+			q.wg.Add(1)
+			go func() {
+				defer q.wg.Done()
+				q.runReplay(q.ctx)
+			}()
+		}
+	})
+}
+
+// Append queues time series for sending. It never blocks.
+func (q *Queue) Append(series []prompb.TimeSeries) {
+	q.Start()
+	for _, ts := range series {
+		if len(ts.Samples) == 0 {
+			continue
+		}
+		s := q.shards[uint64(seriesKey(ts.Labels))%uint64(len(q.shards))]
+		q.appendToShard(s, ts)
+	}
+}
+
+func (q *Queue) appendToShard(s *shard, ts prompb.TimeSeries) {
+	s.mu.Lock()
+	if s.numSamples+len(ts.Samples) > q.cfg.Capacity {
+		if q.spool == nil {
+			s.mu.Unlock()
+			droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonQueueFull).Add(float64(len(ts.Samples)))
+			return
+		}
+		// Move everything pending to disk so new samples fit.
+		overflow, numSamples := s.pending, s.numSamples
+		s.pending, s.numSamples = nil, 0
+		pendingSamples.WithLabelValues(q.cfg.Name).Sub(float64(numSamples))
+		s.mu.Unlock()
+		q.writeSpool(overflow, numSamples)
+		s.mu.Lock()
+	}
+	s.pending = append(s.pending, ts)
+	s.numSamples += len(ts.Samples)
+	full := s.numSamples >= q.cfg.MaxSamplesPerSend
+	s.mu.Unlock()
+
+	pendingSamples.WithLabelValues(q.cfg.Name).Add(float64(len(ts.Samples)))
+	if full {
+		select {
+		case s.ready <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// take removes up to MaxSamplesPerSend samples from the shard. Series are
+// never split, so a batch may be larger when a single series is large.
+func (q *Queue) take(s *shard) ([]prompb.TimeSeries, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, numSamples := 0, 0
+	for n < len(s.pending) && numSamples < q.cfg.MaxSamplesPerSend {
+		numSamples += len(s.pending[n].Samples)
+		n++
+	}
+	if n == 0 {
+		return nil, 0
+	}
+	batch := make([]prompb.TimeSeries, n)
+	copy(batch, s.pending[:n])
+	s.pending = s.pending[n:]
+	s.numSamples -= numSamples
+	pendingSamples.WithLabelValues(q.cfg.Name).Sub(float64(numSamples))
+	return batch, numSamples
+}
+
+func (q *Queue) runShard(ctx context.Context, s *shard) {
+	ticker := time.NewTicker(q.cfg.BatchSendDeadline)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			q.drainShard(s)
+			return
+		case <-ticker.C:
+		case <-s.ready:
+		}
+		for {
+			batch, numSamples := q.take(s)
+			if numSamples == 0 {
+				break
+			}
+			q.sendBatch(ctx, batch, numSamples)
+			if numSamples < q.cfg.MaxSamplesPerSend {
+				break
+			}
+		}
+	}
+}
+
+// drainShard is called on Stop, pending samples are spooled if possible.
+func (q *Queue) drainShard(s *shard) {
+	s.mu.Lock()
+	pending, numSamples := s.pending, s.numSamples
+	s.pending, s.numSamples = nil, 0
+	s.mu.Unlock()
+	if numSamples == 0 {
+		return
+	}
+	pendingSamples.WithLabelValues(q.cfg.Name).Sub(float64(numSamples))
+	if q.spool != nil {
+		q.writeSpool(pending, numSamples)
+		return
+	}
+	droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonSendFailed).Add(float64(numSamples))
+}
+
+func (q *Queue) sendBatch(ctx context.Context, batch []prompb.TimeSeries, numSamples int) {
+	body, err := TimeSeriesToBytes(batch)
+	if err != nil {
+		logger.Error("Error encoding remote write request", "queue", q.cfg.Name, "error", err)
+		droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonEncode).Add(float64(numSamples))
+		return
+	}
+	err = q.sendWithRetries(ctx, body)
+	if err == nil {
+		sentSamplesTotal.WithLabelValues(q.cfg.Name).Add(float64(numSamples))
+		return
+	}
+	var nonRecoverable *nonRecoverableError
+	if errors.As(err, &nonRecoverable) {
+		logger.Error("Remote write endpoint rejected samples", "queue", q.cfg.Name, "numSamples", numSamples, "error", err)
+		droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonNonRecoverable).Add(float64(numSamples))
+		return
+	}
+	if q.spool != nil {
+		q.writeSpoolBody(body, numSamples)
+		return
+	}
+	logger.Error("Error sending to remote write endpoint", "queue", q.cfg.Name, "numSamples", numSamples, "error", err)
+	droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonSendFailed).Add(float64(numSamples))
+}
+
+func (q *Queue) writeSpool(batch []prompb.TimeSeries, numSamples int) {
+	body, err := TimeSeriesToBytes(batch)
+	if err != nil {
+		logger.Error("Error encoding remote write request", "queue", q.cfg.Name, "error", err)
+		droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonEncode).Add(float64(numSamples))
+		return
+	}
+	q.writeSpoolBody(body, numSamples)
+}
+
+func (q *Queue) writeSpoolBody(body []byte, numSamples int) {
+	dropped, err := q.spool.write(body, numSamples)
+	if dropped > 0 {
+		droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonSpoolFull).Add(float64(dropped))
+	}
+	if err != nil {
+		logger.Error("Error writing to remote write spool", "queue", q.cfg.Name, "error", err)
+		droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonSendFailed).Add(float64(numSamples))
+		return
+	}
+	spooledSamplesTotal.WithLabelValues(q.cfg.Name).Add(float64(numSamples))
+	spoolBytes.WithLabelValues(q.cfg.Name).Set(float64(q.spool.size()))
+}
+
+// runReplay sends spooled segments oldest first. Replay stops at the first
+// recoverable error and resumes on the next tick.
+func (q *Queue) runReplay(ctx context.Context) {
+	ticker := time.NewTicker(q.cfg.BatchSendDeadline)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		q.replay(ctx)
+	}
+}
+
+func (q *Queue) replay(ctx context.Context) {
+	for ctx.Err() == nil {
+		seg, ok := q.spool.oldest()
+		if !ok {
+			return
+		}
+		body, err := q.spool.read(seg)
+		if err != nil {
+			logger.Error("Error reading remote write spool segment", "queue", q.cfg.Name, "segment", seg.name, "error", err)
+			droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonSendFailed).Add(float64(seg.samples))
+			_ = q.spool.remove(seg)
+			continue
+		}
+		err = q.sendWithRetries(ctx, body)
+		var nonRecoverable *nonRecoverableError
+		switch {
+		case err == nil:
+			sentSamplesTotal.WithLabelValues(q.cfg.Name).Add(float64(seg.samples))
+		case errors.As(err, &nonRecoverable):
+			logger.Error("Remote write endpoint rejected spooled samples", "queue", q.cfg.Name, "numSamples", seg.samples, "error", err)
+			droppedSamplesTotal.WithLabelValues(q.cfg.Name, dropReasonNonRecoverable).Add(float64(seg.samples))
+		default:
+			logger.Debug("Remote write endpoint unavailable, keep spooled samples", "queue", q.cfg.Name, "error", err)
+			return
+		}
+		if err := q.spool.remove(seg); err != nil {
+			logger.Error("Error removing remote write spool segment", "queue", q.cfg.Name, "segment", seg.name, "error", err)
+			return
+		}
+		spoolBytes.WithLabelValues(q.cfg.Name).Set(float64(q.spool.size()))
+	}
+}
+
+// nonRecoverableError is returned when the endpoint rejects request with
+// 4xx status other than 429, retrying such requests won't help.
+type nonRecoverableError struct {
+	err error
+}
+
+func (e *nonRecoverableError) Error() string {
+	return e.err.Error()
+}
+
+func (q *Queue) sendWithRetries(ctx context.Context, body []byte) error {
+	backoff := q.cfg.MinBackoff
+	for attempt := 0; ; attempt++ {
+		err := q.send(ctx, body)
+		if err == nil {
+			return nil
+		}
+		var nonRecoverable *nonRecoverableError
+		if errors.As(err, &nonRecoverable) || attempt >= q.cfg.MaxRetries {
+			return err
+		}
+		retriedRequestsTotal.WithLabelValues(q.cfg.Name).Inc()
+		if !q.sleep(ctx, backoff) {
+			return err
+		}
+		backoff *= 2
+		if backoff > q.cfg.MaxBackoff {
+			backoff = q.cfg.MaxBackoff
+		}
+	}
+}
+
+func (q *Queue) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return &nonRecoverableError{err: fmt.Errorf("error constructing remote write request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if q.cfg.User != "" {
+		req.SetBasicAuth(q.cfg.User, q.cfg.Password)
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending remote write request: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("unexpected response code from remote write endpoint: %d", resp.StatusCode)
+	default:
+		return &nonRecoverableError{err: fmt.Errorf("unexpected response code from remote write endpoint: %d", resp.StatusCode)}
+	}
+}
+
+// Stop sending. Pending samples are spooled when spooling is enabled.
+func (q *Queue) Stop() {
+	q.cancel()
+	q.wg.Wait()
+	for _, s := range q.shards {
+		// Samples appended after Stop or to a never started queue.
+		q.drainShard(s)
+	}
+}
+
+// seriesKey does not depend on labels order, since labels created from
+// field labels map are not sorted.
+func seriesKey(labels []prompb.Label) metricKey {
+	sorted := make([]prompb.Label, len(labels))
+	copy(sorted, labels)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return makeMetricKey("", sorted)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,217 @@
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+)
+
+type testReceiver struct {
+	mu      sync.Mutex
+	samples int
+	fail    atomic.Bool
+	status  int
+}
+
+func (r *testReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.fail.Load() {
+		w.WriteHeader(r.status)
+		return
+	}
+	compressed, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	raw, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var wr prompb.WriteRequest
+	if err := proto.Unmarshal(raw, &wr); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	for _, ts := range wr.Timeseries {
+		r.samples += len(ts.Samples)
+	}
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *testReceiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.samples
+}
+
+func testSeries(name string, numSamples int) prompb.TimeSeries {
+	ts := prompb.TimeSeries{
+		Labels: []prompb.Label{{Name: "__name__", Value: name}},
+	}
+	for i := 0; i < numSamples; i++ {
+		ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: int64(i), Value: float64(i)})
+	}
+	return ts
+}
+
+func noSleep(_ context.Context, _ time.Duration) bool {
+	return true
+}
+
+func TestQueue_Send(t *testing.T) {
+	receiver := &testReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	q, err := NewQueue(QueueConfig{
+		Name:              "test",
+		Endpoint:          server.URL,
+		Shards:            2,
+		MaxSamplesPerSend: 10,
+		BatchSendDeadline: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer q.Stop()
+
+	q.Append([]prompb.TimeSeries{testSeries("a", 15), testSeries("b", 3), testSeries("c", 1)})
+	require.Eventually(t, func() bool {
+		return receiver.received() == 19
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestQueue_RetryWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	q, err := NewQueue(QueueConfig{Endpoint: server.URL, MinBackoff: time.Second, MaxBackoff: 3 * time.Second})
+	require.NoError(t, err)
+	var backoffs []time.Duration
+	q.sleep = func(_ context.Context, d time.Duration) bool {
+		backoffs = append(backoffs, d)
+		return true
+	}
+
+	body, err := TimeSeriesToBytes([]prompb.TimeSeries{testSeries("a", 1)})
+	require.NoError(t, err)
+	require.NoError(t, q.sendWithRetries(context.Background(), body))
+	require.Equal(t, int32(3), calls.Load())
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, backoffs)
+}
+
+func TestQueue_NonRecoverableError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	q, err := NewQueue(QueueConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+	q.sleep = noSleep
+
+	body, err := TimeSeriesToBytes([]prompb.TimeSeries{testSeries("a", 1)})
+	require.NoError(t, err)
+	err = q.sendWithRetries(context.Background(), body)
+	var nonRecoverable *nonRecoverableError
+	require.ErrorAs(t, err, &nonRecoverable)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestQueue_DropWhenFull(t *testing.T) {
+	q, err := NewQueue(QueueConfig{Endpoint: "http://localhost", Shards: 1, MaxSamplesPerSend: 5, Capacity: 5})
+	require.NoError(t, err)
+
+	// Queue is not started, so nothing is taken from the shard.
+	q.appendToShard(q.shards[0], testSeries("a", 4))
+	q.appendToShard(q.shards[0], testSeries("a", 4))
+	require.Equal(t, 4, q.shards[0].numSamples)
+}
+
+func TestQueue_SpoolAndReplay(t *testing.T) {
+	receiver := &testReceiver{status: http.StatusServiceUnavailable}
+	receiver.fail.Store(true)
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := QueueConfig{
+		Name:              "test",
+		Endpoint:          server.URL,
+		Shards:            1,
+		MaxSamplesPerSend: 10,
+		MaxRetries:        1,
+		SpoolDir:          dir,
+	}
+
+	q, err := NewQueue(cfg)
+	require.NoError(t, err)
+	q.sleep = noSleep
+
+	// Endpoint is down: batch is spooled after retries.
+	q.sendBatch(context.Background(), []prompb.TimeSeries{testSeries("a", 3)}, 3)
+	// Shard overflow: pending samples are spooled too.
+	q.cfg.Capacity = 10
+	q.appendToShard(q.shards[0], testSeries("b", 8))
+	q.appendToShard(q.shards[0], testSeries("b", 8))
+	require.Equal(t, 8, q.shards[0].numSamples)
+	// Stop spools everything left in memory.
+	q.Stop()
+
+	s, err := openSpool(dir, defaultSpoolMaxBytes)
+	require.NoError(t, err)
+	require.Len(t, s.segments, 3)
+
+	// After restart the spool is replayed in order once the endpoint is up.
+	receiver.fail.Store(false)
+	q, err = NewQueue(cfg)
+	require.NoError(t, err)
+	q.replay(context.Background())
+	require.Equal(t, 19, receiver.received())
+	_, ok := q.spool.oldest()
+	require.False(t, ok)
+	q.Stop()
+}
+
+func TestSpool_MaxBytes(t *testing.T) {
+	s, err := openSpool(t.TempDir(), 10)
+	require.NoError(t, err)
+
+	dropped, err := s.write([]byte("12345"), 1)
+	require.NoError(t, err)
+	require.Equal(t, 0, dropped)
+	dropped, err = s.write([]byte("12345"), 2)
+	require.NoError(t, err)
+	require.Equal(t, 0, dropped)
+	dropped, err = s.write([]byte("123"), 3)
+	require.NoError(t, err)
+	require.Equal(t, 1, dropped)
+	require.Equal(t, int64(8), s.size())
+
+	seg, ok := s.oldest()
+	require.True(t, ok)
+	require.Equal(t, 2, seg.samples)
+
+	_, err = s.write([]byte("12345678901"), 1)
+	require.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package remotewrite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const spoolSegmentExt = ".seg"
+
+// spoolSegment is a single remote write request body stored on disk.
+type spoolSegment struct {
+	name    string
+	seq     uint64
+	samples int
+	size    int64
+}
+
+// spool keeps remote write requests which could not be sent in a directory,
+// one file per request. Files are named by an increasing sequence number so
+// they are replayed in the order they were written, and survive restarts.
+type spool struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	nextSeq  uint64
+	segments []spoolSegment
+	bytes    int64
+}
+
+func openSpool(dir string, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("can't create spool directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("can't read spool directory: %w", err)
+	}
+	s := &spool{dir: dir, maxBytes: maxBytes}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			// Leftover of an interrupted write.
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+			continue
+		}
+		seg, ok := parseSegmentName(entry.Name())
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		seg.size = info.Size()
+		s.segments = append(s.segments, seg)
+		s.bytes += seg.size
+		if seg.seq >= s.nextSeq {
+			s.nextSeq = seg.seq + 1
+		}
+	}
+	sort.Slice(s.segments, func(i, j int) bool {
+		return s.segments[i].seq < s.segments[j].seq
+	})
+	return s, nil
+}
+
+// segment file name is <seq>-<samples>.seg.
+func parseSegmentName(name string) (spoolSegment, bool) {
+	base, ok := strings.CutSuffix(name, spoolSegmentExt)
+	if !ok {
+		return spoolSegment{}, false
+	}
+	seqStr, samplesStr, ok := strings.Cut(base, "-")
+	if !ok {
+		return spoolSegment{}, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return spoolSegment{}, false
+	}
+	samples, err := strconv.Atoi(samplesStr)
+	if err != nil {
+		return spoolSegment{}, false
+	}
+	return spoolSegment{name: name, seq: seq, samples: samples}, true
+}
+
+// write stores body as a new segment. Oldest segments are removed to stay
+// within maxBytes, number of samples removed that way is returned.
+func (s *spool) write(body []byte, samples int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := 0
+	for len(s.segments) > 0 && s.bytes+int64(len(body)) > s.maxBytes {
+		oldest := s.segments[0]
+		if err := os.Remove(filepath.Join(s.dir, oldest.name)); err != nil && !os.IsNotExist(err) {
+			return dropped, err
+		}
+		s.segments = s.segments[1:]
+		s.bytes -= oldest.size
+		dropped += oldest.samples
+	}
+	if int64(len(body)) > s.maxBytes {
+		return dropped, fmt.Errorf("request of %d bytes exceeds spool size limit", len(body))
+	}
+
+	seg := spoolSegment{
+		name:    fmt.Sprintf("%020d-%d%s", s.nextSeq, samples, spoolSegmentExt),
+		seq:     s.nextSeq,
+		samples: samples,
+		size:    int64(len(body)),
+	}
+	// Write to a temporary file first, so a crash never leaves a partial segment.
+	path := filepath.Join(s.dir, seg.name)
+	if err := os.WriteFile(path+".tmp", body, 0o640); err != nil {
+		return dropped, err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return dropped, err
+	}
+	s.nextSeq++
+	s.segments = append(s.segments, seg)
+	s.bytes += seg.size
+	return dropped, nil
+}
+
+func (s *spool) oldest() (spoolSegment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.segments) == 0 {
+		return spoolSegment{}, false
+	}
+	return s.segments[0], true
+}
+
+func (s *spool) read(seg spoolSegment) ([]byte, error) {
+	// nolint:gosec
+	return os.ReadFile(filepath.Join(s.dir, seg.name))
+}
+
+// remove deletes the segment. It's a no-op if the segment was already
+// removed to free space.
+func (s *spool) remove(seg spoolSegment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.segments {
+		if existing.seq != seg.seq {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, seg.name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		s.segments = append(s.segments[:i], s.segments[i+1:]...)
+		s.bytes -= seg.size
+		return nil
+	}
+	return nil
+}
+
+func (s *spool) size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bytes
+}
@@ -0,0 +1,131 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// ErrInterpolationNotAllowed is returned when a file that is not read from the provisioning directory
+// references the environment variables or files of the server. A literal $ is written as $$.
+var ErrInterpolationNotAllowed = errors.New("environment variables and file expansion are not supported in this file")
+
+// The fields that are provisioned with their raw value, so they are not interpolated
+var rawFields = map[reflect.Type][]string{
+	reflect.TypeOf(AlertRuleV1{}): {"Annotations"},
+	reflect.TypeOf(QueryV1{}):     {"Model"},
+}
+
+// checkNotInterpolated walks the parsed file and returns an error if any value was changed by
+// the interpolation, other than the $$ escape sequence
+func checkNotInterpolated(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return checkNotInterpolated(v.Elem(), path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkNotInterpolated(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		if !v.CanInterface() {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	var interpolated bool
+	switch val := v.Interface().(type) {
+	case values.StringValue:
+		interpolated = val.Value() != unescape(val.Raw)
+	case values.Int64Value:
+		interpolated = strings.Contains(val.Raw, "$")
+	case values.IntValue:
+		interpolated = strings.Contains(val.Raw, "$")
+	case values.BoolValue:
+		interpolated = strings.Contains(val.Raw, "$")
+	case values.StringMapValue:
+		for k, raw := range val.Raw {
+			interpolated = interpolated || val.Value()[k] != unescape(raw)
+		}
+	case values.JSONValue:
+		interpolated = !isUnescaped(val.Raw, val.Value())
+	case values.JSONSliceValue:
+		interpolated = !isUnescaped(val.Raw, val.Value())
+	default:
+		skip := rawFields[v.Type()]
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || slices.Contains(skip, field.Name) {
+				continue
+			}
+			if err := checkNotInterpolated(v.Field(i), path+"."+field.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if interpolated {
+		return fmt.Errorf("%s: %w", strings.TrimPrefix(path, "."), ErrInterpolationNotAllowed)
+	}
+	return nil
+}
+
+// isUnescaped checks the interpolated JSON value only differs from the raw value by the $$ escape sequence
+func isUnescaped(raw any, value any) bool {
+	switch r := raw.(type) {
+	case string:
+		s, ok := value.(string)
+		return ok && s == unescape(r)
+	case map[string]any:
+		m, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range r {
+			if !isUnescaped(v, m[k]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		s, ok := value.([]any)
+		if !ok || len(s) != len(r) {
+			return false
+		}
+		for i := range r {
+			if !isUnescaped(r[i], s[i]) {
+				return false
+			}
+		}
+		return true
+	case []map[string]any:
+		s, ok := value.([]map[string]any)
+		if !ok || len(s) != len(r) {
+			return false
+		}
+		for i := range r {
+			if !isUnescaped(r[i], s[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true // not a string, so nothing to interpolate
+	}
+}
+
+func unescape(s string) string {
+	return strings.ReplaceAll(s, "$$", "$")
+}
@@ -30,8 +30,17 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	}
 	logger.Info("starting to provision alerting")
 	logger.Debug("read all alerting files", "file_count", len(files))
+	if err := ProvisionFiles(ctx, logger, cfg, files); err != nil {
+		return err
+	}
+	logger.Info("finished to provision alerting")
+	return nil
+}
+
+// ProvisionFiles creates or updates the objects defined in the files and removes the ones listed for deletion
+func ProvisionFiles(ctx context.Context, logger log.Logger, cfg ProvisionerConfig, files []*AlertingFile) error {
 	cpProvisioner := NewContactPointProvisoner(logger, cfg.ContactPointService)
-	err = cpProvisioner.Provision(ctx, files)
+	err := cpProvisioner.Provision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("contact points: %w", err)
 	}
@@ -75,6 +84,5 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("contact points: %w", err)
 	}
-	logger.Info("finished to provision alerting")
 	return nil
 }
@@ -0,0 +1,627 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	authlib "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	compat "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// The files written when exporting the alerting configuration to a repository
+const (
+	ExportRulesFile         = "alert-rules.yaml"
+	ExportContactPointsFile = "contact-points.yaml"
+	ExportPoliciesFile      = "notification-policies.yaml"
+	ExportMuteTimingsFile   = "mute-timings.yaml"
+	ExportTemplatesFile     = "templates.yaml"
+)
+
+// ParseFile reads an alerting file that is not stored in the provisioning directory, such as a file
+// synced from a repository. All the objects are moved to the given organization and the values that
+// reference the environment variables or files of the server are rejected.
+func ParseFile(filename string, data []byte, orgID int64) (*AlertingFile, error) {
+	var fileV1 *AlertingFileV1
+	if err := yaml.Unmarshal(data, &fileV1); err != nil {
+		return nil, fmt.Errorf("failure to parse file %s: %w", filename, err)
+	}
+	if fileV1 == nil {
+		return nil, fmt.Errorf("empty file %s", filename)
+	}
+	if err := checkNotInterpolated(reflect.ValueOf(fileV1), ""); err != nil {
+		return nil, fmt.Errorf("failure to parse file %s: %w", filename, err)
+	}
+	fileV1.Filename = filename
+	file, err := fileV1.MapToModel()
+	if err != nil {
+		return nil, fmt.Errorf("failure to map file %s: %w", filename, err)
+	}
+	file.setOrgID(orgID)
+	return &file, nil
+}
+
+// setOrgID replaces the organization of all the objects in the file
+func (file *AlertingFile) setOrgID(orgID int64) {
+	for i := range file.Groups {
+		file.Groups[i].OrgID = orgID
+		for j := range file.Groups[i].Rules {
+			file.Groups[i].Rules[j].OrgID = orgID
+		}
+	}
+	for i := range file.DeleteRules {
+		file.DeleteRules[i].OrgID = orgID
+	}
+	for i := range file.ContactPoints {
+		file.ContactPoints[i].OrgID = orgID
+	}
+	for i := range file.DeleteContactPoints {
+		file.DeleteContactPoints[i].OrgID = orgID
+	}
+	for i := range file.Policies {
+		file.Policies[i].OrgID = orgID
+	}
+	for i := range file.ResetPolicies {
+		file.ResetPolicies[i] = OrgID(orgID)
+	}
+	for i := range file.MuteTimes {
+		file.MuteTimes[i].OrgID = orgID
+	}
+	for i := range file.DeleteMuteTimes {
+		file.DeleteMuteTimes[i].OrgID = orgID
+	}
+	for i := range file.Templates {
+		file.Templates[i].OrgID = orgID
+	}
+	for i := range file.DeleteTemplates {
+		file.DeleteTemplates[i].OrgID = orgID
+	}
+}
+
+// The kvstore namespace where the alerting objects applied from the files of each repository are recorded
+const repositoryKVNamespace = "alerting.repository"
+
+// ErrObjectManaged is returned when a repository file defines an alerting object that is managed elsewhere
+var ErrObjectManaged = errors.New("alerting object is managed elsewhere")
+
+// The provenance record types of the alerting objects
+var (
+	ruleType         = (&models.AlertRule{}).ResourceType()
+	contactPointType = (&definitions.EmbeddedContactPoint{}).ResourceType()
+	policiesType     = (&definitions.Route{}).ResourceType()
+	muteTimingType   = (&definitions.MuteTimeInterval{}).ResourceType()
+	templateType     = (&definitions.NotificationTemplate{}).ResourceType()
+)
+
+var objectTypeNames = map[string]string{
+	ruleType:         "alert rule",
+	contactPointType: "contact point",
+	policiesType:     "notification policy tree",
+	muteTimingType:   "mute timing",
+	templateType:     "template",
+}
+
+// repositoryObject identifies an alerting object by its provenance record
+type repositoryObject struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+}
+
+func (o repositoryObject) String() string {
+	if o.ID == "" {
+		return objectTypeNames[o.Type]
+	}
+	return fmt.Sprintf("%s %q", objectTypeNames[o.Type], o.ID)
+}
+
+// repositoryFile records the alerting objects applied from a repository file
+type repositoryFile struct {
+	Hash    string             `json:"hash"`
+	Objects []repositoryObject `json:"objects"`
+}
+
+// objects returns the alerting objects defined in the file
+func (file *AlertingFile) objects() []repositoryObject {
+	objects := []repositoryObject{}
+	for _, group := range file.Groups {
+		for _, rule := range group.Rules {
+			objects = append(objects, repositoryObject{Type: ruleType, ID: rule.UID})
+		}
+	}
+	for _, cp := range file.ContactPoints {
+		for _, receiver := range cp.ContactPoints {
+			objects = append(objects, repositoryObject{Type: contactPointType, ID: receiver.UID})
+		}
+	}
+	if len(file.Policies) > 0 {
+		objects = append(objects, repositoryObject{Type: policiesType})
+	}
+	for _, mt := range file.MuteTimes {
+		objects = append(objects, repositoryObject{Type: muteTimingType, ID: mt.MuteTime.Name})
+	}
+	for _, t := range file.Templates {
+		objects = append(objects, repositoryObject{Type: templateType, ID: t.Data.Name})
+	}
+	return objects
+}
+
+// removal returns a file that deletes the objects. The notification policy tree is not reset by the file,
+// it is released instead so the routing of the organization is kept.
+func removal(filename string, orgID int64, objects []repositoryObject) *AlertingFile {
+	removal := &AlertingFile{Filename: filename}
+	for _, o := range objects {
+		switch o.Type {
+		case ruleType:
+			removal.DeleteRules = append(removal.DeleteRules, RuleDelete{UID: o.ID, OrgID: orgID})
+		case contactPointType:
+			removal.DeleteContactPoints = append(removal.DeleteContactPoints, DeleteContactPoint{UID: o.ID, OrgID: orgID})
+		case muteTimingType:
+			removal.DeleteMuteTimes = append(removal.DeleteMuteTimes, DeleteMuteTime{Name: o.ID, OrgID: orgID})
+		case templateType:
+			removal.DeleteTemplates = append(removal.DeleteTemplates, DeleteTemplate{Name: o.ID, OrgID: orgID})
+		}
+	}
+	return removal
+}
+
+// RepositoryProvisioner applies the alerting files synced from a repository to the organization of the
+// repository namespace. The objects are saved with the file provenance, so they are read-only in the UI
+// and the API and can only be changed through the repository. The objects applied from each file are
+// recorded per repository, so a repository never changes the objects managed by another repository,
+// by the provisioning directory or by the API.
+type RepositoryProvisioner struct {
+	logger      log.Logger
+	cfg         ProvisionerConfig
+	provenances provisioning.ProvisioningStore
+	kv          kvstore.KVStore
+}
+
+func NewRepositoryProvisioner(cfg ProvisionerConfig, provenances provisioning.ProvisioningStore, kv kvstore.KVStore) *RepositoryProvisioner {
+	return &RepositoryProvisioner{
+		logger:      log.New("provisioning.alerting.repository"),
+		cfg:         cfg,
+		provenances: provenances,
+		kv:          kv,
+	}
+}
+
+// Validate checks the file can be applied by the repository, without changing anything
+func (p *RepositoryProvisioner) Validate(ctx context.Context, namespace string, repository string, path string, data []byte) error {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return err
+	}
+	file, err := ParseFile(path, data, orgID)
+	if err != nil {
+		return err
+	}
+	owned, err := p.repositoryFiles(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return p.checkOwnership(ctx, orgID, repository, path, file.objects(), owned)
+}
+
+// Apply creates or updates the objects defined in the file, and deletes the objects
+// that were applied from a previous version of the file
+func (p *RepositoryProvisioner) Apply(ctx context.Context, namespace string, repository string, path string, hash string, data []byte) error {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return err
+	}
+	file, err := ParseFile(path, data, orgID)
+	if err != nil {
+		return err
+	}
+	owned, err := p.repositoryFiles(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	objects := file.objects()
+	if err := p.checkOwnership(ctx, orgID, repository, path, objects, owned); err != nil {
+		return err
+	}
+	if err := p.keepSecureSettings(ctx, orgID, file); err != nil {
+		return err
+	}
+
+	files := owned[repository]
+	if files == nil {
+		files = map[string]*repositoryFile{}
+	}
+	var previous []repositoryObject
+	if f, ok := files[path]; ok {
+		previous = f.Objects
+	}
+
+	// Record the objects before applying them, so the ones applied before a failure stay owned by the file.
+	// The hash is only recorded once the file is applied, so a full sync applies it again.
+	files[path] = &repositoryFile{Objects: union(previous, objects)}
+	if err := p.save(ctx, orgID, repository, files); err != nil {
+		return err
+	}
+	if err := ProvisionFiles(ctx, p.logger, p.cfg, []*AlertingFile{file}); err != nil {
+		return err
+	}
+	if err := p.removeObjects(ctx, orgID, path, difference(previous, objects)); err != nil {
+		return err
+	}
+	files[path] = &repositoryFile{Hash: hash, Objects: objects}
+	return p.save(ctx, orgID, repository, files)
+}
+
+// Remove deletes the objects applied from the file, when the file is removed from the repository
+func (p *RepositoryProvisioner) Remove(ctx context.Context, namespace string, repository string, path string) error {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return err
+	}
+	files, err := p.load(ctx, orgID, repository)
+	if err != nil {
+		return err
+	}
+	f, ok := files[path]
+	if !ok {
+		return nil
+	}
+	if err := p.removeObjects(ctx, orgID, path, f.Objects); err != nil {
+		return err
+	}
+	delete(files, path)
+	return p.save(ctx, orgID, repository, files)
+}
+
+// List returns the hash of the applied files of the repository, by path
+func (p *RepositoryProvisioner) List(ctx context.Context, namespace string, repository string) (map[string]string, error) {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.load(ctx, orgID, repository)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string, len(files))
+	for path, f := range files {
+		hashes[path] = f.Hash
+	}
+	return hashes, nil
+}
+
+// checkOwnership verifies the objects are not managed by another repository file, or provisioned
+// by other means. The objects that are not provisioned yet are taken over by the repository.
+func (p *RepositoryProvisioner) checkOwnership(ctx context.Context, orgID int64, repository string, path string, objects []repositoryObject, owned map[string]map[string]*repositoryFile) error {
+	owners := map[repositoryObject]string{}
+	for r, files := range owned {
+		for filePath, f := range files {
+			for _, o := range f.Objects {
+				if r == repository && filePath == path {
+					owners[o] = ""
+					continue
+				}
+				owners[o] = fmt.Sprintf("%s in repository %s", filePath, r)
+			}
+		}
+	}
+
+	provenances := map[string]map[string]models.Provenance{}
+	for _, o := range objects {
+		owner, ok := owners[o]
+		if ok && owner != "" {
+			return fmt.Errorf("%w: %s is managed by %s", ErrObjectManaged, o, owner)
+		}
+		if ok {
+			continue // applied from this file before
+		}
+
+		byID, ok := provenances[o.Type]
+		if !ok {
+			var err error
+			byID, err = p.provenances.GetProvenances(ctx, orgID, o.Type)
+			if err != nil {
+				return fmt.Errorf("get provenances: %w", err)
+			}
+			provenances[o.Type] = byID
+		}
+		if provenance := byID[o.ID]; provenance != models.ProvenanceNone {
+			return fmt.Errorf("%w: %s is provisioned with the %s provenance", ErrObjectManaged, o, provenance)
+		}
+	}
+	return nil
+}
+
+// keepSecureSettings copies the stored secure settings of the existing contact points when they are
+// not set in the file, since the secure settings are never exported to a repository
+func (p *RepositoryProvisioner) keepSecureSettings(ctx context.Context, orgID int64, file *AlertingFile) error {
+	if len(file.ContactPoints) == 0 {
+		return nil
+	}
+	stored, err := p.cfg.ContactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{
+		OrgID:   orgID,
+		Decrypt: true,
+	}, provisionerUser(orgID))
+	if err != nil {
+		return fmt.Errorf("contact points: %w", err)
+	}
+	byUID := make(map[string]definitions.EmbeddedContactPoint, len(stored))
+	for _, cp := range stored {
+		byUID[cp.UID] = cp
+	}
+
+	for _, cp := range file.ContactPoints {
+		for _, receiver := range cp.ContactPoints {
+			existing, ok := byUID[receiver.UID]
+			if !ok || existing.Type != receiver.Type || existing.Settings == nil || receiver.Settings == nil {
+				continue
+			}
+			for _, key := range secureSettingsKeys(receiver.Type) {
+				branch := strings.Split(key, ".")
+				if receiver.Settings.GetPath(branch...).Interface() != nil {
+					continue
+				}
+				if value := existing.Settings.GetPath(branch...).Interface(); value != nil {
+					receiver.Settings.SetPath(branch, value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// removeObjects deletes the objects, and releases the notification policy tree of the organization
+func (p *RepositoryProvisioner) removeObjects(ctx context.Context, orgID int64, path string, objects []repositoryObject) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	if err := ProvisionFiles(ctx, p.logger, p.cfg, []*AlertingFile{removal(path, orgID, objects)}); err != nil {
+		return err
+	}
+	if slices.Contains(objects, repositoryObject{Type: policiesType}) {
+		if err := p.provenances.DeleteProvenance(ctx, &definitions.Route{}, orgID); err != nil {
+			return fmt.Errorf("notification policies: %w", err)
+		}
+	}
+	return nil
+}
+
+// repositoryFiles returns the applied files of all the repositories of the organization
+func (p *RepositoryProvisioner) repositoryFiles(ctx context.Context, orgID int64) (map[string]map[string]*repositoryFile, error) {
+	keys, err := p.kv.Keys(ctx, orgID, repositoryKVNamespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("read repository files: %w", err)
+	}
+	owned := make(map[string]map[string]*repositoryFile, len(keys))
+	for _, key := range keys {
+		files, err := p.load(ctx, orgID, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		owned[key.Key] = files
+	}
+	return owned, nil
+}
+
+// load returns the applied files of the repository
+func (p *RepositoryProvisioner) load(ctx context.Context, orgID int64, repository string) (map[string]*repositoryFile, error) {
+	files := map[string]*repositoryFile{}
+	value, ok, err := p.kv.Get(ctx, orgID, repositoryKVNamespace, repository)
+	if err != nil || !ok {
+		return files, err
+	}
+	if err := json.Unmarshal([]byte(value), &files); err != nil {
+		return nil, fmt.Errorf("read repository files of %s: %w", repository, err)
+	}
+	return files, nil
+}
+
+func (p *RepositoryProvisioner) save(ctx context.Context, orgID int64, repository string, files map[string]*repositoryFile) error {
+	if len(files) == 0 {
+		return p.kv.Del(ctx, orgID, repositoryKVNamespace, repository)
+	}
+	value, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+	return p.kv.Set(ctx, orgID, repositoryKVNamespace, repository, string(value))
+}
+
+func namespaceOrgID(namespace string) (int64, error) {
+	info, err := authlib.ParseNamespace(namespace)
+	if err != nil {
+		return 0, fmt.Errorf("invalid namespace %s: %w", namespace, err)
+	}
+	return info.OrgID, nil
+}
+
+// union returns the objects of a followed by the ones of b that are not in a
+func union(a, b []repositoryObject) []repositoryObject {
+	objects := slices.Clone(a)
+	for _, o := range b {
+		if !slices.Contains(objects, o) {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+// difference returns the objects of a that are not in b
+func difference(a, b []repositoryObject) []repositoryObject {
+	objects := []repositoryObject{}
+	for _, o := range a {
+		if !slices.Contains(b, o) {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+// secureSettingsKeys returns the paths of the secure settings of the contact point type
+func secureSettingsKeys(contactPointType string) []string {
+	keys, err := channels_config.GetSecretKeysForContactPointType(contactPointType)
+	if err != nil {
+		return nil // unknown types are rejected when the contact point is saved
+	}
+	return keys
+}
+
+// removeSecureSettings deletes the secure settings from the contact point
+func removeSecureSettings(cp definitions.EmbeddedContactPoint) {
+	if cp.Settings == nil {
+		return
+	}
+	for _, key := range secureSettingsKeys(cp.Type) {
+		branch := strings.Split(key, ".")
+		cp.Settings.GetPath(branch[:len(branch)-1]...).Del(branch[len(branch)-1])
+	}
+}
+
+// templateExport is the file format of the notification templates, which are not part of the alerting export
+type templateExport struct {
+	OrgID    int64  `json:"orgId" yaml:"orgId"`
+	Name     string `json:"name" yaml:"name"`
+	Template string `json:"template" yaml:"template"`
+}
+
+type templatesFileExport struct {
+	APIVersion int64            `json:"apiVersion" yaml:"apiVersion"`
+	Templates  []templateExport `json:"templates" yaml:"templates"`
+}
+
+// Export returns the alerting configuration of the namespace that is not provisioned yet, with one file
+// per kind of object. The secure settings of the contact points are not exported, the stored values are
+// kept when the files are applied.
+func (p *RepositoryProvisioner) Export(ctx context.Context, namespace string) (map[string][]byte, error) {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return nil, err
+	}
+	ctx, user := identity.WithServiceIdentity(ctx, orgID)
+
+	files := map[string][]byte{}
+	add := func(name string, body any) error {
+		data, err := yaml.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", name, err)
+		}
+		files[name] = data
+		return nil
+	}
+
+	// Alert rules
+	_, provenances, err := p.cfg.RuleService.GetAlertRules(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("alert rules: %w", err)
+	}
+	groups, err := p.cfg.RuleService.GetAlertGroupsWithFolderFullpath(ctx, user, nil)
+	if err != nil {
+		return nil, fmt.Errorf("alert rules: %w", err)
+	}
+	unmanaged := make([]models.AlertRuleGroupWithFolderFullpath, 0, len(groups))
+	for _, group := range groups {
+		rules := make([]models.AlertRule, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			if provenances[rule.UID] == models.ProvenanceNone {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) > 0 {
+			group.Rules = rules
+			unmanaged = append(unmanaged, group)
+		}
+	}
+	if len(unmanaged) > 0 {
+		export, err := compat.AlertingFileExportFromAlertRuleGroupWithFolderFullpath(unmanaged)
+		if err != nil {
+			return nil, fmt.Errorf("alert rules: %w", err)
+		}
+		if err := add(ExportRulesFile, compat.EscapeAlertingFileExport(export)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Contact points
+	cps, err := p.cfg.ContactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: orgID}, user)
+	if err != nil {
+		return nil, fmt.Errorf("contact points: %w", err)
+	}
+	unmanagedCPs := make([]definitions.EmbeddedContactPoint, 0, len(cps))
+	for _, cp := range cps {
+		if cp.Provenance == string(models.ProvenanceNone) {
+			removeSecureSettings(cp)
+			unmanagedCPs = append(unmanagedCPs, cp)
+		}
+	}
+	if len(unmanagedCPs) > 0 {
+		export, err := compat.AlertingFileExportFromEmbeddedContactPoints(orgID, unmanagedCPs)
+		if err != nil {
+			return nil, fmt.Errorf("contact points: %w", err)
+		}
+		if err := add(ExportContactPointsFile, compat.EscapeAlertingFileExport(export)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Notification policy tree
+	policies, _, err := p.cfg.NotificiationPolicyService.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("notification policies: %w", err)
+	}
+	if policies.Provenance == definitions.Provenance(models.ProvenanceNone) {
+		export, err := compat.AlertingFileExportFromRoute(orgID, policies)
+		if err != nil {
+			return nil, fmt.Errorf("notification policies: %w", err)
+		}
+		if err := add(ExportPoliciesFile, compat.EscapeAlertingFileExport(export)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Mute timings
+	timings, err := p.cfg.MuteTimingService.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("mute timings: %w", err)
+	}
+	unmanagedTimings := make([]definitions.MuteTimeInterval, 0, len(timings))
+	for _, timing := range timings {
+		if timing.Provenance == definitions.Provenance(models.ProvenanceNone) {
+			unmanagedTimings = append(unmanagedTimings, timing)
+		}
+	}
+	if len(unmanagedTimings) > 0 {
+		if err := add(ExportMuteTimingsFile, compat.AlertingFileExportFromMuteTimings(orgID, unmanagedTimings)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Notification templates
+	templates, err := p.cfg.TemplateService.GetTemplates(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	export := templatesFileExport{APIVersion: 1}
+	for _, t := range templates {
+		if t.Provenance == definitions.Provenance(models.ProvenanceNone) {
+			export.Templates = append(export.Templates, templateExport{OrgID: orgID, Name: t.Name, Template: t.Template})
+		}
+	}
+	if len(export.Templates) > 0 {
+		if err := add(ExportTemplatesFile, export); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,177 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+const testRepositoryContactPoint = `apiVersion: 1
+contactPoints:
+  - orgId: 1337
+    name: cp_1
+    receivers:
+    - uid: first_uid
+      type: prometheus-alertmanager
+      settings:
+        url: %s
+`
+
+func TestParseFile(t *testing.T) {
+	t.Run("the objects should be moved to the given organization", func(t *testing.T) {
+		data, err := os.ReadFile(testFileCorrectProperties + "/rules.yml")
+		require.NoError(t, err)
+		file, err := ParseFile("rules.yml", data, 3)
+		require.NoError(t, err)
+		require.Equal(t, "rules.yml", file.Filename)
+		require.Equal(t, int64(3), file.Groups[0].OrgID)
+		require.Equal(t, int64(3), file.Groups[0].Rules[0].OrgID)
+
+		data, err = os.ReadFile(testFileCorrectProperties_cp + "/contact_points.yml")
+		require.NoError(t, err)
+		file, err = ParseFile("contact_points.yml", data, 3)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), file.ContactPoints[0].OrgID)
+	})
+	t.Run("environment variables should be rejected", func(t *testing.T) {
+		t.Setenv("TEST_REPOSITORY_URL", "http://secret:9000")
+		_, err := ParseFile("cp.yml", []byte(fmt.Sprintf(testRepositoryContactPoint, "$TEST_REPOSITORY_URL")), 1)
+		require.ErrorIs(t, err, ErrInterpolationNotAllowed)
+	})
+	t.Run("file expansion should be rejected", func(t *testing.T) {
+		_, err := ParseFile("cp.yml", []byte(fmt.Sprintf(testRepositoryContactPoint, "$__file{/etc/hostname}")), 1)
+		require.ErrorIs(t, err, ErrInterpolationNotAllowed)
+	})
+	t.Run("an escaped dollar sign should be allowed", func(t *testing.T) {
+		file, err := ParseFile("cp.yml", []byte(fmt.Sprintf(testRepositoryContactPoint, "http://test:9000/$$path")), 1)
+		require.NoError(t, err)
+		require.Equal(t, "http://test:9000/$path", file.ContactPoints[0].ContactPoints[0].Settings.Get("url").MustString())
+	})
+	t.Run("templated annotations should be allowed", func(t *testing.T) {
+		data, err := os.ReadFile(testFileCorrectProperties + "/rules.yml")
+		require.NoError(t, err)
+		data = []byte(strings.Replace(string(data), "runbook: https://grafana.com", "summary: '{{ $labels.team }} is down'", 1))
+		file, err := ParseFile("rules.yml", data, 1)
+		require.NoError(t, err)
+		require.Equal(t, "{{ $labels.team }} is down", file.Groups[0].Rules[0].Annotations["summary"])
+	})
+	t.Run("an empty file should error", func(t *testing.T) {
+		_, err := ParseFile("empty.yml", []byte(""), 1)
+		require.Error(t, err)
+	})
+}
+
+func TestAlertingFileObjects(t *testing.T) {
+	data, err := os.ReadFile(testFileCorrectProperties + "/rules.yml")
+	require.NoError(t, err)
+	file, err := ParseFile("rules.yml", data, 2)
+	require.NoError(t, err)
+
+	objects := file.objects()
+	require.Equal(t, []repositoryObject{{Type: ruleType, ID: "my_first_rule"}}, objects)
+	deletion := removal(file.Filename, 2, objects)
+	require.Empty(t, deletion.Groups)
+	require.Equal(t, []RuleDelete{{UID: "my_first_rule", OrgID: 2}}, deletion.DeleteRules)
+
+	data, err = os.ReadFile(testFileCorrectProperties_cp + "/contact_points.yml")
+	require.NoError(t, err)
+	file, err = ParseFile("contact_points.yml", data, 2)
+	require.NoError(t, err)
+
+	objects = file.objects()
+	require.Equal(t, []repositoryObject{{Type: contactPointType, ID: "first_uid"}}, objects)
+	deletion = removal(file.Filename, 2, append(objects, repositoryObject{Type: policiesType}))
+	require.Empty(t, deletion.ContactPoints)
+	require.Empty(t, deletion.ResetPolicies) // the policy tree is released, not reset
+	require.Equal(t, []DeleteContactPoint{{UID: "first_uid", OrgID: 2}}, deletion.DeleteContactPoints)
+}
+
+func TestRepositoryProvisionerOwnership(t *testing.T) {
+	ctx := context.Background()
+	data, err := os.ReadFile(testFileCorrectProperties + "/rules.yml")
+	require.NoError(t, err)
+	rule := repositoryObject{Type: ruleType, ID: "my_first_rule"}
+
+	setup := func(t *testing.T) (*RepositoryProvisioner, *fakes.FakeProvisioningStore) {
+		provenances := fakes.NewFakeProvisioningStore()
+		return NewRepositoryProvisioner(ProvisionerConfig{}, provenances, kvstore.NewFakeKVStore()), provenances
+	}
+
+	t.Run("objects that are not provisioned can be taken over", func(t *testing.T) {
+		p, _ := setup(t)
+		require.NoError(t, p.Validate(ctx, "org-2", "repo", "rules.yml", data))
+	})
+	t.Run("objects provisioned by other means are refused", func(t *testing.T) {
+		p, provenances := setup(t)
+		require.NoError(t, provenances.SetProvenance(ctx, &models.AlertRule{UID: rule.ID}, 2, models.ProvenanceFile))
+		err := p.Validate(ctx, "org-2", "repo", "rules.yml", data)
+		require.ErrorIs(t, err, ErrObjectManaged)
+	})
+	t.Run("objects applied from the same file can be updated", func(t *testing.T) {
+		p, provenances := setup(t)
+		require.NoError(t, provenances.SetProvenance(ctx, &models.AlertRule{UID: rule.ID}, 2, models.ProvenanceFile))
+		require.NoError(t, p.save(ctx, 2, "repo", map[string]*repositoryFile{"rules.yml": {Hash: "h1", Objects: []repositoryObject{rule}}}))
+		require.NoError(t, p.Validate(ctx, "org-2", "repo", "rules.yml", data))
+
+		hashes, err := p.List(ctx, "org-2", "repo")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"rules.yml": "h1"}, hashes)
+	})
+	t.Run("objects applied from another repository or file are refused", func(t *testing.T) {
+		p, _ := setup(t)
+		require.NoError(t, p.save(ctx, 2, "other", map[string]*repositoryFile{"rules.yml": {Objects: []repositoryObject{rule}}}))
+		err := p.Validate(ctx, "org-2", "repo", "rules.yml", data)
+		require.ErrorIs(t, err, ErrObjectManaged)
+		require.ErrorContains(t, err, "repository other")
+
+		p, _ = setup(t)
+		require.NoError(t, p.save(ctx, 2, "repo", map[string]*repositoryFile{"other.yml": {Objects: []repositoryObject{rule}}}))
+		err = p.Validate(ctx, "org-2", "repo", "rules.yml", data)
+		require.ErrorIs(t, err, ErrObjectManaged)
+	})
+	t.Run("removing a file releases the notification policy tree", func(t *testing.T) {
+		p, provenances := setup(t)
+		require.NoError(t, provenances.SetProvenance(ctx, &definitions.Route{}, 2, models.ProvenanceFile))
+		require.NoError(t, p.save(ctx, 2, "repo", map[string]*repositoryFile{"policies.yml": {Objects: []repositoryObject{{Type: policiesType}}}}))
+
+		require.NoError(t, p.Remove(ctx, "org-2", "repo", "policies.yml"))
+		provenance, err := provenances.GetProvenance(ctx, &definitions.Route{}, 2)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+
+		hashes, err := p.List(ctx, "org-2", "repo")
+		require.NoError(t, err)
+		require.Empty(t, hashes)
+
+		// files that were never applied are ignored
+		require.NoError(t, p.Remove(ctx, "org-2", "repo", "unknown.yml"))
+	})
+}
+
+func TestRemoveSecureSettings(t *testing.T) {
+	settings := simplejson.NewFromAny(map[string]any{
+		"url":      "http://localhost",
+		"password": "secret",
+		"tlsConfig": map[string]any{
+			"insecureSkipVerify": true,
+			"clientKey":          "key",
+		},
+	})
+	removeSecureSettings(definitions.EmbeddedContactPoint{Type: "webhook", Settings: settings})
+	require.Equal(t, map[string]any{
+		"url": "http://localhost",
+		"tlsConfig": map[string]any{
+			"insecureSkipVerify": true,
+		},
+	}, settings.Interface())
+}
@@ -23,6 +23,7 @@ type DashboardProvisioner interface {
 	PollChanges(ctx context.Context)
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	ProvisionsFolder(orgID int64, uid, title string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
 }
 
@@ -148,6 +149,23 @@ func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// ProvisionsFolder checks if a provider puts its dashboards in the folder, so it creates the folder when it is missing.
+// The providers find their folder by uid, or by title when they have no folder uid.
+func (provider *Provisioner) ProvisionsFolder(orgID int64, uid, title string) bool {
+	for _, config := range provider.configs {
+		if config.OrgID != orgID {
+			continue
+		}
+		if config.FolderUID != "" && config.FolderUID == uid {
+			return true
+		}
+		if config.FolderUID == "" && config.Folder != "" && config.Folder == title {
+			return true
+		}
+	}
+	return false
+}
+
 func getFileReaders(
 	configs []*config,
 	logger log.Logger,
@@ -8,6 +8,7 @@ type calls struct {
 	PollChanges                 []any
 	GetProvisionerResolvedPath  []any
 	GetAllowUIUpdatesFromConfig []any
+	ProvisionsFolder            []any
 }
 
 // ProvisionerMock is a mock implementation of `Provisioner`
@@ -17,6 +18,7 @@ type ProvisionerMock struct {
 	PollChangesFunc                 func(ctx context.Context)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
+	ProvisionsFolderFunc            func(orgID int64, uid, title string) bool
 }
 
 // NewDashboardProvisionerMock returns a new dashboardprovisionermock
@@ -65,5 +67,14 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// ProvisionsFolder is a mock implementation of `Provisioner.ProvisionsFolder`
+func (dpm *ProvisionerMock) ProvisionsFolder(orgID int64, uid, title string) bool {
+	dpm.Calls.ProvisionsFolder = append(dpm.Calls.ProvisionsFolder, uid)
+	if dpm.ProvisionsFolderFunc != nil {
+		return dpm.ProvisionsFolderFunc(orgID, uid, title)
+	}
+	return false
+}
+
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
@@ -41,6 +41,12 @@ type FileReader struct {
 	FoldersFromFilesStructure    bool
 	folderService                folder.Service
 
+	// watch the path for changes instead of polling it, see watchChanges
+	watch             bool
+	debounceInterval  time.Duration
+	pollInterval      time.Duration
+	reconcileInterval time.Duration
+
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
@@ -65,6 +71,20 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
 	}
 
+	watch, _ := cfg.Options["watch"].(bool)
+	reconcileInterval := defaultReconcileInterval
+	switch seconds := cfg.Options["reconcileIntervalSeconds"].(type) {
+	case int:
+		reconcileInterval = time.Duration(seconds) * time.Second
+	case int64:
+		reconcileInterval = time.Duration(seconds) * time.Second
+	case float64:
+		reconcileInterval = time.Duration(seconds * float64(time.Second))
+	}
+	if reconcileInterval <= 0 {
+		return nil, fmt.Errorf("'reconcileIntervalSeconds' must be greater than zero")
+	}
+
 	return &FileReader{
 		Cfg:                          cfg,
 		Path:                         path,
@@ -73,13 +93,29 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		dashboardStore:               dashboardStore,
 		folderService:                folderService,
 		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		watch:                        watch,
+		debounceInterval:             defaultDebounceInterval,
+		pollInterval:                 time.Duration(cfg.UpdateIntervalSeconds) * time.Second,
+		reconcileInterval:            reconcileInterval,
 		usageTracker:                 newUsageTracker(),
 	}, nil
 }
 
 // pollChanges periodically runs walkDisk based on interval specified in the config.
+// When the watch option is set, the path is watched for changes instead, so they are saved without
+// waiting for the next poll. The path is then only walked every reconcile interval, as network file
+// systems like NFS accept the watches but do not report the changes made by other hosts.
+// Polling is used when the path can not be watched.
 func (fr *FileReader) pollChanges(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
+	if fr.watch {
+		err := fr.watchChanges(ctx)
+		if err == nil {
+			return
+		}
+		fr.log.Warn("Failed to watch for dashboard changes, falling back to polling", "path", fr.Path, "error", err)
+	}
+
+	ticker := time.NewTicker(fr.pollInterval)
 	for {
 		select {
 		case <-ticker.C:
@@ -163,7 +199,7 @@ func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnD
 			continue
 		}
 
-		usageTracker.track(provisioningMetadata)
+		usageTracker.track(path, provisioningMetadata)
 	}
 	return nil
 }
@@ -187,7 +223,7 @@ func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Cont
 		}
 
 		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, folderUID, fileInfo, dashboardRefs)
-		usageTracker.track(provisioningMetadata)
+		usageTracker.track(path, provisioningMetadata)
 		if err != nil {
 			fr.log.Error("failed to save dashboard", "file", path, "error", err)
 		}
@@ -544,15 +580,22 @@ func newUsageTracker() *usageTracker {
 	return &usageTracker{
 		uidUsage:   map[string]uint8{},
 		titleUsage: map[dashboardIdentity]uint8{},
+		files:      map[string]provisioningMetadata{},
 	}
 }
 
 type usageTracker struct {
 	uidUsage   map[string]uint8
 	titleUsage map[dashboardIdentity]uint8
+
+	// what each file provisioned, so the usage can be updated when only some files change
+	files map[string]provisioningMetadata
 }
 
-func (t *usageTracker) track(pm provisioningMetadata) {
+func (t *usageTracker) track(path string, pm provisioningMetadata) {
+	t.untrack(path)
+	t.files[path] = pm
+
 	if len(pm.uid) > 0 {
 		t.uidUsage[pm.uid]++
 	}
@@ -560,3 +603,32 @@ func (t *usageTracker) track(pm provisioningMetadata) {
 		t.titleUsage[pm.identity]++
 	}
 }
+
+func (t *usageTracker) untrack(path string) {
+	pm, ok := t.files[path]
+	if !ok {
+		return
+	}
+	delete(t.files, path)
+
+	if len(pm.uid) > 0 {
+		t.uidUsage[pm.uid]--
+		if t.uidUsage[pm.uid] == 0 {
+			delete(t.uidUsage, pm.uid)
+		}
+	}
+	if pm.identity.Exists() {
+		t.titleUsage[pm.identity]--
+		if t.titleUsage[pm.identity] == 0 {
+			delete(t.titleUsage, pm.identity)
+		}
+	}
+}
+
+func (t *usageTracker) clone() *usageTracker {
+	c := newUsageTracker()
+	for path, pm := range t.files {
+		c.track(path, pm)
+	}
+	return c
+}
@@ -0,0 +1,265 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+const (
+	// defaultDebounceInterval is how long the watcher waits for the events to stop before saving the changes.
+	// Editors and tools like kubectl write several events for a single change.
+	defaultDebounceInterval = time.Second
+	// maxDebounceIntervals limits how long a constant stream of events can delay saving the changes.
+	maxDebounceIntervals = 10
+	// defaultReconcileInterval is how often the whole watched path is walked when the provider
+	// does not set reconcileIntervalSeconds.
+	defaultReconcileInterval = time.Hour
+)
+
+// watchChanges watches the provider path for changes and only saves the files that changed.
+// The whole path is only walked when events were dropped, and every reconcile interval,
+// as network file systems do not always report changes.
+// It returns when the context is done, or with an error when the path can no longer be watched.
+func (fr *FileReader) watchChanges(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			fr.log.Warn("Failed to close watcher", "path", fr.Path, "error", err)
+		}
+	}()
+
+	resolvedPath := fr.resolvedPath()
+	if err := addWatches(watcher, resolvedPath); err != nil {
+		return err
+	}
+
+	// Changes between the last walk and the first watch would be lost
+	if err := fr.walkDisk(ctx); err != nil {
+		fr.log.Error("failed to search for dashboards", "error", err)
+	}
+
+	fr.log.Debug("Watching for dashboard changes", "path", resolvedPath)
+
+	timer := time.NewTimer(fr.debounceInterval)
+	timer.Stop()
+
+	var reconcile <-chan time.Time
+	if fr.reconcileInterval > 0 {
+		ticker := time.NewTicker(fr.reconcileInterval)
+		defer ticker.Stop()
+		reconcile = ticker.C
+	}
+
+	var firstEvent time.Time
+	fullSync := false
+	changed := map[string]struct{}{}
+	debounce := func() {
+		if firstEvent.IsZero() {
+			firstEvent = time.Now()
+		}
+		wait := fr.debounceInterval
+		if remaining := time.Until(firstEvent.Add(maxDebounceIntervals * fr.debounceInterval)); remaining < wait {
+			wait = max(remaining, 0)
+		}
+		timer.Reset(wait)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return errors.New("watcher closed")
+			}
+			if event.Name == resolvedPath && event.Has(fsnotify.Remove|fsnotify.Rename) {
+				return fmt.Errorf("path %s was removed", resolvedPath)
+			}
+
+			path, ok := changedPath(event)
+			if !ok {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				// New folders are not watched yet, so the files created in them are found by walking them
+				if info, err := os.Lstat(path); err == nil && info.IsDir() {
+					if err := addWatches(watcher, path); err != nil {
+						return err
+					}
+				}
+			}
+
+			changed[path] = struct{}{}
+			debounce()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return errors.New("watcher closed")
+			}
+			// Some events were dropped, so we do not know what changed
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				fr.log.Warn("Too many dashboard changes to watch, saving all the dashboards", "path", resolvedPath)
+				fullSync = true
+				debounce()
+				continue
+			}
+			fr.log.Error("Failed to watch dashboard changes", "path", resolvedPath, "error", err)
+
+		case <-reconcile:
+			if err := fr.walkDisk(ctx); err != nil {
+				fr.log.Error("failed to search for dashboards", "error", err)
+			}
+
+		case <-timer.C:
+			if fullSync {
+				err = fr.walkDisk(ctx)
+			} else {
+				err = fr.saveChangedFiles(ctx, changed)
+			}
+			if err != nil {
+				fr.log.Error("failed to save dashboard changes", "error", err)
+			}
+
+			firstEvent = time.Time{}
+			fullSync = false
+			changed = map[string]struct{}{}
+		}
+	}
+}
+
+// changedPath returns the path that must be saved for the event.
+// Hidden files and folders are skipped like when walking the disk, except for symlinks:
+// kubernetes config maps update the files by swapping a hidden symlink, so the whole folder is saved.
+func changedPath(event fsnotify.Event) (string, bool) {
+	if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) {
+		return "", false
+	}
+	if !strings.HasPrefix(filepath.Base(event.Name), ".") {
+		return event.Name, true
+	}
+	if !event.Has(fsnotify.Create) {
+		return "", false
+	}
+	info, err := os.Lstat(event.Name)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return "", false
+	}
+	return filepath.Dir(event.Name), true
+}
+
+// addWatches watches the folder and its sub folders, skipping the hidden ones
+func addWatches(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// saveChangedFiles is walkDisk for the changed paths only.
+// The paths can be files or folders, and may no longer exist.
+func (fr *FileReader) saveChangedFiles(ctx context.Context, changed map[string]struct{}) error {
+	fr.log.Debug("Saving changed dashboards", "path", fr.Path, "changes", len(changed))
+	resolvedPath := fr.resolvedPath()
+	if _, err := os.Stat(resolvedPath); err != nil {
+		return err
+	}
+
+	provisionedDashboardRefs, err := fr.getProvisionedDashboardsByPath(ctx, fr.dashboardProvisioningService, fr.Cfg.Name)
+	if err != nil {
+		return err
+	}
+
+	filesFoundOnDisk := map[string]os.FileInfo{}
+	for path := range changed {
+		info, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if err := filepath.Walk(path, createWalkFn(filesFoundOnDisk)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		if isValid, _ := validateWalkablePath(info); isValid {
+			filesFoundOnDisk[path] = info
+		}
+	}
+
+	// Only the dashboards in the changed paths can be missing
+	changedDashboardRefs := map[string]*dashboards.DashboardProvisioning{}
+	for path, ref := range provisionedDashboardRefs {
+		if inChangedPaths(path, changed) {
+			changedDashboardRefs[path] = ref
+		}
+	}
+	fr.handleMissingDashboardFiles(ctx, changedDashboardRefs, filesFoundOnDisk)
+
+	fr.mux.RLock()
+	usageTracker := fr.usageTracker.clone()
+	fr.mux.RUnlock()
+	for path := range usageTracker.files {
+		if inChangedPaths(path, changed) {
+			usageTracker.untrack(path)
+		}
+	}
+
+	if fr.FoldersFromFilesStructure {
+		err = fr.storeDashboardsInFoldersFromFileStructure(ctx, filesFoundOnDisk, provisionedDashboardRefs, resolvedPath, usageTracker)
+	} else {
+		err = fr.storeDashboardsInFolder(ctx, filesFoundOnDisk, provisionedDashboardRefs, usageTracker)
+	}
+	if err != nil {
+		return err
+	}
+
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+
+	fr.usageTracker = usageTracker
+	return nil
+}
+
+// inChangedPaths checks if the file is one of the changed paths, or in one of the changed folders
+func inChangedPaths(path string, changed map[string]struct{}) bool {
+	for dir := path; ; {
+		if _, ok := changed[dir]; ok {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,285 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func newWatchedReader(t *testing.T, service dashboards.DashboardProvisioningService) (*FileReader, string) {
+	t.Helper()
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	reader, err := NewDashboardFileReader(&config{
+		Name:    configName,
+		Type:    "file",
+		OrgID:   1,
+		Options: map[string]any{"path": dir, "watch": true},
+	}, log.New("test-logger"), service, &fakeDashboardStore{}, nil)
+	require.NoError(t, err)
+	require.True(t, reader.watch)
+
+	reader.debounceInterval = 10 * time.Millisecond
+	return reader, dir
+}
+
+func writeDashboard(t *testing.T, path, uid string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte(`{"uid":"`+uid+`","title":"`+uid+`"}`), 0600))
+}
+
+func savedPath(path string) any {
+	return mock.MatchedBy(func(dp *dashboards.DashboardProvisioning) bool {
+		return dp.ExternalID == path
+	})
+}
+
+func TestSaveChangedFiles(t *testing.T) {
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+	reader, dir := newWatchedReader(t, fakeService)
+
+	changed := filepath.Join(dir, "changed.json")
+	unchanged := filepath.Join(dir, "unchanged.json")
+	created := filepath.Join(dir, "folder", "created.json")
+	removed := filepath.Join(dir, "removed.json")
+	writeDashboard(t, changed, "changed")
+	writeDashboard(t, unchanged, "unchanged")
+	writeDashboard(t, created, "created")
+
+	reader.usageTracker.track(unchanged, provisioningMetadata{uid: "unchanged"})
+	reader.usageTracker.track(removed, provisioningMetadata{uid: "removed"})
+
+	fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return([]*dashboards.DashboardProvisioning{
+		{DashboardID: 1, Name: configName, ExternalID: changed, CheckSum: "old"},
+		{DashboardID: 2, Name: configName, ExternalID: unchanged, CheckSum: "old"},
+		{DashboardID: 3, Name: configName, ExternalID: removed, CheckSum: "old"},
+	}, nil).Once()
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, savedPath(changed)).Return(&dashboards.Dashboard{}, nil).Once()
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, savedPath(created)).Return(&dashboards.Dashboard{}, nil).Once()
+	fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(3), int64(1)).Return(nil).Once()
+
+	err := reader.saveChangedFiles(context.Background(), map[string]struct{}{
+		changed:                      {},
+		filepath.Join(dir, "folder"): {},
+		removed:                      {},
+	})
+	require.NoError(t, err)
+
+	// The unchanged files are still tracked
+	require.Equal(t, map[string]uint8{"changed": 1, "created": 1, "unchanged": 1}, reader.getUsageTracker().uidUsage)
+}
+
+func TestWatchChanges(t *testing.T) {
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	reader, dir := newWatchedReader(t, fakeService)
+
+	saved := make(chan string, 10)
+	fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil)
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			saved <- args.Get(2).(*dashboards.DashboardProvisioning).ExternalID
+		}).Return(&dashboards.Dashboard{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- reader.watchChanges(ctx)
+	}()
+
+	// A file can be saved twice when its events are split between two batches
+	waitForSave := func(expected string) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case path := <-saved:
+				if path == expected {
+					return
+				}
+			case err := <-done:
+				require.FailNow(t, "stopped watching", err)
+			case <-timeout:
+				require.FailNow(t, "dashboard was not saved", expected)
+			}
+		}
+	}
+
+	// Files are only saved once the watches are in place, so wait for the first event to be handled
+	require.Eventually(t, func() bool {
+		writeDashboard(t, filepath.Join(dir, "dashboard.json"), "dashboard")
+		select {
+		case path := <-saved:
+			return path == filepath.Join(dir, "dashboard.json")
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Files in new folders are found, even when they were written before the folder was watched
+	writeDashboard(t, filepath.Join(dir, "folder", "nested.json"), "nested")
+	waitForSave(filepath.Join(dir, "folder", "nested.json"))
+
+	writeDashboard(t, filepath.Join(dir, "folder", "later.json"), "later")
+	waitForSave(filepath.Join(dir, "folder", "later.json"))
+
+	// The watcher stops when the path is removed, so the reader can go back to polling
+	require.NoError(t, os.RemoveAll(dir))
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "still watching a removed path")
+	}
+}
+
+func TestWatchChanges_Reconciles(t *testing.T) {
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	reader, _ := newWatchedReader(t, fakeService)
+	reader.reconcileInterval = 10 * time.Millisecond
+
+	// Network file systems can be watched without reporting any change, so the path is still walked
+	walked := make(chan struct{}, 10)
+	fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).
+		Run(func(mock.Arguments) {
+			select {
+			case walked <- struct{}{}:
+			default:
+			}
+		}).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- reader.watchChanges(ctx)
+	}()
+
+	// The first walk happens once the watches are in place, the next ones are the reconciles
+	for i := 0; i < 3; i++ {
+		select {
+		case <-walked:
+		case err := <-done:
+			require.FailNow(t, "stopped watching", err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "path was not reconciled")
+		}
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestWatchChanges_DoesNotPoll(t *testing.T) {
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	reader, _ := newWatchedReader(t, fakeService)
+	reader.pollInterval = 10 * time.Millisecond
+	require.Equal(t, defaultReconcileInterval, reader.reconcileInterval)
+
+	walks := make(chan struct{}, 10)
+	fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).
+		Run(func(mock.Arguments) {
+			walks <- struct{}{}
+		}).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- reader.watchChanges(ctx)
+	}()
+
+	// Only the first walk happens while nothing changes
+	select {
+	case <-walks:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "path was not walked")
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+	require.Empty(t, walks)
+}
+
+func TestReconcileIntervalOption(t *testing.T) {
+	newReader := func(options map[string]any) (*FileReader, error) {
+		options["path"] = t.TempDir()
+		return NewDashboardFileReader(&config{Name: configName, Type: "file", OrgID: 1, Options: options},
+			log.New("test-logger"), &dashboards.FakeDashboardProvisioning{}, &fakeDashboardStore{}, nil)
+	}
+
+	reader, err := newReader(map[string]any{"watch": true, "reconcileIntervalSeconds": 600})
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, reader.reconcileInterval)
+
+	_, err = newReader(map[string]any{"watch": true, "reconcileIntervalSeconds": 0})
+	require.Error(t, err)
+}
+
+func TestWatchChanges_MissingPath(t *testing.T) {
+	reader, dir := newWatchedReader(t, &dashboards.FakeDashboardProvisioning{})
+	reader.Path = filepath.Join(dir, "missing")
+
+	err := reader.watchChanges(context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestChangedPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs extra privileges on windows")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.Symlink(dir, filepath.Join(dir, "..data")))
+
+	tests := []struct {
+		name     string
+		event    fsnotify.Event
+		expected string
+	}{
+		{
+			name:     "written file",
+			event:    fsnotify.Event{Name: filepath.Join(dir, "a.json"), Op: fsnotify.Write},
+			expected: filepath.Join(dir, "a.json"),
+		},
+		{
+			name:     "removed file",
+			event:    fsnotify.Event{Name: filepath.Join(dir, "a.json"), Op: fsnotify.Remove},
+			expected: filepath.Join(dir, "a.json"),
+		},
+		{
+			name:  "permissions",
+			event: fsnotify.Event{Name: filepath.Join(dir, "a.json"), Op: fsnotify.Chmod},
+		},
+		{
+			name:  "hidden file",
+			event: fsnotify.Event{Name: filepath.Join(dir, ".a.json.swp"), Op: fsnotify.Create},
+		},
+		{
+			name:     "hidden symlink, like in config maps",
+			event:    fsnotify.Event{Name: filepath.Join(dir, "..data"), Op: fsnotify.Create},
+			expected: dir,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := changedPath(tt.event)
+			require.Equal(t, tt.expected != "", ok)
+			require.Equal(t, tt.expected, path)
+		})
+	}
+}
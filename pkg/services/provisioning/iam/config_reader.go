@@ -0,0 +1,276 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+var (
+	ErrMissingUser       = errors.New("user is required")
+	ErrMissingName       = errors.New("name is required")
+	ErrMissingFolderUID  = errors.New("folder uid is required")
+	ErrInvalidRole       = errors.New("invalid role, must be one of None, Viewer, Editor or Admin")
+	ErrInvalidPermission = errors.New("invalid permission, must be one of View, Edit or Admin")
+	ErrInvalidGrantee    = errors.New("a folder permission must have exactly one of user, team, serviceAccount or role")
+	ErrManagedFolder     = errors.New("only the permissions of a managed folder can be provisioned")
+)
+
+// Folder permissions that can be provisioned
+var folderPermissions = []string{"View", "Edit", "Admin"}
+
+// Basic roles that can be granted folder permissions, None can not
+var folderPermissionRoles = []org.RoleType{org.RoleViewer, org.RoleEditor, org.RoleAdmin}
+
+type configReader struct {
+	log        log.Logger
+	orgService org.Service
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	var iamConfigs []*configs
+	cr.log.Debug("Looking for iam provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read iam provisioning files from directory", "path", path, "error", err)
+		return iamConfigs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing iam provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseConfig(path, file)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file.Name(), err)
+			}
+
+			if cfg != nil {
+				iamConfigs = append(iamConfigs, cfg)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, iamConfigs); err != nil {
+		return nil, err
+	}
+
+	return iamConfigs, nil
+}
+
+func (cr *configReader) parseConfig(path string, file fs.DirEntry) (*configs, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	if err := yaml.Unmarshal(yamlFile, &apiVersion); err != nil {
+		return nil, err
+	}
+	// Empty files are skipped
+	if apiVersion == nil {
+		return nil, nil
+	}
+	if apiVersion.APIVersion != 1 {
+		return nil, fmt.Errorf("unsupported apiVersion %d, the iam provisioning files must use apiVersion 1", apiVersion.APIVersion)
+	}
+
+	v1 := &configsV1{}
+	if err := yaml.Unmarshal(yamlFile, v1); err != nil {
+		return nil, err
+	}
+
+	return v1.mapToIAMFromConfig(), nil
+}
+
+// validate checks the required fields and sets the defaults.
+// Every referenced org must exist.
+func (cr *configReader) validate(ctx context.Context, iamConfigs []*configs) error {
+	orgExists := utils.NewOrgExistsChecker(cr.orgService)
+	checkedOrgs := map[int64]bool{}
+	checkOrg := func(orgID *int64) error {
+		if *orgID < 1 {
+			*orgID = 1
+		}
+		if checkedOrgs[*orgID] {
+			return nil
+		}
+		if err := orgExists(ctx, *orgID); err != nil {
+			return err
+		}
+		checkedOrgs[*orgID] = true
+		return nil
+	}
+
+	for _, cfg := range iamConfigs {
+		for _, member := range cfg.OrgMembers {
+			if member.User == "" {
+				return fmt.Errorf("org member: %w", ErrMissingUser)
+			}
+			if member.Role == "" {
+				member.Role = org.RoleViewer
+			}
+			if !member.Role.IsValid() {
+				return fmt.Errorf("org member %q: %w", member.User, ErrInvalidRole)
+			}
+			if err := checkOrg(&member.OrgID); err != nil {
+				return fmt.Errorf("org member %q: %w", member.User, err)
+			}
+		}
+
+		for _, team := range cfg.Teams {
+			if team.Name == "" {
+				return fmt.Errorf("team: %w", ErrMissingName)
+			}
+			if err := checkOrg(&team.OrgID); err != nil {
+				return fmt.Errorf("team %q: %w", team.Name, err)
+			}
+		}
+
+		for _, sa := range cfg.ServiceAccounts {
+			if sa.Name == "" {
+				return fmt.Errorf("service account: %w", ErrMissingName)
+			}
+			if sa.Role == "" {
+				sa.Role = org.RoleViewer
+			}
+			if !sa.Role.IsValid() {
+				return fmt.Errorf("service account %q: %w", sa.Name, ErrInvalidRole)
+			}
+			if err := checkOrg(&sa.OrgID); err != nil {
+				return fmt.Errorf("service account %q: %w", sa.Name, err)
+			}
+		}
+
+		for _, folder := range cfg.Folders {
+			if folder.UID == "" {
+				return fmt.Errorf("folder %q: %w", folder.Title, ErrMissingFolderUID)
+			}
+			if folder.UID == accesscontrol.GeneralFolderUID {
+				return fmt.Errorf("folder %q: %w", folder.UID, dashboards.ErrFolderInvalidUID)
+			}
+			if folder.Title == "" {
+				folder.Title = folder.UID
+			}
+			if err := checkOrg(&folder.OrgID); err != nil {
+				return fmt.Errorf("folder %q: %w", folder.UID, err)
+			}
+			for _, p := range folder.Permissions {
+				if err := validateFolderPermission(p); err != nil {
+					return fmt.Errorf("folder %q: %w", folder.UID, err)
+				}
+			}
+		}
+
+		for _, member := range cfg.DeleteOrgMembers {
+			if member.User == "" {
+				return fmt.Errorf("deleted org member: %w", ErrMissingUser)
+			}
+			if err := checkOrg(&member.OrgID); err != nil {
+				return fmt.Errorf("deleted org member %q: %w", member.User, err)
+			}
+		}
+
+		for _, team := range cfg.DeleteTeams {
+			if team.Name == "" {
+				return fmt.Errorf("deleted team: %w", ErrMissingName)
+			}
+			if err := checkOrg(&team.OrgID); err != nil {
+				return fmt.Errorf("deleted team %q: %w", team.Name, err)
+			}
+		}
+
+		for _, sa := range cfg.DeleteServiceAccounts {
+			if sa.Name == "" {
+				return fmt.Errorf("deleted service account: %w", ErrMissingName)
+			}
+			if err := checkOrg(&sa.OrgID); err != nil {
+				return fmt.Errorf("deleted service account %q: %w", sa.Name, err)
+			}
+		}
+	}
+
+	return validateUniqueness(iamConfigs)
+}
+
+func validateFolderPermission(p *folderPermissionFromConfig) error {
+	grantees := 0
+	for _, grantee := range []string{p.User, p.Team, p.ServiceAccount, p.Role} {
+		if grantee != "" {
+			grantees++
+		}
+	}
+	if grantees != 1 {
+		return ErrInvalidGrantee
+	}
+
+	if p.Role != "" && !slices.Contains(folderPermissionRoles, org.RoleType(p.Role)) {
+		return fmt.Errorf("role %q: %w", p.Role, ErrInvalidRole)
+	}
+	if !slices.Contains(folderPermissions, p.Permission) {
+		return fmt.Errorf("permission %q: %w", p.Permission, ErrInvalidPermission)
+	}
+	return nil
+}
+
+// validateUniqueness makes sure the files do not disagree on the state of an object
+func validateUniqueness(iamConfigs []*configs) error {
+	orgMembers := map[string]bool{}
+	teams := map[string]bool{}
+	serviceAccounts := map[string]bool{}
+	folders := map[string]bool{}
+
+	for _, cfg := range iamConfigs {
+		for _, member := range cfg.OrgMembers {
+			key := fmt.Sprintf("%d/%s", member.OrgID, strings.ToLower(member.User))
+			if orgMembers[key] {
+				return fmt.Errorf("org member %q is provisioned more than once in org %d", member.User, member.OrgID)
+			}
+			orgMembers[key] = true
+		}
+		for _, team := range cfg.Teams {
+			key := fmt.Sprintf("%d/%s", team.OrgID, team.Name)
+			if teams[key] {
+				return fmt.Errorf("team %q is provisioned more than once in org %d", team.Name, team.OrgID)
+			}
+			teams[key] = true
+		}
+		for _, sa := range cfg.ServiceAccounts {
+			key := fmt.Sprintf("%d/%s", sa.OrgID, sa.Name)
+			if serviceAccounts[key] {
+				return fmt.Errorf("service account %q is provisioned more than once in org %d", sa.Name, sa.OrgID)
+			}
+			serviceAccounts[key] = true
+		}
+		for _, folder := range cfg.Folders {
+			key := fmt.Sprintf("%d/%s", folder.OrgID, folder.UID)
+			if folders[key] {
+				return fmt.Errorf("folder %q is provisioned more than once in org %d", folder.UID, folder.OrgID)
+			}
+			folders[key] = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package iam
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+var (
+	logger log.Logger = log.New("fake.log")
+
+	allProperties     = "testdata/all-properties"
+	deleteObjects     = "testdata/delete-objects"
+	withoutDefaults   = "testdata/appliedDefaults"
+	invalidPermission = "testdata/invalid-permission"
+	invalidGrantee    = "testdata/invalid-grantee"
+	duplicateTeam     = "testdata/duplicate-team"
+	versionZero       = "testdata/version-0"
+)
+
+func newTestConfigReader(orgService org.Service) *configReader {
+	if orgService == nil {
+		orgService = &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}
+	}
+	return &configReader{log: logger, orgService: orgService}
+}
+
+func TestIAMAsConfig(t *testing.T) {
+	t.Run("can read all properties", func(t *testing.T) {
+		t.Setenv("EDITOR_EMAIL", "editor@example.com")
+
+		cfgs, err := newTestConfigReader(nil).readConfig(context.Background(), allProperties)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+		cfg := cfgs[0]
+
+		require.Equal(t, []*orgMemberFromConfig{
+			{OrgID: 1, User: "viewer", Role: org.RoleViewer},
+			{OrgID: 1, User: "editor@example.com", Role: org.RoleEditor},
+		}, cfg.OrgMembers)
+
+		require.Equal(t, []*teamFromConfig{{
+			OrgID:   1,
+			Name:    "Platform",
+			Email:   "platform@example.com",
+			Members: []string{"viewer"},
+			Admins:  []string{"editor@example.com"},
+		}}, cfg.Teams)
+
+		require.Equal(t, []*serviceAccountFromConfig{
+			{OrgID: 1, Name: "ci", Role: org.RoleEditor, Roles: []string{"fixed:dashboards:writer"}, RemoveRoles: []string{"fixed:folders:writer"}},
+			{OrgID: 1, Name: "legacy", Role: org.RoleViewer, IsDisabled: true, Roles: []string{}, RemoveRoles: []string{}},
+		}, cfg.ServiceAccounts)
+
+		require.Len(t, cfg.Folders, 2)
+		require.Equal(t, &folderFromConfig{
+			OrgID:       1,
+			UID:         "platform",
+			Title:       "Platform",
+			Description: "Dashboards of the platform team",
+			Permissions: []*folderPermissionFromConfig{
+				{Team: "Platform", Permission: "Admin"},
+				{ServiceAccount: "ci", Permission: "Edit"},
+				{User: "viewer", Permission: "View"},
+				{Role: "Viewer", Permission: "View"},
+			},
+		}, cfg.Folders[0])
+		require.Equal(t, "platform", cfg.Folders[1].ParentUID)
+		require.Nil(t, cfg.Folders[1].Permissions, "permissions are not managed when they are not set")
+	})
+
+	t.Run("can read the objects to delete", func(t *testing.T) {
+		cfgs, err := newTestConfigReader(nil).readConfig(context.Background(), deleteObjects)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		require.Equal(t, []*deleteOrgMemberConfig{{OrgID: 1, User: "viewer"}}, cfgs[0].DeleteOrgMembers)
+		require.Equal(t, []*deleteByNameConfig{{OrgID: 1, Name: "Platform"}, {OrgID: 1, Name: "Missing"}}, cfgs[0].DeleteTeams)
+		require.Equal(t, []*deleteByNameConfig{{OrgID: 1, Name: "ci"}}, cfgs[0].DeleteServiceAccounts)
+	})
+
+	t.Run("applies the defaults", func(t *testing.T) {
+		cfgs, err := newTestConfigReader(nil).readConfig(context.Background(), withoutDefaults)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		require.Equal(t, org.RoleViewer, cfgs[0].OrgMembers[0].Role)
+		require.Equal(t, int64(1), cfgs[0].OrgMembers[0].OrgID)
+		require.Equal(t, org.RoleViewer, cfgs[0].ServiceAccounts[0].Role)
+		require.Equal(t, "platform", cfgs[0].Folders[0].Title)
+	})
+
+	t.Run("missing folder is not an error", func(t *testing.T) {
+		cfgs, err := newTestConfigReader(nil).readConfig(context.Background(), "testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, cfgs)
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			path     string
+			expected error
+		}{
+			{name: "invalid permission", path: invalidPermission, expected: ErrInvalidPermission},
+			{name: "more than one grantee", path: invalidGrantee, expected: ErrInvalidGrantee},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := newTestConfigReader(nil).readConfig(context.Background(), tt.path)
+				require.ErrorIs(t, err, tt.expected)
+			})
+		}
+	})
+
+	t.Run("the same team can not be provisioned twice", func(t *testing.T) {
+		_, err := newTestConfigReader(nil).readConfig(context.Background(), duplicateTeam)
+		require.ErrorContains(t, err, `team "Platform" is provisioned more than once in org 1`)
+	})
+
+	t.Run("apiVersion 1 is required", func(t *testing.T) {
+		_, err := newTestConfigReader(nil).readConfig(context.Background(), versionZero)
+		require.ErrorContains(t, err, "unsupported apiVersion 0")
+	})
+
+	t.Run("the org must exist", func(t *testing.T) {
+		orgFake := &orgtest.FakeOrgService{ExpectedError: org.ErrOrgNotFound}
+		_, err := newTestConfigReader(orgFake).readConfig(context.Background(), allProperties)
+		require.ErrorIs(t, err, org.ErrOrgNotFound)
+	})
+}
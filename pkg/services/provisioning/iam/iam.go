@@ -0,0 +1,560 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type UserService interface {
+	GetByLogin(ctx context.Context, query *user.GetUserByLoginQuery) (*user.User, error)
+}
+
+type TeamService interface {
+	CreateTeam(ctx context.Context, cmd *team.CreateTeamCommand) (team.Team, error)
+	UpdateTeam(ctx context.Context, cmd *team.UpdateTeamCommand) error
+	DeleteTeam(ctx context.Context, cmd *team.DeleteTeamCommand) error
+	SearchTeams(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error)
+	GetTeamMembers(ctx context.Context, query *team.GetTeamMembersQuery) ([]*team.TeamMemberDTO, error)
+}
+
+// RoleService removes the roles and permissions of the deleted teams and assigns the service account roles
+type RoleService interface {
+	DeleteTeamPermissions(ctx context.Context, orgID, teamID int64) error
+	SyncUserRoles(ctx context.Context, orgID int64, cmd accesscontrol.SyncUserRolesCommand) error
+}
+
+type ServiceAccountService interface {
+	RetrieveServiceAccount(ctx context.Context, query *serviceaccounts.GetServiceAccountQuery) (*serviceaccounts.ServiceAccountProfileDTO, error)
+	RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error)
+	CreateServiceAccount(ctx context.Context, orgID int64, saForm *serviceaccounts.CreateServiceAccountForm) (*serviceaccounts.ServiceAccountDTO, error)
+	UpdateServiceAccount(ctx context.Context, orgID, serviceAccountID int64, saForm *serviceaccounts.UpdateServiceAccountForm) (*serviceaccounts.ServiceAccountProfileDTO, error)
+	DeleteServiceAccount(ctx context.Context, orgID, serviceAccountID int64) error
+}
+
+type FolderService interface {
+	Get(ctx context.Context, q *folder.GetFolderQuery) (*folder.Folder, error)
+	Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error)
+	Update(ctx context.Context, cmd *folder.UpdateFolderCommand) (*folder.Folder, error)
+	Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error)
+}
+
+// PermissionsService is used for the team memberships and the folder permissions
+type PermissionsService interface {
+	GetPermissions(ctx context.Context, user identity.Requester, resourceID string) ([]accesscontrol.ResourcePermission, error)
+	SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error)
+	MapActions(permission accesscontrol.ResourcePermission) string
+}
+
+// DashboardFolders tells which folders the dashboard provisioning puts its dashboards in
+type DashboardFolders interface {
+	ProvisionsFolder(orgID int64, uid, title string) bool
+}
+
+type ProvisionerConfig struct {
+	Path                     string
+	OrgService               org.Service
+	UserService              UserService
+	TeamService              TeamService
+	RoleService              RoleService
+	TeamPermissionsService   accesscontrol.TeamPermissionsService
+	ServiceAccountService    ServiceAccountService
+	FolderService            FolderService
+	FolderPermissionsService PermissionsService
+	DashboardFolders         DashboardFolders
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the org members, teams, service accounts and folders in those files.
+// Users are not created, they must exist (e.g. be synced from an identity provider) to be provisioned.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.iam")
+	p := newIAMProvisioner(logger, cfg)
+	return p.applyChanges(ctx, cfg.Path)
+}
+
+// IAMProvisioner is responsible for provisioning the identity objects based on
+// configuration read by the `configReader`
+type IAMProvisioner struct {
+	log               log.Logger
+	cfgProvider       *configReader
+	orgService        org.Service
+	userService       UserService
+	teamService       TeamService
+	roleService       RoleService
+	teamPermissions   accesscontrol.TeamPermissionsService
+	serviceAccounts   ServiceAccountService
+	folderService     FolderService
+	folderPermissions PermissionsService
+	dashboardFolders  DashboardFolders
+}
+
+func newIAMProvisioner(logger log.Logger, cfg ProvisionerConfig) *IAMProvisioner {
+	return &IAMProvisioner{
+		log:               logger,
+		cfgProvider:       &configReader{log: logger, orgService: cfg.OrgService},
+		orgService:        cfg.OrgService,
+		userService:       cfg.UserService,
+		teamService:       cfg.TeamService,
+		roleService:       cfg.RoleService,
+		teamPermissions:   cfg.TeamPermissionsService,
+		serviceAccounts:   cfg.ServiceAccountService,
+		folderService:     cfg.FolderService,
+		folderPermissions: cfg.FolderPermissionsService,
+		dashboardFolders:  cfg.DashboardFolders,
+	}
+}
+
+// applyChanges deletes the objects listed for deletion first, then creates or updates the others.
+// The teams and service accounts are provisioned before the folders, so the folder permissions can reference them.
+func (p *IAMProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := p.deleteObjects(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, member := range cfg.OrgMembers {
+			if err := p.provisionOrgMember(ctx, member); err != nil {
+				return fmt.Errorf("org member %q: %w", member.User, err)
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, t := range cfg.Teams {
+			if err := p.provisionTeam(ctx, t); err != nil {
+				return fmt.Errorf("team %q: %w", t.Name, err)
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, sa := range cfg.ServiceAccounts {
+			if err := p.provisionServiceAccount(ctx, sa); err != nil {
+				return fmt.Errorf("service account %q: %w", sa.Name, err)
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, f := range cfg.Folders {
+			if err := p.provisionFolder(ctx, f); err != nil {
+				return fmt.Errorf("folder %q: %w", f.UID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *IAMProvisioner) deleteObjects(ctx context.Context, cfg *configs) error {
+	for _, member := range cfg.DeleteOrgMembers {
+		u, err := p.getUser(ctx, member.User)
+		if errors.Is(err, user.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("deleted org member %q: %w", member.User, err)
+		}
+
+		p.log.Info("removing org member from configuration", "user", member.User, "orgId", member.OrgID)
+		if err := p.orgService.RemoveOrgUser(ctx, &org.RemoveOrgUserCommand{UserID: u.ID, OrgID: member.OrgID}); err != nil {
+			return fmt.Errorf("deleted org member %q: %w", member.User, err)
+		}
+	}
+
+	for _, t := range cfg.DeleteTeams {
+		ctx, requester := identity.WithServiceIdentity(ctx, t.OrgID)
+		existing, err := p.getTeam(ctx, requester, t.OrgID, t.Name)
+		if errors.Is(err, team.ErrTeamNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("deleted team %q: %w", t.Name, err)
+		}
+
+		p.log.Info("deleting team from configuration", "name", t.Name, "orgId", t.OrgID)
+		if err := p.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: t.OrgID, ID: existing.ID}); err != nil {
+			return fmt.Errorf("deleted team %q: %w", t.Name, err)
+		}
+		if err := p.roleService.DeleteTeamPermissions(ctx, t.OrgID, existing.ID); err != nil {
+			return fmt.Errorf("deleted team %q: %w", t.Name, err)
+		}
+	}
+
+	for _, sa := range cfg.DeleteServiceAccounts {
+		id, err := p.serviceAccounts.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("deleted service account %q: %w", sa.Name, err)
+		}
+
+		p.log.Info("deleting service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+		if err := p.serviceAccounts.DeleteServiceAccount(ctx, sa.OrgID, id); err != nil {
+			return fmt.Errorf("deleted service account %q: %w", sa.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *IAMProvisioner) provisionOrgMember(ctx context.Context, member *orgMemberFromConfig) error {
+	u, err := p.getUser(ctx, member.User)
+	if err != nil {
+		return err
+	}
+	if u.IsServiceAccount {
+		return fmt.Errorf("%q is a service account, use serviceAccounts to provision it", member.User)
+	}
+
+	orgs, err := p.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: u.ID})
+	if err != nil {
+		return err
+	}
+	for _, o := range orgs {
+		if o.OrgID != member.OrgID {
+			continue
+		}
+		if o.Role == member.Role {
+			return nil
+		}
+		p.log.Debug("updating org member from configuration", "user", member.User, "orgId", member.OrgID, "role", member.Role)
+		return p.orgService.UpdateOrgUser(ctx, &org.UpdateOrgUserCommand{Role: member.Role, OrgID: member.OrgID, UserID: u.ID})
+	}
+
+	p.log.Info("adding org member from configuration", "user", member.User, "orgId", member.OrgID, "role", member.Role)
+	return p.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{
+		LoginOrEmail: member.User,
+		Role:         member.Role,
+		OrgID:        member.OrgID,
+		UserID:       u.ID,
+	})
+}
+
+func (p *IAMProvisioner) provisionTeam(ctx context.Context, t *teamFromConfig) error {
+	ctx, requester := identity.WithServiceIdentity(ctx, t.OrgID)
+	existing, err := p.getTeam(ctx, requester, t.OrgID, t.Name)
+	if err != nil && !errors.Is(err, team.ErrTeamNotFound) {
+		return err
+	}
+
+	var teamID int64
+	if errors.Is(err, team.ErrTeamNotFound) {
+		p.log.Info("inserting team from configuration", "name", t.Name, "orgId", t.OrgID)
+		created, err := p.teamService.CreateTeam(ctx, &team.CreateTeamCommand{Name: t.Name, Email: t.Email, OrgID: t.OrgID})
+		if err != nil {
+			return err
+		}
+		teamID = created.ID
+	} else {
+		teamID = existing.ID
+		if existing.Email != t.Email {
+			p.log.Debug("updating team from configuration", "name", t.Name, "orgId", t.OrgID)
+			if err := p.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{ID: teamID, Name: t.Name, Email: t.Email, OrgID: t.OrgID}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return p.syncTeamMembers(ctx, requester, t, teamID)
+}
+
+// syncTeamMembers adds the missing members and removes the ones that are not in the configuration.
+// Members synced from an identity provider are left alone.
+func (p *IAMProvisioner) syncTeamMembers(ctx context.Context, requester identity.Requester, t *teamFromConfig, teamID int64) error {
+	desired := map[int64]string{}
+	for _, login := range t.Members {
+		u, err := p.getUser(ctx, login)
+		if err != nil {
+			return fmt.Errorf("member %q: %w", login, err)
+		}
+		desired[u.ID] = "Member"
+	}
+	for _, login := range t.Admins {
+		u, err := p.getUser(ctx, login)
+		if err != nil {
+			return fmt.Errorf("admin %q: %w", login, err)
+		}
+		desired[u.ID] = "Admin"
+	}
+
+	members, err := p.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: t.OrgID, TeamID: teamID, SignedInUser: requester})
+	if err != nil {
+		return err
+	}
+
+	var commands []accesscontrol.SetResourcePermissionCommand
+	for _, m := range members {
+		if m.External {
+			continue
+		}
+		permission, ok := desired[m.UserID]
+		if !ok {
+			commands = append(commands, accesscontrol.SetResourcePermissionCommand{UserID: m.UserID, Permission: ""})
+			continue
+		}
+		if (m.Permission == team.PermissionTypeAdmin) == (permission == "Admin") {
+			delete(desired, m.UserID)
+		}
+	}
+	for _, userID := range slices.Sorted(maps.Keys(desired)) {
+		commands = append(commands, accesscontrol.SetResourcePermissionCommand{UserID: userID, Permission: desired[userID]})
+	}
+
+	if len(commands) == 0 {
+		return nil
+	}
+	p.log.Debug("updating team members from configuration", "name", t.Name, "orgId", t.OrgID, "changes", len(commands))
+	_, err = p.teamPermissions.SetPermissions(ctx, t.OrgID, strconv.FormatInt(teamID, 10), commands...)
+	return err
+}
+
+func (p *IAMProvisioner) provisionServiceAccount(ctx context.Context, sa *serviceAccountFromConfig) error {
+	id, err := p.serviceAccounts.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+	if err != nil && !errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+		return err
+	}
+
+	if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+		p.log.Info("inserting service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+		created, err := p.serviceAccounts.CreateServiceAccount(ctx, sa.OrgID, &serviceaccounts.CreateServiceAccountForm{
+			Name:       sa.Name,
+			Role:       &sa.Role,
+			IsDisabled: &sa.IsDisabled,
+		})
+		if err != nil {
+			return err
+		}
+		return p.syncServiceAccountRoles(ctx, sa, created.Id)
+	}
+
+	existing, err := p.serviceAccounts.RetrieveServiceAccount(ctx, &serviceaccounts.GetServiceAccountQuery{OrgID: sa.OrgID, ID: id})
+	if err != nil {
+		return err
+	}
+	if existing.Role != string(sa.Role) || existing.IsDisabled != sa.IsDisabled {
+		p.log.Debug("updating service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+		_, err = p.serviceAccounts.UpdateServiceAccount(ctx, sa.OrgID, id, &serviceaccounts.UpdateServiceAccountForm{
+			ServiceAccountID: id,
+			Role:             &sa.Role,
+			IsDisabled:       &sa.IsDisabled,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return p.syncServiceAccountRoles(ctx, sa, id)
+}
+
+// syncServiceAccountRoles assigns the roles of the configuration and unassigns the ones listed for removal.
+// The roles that were assigned another way are left alone.
+func (p *IAMProvisioner) syncServiceAccountRoles(ctx context.Context, sa *serviceAccountFromConfig, id int64) error {
+	if len(sa.Roles) == 0 && len(sa.RemoveRoles) == 0 {
+		return nil
+	}
+	p.log.Debug("updating service account roles from configuration", "name", sa.Name, "orgId", sa.OrgID, "roles", sa.Roles)
+	return p.roleService.SyncUserRoles(ctx, sa.OrgID, accesscontrol.SyncUserRolesCommand{
+		UserID:        id,
+		RolesToAdd:    sa.Roles,
+		RolesToRemove: sa.RemoveRoles,
+	})
+}
+
+func (p *IAMProvisioner) provisionFolder(ctx context.Context, f *folderFromConfig) error {
+	ctx, requester := identity.WithServiceIdentity(ctx, f.OrgID)
+	existing, err := p.folderService.Get(ctx, &folder.GetFolderQuery{UID: &f.UID, OrgID: f.OrgID, SignedInUser: requester})
+	if err != nil && !errors.Is(err, dashboards.ErrFolderNotFound) {
+		return err
+	}
+
+	if errors.Is(err, dashboards.ErrFolderNotFound) {
+		p.log.Info("inserting folder from configuration", "uid", f.UID, "orgId", f.OrgID)
+		_, err := p.folderService.Create(ctx, &folder.CreateFolderCommand{
+			UID:          f.UID,
+			OrgID:        f.OrgID,
+			Title:        f.Title,
+			Description:  f.Description,
+			ParentUID:    f.ParentUID,
+			SignedInUser: requester,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := p.checkFolderManager(existing, f); err != nil {
+			return err
+		}
+		if existing.Title != f.Title || existing.Description != f.Description {
+			p.log.Debug("updating folder from configuration", "uid", f.UID, "orgId", f.OrgID)
+			_, err := p.folderService.Update(ctx, &folder.UpdateFolderCommand{
+				UID:            f.UID,
+				OrgID:          f.OrgID,
+				NewTitle:       &f.Title,
+				NewDescription: &f.Description,
+				Overwrite:      true,
+				SignedInUser:   requester,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if existing.ParentUID != f.ParentUID {
+			p.log.Debug("moving folder from configuration", "uid", f.UID, "orgId", f.OrgID, "parentUid", f.ParentUID)
+			_, err := p.folderService.Move(ctx, &folder.MoveFolderCommand{
+				UID:          f.UID,
+				NewParentUID: f.ParentUID,
+				OrgID:        f.OrgID,
+				SignedInUser: requester,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// The permissions are only managed when they are in the configuration
+	if f.Permissions == nil {
+		return nil
+	}
+	return p.syncFolderPermissions(ctx, requester, f)
+}
+
+// checkFolderManager refuses to change the title, description or parent of a folder that another process manages,
+// as both would keep overwriting each other. Only the permissions of those folders can be provisioned.
+func (p *IAMProvisioner) checkFolderManager(existing *folder.Folder, f *folderFromConfig) error {
+	if existing.Title == f.Title && existing.Description == f.Description && existing.ParentUID == f.ParentUID {
+		return nil
+	}
+	if existing.ManagedBy != utils.ManagerKindUnknown {
+		return fmt.Errorf("%w: managed by %s", ErrManagedFolder, existing.ManagedBy)
+	}
+	if p.dashboardFolders != nil && p.dashboardFolders.ProvisionsFolder(f.OrgID, existing.UID, existing.Title) {
+		return fmt.Errorf("%w: managed by the dashboard provisioning", ErrManagedFolder)
+	}
+	return nil
+}
+
+// syncFolderPermissions sets the folder permissions and removes the ones that are not in the configuration.
+// Only the permissions set on the folder itself are changed, the ones inherited from the parent folders are left alone.
+func (p *IAMProvisioner) syncFolderPermissions(ctx context.Context, requester identity.Requester, f *folderFromConfig) error {
+	desired := map[string]accesscontrol.SetResourcePermissionCommand{}
+	for _, permission := range f.Permissions {
+		cmd, err := p.folderPermissionCommand(ctx, requester, f.OrgID, permission)
+		if err != nil {
+			return err
+		}
+		desired[permissionKey(cmd)] = cmd
+	}
+
+	current, err := p.folderPermissions.GetPermissions(ctx, requester, f.UID)
+	if err != nil {
+		return err
+	}
+
+	var commands []accesscontrol.SetResourcePermissionCommand
+	for _, c := range current {
+		if !c.IsManaged || c.IsInherited {
+			continue
+		}
+		cmd := accesscontrol.SetResourcePermissionCommand{UserID: c.UserID, TeamID: c.TeamID, BuiltinRole: c.BuiltInRole}
+		key := permissionKey(cmd)
+		wanted, ok := desired[key]
+		if !ok {
+			commands = append(commands, cmd)
+			continue
+		}
+		if p.folderPermissions.MapActions(c) == wanted.Permission {
+			delete(desired, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(desired)) {
+		commands = append(commands, desired[key])
+	}
+
+	if len(commands) == 0 {
+		return nil
+	}
+	p.log.Debug("updating folder permissions from configuration", "uid", f.UID, "orgId", f.OrgID, "changes", len(commands))
+	_, err = p.folderPermissions.SetPermissions(ctx, f.OrgID, f.UID, commands...)
+	return err
+}
+
+func (p *IAMProvisioner) folderPermissionCommand(ctx context.Context, requester identity.Requester, orgID int64, permission *folderPermissionFromConfig) (accesscontrol.SetResourcePermissionCommand, error) {
+	cmd := accesscontrol.SetResourcePermissionCommand{Permission: permission.Permission}
+	switch {
+	case permission.User != "":
+		u, err := p.getUser(ctx, permission.User)
+		if err != nil {
+			return cmd, fmt.Errorf("user %q: %w", permission.User, err)
+		}
+		cmd.UserID = u.ID
+	case permission.Team != "":
+		t, err := p.getTeam(ctx, requester, orgID, permission.Team)
+		if err != nil {
+			return cmd, fmt.Errorf("team %q: %w", permission.Team, err)
+		}
+		cmd.TeamID = t.ID
+	case permission.ServiceAccount != "":
+		id, err := p.serviceAccounts.RetrieveServiceAccountIdByName(ctx, orgID, permission.ServiceAccount)
+		if err != nil {
+			return cmd, fmt.Errorf("service account %q: %w", permission.ServiceAccount, err)
+		}
+		cmd.UserID = id
+	default:
+		cmd.BuiltinRole = permission.Role
+	}
+	return cmd, nil
+}
+
+func permissionKey(cmd accesscontrol.SetResourcePermissionCommand) string {
+	switch {
+	case cmd.UserID != 0:
+		return fmt.Sprintf("user:%d", cmd.UserID)
+	case cmd.TeamID != 0:
+		return fmt.Sprintf("team:%d", cmd.TeamID)
+	default:
+		return "role:" + cmd.BuiltinRole
+	}
+}
+
+// getUser finds an existing user by login or email
+func (p *IAMProvisioner) getUser(ctx context.Context, loginOrEmail string) (*user.User, error) {
+	return p.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginOrEmail})
+}
+
+// getTeam finds a team by its name, the names are unique in an org
+func (p *IAMProvisioner) getTeam(ctx context.Context, requester identity.Requester, orgID int64, name string) (*team.TeamDTO, error) {
+	result, err := p.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{OrgID: orgID, Name: name, Limit: 1, SignedInUser: requester})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range result.Teams {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, team.ErrTeamNotFound
+}
@@ -0,0 +1,459 @@
+package iam
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIAMProvisioner(t *testing.T) {
+	t.Setenv("EDITOR_EMAIL", "editor@example.com")
+
+	t.Run("creates the objects", func(t *testing.T) {
+		fake := newFakeIAM()
+		require.NoError(t, fake.provisioner().applyChanges(context.Background(), allProperties))
+
+		require.Equal(t, map[int64]org.RoleType{1: org.RoleViewer}, fake.orgUsers[1])
+		require.Equal(t, map[int64]org.RoleType{1: org.RoleEditor}, fake.orgUsers[2])
+
+		require.Len(t, fake.teams, 1)
+		require.Equal(t, "platform@example.com", fake.teams[0].Email)
+		require.Equal(t, map[int64]string{1: "Member", 2: "Admin"}, fake.teamMembers[fake.teams[0].ID])
+
+		require.Equal(t, "Editor", fake.serviceAccounts["ci"].Role)
+		require.Equal(t, []string{"fixed:dashboards:writer"}, fake.userRoles[fake.serviceAccounts["ci"].Id])
+		require.True(t, fake.serviceAccounts["legacy"].IsDisabled)
+
+		require.Equal(t, "Platform", fake.folders["platform"].Title)
+		require.Equal(t, "platform", fake.folders["platform-alerts"].ParentUID)
+		require.Equal(t, map[string]string{
+			"team:" + strconv.FormatInt(fake.teams[0].ID, 10):              "Admin",
+			"user:" + strconv.FormatInt(fake.serviceAccounts["ci"].Id, 10): "Edit",
+			"user:1":      "View",
+			"role:Viewer": "View",
+		}, fake.folderPermissions["platform"])
+		// The default permissions of the folders without provisioned permissions are kept
+		require.Equal(t, map[string]string{"role:Editor": "Edit"}, fake.folderPermissions["platform-alerts"])
+	})
+
+	t.Run("reconciles the existing objects", func(t *testing.T) {
+		fake := newFakeIAM()
+		fake.orgUsers[1] = map[int64]org.RoleType{1: org.RoleAdmin}
+		fake.teams = append(fake.teams, &team.TeamDTO{ID: 10, OrgID: 1, Name: "Platform"})
+		fake.teamMembers[10] = map[int64]string{1: "Admin", 3: "Member"}
+		fake.serviceAccounts["ci"] = &serviceaccounts.ServiceAccountProfileDTO{Id: 20, OrgId: 1, Name: "ci", Role: "Viewer"}
+		fake.userRoles[20] = []string{"custom:reader", "fixed:folders:writer"}
+		fake.folders["platform"] = &folder.Folder{UID: "platform", OrgID: 1, Title: "Old title"}
+		fake.folderPermissions["platform"] = map[string]string{"role:Editor": "Edit", "user:1": "Admin", "inherited:user:3": "Admin"}
+
+		require.NoError(t, fake.provisioner().applyChanges(context.Background(), allProperties))
+
+		require.Equal(t, map[int64]org.RoleType{1: org.RoleViewer}, fake.orgUsers[1])
+		require.Len(t, fake.teams, 1)
+		require.Equal(t, "platform@example.com", fake.teams[0].Email)
+		require.Equal(t, map[int64]string{1: "Member", 2: "Admin"}, fake.teamMembers[10])
+		require.Equal(t, "Editor", fake.serviceAccounts["ci"].Role)
+		// The roles that are not in the configuration are kept
+		require.Equal(t, []string{"custom:reader", "fixed:dashboards:writer"}, fake.userRoles[20])
+		require.Equal(t, "Platform", fake.folders["platform"].Title)
+		require.Equal(t, map[string]string{
+			"team:10":          "Admin",
+			"user:20":          "Edit",
+			"user:1":           "View",
+			"role:Viewer":      "View",
+			"inherited:user:3": "Admin",
+		}, fake.folderPermissions["platform"])
+	})
+
+	t.Run("does not change objects that are up to date", func(t *testing.T) {
+		fake := newFakeIAM()
+		p := fake.provisioner()
+		require.NoError(t, p.applyChanges(context.Background(), allProperties))
+
+		fake.writes = 0
+		require.NoError(t, p.applyChanges(context.Background(), allProperties))
+		require.Zero(t, fake.writes)
+	})
+
+	t.Run("deletes the objects", func(t *testing.T) {
+		fake := newFakeIAM()
+		fake.orgUsers[1] = map[int64]org.RoleType{1: org.RoleViewer}
+		fake.teams = append(fake.teams, &team.TeamDTO{ID: 10, OrgID: 1, Name: "Platform"})
+		fake.serviceAccounts["ci"] = &serviceaccounts.ServiceAccountProfileDTO{Id: 20, OrgId: 1, Name: "ci", Role: "Viewer"}
+
+		require.NoError(t, fake.provisioner().applyChanges(context.Background(), deleteObjects))
+
+		require.Empty(t, fake.orgUsers[1])
+		require.Empty(t, fake.teams)
+		require.Equal(t, []int64{10}, fake.deletedTeamPermissions)
+		require.Empty(t, fake.serviceAccounts)
+	})
+
+	t.Run("only provisions the permissions of managed folders", func(t *testing.T) {
+		for name, setup := range map[string]func(fake *fakeIAM){
+			"git sync": func(fake *fakeIAM) {
+				fake.folders["platform"].ManagedBy = utils.ManagerKindRepo
+			},
+			"dashboard provisioning": func(fake *fakeIAM) {
+				fake.dashboardFolders = []string{"platform"}
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				fake := newFakeIAM()
+				fake.folders["platform"] = &folder.Folder{UID: "platform", OrgID: 1, Title: "Old title"}
+				setup(fake)
+
+				err := fake.provisioner().applyChanges(context.Background(), allProperties)
+				require.ErrorIs(t, err, ErrManagedFolder)
+				require.ErrorContains(t, err, `folder "platform"`)
+				require.Equal(t, "Old title", fake.folders["platform"].Title)
+
+				// The permissions are provisioned when the folder matches the configuration
+				fake.folders["platform"].Title = "Platform"
+				fake.folders["platform"].Description = "Dashboards of the platform team"
+				require.NoError(t, fake.provisioner().applyChanges(context.Background(), allProperties))
+				require.Contains(t, fake.folderPermissions["platform"], "role:Viewer")
+			})
+		}
+	})
+
+	t.Run("users must exist", func(t *testing.T) {
+		fake := newFakeIAM()
+		delete(fake.users, "viewer")
+
+		err := fake.provisioner().applyChanges(context.Background(), allProperties)
+		require.ErrorIs(t, err, user.ErrUserNotFound)
+		require.ErrorContains(t, err, `org member "viewer"`)
+	})
+}
+
+// fakeIAM keeps the state of the identity objects in memory.
+// Every change increments writes.
+type fakeIAM struct {
+	users                  map[string]*user.User
+	orgUsers               map[int64]map[int64]org.RoleType
+	teams                  []*team.TeamDTO
+	teamMembers            map[int64]map[int64]string
+	deletedTeamPermissions []int64
+	serviceAccounts        map[string]*serviceaccounts.ServiceAccountProfileDTO
+	folders                map[string]*folder.Folder
+	// The permissions by folder, keyed by permissionKey. Inherited permissions are prefixed with inherited:
+	folderPermissions map[string]map[string]string
+	// The roles assigned to the users
+	userRoles map[int64][]string
+	// The uids of the folders of the dashboard provisioning
+	dashboardFolders []string
+
+	nextID int64
+	writes int
+}
+
+func newFakeIAM() *fakeIAM {
+	return &fakeIAM{
+		users: map[string]*user.User{
+			"viewer":             {ID: 1, Login: "viewer"},
+			"editor@example.com": {ID: 2, Login: "editor", Email: "editor@example.com"},
+			"other":              {ID: 3, Login: "other"},
+		},
+		orgUsers:          map[int64]map[int64]org.RoleType{},
+		teamMembers:       map[int64]map[int64]string{},
+		serviceAccounts:   map[string]*serviceaccounts.ServiceAccountProfileDTO{},
+		folders:           map[string]*folder.Folder{},
+		folderPermissions: map[string]map[string]string{},
+		userRoles:         map[int64][]string{},
+		nextID:            100,
+	}
+}
+
+func (f *fakeIAM) provisioner() *IAMProvisioner {
+	return newIAMProvisioner(logger, ProvisionerConfig{
+		OrgService:               &fakeOrgService{FakeOrgService: orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}, fake: f},
+		UserService:              f,
+		TeamService:              f,
+		RoleService:              f,
+		TeamPermissionsService:   &fakeTeamPermissions{fake: f},
+		ServiceAccountService:    f,
+		FolderService:            f,
+		FolderPermissionsService: &fakeFolderPermissions{fake: f},
+		DashboardFolders:         f,
+	})
+}
+
+func (f *fakeIAM) GetByLogin(_ context.Context, query *user.GetUserByLoginQuery) (*user.User, error) {
+	if u, ok := f.users[query.LoginOrEmail]; ok {
+		return u, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (f *fakeIAM) CreateTeam(_ context.Context, cmd *team.CreateTeamCommand) (team.Team, error) {
+	f.writes++
+	f.nextID++
+	f.teams = append(f.teams, &team.TeamDTO{ID: f.nextID, OrgID: cmd.OrgID, Name: cmd.Name, Email: cmd.Email})
+	return team.Team{ID: f.nextID, OrgID: cmd.OrgID, Name: cmd.Name, Email: cmd.Email}, nil
+}
+
+func (f *fakeIAM) UpdateTeam(_ context.Context, cmd *team.UpdateTeamCommand) error {
+	f.writes++
+	for _, t := range f.teams {
+		if t.ID == cmd.ID {
+			t.Name = cmd.Name
+			t.Email = cmd.Email
+		}
+	}
+	return nil
+}
+
+func (f *fakeIAM) DeleteTeam(_ context.Context, cmd *team.DeleteTeamCommand) error {
+	f.writes++
+	f.teams = slices.DeleteFunc(f.teams, func(t *team.TeamDTO) bool { return t.ID == cmd.ID })
+	return nil
+}
+
+func (f *fakeIAM) SearchTeams(_ context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	result := team.SearchTeamQueryResult{}
+	for _, t := range f.teams {
+		if t.OrgID == query.OrgID && t.Name == query.Name {
+			result.Teams = append(result.Teams, t)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeIAM) GetTeamMembers(_ context.Context, query *team.GetTeamMembersQuery) ([]*team.TeamMemberDTO, error) {
+	var members []*team.TeamMemberDTO
+	for userID, permission := range f.teamMembers[query.TeamID] {
+		m := &team.TeamMemberDTO{OrgID: query.OrgID, TeamID: query.TeamID, UserID: userID}
+		if permission == "Admin" {
+			m.Permission = team.PermissionTypeAdmin
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (f *fakeIAM) DeleteTeamPermissions(_ context.Context, _, teamID int64) error {
+	f.deletedTeamPermissions = append(f.deletedTeamPermissions, teamID)
+	return nil
+}
+
+func (f *fakeIAM) SyncUserRoles(_ context.Context, _ int64, cmd accesscontrol.SyncUserRolesCommand) error {
+	roles := slices.DeleteFunc(slices.Clone(f.userRoles[cmd.UserID]), func(role string) bool {
+		return slices.Contains(cmd.RolesToRemove, role)
+	})
+	for _, role := range cmd.RolesToAdd {
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	slices.Sort(roles)
+	if !slices.Equal(roles, f.userRoles[cmd.UserID]) {
+		f.writes++
+		f.userRoles[cmd.UserID] = roles
+	}
+	return nil
+}
+
+func (f *fakeIAM) ProvisionsFolder(_ int64, uid, _ string) bool {
+	return slices.Contains(f.dashboardFolders, uid)
+}
+
+func (f *fakeIAM) RetrieveServiceAccount(_ context.Context, query *serviceaccounts.GetServiceAccountQuery) (*serviceaccounts.ServiceAccountProfileDTO, error) {
+	for _, sa := range f.serviceAccounts {
+		if sa.Id == query.ID {
+			return sa, nil
+		}
+	}
+	return nil, serviceaccounts.ErrServiceAccountNotFound.Errorf("not found")
+}
+
+func (f *fakeIAM) RetrieveServiceAccountIdByName(_ context.Context, _ int64, name string) (int64, error) {
+	if sa, ok := f.serviceAccounts[name]; ok {
+		return sa.Id, nil
+	}
+	return 0, serviceaccounts.ErrServiceAccountNotFound.Errorf("service account with name %s not found", name)
+}
+
+func (f *fakeIAM) CreateServiceAccount(_ context.Context, orgID int64, form *serviceaccounts.CreateServiceAccountForm) (*serviceaccounts.ServiceAccountDTO, error) {
+	f.writes++
+	f.nextID++
+	f.serviceAccounts[form.Name] = &serviceaccounts.ServiceAccountProfileDTO{
+		Id:         f.nextID,
+		OrgId:      orgID,
+		Name:       form.Name,
+		Role:       string(*form.Role),
+		IsDisabled: *form.IsDisabled,
+	}
+	return &serviceaccounts.ServiceAccountDTO{Id: f.nextID, Name: form.Name, OrgId: orgID}, nil
+}
+
+func (f *fakeIAM) UpdateServiceAccount(_ context.Context, _, id int64, form *serviceaccounts.UpdateServiceAccountForm) (*serviceaccounts.ServiceAccountProfileDTO, error) {
+	f.writes++
+	for _, sa := range f.serviceAccounts {
+		if sa.Id == id {
+			sa.Role = string(*form.Role)
+			sa.IsDisabled = *form.IsDisabled
+			return sa, nil
+		}
+	}
+	return nil, serviceaccounts.ErrServiceAccountNotFound.Errorf("not found")
+}
+
+func (f *fakeIAM) DeleteServiceAccount(_ context.Context, _, id int64) error {
+	f.writes++
+	for name, sa := range f.serviceAccounts {
+		if sa.Id == id {
+			delete(f.serviceAccounts, name)
+		}
+	}
+	return nil
+}
+
+func (f *fakeIAM) Get(_ context.Context, q *folder.GetFolderQuery) (*folder.Folder, error) {
+	if existing, ok := f.folders[*q.UID]; ok {
+		copied := *existing
+		return &copied, nil
+	}
+	return nil, dashboards.ErrFolderNotFound
+}
+
+func (f *fakeIAM) Create(_ context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error) {
+	f.writes++
+	f.folders[cmd.UID] = &folder.Folder{UID: cmd.UID, OrgID: cmd.OrgID, Title: cmd.Title, Description: cmd.Description, ParentUID: cmd.ParentUID}
+	// Like the folder service, new folders get the default permissions
+	f.folderPermissions[cmd.UID] = map[string]string{"role:Editor": "Edit"}
+	return f.folders[cmd.UID], nil
+}
+
+func (f *fakeIAM) Update(_ context.Context, cmd *folder.UpdateFolderCommand) (*folder.Folder, error) {
+	f.writes++
+	existing := f.folders[cmd.UID]
+	existing.Title = *cmd.NewTitle
+	existing.Description = *cmd.NewDescription
+	return existing, nil
+}
+
+func (f *fakeIAM) Move(_ context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error) {
+	f.writes++
+	existing := f.folders[cmd.UID]
+	existing.ParentUID = cmd.NewParentUID
+	return existing, nil
+}
+
+type fakeOrgService struct {
+	orgtest.FakeOrgService
+	fake *fakeIAM
+}
+
+func (s *fakeOrgService) GetUserOrgList(_ context.Context, query *org.GetUserOrgListQuery) ([]*org.UserOrgDTO, error) {
+	var orgs []*org.UserOrgDTO
+	for orgID, role := range s.fake.orgUsers[query.UserID] {
+		orgs = append(orgs, &org.UserOrgDTO{OrgID: orgID, Role: role})
+	}
+	return orgs, nil
+}
+
+func (s *fakeOrgService) AddOrgUser(_ context.Context, cmd *org.AddOrgUserCommand) error {
+	s.fake.writes++
+	if s.fake.orgUsers[cmd.UserID] == nil {
+		s.fake.orgUsers[cmd.UserID] = map[int64]org.RoleType{}
+	}
+	s.fake.orgUsers[cmd.UserID][cmd.OrgID] = cmd.Role
+	return nil
+}
+
+func (s *fakeOrgService) UpdateOrgUser(_ context.Context, cmd *org.UpdateOrgUserCommand) error {
+	s.fake.writes++
+	s.fake.orgUsers[cmd.UserID][cmd.OrgID] = cmd.Role
+	return nil
+}
+
+func (s *fakeOrgService) RemoveOrgUser(_ context.Context, cmd *org.RemoveOrgUserCommand) error {
+	s.fake.writes++
+	delete(s.fake.orgUsers[cmd.UserID], cmd.OrgID)
+	return nil
+}
+
+type fakeTeamPermissions struct {
+	accesscontrol.TeamPermissionsService
+	fake *fakeIAM
+}
+
+func (s *fakeTeamPermissions) SetPermissions(_ context.Context, _ int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	s.fake.writes++
+	teamID, err := strconv.ParseInt(resourceID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if s.fake.teamMembers[teamID] == nil {
+		s.fake.teamMembers[teamID] = map[int64]string{}
+	}
+	for _, cmd := range commands {
+		if cmd.Permission == "" {
+			delete(s.fake.teamMembers[teamID], cmd.UserID)
+			continue
+		}
+		s.fake.teamMembers[teamID][cmd.UserID] = cmd.Permission
+	}
+	return nil, nil
+}
+
+type fakeFolderPermissions struct {
+	fake *fakeIAM
+}
+
+func (s *fakeFolderPermissions) GetPermissions(_ context.Context, _ identity.Requester, resourceID string) ([]accesscontrol.ResourcePermission, error) {
+	var permissions []accesscontrol.ResourcePermission
+	for key, permission := range s.fake.folderPermissions[resourceID] {
+		p := accesscontrol.ResourcePermission{IsManaged: true, Actions: []string{permission}}
+		if inheritedKey, ok := strings.CutPrefix(key, "inherited:"); ok {
+			p.IsInherited = true
+			key = inheritedKey
+		}
+		switch {
+		case strings.HasPrefix(key, "user:"):
+			p.UserID, _ = strconv.ParseInt(key[len("user:"):], 10, 64)
+		case strings.HasPrefix(key, "team:"):
+			p.TeamID, _ = strconv.ParseInt(key[len("team:"):], 10, 64)
+		default:
+			p.BuiltInRole = key[len("role:"):]
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, nil
+}
+
+func (s *fakeFolderPermissions) SetPermissions(_ context.Context, _ int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	s.fake.writes++
+	if s.fake.folderPermissions[resourceID] == nil {
+		s.fake.folderPermissions[resourceID] = map[string]string{}
+	}
+	for _, cmd := range commands {
+		if cmd.Permission == "" {
+			delete(s.fake.folderPermissions[resourceID], permissionKey(cmd))
+			continue
+		}
+		s.fake.folderPermissions[resourceID][permissionKey(cmd)] = cmd.Permission
+	}
+	return nil, nil
+}
+
+// The fake permissions keep the permission name as the only action
+func (s *fakeFolderPermissions) MapActions(permission accesscontrol.ResourcePermission) string {
+	return permission.Actions[0]
+}
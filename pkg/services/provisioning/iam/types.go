@@ -0,0 +1,256 @@
+package iam
+
+import (
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// configs is the normalized content of one provisioning file
+type configs struct {
+	OrgMembers      []*orgMemberFromConfig
+	Teams           []*teamFromConfig
+	ServiceAccounts []*serviceAccountFromConfig
+	Folders         []*folderFromConfig
+
+	DeleteOrgMembers      []*deleteOrgMemberConfig
+	DeleteTeams           []*deleteByNameConfig
+	DeleteServiceAccounts []*deleteByNameConfig
+}
+
+type orgMemberFromConfig struct {
+	OrgID int64
+	// Login or email of an existing user
+	User string
+	Role org.RoleType
+}
+
+type deleteOrgMemberConfig struct {
+	OrgID int64
+	User  string
+}
+
+type teamFromConfig struct {
+	OrgID int64
+	Name  string
+	Email string
+	// Logins or emails of the team members. Members that are not listed are removed from the team.
+	Members []string
+	Admins  []string
+}
+
+type serviceAccountFromConfig struct {
+	OrgID      int64
+	Name       string
+	Role       org.RoleType
+	IsDisabled bool
+	// Names of the fixed or custom roles to assign and to unassign
+	Roles       []string
+	RemoveRoles []string
+}
+
+type deleteByNameConfig struct {
+	OrgID int64
+	Name  string
+}
+
+type folderFromConfig struct {
+	OrgID       int64
+	UID         string
+	Title       string
+	Description string
+	ParentUID   string
+	// The managed permissions of the folder, nil when they are not provisioned.
+	// Permissions that are not listed are removed.
+	Permissions []*folderPermissionFromConfig
+}
+
+// folderPermissionFromConfig grants the permission to one user, team, service account or basic role
+type folderPermissionFromConfig struct {
+	User           string
+	Team           string
+	ServiceAccount string
+	Role           string
+	Permission     string
+}
+
+type configsV1 struct {
+	configVersion
+
+	OrgMembers      []*orgMemberFromConfigV1      `json:"orgMembers" yaml:"orgMembers"`
+	Teams           []*teamFromConfigV1           `json:"teams" yaml:"teams"`
+	ServiceAccounts []*serviceAccountFromConfigV1 `json:"serviceAccounts" yaml:"serviceAccounts"`
+	Folders         []*folderFromConfigV1         `json:"folders" yaml:"folders"`
+
+	DeleteOrgMembers      []*deleteOrgMemberConfigV1 `json:"deleteOrgMembers" yaml:"deleteOrgMembers"`
+	DeleteTeams           []*deleteByNameConfigV1    `json:"deleteTeams" yaml:"deleteTeams"`
+	DeleteServiceAccounts []*deleteByNameConfigV1    `json:"deleteServiceAccounts" yaml:"deleteServiceAccounts"`
+}
+
+type orgMemberFromConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	User  values.StringValue `json:"user" yaml:"user"`
+	Role  values.StringValue `json:"role" yaml:"role"`
+}
+
+type deleteOrgMemberConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	User  values.StringValue `json:"user" yaml:"user"`
+}
+
+type teamFromConfigV1 struct {
+	OrgID   values.Int64Value    `json:"orgId" yaml:"orgId"`
+	Name    values.StringValue   `json:"name" yaml:"name"`
+	Email   values.StringValue   `json:"email" yaml:"email"`
+	Members []values.StringValue `json:"members" yaml:"members"`
+	Admins  []values.StringValue `json:"admins" yaml:"admins"`
+}
+
+type serviceAccountFromConfigV1 struct {
+	OrgID       values.Int64Value    `json:"orgId" yaml:"orgId"`
+	Name        values.StringValue   `json:"name" yaml:"name"`
+	Role        values.StringValue   `json:"role" yaml:"role"`
+	IsDisabled  values.BoolValue     `json:"isDisabled" yaml:"isDisabled"`
+	Roles       []values.StringValue `json:"roles" yaml:"roles"`
+	RemoveRoles []values.StringValue `json:"removeRoles" yaml:"removeRoles"`
+}
+
+type deleteByNameConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+type folderFromConfigV1 struct {
+	OrgID       values.Int64Value               `json:"orgId" yaml:"orgId"`
+	UID         values.StringValue              `json:"uid" yaml:"uid"`
+	Title       values.StringValue              `json:"title" yaml:"title"`
+	Description values.StringValue              `json:"description" yaml:"description"`
+	ParentUID   values.StringValue              `json:"parentUid" yaml:"parentUid"`
+	Permissions []*folderPermissionFromConfigV1 `json:"permissions" yaml:"permissions"`
+}
+
+type folderPermissionFromConfigV1 struct {
+	User           values.StringValue `json:"user" yaml:"user"`
+	Team           values.StringValue `json:"team" yaml:"team"`
+	ServiceAccount values.StringValue `json:"serviceAccount" yaml:"serviceAccount"`
+	Role           values.StringValue `json:"role" yaml:"role"`
+	Permission     values.StringValue `json:"permission" yaml:"permission"`
+}
+
+func (cfg *configsV1) mapToIAMFromConfig() *configs {
+	r := &configs{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, member := range cfg.OrgMembers {
+		if member == nil {
+			continue
+		}
+		r.OrgMembers = append(r.OrgMembers, &orgMemberFromConfig{
+			OrgID: member.OrgID.Value(),
+			User:  member.User.Value(),
+			Role:  org.RoleType(member.Role.Value()),
+		})
+	}
+
+	for _, team := range cfg.Teams {
+		if team == nil {
+			continue
+		}
+		r.Teams = append(r.Teams, &teamFromConfig{
+			OrgID:   team.OrgID.Value(),
+			Name:    team.Name.Value(),
+			Email:   team.Email.Value(),
+			Members: stringValues(team.Members),
+			Admins:  stringValues(team.Admins),
+		})
+	}
+
+	for _, sa := range cfg.ServiceAccounts {
+		if sa == nil {
+			continue
+		}
+		r.ServiceAccounts = append(r.ServiceAccounts, &serviceAccountFromConfig{
+			OrgID:       sa.OrgID.Value(),
+			Name:        sa.Name.Value(),
+			Role:        org.RoleType(sa.Role.Value()),
+			IsDisabled:  sa.IsDisabled.Value(),
+			Roles:       stringValues(sa.Roles),
+			RemoveRoles: stringValues(sa.RemoveRoles),
+		})
+	}
+
+	for _, folder := range cfg.Folders {
+		if folder == nil {
+			continue
+		}
+		f := &folderFromConfig{
+			OrgID:       folder.OrgID.Value(),
+			UID:         folder.UID.Value(),
+			Title:       folder.Title.Value(),
+			Description: folder.Description.Value(),
+			ParentUID:   folder.ParentUID.Value(),
+		}
+		// The permissions are only reconciled when they are set, an empty list removes them all
+		if folder.Permissions != nil {
+			f.Permissions = make([]*folderPermissionFromConfig, 0, len(folder.Permissions))
+		}
+		for _, p := range folder.Permissions {
+			if p == nil {
+				continue
+			}
+			f.Permissions = append(f.Permissions, &folderPermissionFromConfig{
+				User:           p.User.Value(),
+				Team:           p.Team.Value(),
+				ServiceAccount: p.ServiceAccount.Value(),
+				Role:           p.Role.Value(),
+				Permission:     p.Permission.Value(),
+			})
+		}
+		r.Folders = append(r.Folders, f)
+	}
+
+	for _, member := range cfg.DeleteOrgMembers {
+		if member == nil {
+			continue
+		}
+		r.DeleteOrgMembers = append(r.DeleteOrgMembers, &deleteOrgMemberConfig{
+			OrgID: member.OrgID.Value(),
+			User:  member.User.Value(),
+		})
+	}
+
+	r.DeleteTeams = mapDeleteByName(cfg.DeleteTeams)
+	r.DeleteServiceAccounts = mapDeleteByName(cfg.DeleteServiceAccounts)
+
+	return r
+}
+
+func mapDeleteByName(items []*deleteByNameConfigV1) []*deleteByNameConfig {
+	r := make([]*deleteByNameConfig, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		r = append(r, &deleteByNameConfig{
+			OrgID: item.OrgID.Value(),
+			Name:  item.Name.Value(),
+		})
+	}
+	return r
+}
+
+func stringValues(items []values.StringValue) []string {
+	r := make([]string, 0, len(items))
+	for _, item := range items {
+		if v := item.Value(); v != "" {
+			r = append(r, v)
+		}
+	}
+	return r
+}
@@ -0,0 +1,200 @@
+package librarypanels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var (
+	ErrMissingUID    = errors.New("library panel uid is required")
+	ErrInvalidUID    = errors.New("library panel uid contains illegal characters or is longer than 40 characters")
+	ErrMissingName   = errors.New("library panel name is required, set the name or the title of the model")
+	ErrMissingModel  = errors.New("library panel model is required")
+	ErrInvalidModel  = errors.New("library panel model must have a string type and description")
+	ErrMissingFolder = errors.New("library panel folder does not exist")
+
+	// ErrInterpolationNotAllowed is returned when a file that is not read from the provisioning directory
+	// references the environment variables or files of the server. A literal $ is written as $$.
+	ErrInterpolationNotAllowed = errors.New("environment variables and file expansion are not supported in this file")
+)
+
+type configReader struct {
+	log        log.Logger
+	orgService org.Service
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	var panelConfigs []*configs
+	cr.log.Debug("Looking for library panel provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read library panel provisioning files from directory", "path", path, "error", err)
+		return panelConfigs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") || strings.HasSuffix(file.Name(), ".json") {
+			cr.log.Debug("Parsing library panel provisioning file", "path", path, "file.Name", file.Name())
+			filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			// nolint:gosec
+			// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			cfg, err := parseConfig(file.Name(), data, false)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				panelConfigs = append(panelConfigs, cfg)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, panelConfigs); err != nil {
+		return nil, err
+	}
+
+	return panelConfigs, nil
+}
+
+// parseConfig reads one library panel provisioning file. Empty files are skipped.
+// The files that are not read from the provisioning directory can not use interpolation.
+func parseConfig(filename string, data []byte, rejectInterpolation bool) (*configs, error) {
+	var apiVersion *configVersion
+	if err := yaml.Unmarshal(data, &apiVersion); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if apiVersion == nil {
+		return nil, nil
+	}
+	if apiVersion.APIVersion != 1 {
+		return nil, fmt.Errorf("%s: unsupported apiVersion %d, the library panel provisioning files must use apiVersion 1", filename, apiVersion.APIVersion)
+	}
+
+	v1 := &configsV1{}
+	if err := yaml.Unmarshal(data, v1); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if rejectInterpolation {
+		if err := v1.checkNotInterpolated(); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+
+	cfg := v1.mapToLibraryPanelsFromConfig()
+	cfg.Filename = filename
+	if err := validatePanels(cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return cfg, nil
+}
+
+// validatePanels checks the required fields and sets the defaults
+func validatePanels(cfg *configs) error {
+	for _, panel := range cfg.LibraryPanels {
+		if err := validateUID(panel.UID); err != nil {
+			return fmt.Errorf("library panel %q: %w", panel.Name, err)
+		}
+		if len(panel.Model) == 0 {
+			return fmt.Errorf("library panel %q: %w", panel.UID, ErrMissingModel)
+		}
+		if panelType, ok := panel.Model["type"].(string); !ok || panelType == "" {
+			return fmt.Errorf("library panel %q: %w", panel.UID, ErrInvalidModel)
+		}
+		if description, ok := panel.Model["description"]; ok && description != nil {
+			if _, ok := description.(string); !ok {
+				return fmt.Errorf("library panel %q: %w", panel.UID, ErrInvalidModel)
+			}
+		}
+		if panel.Name == "" {
+			panel.Name, _ = panel.Model["title"].(string)
+		}
+		if panel.Name == "" {
+			return fmt.Errorf("library panel %q: %w", panel.UID, ErrMissingName)
+		}
+		if panel.FolderUID == accesscontrol.GeneralFolderUID {
+			panel.FolderUID = ""
+		}
+		if panel.OrgID < 1 {
+			panel.OrgID = 1
+		}
+	}
+
+	for _, panel := range cfg.DeleteLibraryPanels {
+		if panel.UID == "" {
+			return fmt.Errorf("deleted library panel: %w", ErrMissingUID)
+		}
+		if panel.OrgID < 1 {
+			panel.OrgID = 1
+		}
+	}
+	return nil
+}
+
+func validateUID(uid string) error {
+	if uid == "" {
+		return ErrMissingUID
+	}
+	if !util.IsValidShortUID(uid) || util.IsShortUIDTooLong(uid) {
+		return ErrInvalidUID
+	}
+	return nil
+}
+
+// validate checks every referenced org exists and that the files do not provision the same library panel twice
+func (cr *configReader) validate(ctx context.Context, panelConfigs []*configs) error {
+	orgExists := utils.NewOrgExistsChecker(cr.orgService)
+	checkedOrgs := map[int64]bool{}
+	checkOrg := func(orgID int64) error {
+		if checkedOrgs[orgID] {
+			return nil
+		}
+		if err := orgExists(ctx, orgID); err != nil {
+			return err
+		}
+		checkedOrgs[orgID] = true
+		return nil
+	}
+
+	provisioned := map[string]string{}
+	for _, cfg := range panelConfigs {
+		for _, panel := range cfg.LibraryPanels {
+			if err := checkOrg(panel.OrgID); err != nil {
+				return fmt.Errorf("%s: library panel %q: %w", cfg.Filename, panel.UID, err)
+			}
+			key := fmt.Sprintf("%d/%s", panel.OrgID, panel.UID)
+			if other, ok := provisioned[key]; ok {
+				return fmt.Errorf("library panel %q is provisioned more than once in org %d, in %s and %s", panel.UID, panel.OrgID, other, cfg.Filename)
+			}
+			provisioned[key] = cfg.Filename
+		}
+		for _, panel := range cfg.DeleteLibraryPanels {
+			if err := checkOrg(panel.OrgID); err != nil {
+				return fmt.Errorf("%s: deleted library panel %q: %w", cfg.Filename, panel.UID, err)
+			}
+		}
+	}
+
+	return nil
+}
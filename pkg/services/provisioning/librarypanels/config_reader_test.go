@@ -0,0 +1,102 @@
+package librarypanels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+var (
+	logger log.Logger = log.New("fake.log")
+
+	allProperties  = "testdata/all-properties"
+	deletePanels   = "testdata/delete-panels"
+	invalidUID     = "testdata/invalid-uid"
+	invalidModel   = "testdata/invalid-model"
+	duplicatePanel = "testdata/duplicate-panel"
+	versionZero    = "testdata/version-0"
+)
+
+func newTestConfigReader(orgService org.Service) *configReader {
+	if orgService == nil {
+		orgService = &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}
+	}
+	return &configReader{log: logger, orgService: orgService}
+}
+
+func TestLibraryPanelsAsConfig(t *testing.T) {
+	t.Run("can read all properties", func(t *testing.T) {
+		t.Setenv("PANEL_UID", "runbook")
+
+		cfgs, err := newTestConfigReader(nil).readConfig(context.Background(), allProperties)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+		require.Equal(t, "panels.yaml", cfgs[0].Filename)
+		require.Len(t, cfgs[0].LibraryPanels, 2)
+
+		cpu := cfgs[0].LibraryPanels[0]
+		require.Equal(t, int64(1), cpu.OrgID)
+		require.Equal(t, "cpu-usage", cpu.UID)
+		require.Equal(t, "CPU usage", cpu.Name)
+		require.Equal(t, "platform", cpu.FolderUID)
+		require.Equal(t, "timeseries", cpu.Model["type"])
+		require.Equal(t, "The CPU usage of the $instance", cpu.Model["description"], "the model is not interpolated")
+
+		runbook := cfgs[0].LibraryPanels[1]
+		require.Equal(t, int64(1), runbook.OrgID, "the default org is used")
+		require.Equal(t, "runbook", runbook.UID)
+		require.Equal(t, "Runbook", runbook.Name, "the name defaults to the title of the model")
+		require.Empty(t, runbook.FolderUID)
+	})
+
+	t.Run("can read the library panels to delete", func(t *testing.T) {
+		cfgs, err := newTestConfigReader(nil).readConfig(context.Background(), deletePanels)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+		require.Equal(t, []*deleteLibraryPanelConfig{{OrgID: 1, UID: "cpu-usage"}, {OrgID: 1, UID: "missing"}}, cfgs[0].DeleteLibraryPanels)
+	})
+
+	t.Run("missing folder is not an error", func(t *testing.T) {
+		cfgs, err := newTestConfigReader(nil).readConfig(context.Background(), "testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, cfgs)
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			path     string
+			expected error
+		}{
+			{name: "invalid uid", path: invalidUID, expected: ErrInvalidUID},
+			{name: "model without type", path: invalidModel, expected: ErrInvalidModel},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := newTestConfigReader(nil).readConfig(context.Background(), tt.path)
+				require.ErrorIs(t, err, tt.expected)
+			})
+		}
+	})
+
+	t.Run("the same library panel can not be provisioned twice", func(t *testing.T) {
+		_, err := newTestConfigReader(nil).readConfig(context.Background(), duplicatePanel)
+		require.ErrorContains(t, err, `library panel "cpu-usage" is provisioned more than once in org 1, in a.yaml and b.yaml`)
+	})
+
+	t.Run("apiVersion 1 is required", func(t *testing.T) {
+		_, err := newTestConfigReader(nil).readConfig(context.Background(), versionZero)
+		require.ErrorContains(t, err, "unsupported apiVersion 0")
+	})
+
+	t.Run("the org must exist", func(t *testing.T) {
+		orgFake := &orgtest.FakeOrgService{ExpectedError: org.ErrOrgNotFound}
+		_, err := newTestConfigReader(orgFake).readConfig(context.Background(), deletePanels)
+		require.ErrorIs(t, err, org.ErrOrgNotFound)
+	})
+}
@@ -0,0 +1,212 @@
+package librarypanels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+type LibraryElementService interface {
+	GetElement(c context.Context, signedInUser identity.Requester, cmd model.GetLibraryElementCommand) (model.LibraryElementDTO, error)
+	CreateElement(c context.Context, signedInUser identity.Requester, cmd model.CreateLibraryElementCommand) (model.LibraryElementDTO, error)
+	PatchElement(c context.Context, signedInUser identity.Requester, cmd model.PatchLibraryElementCommand, uid string) (model.LibraryElementDTO, error)
+	DeleteElement(c context.Context, signedInUser identity.Requester, uid string) error
+}
+
+type FolderService interface {
+	Get(ctx context.Context, q *folder.GetFolderQuery) (*folder.Folder, error)
+}
+
+type ProvisionerConfig struct {
+	Path                  string
+	OrgService            org.Service
+	LibraryElementService LibraryElementService
+	FolderService         FolderService
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the library panels in those files.
+// It runs before the dashboards are provisioned, so the dashboards can reference the library panels.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.librarypanels")
+	p := newLibraryPanelsProvisioner(logger, cfg)
+	return p.applyChanges(ctx, cfg.Path)
+}
+
+// LibraryPanelsProvisioner is responsible for provisioning the library panels based on
+// configuration read by the `configReader`
+type LibraryPanelsProvisioner struct {
+	log             log.Logger
+	cfgProvider     *configReader
+	libraryElements LibraryElementService
+	folderService   FolderService
+}
+
+func newLibraryPanelsProvisioner(logger log.Logger, cfg ProvisionerConfig) *LibraryPanelsProvisioner {
+	return &LibraryPanelsProvisioner{
+		log:             logger,
+		cfgProvider:     &configReader{log: logger, orgService: cfg.OrgService},
+		libraryElements: cfg.LibraryElementService,
+		folderService:   cfg.FolderService,
+	}
+}
+
+func (p *LibraryPanelsProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+	return p.apply(ctx, configs)
+}
+
+// apply deletes the library panels listed for deletion first, then creates or updates the others
+func (p *LibraryPanelsProvisioner) apply(ctx context.Context, configs []*configs) error {
+	for _, cfg := range configs {
+		for _, panel := range cfg.DeleteLibraryPanels {
+			if err := p.deletePanel(ctx, panel.OrgID, panel.UID); err != nil {
+				return fmt.Errorf("%s: deleted library panel %q: %w", cfg.Filename, panel.UID, err)
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, panel := range cfg.LibraryPanels {
+			if err := p.provisionPanel(ctx, panel); err != nil {
+				return fmt.Errorf("%s: library panel %q: %w", cfg.Filename, panel.UID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// provisionPanel creates the library panel, or updates it when it differs from the file
+func (p *LibraryPanelsProvisioner) provisionPanel(ctx context.Context, panel *libraryPanelFromConfig) error {
+	ctx, user := identity.WithServiceIdentity(ctx, panel.OrgID)
+
+	// The library elements are still stored with the folder id
+	var folderID int64
+	folderUID := accesscontrol.GeneralFolderUID
+	if panel.FolderUID != "" {
+		f, err := p.folderService.Get(ctx, &folder.GetFolderQuery{OrgID: panel.OrgID, UID: &panel.FolderUID, SignedInUser: user})
+		if err != nil {
+			if errors.Is(err, dashboards.ErrFolderNotFound) {
+				return fmt.Errorf("%w: %s", ErrMissingFolder, panel.FolderUID)
+			}
+			return err
+		}
+		folderID = f.ID // nolint:staticcheck
+		folderUID = f.UID
+	}
+
+	panelModel := desiredModel(panel)
+	data, err := json.Marshal(panelModel)
+	if err != nil {
+		return err
+	}
+
+	existing, err := p.libraryElements.GetElement(ctx, user, model.GetLibraryElementCommand{UID: panel.UID, FolderName: dashboards.RootFolderName})
+	if errors.Is(err, model.ErrLibraryElementNotFound) {
+		p.log.Debug("Creating library panel", "orgId", panel.OrgID, "uid", panel.UID)
+		_, err = p.libraryElements.CreateElement(ctx, user, model.CreateLibraryElementCommand{
+			FolderID:  folderID, // nolint:staticcheck
+			FolderUID: &folderUID,
+			Name:      panel.Name,
+			Model:     data,
+			Kind:      int64(model.PanelElement),
+			UID:       panel.UID,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Kind != int64(model.PanelElement) {
+		return fmt.Errorf("the library element %q is not a library panel", panel.UID)
+	}
+	if existing.Name == panel.Name && existing.FolderUID == folderUID && sameModel(existing.Model, panelModel) {
+		return nil
+	}
+
+	p.log.Debug("Updating library panel", "orgId", panel.OrgID, "uid", panel.UID)
+	_, err = p.libraryElements.PatchElement(ctx, user, model.PatchLibraryElementCommand{
+		FolderID:  folderID, // nolint:staticcheck
+		FolderUID: &folderUID,
+		Name:      panel.Name,
+		Model:     data,
+		Kind:      int64(model.PanelElement),
+		Version:   existing.Version,
+	}, panel.UID)
+	return err
+}
+
+// deletePanel removes the library panel, it fails while dashboards are connected to it
+func (p *LibraryPanelsProvisioner) deletePanel(ctx context.Context, orgID int64, uid string) error {
+	ctx, user := identity.WithServiceIdentity(ctx, orgID)
+	err := p.libraryElements.DeleteElement(ctx, user, uid)
+	if errors.Is(err, model.ErrLibraryElementNotFound) {
+		return nil
+	}
+	if errors.Is(err, model.ErrLibraryElementHasConnections) {
+		return fmt.Errorf("it is still used by dashboards: %w", err)
+	}
+	if err == nil {
+		p.log.Debug("Deleted library panel", "orgId", orgID, "uid", uid)
+	}
+	return err
+}
+
+// exists checks if a library element with the uid exists, whatever its kind
+func (p *LibraryPanelsProvisioner) exists(ctx context.Context, orgID int64, uid string) (bool, error) {
+	ctx, user := identity.WithServiceIdentity(ctx, orgID)
+	_, err := p.libraryElements.GetElement(ctx, user, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if errors.Is(err, model.ErrLibraryElementNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// desiredModel returns the model as it is stored, the library elements always have a description
+func desiredModel(panel *libraryPanelFromConfig) map[string]any {
+	m := maps.Clone(panel.Model)
+	if m["description"] == nil {
+		m["description"] = ""
+	}
+	return m
+}
+
+// sameModel compares the stored JSON model with the provisioned one
+func sameModel(existing json.RawMessage, desired map[string]any) bool {
+	var current map[string]any
+	if err := json.Unmarshal(existing, &current); err != nil {
+		return false
+	}
+
+	// The provisioned model is compared after a round trip, so the numbers have the same type
+	data, err := json.Marshal(desired)
+	if err != nil {
+		return false
+	}
+	var expected map[string]any
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return false
+	}
+
+	// The uid of the library panel is added to the stored model when it is read
+	delete(current, "libraryPanel")
+	delete(expected, "libraryPanel")
+
+	return reflect.DeepEqual(current, expected)
+}
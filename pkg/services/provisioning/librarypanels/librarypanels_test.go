@@ -0,0 +1,236 @@
+package librarypanels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	libraryelementsfake "github.com/grafana/grafana/pkg/services/libraryelements/fake"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+const testRepositoryPanel = `apiVersion: 1
+libraryPanels:
+  - orgId: 1337
+    uid: %s
+    folderUid: ignored
+    model:
+      type: stat
+      title: Requests
+      targets:
+        - refId: A
+          expr: sum(rate(requests_total{job="$job"}[$__rate_interval]))
+`
+
+func setupProvisioner(t *testing.T) (*LibraryPanelsProvisioner, *libraryelementsfake.LibraryElementService) {
+	t.Helper()
+
+	folders := foldertest.NewFakeService()
+	folders.ExpectedError = dashboards.ErrFolderNotFound
+	folders.AddFolder(&folder.Folder{ID: 10, UID: "platform", Title: "Platform"})
+	folders.AddFolder(&folder.Folder{ID: 11, UID: "other", Title: "Other"})
+
+	elements := &libraryelementsfake.LibraryElementService{}
+	p := newLibraryPanelsProvisioner(logger, ProvisionerConfig{
+		OrgService:            &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}},
+		LibraryElementService: elements,
+		FolderService:         folders,
+	})
+	return p, elements
+}
+
+func getElement(t *testing.T, elements *libraryelementsfake.LibraryElementService, uid string) model.LibraryElementDTO {
+	t.Helper()
+	_, user := identity.WithServiceIdentity(context.Background(), 1)
+	element, err := elements.GetElement(context.Background(), user, model.GetLibraryElementCommand{UID: uid})
+	require.NoError(t, err)
+	return element
+}
+
+func mustField(t *testing.T, data json.RawMessage, field string) json.RawMessage {
+	t.Helper()
+	var m map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &m))
+	return m[field]
+}
+
+func TestLibraryPanelsProvisioner(t *testing.T) {
+	t.Run("creates the library panels in their folder", func(t *testing.T) {
+		t.Setenv("PANEL_UID", "runbook")
+		p, elements := setupProvisioner(t)
+
+		require.NoError(t, p.applyChanges(context.Background(), allProperties))
+
+		cpu := getElement(t, elements, "cpu-usage")
+		require.Equal(t, "CPU usage", cpu.Name)
+		require.Equal(t, "platform", cpu.FolderUID)
+		require.Equal(t, int64(10), cpu.FolderID) // nolint:staticcheck
+		require.Equal(t, int64(model.PanelElement), cpu.Kind)
+		require.JSONEq(t, `"The CPU usage of the $instance"`, string(mustField(t, cpu.Model, "description")))
+
+		runbook := getElement(t, elements, "runbook")
+		require.Equal(t, "Runbook", runbook.Name)
+		require.Equal(t, "general", runbook.FolderUID)
+	})
+
+	t.Run("does not update the library panels that did not change", func(t *testing.T) {
+		t.Setenv("PANEL_UID", "runbook")
+		p, elements := setupProvisioner(t)
+
+		require.NoError(t, p.applyChanges(context.Background(), allProperties))
+		require.NoError(t, p.applyChanges(context.Background(), allProperties))
+
+		require.Equal(t, int64(1), getElement(t, elements, "cpu-usage").Version)
+		require.Equal(t, int64(1), getElement(t, elements, "runbook").Version)
+	})
+
+	t.Run("updates the library panels that changed", func(t *testing.T) {
+		t.Setenv("PANEL_UID", "runbook")
+		p, elements := setupProvisioner(t)
+		require.NoError(t, p.applyChanges(context.Background(), allProperties))
+
+		cfgs, err := p.cfgProvider.readConfig(context.Background(), allProperties)
+		require.NoError(t, err)
+		cfgs[0].LibraryPanels[0].FolderUID = "other"
+		cfgs[0].LibraryPanels[1].Model["title"] = "Runbook link"
+		require.NoError(t, p.apply(context.Background(), cfgs))
+
+		cpu := getElement(t, elements, "cpu-usage")
+		require.Equal(t, int64(2), cpu.Version)
+		require.Equal(t, "other", cpu.FolderUID)
+		require.Equal(t, int64(11), cpu.FolderID) // nolint:staticcheck
+
+		runbook := getElement(t, elements, "runbook")
+		require.Equal(t, int64(2), runbook.Version)
+		require.JSONEq(t, `"Runbook link"`, string(mustField(t, runbook.Model, "title")))
+	})
+
+	t.Run("deletes the library panels", func(t *testing.T) {
+		t.Setenv("PANEL_UID", "runbook")
+		p, elements := setupProvisioner(t)
+		require.NoError(t, p.applyChanges(context.Background(), allProperties))
+
+		require.NoError(t, p.applyChanges(context.Background(), deletePanels))
+
+		_, user := identity.WithServiceIdentity(context.Background(), 1)
+		_, err := elements.GetElement(context.Background(), user, model.GetLibraryElementCommand{UID: "cpu-usage"})
+		require.ErrorIs(t, err, model.ErrLibraryElementNotFound)
+		getElement(t, elements, "runbook")
+	})
+
+	t.Run("the folder must exist", func(t *testing.T) {
+		p, _ := setupProvisioner(t)
+		cfg := &configs{Filename: "panels.yaml", LibraryPanels: []*libraryPanelFromConfig{{
+			OrgID: 1, UID: "cpu-usage", Name: "CPU usage", FolderUID: "missing", Model: map[string]any{"type": "stat"},
+		}}}
+
+		err := p.apply(context.Background(), []*configs{cfg})
+		require.ErrorIs(t, err, ErrMissingFolder)
+	})
+}
+
+func TestRepositoryProvisioner(t *testing.T) {
+	setup := func(t *testing.T) (*RepositoryProvisioner, *libraryelementsfake.LibraryElementService) {
+		p, elements := setupProvisioner(t)
+		return &RepositoryProvisioner{provisioner: p, kv: kvstore.NewFakeKVStore()}, elements
+	}
+	ctx := context.Background()
+
+	t.Run("applies the library panels in the folder of the file", func(t *testing.T) {
+		p, elements := setup(t)
+
+		err := p.Apply(ctx, "default", "repo", "platform", "panels/requests.yaml", "h1", []byte(fmt.Sprintf(testRepositoryPanel, "requests")))
+		require.NoError(t, err)
+
+		requests := getElement(t, elements, "requests")
+		require.Equal(t, "Requests", requests.Name)
+		require.Equal(t, "platform", requests.FolderUID)
+		require.Contains(t, string(requests.Model), `$__rate_interval`)
+
+		files, err := p.List(ctx, "default", "repo")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"panels/requests.yaml": "h1"}, files)
+	})
+
+	t.Run("JSON files are applied", func(t *testing.T) {
+		p, elements := setup(t)
+
+		err := p.Apply(ctx, "default", "repo", "", "requests.json", "h1", []byte(`{"apiVersion": 1, "libraryPanels": [{"uid": "requests", "model": {"type": "stat", "title": "Requests"}}]}`))
+		require.NoError(t, err)
+		require.Equal(t, "Requests", getElement(t, elements, "requests").Name)
+	})
+
+	t.Run("removes the library panels of the file", func(t *testing.T) {
+		p, elements := setup(t)
+		data := []byte(fmt.Sprintf(testRepositoryPanel, "requests"))
+		require.NoError(t, p.Apply(ctx, "default", "repo", "", "requests.yaml", "h1", data))
+
+		require.NoError(t, p.Remove(ctx, "default", "repo", "requests.yaml"))
+
+		_, user := identity.WithServiceIdentity(ctx, 1)
+		_, err := elements.GetElement(ctx, user, model.GetLibraryElementCommand{UID: "requests"})
+		require.ErrorIs(t, err, model.ErrLibraryElementNotFound)
+
+		files, err := p.List(ctx, "default", "repo")
+		require.NoError(t, err)
+		require.Empty(t, files)
+	})
+
+	t.Run("removes the library panels that are no longer in the file", func(t *testing.T) {
+		p, elements := setup(t)
+		require.NoError(t, p.Apply(ctx, "default", "repo", "", "requests.yaml", "h1", []byte(fmt.Sprintf(testRepositoryPanel, "requests"))))
+
+		require.NoError(t, p.Apply(ctx, "default", "repo", "", "requests.yaml", "h2", []byte(fmt.Sprintf(testRepositoryPanel, "errors"))))
+
+		_, user := identity.WithServiceIdentity(ctx, 1)
+		_, err := elements.GetElement(ctx, user, model.GetLibraryElementCommand{UID: "requests"})
+		require.ErrorIs(t, err, model.ErrLibraryElementNotFound)
+		require.Equal(t, "Requests", getElement(t, elements, "errors").Name)
+	})
+
+	t.Run("library panels of another file can not be changed", func(t *testing.T) {
+		p, _ := setup(t)
+		data := []byte(fmt.Sprintf(testRepositoryPanel, "requests"))
+		require.NoError(t, p.Apply(ctx, "default", "repo", "", "requests.yaml", "h1", data))
+
+		err := p.Validate(ctx, "default", "other", "requests.yaml", data)
+		require.ErrorIs(t, err, ErrPanelManaged)
+		require.ErrorContains(t, err, "requests.yaml in repository repo")
+
+		err = p.Apply(ctx, "default", "repo", "", "copy.yaml", "h1", data)
+		require.ErrorIs(t, err, ErrPanelManaged)
+	})
+
+	t.Run("library panels created another way can not be changed", func(t *testing.T) {
+		p, elements := setup(t)
+		_, user := identity.WithServiceIdentity(ctx, 1)
+		_, err := elements.CreateElement(ctx, user, model.CreateLibraryElementCommand{UID: "requests", Name: "Created in the UI", Kind: int64(model.PanelElement)})
+		require.NoError(t, err)
+
+		err = p.Apply(ctx, "default", "repo", "", "requests.yaml", "h1", []byte(fmt.Sprintf(testRepositoryPanel, "requests")))
+		require.ErrorIs(t, err, ErrPanelManaged)
+		require.Equal(t, "Created in the UI", getElement(t, elements, "requests").Name)
+
+		// The library panels listed for deletion are checked as well
+		err = p.Validate(ctx, "default", "repo", "delete.yaml", []byte("apiVersion: 1\ndeleteLibraryPanels:\n  - uid: requests\n"))
+		require.ErrorIs(t, err, ErrPanelManaged)
+	})
+
+	t.Run("environment variables should be rejected", func(t *testing.T) {
+		p, _ := setup(t)
+		t.Setenv("PANEL_UID", "requests")
+
+		err := p.Validate(ctx, "default", "repo", "requests.yaml", []byte(fmt.Sprintf(testRepositoryPanel, "$PANEL_UID")))
+		require.ErrorIs(t, err, ErrInterpolationNotAllowed)
+	})
+}
@@ -0,0 +1,279 @@
+package librarypanels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	authlib "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// The kvstore namespace where the library panels applied from the files of each repository are recorded
+const repositoryKVNamespace = "librarypanels.repository"
+
+// ErrPanelManaged is returned when a repository file defines a library panel that the repository does not manage
+var ErrPanelManaged = errors.New("library panel is managed elsewhere")
+
+// repositoryFile records the library panels applied from a repository file
+type repositoryFile struct {
+	Hash string   `json:"hash"`
+	UIDs []string `json:"uids"`
+}
+
+// RepositoryProvisioner applies the library panel files synced from a repository to the organization
+// of the repository namespace. The library panels are saved in the folder of the file, the folder and
+// organization set in the file are ignored. The library panels applied from each file are recorded per
+// repository, so a repository never changes the library panels of another repository, or the ones
+// created by other means.
+type RepositoryProvisioner struct {
+	provisioner *LibraryPanelsProvisioner
+	kv          kvstore.KVStore
+}
+
+func NewRepositoryProvisioner(cfg ProvisionerConfig, kv kvstore.KVStore) *RepositoryProvisioner {
+	return &RepositoryProvisioner{
+		provisioner: newLibraryPanelsProvisioner(log.New("provisioning.librarypanels.repository"), cfg),
+		kv:          kv,
+	}
+}
+
+// Validate checks the file can be applied by the repository, without changing anything
+func (p *RepositoryProvisioner) Validate(ctx context.Context, namespace string, repository string, path string, data []byte) error {
+	cfg, orgID, err := p.parse(namespace, "", path, data)
+	if err != nil {
+		return err
+	}
+	owned, err := p.repositoryFiles(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return p.checkOwnership(ctx, orgID, repository, path, cfg.uids(), owned)
+}
+
+// Apply creates or updates the library panels defined in the file in the given folder, and deletes
+// the library panels that were applied from a previous version of the file
+func (p *RepositoryProvisioner) Apply(ctx context.Context, namespace string, repository string, folderUID string, path string, hash string, data []byte) error {
+	cfg, orgID, err := p.parse(namespace, folderUID, path, data)
+	if err != nil {
+		return err
+	}
+	owned, err := p.repositoryFiles(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if err := p.checkOwnership(ctx, orgID, repository, path, cfg.uids(), owned); err != nil {
+		return err
+	}
+
+	files := owned[repository]
+	if files == nil {
+		files = map[string]*repositoryFile{}
+	}
+	var previous []string
+	if f, ok := files[path]; ok {
+		previous = f.UIDs
+	}
+	applied := make([]string, 0, len(cfg.LibraryPanels))
+	for _, panel := range cfg.LibraryPanels {
+		applied = append(applied, panel.UID)
+	}
+
+	// Record the library panels before applying them, so the ones applied before a failure stay owned by the file.
+	// The hash is only recorded once the file is applied, so a full sync applies it again.
+	files[path] = &repositoryFile{UIDs: union(previous, applied)}
+	if err := p.save(ctx, orgID, repository, files); err != nil {
+		return err
+	}
+	if err := p.provisioner.apply(ctx, []*configs{cfg}); err != nil {
+		return err
+	}
+	if err := p.removePanels(ctx, orgID, path, difference(previous, applied)); err != nil {
+		return err
+	}
+	files[path] = &repositoryFile{Hash: hash, UIDs: applied}
+	return p.save(ctx, orgID, repository, files)
+}
+
+// Remove deletes the library panels applied from the file, when the file is removed from the repository
+func (p *RepositoryProvisioner) Remove(ctx context.Context, namespace string, repository string, path string) error {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return err
+	}
+	files, err := p.load(ctx, orgID, repository)
+	if err != nil {
+		return err
+	}
+	f, ok := files[path]
+	if !ok {
+		return nil
+	}
+	if err := p.removePanels(ctx, orgID, path, f.UIDs); err != nil {
+		return err
+	}
+	delete(files, path)
+	return p.save(ctx, orgID, repository, files)
+}
+
+// List returns the hash of the applied files of the repository, by path
+func (p *RepositoryProvisioner) List(ctx context.Context, namespace string, repository string) (map[string]string, error) {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.load(ctx, orgID, repository)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string, len(files))
+	for path, f := range files {
+		hashes[path] = f.Hash
+	}
+	return hashes, nil
+}
+
+// checkOwnership verifies the library panels are not managed by another repository file, and that the
+// ones this file did not apply yet do not exist, since they were created by other means
+func (p *RepositoryProvisioner) checkOwnership(ctx context.Context, orgID int64, repository string, path string, uids []string, owned map[string]map[string]*repositoryFile) error {
+	owners := map[string]string{}
+	for r, files := range owned {
+		for filePath, f := range files {
+			for _, uid := range f.UIDs {
+				if r == repository && filePath == path {
+					owners[uid] = ""
+					continue
+				}
+				owners[uid] = fmt.Sprintf("%s in repository %s", filePath, r)
+			}
+		}
+	}
+
+	for _, uid := range uids {
+		owner, ok := owners[uid]
+		if ok && owner != "" {
+			return fmt.Errorf("%w: library panel %q is managed by %s", ErrPanelManaged, uid, owner)
+		}
+		if ok {
+			continue // applied from this file before
+		}
+		exists, err := p.provisioner.exists(ctx, orgID, uid)
+		if err != nil {
+			return fmt.Errorf("library panel %q: %w", uid, err)
+		}
+		if exists {
+			return fmt.Errorf("%w: library panel %q already exists and is not managed by the repository", ErrPanelManaged, uid)
+		}
+	}
+	return nil
+}
+
+// removePanels deletes the library panels, it fails while dashboards are connected to them
+func (p *RepositoryProvisioner) removePanels(ctx context.Context, orgID int64, path string, uids []string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	removal := &configs{Filename: path}
+	for _, uid := range uids {
+		removal.DeleteLibraryPanels = append(removal.DeleteLibraryPanels, &deleteLibraryPanelConfig{OrgID: orgID, UID: uid})
+	}
+	return p.provisioner.apply(ctx, []*configs{removal})
+}
+
+// repositoryFiles returns the applied files of all the repositories of the organization
+func (p *RepositoryProvisioner) repositoryFiles(ctx context.Context, orgID int64) (map[string]map[string]*repositoryFile, error) {
+	keys, err := p.kv.Keys(ctx, orgID, repositoryKVNamespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("read repository files: %w", err)
+	}
+	owned := make(map[string]map[string]*repositoryFile, len(keys))
+	for _, key := range keys {
+		files, err := p.load(ctx, orgID, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		owned[key.Key] = files
+	}
+	return owned, nil
+}
+
+// load returns the applied files of the repository
+func (p *RepositoryProvisioner) load(ctx context.Context, orgID int64, repository string) (map[string]*repositoryFile, error) {
+	files := map[string]*repositoryFile{}
+	value, ok, err := p.kv.Get(ctx, orgID, repositoryKVNamespace, repository)
+	if err != nil || !ok {
+		return files, err
+	}
+	if err := json.Unmarshal([]byte(value), &files); err != nil {
+		return nil, fmt.Errorf("read repository files of %s: %w", repository, err)
+	}
+	return files, nil
+}
+
+func (p *RepositoryProvisioner) save(ctx context.Context, orgID int64, repository string, files map[string]*repositoryFile) error {
+	if len(files) == 0 {
+		return p.kv.Del(ctx, orgID, repositoryKVNamespace, repository)
+	}
+	value, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+	return p.kv.Set(ctx, orgID, repositoryKVNamespace, repository, string(value))
+}
+
+func (p *RepositoryProvisioner) parse(namespace string, folderUID string, path string, data []byte) (*configs, int64, error) {
+	orgID, err := namespaceOrgID(namespace)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cfg, err := parseConfig(path, data, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cfg == nil {
+		return nil, 0, fmt.Errorf("empty file %s", path)
+	}
+
+	for _, panel := range cfg.LibraryPanels {
+		panel.OrgID = orgID
+		panel.FolderUID = folderUID
+	}
+	for _, panel := range cfg.DeleteLibraryPanels {
+		panel.OrgID = orgID
+	}
+	return cfg, orgID, nil
+}
+
+func namespaceOrgID(namespace string) (int64, error) {
+	info, err := authlib.ParseNamespace(namespace)
+	if err != nil {
+		return 0, fmt.Errorf("invalid namespace %s: %w", namespace, err)
+	}
+	return info.OrgID, nil
+}
+
+// union returns the uids of a followed by the ones of b that are not in a
+func union(a, b []string) []string {
+	uids := slices.Clone(a)
+	for _, uid := range b {
+		if !slices.Contains(uids, uid) {
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
+
+// difference returns the uids of a that are not in b
+func difference(a, b []string) []string {
+	uids := []string{}
+	for _, uid := range a {
+		if !slices.Contains(b, uid) {
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
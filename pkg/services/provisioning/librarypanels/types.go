@@ -0,0 +1,126 @@
+package librarypanels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// configs is the normalized content of one provisioning file
+type configs struct {
+	Filename            string
+	LibraryPanels       []*libraryPanelFromConfig
+	DeleteLibraryPanels []*deleteLibraryPanelConfig
+}
+
+type libraryPanelFromConfig struct {
+	OrgID int64
+	UID   string
+	Name  string
+	// The folder of the library panel, the General folder when it is empty
+	FolderUID string
+	// The panel JSON, as it is found in the panels of a dashboard
+	Model map[string]any
+}
+
+// uids returns the uids of the library panels defined or deleted by the file
+func (cfg *configs) uids() []string {
+	uids := make([]string, 0, len(cfg.LibraryPanels)+len(cfg.DeleteLibraryPanels))
+	for _, panel := range cfg.LibraryPanels {
+		uids = append(uids, panel.UID)
+	}
+	for _, panel := range cfg.DeleteLibraryPanels {
+		uids = append(uids, panel.UID)
+	}
+	return uids
+}
+
+type deleteLibraryPanelConfig struct {
+	OrgID int64
+	UID   string
+}
+
+type configsV1 struct {
+	configVersion
+
+	LibraryPanels       []*libraryPanelFromConfigV1   `json:"libraryPanels" yaml:"libraryPanels"`
+	DeleteLibraryPanels []*deleteLibraryPanelConfigV1 `json:"deleteLibraryPanels" yaml:"deleteLibraryPanels"`
+}
+
+type libraryPanelFromConfigV1 struct {
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID       values.StringValue `json:"uid" yaml:"uid"`
+	Name      values.StringValue `json:"name" yaml:"name"`
+	FolderUID values.StringValue `json:"folderUid" yaml:"folderUid"`
+	// The model is not interpolated, the panel queries use $ for the dashboard variables
+	Model map[string]any `json:"model" yaml:"model"`
+}
+
+type deleteLibraryPanelConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (cfg *configsV1) mapToLibraryPanelsFromConfig() *configs {
+	r := &configs{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, panel := range cfg.LibraryPanels {
+		if panel == nil {
+			continue
+		}
+		r.LibraryPanels = append(r.LibraryPanels, &libraryPanelFromConfig{
+			OrgID:     panel.OrgID.Value(),
+			UID:       panel.UID.Value(),
+			Name:      panel.Name.Value(),
+			FolderUID: panel.FolderUID.Value(),
+			Model:     panel.Model,
+		})
+	}
+
+	for _, panel := range cfg.DeleteLibraryPanels {
+		if panel == nil {
+			continue
+		}
+		r.DeleteLibraryPanels = append(r.DeleteLibraryPanels, &deleteLibraryPanelConfig{
+			OrgID: panel.OrgID.Value(),
+			UID:   panel.UID.Value(),
+		})
+	}
+
+	return r
+}
+
+// checkNotInterpolated returns an error if a value was changed by the interpolation of
+// environment variables or files, other than the $$ escape sequence
+func (cfg *configsV1) checkNotInterpolated() error {
+	for i, panel := range cfg.LibraryPanels {
+		if panel == nil {
+			continue
+		}
+		if isInterpolated(panel.UID) || isInterpolated(panel.Name) || isInterpolated(panel.FolderUID) || strings.Contains(panel.OrgID.Raw, "$") {
+			return fmt.Errorf("libraryPanels[%d]: %w", i, ErrInterpolationNotAllowed)
+		}
+	}
+	for i, panel := range cfg.DeleteLibraryPanels {
+		if panel == nil {
+			continue
+		}
+		if isInterpolated(panel.UID) || strings.Contains(panel.OrgID.Raw, "$") {
+			return fmt.Errorf("deleteLibraryPanels[%d]: %w", i, ErrInterpolationNotAllowed)
+		}
+	}
+	return nil
+}
+
+func isInterpolated(val values.StringValue) bool {
+	return val.Value() != strings.ReplaceAll(val.Raw, "$$", "$")
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/registry"
@@ -17,6 +18,7 @@ import (
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
 	alertingauthz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
@@ -30,10 +32,15 @@ import (
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/iam"
+	prov_librarypanels "github.com/grafana/grafana/pkg/services/provisioning/librarypanels"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/storage/legacysql/dualwrite"
 )
@@ -59,6 +66,13 @@ func ProvideService(
 	resourcePermissions accesscontrol.ReceiverPermissionsService,
 	tracer tracing.Tracer,
 	dual dualwrite.Service,
+	acService accesscontrol.Service,
+	userService user.Service,
+	teamService team.Service,
+	teamPermissions accesscontrol.TeamPermissionsService,
+	serviceAccountsService serviceaccounts.Service,
+	folderPermissions accesscontrol.FolderPermissionsService,
+	libraryElementService libraryelements.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -72,6 +86,8 @@ func ProvideService(
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
+		provisionIAM:                 iam.Provision,
+		provisionLibraryPanels:       prov_librarypanels.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
 		datasourceService:            datasourceService,
@@ -85,6 +101,13 @@ func ProvideService(
 		folderService:                folderService,
 		resourcePermissions:          resourcePermissions,
 		tracer:                       tracer,
+		acService:                    acService,
+		userService:                  userService,
+		teamService:                  teamService,
+		teamPermissions:              teamPermissions,
+		serviceAccountsService:       serviceAccountsService,
+		folderPermissions:            folderPermissions,
+		libraryElementService:        libraryElementService,
 	}
 
 	if err := s.setDashboardProvisioner(); err != nil {
@@ -111,6 +134,8 @@ type ProvisioningService interface {
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	ProvisionIAM(ctx context.Context) error
+	ProvisionLibraryPanels(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 }
@@ -154,6 +179,8 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.BaseDataSourceService, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionIAM                 func(context.Context, iam.ProvisionerConfig) error
+	provisionLibraryPanels       func(context.Context, prov_librarypanels.ProvisionerConfig) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -167,6 +194,13 @@ type ProvisioningServiceImpl struct {
 	resourcePermissions          accesscontrol.ReceiverPermissionsService
 	tracer                       tracing.Tracer
 	dual                         dualwrite.Service
+	acService                    accesscontrol.Service
+	userService                  user.Service
+	teamService                  team.Service
+	teamPermissions              accesscontrol.TeamPermissionsService
+	serviceAccountsService       serviceaccounts.Service
+	folderPermissions            accesscontrol.FolderPermissionsService
+	libraryElementService        libraryelements.Service
 	onceInitProvisioners         sync.Once
 }
 
@@ -180,7 +214,7 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 	var err error
 
-	// Run Datasources, Plugins and Alerting Provisioning only once.
+	// Run Datasources, Plugins, Alerting, IAM and Library Panels Provisioning only once.
 	// It can't be initialized at RunInitProvisioners because it
 	// depends on the /apis endpoints to be already running and listeningq
 	ps.onceInitProvisioners.Do(func() {
@@ -201,6 +235,19 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 			ps.log.Error("Failed to provision alerting", "error", err)
 			return
 		}
+
+		err = ps.ProvisionIAM(ctx)
+		if err != nil {
+			ps.log.Error("Failed to provision iam", "error", err)
+			return
+		}
+
+		// The library panels are provisioned before the dashboards that reference them
+		err = ps.ProvisionLibraryPanels(ctx)
+		if err != nil {
+			ps.log.Error("Failed to provision library panels", "error", err)
+			return
+		}
 	})
 
 	if err != nil {
@@ -289,7 +336,68 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
-	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	cfg := ps.alertingProvisionerConfig()
+	cfg.Path = filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	return ps.provisionAlerting(ctx, cfg)
+}
+
+// ProvisionIAM reconciles the org members, teams, service accounts and folders of the iam provisioning files
+func (ps *ProvisioningServiceImpl) ProvisionIAM(ctx context.Context) error {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	err := ps.provisionIAM(ctx, iam.ProvisionerConfig{
+		Path:                     filepath.Join(ps.Cfg.ProvisioningPath, "iam"),
+		OrgService:               ps.orgService,
+		UserService:              ps.userService,
+		TeamService:              ps.teamService,
+		RoleService:              ps.acService,
+		TeamPermissionsService:   ps.teamPermissions,
+		ServiceAccountService:    ps.serviceAccountsService,
+		FolderService:            ps.folderService,
+		FolderPermissionsService: ps.folderPermissions,
+		DashboardFolders:         ps.dashboardProvisioner,
+	})
+	if err != nil {
+		err = fmt.Errorf("%v: %w", "iam provisioning error", err)
+		ps.log.Error("Failed to provision iam", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ProvisionLibraryPanels creates, updates and deletes the library panels of the library panel provisioning files
+func (ps *ProvisioningServiceImpl) ProvisionLibraryPanels(ctx context.Context) error {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	cfg := ps.libraryPanelsProvisionerConfig()
+	cfg.Path = filepath.Join(ps.Cfg.ProvisioningPath, "library-panels")
+	if err := ps.provisionLibraryPanels(ctx, cfg); err != nil {
+		err = fmt.Errorf("%v: %w", "library panel provisioning error", err)
+		ps.log.Error("Failed to provision library panels", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ProvideLibraryPanelsRepositoryProvisioner returns the provisioner of the library panel files synced from a repository
+func ProvideLibraryPanelsRepositoryProvisioner(ps *ProvisioningServiceImpl, kv kvstore.KVStore) *prov_librarypanels.RepositoryProvisioner {
+	return prov_librarypanels.NewRepositoryProvisioner(ps.libraryPanelsProvisionerConfig(), kv)
+}
+
+func (ps *ProvisioningServiceImpl) libraryPanelsProvisionerConfig() prov_librarypanels.ProvisionerConfig {
+	return prov_librarypanels.ProvisionerConfig{
+		OrgService:            ps.orgService,
+		LibraryElementService: ps.libraryElementService,
+		FolderService:         ps.folderService,
+	}
+}
+
+// ProvideAlertingRepositoryProvisioner returns the provisioner of the alerting files synced from a repository
+func ProvideAlertingRepositoryProvisioner(ps *ProvisioningServiceImpl, kv kvstore.KVStore) *prov_alerting.RepositoryProvisioner {
+	return prov_alerting.NewRepositoryProvisioner(ps.alertingProvisionerConfig(), ps.alertingStore, kv)
+}
+
+func (ps *ProvisioningServiceImpl) alertingProvisionerConfig() prov_alerting.ProvisionerConfig {
 	ruleService := provisioning.NewAlertRuleService(
 		ps.alertingStore,
 		ps.alertingStore,
@@ -322,8 +430,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		ps.alertingStore, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(configStore, ps.alertingStore, ps.alertingStore, ps.log, ps.alertingStore)
 	templateService := provisioning.NewTemplateService(configStore, ps.alertingStore, ps.alertingStore, ps.log)
-	cfg := prov_alerting.ProvisionerConfig{
-		Path:                       alertingPath,
+	return prov_alerting.ProvisionerConfig{
 		RuleService:                *ruleService,
 		FolderService:              ps.folderService,
 		DashboardProvService:       ps.dashboardProvisioningService,
@@ -332,7 +439,6 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
 	}
-	return ps.provisionAlerting(ctx, cfg)
 }
 
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
@@ -8,6 +8,8 @@ type Calls struct {
 	ProvisionPlugins                    []any
 	ProvisionDashboards                 []any
 	ProvisionAlerting                   []any
+	ProvisionIAM                        []any
+	ProvisionLibraryPanels              []any
 	GetDashboardProvisionerResolvedPath []any
 	GetAllowUIUpdatesFromConfig         []any
 	Run                                 []any
@@ -67,6 +69,16 @@ func (mock *ProvisioningServiceMock) ProvisionAlerting(ctx context.Context) erro
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionIAM(ctx context.Context) error {
+	mock.Calls.ProvisionIAM = append(mock.Calls.ProvisionIAM, nil)
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionLibraryPanels(ctx context.Context) error {
+	mock.Calls.ProvisionLibraryPanels = append(mock.Calls.ProvisionLibraryPanels, nil)
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) GetDashboardProvisionerResolvedPath(name string) string {
 	mock.Calls.GetDashboardProvisionerResolvedPath = append(mock.Calls.GetDashboardProvisionerResolvedPath, name)
 	if mock.GetDashboardProvisionerResolvedPathFunc != nil {
@@ -18,6 +18,8 @@ import (
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/iam"
+	prov_librarypanels "github.com/grafana/grafana/pkg/services/provisioning/librarypanels"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/storage/legacysql/dualwrite"
@@ -175,6 +177,12 @@ func setup(t *testing.T) *serviceTestStruct {
 	service.provisionAlerting = func(context.Context, prov_alerting.ProvisionerConfig) error {
 		return nil
 	}
+	service.provisionIAM = func(context.Context, iam.ProvisionerConfig) error {
+		return nil
+	}
+	service.provisionLibraryPanels = func(context.Context, prov_librarypanels.ProvisionerConfig) error {
+		return nil
+	}
 	serviceTest.service = service
 	require.NoError(t, err)
 
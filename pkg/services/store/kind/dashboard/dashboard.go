@@ -482,6 +482,8 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 	}
 
 	panel.Datasource = targets.GetDatasourceInfo()
+	panel.Queries = targets.GetQueries()
+	panel.Metrics = targets.GetMetrics()
 
 	return panel
 }
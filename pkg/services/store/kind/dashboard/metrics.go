@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Used in place of template variables so the expression can be parsed
+const variablePlaceholder = "__grafana_variable__"
+
+var (
+	// [[var]] or [[var:format]]
+	legacyVariableRegex = regexp.MustCompile(`\[\[(\w+)(?::\w+)?\]\]`)
+	// Range and subquery durations that include a variable, eg [$__rate_interval]
+	variableRangeRegex = regexp.MustCompile(`\[[^\[\]]*\$[^\[\]]*\]`)
+	// $var, ${var} and ${var:format}
+	variableRegex = regexp.MustCompile(`\$\{[^}]+\}|\$\w+`)
+)
+
+// MetricNames returns the metric names referenced by a PromQL expression.
+// Template variables are replaced before parsing, and nothing is returned for
+// expressions that can not be parsed (eg, LogQL or SQL)
+func MetricNames(expr string) []string {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil
+	}
+
+	expr = legacyVariableRegex.ReplaceAllString(expr, "$${$1}")
+	expr = variableRangeRegex.ReplaceAllStringFunc(expr, func(r string) string {
+		if strings.Contains(r, ":") {
+			return "[1m:1m]"
+		}
+		return "[1m]"
+	})
+	expr = variableRegex.ReplaceAllString(expr, variablePlaceholder)
+
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil
+	}
+
+	names := make(map[string]bool)
+	parser.Inspect(node, func(n parser.Node, _ []parser.Node) error {
+		vs, ok := n.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		name := vs.Name
+		if name == "" {
+			for _, m := range vs.LabelMatchers {
+				if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+					name = m.Value
+				}
+			}
+		}
+		if name != "" && !strings.Contains(name, variablePlaceholder) {
+			names[name] = true
+		}
+		return nil
+	})
+
+	keys := make([]string, 0, len(names))
+	for k := range names {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
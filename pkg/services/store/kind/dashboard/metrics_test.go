@@ -0,0 +1,37 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricNames(t *testing.T) {
+	tests := []struct {
+		expr    string
+		metrics []string
+	}{
+		{expr: "", metrics: nil},
+		{expr: "up", metrics: []string{"up"}},
+		{
+			expr:    `sum by (mode) (rate(node_cpu_seconds_total{instance="$instance"}[$__rate_interval])) / on() group_left count(node_cpu_seconds_total)`,
+			metrics: []string{"node_cpu_seconds_total"},
+		},
+		{
+			expr:    `histogram_quantile(0.9, sum(rate(http_request_duration_seconds_bucket{job=~"${job:regex}"}[5m])) by (le)) > bool http_requests_total`,
+			metrics: []string{"http_request_duration_seconds_bucket", "http_requests_total"},
+		},
+		{
+			expr:    `max_over_time(go_goroutines[$__range:$__interval]) + {__name__="process_open_fds", job="[[job]]"}`,
+			metrics: []string{"go_goroutines", "process_open_fds"},
+		},
+		{expr: `$metric{job="api"}`, metrics: []string{}},
+		{expr: `sum(count_over_time({app="api"} |= "error" [5m]))`, metrics: nil}, // LogQL
+		{expr: "select * from users", metrics: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			require.Equal(t, tt.metrics, MetricNames(tt.expr))
+		})
+	}
+}
@@ -1,12 +1,17 @@
 package dashboard
 
 import (
+	"slices"
+	"sort"
+	"strings"
+
 	jsoniter "github.com/json-iterator/go"
 )
 
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -75,6 +80,14 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 		case "refId":
 			iter.Skip()
 
+		// The query text for the common datasources (PromQL, LogQL, SQL, etc)
+		case "expr", "expression", "query", "rawSql", "queryText":
+			if iter.WhatIsNext() == jsoniter.StringValue {
+				s.addQuery(iter.ReadString())
+			} else {
+				iter.Skip()
+			}
+
 		default:
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
@@ -89,3 +102,29 @@ func (s *targetInfo) addPanel(panel PanelSummaryInfo) {
 		}
 	}
 }
+
+func (s *targetInfo) addQuery(q string) {
+	q = strings.TrimSpace(q)
+	if q != "" && !slices.Contains(s.queries, q) {
+		s.queries = append(s.queries, q)
+	}
+}
+
+// GetQueries returns the distinct query expressions
+func (s *targetInfo) GetQueries() []string {
+	return s.queries
+}
+
+// GetMetrics returns the distinct metric names referenced by the queries
+func (s *targetInfo) GetMetrics() []string {
+	var metrics []string
+	for _, q := range s.queries {
+		for _, m := range MetricNames(q) {
+			if !slices.Contains(metrics, m) {
+				metrics = append(metrics, m)
+			}
+		}
+	}
+	sort.Strings(metrics)
+	return metrics
+}
@@ -132,6 +132,7 @@ type Cfg struct {
 	HomePath                   string
 	ProvisioningPath           string
 	PermittedProvisioningPaths []string
+	PermittedBucketEndpoints   []string
 	// Job History Configuration
 	ProvisioningLokiURL      string
 	ProvisioningLokiUser     string
@@ -471,6 +472,23 @@ type Cfg struct {
 	// LiveMessageSizeLimit is the maximum size in bytes of Websocket messages
 	// from clients. Defaults to 64KB.
 	LiveMessageSizeLimit int
+	// LivePipelineEnabled processes data published into Live channels with
+	// the channel rules of the organization.
+	LivePipelineEnabled bool
+	// LiveGraphiteListenAddress enables Graphite plaintext protocol listener
+	// feeding the Live pipeline.
+	LiveGraphiteListenAddress string
+	// LiveGraphiteChannel is a Live channel Graphite metrics are pushed to.
+	LiveGraphiteChannel string
+	// LiveStatsDListenAddress enables StatsD listener feeding the Live pipeline.
+	LiveStatsDListenAddress string
+	// LiveStatsDChannel is a Live channel aggregated StatsD metrics are pushed to.
+	LiveStatsDChannel string
+	// LiveStatsDFlushInterval is how often aggregated StatsD metrics are pushed.
+	LiveStatsDFlushInterval time.Duration
+	// LiveListenerOrgID is an organization whose channel rules process metrics
+	// received by Graphite and StatsD listeners.
+	LiveListenerOrgID int64
 
 	// Grafana.com URL, used for OAuth redirect.
 	GrafanaComURL string
@@ -595,6 +613,12 @@ type Cfg struct {
 	SprinklesApiServerPageLimit                int
 	CACertPath                                 string
 	HttpsSkipVerify                            bool
+	HistoryCompactionInterval                  time.Duration
+	HistoryCompactionDryRun                    bool
+	AuditSinks                                 []string
+	AuditFilePath                              string
+	AuditSyslogNetwork                         string
+	AuditSyslogAddress                         string
 
 	// Secrets Management
 	SecretsManagement SecretsManagerSettings
@@ -608,6 +632,20 @@ type UnifiedStorageConfig struct {
 	DataSyncerInterval time.Duration
 	// DataSyncerRecordsLimit defines how many records will be processed at max during a sync invocation.
 	DataSyncerRecordsLimit int
+	// HistoryKeepVersions keeps the most recent versions of each resource.
+	HistoryKeepVersions int
+	// HistoryKeepNewerThan keeps every version newer than this.
+	HistoryKeepNewerThan time.Duration
+	// HistoryThinToDaily keeps one version per day for versions not kept by the rules above.
+	HistoryThinToDaily bool
+	// TrashPurgeAfter removes deleted resources once they have been in the trash this long.
+	TrashPurgeAfter time.Duration
+	// BlobGCGracePeriod removes blobs no longer referenced by any version once they are this old.
+	BlobGCGracePeriod time.Duration
+	// QuotaMaxObjects limits the number of objects in each namespace (zero is unlimited).
+	QuotaMaxObjects int64
+	// QuotaMaxBytes limits the total size of the objects in each namespace (zero is unlimited).
+	QuotaMaxBytes int64
 }
 
 type InstallPlugin struct {
@@ -2084,6 +2122,20 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 	}
 
 	cfg.LiveAllowedOrigins = originPatterns
+
+	cfg.LivePipelineEnabled = section.Key("pipeline_enabled").MustBool(false)
+	cfg.LiveGraphiteListenAddress = section.Key("graphite_listen_address").MustString("")
+	cfg.LiveGraphiteChannel = section.Key("graphite_channel").MustString("stream/graphite/metrics")
+	cfg.LiveStatsDListenAddress = section.Key("statsd_listen_address").MustString("")
+	cfg.LiveStatsDChannel = section.Key("statsd_channel").MustString("stream/statsd/metrics")
+	cfg.LiveStatsDFlushInterval = section.Key("statsd_flush_interval").MustDuration(10 * time.Second)
+	if cfg.LiveStatsDFlushInterval <= 0 {
+		return fmt.Errorf("unexpected value %s for [live] statsd_flush_interval", cfg.LiveStatsDFlushInterval)
+	}
+	cfg.LiveListenerOrgID = section.Key("listener_org_id").MustInt64(1)
+	if !cfg.LivePipelineEnabled && (cfg.LiveGraphiteListenAddress != "" || cfg.LiveStatsDListenAddress != "") {
+		return errors.New("[live] graphite_listen_address and statsd_listen_address require [live] pipeline_enabled")
+	}
 	return nil
 }
 
@@ -2103,6 +2155,18 @@ func (cfg *Cfg) readProvisioningSettings(iniFile *ini.File) error {
 		}
 	}
 
+	bucketEndpoints := strings.TrimSpace(valueAsString(iniFile.Section("paths"), "permitted_bucket_endpoints", ""))
+	if bucketEndpoints != "" {
+		cfg.PermittedBucketEndpoints = strings.Split(bucketEndpoints, "|")
+		for i, s := range cfg.PermittedBucketEndpoints {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				return fmt.Errorf("a bucket endpoint is empty in '%s' (at index %d)", bucketEndpoints, i)
+			}
+			cfg.PermittedBucketEndpoints[i] = s
+		}
+	}
+
 	// Read job history configuration
 	cfg.ProvisioningLokiURL = valueAsString(iniFile.Section("provisioning"), "loki_url", "")
 	cfg.ProvisioningLokiUser = valueAsString(iniFile.Section("provisioning"), "loki_user", "")
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/apiserver/rest"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 // read storage configs from ini file. They look like:
@@ -45,6 +46,17 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 			DualWriterMigrationDataSyncDisabled:  dualWriterMigrationDataSyncDisabled,
 			DataSyncerRecordsLimit:               dataSyncerRecordsLimit,
 			DataSyncerInterval:                   dataSyncerInterval,
+
+			// history retention rules
+			HistoryKeepVersions:  section.Key("historyKeepVersions").MustInt(0),
+			HistoryKeepNewerThan: section.Key("historyKeepNewerThan").MustDuration(0),
+			HistoryThinToDaily:   section.Key("historyThinToDaily").MustBool(false),
+			TrashPurgeAfter:      section.Key("trashPurgeAfter").MustDuration(0),
+			BlobGCGracePeriod:    section.Key("blobGCGracePeriod").MustDuration(0),
+
+			// default quota limits for every namespace
+			QuotaMaxObjects: section.Key("quotaMaxObjects").MustInt64(0),
+			QuotaMaxBytes:   section.Key("quotaMaxBytes").MustInt64(0),
 		}
 	}
 	cfg.UnifiedStorage = storageConfig
@@ -77,4 +89,12 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 	cfg.SprinklesApiServerPageLimit = section.Key("sprinkles_api_server_page_limit").MustInt(10000)
 	cfg.CACertPath = section.Key("ca_cert_path").String()
 	cfg.HttpsSkipVerify = section.Key("https_skip_verify").MustBool(false)
+	cfg.HistoryCompactionInterval = section.Key("history_compaction_interval").MustDuration(time.Hour)
+	cfg.HistoryCompactionDryRun = section.Key("history_compaction_dry_run").MustBool(false)
+
+	// Audit trail sinks: sql, file and/or syslog
+	cfg.AuditSinks = util.SplitString(section.Key("audit_sinks").String())
+	cfg.AuditFilePath = section.Key("audit_file_path").String()
+	cfg.AuditSyslogNetwork = section.Key("audit_syslog_network").MustString("udp")
+	cfg.AuditSyslogAddress = section.Key("audit_syslog_address").String()
 }
@@ -28,6 +28,18 @@ func TestCfg_setUnifiedStorageConfig(t *testing.T) {
 		_, err = s.NewKey("dataSyncerInterval", "10m")
 		assert.NoError(t, err)
 
+		_, err = s.NewKey("historyKeepVersions", "10")
+		assert.NoError(t, err)
+
+		_, err = s.NewKey("trashPurgeAfter", "720h")
+		assert.NoError(t, err)
+
+		_, err = s.NewKey("blobGCGracePeriod", "2h")
+		assert.NoError(t, err)
+
+		_, err = s.NewKey("quotaMaxObjects", "500")
+		assert.NoError(t, err)
+
 		// Add unified_storage section for index settings
 		unifiedStorageSection, err := cfg.Raw.NewSection("unified_storage")
 		assert.NoError(t, err)
@@ -48,6 +60,10 @@ func TestCfg_setUnifiedStorageConfig(t *testing.T) {
 			DualWriterPeriodicDataSyncJobEnabled: true,
 			DataSyncerRecordsLimit:               1001,
 			DataSyncerInterval:                   time.Minute * 10,
+			HistoryKeepVersions:                  10,
+			TrashPurgeAfter:                      time.Hour * 720,
+			BlobGCGracePeriod:                    time.Hour * 2,
+			QuotaMaxObjects:                      500,
 		})
 
 		// Test that index settings are correctly parsed
@@ -66,5 +82,9 @@ func TestCfg_setUnifiedStorageConfig(t *testing.T) {
 		// Test that default index settings are applied
 		assert.Equal(t, 1, cfg.IndexMinCount)
 		assert.Equal(t, 0, cfg.IndexMaxCount)
+		assert.Equal(t, time.Hour, cfg.HistoryCompactionInterval)
+		assert.False(t, cfg.HistoryCompactionDryRun)
+		assert.Empty(t, cfg.AuditSinks)
+		assert.Equal(t, "udp", cfg.AuditSyslogNetwork)
 	})
 }
@@ -131,6 +131,46 @@ func TestGet(t *testing.T) {
 	storagetesting.RunTestGet(ctx, t, store)
 }
 
+// The field selectors are sent as fields, the list must still return the same items
+func TestGetListWithSelectors(t *testing.T) {
+	ctx, store, destroyFunc, err := testSetup(t)
+	defer destroyFunc()
+	require.NoError(t, err)
+
+	for _, pod := range []*example.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns", Labels: map[string]string{"app": "grafana"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns", Labels: map[string]string{"app": "grafana"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "ns", Labels: map[string]string{"app": "other"}}},
+	} {
+		err := store.Create(ctx, storagetesting.KeyFunc(pod.Namespace, pod.Name), pod, &example.Pod{}, 0)
+		require.NoError(t, err)
+	}
+
+	list := func(label labels.Selector, field fields.Selector) []string {
+		out := &example.PodList{}
+		err := store.GetList(ctx, storagetesting.KeyFunc("ns", ""), storage.ListOptions{
+			Recursive: true,
+			Predicate: storage.SelectionPredicate{
+				Label:    label,
+				Field:    field,
+				GetAttrs: GetPodAttrs,
+			},
+		}, out)
+		require.NoError(t, err)
+		names := []string{}
+		for _, item := range out.Items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"a", "b", "c"}, list(labels.Everything(), fields.Everything()))
+	require.Equal(t, []string{"a", "b"}, list(labels.SelectorFromSet(labels.Set{"app": "grafana"}), fields.Everything()))
+	require.Equal(t, []string{"b"}, list(labels.Everything(), fields.OneTermEqualSelector("metadata.name", "b")))
+	require.Equal(t, []string{"a"}, list(labels.SelectorFromSet(labels.Set{"app": "grafana"}), fields.OneTermNotEqualSelector("metadata.name", "b")))
+	require.Empty(t, list(labels.SelectorFromSet(labels.Set{"app": "other"}), fields.OneTermEqualSelector("metadata.name", "a")))
+}
+
 func TestUnconditionalDelete(t *testing.T) {
 	ctx, store, destroyFunc, err := testSetup(t)
 	defer destroyFunc()
@@ -166,6 +206,9 @@ type resourceClientMock struct {
 	resourcepb.BulkStoreClient
 	resourcepb.BlobStoreClient
 	resourcepb.DiagnosticsClient
+	resourcepb.AuditLogClient
+	resourcepb.ResourceQuotasClient
+	resourcepb.BackupStoreClient
 }
 
 // always return GRPC Unauthenticated code
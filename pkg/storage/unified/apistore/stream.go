@@ -9,12 +9,15 @@ import (
 
 	grpcCodes "google.golang.org/grpc/codes"
 	grpcStatus "google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/klog/v2"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
 )
 
@@ -25,15 +28,19 @@ type streamDecoder struct {
 	codec       runtime.Codec
 	cancelWatch context.CancelFunc
 	done        sync.WaitGroup
+
+	// true until the bookmark marking the end of the initial events is received
+	waitInitialEventsEnd bool
 }
 
-func newStreamDecoder(client resourcepb.ResourceStore_WatchClient, newFunc func() runtime.Object, predicate storage.SelectionPredicate, codec runtime.Codec, cancelWatch context.CancelFunc) *streamDecoder {
+func newStreamDecoder(client resourcepb.ResourceStore_WatchClient, newFunc func() runtime.Object, predicate storage.SelectionPredicate, codec runtime.Codec, cancelWatch context.CancelFunc, sendInitialEvents bool) *streamDecoder {
 	return &streamDecoder{
-		client:      client,
-		newFunc:     newFunc,
-		predicate:   predicate,
-		codec:       codec,
-		cancelWatch: cancelWatch,
+		client:               client,
+		newFunc:              newFunc,
+		predicate:            predicate,
+		codec:                codec,
+		cancelWatch:          cancelWatch,
+		waitInitialEventsEnd: sendInitialEvents,
 	}
 }
 func (d *streamDecoder) toObject(w *resourcepb.WatchEvent_Resource) (runtime.Object, error) {
@@ -80,6 +87,13 @@ decode:
 
 		// Error event
 		if evt.Type == resourcepb.WatchEvent_ERROR {
+			if evt.Error != nil {
+				// k8s expects the status of the error, eg: 410 when the resource version is too old
+				var status *apierrors.StatusError
+				if errors.As(resource.GetError(evt.Error), &status) {
+					return watch.Error, &status.ErrStatus, nil
+				}
+			}
 			err = fmt.Errorf("stream error")
 			klog.Errorf("client: error receiving result: %s", err)
 			return watch.Error, nil, err
@@ -93,7 +107,9 @@ decode:
 		if evt.Type == resourcepb.WatchEvent_BOOKMARK {
 			obj := d.newFunc()
 
-			// here k8s expects an empty object with just resource version and k8s.io/initial-events-end annotation
+			// here k8s expects an empty object with just resource version, and the k8s.io/initial-events-end
+			// annotation for the bookmark sent after the initial events. Later bookmarks are periodic progress
+			// notifications.
 			accessor, err := utils.MetaAccessor(obj)
 			if err != nil {
 				klog.Errorf("error getting object accessor: %s", err)
@@ -101,7 +117,10 @@ decode:
 			}
 
 			accessor.SetResourceVersionInt64(evt.Resource.Version)
-			accessor.SetAnnotations(map[string]string{"k8s.io/initial-events-end": "true"})
+			if d.waitInitialEventsEnd {
+				accessor.SetAnnotations(map[string]string{metav1.InitialEventsAnnotationKey: "true"})
+				d.waitInitialEventsEnd = false
+			}
 			return watch.Bookmark, obj, nil
 		}
 
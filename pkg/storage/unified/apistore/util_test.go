@@ -202,6 +202,41 @@ func TestToListRequest(t *testing.T) {
 			wantPredicate: storage.Everything,
 			wantErr:       nil,
 		},
+		{
+			name: "with label and field selectors",
+			key: &resourcepb.ResourceKey{
+				Group:     "test",
+				Resource:  "test",
+				Namespace: "default",
+			},
+			opts: storage.ListOptions{
+				Predicate: storage.SelectionPredicate{
+					Label: labels.SelectorFromSet(labels.Set{"app": "grafana"}),
+					Field: fields.SelectorFromSet(fields.Set{"metadata.name": "test-name"}),
+				},
+			},
+			want: &resourcepb.ListRequest{
+				VersionMatchV2: 1,
+				Options: &resourcepb.ListOptions{
+					Labels: []*resourcepb.Requirement{
+						{Key: "app", Operator: "=", Values: []string{"grafana"}},
+					},
+					Fields: []*resourcepb.Requirement{
+						{Key: "metadata.name", Operator: "=", Values: []string{"test-name"}},
+					},
+					Key: &resourcepb.ResourceKey{
+						Group:     "test",
+						Resource:  "test",
+						Namespace: "default",
+					},
+				},
+			},
+			wantPredicate: storage.SelectionPredicate{
+				Label: labels.SelectorFromSet(labels.Set{"app": "grafana"}),
+				Field: fields.SelectorFromSet(fields.Set{"metadata.name": "test-name"}),
+			},
+			wantErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -28,6 +28,7 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/storage/legacysql"
 	"github.com/grafana/grafana/pkg/storage/unified/federated"
+	"github.com/grafana/grafana/pkg/storage/unified/parquet"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 	"github.com/grafana/grafana/pkg/storage/unified/search"
 	"github.com/grafana/grafana/pkg/storage/unified/sql"
@@ -113,6 +114,7 @@ func newClient(opts options.StorageOptions,
 			Blob: resource.BlobConfig{
 				URL: opts.BlobStoreURL,
 			},
+			Backup: parquet.WriteBackup,
 		})
 		if err != nil {
 			return nil, err
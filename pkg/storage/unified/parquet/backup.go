@@ -0,0 +1,315 @@
+package parquet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// ManifestKey is the parquet key/value metadata entry holding the backup manifest
+const ManifestKey = "grafana.unified.backup"
+
+// Number of items requested from the backend at once
+var backupPageSize int64 = 500
+
+// Bump this when the archive layout changes in a way old readers can not handle
+const manifestVersion = 1
+
+// BackupManifest describes the contents of a backup archive
+type BackupManifest struct {
+	Version   int       `json:"version"`
+	Namespace string    `json:"namespace"`
+	Created   time.Time `json:"created"`
+
+	// When true, every version of each resource is included (oldest first)
+	// otherwise only the latest value
+	History bool `json:"history,omitempty"`
+
+	Resources []BackupResource `json:"resources"`
+}
+
+// BackupResource summarizes one group/resource in the archive
+type BackupResource struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+
+	// Number of distinct objects
+	Objects int64 `json:"objects"`
+
+	// Number of deleted objects whose history is included
+	Deleted int64 `json:"deleted,omitempty"`
+
+	// Number of rows written, this is larger than objects when history is included
+	Rows int64 `json:"rows"`
+
+	// The latest resource version seen while reading the backend
+	ResourceVersion int64 `json:"resourceVersion,omitempty"`
+}
+
+type BackupOptions struct {
+	Namespace string
+
+	// Limit the backup to these resources, when empty everything in the namespace is included
+	Resources []schema.GroupResource
+
+	// Include the full history for each resource, including the deleted ones
+	WithHistory bool
+
+	// Called periodically with the number of rows written so far
+	Progress func(count int, msg string)
+}
+
+// Backup streams every resource in a namespace from the backend into a parquet archive.
+// The output is closed when finished if it implements io.Closer.
+func Backup(ctx context.Context, backend resource.StorageBackend, out io.Writer, opts BackupOptions) (*BackupManifest, error) {
+	if opts.Namespace == "" {
+		return nil, fmt.Errorf("missing namespace")
+	}
+	if opts.Progress == nil {
+		opts.Progress = func(count int, msg string) {}
+	}
+
+	resources := opts.Resources
+	if len(resources) == 0 {
+		stats, err := backend.GetResourceStats(ctx, opts.Namespace, 0)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list resources: %w", err)
+		}
+		for _, s := range stats {
+			resources = append(resources, schema.GroupResource{Group: s.Group, Resource: s.Resource})
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group == resources[j].Group {
+			return resources[i].Resource < resources[j].Resource
+		}
+		return resources[i].Group < resources[j].Group
+	})
+
+	writer, err := NewParquetWriter(out)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &BackupManifest{
+		Version:   manifestVersion,
+		Namespace: opts.Namespace,
+		Created:   time.Now().UTC(),
+		History:   opts.WithHistory,
+		Resources: make([]BackupResource, 0, len(resources)),
+	}
+
+	count := 0
+	write := func(key *resourcepb.ResourceKey, value []byte) error {
+		err := writer.Write(ctx, key, value)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", resource.SearchID(key), err)
+		}
+		count++
+		if count%1000 == 0 {
+			opts.Progress(count, resource.NSGR(key))
+		}
+		return nil
+	}
+
+	for _, gr := range resources {
+		info, err := backupResource(ctx, backend, opts.Namespace, gr, opts.WithHistory, write)
+		if err != nil {
+			_ = writer.Close()
+			return nil, err
+		}
+		manifest.Resources = append(manifest.Resources, info)
+		opts.Progress(count, fmt.Sprintf("%s (%d objects)", gr.String(), info.Objects))
+	}
+
+	jj, err := json.Marshal(manifest)
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	if err = writer.AppendKeyValueMetadata(ManifestKey, string(jj)); err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	return manifest, writer.Close()
+}
+
+var _ resource.BackupWriter = WriteBackup
+
+// WriteBackup writes the archive returned by the backup API
+func WriteBackup(ctx context.Context, backend resource.StorageBackend, req *resourcepb.BackupRequest, out io.Writer) error {
+	opts := BackupOptions{
+		Namespace:   req.Namespace,
+		WithHistory: req.WithHistory,
+	}
+	for _, key := range req.Resources {
+		opts.Resources = append(opts.Resources, schema.GroupResource{Group: key.Group, Resource: key.Resource})
+	}
+	_, err := Backup(ctx, backend, out, opts)
+	return err
+}
+
+// DownloadBackup writes the archive returned by the backup API to out
+func DownloadBackup(ctx context.Context, client resourcepb.BackupStoreClient, req *resourcepb.BackupRequest, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Backup(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		rsp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if rsp.Error != nil {
+			return resource.GetError(rsp.Error)
+		}
+		if _, err = out.Write(rsp.Chunk); err != nil {
+			return err
+		}
+	}
+}
+
+func backupResource(ctx context.Context, backend resource.StorageBackend, namespace string, gr schema.GroupResource, withHistory bool,
+	write func(key *resourcepb.ResourceKey, value []byte) error) (BackupResource, error) {
+	info := BackupResource{Group: gr.Group, Resource: gr.Resource}
+	key := &resourcepb.ResourceKey{
+		Namespace: namespace,
+		Group:     gr.Group,
+		Resource:  gr.Resource,
+	}
+
+	// Names are collected first so the history is read outside the list transaction
+	var names []string
+	rv, err := listAll(ctx, backend.ListIterator, &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{Key: key},
+	}, func(iter resource.ListIterator) error {
+		info.Objects++
+		if withHistory {
+			names = append(names, iter.Name())
+			return nil
+		}
+		info.Rows++
+		return write(&resourcepb.ResourceKey{
+			Namespace: namespace,
+			Group:     gr.Group,
+			Resource:  gr.Resource,
+			Name:      iter.Name(),
+		}, iter.Value())
+	})
+	if err != nil {
+		return info, fmt.Errorf("error listing %s: %w", gr.String(), err)
+	}
+	info.ResourceVersion = rv
+
+	if withHistory {
+		// The deleted objects are not listed, but their history is kept
+		_, err = listAll(ctx, backend.ListHistory, &resourcepb.ListRequest{
+			Source:  resourcepb.ListRequest_TRASH,
+			Options: &resourcepb.ListOptions{Key: key},
+		}, func(iter resource.ListIterator) error {
+			info.Deleted++
+			names = append(names, iter.Name())
+			return nil
+		})
+		if err != nil {
+			return info, fmt.Errorf("error listing deleted %s: %w", gr.String(), err)
+		}
+	}
+
+	for _, name := range names {
+		k := &resourcepb.ResourceKey{
+			Namespace: namespace,
+			Group:     gr.Group,
+			Resource:  gr.Resource,
+			Name:      name,
+		}
+		_, err = listAll(ctx, backend.ListHistory, &resourcepb.ListRequest{
+			Source: resourcepb.ListRequest_HISTORY,
+			// Oldest first, and including versions before a delete
+			VersionMatchV2:  resourcepb.ResourceVersionMatchV2_NotOlderThan,
+			ResourceVersion: 1,
+			Options:         &resourcepb.ListOptions{Key: k},
+		}, func(iter resource.ListIterator) error {
+			info.Rows++
+			return write(k, iter.Value())
+		})
+		if err != nil {
+			return info, fmt.Errorf("error reading history for %s: %w", resource.SearchID(k), err)
+		}
+	}
+	return info, nil
+}
+
+type listFunc = func(context.Context, *resourcepb.ListRequest, func(resource.ListIterator) error) (int64, error)
+
+// listAll reads every page of a list request. Like the resource server, it stops each
+// page at the limit since not every backend applies it, then continues from the last item
+func listAll(ctx context.Context, list listFunc, req *resourcepb.ListRequest, fn func(resource.ListIterator) error) (int64, error) {
+	req.Limit = backupPageSize
+	var listRV int64
+	for {
+		var count int64
+		var token string
+		rv, err := list(ctx, req, func(iter resource.ListIterator) error {
+			for count < req.Limit && iter.Next() {
+				if err := iter.Error(); err != nil {
+					return err
+				}
+				count++
+				token = iter.ContinueToken()
+				if err := fn(iter); err != nil {
+					return err
+				}
+			}
+			return iter.Error()
+		})
+		if err != nil {
+			return 0, err
+		}
+		if listRV == 0 {
+			listRV = rv
+		}
+		if count < req.Limit || token == "" {
+			return listRV, nil
+		}
+		req.NextPageToken = token
+	}
+}
+
+// ReadBackupManifest reads the manifest from a backup archive
+func ReadBackupManifest(inputPath string) (*BackupManifest, error) {
+	rdr, err := file.OpenParquetFile(inputPath, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rdr.Close() }()
+
+	v := rdr.MetaData().KeyValueMetadata().FindValue(ManifestKey)
+	if v == nil {
+		return nil, fmt.Errorf("missing backup manifest (%s)", inputPath)
+	}
+	manifest := &BackupManifest{}
+	if err = json.Unmarshal([]byte(*v), manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if manifest.Version > manifestVersion {
+		return nil, fmt.Errorf("unsupported backup version: %d", manifest.Version)
+	}
+	return manifest, nil
+}
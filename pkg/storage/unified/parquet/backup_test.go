@@ -0,0 +1,214 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+type collectingWriter struct {
+	keys   []string
+	values []*unstructured.Unstructured
+}
+
+func (c *collectingWriter) Write(_ context.Context, key *resourcepb.ResourceKey, value []byte) error {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(value); err != nil {
+		return err
+	}
+	c.keys = append(c.keys, resource.SearchID(key))
+	c.values = append(c.values, obj)
+	return nil
+}
+
+func (c *collectingWriter) Close() error {
+	return nil
+}
+
+func (c *collectingWriter) CloseWithResults() (*resourcepb.BulkResponse, error) {
+	return &resourcepb.BulkResponse{Processed: int64(len(c.keys))}, nil
+}
+
+func newTestBackend(t *testing.T) resource.StorageBackend {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return resource.NewKvStorageBackend(resource.NewBadgerKV(db))
+}
+
+func writeTestObject(t *testing.T, backend resource.StorageBackend, action resourcepb.WatchEvent_Type, group, res, name, title string) {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": group + "/v1",
+		"kind":       res,
+		"metadata": map[string]any{
+			"namespace": "default",
+			"name":      name,
+		},
+		"spec": map[string]any{
+			"title": title,
+		},
+	}}
+	meta, err := utils.MetaAccessor(obj)
+	require.NoError(t, err)
+	if action == resourcepb.WatchEvent_DELETED {
+		meta.SetGeneration(utils.DeletedGeneration)
+	}
+	value, err := obj.MarshalJSON()
+	require.NoError(t, err)
+	_, err = backend.WriteEvent(context.Background(), resource.WriteEvent{
+		Type: action,
+		Key: &resourcepb.ResourceKey{
+			Namespace: "default",
+			Group:     group,
+			Resource:  res,
+			Name:      name,
+		},
+		Value:     value,
+		Object:    meta,
+		ObjectOld: meta,
+	})
+	require.NoError(t, err)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	backend := newTestBackend(t)
+
+	// read the backend in multiple pages
+	pageSize := backupPageSize
+	backupPageSize = 40
+	t.Cleanup(func() { backupPageSize = pageSize })
+
+	// more than the restore batch size
+	for i := 0; i < 150; i++ {
+		writeTestObject(t, backend, resourcepb.WatchEvent_ADDED, "dashboard.grafana.app", "dashboards", fmt.Sprintf("dash-%03d", i), "v1")
+	}
+	writeTestObject(t, backend, resourcepb.WatchEvent_MODIFIED, "dashboard.grafana.app", "dashboards", "dash-000", "v2")
+	writeTestObject(t, backend, resourcepb.WatchEvent_ADDED, "folder.grafana.app", "folders", "folder-a", "v1")
+	writeTestObject(t, backend, resourcepb.WatchEvent_ADDED, "dashboard.grafana.app", "dashboards", "deleted", "v1")
+	writeTestObject(t, backend, resourcepb.WatchEvent_DELETED, "dashboard.grafana.app", "dashboards", "deleted", "v1")
+
+	t.Run("latest", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "backup.parquet")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		manifest, err := Backup(ctx, backend, f, BackupOptions{Namespace: "default"})
+		require.NoError(t, err)
+
+		require.Equal(t, []BackupResource{
+			{Group: "dashboard.grafana.app", Resource: "dashboards", Objects: 150, Rows: 150, ResourceVersion: manifest.Resources[0].ResourceVersion},
+			{Group: "folder.grafana.app", Resource: "folders", Objects: 1, Rows: 1, ResourceVersion: manifest.Resources[1].ResourceVersion},
+		}, manifest.Resources)
+
+		read, err := ReadBackupManifest(path)
+		require.NoError(t, err)
+		require.Equal(t, manifest.Resources, read.Resources)
+		require.Equal(t, "default", read.Namespace)
+
+		collector := &collectingWriter{}
+		client := NewBulkResourceWriterClient(collector)
+		rsp, err := Restore(ctx, client, path, RestoreOptions{Namespace: "org-2"})
+		require.NoError(t, err)
+		require.Equal(t, int64(151), rsp.Processed)
+
+		require.Equal(t, "org-2/dashboard.grafana.app/dashboards/dash-000", collector.keys[0])
+		require.Equal(t, "org-2/folder.grafana.app/folders/folder-a", collector.keys[150])
+		require.Equal(t, "org-2", collector.values[0].GetNamespace())
+		title, _, _ := unstructured.NestedString(collector.values[0].Object, "spec", "title")
+		require.Equal(t, "v2", title)
+
+		md, ok := metadata.FromOutgoingContext(client.Context())
+		require.True(t, ok)
+		settings, err := resource.NewBulkSettings(md)
+		require.NoError(t, err)
+		require.True(t, settings.RebuildCollection)
+		require.Len(t, settings.Collection, 2)
+		require.Equal(t, "org-2", settings.Collection[0].Namespace)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "backup.parquet")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		manifest, err := Backup(ctx, backend, f, BackupOptions{
+			Namespace:   "default",
+			Resources:   []schema.GroupResource{{Group: "dashboard.grafana.app", Resource: "dashboards"}},
+			WithHistory: true,
+		})
+		require.NoError(t, err)
+		require.True(t, manifest.History)
+		require.Len(t, manifest.Resources, 1)
+		require.Equal(t, int64(150), manifest.Resources[0].Objects)
+		require.Equal(t, int64(1), manifest.Resources[0].Deleted)
+		require.Equal(t, int64(153), manifest.Resources[0].Rows)
+
+		collector := &collectingWriter{}
+		_, err = Restore(ctx, NewBulkResourceWriterClient(collector), path, RestoreOptions{})
+		require.NoError(t, err)
+		require.Len(t, collector.keys, 153)
+
+		// oldest version first
+		require.Equal(t, "default/dashboard.grafana.app/dashboards/dash-000", collector.keys[0])
+		require.Equal(t, "default/dashboard.grafana.app/dashboards/dash-000", collector.keys[1])
+		title, _, _ := unstructured.NestedString(collector.values[0].Object, "spec", "title")
+		require.Equal(t, "v1", title)
+		title, _, _ = unstructured.NestedString(collector.values[1].Object, "spec", "title")
+		require.Equal(t, "v2", title)
+
+		// the history of deleted objects ends with the delete
+		require.Equal(t, "default/dashboard.grafana.app/dashboards/deleted", collector.keys[151])
+		require.Equal(t, "default/dashboard.grafana.app/dashboards/deleted", collector.keys[152])
+		require.Equal(t, utils.DeletedGeneration, collector.values[152].GetGeneration())
+	})
+
+	t.Run("api", func(t *testing.T) {
+		server, err := resource.NewResourceServer(resource.ResourceServerOptions{
+			Backend: backend,
+			Backup:  WriteBackup,
+		})
+		require.NoError(t, err)
+		client := resource.NewLocalResourceClient(server)
+
+		path := filepath.Join(t.TempDir(), "backup.parquet")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		err = DownloadBackup(identity.WithServiceIdentityContext(ctx, 1), client, &resourcepb.BackupRequest{
+			Namespace:   "default",
+			Resources:   []*resourcepb.ResourceKey{{Group: "dashboard.grafana.app", Resource: "dashboards"}},
+			WithHistory: true,
+		}, f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		manifest, err := ReadBackupManifest(path)
+		require.NoError(t, err)
+		require.True(t, manifest.History)
+		require.Len(t, manifest.Resources, 1)
+		require.Equal(t, int64(153), manifest.Resources[0].Rows)
+	})
+
+	t.Run("missing manifest", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "plain.parquet")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		writer, err := NewParquetWriter(f)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		_, err = ReadBackupManifest(path)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,101 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+type RestoreOptions struct {
+	// Restore into this namespace, defaults to the namespace in the backup manifest
+	Namespace string
+
+	// Number of rows read from the archive at once
+	BatchSize int64
+
+	// Called periodically with the number of rows sent so far
+	Progress func(count int, msg string)
+}
+
+// Restore sends the contents of a backup archive to the BulkProcess endpoint.
+// Every collection in the manifest is replaced in the target namespace.
+func Restore(ctx context.Context, client resourcepb.BulkStoreClient, inputPath string, opts RestoreOptions) (*resourcepb.BulkResponse, error) {
+	manifest, err := ReadBackupManifest(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = manifest.Namespace
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 100
+	}
+	if opts.Progress == nil {
+		opts.Progress = func(count int, msg string) {}
+	}
+
+	settings := resource.BulkSettings{
+		RebuildCollection: true,
+	}
+	for _, r := range manifest.Resources {
+		settings.Collection = append(settings.Collection, &resourcepb.ResourceKey{
+			Namespace: opts.Namespace,
+			Group:     r.Group,
+			Resource:  r.Resource,
+		})
+	}
+	if len(settings.Collection) == 0 {
+		return &resourcepb.BulkResponse{}, nil
+	}
+
+	iter, err := newResourceReader(inputPath, opts.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := client.BulkProcess(metadata.NewOutgoingContext(ctx, settings.ToMD()))
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+	for iter.Next() {
+		req := iter.Request()
+		if req.Key.Namespace != opts.Namespace {
+			req.Value, err = setNamespace(req.Value, opts.Namespace)
+			if err != nil {
+				_ = stream.CloseSend()
+				return nil, fmt.Errorf("error reading %s: %w", resource.SearchID(req.Key), err)
+			}
+			req.Key.Namespace = opts.Namespace
+		}
+		if err = stream.Send(req); err != nil {
+			_ = stream.CloseSend()
+			return nil, err
+		}
+		count++
+		if count%1000 == 0 {
+			opts.Progress(count, resource.NSGR(req.Key))
+		}
+	}
+	if iter.err != nil {
+		_ = stream.CloseSend()
+		return nil, fmt.Errorf("error reading backup: %w", iter.err)
+	}
+	opts.Progress(count, "done")
+	return stream.CloseAndRecv()
+}
+
+func setNamespace(value []byte, namespace string) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(value); err != nil {
+		return nil, err
+	}
+	obj.SetNamespace(namespace)
+	return obj.MarshalJSON()
+}
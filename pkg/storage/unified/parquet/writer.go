@@ -114,11 +114,12 @@ func (w *parquetWriter) Close() error {
 // writes the current buffer to parquet and re-inits the arrow buffer
 func (w *parquetWriter) flush() error {
 	w.logger.Info("flush", "count", w.rv.Len())
+	// columns must be in the same order as the schema
 	rec := array.NewRecord(w.schema, []arrow.Array{
 		w.rv.NewArray(),
-		w.namespace.NewArray(),
 		w.group.NewArray(),
 		w.resource.NewArray(),
+		w.namespace.NewArray(),
 		w.name.NewArray(),
 		w.folder.NewArray(),
 		w.action.NewArray(),
@@ -177,12 +178,6 @@ func (w *parquetWriter) Write(ctx context.Context, key *resourcepb.ResourceKey,
 	}
 	w.action.Append(int8(action))
 
-	w.wrote = w.wrote + len(value)
-	if w.wrote > w.buffer {
-		w.logger.Info("buffer full", "buffer", w.wrote, "max", w.buffer)
-		return w.flush()
-	}
-
 	summary := w.summary[resource.NSGR(key)]
 	if summary == nil {
 		summary = &resourcepb.BulkResponse_Summary{
@@ -194,9 +189,20 @@ func (w *parquetWriter) Write(ctx context.Context, key *resourcepb.ResourceKey,
 		w.rsp.Summary = append(w.rsp.Summary, summary)
 	}
 	summary.Count++
+
+	w.wrote = w.wrote + len(value)
+	if w.wrote > w.buffer {
+		w.logger.Info("buffer full", "buffer", w.wrote, "max", w.buffer)
+		return w.flush()
+	}
 	return nil
 }
 
+// AppendKeyValueMetadata adds a key/value pair to the parquet file footer
+func (w *parquetWriter) AppendKeyValueMetadata(key string, value string) error {
+	return w.writer.AppendKeyValueMetadata(key, value)
+}
+
 func newSchema(metadata *arrow.Metadata) *arrow.Schema {
 	return arrow.NewSchema([]arrow.Field{
 		{Name: "resource_version", Type: &arrow.Int64Type{}, Nullable: false},
@@ -0,0 +1,433 @@
+package resource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattbaird/jsonpatch"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	// AuditOriginUI is used for changes made by users without a manager
+	AuditOriginUI = "ui"
+	// AuditOriginAPI is used for changes made by service accounts, API keys and services without a manager
+	AuditOriginAPI = "api"
+
+	// The maximum number of events returned by a single audit query
+	maxAuditQueryLimit = 1000
+
+	// The maximum number of events read from the sink by a single audit query,
+	// a continue token is returned when the events read are not visible to the user
+	maxAuditQueryScan = 10 * maxAuditQueryLimit
+
+	// The number of times writing an event to a sink is attempted
+	auditWriteAttempts = 3
+)
+
+// AuditSink receives an event for every create, update and delete saved by the resource server
+type AuditSink interface {
+	WriteAuditEvent(ctx context.Context, event *resourcepb.AuditEvent) error
+}
+
+// TransactionalAuditSink is implemented by backends that save the WriteEvent.Audit event
+// in the same transaction as the change, so a change is never saved without its audit event.
+type TransactionalAuditSink interface {
+	AuditSink
+
+	// AuditsWrites is true when the audit events of the writes are saved with the change
+	AuditsWrites() bool
+}
+
+// AuditQuerier is implemented by sinks that can be queried
+type AuditQuerier interface {
+	// ListAuditEvents returns up to req.Limit events matching the request, newest first.
+	// When after is set, only the events older than that event are returned.
+	ListAuditEvents(ctx context.Context, req *resourcepb.AuditQueryRequest, after *AuditContinueToken) ([]*resourcepb.AuditEvent, error)
+}
+
+// AuditContinueToken identifies the last event returned by an audit query
+type AuditContinueToken struct {
+	Timestamp int64  `json:"t"`
+	GUID      string `json:"g"`
+}
+
+func (c AuditContinueToken) String() string {
+	b, _ := json.Marshal(c)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func GetAuditContinueToken(token string) (*AuditContinueToken, error) {
+	continueVal, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding continue token")
+	}
+
+	t := &AuditContinueToken{}
+	err = json.Unmarshal(continueVal, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// newAuditEvent describes a saved write event
+func newAuditEvent(user claims.AuthInfo, event *WriteEvent, rv int64, now int64) (*resourcepb.AuditEvent, error) {
+	patch, err := auditPatch(event)
+	if err != nil {
+		return nil, err
+	}
+	audit := &resourcepb.AuditEvent{
+		Guid:                    event.GUID,
+		Timestamp:               now,
+		Action:                  event.Type,
+		Key:                     event.Key,
+		Identity:                user.GetUID(),
+		PreviousResourceVersion: event.PreviousRV,
+		ResourceVersion:         rv,
+		Patch:                   patch,
+	}
+	if event.Object != nil {
+		audit.Folder = event.Object.GetFolder()
+	}
+	audit.Origin, audit.OriginIdentity = auditOrigin(user, event.Object)
+	return audit, nil
+}
+
+// auditPatch returns the JSON patch from the previous value to the saved value.
+// Deletes do not change the saved value (besides the deletion marker), so no patch is included.
+func auditPatch(event *WriteEvent) (string, error) {
+	if event.Type == resourcepb.WatchEvent_DELETED {
+		return "", nil
+	}
+
+	previous := []byte("{}")
+	if event.ObjectOld != nil {
+		if obj, ok := event.ObjectOld.GetRuntimeObject(); ok {
+			var err error
+			previous, err = json.Marshal(obj)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	ops, err := jsonpatch.CreatePatch(previous, event.Value)
+	if err != nil {
+		return "", fmt.Errorf("unable to create patch: %w", err)
+	}
+	if len(ops) == 0 {
+		return "[]", nil
+	}
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
+
+// auditOrigin returns where a change came from. Managed resources use the manager kind and identity,
+// otherwise changes made by users are attributed to the UI and everything else to the API.
+func auditOrigin(user claims.AuthInfo, obj utils.GrafanaMetaAccessor) (string, string) {
+	if obj != nil {
+		if m, ok := obj.GetManagerProperties(); ok && m.Kind != utils.ManagerKindUnknown {
+			return string(m.Kind), m.Identity
+		}
+	}
+	if user.GetIdentityType() == claims.TypeUser {
+		return AuditOriginUI, ""
+	}
+	return AuditOriginAPI, ""
+}
+
+// prepareAudit creates the audit event of a write before it is saved, so backends implementing
+// TransactionalAuditSink can save it with the change. Nothing is written when this fails.
+func (s *server) prepareAudit(user claims.AuthInfo, event *WriteEvent) *resourcepb.ErrorResult {
+	if len(s.auditSinks) == 0 {
+		return nil
+	}
+	audit, err := newAuditEvent(user, event, 0, s.now())
+	if err != nil {
+		return AsErrorResult(fmt.Errorf("failed to create audit event: %w", err))
+	}
+	event.Audit = audit
+	return nil
+}
+
+// recordAudit sends a saved write event to the audit sinks that are not written with the change.
+// The write is already committed, so the sinks are retried and errors are logged.
+func (s *server) recordAudit(ctx context.Context, event *WriteEvent, rv int64) {
+	if event.Audit == nil {
+		return
+	}
+	event.Audit.ResourceVersion = rv
+	for _, sink := range s.auditSinks {
+		if s.auditInTx != nil && sink == s.auditInTx {
+			continue
+		}
+		s.writeAudit(ctx, sink, event.Audit)
+	}
+}
+
+func (s *server) writeAudit(ctx context.Context, sink AuditSink, audit *resourcepb.AuditEvent) {
+	var err error
+	for attempt := 1; attempt <= auditWriteAttempts; attempt++ {
+		if err = sink.WriteAuditEvent(ctx, audit); err == nil {
+			return
+		}
+		if attempt < auditWriteAttempts {
+			select {
+			case <-ctx.Done():
+				attempt = auditWriteAttempts
+			case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+			}
+		}
+	}
+	s.log.Error("failed to write audit event", "key", audit.Key, "rv", audit.ResourceVersion, "error", err)
+}
+
+// recordBulkAudit sends an audit event for every resource imported by a bulk request to all the sinks.
+// The values are not included, they are saved in the history of the resources.
+func (s *server) recordBulkAudit(ctx context.Context, user claims.AuthInfo, requests []*resourcepb.BulkRequest, rsp *resourcepb.BulkResponse) {
+	if len(s.auditSinks) == 0 {
+		return
+	}
+	rvs := make(map[string]int64, len(rsp.Summary))
+	for _, sum := range rsp.Summary {
+		rvs[NSGR(&resourcepb.ResourceKey{Namespace: sum.Namespace, Group: sum.Group, Resource: sum.Resource})] = sum.ResourceVersion
+	}
+	rejected := make(map[string]bool, len(rsp.Rejected))
+	for _, r := range rsp.Rejected {
+		rejected[SearchID(r.Key)] = true
+	}
+	origin, originIdentity := auditOrigin(user, nil)
+	now := s.now()
+	for _, req := range requests {
+		if rejected[SearchID(req.Key)] {
+			continue
+		}
+		audit := &resourcepb.AuditEvent{
+			Guid:            uuid.New().String(),
+			Timestamp:       now,
+			Action:          resourcepb.WatchEvent_Type(req.Action),
+			Key:             req.Key,
+			Folder:          req.Folder,
+			Identity:        user.GetUID(),
+			ResourceVersion: rvs[NSGR(req.Key)],
+			Origin:          origin,
+			OriginIdentity:  originIdentity,
+		}
+		for _, sink := range s.auditSinks {
+			s.writeAudit(ctx, sink, audit)
+		}
+	}
+}
+
+func (s *server) QueryAudit(ctx context.Context, req *resourcepb.AuditQueryRequest) (*resourcepb.AuditQueryResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.QueryAudit")
+	defer span.End()
+
+	rsp := &resourcepb.AuditQueryResponse{}
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return rsp, nil
+	}
+	if s.auditQuerier == nil {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "no queryable audit sink configured",
+			Code:    http.StatusNotImplemented,
+		}
+		return rsp, nil
+	}
+	if req.Key == nil || req.Key.Namespace == "" {
+		rsp.Error = NewBadRequestError("audit query requires a namespace")
+		return rsp, nil
+	}
+	if req.Key.Resource != "" && req.Key.Group == "" {
+		rsp.Error = NewBadRequestError("audit query by resource requires a group")
+		return rsp, nil
+	}
+	if req.Limit <= 0 || req.Limit > maxAuditQueryLimit {
+		req.Limit = maxAuditQueryLimit
+	}
+	var after *AuditContinueToken
+	if req.NextPageToken != "" {
+		var err error
+		after, err = GetAuditContinueToken(req.NextPageToken)
+		if err != nil {
+			rsp.Error = NewBadRequestError("invalid continue token")
+			return rsp, nil
+		}
+	}
+
+	// Only return changes to resources the user can read. The events are read page by page
+	// until the limit is filled, or enough events were read to return a partial page
+	checkers := make(map[string]claims.ItemChecker)
+	for scanned := 0; ; {
+		events, err := s.auditQuerier.ListAuditEvents(ctx, req, after)
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+		for _, event := range events {
+			scanned++
+			after = &AuditContinueToken{Timestamp: event.Timestamp, GUID: event.Guid}
+
+			gr := event.Key.Group + "/" + event.Key.Resource
+			checker, ok := checkers[gr]
+			if !ok {
+				checker, err = s.access.Compile(ctx, user, claims.ListRequest{
+					Group:     event.Key.Group,
+					Resource:  event.Key.Resource,
+					Namespace: event.Key.Namespace,
+					Verb:      utils.VerbGet,
+				})
+				if err != nil {
+					rsp.Error = AsErrorResult(err)
+					return rsp, nil
+				}
+				checkers[gr] = checker
+			}
+			if checker != nil && checker(event.Key.Name, event.Folder) {
+				rsp.Events = append(rsp.Events, event)
+			}
+			if int64(len(rsp.Events)) >= req.Limit || scanned >= maxAuditQueryScan {
+				rsp.NextPageToken = after.String()
+				return rsp, nil
+			}
+		}
+		if int64(len(events)) < req.Limit {
+			return rsp, nil
+		}
+	}
+}
+
+// NewFileAuditSink appends every audit event to a file, one JSON object per line
+func NewFileAuditSink(path string) (AuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit file: %w", err)
+	}
+	return &writerAuditSink{w: f, format: func(event *resourcepb.AuditEvent) ([]byte, error) {
+		b, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}}, nil
+}
+
+// NewSyslogAuditSink sends every audit event to a syslog server using the RFC 5424 format.
+// Network is udp, tcp or unix; stream connections use octet counting framing (RFC 6587).
+// The connection is opened again when a write fails.
+func NewSyslogAuditSink(network, address string) (AuditSink, error) {
+	dial := func() (io.WriteCloser, error) {
+		conn, err := net.DialTimeout(network, address, syslogDialTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to syslog: %w", err)
+		}
+		return conn, nil
+	}
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	framed := network != "udp" && network != "udp4" && network != "udp6" && network != "unixgram"
+	return &writerAuditSink{w: conn, dial: dial, format: func(event *resourcepb.AuditEvent) ([]byte, error) {
+		msg, err := formatSyslogAuditEvent(hostname, event)
+		if err != nil {
+			return nil, err
+		}
+		if framed {
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		}
+		return msg, nil
+	}}, nil
+}
+
+const (
+	// The facility (local0) and severity (notice) of the syslog messages
+	syslogAuditPriority = 16*8 + 5
+
+	syslogDialTimeout = 5 * time.Second
+)
+
+func formatSyslogAuditEvent(hostname string, event *resourcepb.AuditEvent) ([]byte, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if hostname == "" {
+		hostname = "-"
+	}
+	ts := time.UnixMilli(event.Timestamp).UTC().Format(time.RFC3339Nano)
+	msg := fmt.Sprintf("<%d>1 %s %s grafana %d audit - %s", syslogAuditPriority, ts, strings.ReplaceAll(hostname, " ", "-"), os.Getpid(), body)
+	return []byte(msg), nil
+}
+
+type writerAuditSink struct {
+	mu     sync.Mutex
+	w      io.WriteCloser
+	closed bool
+	format func(*resourcepb.AuditEvent) ([]byte, error)
+
+	// Opens the writer again after a failed write, optional
+	dial func() (io.WriteCloser, error)
+}
+
+var _ io.Closer = (*writerAuditSink)(nil)
+
+func (s *writerAuditSink) WriteAuditEvent(_ context.Context, event *resourcepb.AuditEvent) error {
+	b, err := s.format(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.w == nil {
+		if s.w, err = s.dial(); err != nil {
+			return err
+		}
+	}
+	_, err = s.w.Write(b)
+	if err != nil && s.dial != nil {
+		// the next write opens a new connection
+		_ = s.w.Close()
+		s.w = nil
+	}
+	return err
+}
+
+func (s *writerAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.w == nil {
+		return nil
+	}
+	err := s.w.Close()
+	s.w = nil
+	return err
+}
@@ -0,0 +1,288 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	authlib "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestServerAudit(t *testing.T) {
+	user := &identity.StaticRequester{
+		Type:           authlib.TypeUser,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true,
+	}
+	ctx := authlib.WithAuthInfo(context.Background(), user)
+	sink := &memoryAuditSink{}
+	server, err := NewResourceServer(ResourceServerOptions{
+		Backend:    setupTestStorageBackend(t),
+		AuditSinks: []AuditSink{sink},
+		Now:        func() int64 { return 1000 },
+	})
+	require.NoError(t, err)
+
+	key := &resourcepb.ResourceKey{Namespace: "default", Group: "playlist.grafana.app", Resource: "playlists", Name: "abc"}
+	value := func(title string, annotations string) []byte {
+		return []byte(`{
+			"apiVersion": "playlist.grafana.app/v0alpha1",
+			"kind": "Playlist",
+			"metadata": {"name": "abc", "uid": "xyz", "namespace": "default"` + annotations + `},
+			"spec": {"title": "` + title + `"}
+		}`)
+	}
+
+	created, err := server.Create(ctx, &resourcepb.CreateRequest{Key: key, Value: value("hello", "")})
+	require.NoError(t, err)
+	require.Nil(t, created.Error)
+
+	// failed writes are not recorded
+	failed, err := server.Create(ctx, &resourcepb.CreateRequest{Key: key, Value: value("hello", "")})
+	require.NoError(t, err)
+	require.NotNil(t, failed.Error)
+
+	updated, err := server.Update(ctx, &resourcepb.UpdateRequest{
+		Key:             key,
+		Value:           value("world", `, "annotations": {"grafana.app/managedBy": "repo", "grafana.app/managerId": "my-repo"}`),
+		ResourceVersion: created.ResourceVersion,
+	})
+	require.NoError(t, err)
+	require.Nil(t, updated.Error)
+
+	deleted, err := server.Delete(ctx, &resourcepb.DeleteRequest{Key: key, ResourceVersion: updated.ResourceVersion})
+	require.NoError(t, err)
+	require.Nil(t, deleted.Error)
+
+	events := sink.events
+	require.Len(t, events, 3)
+
+	require.Equal(t, resourcepb.WatchEvent_ADDED, events[0].Action)
+	require.Equal(t, user.GetUID(), events[0].Identity)
+	require.Equal(t, int64(1000), events[0].Timestamp)
+	require.Equal(t, int64(0), events[0].PreviousResourceVersion)
+	require.Equal(t, created.ResourceVersion, events[0].ResourceVersion)
+	require.Equal(t, AuditOriginUI, events[0].Origin)
+	require.Contains(t, events[0].Patch, `"path":"/spec"`)
+
+	require.Equal(t, resourcepb.WatchEvent_MODIFIED, events[1].Action)
+	require.Equal(t, created.ResourceVersion, events[1].PreviousResourceVersion)
+	require.Equal(t, updated.ResourceVersion, events[1].ResourceVersion)
+	require.Equal(t, "repo", events[1].Origin)
+	require.Equal(t, "my-repo", events[1].OriginIdentity)
+	var patch []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(events[1].Patch), &patch))
+	require.Contains(t, patch, map[string]any{"op": "replace", "path": "/spec/title", "value": "world"})
+
+	require.Equal(t, resourcepb.WatchEvent_DELETED, events[2].Action)
+	require.Equal(t, updated.ResourceVersion, events[2].PreviousResourceVersion)
+	require.Equal(t, deleted.ResourceVersion, events[2].ResourceVersion)
+	require.Empty(t, events[2].Patch)
+
+	t.Run("query", func(t *testing.T) {
+		rsp, err := server.QueryAudit(ctx, &resourcepb.AuditQueryRequest{
+			Key: &resourcepb.ResourceKey{Namespace: "default", Group: key.Group, Resource: key.Resource},
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Len(t, rsp.Events, 3)
+		require.Equal(t, int64(maxAuditQueryLimit), sink.lastQuery.Limit)
+
+		rsp, err = server.QueryAudit(ctx, &resourcepb.AuditQueryRequest{Key: &resourcepb.ResourceKey{Resource: "playlists"}})
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+	})
+
+	t.Run("query pages", func(t *testing.T) {
+		query := &resourcepb.AuditQueryRequest{
+			Key:   &resourcepb.ResourceKey{Namespace: "default", Group: key.Group, Resource: key.Resource},
+			Limit: 2,
+		}
+		rsp, err := server.QueryAudit(ctx, query)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Equal(t, []*resourcepb.AuditEvent{events[2], events[1]}, rsp.Events)
+		require.NotEmpty(t, rsp.NextPageToken)
+
+		query.NextPageToken = rsp.NextPageToken
+		rsp, err = server.QueryAudit(ctx, query)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Equal(t, []*resourcepb.AuditEvent{events[0]}, rsp.Events)
+		require.Empty(t, rsp.NextPageToken)
+
+		query.NextPageToken = "invalid"
+		rsp, err = server.QueryAudit(ctx, query)
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusBadRequest), rsp.Error.Code)
+	})
+
+	t.Run("bulk", func(t *testing.T) {
+		sink.events = nil
+		rejected := &resourcepb.ResourceKey{Namespace: "default", Group: key.Group, Resource: key.Resource, Name: "rejected"}
+		server.recordBulkAudit(ctx, user, []*resourcepb.BulkRequest{
+			{Key: key, Action: resourcepb.BulkRequest_ADDED, Folder: "f"},
+			{Key: rejected, Action: resourcepb.BulkRequest_ADDED},
+		}, &resourcepb.BulkResponse{
+			Summary:  []*resourcepb.BulkResponse_Summary{{Namespace: "default", Group: key.Group, Resource: key.Resource, ResourceVersion: 5}},
+			Rejected: []*resourcepb.BulkResponse_Rejected{{Key: rejected}},
+		})
+		require.Len(t, sink.events, 1)
+		require.Equal(t, key, sink.events[0].Key)
+		require.Equal(t, resourcepb.WatchEvent_ADDED, sink.events[0].Action)
+		require.Equal(t, "f", sink.events[0].Folder)
+		require.Equal(t, int64(5), sink.events[0].ResourceVersion)
+	})
+
+	t.Run("query without a queryable sink", func(t *testing.T) {
+		server, err := NewResourceServer(ResourceServerOptions{Backend: setupTestStorageBackend(t)})
+		require.NoError(t, err)
+		rsp, err := server.QueryAudit(ctx, &resourcepb.AuditQueryRequest{Key: &resourcepb.ResourceKey{Namespace: "default"}})
+		require.NoError(t, err)
+		require.Equal(t, int32(501), rsp.Error.Code)
+	})
+}
+
+func TestAuditOrigin(t *testing.T) {
+	origin, _ := auditOrigin(&identity.StaticRequester{Type: authlib.TypeServiceAccount}, nil)
+	require.Equal(t, AuditOriginAPI, origin)
+
+	origin, _ = auditOrigin(&identity.StaticRequester{Type: authlib.TypeUser}, nil)
+	require.Equal(t, AuditOriginUI, origin)
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(path)
+	require.NoError(t, err)
+
+	for _, rv := range []int64{1, 2} {
+		err = sink.WriteAuditEvent(context.Background(), &resourcepb.AuditEvent{
+			Guid:            "guid",
+			Key:             &resourcepb.ResourceKey{Namespace: "default", Group: "g", Resource: "r", Name: "n"},
+			ResourceVersion: rv,
+		})
+		require.NoError(t, err)
+	}
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	var rvs []int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		event := &resourcepb.AuditEvent{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), event))
+		rvs = append(rvs, event.ResourceVersion)
+	}
+	require.Equal(t, []int64{1, 2}, rvs)
+
+	closer, ok := sink.(io.Closer)
+	require.True(t, ok)
+	require.NoError(t, closer.Close())
+	err = sink.WriteAuditEvent(context.Background(), &resourcepb.AuditEvent{Key: &resourcepb.ResourceKey{}})
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestWriterAuditSinkReconnects(t *testing.T) {
+	conns := []*testAuditConn{{fail: true}, {}}
+	dials := 0
+	sink := &writerAuditSink{
+		w: conns[0],
+		dial: func() (io.WriteCloser, error) {
+			dials++
+			return conns[dials], nil
+		},
+		format: func(event *resourcepb.AuditEvent) ([]byte, error) {
+			return []byte(event.Guid), nil
+		},
+	}
+
+	err := sink.WriteAuditEvent(context.Background(), &resourcepb.AuditEvent{Guid: "a"})
+	require.Error(t, err)
+	require.True(t, conns[0].closed)
+	require.Equal(t, 0, dials)
+
+	err = sink.WriteAuditEvent(context.Background(), &resourcepb.AuditEvent{Guid: "b"})
+	require.NoError(t, err)
+	require.Equal(t, 1, dials)
+	require.Equal(t, "b", conns[1].String())
+
+	require.NoError(t, sink.Close())
+	require.True(t, conns[1].closed)
+}
+
+type testAuditConn struct {
+	bytes.Buffer
+	fail   bool
+	closed bool
+}
+
+func (c *testAuditConn) Write(p []byte) (int, error) {
+	if c.fail {
+		return 0, errors.New("connection reset")
+	}
+	return c.Buffer.Write(p)
+}
+
+func (c *testAuditConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestFormatSyslogAuditEvent(t *testing.T) {
+	msg, err := formatSyslogAuditEvent("my host", &resourcepb.AuditEvent{
+		Timestamp: 1700000000000,
+		Key:       &resourcepb.ResourceKey{Namespace: "default", Name: "n"},
+		Identity:  "user:u123",
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(msg), "<133>1 2023-11-14T22:13:20Z my-host grafana "), string(msg))
+	require.Contains(t, string(msg), ` audit - {"timestamp":1700000000000`)
+	require.Contains(t, string(msg), `"identity":"user:u123"`)
+}
+
+type memoryAuditSink struct {
+	mu        sync.Mutex
+	events    []*resourcepb.AuditEvent
+	lastQuery *resourcepb.AuditQueryRequest
+}
+
+func (s *memoryAuditSink) WriteAuditEvent(_ context.Context, event *resourcepb.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memoryAuditSink) ListAuditEvents(_ context.Context, req *resourcepb.AuditQueryRequest, after *AuditContinueToken) ([]*resourcepb.AuditEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastQuery = req
+	var events []*resourcepb.AuditEvent
+	skip := after != nil
+	for i := len(s.events) - 1; i >= 0 && int64(len(events)) < req.Limit; i-- {
+		if skip {
+			skip = s.events[i].Guid != after.GUID
+			continue
+		}
+		if matchesQueryKey(req.Key, s.events[i].Key) {
+			events = append(events, s.events[i])
+		}
+	}
+	return events, nil
+}
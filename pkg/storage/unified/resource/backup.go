@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// BackupWriter writes the resources of a namespace from the backend to an archive
+type BackupWriter = func(ctx context.Context, backend StorageBackend, req *resourcepb.BackupRequest, out io.Writer) error
+
+// The maximum size of each chunk sent to the client
+const backupChunkSize = 1024 * 1024
+
+// Backup implements BackupStoreServer.
+func (s *server) Backup(req *resourcepb.BackupRequest, stream resourcepb.BackupStore_BackupServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), "storage_server.Backup")
+	defer span.End()
+
+	if rsp := s.checkBackup(ctx, req); rsp != nil {
+		return stream.Send(&resourcepb.BackupResponse{Error: rsp})
+	}
+
+	out := bufio.NewWriterSize(&backupStreamWriter{stream: stream}, backupChunkSize)
+	err := s.backup(ctx, s.backend, req, out)
+	if err == nil {
+		err = out.Flush()
+	}
+	if err != nil {
+		s.log.Warn("backup failed", "namespace", req.Namespace, "error", err)
+		return stream.Send(&resourcepb.BackupResponse{Error: AsErrorResult(err)})
+	}
+	return nil
+}
+
+func (s *server) checkBackup(ctx context.Context, req *resourcepb.BackupRequest) *resourcepb.ErrorResult {
+	if err := s.Init(ctx); err != nil {
+		return AsErrorResult(err)
+	}
+	if s.backup == nil {
+		return &resourcepb.ErrorResult{
+			Message: "backups are not supported by the storage server",
+			Code:    http.StatusNotImplemented,
+		}
+	}
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		return &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+	}
+	if !canAdministerStorage(user) {
+		return &resourcepb.ErrorResult{
+			Message: "backups can only be created by grafana admins",
+			Code:    http.StatusForbidden,
+		}
+	}
+	if req.Namespace == "" {
+		return NewBadRequestError("missing namespace")
+	}
+	for _, key := range req.Resources {
+		if key.Group == "" || key.Resource == "" {
+			return NewBadRequestError("backup resources require a group and resource")
+		}
+	}
+	return nil
+}
+
+// backupStreamWriter sends everything written as backup chunks
+type backupStreamWriter struct {
+	stream resourcepb.BackupStore_BackupServer
+}
+
+func (w *backupStreamWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		n := min(len(p)-written, backupChunkSize)
+		err := w.stream.Send(&resourcepb.BackupResponse{
+			Chunk: bytes.Clone(p[written : written+n]),
+		})
+		if err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(p), nil
+}
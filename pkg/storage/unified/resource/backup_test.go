@@ -0,0 +1,67 @@
+package resource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	authlib "github.com/grafana/authlib/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestServerBackupAccess(t *testing.T) {
+	admin := authlib.WithAuthInfo(context.Background(), &identity.StaticRequester{
+		Type:           authlib.TypeUser,
+		Login:          "testuser",
+		UserID:         123,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true,
+	})
+	orgAdmin := authlib.WithAuthInfo(context.Background(), &identity.StaticRequester{
+		Type:    authlib.TypeUser,
+		Login:   "orgadmin",
+		UserID:  456,
+		UserUID: "u456",
+		OrgRole: identity.RoleAdmin,
+	})
+	service := identity.WithServiceIdentityContext(context.Background(), 1)
+
+	writer := func(ctx context.Context, backend StorageBackend, req *resourcepb.BackupRequest, out io.Writer) error {
+		return nil
+	}
+	server, err := NewResourceServer(ResourceServerOptions{
+		Backend: setupTestStorageBackend(t),
+		Backup:  writer,
+	})
+	require.NoError(t, err)
+
+	require.Nil(t, server.checkBackup(admin, &resourcepb.BackupRequest{Namespace: "default"}))
+	require.Nil(t, server.checkBackup(service, &resourcepb.BackupRequest{Namespace: "default"}))
+
+	rsp := server.checkBackup(orgAdmin, &resourcepb.BackupRequest{Namespace: "default"})
+	require.NotNil(t, rsp)
+	require.Equal(t, int32(http.StatusForbidden), rsp.Code)
+
+	rsp = server.checkBackup(admin, &resourcepb.BackupRequest{})
+	require.NotNil(t, rsp)
+	require.Equal(t, int32(http.StatusBadRequest), rsp.Code)
+
+	rsp = server.checkBackup(admin, &resourcepb.BackupRequest{
+		Namespace: "default",
+		Resources: []*resourcepb.ResourceKey{{Group: "dashboard.grafana.app"}},
+	})
+	require.NotNil(t, rsp)
+	require.Equal(t, int32(http.StatusBadRequest), rsp.Code)
+
+	// not available without a backup writer
+	server, err = NewResourceServer(ResourceServerOptions{Backend: setupTestStorageBackend(t)})
+	require.NoError(t, err)
+	rsp = server.checkBackup(admin, &resourcepb.BackupRequest{Namespace: "default"})
+	require.NotNil(t, rsp)
+	require.Equal(t, int32(http.StatusNotImplemented), rsp.Code)
+}
@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// Used to derive the content addressed blob UIDs
+var blobUIDNamespace = uuid.MustParse("9d3c3b5e-8f1a-4a52-9a0e-6b1b0c2f4d71")
+
+// ContentAddressedBlobUID returns a stable UID for a blob value saved for a resource.
+// Saving the same value for the same resource returns the same UID, so versions that did
+// not change the large payload share a single blob.  The resource key is part of the UID
+// so blobs are never shared between resources, and are always read with the same access checks.
+func ContentAddressedBlobUID(key *resourcepb.ResourceKey, value []byte) string {
+	hash := sha256.Sum256(value)
+	return uuid.NewSHA1(blobUIDNamespace, []byte(
+		key.Namespace+"/"+key.Group+"/"+key.Resource+"/"+key.Name+"/"+hex.EncodeToString(hash[:]),
+	)).String()
+}
+
+// BlobGarbageCollector is implemented by blob stores that can remove blobs once
+// no saved version of the resource (including history) references them anymore.
+type BlobGarbageCollector interface {
+	// GarbageCollectBlobs removes the unreferenced blobs of a group/resource created before the cutoff.
+	// Blobs are written before the resource that references them, so recent blobs
+	// must be kept until the write that references them has been saved.
+	GarbageCollectBlobs(ctx context.Context, group, resource string, before time.Time, dryRun bool) (BlobGCResult, error)
+}
+
+// BlobReference identifies a blob saved for a resource
+type BlobReference struct {
+	Namespace string
+	Name      string
+	UID       string
+}
+
+// BlobReferenceCounter is implemented by storage backends that save the blob referenced by every version
+type BlobReferenceCounter interface {
+	// CountBlobReferences returns how many saved versions (including history) of a group/resource reference each blob
+	CountBlobReferences(ctx context.Context, group, resource string) (map[BlobReference]int64, error)
+}
+
+// BlobGCResult summarizes a blob garbage collection run
+type BlobGCResult struct {
+	// Number of blobs that were checked
+	Blobs int64 `json:"blobs"`
+
+	// Number of saved versions referencing a blob
+	References int64 `json:"references"`
+
+	// Number of blobs shared by more than one version
+	Shared int64 `json:"shared"`
+
+	// Number of unreferenced blobs removed
+	Removed int64 `json:"removed"`
+}
@@ -149,6 +149,7 @@ func (s *server) BulkProcess(stream resourcepb.BulkStore_BulkProcessServer) erro
 		checker: make(map[string]authlib.ItemChecker), // Can create
 		stream:  stream,
 		span:    span,
+		audit:   len(s.auditSinks) > 0,
 	}
 	settings, err := NewBulkSettings(md)
 	if err != nil {
@@ -236,6 +237,9 @@ func (s *server) BulkProcess(stream resourcepb.BulkStore_BulkProcessServer) erro
 	if runner.err != nil {
 		rsp.Error = AsErrorResult(runner.err)
 	}
+	if rsp.Error == nil {
+		s.recordBulkAudit(ctx, user, runner.audited, rsp)
+	}
 
 	if rsp.Error == nil && s.search != nil {
 		// Rebuild any changed indexes
@@ -269,6 +273,10 @@ type batchRunner struct {
 	err      error
 	checker  map[string]authlib.ItemChecker
 	span     trace.Span
+
+	// The accepted requests (without values) when audit sinks are configured
+	audit   bool
+	audited []*resourcepb.BulkRequest
 }
 
 // Next implements BulkRequestIterator.
@@ -301,6 +309,12 @@ func (b *batchRunner) Next() bool {
 		} else if !checker(key.Name, b.request.Folder) {
 			b.err = fmt.Errorf("not allowed to create resource")
 			b.rollback = true
+		} else if b.audit {
+			b.audited = append(b.audited, &resourcepb.BulkRequest{
+				Key:    key,
+				Action: b.request.Action,
+				Folder: b.request.Folder,
+			})
 		}
 
 		// Mention resource in the span.
@@ -5,7 +5,9 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"mime"
 	"strings"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
@@ -31,8 +34,15 @@ type CDKBlobSupportOptions struct {
 	Bucket        CDKBucket
 	RootFolder    string
 	URLExpiration time.Duration
+
+	// Counts the blob references of the saved versions, required for garbage collection
+	References BlobReferenceCounter
 }
 
+// Existing blobs saved again after this long are written again to refresh the modified time,
+// so they are not garbage collected before the version referencing them is saved
+const cdkBlobTouchInterval = time.Minute
+
 // Called in a context that loaded the possible drivers
 func OpenBlobBucket(ctx context.Context, url string) (*blob.Bucket, error) {
 	if strings.HasPrefix(url, "file:") {
@@ -75,15 +85,19 @@ func NewCDKBlobSupport(ctx context.Context, opts CDKBlobSupportOptions) (BlobSup
 		root:        opts.RootFolder,
 		cansignurls: false, // TODO depends on the implementation
 		expiration:  opts.URLExpiration,
+		references:  opts.References,
 	}, nil
 }
 
+var _ BlobGarbageCollector = (*cdkBlobSupport)(nil)
+
 type cdkBlobSupport struct {
 	tracer      trace.Tracer
 	bucket      CDKBucket
 	root        string
 	cansignurls bool
 	expiration  time.Duration
+	references  BlobReferenceCounter
 }
 
 func (s *cdkBlobSupport) getBlobPath(key *resourcepb.ResourceKey, info *utils.BlobInfo) (string, error) {
@@ -134,6 +148,10 @@ func (s *cdkBlobSupport) PutResourceBlob(ctx context.Context, req *resourcepb.Pu
 	info := &utils.BlobInfo{
 		UID: uuid.New().String(),
 	}
+	if req.Method != resourcepb.PutBlobRequest_HTTP {
+		// The value is known, so identical values for the same resource share one blob
+		info.UID = ContentAddressedBlobUID(req.Resource, req.Value)
+	}
 	info.SetContentType(req.ContentType)
 	path, err := s.getBlobPath(req.Resource, info)
 	if err != nil {
@@ -153,15 +171,17 @@ func (s *cdkBlobSupport) PutResourceBlob(ctx context.Context, req *resourcepb.Pu
 		return nil, fmt.Errorf("missing content value")
 	}
 
-	// Write the value
-	err = s.bucket.WriteAll(ctx, path, req.Value, &blob.WriterOptions{
-		ContentType: req.ContentType,
-	})
-	if err != nil {
-		return nil, err
-	}
-
+	// Write the value, unless the same value was already saved recently
 	attrs, err := s.bucket.Attributes(ctx, path)
+	if gcerrors.Code(err) == gcerrors.NotFound || (err == nil && time.Since(attrs.ModTime) > cdkBlobTouchInterval) {
+		err = s.bucket.WriteAll(ctx, path, req.Value, &blob.WriterOptions{
+			ContentType: req.ContentType,
+		})
+		if err != nil {
+			return nil, err
+		}
+		attrs, err = s.bucket.Attributes(ctx, path)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -195,3 +215,101 @@ func (s *cdkBlobSupport) GetResourceBlob(ctx context.Context, resource *resource
 	})
 	return rsp, err
 }
+
+// GarbageCollectBlobs removes the blobs of a group/resource that are not referenced by any saved version of the resource
+// they were written for.  The blobs are listed for every namespace, and the modified time is used as the created time.
+func (s *cdkBlobSupport) GarbageCollectBlobs(ctx context.Context, group, resource string, before time.Time, dryRun bool) (BlobGCResult, error) {
+	ctx, span := s.tracer.Start(ctx, "cdk_blob.GarbageCollectBlobs")
+	defer span.End()
+
+	result := BlobGCResult{}
+	if s.references == nil {
+		return result, fmt.Errorf("the storage backend does not count blob references")
+	}
+
+	type cdkBlob struct {
+		path    string
+		ref     BlobReference
+		modTime time.Time
+	}
+	var blobs []cdkBlob
+	namespaces := s.bucket.List(&blob.ListOptions{Prefix: s.root, Delimiter: "/"})
+	for {
+		ns, err := namespaces.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("list namespaces: %w", err)
+		}
+		if !ns.IsDir {
+			continue
+		}
+		namespace := strings.TrimSuffix(strings.TrimPrefix(ns.Key, s.root), "/")
+		if namespace == "__cluster__" {
+			namespace = ""
+		}
+		prefix := ns.Key + group + "/" + resource + "/"
+		objects := s.bucket.List(&blob.ListOptions{Prefix: prefix})
+		for {
+			obj, err := objects.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return result, fmt.Errorf("list blobs: %w", err)
+			}
+			// {name}/{uid}{ext}
+			name, file, ok := strings.Cut(strings.TrimPrefix(obj.Key, prefix), "/")
+			if !ok || obj.IsDir {
+				continue
+			}
+			uid, _, _ := strings.Cut(file, ".")
+			blobs = append(blobs, cdkBlob{
+				path:    obj.Key,
+				ref:     BlobReference{Namespace: namespace, Name: name, UID: uid},
+				modTime: obj.ModTime,
+			})
+		}
+	}
+	result.Blobs = int64(len(blobs))
+	if len(blobs) == 0 {
+		return result, nil
+	}
+
+	// References are counted after listing, so blobs referenced while listing are kept
+	refs, err := s.references.CountBlobReferences(ctx, group, resource)
+	if err != nil {
+		return result, fmt.Errorf("count blob references: %w", err)
+	}
+	for _, b := range blobs {
+		count := refs[b.ref]
+		result.References += count
+		if count > 1 {
+			result.Shared++
+		}
+		if count > 0 || !b.modTime.Before(before) {
+			continue
+		}
+		if dryRun {
+			result.Removed++
+			continue
+		}
+		// The modified time is checked again, since the same value may have been saved after the blob was listed
+		attrs, err := s.bucket.Attributes(ctx, b.path)
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("read blob attributes: %w", err)
+		}
+		if !attrs.ModTime.Before(before) {
+			continue
+		}
+		if err := s.bucket.Delete(ctx, b.path); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return result, fmt.Errorf("delete blob: %w", err)
+		}
+		result.Removed++
+	}
+	return result, nil
+}
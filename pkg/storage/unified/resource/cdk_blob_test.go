@@ -3,13 +3,16 @@ package resource
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
 
 	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
 	"gocloud.dev/blob/fileblob"
 	"gocloud.dev/blob/memblob"
 )
@@ -65,4 +68,118 @@ func TestCDKBlobStore(t *testing.T) {
 		require.Equal(t, raw, found.Value)
 		require.Equal(t, "application/json", found.ContentType)
 	})
+
+	t.Run("identical values share a blob", func(t *testing.T) {
+		key := &resourcepb.ResourceKey{
+			Group:     "dashboard.grafana.app",
+			Resource:  "dashboards",
+			Namespace: "default",
+			Name:      "large",
+		}
+		put := func(key *resourcepb.ResourceKey, value string) string {
+			rsp, err := store.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{
+				Resource:    key,
+				Method:      resourcepb.PutBlobRequest_GRPC,
+				ContentType: "application/json",
+				Value:       []byte(value),
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(len(value)), rsp.Size)
+			return rsp.Uid
+		}
+
+		uid := put(key, `{"panels": []}`)
+		require.Equal(t, uid, put(key, `{"panels": []}`))
+		require.Equal(t, ContentAddressedBlobUID(key, []byte(`{"panels": []}`)), uid)
+		require.NotEqual(t, uid, put(key, `{"panels": [{}]}`))
+
+		// Blobs are never shared between resources
+		other := &resourcepb.ResourceKey{Group: key.Group, Resource: key.Resource, Namespace: key.Namespace, Name: "other"}
+		require.NotEqual(t, uid, put(other, `{"panels": []}`))
+
+		count := 0
+		iter := bucket.List(&blob.ListOptions{Prefix: "default/dashboard.grafana.app/dashboards/large/"})
+		for {
+			_, err := iter.Next(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			count++
+		}
+		require.Equal(t, 2, count)
+	})
+}
+
+func TestCDKBlobGarbageCollection(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	refs := &staticBlobReferences{}
+	store, err := NewCDKBlobSupport(ctx, CDKBlobSupportOptions{
+		Bucket:     bucket,
+		References: refs,
+	})
+	require.NoError(t, err)
+
+	put := func(ns, name, value string) string {
+		rsp, err := store.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{
+			Resource:    &resourcepb.ResourceKey{Namespace: ns, Group: "dashboard.grafana.app", Resource: "dashboards", Name: name},
+			Method:      resourcepb.PutBlobRequest_GRPC,
+			ContentType: "application/json",
+			Value:       []byte(value),
+		})
+		require.NoError(t, err)
+		return rsp.Uid
+	}
+	shared := put("default", "a", `{"v": 1}`)
+	unused := put("default", "a", `{"v": 2}`)
+	cluster := put("", "b", `{"v": 1}`)
+	other := put("other", "c", `{"v": 1}`)
+	refs.refs = map[BlobReference]int64{
+		{Namespace: "default", Name: "a", UID: shared}: 2,
+		{Namespace: "", Name: "b", UID: cluster}:       1,
+		// the same uid saved for a different resource is not a reference
+		{Namespace: "other", Name: "x", UID: other}: 1,
+	}
+
+	gc, ok := store.(BlobGarbageCollector)
+	require.True(t, ok)
+
+	// recent blobs are kept
+	res, err := gc.GarbageCollectBlobs(ctx, "dashboard.grafana.app", "dashboards", time.Now().Add(-time.Hour), false)
+	require.NoError(t, err)
+	require.Equal(t, BlobGCResult{Blobs: 4, References: 3, Shared: 1}, res)
+
+	res, err = gc.GarbageCollectBlobs(ctx, "dashboard.grafana.app", "dashboards", time.Now().Add(time.Hour), true)
+	require.NoError(t, err)
+	require.Equal(t, BlobGCResult{Blobs: 4, References: 3, Shared: 1, Removed: 2}, res)
+
+	res, err = gc.GarbageCollectBlobs(ctx, "dashboard.grafana.app", "dashboards", time.Now().Add(time.Hour), false)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), res.Removed)
+
+	exists := func(ns, name, uid string) bool {
+		rsp, err := store.GetResourceBlob(ctx, &resourcepb.ResourceKey{Namespace: ns, Group: "dashboard.grafana.app", Resource: "dashboards", Name: name},
+			&utils.BlobInfo{UID: uid, MimeType: "application/json"}, true)
+		return err == nil && rsp.Error == nil
+	}
+	require.True(t, exists("default", "a", shared))
+	require.True(t, exists("", "b", cluster))
+	require.False(t, exists("default", "a", unused))
+	require.False(t, exists("other", "c", other))
+
+	t.Run("requires a reference counter", func(t *testing.T) {
+		store, err := NewCDKBlobSupport(ctx, CDKBlobSupportOptions{Bucket: bucket})
+		require.NoError(t, err)
+		_, err = store.(BlobGarbageCollector).GarbageCollectBlobs(ctx, "dashboard.grafana.app", "dashboards", time.Now(), true)
+		require.Error(t, err)
+	})
+}
+
+type staticBlobReferences struct {
+	refs map[BlobReference]int64
+}
+
+func (s *staticBlobReferences) CountBlobReferences(_ context.Context, _, _ string) (map[BlobReference]int64, error) {
+	return s.refs, nil
 }
@@ -36,6 +36,9 @@ type ResourceClient interface {
 	resourcepb.BulkStoreClient
 	resourcepb.BlobStoreClient
 	resourcepb.DiagnosticsClient
+	resourcepb.AuditLogClient
+	resourcepb.ResourceQuotasClient
+	resourcepb.BackupStoreClient
 }
 
 // Internal implementation
@@ -46,6 +49,9 @@ type resourceClient struct {
 	resourcepb.BulkStoreClient
 	resourcepb.BlobStoreClient
 	resourcepb.DiagnosticsClient
+	resourcepb.AuditLogClient
+	resourcepb.ResourceQuotasClient
+	resourcepb.BackupStoreClient
 }
 
 func NewResourceClient(conn, indexConn grpc.ClientConnInterface, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer trace.Tracer) (ResourceClient, error) {
@@ -72,6 +78,9 @@ func newResourceClient(storageCc grpc.ClientConnInterface, indexCc grpc.ClientCo
 		BulkStoreClient:          resourcepb.NewBulkStoreClient(storageCc),
 		BlobStoreClient:          resourcepb.NewBlobStoreClient(storageCc),
 		DiagnosticsClient:        resourcepb.NewDiagnosticsClient(storageCc),
+		AuditLogClient:           resourcepb.NewAuditLogClient(storageCc),
+		ResourceQuotasClient:     resourcepb.NewResourceQuotasClient(storageCc),
+		BackupStoreClient:        resourcepb.NewBackupStoreClient(storageCc),
 	}
 }
 
@@ -98,6 +107,9 @@ func NewLocalResourceClient(server ResourceServer) ResourceClient {
 		&resourcepb.BlobStore_ServiceDesc,
 		&resourcepb.BulkStore_ServiceDesc,
 		&resourcepb.Diagnostics_ServiceDesc,
+		&resourcepb.AuditLog_ServiceDesc,
+		&resourcepb.ResourceQuotas_ServiceDesc,
+		&resourcepb.BackupStore_ServiceDesc,
 	} {
 		channel.RegisterService(
 			grpchan.InterceptServer(
@@ -93,6 +93,9 @@ type IndexableDocument struct {
 	// Who updated the resource (will be in the form `user:uid`)
 	UpdatedBy string `json:"updatedBy,omitempty"`
 
+	// The owner references (will be in the form `{kind}:{name}`)
+	Owners []string `json:"owners,omitempty"`
+
 	// Searchable nested keys
 	// The key should exist from the fields defined in DocumentBuilderInfo
 	// This should not contain duplicate information from the results above
@@ -214,9 +217,12 @@ func NewIndexableDocument(key *resourcepb.ResourceKey, rv int64, obj utils.Grafa
 		doc.Created = ts.UnixMilli()
 	}
 	tt, err := obj.GetUpdatedTimestamp()
-	if err != nil && tt != nil {
+	if err == nil && tt != nil {
 		doc.Updated = tt.UnixMilli()
 	}
+	for _, owner := range obj.GetOwnerReferences() {
+		doc.Owners = append(doc.Owners, fmt.Sprintf("%s:%s", owner.Kind, owner.Name))
+	}
 	return doc.UpdateCopyFields()
 }
 
@@ -301,6 +307,7 @@ const SEARCH_FIELD_CREATED = "created"
 const SEARCH_FIELD_CREATED_BY = "createdBy"
 const SEARCH_FIELD_UPDATED = "updated"
 const SEARCH_FIELD_UPDATED_BY = "updatedBy"
+const SEARCH_FIELD_OWNERS = "owners" // {kind}:{name}
 
 const SEARCH_FIELD_MANAGED_BY = "managedBy" // {kind}:{id}
 const SEARCH_FIELD_MANAGER_KIND = "manager.kind"
@@ -13,6 +13,9 @@ type WriteEvent struct {
 	Key        *resourcepb.ResourceKey    // the request key
 	PreviousRV int64                      // only for Update+Delete
 
+	// The size of the saved value, only for Update+Delete
+	PreviousSize int64
+
 	// GUID is optional and might be used when persisting an event.
 	// It is always set by the resource server.
 	GUID string
@@ -25,6 +28,10 @@ type WriteEvent struct {
 
 	// Access to the old metadata
 	ObjectOld utils.GrafanaMetaAccessor
+
+	// The audit event of the change, when audit sinks are configured.
+	// Backends implementing TransactionalAuditSink save it with the change
+	Audit *resourcepb.AuditEvent
 }
 
 func (e *WriteEvent) Validate() error {
@@ -47,7 +47,18 @@ type KV interface {
 	UnixTimestamp(ctx context.Context) (int64, error)
 }
 
-var _ KV = &badgerKV{}
+// BatchKV is implemented by KV stores that can apply multiple writes atomically
+type BatchKV interface {
+	// Batch calls fn with a KV that buffers every Save and Delete. The writes are
+	// committed together when fn returns without an error, otherwise they are discarded.
+	// NOTE: reads within the batch do not see the buffered writes
+	Batch(ctx context.Context, fn func(kv KV) error) error
+}
+
+var (
+	_ KV      = &badgerKV{}
+	_ BatchKV = &badgerKV{}
+)
 
 // Reference implementation of the KV interface using BadgerDB
 // This is only used for testing purposes, and will not work HA
@@ -95,6 +106,7 @@ func (k *badgerKV) Get(ctx context.Context, section string, key string) (io.Read
 // badgerWriteCloser implements io.WriteCloser for badgerKV
 type badgerWriteCloser struct {
 	db             *badger.DB
+	txn            *badger.Txn // when set, the value is added to this (batch) transaction
 	keyWithSection string
 	buf            *bytes.Buffer
 	closed         bool
@@ -120,6 +132,9 @@ func (w *badgerWriteCloser) Close() error {
 	}
 
 	data := w.buf.Bytes()
+	if w.txn != nil {
+		return w.txn.Set([]byte(w.keyWithSection), data)
+	}
 
 	txn := w.db.NewTransaction(true)
 	defer txn.Discard()
@@ -242,6 +257,59 @@ func (k *badgerKV) UnixTimestamp(ctx context.Context) (int64, error) {
 	return time.Now().Unix(), nil
 }
 
+func (k *badgerKV) Batch(ctx context.Context, fn func(kv KV) error) error {
+	if k.db.IsClosed() {
+		return fmt.Errorf("database is closed")
+	}
+
+	txn := k.db.NewTransaction(true)
+	defer txn.Discard()
+
+	if err := fn(&badgerBatchKV{badgerKV: k, txn: txn}); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// badgerBatchKV reads from the committed state and adds all writes to a single transaction
+type badgerBatchKV struct {
+	*badgerKV
+	txn *badger.Txn
+}
+
+func (k *badgerBatchKV) Save(ctx context.Context, section string, key string) (io.WriteCloser, error) {
+	if section == "" {
+		return nil, fmt.Errorf("section is required")
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	return &badgerWriteCloser{
+		db:             k.db,
+		txn:            k.txn,
+		keyWithSection: section + "/" + key,
+		buf:            &bytes.Buffer{},
+	}, nil
+}
+
+func (k *badgerBatchKV) Delete(ctx context.Context, section string, key string) error {
+	if section == "" {
+		return fmt.Errorf("section is required")
+	}
+
+	key = section + "/" + key
+	_, err := k.txn.Get([]byte(key))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return k.txn.Delete([]byte(key))
+}
+
 // PrefixRangeEnd returns the end key for the given prefix
 func PrefixRangeEnd(prefix string) string {
 	key := []byte(prefix)
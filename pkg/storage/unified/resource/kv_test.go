@@ -217,6 +217,46 @@ func TestBadgerKV_UnderlyingStorage(t *testing.T) {
 	})
 }
 
+func TestBadgerKV_Batch(t *testing.T) {
+	kv := NewBadgerKV(setupTestBadgerDB(t))
+	ctx := context.Background()
+	saveKVHelper(t, kv, ctx, "section", "existing", strings.NewReader("old"))
+
+	t.Run("writes are committed together", func(t *testing.T) {
+		err := kv.Batch(ctx, func(batch KV) error {
+			saveKVHelper(t, batch, ctx, "section", "a", strings.NewReader("a"))
+			saveKVHelper(t, batch, ctx, "section", "b", strings.NewReader("b"))
+
+			// not visible until commit
+			_, err := kv.Get(ctx, "section", "a")
+			require.ErrorIs(t, err, ErrNotFound)
+			return batch.Delete(ctx, "section", "existing")
+		})
+		require.NoError(t, err)
+
+		for _, k := range []string{"a", "b"} {
+			r, err := kv.Get(ctx, "section", k)
+			require.NoError(t, err)
+			v, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, k, string(v))
+		}
+		_, err = kv.Get(ctx, "section", "existing")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("writes are discarded on error", func(t *testing.T) {
+		err := kv.Batch(ctx, func(batch KV) error {
+			saveKVHelper(t, batch, ctx, "section", "c", strings.NewReader("c"))
+			return io.ErrUnexpectedEOF
+		})
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+		_, err = kv.Get(ctx, "section", "c")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
 func TestIsValidKey(t *testing.T) {
 	tests := []struct {
 		name     string
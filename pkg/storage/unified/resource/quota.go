@@ -0,0 +1,423 @@
+package resource
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// How long the namespace quota overrides are cached by each server
+const quotaOverridesTTL = 30 * time.Second
+
+// How long the usage counted by the backend is cached by each server.  The writes saved by
+// the server are added to the cached usage, writes saved by other servers are counted once it expires
+const quotaUsageTTL = 30 * time.Second
+
+// QuotaLimit is the maximum number of objects and total size of a group/resource within a namespace.
+// Zero values are unlimited
+type QuotaLimit struct {
+	Group      string
+	Resource   string
+	MaxObjects int64
+	MaxBytes   int64
+}
+
+func (l QuotaLimit) unlimited() bool {
+	return l.MaxObjects <= 0 && l.MaxBytes <= 0
+}
+
+// QuotaUsage is the current number of objects and their total size
+type QuotaUsage struct {
+	Objects int64
+	Bytes   int64
+}
+
+// QuotaBackend is implemented by backends that can enforce quotas
+type QuotaBackend interface {
+	// GetQuotaUsage returns the usage of a group/resource within a namespace.
+	// Only the current values are counted (not history or trash), and the size
+	// is only summed when withBytes is set
+	GetQuotaUsage(ctx context.Context, namespace, group, resource string, withBytes bool) (QuotaUsage, error)
+
+	// ListQuotaOverrides returns the limits saved for a namespace
+	ListQuotaOverrides(ctx context.Context, namespace string) ([]QuotaLimit, error)
+
+	// SetQuotaOverride saves the limit for a namespace, replacing any existing value
+	SetQuotaOverride(ctx context.Context, namespace string, limit QuotaLimit) error
+
+	// DeleteQuotaOverride removes the limit saved for a namespace
+	DeleteQuotaOverride(ctx context.Context, namespace, group, resource string) error
+}
+
+type quotaSupport struct {
+	backend  QuotaBackend
+	defaults map[string]QuotaLimit // by group/resource
+	now      func() time.Time
+
+	mu        sync.Mutex
+	overrides map[string]cachedQuotaOverrides // by namespace
+	usage     map[string]*cachedQuotaUsage    // by namespace/group/resource
+	// when the expired overrides and usage are next removed from the cache
+	nextEviction time.Time
+}
+
+type cachedQuotaOverrides struct {
+	limits  map[string]QuotaLimit
+	expires time.Time
+}
+
+type cachedQuotaUsage struct {
+	usage     QuotaUsage
+	withBytes bool
+	expires   time.Time
+}
+
+func newQuotaSupport(backend QuotaBackend, defaults []QuotaLimit) (*quotaSupport, error) {
+	q := &quotaSupport{
+		backend:   backend,
+		defaults:  make(map[string]QuotaLimit, len(defaults)),
+		now:       time.Now,
+		overrides: make(map[string]cachedQuotaOverrides),
+		usage:     make(map[string]*cachedQuotaUsage),
+	}
+	for _, l := range defaults {
+		if l.Group == "" || l.Resource == "" {
+			return nil, fmt.Errorf("quota requires a group and resource")
+		}
+		if l.MaxObjects < 0 || l.MaxBytes < 0 {
+			return nil, fmt.Errorf("invalid quota for %s.%s", l.Resource, l.Group)
+		}
+		if !l.unlimited() {
+			q.defaults[l.Group+"/"+l.Resource] = l
+		}
+	}
+	return q, nil
+}
+
+// limits returns every limit that applies to the namespace, by group/resource
+func (q *quotaSupport) limits(ctx context.Context, namespace string) (map[string]QuotaLimit, map[string]bool, error) {
+	overrides, err := q.namespaceOverrides(ctx, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	limits := make(map[string]QuotaLimit, len(q.defaults)+len(overrides))
+	for k, l := range q.defaults {
+		limits[k] = l
+	}
+	overridden := make(map[string]bool, len(overrides))
+	for k, l := range overrides {
+		limits[k] = l
+		overridden[k] = true
+	}
+	return limits, overridden, nil
+}
+
+func (q *quotaSupport) namespaceOverrides(ctx context.Context, namespace string) (map[string]QuotaLimit, error) {
+	q.mu.Lock()
+	cached, ok := q.overrides[namespace]
+	q.mu.Unlock()
+	if ok && q.now().Before(cached.expires) {
+		return cached.limits, nil
+	}
+
+	list, err := q.backend.ListQuotaOverrides(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string]QuotaLimit, len(list))
+	for _, l := range list {
+		limits[l.Group+"/"+l.Resource] = l
+	}
+
+	q.mu.Lock()
+	q.evictExpired()
+	q.overrides[namespace] = cachedQuotaOverrides{limits: limits, expires: q.now().Add(quotaOverridesTTL)}
+	q.mu.Unlock()
+	return limits, nil
+}
+
+// currentUsage returns the usage of a group/resource within a namespace, counted by the backend once the cached usage expired
+func (q *quotaSupport) currentUsage(ctx context.Context, namespace, group, resource string, withBytes bool) (QuotaUsage, error) {
+	k := namespace + "/" + group + "/" + resource
+	q.mu.Lock()
+	cached, ok := q.usage[k]
+	if ok && q.now().Before(cached.expires) && (cached.withBytes || !withBytes) {
+		usage := cached.usage
+		q.mu.Unlock()
+		return usage, nil
+	}
+	q.mu.Unlock()
+
+	usage, err := q.backend.GetQuotaUsage(ctx, namespace, group, resource, withBytes)
+	if err != nil {
+		return usage, err
+	}
+	q.mu.Lock()
+	q.evictExpired()
+	q.usage[k] = &cachedQuotaUsage{usage: usage, withBytes: withBytes, expires: q.now().Add(quotaUsageTTL)}
+	q.mu.Unlock()
+	return usage, nil
+}
+
+// evictExpired removes the expired overrides and usage, so namespaces that are no longer
+// written do not stay in the cache.  The cache is swept at most once per TTL; q.mu must be held
+func (q *quotaSupport) evictExpired() {
+	now := q.now()
+	if now.Before(q.nextEviction) {
+		return
+	}
+	q.nextEviction = now.Add(min(quotaOverridesTTL, quotaUsageTTL))
+	for k, cached := range q.overrides {
+		if !now.Before(cached.expires) {
+			delete(q.overrides, k)
+		}
+	}
+	for k, cached := range q.usage {
+		if !now.Before(cached.expires) {
+			delete(q.usage, k)
+		}
+	}
+}
+
+// quotaChange returns how a write changes the number of objects and their total size
+func quotaChange(event WriteEvent) QuotaUsage {
+	switch event.Type {
+	case resourcepb.WatchEvent_ADDED:
+		return QuotaUsage{Objects: 1, Bytes: int64(len(event.Value))}
+	case resourcepb.WatchEvent_MODIFIED:
+		return QuotaUsage{Bytes: int64(len(event.Value)) - event.PreviousSize}
+	case resourcepb.WatchEvent_DELETED:
+		return QuotaUsage{Objects: -1, Bytes: -event.PreviousSize}
+	}
+	return QuotaUsage{}
+}
+
+// check verifies that the created and updated objects fit within the namespace quotas.
+// Concurrent writes are not serialized, so the limits may be exceeded by a few objects.
+// The index of the first event that exceeds a quota is returned with the error
+func (q *quotaSupport) check(ctx context.Context, namespace string, events []WriteEvent) (int, *resourcepb.ErrorResult) {
+	limits, _, err := q.limits(ctx, namespace)
+	if err != nil {
+		return -1, AsErrorResult(err)
+	}
+	if len(limits) == 0 {
+		return -1, nil
+	}
+
+	usage := make(map[string]*QuotaUsage)
+	for i, event := range events {
+		gr := event.Key.Group + "/" + event.Key.Resource
+		limit, ok := limits[gr]
+		if !ok || limit.unlimited() {
+			continue
+		}
+		current, ok := usage[gr]
+		if !ok {
+			u, err := q.currentUsage(ctx, namespace, event.Key.Group, event.Key.Resource, limit.MaxBytes > 0)
+			if err != nil {
+				return i, AsErrorResult(err)
+			}
+			current = &u
+			usage[gr] = current
+		}
+		change := quotaChange(event)
+		current.Objects += change.Objects
+		current.Bytes += change.Bytes
+
+		if limit.MaxObjects > 0 && change.Objects > 0 && current.Objects > limit.MaxObjects {
+			return i, newQuotaExceededError(namespace, limit, fmt.Sprintf("%d objects", limit.MaxObjects))
+		}
+		if limit.MaxBytes > 0 && change.Bytes > 0 && current.Bytes > limit.MaxBytes {
+			return i, newQuotaExceededError(namespace, limit, fmt.Sprintf("%d bytes", limit.MaxBytes))
+		}
+	}
+	return -1, nil
+}
+
+// record adds the saved writes to the cached usage
+func (q *quotaSupport) record(namespace string, events ...WriteEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, event := range events {
+		cached, ok := q.usage[namespace+"/"+event.Key.Group+"/"+event.Key.Resource]
+		if !ok {
+			continue
+		}
+		change := quotaChange(event)
+		cached.usage.Objects += change.Objects
+		cached.usage.Bytes += change.Bytes
+	}
+}
+
+func (s *server) recordQuotaUsage(namespace string, events ...WriteEvent) {
+	if s.quotas != nil {
+		s.quotas.record(namespace, events...)
+	}
+}
+
+func newQuotaExceededError(namespace string, limit QuotaLimit, max string) *resourcepb.ErrorResult {
+	return &resourcepb.ErrorResult{
+		Message: fmt.Sprintf("quota exceeded for %s.%s in namespace %s (max %s)", limit.Resource, limit.Group, namespace, max),
+		Reason:  string(metav1.StatusReasonForbidden),
+		Code:    http.StatusForbidden,
+	}
+}
+
+// addStats sets the quota limits (and size) on the stats of a namespace
+func (q *quotaSupport) addStats(ctx context.Context, namespace string, rsp *resourcepb.ResourceStatsResponse) error {
+	limits, _, err := q.limits(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, stat := range rsp.Stats {
+		if stat == nil {
+			continue
+		}
+		limit, ok := limits[stat.Group+"/"+stat.Resource]
+		if !ok {
+			continue
+		}
+		usage, err := q.backend.GetQuotaUsage(ctx, namespace, stat.Group, stat.Resource, true)
+		if err != nil {
+			return err
+		}
+		stat.Bytes = usage.Bytes
+		stat.QuotaMaxObjects = limit.MaxObjects
+		stat.QuotaMaxBytes = limit.MaxBytes
+	}
+	return nil
+}
+
+// Quotas and backups are not scoped to a resource, so only grafana admins and service identities are allowed
+func canAdministerStorage(user claims.AuthInfo) bool {
+	if claims.IsIdentityType(user.GetIdentityType(), claims.TypeAccessPolicy) {
+		return true
+	}
+	requester, ok := user.(identity.Requester)
+	return ok && requester.GetIsGrafanaAdmin()
+}
+
+func (s *server) checkQuotaAdmin(ctx context.Context) *resourcepb.ErrorResult {
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		return &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+	}
+	if !canAdministerStorage(user) {
+		return &resourcepb.ErrorResult{
+			Message: "quotas can only be managed by grafana admins",
+			Code:    http.StatusForbidden,
+		}
+	}
+	if s.quotas == nil {
+		return &resourcepb.ErrorResult{
+			Message: "quotas are not supported by the storage backend",
+			Code:    http.StatusNotImplemented,
+		}
+	}
+	return nil
+}
+
+// GetQuotas implements ResourceQuotasServer.
+func (s *server) GetQuotas(ctx context.Context, req *resourcepb.GetQuotasRequest) (*resourcepb.GetQuotasResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.GetQuotas")
+	defer span.End()
+
+	rsp := &resourcepb.GetQuotasResponse{}
+	if rsp.Error = s.checkQuotaAdmin(ctx); rsp.Error != nil {
+		return rsp, nil
+	}
+	if req.Namespace == "" {
+		rsp.Error = NewBadRequestError("missing namespace")
+		return rsp, nil
+	}
+
+	limits, overridden, err := s.quotas.limits(ctx, req.Namespace)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+	for gr, limit := range limits {
+		usage, err := s.quotas.backend.GetQuotaUsage(ctx, req.Namespace, limit.Group, limit.Resource, true)
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+		rsp.Quotas = append(rsp.Quotas, &resourcepb.GetQuotasResponse_Quota{
+			Limit: &resourcepb.QuotaLimit{
+				Group:      limit.Group,
+				Resource:   limit.Resource,
+				MaxObjects: limit.MaxObjects,
+				MaxBytes:   limit.MaxBytes,
+			},
+			Override: overridden[gr],
+			Objects:  usage.Objects,
+			Bytes:    usage.Bytes,
+		})
+	}
+	slices.SortFunc(rsp.Quotas, func(a, b *resourcepb.GetQuotasResponse_Quota) int {
+		return cmp.Or(
+			cmp.Compare(a.Limit.Group, b.Limit.Group),
+			cmp.Compare(a.Limit.Resource, b.Limit.Resource),
+		)
+	})
+	return rsp, nil
+}
+
+// SetQuota implements ResourceQuotasServer.
+func (s *server) SetQuota(ctx context.Context, req *resourcepb.SetQuotaRequest) (*resourcepb.SetQuotaResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.SetQuota")
+	defer span.End()
+
+	rsp := &resourcepb.SetQuotaResponse{}
+	if rsp.Error = s.checkQuotaAdmin(ctx); rsp.Error != nil {
+		return rsp, nil
+	}
+	if req.Namespace == "" {
+		rsp.Error = NewBadRequestError("missing namespace")
+		return rsp, nil
+	}
+	if req.Limit == nil || req.Limit.Group == "" || req.Limit.Resource == "" {
+		rsp.Error = NewBadRequestError("quota requires a group and resource")
+		return rsp, nil
+	}
+	if req.Limit.MaxObjects < 0 || req.Limit.MaxBytes < 0 {
+		rsp.Error = NewBadRequestError("quota limits can not be negative")
+		return rsp, nil
+	}
+
+	var err error
+	if req.RemoveOverride {
+		err = s.quotas.backend.DeleteQuotaOverride(ctx, req.Namespace, req.Limit.Group, req.Limit.Resource)
+	} else {
+		err = s.quotas.backend.SetQuotaOverride(ctx, req.Namespace, QuotaLimit{
+			Group:      req.Limit.Group,
+			Resource:   req.Limit.Resource,
+			MaxObjects: req.Limit.MaxObjects,
+			MaxBytes:   req.Limit.MaxBytes,
+		})
+	}
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+
+	// Other servers pick up the change once their cache expires
+	s.quotas.mu.Lock()
+	delete(s.quotas.overrides, req.Namespace)
+	s.quotas.mu.Unlock()
+	return rsp, nil
+}
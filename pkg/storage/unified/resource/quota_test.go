@@ -0,0 +1,457 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	authlib "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// quotaTestBackend tracks the usage of the objects written to a kv backend
+type quotaTestBackend struct {
+	*kvStorageBackend
+
+	mu        sync.Mutex
+	usage     map[string]QuotaUsage   // by namespace/group/resource
+	overrides map[string][]QuotaLimit // by namespace
+	listed    int
+	counted   int
+	withBytes bool // the last usage included the size
+}
+
+func newQuotaTestBackend(t *testing.T) *quotaTestBackend {
+	return &quotaTestBackend{
+		kvStorageBackend: setupTestStorageBackend(t),
+		usage:            make(map[string]QuotaUsage),
+		overrides:        make(map[string][]QuotaLimit),
+	}
+}
+
+func (b *quotaTestBackend) track(event WriteEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	k := event.Key.Namespace + "/" + event.Key.Group + "/" + event.Key.Resource
+	u := b.usage[k]
+	switch event.Type {
+	case resourcepb.WatchEvent_ADDED:
+		u.Objects++
+		u.Bytes += int64(len(event.Value))
+	case resourcepb.WatchEvent_MODIFIED:
+		u.Bytes += int64(len(event.Value)) - event.PreviousSize
+	case resourcepb.WatchEvent_DELETED:
+		u.Objects--
+		u.Bytes -= event.PreviousSize
+	}
+	b.usage[k] = u
+}
+
+func (b *quotaTestBackend) WriteEvent(ctx context.Context, event WriteEvent) (int64, error) {
+	rv, err := b.kvStorageBackend.WriteEvent(ctx, event)
+	if err == nil {
+		b.track(event)
+	}
+	return rv, err
+}
+
+func (b *quotaTestBackend) WriteEvents(ctx context.Context, events []WriteEvent) ([]int64, error) {
+	rvs, err := b.kvStorageBackend.WriteEvents(ctx, events)
+	if err == nil {
+		for _, event := range events {
+			b.track(event)
+		}
+	}
+	return rvs, err
+}
+
+func (b *quotaTestBackend) GetQuotaUsage(_ context.Context, namespace, group, resource string, withBytes bool) (QuotaUsage, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counted++
+	b.withBytes = withBytes
+	u := b.usage[namespace+"/"+group+"/"+resource]
+	if !withBytes {
+		u.Bytes = 0
+	}
+	return u, nil
+}
+
+func (b *quotaTestBackend) ListQuotaOverrides(_ context.Context, namespace string) ([]QuotaLimit, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listed++
+	return b.overrides[namespace], nil
+}
+
+func (b *quotaTestBackend) SetQuotaOverride(ctx context.Context, namespace string, limit QuotaLimit) error {
+	_ = b.DeleteQuotaOverride(ctx, namespace, limit.Group, limit.Resource)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.overrides[namespace] = append(b.overrides[namespace], limit)
+	return nil
+}
+
+func (b *quotaTestBackend) DeleteQuotaOverride(_ context.Context, namespace, group, resource string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var keep []QuotaLimit
+	for _, l := range b.overrides[namespace] {
+		if l.Group != group || l.Resource != resource {
+			keep = append(keep, l)
+		}
+	}
+	b.overrides[namespace] = keep
+	return nil
+}
+
+func TestServerQuotas(t *testing.T) {
+	admin := authlib.WithAuthInfo(context.Background(), &identity.StaticRequester{
+		Type:           authlib.TypeUser,
+		Login:          "testuser",
+		UserID:         123,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true, // can do anything
+	})
+	editor := authlib.WithAuthInfo(context.Background(), &identity.StaticRequester{
+		Type:    authlib.TypeUser,
+		Login:   "editor",
+		UserID:  456,
+		UserUID: "u456",
+		OrgRole: identity.RoleEditor,
+	})
+
+	backend := newQuotaTestBackend(t)
+	server, err := NewResourceServer(ResourceServerOptions{
+		Backend: backend,
+		Quotas: []QuotaLimit{
+			{Group: "playlist.grafana.app", Resource: "playlists", MaxObjects: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	key := func(ns, name string) *resourcepb.ResourceKey {
+		return &resourcepb.ResourceKey{Namespace: ns, Group: "playlist.grafana.app", Resource: "playlists", Name: name}
+	}
+	value := func(ns, name string) []byte {
+		return []byte(fmt.Sprintf(`{
+			"apiVersion": "playlist.grafana.app/v0alpha1",
+			"kind": "Playlist",
+			"metadata": {
+				"name": "%s",
+				"uid": "uid-%s",
+				"namespace": "%s"
+			},
+			"spec": {"title": "hello"}
+		}`, name, name, ns))
+	}
+	create := func(ns, name string) *resourcepb.CreateResponse {
+		rsp, err := server.Create(admin, &resourcepb.CreateRequest{Key: key(ns, name), Value: value(ns, name)})
+		require.NoError(t, err)
+		return rsp
+	}
+
+	t.Run("create is denied once the limit is reached", func(t *testing.T) {
+		require.Nil(t, create("default", "a").Error)
+		require.Nil(t, create("default", "b").Error)
+
+		rsp := create("default", "c")
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusForbidden), rsp.Error.Code)
+		require.Contains(t, rsp.Error.Message, "quota exceeded for playlists.playlist.grafana.app")
+
+		// other namespaces have their own usage
+		require.Nil(t, create("other", "c").Error)
+	})
+
+	t.Run("updates are allowed over the limit", func(t *testing.T) {
+		found, err := server.Read(admin, &resourcepb.ReadRequest{Key: key("default", "a")})
+		require.NoError(t, err)
+		require.Nil(t, found.Error)
+
+		rsp, err := server.Update(admin, &resourcepb.UpdateRequest{
+			Key:             key("default", "a"),
+			Value:           value("default", "a"),
+			ResourceVersion: found.ResourceVersion,
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+	})
+
+	t.Run("transaction is denied when any create exceeds the limit", func(t *testing.T) {
+		rsp, err := server.Transaction(admin, &resourcepb.TransactionRequest{
+			Namespace: "other",
+			Items: []*resourcepb.TransactionRequest_Item{
+				{Action: resourcepb.TransactionRequest_Item_CREATE, Key: key("other", "d"), Value: value("other", "d")},
+				{Action: resourcepb.TransactionRequest_Item_CREATE, Key: key("other", "e"), Value: value("other", "e")},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusForbidden), rsp.Error.Code)
+		require.Equal(t, int32(1), rsp.ErrorIndex)
+
+		found, err := server.Read(admin, &resourcepb.ReadRequest{Key: key("other", "d")})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusNotFound), found.Error.Code)
+	})
+
+	t.Run("only admins can manage quotas", func(t *testing.T) {
+		rsp, err := server.GetQuotas(editor, &resourcepb.GetQuotasRequest{Namespace: "default"})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusForbidden), rsp.Error.Code)
+
+		set, err := server.SetQuota(editor, &resourcepb.SetQuotaRequest{
+			Namespace: "default",
+			Limit:     &resourcepb.QuotaLimit{Group: "playlist.grafana.app", Resource: "playlists", MaxObjects: 100},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusForbidden), set.Error.Code)
+	})
+
+	t.Run("override the default limit", func(t *testing.T) {
+		set, err := server.SetQuota(admin, &resourcepb.SetQuotaRequest{
+			Namespace: "default",
+			Limit:     &resourcepb.QuotaLimit{Group: "playlist.grafana.app", Resource: "playlists", MaxObjects: 3},
+		})
+		require.NoError(t, err)
+		require.Nil(t, set.Error)
+
+		rsp, err := server.GetQuotas(admin, &resourcepb.GetQuotasRequest{Namespace: "default"})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Len(t, rsp.Quotas, 1)
+		require.True(t, rsp.Quotas[0].Override)
+		require.Equal(t, int64(3), rsp.Quotas[0].Limit.MaxObjects)
+		require.Equal(t, int64(2), rsp.Quotas[0].Objects)
+
+		require.Nil(t, create("default", "c").Error)
+		require.NotNil(t, create("default", "d").Error)
+
+		// back to the default
+		set, err = server.SetQuota(admin, &resourcepb.SetQuotaRequest{
+			Namespace:      "default",
+			Limit:          &resourcepb.QuotaLimit{Group: "playlist.grafana.app", Resource: "playlists"},
+			RemoveOverride: true,
+		})
+		require.NoError(t, err)
+		require.Nil(t, set.Error)
+
+		rsp, err = server.GetQuotas(admin, &resourcepb.GetQuotasRequest{Namespace: "default"})
+		require.NoError(t, err)
+		require.False(t, rsp.Quotas[0].Override)
+		require.Equal(t, int64(2), rsp.Quotas[0].Limit.MaxObjects)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		set, err := server.SetQuota(admin, &resourcepb.SetQuotaRequest{Namespace: "default"})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusBadRequest), set.Error.Code)
+
+		set, err = server.SetQuota(admin, &resourcepb.SetQuotaRequest{
+			Namespace: "default",
+			Limit:     &resourcepb.QuotaLimit{Group: "playlist.grafana.app", Resource: "playlists", MaxObjects: -1},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusBadRequest), set.Error.Code)
+	})
+}
+
+func TestQuotaSupport(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("bytes limit", func(t *testing.T) {
+		backend := newQuotaTestBackend(t)
+		backend.usage["ns/g/r"] = QuotaUsage{Objects: 1, Bytes: 90}
+
+		q, err := newQuotaSupport(backend, []QuotaLimit{{Group: "g", Resource: "r", MaxBytes: 100}})
+		require.NoError(t, err)
+
+		event := func(size int) WriteEvent {
+			return WriteEvent{
+				Type:  resourcepb.WatchEvent_ADDED,
+				Key:   &resourcepb.ResourceKey{Namespace: "ns", Group: "g", Resource: "r"},
+				Value: make([]byte, size),
+			}
+		}
+		i, e := q.check(ctx, "ns", []WriteEvent{event(5)})
+		require.Nil(t, e)
+		require.Equal(t, -1, i)
+
+		i, e = q.check(ctx, "ns", []WriteEvent{event(5), event(6)})
+		require.NotNil(t, e)
+		require.Equal(t, 1, i)
+		require.Contains(t, e.Message, "max 100 bytes")
+	})
+
+	t.Run("updates can not grow over the bytes limit", func(t *testing.T) {
+		backend := newQuotaTestBackend(t)
+		backend.usage["ns/g/r"] = QuotaUsage{Objects: 2, Bytes: 90}
+
+		q, err := newQuotaSupport(backend, []QuotaLimit{{Group: "g", Resource: "r", MaxBytes: 100}})
+		require.NoError(t, err)
+
+		update := func(previous, size int) WriteEvent {
+			return WriteEvent{
+				Type:         resourcepb.WatchEvent_MODIFIED,
+				Key:          &resourcepb.ResourceKey{Namespace: "ns", Group: "g", Resource: "r"},
+				Value:        make([]byte, size),
+				PreviousSize: int64(previous),
+			}
+		}
+		_, e := q.check(ctx, "ns", []WriteEvent{update(10, 20)})
+		require.Nil(t, e)
+
+		_, e = q.check(ctx, "ns", []WriteEvent{update(10, 21)})
+		require.NotNil(t, e)
+		require.Contains(t, e.Message, "max 100 bytes")
+
+		// shrinking is always allowed
+		backend.usage["ns/g/r"] = QuotaUsage{Objects: 2, Bytes: 200}
+		q.usage = make(map[string]*cachedQuotaUsage)
+		_, e = q.check(ctx, "ns", []WriteEvent{update(50, 40)})
+		require.Nil(t, e)
+	})
+
+	t.Run("size is only counted with a bytes limit", func(t *testing.T) {
+		backend := newQuotaTestBackend(t)
+		q, err := newQuotaSupport(backend, []QuotaLimit{{Group: "g", Resource: "r", MaxObjects: 10}})
+		require.NoError(t, err)
+
+		_, e := q.check(ctx, "ns", []WriteEvent{{
+			Type:  resourcepb.WatchEvent_ADDED,
+			Key:   &resourcepb.ResourceKey{Namespace: "ns", Group: "g", Resource: "r"},
+			Value: []byte("{}"),
+		}})
+		require.Nil(t, e)
+		require.Equal(t, 1, backend.counted)
+		require.False(t, backend.withBytes)
+	})
+
+	t.Run("usage is cached and updated with the saved writes", func(t *testing.T) {
+		backend := newQuotaTestBackend(t)
+		backend.usage["ns/g/r"] = QuotaUsage{Objects: 1}
+
+		q, err := newQuotaSupport(backend, []QuotaLimit{{Group: "g", Resource: "r", MaxObjects: 2}})
+		require.NoError(t, err)
+		now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+		q.now = func() time.Time { return now }
+
+		create := WriteEvent{
+			Type:  resourcepb.WatchEvent_ADDED,
+			Key:   &resourcepb.ResourceKey{Namespace: "ns", Group: "g", Resource: "r"},
+			Value: []byte("{}"),
+		}
+		_, e := q.check(ctx, "ns", []WriteEvent{create})
+		require.Nil(t, e)
+		q.record("ns", create)
+
+		_, e = q.check(ctx, "ns", []WriteEvent{create})
+		require.NotNil(t, e)
+		require.Equal(t, 1, backend.counted)
+
+		// deletes free the space
+		q.record("ns", WriteEvent{Type: resourcepb.WatchEvent_DELETED, Key: create.Key})
+		_, e = q.check(ctx, "ns", []WriteEvent{create})
+		require.Nil(t, e)
+		require.Equal(t, 1, backend.counted)
+
+		// writes by other servers are counted once the usage expires
+		now = now.Add(quotaUsageTTL)
+		backend.usage["ns/g/r"] = QuotaUsage{Objects: 2}
+		_, e = q.check(ctx, "ns", []WriteEvent{create})
+		require.NotNil(t, e)
+		require.Equal(t, 2, backend.counted)
+	})
+
+	t.Run("overrides are cached", func(t *testing.T) {
+		backend := newQuotaTestBackend(t)
+		q, err := newQuotaSupport(backend, nil)
+		require.NoError(t, err)
+
+		now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+		q.now = func() time.Time { return now }
+
+		_, _, err = q.limits(ctx, "ns")
+		require.NoError(t, err)
+		_, _, err = q.limits(ctx, "ns")
+		require.NoError(t, err)
+		require.Equal(t, 1, backend.listed)
+
+		now = now.Add(quotaOverridesTTL)
+		_, _, err = q.limits(ctx, "ns")
+		require.NoError(t, err)
+		require.Equal(t, 2, backend.listed)
+	})
+
+	t.Run("expired entries are removed from the cache", func(t *testing.T) {
+		backend := newQuotaTestBackend(t)
+		q, err := newQuotaSupport(backend, []QuotaLimit{{Group: "g", Resource: "r", MaxObjects: 10}})
+		require.NoError(t, err)
+
+		now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+		q.now = func() time.Time { return now }
+
+		for i := range 3 {
+			ns := fmt.Sprintf("ns-%d", i)
+			_, e := q.check(ctx, ns, []WriteEvent{{
+				Type:  resourcepb.WatchEvent_ADDED,
+				Key:   &resourcepb.ResourceKey{Namespace: ns, Group: "g", Resource: "r"},
+				Value: []byte("{}"),
+			}})
+			require.Nil(t, e)
+		}
+		require.Len(t, q.overrides, 3)
+		require.Len(t, q.usage, 3)
+
+		// only the namespace written after the entries expired is kept
+		now = now.Add(max(quotaOverridesTTL, quotaUsageTTL))
+		_, e := q.check(ctx, "ns-0", []WriteEvent{{
+			Type:  resourcepb.WatchEvent_ADDED,
+			Key:   &resourcepb.ResourceKey{Namespace: "ns-0", Group: "g", Resource: "r"},
+			Value: []byte("{}"),
+		}})
+		require.Nil(t, e)
+		require.Len(t, q.overrides, 1)
+		require.Contains(t, q.overrides, "ns-0")
+		require.Len(t, q.usage, 1)
+		require.Contains(t, q.usage, "ns-0/g/r")
+	})
+
+	t.Run("stats include the limits", func(t *testing.T) {
+		backend := newQuotaTestBackend(t)
+		backend.usage["ns/g/r"] = QuotaUsage{Objects: 3, Bytes: 1024}
+
+		q, err := newQuotaSupport(backend, []QuotaLimit{{Group: "g", Resource: "r", MaxObjects: 10}})
+		require.NoError(t, err)
+
+		rsp := &resourcepb.ResourceStatsResponse{Stats: []*resourcepb.ResourceStatsResponse_Stats{
+			{Group: "g", Resource: "r", Count: 3},
+			{Group: "g", Resource: "unlimited", Count: 5},
+		}}
+		require.NoError(t, q.addStats(ctx, "ns", rsp))
+		require.Equal(t, int64(1024), rsp.Stats[0].Bytes)
+		require.Equal(t, int64(10), rsp.Stats[0].QuotaMaxObjects)
+		require.Equal(t, int64(0), rsp.Stats[1].QuotaMaxObjects)
+	})
+
+	t.Run("invalid defaults", func(t *testing.T) {
+		_, err := newQuotaSupport(newQuotaTestBackend(t), []QuotaLimit{{Group: "g", MaxObjects: 1}})
+		require.Error(t, err)
+	})
+}
+
+func TestServerQuotas_UnsupportedBackend(t *testing.T) {
+	_, err := NewResourceServer(ResourceServerOptions{
+		Backend: setupTestStorageBackend(t),
+		Quotas:  []QuotaLimit{{Group: "g", Resource: "r", MaxObjects: 1}},
+	})
+	require.ErrorContains(t, err, "does not support quotas")
+}
@@ -0,0 +1,310 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RetentionPolicy controls how much history is kept for a group/resource.
+// A version is kept when any of the configured rules match.  The current value of
+// each resource (or the deletion marker for items in the trash) is always kept.
+type RetentionPolicy struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+
+	// Keep the most recent versions of each resource
+	KeepVersions int `json:"keepVersions,omitempty"`
+
+	// Keep every version newer than this
+	KeepNewerThan time.Duration `json:"keepNewerThan,omitempty"`
+
+	// Keep the latest version from each (UTC) day for versions not kept by the rules above
+	ThinToDaily bool `json:"thinToDaily,omitempty"`
+
+	// Remove deleted resources, including all their history, once they have been in the trash this long
+	PurgeTrashAfter time.Duration `json:"purgeTrashAfter,omitempty"`
+
+	// Remove blobs that are no longer referenced by any saved version once they are this old.
+	// Blobs are saved before the resource referencing them, so this must be longer than a write
+	BlobGracePeriod time.Duration `json:"blobGracePeriod,omitempty"`
+}
+
+// Enabled is true when the policy will remove anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.prunesHistory() || p.PurgeTrashAfter > 0 || p.BlobGracePeriod > 0
+}
+
+func (p RetentionPolicy) prunesHistory() bool {
+	return p.KeepVersions > 0 || p.KeepNewerThan > 0 || p.ThinToDaily
+}
+
+// HistoryVersion describes a single saved version of a resource
+type HistoryVersion struct {
+	ResourceVersion int64
+	Created         time.Time
+	Deleted         bool
+}
+
+// Prune returns the versions that should be removed according to the policy.
+// The versions must all belong to the same resource and be sorted newest first.
+func (p RetentionPolicy) Prune(versions []HistoryVersion, now time.Time) (remove []HistoryVersion, trash bool) {
+	if len(versions) == 0 {
+		return nil, false
+	}
+
+	// The whole resource is removed from the trash
+	latest := versions[0]
+	if latest.Deleted && p.PurgeTrashAfter > 0 && now.Sub(latest.Created) >= p.PurgeTrashAfter {
+		return versions, true
+	}
+	if !p.prunesHistory() {
+		return nil, false
+	}
+
+	days := make(map[string]bool)
+	for i, v := range versions {
+		day := v.Created.UTC().Format(time.DateOnly)
+		firstInDay := !days[day]
+		days[day] = true
+
+		switch {
+		case i == 0:
+			continue // the current value
+		case p.KeepVersions > 0 && i < p.KeepVersions:
+			continue
+		case p.KeepNewerThan > 0 && now.Sub(v.Created) < p.KeepNewerThan:
+			continue
+		case p.ThinToDaily && firstInDay:
+			continue
+		}
+		remove = append(remove, v)
+	}
+	return remove, false
+}
+
+// FindRetentionPolicy returns the policy for a group/resource
+func FindRetentionPolicy(policies []RetentionPolicy, group, resource string) (RetentionPolicy, bool) {
+	for _, p := range policies {
+		if p.Group == group && p.Resource == resource {
+			return p, true
+		}
+	}
+	return RetentionPolicy{}, false
+}
+
+// HistoryCompactionBackend is implemented by backends that can enforce retention policies
+type HistoryCompactionBackend interface {
+	// CompactHistory applies the policy to every resource of the policy group/resource (in all namespaces).
+	// When dryRun is true, the result describes what would be removed without changing anything
+	CompactHistory(ctx context.Context, policy RetentionPolicy, now time.Time, dryRun bool) (CompactionResult, error)
+}
+
+// CompactionResult summarizes compacting one group/resource
+type CompactionResult struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+
+	// Number of distinct resources (including trash) that were checked
+	Resources int64 `json:"resources"`
+
+	// Number of history rows that were checked
+	Versions int64 `json:"versions"`
+
+	// Number of historical versions removed
+	RemovedVersions int64 `json:"removedVersions"`
+
+	// Number of resources removed from the trash (including all their versions)
+	PurgedTrash int64 `json:"purgedTrash"`
+
+	// Set when the unreferenced blobs were collected
+	Blobs *BlobGCResult `json:"blobs,omitempty"`
+}
+
+// CompactionReport is the result of applying all retention policies once
+type CompactionReport struct {
+	DryRun   bool               `json:"dryRun"`
+	Started  time.Time          `json:"started"`
+	Duration time.Duration      `json:"duration"`
+	Results  []CompactionResult `json:"results"`
+}
+
+// RetentionOptions configures the background history compactor in the resource server
+type RetentionOptions struct {
+	Policies []RetentionPolicy
+
+	// How often the policies are applied
+	Interval time.Duration
+
+	// Only report what would be removed
+	DryRun bool
+}
+
+type HistoryCompactorOptions struct {
+	RetentionOptions
+
+	Backend HistoryCompactionBackend
+	Blobs   BlobGarbageCollector // optional
+	Reg     prometheus.Registerer
+	Log     *slog.Logger
+}
+
+// HistoryCompactor periodically applies retention policies to a backend
+type HistoryCompactor struct {
+	opts HistoryCompactorOptions
+	now  func() time.Time
+
+	removed  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	lastRun  prometheus.Gauge
+}
+
+func NewHistoryCompactor(opts HistoryCompactorOptions) (*HistoryCompactor, error) {
+	if opts.Backend == nil {
+		return nil, fmt.Errorf("missing backend")
+	}
+	if opts.Log == nil {
+		opts.Log = slog.Default().With("logger", "history-compactor")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+
+	policies := make([]RetentionPolicy, 0, len(opts.Policies))
+	for _, p := range opts.Policies {
+		if p.Group == "" || p.Resource == "" {
+			return nil, fmt.Errorf("retention policy requires a group and resource")
+		}
+		if p.KeepVersions < 0 || p.KeepNewerThan < 0 || p.PurgeTrashAfter < 0 || p.BlobGracePeriod < 0 {
+			return nil, fmt.Errorf("invalid retention policy for %s.%s", p.Resource, p.Group)
+		}
+		if p.Enabled() {
+			policies = append(policies, p)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Group == policies[j].Group {
+			return policies[i].Resource < policies[j].Resource
+		}
+		return policies[i].Group < policies[j].Group
+	})
+	opts.Policies = policies
+
+	return &HistoryCompactor{
+		opts: opts,
+		now:  time.Now,
+		removed: promauto.With(opts.Reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "storage_server",
+			Name:      "history_compaction_removed_total",
+			Help:      "Number of history versions, trashed resources and blobs removed by the retention policies",
+		}, []string{"group", "resource", "kind"}),
+		duration: promauto.With(opts.Reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:                   "storage_server",
+			Name:                        "history_compaction_duration_seconds",
+			Help:                        "Time spent applying a retention policy",
+			NativeHistogramBucketFactor: 1.1,
+		}, []string{"group", "resource", "status"}),
+		lastRun: promauto.With(opts.Reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: "storage_server",
+			Name:      "history_compaction_last_run_timestamp_seconds",
+			Help:      "When the retention policies were last applied",
+		}),
+	}, nil
+}
+
+// Run applies the policies on every interval until the context is done
+func (c *HistoryCompactor) Run(ctx context.Context) {
+	if len(c.opts.Policies) == 0 {
+		return
+	}
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := c.Compact(ctx)
+			if err != nil {
+				c.opts.Log.Error("history compaction failed", "error", err)
+				continue
+			}
+			for _, r := range report.Results {
+				c.opts.Log.Info("history compaction",
+					"group", r.Group,
+					"resource", r.Resource,
+					"dryRun", report.DryRun,
+					"resources", r.Resources,
+					"versions", r.Versions,
+					"removedVersions", r.RemovedVersions,
+					"purgedTrash", r.PurgedTrash)
+				if r.Blobs != nil {
+					c.opts.Log.Info("blob garbage collection",
+						"group", r.Group,
+						"resource", r.Resource,
+						"dryRun", report.DryRun,
+						"blobs", r.Blobs.Blobs,
+						"references", r.Blobs.References,
+						"shared", r.Blobs.Shared,
+						"removed", r.Blobs.Removed)
+				}
+			}
+		}
+	}
+}
+
+// Compact applies every policy once.  Unreferenced blobs are collected after the history
+// is compacted, since removing versions may leave blobs without any reference.
+func (c *HistoryCompactor) Compact(ctx context.Context) (*CompactionReport, error) {
+	report := &CompactionReport{
+		DryRun:  c.opts.DryRun,
+		Started: c.now(),
+	}
+	for _, p := range c.opts.Policies {
+		start := time.Now()
+		res, err := c.compactPolicy(ctx, p, report.Started)
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		c.duration.WithLabelValues(p.Group, p.Resource, status).Observe(time.Since(start).Seconds())
+		if err != nil {
+			return report, fmt.Errorf("compacting %s.%s: %w", p.Resource, p.Group, err)
+		}
+		if !c.opts.DryRun {
+			c.removed.WithLabelValues(p.Group, p.Resource, "version").Add(float64(res.RemovedVersions))
+			c.removed.WithLabelValues(p.Group, p.Resource, "trash").Add(float64(res.PurgedTrash))
+			if res.Blobs != nil {
+				c.removed.WithLabelValues(p.Group, p.Resource, "blob").Add(float64(res.Blobs.Removed))
+			}
+		}
+		report.Results = append(report.Results, res)
+	}
+	report.Duration = time.Since(report.Started)
+	c.lastRun.SetToCurrentTime()
+	return report, nil
+}
+
+func (c *HistoryCompactor) compactPolicy(ctx context.Context, p RetentionPolicy, now time.Time) (CompactionResult, error) {
+	res := CompactionResult{Group: p.Group, Resource: p.Resource}
+	if p.prunesHistory() || p.PurgeTrashAfter > 0 {
+		var err error
+		res, err = c.opts.Backend.CompactHistory(ctx, p, now, c.opts.DryRun)
+		if err != nil {
+			return res, err
+		}
+	}
+	if p.BlobGracePeriod > 0 && c.opts.Blobs != nil {
+		blobs, err := c.opts.Blobs.GarbageCollectBlobs(ctx, p.Group, p.Resource, now.Add(-p.BlobGracePeriod), c.opts.DryRun)
+		if err != nil {
+			return res, fmt.Errorf("collecting blobs: %w", err)
+		}
+		res.Blobs = &blobs
+	}
+	return res, nil
+}
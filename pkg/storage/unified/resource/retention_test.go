@@ -0,0 +1,234 @@
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestRetentionPolicy_Prune(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	version := func(rv int64, ago time.Duration) HistoryVersion {
+		return HistoryVersion{ResourceVersion: rv, Created: now.Add(-ago)}
+	}
+	rvs := func(versions []HistoryVersion) []int64 {
+		out := make([]int64, len(versions))
+		for i, v := range versions {
+			out[i] = v.ResourceVersion
+		}
+		return out
+	}
+
+	// newest first
+	history := []HistoryVersion{
+		version(6, time.Hour),
+		version(5, 2*time.Hour),
+		version(4, 25*time.Hour),
+		version(3, 26*time.Hour),
+		version(2, 72*time.Hour),
+		version(1, 73*time.Hour),
+	}
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		remove []int64
+	}{
+		{
+			name:   "no rules",
+			policy: RetentionPolicy{},
+			remove: []int64{},
+		},
+		{
+			name:   "keep versions",
+			policy: RetentionPolicy{KeepVersions: 3},
+			remove: []int64{3, 2, 1},
+		},
+		{
+			name:   "keep newer than",
+			policy: RetentionPolicy{KeepNewerThan: 24 * time.Hour},
+			remove: []int64{4, 3, 2, 1},
+		},
+		{
+			name:   "thin to daily",
+			policy: RetentionPolicy{ThinToDaily: true},
+			remove: []int64{5, 3, 1},
+		},
+		{
+			name:   "combined",
+			policy: RetentionPolicy{KeepVersions: 2, ThinToDaily: true},
+			remove: []int64{3, 1},
+		},
+		{
+			name:   "trash is only purged for deleted resources",
+			policy: RetentionPolicy{PurgeTrashAfter: time.Minute},
+			remove: []int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remove, trash := tt.policy.Prune(history, now)
+			require.False(t, trash)
+			require.Equal(t, tt.remove, rvs(remove))
+		})
+	}
+
+	t.Run("purge trash", func(t *testing.T) {
+		deleted := []HistoryVersion{
+			{ResourceVersion: 3, Created: now.Add(-48 * time.Hour), Deleted: true},
+			version(2, 50*time.Hour),
+			version(1, 51*time.Hour),
+		}
+
+		remove, trash := RetentionPolicy{PurgeTrashAfter: 24 * time.Hour}.Prune(deleted, now)
+		require.True(t, trash)
+		require.Equal(t, []int64{3, 2, 1}, rvs(remove))
+
+		// not in the trash long enough
+		remove, trash = RetentionPolicy{PurgeTrashAfter: 72 * time.Hour, KeepVersions: 1}.Prune(deleted, now)
+		require.False(t, trash)
+		require.Equal(t, []int64{2, 1}, rvs(remove))
+	})
+}
+
+func TestHistoryCompactor(t *testing.T) {
+	ctx := context.Background()
+	backend := setupTestStorageBackend(t)
+
+	obj, err := createTestObjectWithName("item", "apps", "v1")
+	require.NoError(t, err)
+	meta, err := utils.MetaAccessor(obj)
+	require.NoError(t, err)
+	event := WriteEvent{
+		Type: resourcepb.WatchEvent_ADDED,
+		Key: &resourcepb.ResourceKey{
+			Namespace: "default",
+			Group:     "apps",
+			Resource:  "resources",
+			Name:      "item",
+		},
+		Value:  objectToJSONBytes(t, obj),
+		Object: meta,
+	}
+	var rvs []int64
+	for i := 0; i < 3; i++ {
+		rv, err := backend.WriteEvent(ctx, event)
+		require.NoError(t, err)
+		rvs = append(rvs, rv)
+		event.Type = resourcepb.WatchEvent_MODIFIED
+		event.PreviousRV = rv
+	}
+
+	countHistory := func() int {
+		count := 0
+		_, err := backend.ListHistory(ctx, &resourcepb.ListRequest{
+			Source: resourcepb.ListRequest_HISTORY,
+			Options: &resourcepb.ListOptions{
+				Key: event.Key,
+			},
+		}, func(iter ListIterator) error {
+			for iter.Next() {
+				count++
+			}
+			return iter.Error()
+		})
+		require.NoError(t, err)
+		return count
+	}
+	require.Equal(t, 3, countHistory())
+
+	newCompactor := func(dryRun bool) *HistoryCompactor {
+		c, err := NewHistoryCompactor(HistoryCompactorOptions{
+			RetentionOptions: RetentionOptions{
+				Policies: []RetentionPolicy{
+					{Group: "apps", Resource: "resources", KeepVersions: 1},
+					{Group: "apps", Resource: "disabled"},
+				},
+				DryRun: dryRun,
+			},
+			Backend: backend,
+			Reg:     prometheus.NewRegistry(),
+		})
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		report, err := newCompactor(true).Compact(ctx)
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, []CompactionResult{{
+			Group:           "apps",
+			Resource:        "resources",
+			Resources:       1,
+			Versions:        3,
+			RemovedVersions: 2,
+		}}, report.Results)
+		require.Equal(t, 3, countHistory())
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		report, err := newCompactor(false).Compact(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), report.Results[0].RemovedVersions)
+		require.Equal(t, 1, countHistory())
+
+		rsp := backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: event.Key})
+		require.Nil(t, rsp.Error)
+		require.Equal(t, rvs[2], rsp.ResourceVersion)
+	})
+
+	t.Run("collect blobs", func(t *testing.T) {
+		blobs := &fakeBlobGarbageCollector{}
+		c, err := NewHistoryCompactor(HistoryCompactorOptions{
+			RetentionOptions: RetentionOptions{
+				Policies: []RetentionPolicy{
+					{Group: "apps", Resource: "resources", BlobGracePeriod: time.Hour},
+				},
+			},
+			Backend: backend,
+			Blobs:   blobs,
+			Reg:     prometheus.NewRegistry(),
+		})
+		require.NoError(t, err)
+		c.now = func() time.Time { return time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC) }
+
+		report, err := c.Compact(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []CompactionResult{{
+			Group:    "apps",
+			Resource: "resources",
+			Blobs:    &BlobGCResult{Blobs: 2, Removed: 1},
+		}}, report.Results)
+		require.Equal(t, "apps/resources", blobs.collected)
+		require.Equal(t, time.Date(2025, 6, 10, 11, 0, 0, 0, time.UTC), blobs.before)
+		require.Equal(t, 1, countHistory())
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		_, err := NewHistoryCompactor(HistoryCompactorOptions{
+			RetentionOptions: RetentionOptions{
+				Policies: []RetentionPolicy{{Resource: "resources", KeepVersions: 1}},
+			},
+			Backend: backend,
+		})
+		require.Error(t, err)
+	})
+}
+
+type fakeBlobGarbageCollector struct {
+	collected string
+	before    time.Time
+}
+
+func (f *fakeBlobGarbageCollector) GarbageCollectBlobs(_ context.Context, group, resource string, before time.Time, _ bool) (BlobGCResult, error) {
+	f.collected = group + "/" + resource
+	f.before = before
+	return BlobGCResult{Blobs: 2, Removed: 1}, nil
+}
@@ -1058,6 +1058,12 @@ func AsResourceKey(ns string, t string) (*resourcepb.ResourceKey, error) {
 			Group:     "playlist.grafana.app",
 			Resource:  "playlists",
 		}, nil
+	case "alertrule", "alertrules":
+		return &resourcepb.ResourceKey{
+			Namespace: ns,
+			Group:     "rules.alerting.grafana.app",
+			Resource:  "alertrules",
+		}, nil
 	}
 
 	return nil, fmt.Errorf("unknown resource type")
@@ -3,7 +3,9 @@ package resource
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"iter"
 	"log/slog"
 	"net/http"
@@ -36,6 +38,9 @@ type ResourceServer interface {
 	resourcepb.ManagedObjectIndexServer
 	resourcepb.BlobStoreServer
 	resourcepb.DiagnosticsServer
+	resourcepb.AuditLogServer
+	resourcepb.ResourceQuotasServer
+	resourcepb.BackupStoreServer
 }
 
 type ListIterator interface {
@@ -246,6 +251,24 @@ type ResourceServerOptions struct {
 
 	// Enable strong consistency for searches. When enabled, index is always updated with latest changes before search.
 	SearchAfterWrite bool
+
+	// History retention policies, applied in the background when the backend supports compaction
+	Retention RetentionOptions
+
+	// How often watchers that allow bookmarks receive a BOOKMARK event with the
+	// latest resource version. Defaults to one minute
+	WatchBookmarkInterval time.Duration
+
+	// Receive an event for every create, update and delete.  The first sink that
+	// implements AuditQuerier is used to answer audit queries
+	AuditSinks []AuditSink
+
+	// Default quota limits for every namespace.  Limits for a namespace can be changed with
+	// the quotas API when the backend supports quotas
+	Quotas []QuotaLimit
+
+	// Writes the archives returned by the backup API, the API is not available when nil
+	Backup BackupWriter
 }
 
 func NewResourceServer(opts ResourceServerOptions) (*server, error) {
@@ -280,6 +303,10 @@ func NewResourceServer(opts ResourceServerOptions) (*server, error) {
 		opts.QOSQueue = scheduler.NewNoopQueue()
 	}
 
+	if opts.WatchBookmarkInterval <= 0 {
+		opts.WatchBookmarkInterval = time.Minute
+	}
+
 	if opts.QOSConfig.Timeout == 0 {
 		opts.QOSConfig.Timeout = 30 * time.Second
 	}
@@ -303,9 +330,11 @@ func NewResourceServer(opts ResourceServerOptions) (*server, error) {
 				return nil, err
 			}
 
+			references, _ := opts.Backend.(BlobReferenceCounter)
 			blobstore, err = NewCDKBlobSupport(ctx, CDKBlobSupportOptions{
-				Tracer: opts.Tracer,
-				Bucket: NewInstrumentedBucket(bucket, opts.Reg, opts.Tracer),
+				Tracer:     opts.Tracer,
+				Bucket:     NewInstrumentedBucket(bucket, opts.Reg, opts.Tracer),
+				References: references,
 			})
 			if err != nil {
 				return nil, err
@@ -339,6 +368,47 @@ func NewResourceServer(opts ResourceServerOptions) (*server, error) {
 		reg:              opts.Reg,
 		queue:            opts.QOSQueue,
 		queueConfig:      opts.QOSConfig,
+		bookmarkInterval: opts.WatchBookmarkInterval,
+		auditSinks:       opts.AuditSinks,
+		backup:           opts.Backup,
+	}
+
+	for _, sink := range opts.AuditSinks {
+		if querier, ok := sink.(AuditQuerier); ok && s.auditQuerier == nil {
+			s.auditQuerier = querier
+		}
+		if tx, ok := sink.(TransactionalAuditSink); ok && any(sink) == any(opts.Backend) && tx.AuditsWrites() {
+			s.auditInTx = tx
+		}
+	}
+
+	if quotaBackend, ok := opts.Backend.(QuotaBackend); ok {
+		var err error
+		s.quotas, err = newQuotaSupport(quotaBackend, opts.Quotas)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(opts.Quotas) > 0 {
+		return nil, fmt.Errorf("the storage backend does not support quotas")
+	}
+
+	if len(opts.Retention.Policies) > 0 {
+		backend, ok := opts.Backend.(HistoryCompactionBackend)
+		if !ok {
+			return nil, fmt.Errorf("the storage backend does not support history retention policies")
+		}
+		var err error
+		blobs, _ := blobstore.(BlobGarbageCollector)
+		s.compactor, err = NewHistoryCompactor(HistoryCompactorOptions{
+			RetentionOptions: opts.Retention,
+			Backend:          backend,
+			Blobs:            blobs,
+			Reg:              opts.Reg,
+			Log:              logger.With("component", "history-compactor"),
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if opts.Search.Resources != nil {
@@ -381,6 +451,20 @@ type server struct {
 	cancel      context.CancelFunc
 	broadcaster Broadcaster[*WrittenEvent]
 
+	// Applies the history retention policies in the background
+	compactor *HistoryCompactor
+
+	// Audit trail for all writes
+	auditSinks   []AuditSink
+	auditInTx    TransactionalAuditSink // the backend saves the audit events with the changes
+	auditQuerier AuditQuerier
+
+	// Limits the objects created in each namespace
+	quotas *quotaSupport
+
+	// Writes backup archives
+	backup BackupWriter
+
 	// init checking
 	once    sync.Once
 	initErr error
@@ -389,6 +473,7 @@ type server struct {
 	reg              prometheus.Registerer
 	queue            QOSEnqueuer
 	queueConfig      QueueConfig
+	bookmarkInterval time.Duration
 }
 
 // Init implements ResourceServer.
@@ -412,6 +497,10 @@ func (s *server) Init(ctx context.Context) error {
 			s.initErr = s.initWatcher()
 		}
 
+		if s.initErr == nil && s.compactor != nil {
+			go s.compactor.Run(s.ctx)
+		}
+
 		if s.initErr != nil {
 			s.log.Error("error running resource server init", "error", s.initErr)
 		}
@@ -434,6 +523,15 @@ func (s *server) Stop(ctx context.Context) error {
 	// Stops the streaming
 	s.cancel()
 
+	// The backend is stopped by the lifecycle hooks
+	for _, sink := range s.auditSinks {
+		if closer, ok := sink.(io.Closer); ok && any(sink) != any(s.backend) {
+			if err := closer.Close(); err != nil {
+				s.log.Warn("failed to close audit sink", "error", err)
+			}
+		}
+	}
+
 	// mark the value as done
 	if stopFailed {
 		return s.initErr
@@ -670,12 +768,27 @@ func (s *server) create(ctx context.Context, user claims.AuthInfo, req *resource
 		return rsp, nil
 	}
 
+	if s.quotas != nil {
+		if _, e := s.quotas.check(ctx, req.Key.Namespace, []WriteEvent{*event}); e != nil {
+			rsp.Error = e
+			return rsp, nil
+		}
+	}
+
+	if e := s.prepareAudit(user, event); e != nil {
+		rsp.Error = e
+		return rsp, nil
+	}
+
 	// If the resource already exists, the create will return an already exists error that is remapped appropriately by AsErrorResult.
 	// This also benefits from ACID behaviours on our databases, so we avoid race conditions.
 	var err error
 	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, *event)
 	if err != nil {
 		rsp.Error = AsErrorResult(err)
+	} else {
+		s.recordAudit(ctx, event, rsp.ResourceVersion)
+		s.recordQuotaUsage(req.Key.Namespace, *event)
 	}
 	s.log.Debug("server.WriteEvent", "type", event.Type, "rv", rsp.ResourceVersion, "previousRV", event.PreviousRV, "group", event.Key.Group, "namespace", event.Key.Namespace, "name", event.Key.Name, "resource", event.Key.Resource)
 	return rsp, nil
@@ -740,11 +853,27 @@ func (s *server) update(ctx context.Context, user claims.AuthInfo, req *resource
 
 	event.Type = resourcepb.WatchEvent_MODIFIED
 	event.PreviousRV = latest.ResourceVersion
+	event.PreviousSize = int64(len(latest.Value))
+
+	if s.quotas != nil {
+		if _, e := s.quotas.check(ctx, req.Key.Namespace, []WriteEvent{*event}); e != nil {
+			rsp.Error = e
+			return rsp, nil
+		}
+	}
+
+	if e := s.prepareAudit(user, event); e != nil {
+		rsp.Error = e
+		return rsp, nil
+	}
 
 	var err error
 	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, *event)
 	if err != nil {
 		rsp.Error = AsErrorResult(err)
+	} else {
+		s.recordAudit(ctx, event, rsp.ResourceVersion)
+		s.recordQuotaUsage(req.Key.Namespace, *event)
 	}
 	return rsp, nil
 }
@@ -816,15 +945,37 @@ func (s *server) delete(ctx context.Context, user claims.AuthInfo, req *resource
 		return rsp, nil
 	}
 
+	event, err := s.newDeleteEvent(user, req.Key, latest)
+	if err != nil {
+		return nil, err
+	}
+	if e := s.prepareAudit(user, event); e != nil {
+		rsp.Error = e
+		return rsp, nil
+	}
+
+	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, *event)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+	} else {
+		s.recordAudit(ctx, event, rsp.ResourceVersion)
+		s.recordQuotaUsage(req.Key.Namespace, *event)
+	}
+	return rsp, nil
+}
+
+// newDeleteEvent creates the deletion marker event for the latest saved value
+func (s *server) newDeleteEvent(user claims.AuthInfo, key *resourcepb.ResourceKey, latest *BackendReadResponse) (*WriteEvent, error) {
 	now := metav1.NewTime(time.UnixMilli(s.now()))
-	event := WriteEvent{
-		Key:        req.Key,
-		Type:       resourcepb.WatchEvent_DELETED,
-		PreviousRV: latest.ResourceVersion,
-		GUID:       uuid.New().String(),
+	event := &WriteEvent{
+		Key:          key,
+		Type:         resourcepb.WatchEvent_DELETED,
+		PreviousRV:   latest.ResourceVersion,
+		PreviousSize: int64(len(latest.Value)),
+		GUID:         uuid.New().String(),
 	}
 	marker := &unstructured.Unstructured{}
-	err = json.Unmarshal(latest.Value, marker)
+	err := json.Unmarshal(latest.Value, marker)
 	if err != nil {
 		return nil, apierrors.NewBadRequest(
 			fmt.Sprintf("unable to read previous object, %v", err))
@@ -852,12 +1003,7 @@ func (s *server) delete(ctx context.Context, user claims.AuthInfo, req *resource
 		return nil, apierrors.NewBadRequest(
 			fmt.Sprintf("unable creating deletion marker, %v", err))
 	}
-
-	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, event)
-	if err != nil {
-		rsp.Error = AsErrorResult(err)
-	}
-	return rsp, nil
+	return event, nil
 }
 
 func (s *server) Read(ctx context.Context, req *resourcepb.ReadRequest) (*resourcepb.ReadResponse, error) {
@@ -1119,6 +1265,11 @@ func (s *server) Watch(req *resourcepb.WatchRequest, srv resourcepb.ResourceStor
 		return apierrors.NewUnauthorized("not allowed to list anything") // ?? or a single error?
 	}
 
+	filter, err := newWatchFilter(req.Options)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
 	// Start listening -- this will buffer any changes that happen while we backfill.
 	// If events are generated faster than we can process them, then some events will be dropped.
 	// TODO: Think of a way to allow the client to catch up.
@@ -1177,6 +1328,9 @@ func (s *server) Watch(req *resourcepb.WatchRequest, srv resourcepb.ResourceStor
 				if err := iter.Error(); err != nil {
 					return err
 				}
+				if !filter.matches(iter.Value()) {
+					continue
+				}
 				if err := srv.Send(&resourcepb.WatchEvent{
 					Type: resourcepb.WatchEvent_ADDED,
 					Resource: &resourcepb.WatchEvent_Resource{
@@ -1213,66 +1367,140 @@ func (s *server) Watch(req *resourcepb.WatchRequest, srv resourcepb.ResourceStor
 	default:
 		since = req.Since
 	}
+
+	// The client is resuming from a resource version: replay the stored events
+	// instead of relying on the (in-memory) broadcaster cache. Events that are
+	// also delivered by the broadcaster are skipped as they are not newer than since.
+	if !req.SendInitialEvents && req.Since > 0 {
+		if replay, ok := s.backend.(WatchReplayBackend); ok {
+			replayedRV := since
+			err = replay.ReplayEvents(ctx, req.Options.Key, since, func(event *WrittenEvent) error {
+				if event.ResourceVersion > replayedRV {
+					replayedRV = event.ResourceVersion
+				}
+				return s.sendWatchEvent(ctx, srv, req.Options.Key, checker, filter, event)
+			})
+			var apistatus apierrors.APIStatus
+			if errors.As(err, &apistatus) {
+				// eg: the history was removed, the client must list again
+				return srv.Send(&resourcepb.WatchEvent{
+					Type:  resourcepb.WatchEvent_ERROR,
+					Error: AsErrorResult(err),
+				})
+			}
+			if err != nil {
+				return err
+			}
+			since = replayedRV
+		}
+	}
+
+	// Periodic bookmarks let the client resume from a recent resource version,
+	// even when none of the events are selected by the watch
+	var bookmarks <-chan time.Time
+	if req.AllowWatchBookmarks {
+		ticker := time.NewTicker(s.bookmarkInterval)
+		defer ticker.Stop()
+		bookmarks = ticker.C
+	}
+	latestRV := since   // the latest resource version seen by this watch
+	bookmarkRV := since // the resource version of the last bookmark
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 
+		case <-bookmarks:
+			if latestRV <= bookmarkRV {
+				continue
+			}
+			if err := srv.Send(&resourcepb.WatchEvent{
+				Type: resourcepb.WatchEvent_BOOKMARK,
+				Resource: &resourcepb.WatchEvent_Resource{
+					Version: latestRV,
+				},
+			}); err != nil {
+				return err
+			}
+			bookmarkRV = latestRV
+
 		case event, ok := <-stream:
 			if !ok {
 				s.log.Debug("watch events closed")
 				return nil
 			}
 			s.log.Debug("Server Broadcasting", "type", event.Type, "rv", event.ResourceVersion, "previousRV", event.PreviousRV, "group", event.Key.Group, "namespace", event.Key.Namespace, "resource", event.Key.Resource, "name", event.Key.Name)
-			if event.ResourceVersion > since && matchesQueryKey(req.Options.Key, event.Key) {
-				if !checker(event.Key.Name, event.Folder) {
-					continue
-				}
-
-				value := event.Value
-				// remove the delete marker stored in the value for deleted objects
-				if event.Type == resourcepb.WatchEvent_DELETED {
-					value = []byte{}
+			if event.ResourceVersion > since {
+				if event.ResourceVersion > latestRV && matchesQueryKey(req.Options.Key, event.Key) {
+					latestRV = event.ResourceVersion
 				}
-				resp := &resourcepb.WatchEvent{
-					Timestamp: event.Timestamp,
-					Type:      event.Type,
-					Resource: &resourcepb.WatchEvent_Resource{
-						Value:   value,
-						Version: event.ResourceVersion,
-					},
-				}
-				if event.PreviousRV > 0 {
-					prevObj, err := s.Read(ctx, &resourcepb.ReadRequest{Key: event.Key, ResourceVersion: event.PreviousRV})
-					if err != nil {
-						// This scenario should never happen, but if it does, we should log it and continue
-						// sending the event without the previous object. The client will decide what to do.
-						s.log.Error("error reading previous object", "key", event.Key, "resource_version", event.PreviousRV, "error", prevObj.Error)
-					} else {
-						if prevObj.ResourceVersion != event.PreviousRV {
-							s.log.Error("resource version mismatch", "key", event.Key, "resource_version", event.PreviousRV, "actual", prevObj.ResourceVersion)
-							return fmt.Errorf("resource version mismatch")
-						}
-						resp.Previous = &resourcepb.WatchEvent_Resource{
-							Value:   prevObj.Value,
-							Version: prevObj.ResourceVersion,
-						}
-					}
-				}
-				if err := srv.Send(resp); err != nil {
+				if err := s.sendWatchEvent(ctx, srv, req.Options.Key, checker, filter, event); err != nil {
 					return err
 				}
+			}
+		}
+	}
+}
 
-				if s.storageMetrics != nil {
-					// record latency - resource version is a unix timestamp in microseconds so we convert to seconds
-					latencySeconds := float64(time.Now().UnixMicro()-event.ResourceVersion) / 1e6
-					if latencySeconds > 0 {
-						s.storageMetrics.WatchEventLatency.WithLabelValues(event.Key.Resource).Observe(latencySeconds)
-					}
-				}
+// sendWatchEvent sends the event to the watcher when it matches the key, is
+// allowed by the checker and is selected by the filter.
+func (s *server) sendWatchEvent(ctx context.Context, srv resourcepb.ResourceStore_WatchServer, key *resourcepb.ResourceKey, checker claims.ItemChecker, filter *watchFilter, event *WrittenEvent) error {
+	if !matchesQueryKey(key, event.Key) {
+		return nil
+	}
+	if !checker(event.Key.Name, event.Folder) {
+		return nil
+	}
+
+	value := event.Value
+	// remove the delete marker stored in the value for deleted objects
+	if event.Type == resourcepb.WatchEvent_DELETED {
+		value = []byte{}
+	}
+	resp := &resourcepb.WatchEvent{
+		Timestamp: event.Timestamp,
+		Type:      event.Type,
+		Resource: &resourcepb.WatchEvent_Resource{
+			Value:   value,
+			Version: event.ResourceVersion,
+		},
+	}
+	if event.PreviousRV > 0 {
+		prevObj, err := s.Read(ctx, &resourcepb.ReadRequest{Key: event.Key, ResourceVersion: event.PreviousRV})
+		if err != nil {
+			// This scenario should never happen, but if it does, we should log it and continue
+			// sending the event without the previous object. The client will decide what to do.
+			s.log.Error("error reading previous object", "key", event.Key, "resource_version", event.PreviousRV, "error", prevObj.Error)
+		} else {
+			if prevObj.ResourceVersion != event.PreviousRV {
+				s.log.Error("resource version mismatch", "key", event.Key, "resource_version", event.PreviousRV, "actual", prevObj.ResourceVersion)
+				return fmt.Errorf("resource version mismatch")
+			}
+			resp.Previous = &resourcepb.WatchEvent_Resource{
+				Value:   prevObj.Value,
+				Version: prevObj.ResourceVersion,
 			}
 		}
 	}
+
+	// Send the event when either the new or the previous value is selected,
+	// so the client can turn transitions into ADDED or DELETED events
+	if !filter.matches(event.Value) && (resp.Previous == nil || !filter.matches(resp.Previous.Value)) {
+		return nil
+	}
+
+	if err := srv.Send(resp); err != nil {
+		return err
+	}
+
+	if s.storageMetrics != nil {
+		// record latency - resource version is a unix timestamp in microseconds so we convert to seconds
+		latencySeconds := float64(time.Now().UnixMicro()-event.ResourceVersion) / 1e6
+		if latencySeconds > 0 {
+			s.storageMetrics.WatchEventLatency.WithLabelValues(event.Key.Resource).Observe(latencySeconds)
+		}
+	}
+	return nil
 }
 
 func (s *server) Search(ctx context.Context, req *resourcepb.ResourceSearchRequest) (*resourcepb.ResourceSearchResponse, error) {
@@ -1297,7 +1525,14 @@ func (s *server) GetStats(ctx context.Context, req *resourcepb.ResourceStatsRequ
 		}
 		return nil, fmt.Errorf("search index not configured")
 	}
-	return s.search.GetStats(ctx, req)
+	rsp, err := s.search.GetStats(ctx, req)
+	if err != nil || rsp.Error != nil || s.quotas == nil {
+		return rsp, err
+	}
+	if err = s.quotas.addStats(ctx, req.Namespace, rsp); err != nil {
+		rsp.Error = AsErrorResult(err)
+	}
+	return rsp, nil
 }
 
 func (s *server) ListManagedObjects(ctx context.Context, req *resourcepb.ListManagedObjectsRequest) (*resourcepb.ListManagedObjectsResponse, error) {
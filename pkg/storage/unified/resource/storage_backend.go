@@ -37,7 +37,11 @@ type kvStorageBackend struct {
 	log        logging.Logger
 }
 
-var _ StorageBackend = &kvStorageBackend{}
+var (
+	_ StorageBackend       = &kvStorageBackend{}
+	_ TransactionalBackend = &kvStorageBackend{}
+	_ WatchReplayBackend   = &kvStorageBackend{}
+)
 
 func NewKvStorageBackend(kv KV) *kvStorageBackend {
 	s, err := snowflake.NewNode(rand.Int64N(1024))
@@ -153,6 +157,37 @@ func (k *kvStorageBackend) WriteEvent(ctx context.Context, event WriteEvent) (in
 	return rv, nil
 }
 
+// WriteEvents writes all events in a single KV batch.
+// The events are only visible to watchers once the batch has been committed.
+func (k *kvStorageBackend) WriteEvents(ctx context.Context, events []WriteEvent) ([]int64, error) {
+	batcher, ok := k.kv.(BatchKV)
+	if !ok {
+		return nil, fmt.Errorf("kv store does not support batch writes")
+	}
+
+	rvs := make([]int64, len(events))
+	err := batcher.Batch(ctx, func(kv KV) error {
+		// Same backend, but every write goes to the batch
+		tx := *k
+		tx.kv = kv
+		tx.dataStore = newDataStore(kv)
+		tx.metaStore = newMetadataStore(kv)
+		tx.eventStore = newEventStore(kv)
+		for i, event := range events {
+			rv, err := tx.WriteEvent(ctx, event)
+			if err != nil {
+				return &TransactionItemError{Index: i, Err: err}
+			}
+			rvs[i] = rv
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rvs, nil
+}
+
 func (k *kvStorageBackend) ReadResource(ctx context.Context, req *resourcepb.ReadRequest) *BackendReadResponse {
 	if req.Key == nil {
 		return &BackendReadResponse{Error: &resourcepb.ErrorResult{Code: http.StatusBadRequest, Message: "missing key"}}
@@ -352,7 +387,8 @@ func validateListHistoryRequest(req *resourcepb.ListRequest) error {
 	if key.Namespace == "" {
 		return fmt.Errorf("namespace is required")
 	}
-	if key.Name == "" {
+	// The trash can be listed for every name in a resource
+	if key.Name == "" && req.Source != resourcepb.ListRequest_TRASH {
 		return fmt.Errorf("name is required")
 	}
 	return nil
@@ -538,38 +574,30 @@ func (k *kvStorageBackend) ListHistory(ctx context.Context, req *resourcepb.List
 
 // processTrashEntries handles the special case of listing deleted items (trash)
 func (k *kvStorageBackend) processTrashEntries(ctx context.Context, req *resourcepb.ListRequest, fn func(ListIterator) error, historyKeys []DataKey, lastSeenRV int64, sortAscending bool, listRV int64) (int64, error) {
-	// Filter to only deleted entries
-	var deletedKeys []DataKey
+	// Find the latest delete event of each name
+	latestDeletes := make(map[string]DataKey)
 	for _, key := range historyKeys {
-		if key.Action == DataActionDeleted {
-			deletedKeys = append(deletedKeys, key)
+		if key.Action != DataActionDeleted {
+			continue
+		}
+		if latest, ok := latestDeletes[key.Name]; !ok || key.ResourceVersion > latest.ResourceVersion {
+			latestDeletes[key.Name] = key
 		}
 	}
 
-	// Check if the resource currently exists (is live)
-	// If it exists, don't return any trash entries
-	_, err := k.metaStore.GetLatestResourceKey(ctx, MetaGetRequestKey{
-		Namespace: req.Options.Key.Namespace,
-		Group:     req.Options.Key.Group,
-		Resource:  req.Options.Key.Resource,
-		Name:      req.Options.Key.Name,
-	})
-
+	// Only the resources that don't currently exist (are not live) are in the trash
 	var trashKeys []DataKey
-	if errors.Is(err, ErrNotFound) {
-		// Resource doesn't exist currently, so we can return the latest delete
-		// Find the latest delete event
-		var latestDelete *DataKey
-		for _, key := range deletedKeys {
-			if latestDelete == nil || key.ResourceVersion > latestDelete.ResourceVersion {
-				latestDelete = &key
-			}
-		}
-		if latestDelete != nil {
-			trashKeys = append(trashKeys, *latestDelete)
+	for name, latestDelete := range latestDeletes {
+		_, err := k.metaStore.GetLatestResourceKey(ctx, MetaGetRequestKey{
+			Namespace: req.Options.Key.Namespace,
+			Group:     req.Options.Key.Group,
+			Resource:  req.Options.Key.Resource,
+			Name:      name,
+		})
+		if errors.Is(err, ErrNotFound) {
+			trashKeys = append(trashKeys, latestDelete)
 		}
 	}
-	// If err != ErrNotFound, the resource exists, so no trash entries should be returned
 
 	// Apply version filtering
 	filteredKeys, err := filterHistoryKeysByVersion(trashKeys, req)
@@ -710,54 +738,97 @@ func (k *kvStorageBackend) WatchWriteEvents(ctx context.Context) (<-chan *Writte
 	notifierEvents := k.notifier.Watch(ctx, defaultWatchOptions())
 	go func() {
 		for event := range notifierEvents {
-			// fetch the data
-			dataReader, err := k.dataStore.Get(ctx, DataKey{
-				Namespace:       event.Namespace,
-				Group:           event.Group,
-				Resource:        event.Resource,
-				Name:            event.Name,
-				ResourceVersion: event.ResourceVersion,
-				Action:          event.Action,
-			})
-			if err != nil || dataReader == nil {
-				k.log.Error("failed to get data for event", "error", err)
-				continue
-			}
-			data, err := readAndClose(dataReader)
+			written, err := k.writtenEvent(ctx, event)
 			if err != nil {
-				k.log.Error("failed to read and close data for event", "error", err)
+				k.log.Error("failed to get data for event", "error", err)
 				continue
 			}
-			var t resourcepb.WatchEvent_Type
-			switch event.Action {
-			case DataActionCreated:
-				t = resourcepb.WatchEvent_ADDED
-			case DataActionUpdated:
-				t = resourcepb.WatchEvent_MODIFIED
-			case DataActionDeleted:
-				t = resourcepb.WatchEvent_DELETED
-			}
-
-			events <- &WrittenEvent{
-				Key: &resourcepb.ResourceKey{
-					Namespace: event.Namespace,
-					Group:     event.Group,
-					Resource:  event.Resource,
-					Name:      event.Name,
-				},
-				Type:            t,
-				Folder:          event.Folder,
-				Value:           data,
-				ResourceVersion: event.ResourceVersion,
-				PreviousRV:      event.PreviousRV,
-				Timestamp:       event.ResourceVersion / time.Second.Nanoseconds(), // convert to seconds
-			}
+			events <- written
 		}
 		close(events)
 	}()
 	return events, nil
 }
 
+// ReplayEvents replays the events stored after sinceRV that match the key, oldest first.
+// The events are kept, but the values of compacted versions are removed.
+func (k *kvStorageBackend) ReplayEvents(ctx context.Context, key *resourcepb.ResourceKey, sinceRV int64, fn func(*WrittenEvent) error) error {
+	check := NewReplayCheck(sinceRV)
+	for event, err := range k.eventStore.ListSince(ctx, sinceRV) {
+		if err != nil {
+			return err
+		}
+		if !matchesQueryKey(key, &resourcepb.ResourceKey{
+			Namespace: event.Namespace,
+			Group:     event.Group,
+			Resource:  event.Resource,
+			Name:      event.Name,
+		}) {
+			continue
+		}
+		written, err := k.writtenEvent(ctx, event)
+		if errors.Is(err, ErrNotFound) {
+			return NewResourceVersionTooOldError(sinceRV)
+		}
+		if err != nil {
+			return err
+		}
+		if err := check.Check(written); err != nil {
+			return err
+		}
+		if err := fn(written); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writtenEvent reads the value of a stored event.
+func (k *kvStorageBackend) writtenEvent(ctx context.Context, event Event) (*WrittenEvent, error) {
+	dataReader, err := k.dataStore.Get(ctx, DataKey{
+		Namespace:       event.Namespace,
+		Group:           event.Group,
+		Resource:        event.Resource,
+		Name:            event.Name,
+		ResourceVersion: event.ResourceVersion,
+		Action:          event.Action,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dataReader == nil {
+		return nil, fmt.Errorf("missing data for event %s/%s/%s/%s@%d", event.Namespace, event.Group, event.Resource, event.Name, event.ResourceVersion)
+	}
+	data, err := readAndClose(dataReader)
+	if err != nil {
+		return nil, err
+	}
+	var t resourcepb.WatchEvent_Type
+	switch event.Action {
+	case DataActionCreated:
+		t = resourcepb.WatchEvent_ADDED
+	case DataActionUpdated:
+		t = resourcepb.WatchEvent_MODIFIED
+	case DataActionDeleted:
+		t = resourcepb.WatchEvent_DELETED
+	}
+
+	return &WrittenEvent{
+		Key: &resourcepb.ResourceKey{
+			Namespace: event.Namespace,
+			Group:     event.Group,
+			Resource:  event.Resource,
+			Name:      event.Name,
+		},
+		Type:            t,
+		Folder:          event.Folder,
+		Value:           data,
+		ResourceVersion: event.ResourceVersion,
+		PreviousRV:      event.PreviousRV,
+		Timestamp:       event.ResourceVersion / time.Second.Nanoseconds(), // convert to seconds
+	}, nil
+}
+
 // GetResourceStats returns resource stats within the storage backend.
 // TODO: this isn't very efficient, we should use a more efficient algorithm.
 func (k *kvStorageBackend) GetResourceStats(ctx context.Context, namespace string, minCount int) ([]ResourceStats, error) {
@@ -809,3 +880,85 @@ func readAndClose(r io.ReadCloser) ([]byte, error) {
 	data, err := io.ReadAll(r)
 	return data, errors.Join(err, r.Close())
 }
+
+var _ HistoryCompactionBackend = &kvStorageBackend{}
+
+// CompactHistory removes the versions that are not kept by the retention policy.
+func (k *kvStorageBackend) CompactHistory(ctx context.Context, policy RetentionPolicy, now time.Time, dryRun bool) (CompactionResult, error) {
+	result := CompactionResult{Group: policy.Group, Resource: policy.Resource}
+	prefix := fmt.Sprintf("%s/%s/", policy.Group, policy.Resource)
+
+	// Keys are sorted by namespace/name and then resource version (oldest first)
+	var (
+		remove  []MetaDataKey
+		current []MetaDataKey
+	)
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		versions := make([]HistoryVersion, len(current))
+		for i, key := range current {
+			versions[len(current)-1-i] = HistoryVersion{
+				ResourceVersion: key.ResourceVersion,
+				Created:         time.UnixMilli(snowflake.ID(key.ResourceVersion).Time()),
+				Deleted:         key.Action == DataActionDeleted,
+			}
+		}
+		pruned, trash := policy.Prune(versions, now)
+		if trash {
+			result.PurgedTrash++
+		} else {
+			result.RemovedVersions += int64(len(pruned))
+		}
+		byRV := make(map[int64]MetaDataKey, len(current))
+		for _, key := range current {
+			byRV[key.ResourceVersion] = key
+		}
+		for _, v := range pruned {
+			remove = append(remove, byRV[v.ResourceVersion])
+		}
+		result.Resources++
+		current = current[:0]
+	}
+
+	for k, err := range k.kv.Keys(ctx, metaSection, ListOptions{
+		StartKey: prefix,
+		EndKey:   PrefixRangeEnd(prefix),
+	}) {
+		if err != nil {
+			return result, err
+		}
+		key, err := parseMetaDataKey(k)
+		if err != nil {
+			return result, err
+		}
+		if len(current) > 0 && !current[0].SameResource(key) {
+			flush()
+		}
+		current = append(current, key)
+		result.Versions++
+	}
+	flush()
+
+	if dryRun {
+		return result, nil
+	}
+	for _, key := range remove {
+		if err := k.kv.Delete(ctx, metaSection, key.String()); err != nil && !errors.Is(err, ErrNotFound) {
+			return result, fmt.Errorf("failed to delete metadata: %w", err)
+		}
+		err := k.dataStore.Delete(ctx, DataKey{
+			Namespace:       key.Namespace,
+			Group:           key.Group,
+			Resource:        key.Resource,
+			Name:            key.Name,
+			ResourceVersion: key.ResourceVersion,
+			Action:          key.Action,
+		})
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return result, fmt.Errorf("failed to delete data: %w", err)
+		}
+	}
+	return result, nil
+}
@@ -852,6 +852,35 @@ func TestKvStorageBackend_ListTrash_Success(t *testing.T) {
 	require.Equal(t, "test-resource", trashItems[0].name)
 	require.Equal(t, rv2, trashItems[0].resourceVersion)
 	require.Equal(t, objectToJSONBytes(t, testObj), trashItems[0].value)
+
+	// The trash of every name in the resource, live resources are not included
+	liveObj, err := createTestObjectWithName("live-resource", "apps", "test-data")
+	require.NoError(t, err)
+	liveMeta, err := utils.MetaAccessor(liveObj)
+	require.NoError(t, err)
+	_, err = backend.WriteEvent(ctx, WriteEvent{
+		Type: resourcepb.WatchEvent_ADDED,
+		Key: &resourcepb.ResourceKey{
+			Namespace: "default",
+			Group:     "apps",
+			Resource:  "resources",
+			Name:      "live-resource",
+		},
+		Value:  objectToJSONBytes(t, liveObj),
+		Object: liveMeta,
+	})
+	require.NoError(t, err)
+
+	listReq.Options.Key.Name = ""
+	var names []string
+	_, err = backend.ListHistory(ctx, listReq, func(iter ListIterator) error {
+		for iter.Next() {
+			names = append(names, iter.Name())
+		}
+		return iter.Error()
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test-resource"}, names)
 }
 
 func TestKvStorageBackend_GetResourceStats_Success(t *testing.T) {
@@ -0,0 +1,209 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// The maximum number of items that can be saved in a single transaction
+const maxTransactionItems = 1000
+
+// TransactionalBackend is implemented by backends that can save multiple events atomically
+type TransactionalBackend interface {
+	// WriteEvents saves every event, or none of them.  Watch events are only sent after
+	// the whole transaction has been committed.
+	// Return the revisionVersion for each event (in the same order)
+	WriteEvents(context.Context, []WriteEvent) ([]int64, error)
+}
+
+// TransactionItemError is returned when a specific event in a transaction can not be saved
+type TransactionItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *TransactionItemError) Error() string {
+	return fmt.Sprintf("transaction item %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *TransactionItemError) Unwrap() error {
+	return e.Err
+}
+
+func (s *server) Transaction(ctx context.Context, req *resourcepb.TransactionRequest) (*resourcepb.TransactionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.Transaction")
+	defer span.End()
+
+	rsp := &resourcepb.TransactionResponse{ErrorIndex: -1}
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return rsp, nil
+	}
+	if req.Namespace == "" {
+		rsp.Error = NewBadRequestError("transaction requires a namespace")
+		return rsp, nil
+	}
+	if len(req.Items) > maxTransactionItems {
+		rsp.Error = NewBadRequestError(fmt.Sprintf("too many items in transaction (max %d)", maxTransactionItems))
+		return rsp, nil
+	}
+
+	backend, ok := s.backend.(TransactionalBackend)
+	if !ok {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "The server backend does not support transactions",
+			Code:    http.StatusNotImplemented,
+		}
+		return rsp, nil
+	}
+
+	var (
+		res *resourcepb.TransactionResponse
+		err error
+	)
+	runErr := s.runInQueue(ctx, req.Namespace, func(queueCtx context.Context) {
+		res, err = s.transaction(queueCtx, user, backend, req)
+	})
+	if runErr != nil {
+		return HandleQueueError(runErr, func(e *resourcepb.ErrorResult) *resourcepb.TransactionResponse {
+			return &resourcepb.TransactionResponse{Error: e, ErrorIndex: -1}
+		})
+	}
+
+	return res, err
+}
+
+func (s *server) transaction(ctx context.Context, user claims.AuthInfo, backend TransactionalBackend, req *resourcepb.TransactionRequest) (*resourcepb.TransactionResponse, error) {
+	rsp := &resourcepb.TransactionResponse{ErrorIndex: -1}
+	if len(req.Items) == 0 {
+		return rsp, nil
+	}
+
+	// Validate every item before anything is written
+	events := make([]WriteEvent, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
+	for i, item := range req.Items {
+		event, e := s.newTransactionEvent(ctx, user, req.Namespace, item)
+		if e == nil {
+			// The backend reads the committed state, so each resource may only be changed once
+			id := SearchID(event.Key)
+			if seen[id] {
+				e = NewBadRequestError("duplicate resource in transaction: " + id)
+			}
+			seen[id] = true
+		}
+		if e == nil {
+			e = s.prepareAudit(user, event)
+		}
+		if e != nil {
+			rsp.Error = e
+			rsp.ErrorIndex = int32(i)
+			return rsp, nil
+		}
+		events[i] = *event
+	}
+
+	if s.quotas != nil {
+		if i, e := s.quotas.check(ctx, req.Namespace, events); e != nil {
+			rsp.Error = e
+			rsp.ErrorIndex = int32(i)
+			return rsp, nil
+		}
+	}
+
+	rvs, err := backend.WriteEvents(ctx, events)
+	if err != nil {
+		var itemErr *TransactionItemError
+		if errors.As(err, &itemErr) {
+			rsp.ErrorIndex = int32(itemErr.Index)
+		}
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+	rsp.ResourceVersions = rvs
+	for i := range events {
+		s.recordAudit(ctx, &events[i], rvs[i])
+	}
+	s.recordQuotaUsage(req.Namespace, events...)
+	s.log.Debug("server.WriteEvents", "namespace", req.Namespace, "count", len(events))
+	return rsp, nil
+}
+
+// newTransactionEvent validates a single item and checks the same permissions as create/update/delete
+func (s *server) newTransactionEvent(ctx context.Context, user claims.AuthInfo, namespace string, item *resourcepb.TransactionRequest_Item) (*WriteEvent, *resourcepb.ErrorResult) {
+	if item.Key == nil {
+		return nil, NewBadRequestError("missing key")
+	}
+	if item.Key.Namespace != namespace {
+		return nil, NewBadRequestError("all items must be in the transaction namespace")
+	}
+	if item.ResourceVersion < 0 {
+		return nil, NewBadRequestError("invalid resource version")
+	}
+
+	switch item.Action {
+	case resourcepb.TransactionRequest_Item_CREATE:
+		return s.newEvent(ctx, user, item.Key, item.Value, nil)
+
+	case resourcepb.TransactionRequest_Item_UPDATE:
+		latest := s.backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: item.Key})
+		if latest.Error != nil {
+			return nil, latest.Error
+		}
+		if latest.Value == nil {
+			return nil, NewBadRequestError("current value does not exist")
+		}
+		if item.ResourceVersion > 0 && latest.ResourceVersion != item.ResourceVersion {
+			return nil, AsErrorResult(ErrOptimisticLockingFailed)
+		}
+		event, e := s.newEvent(ctx, user, item.Key, item.Value, latest.Value)
+		if e != nil {
+			return nil, e
+		}
+		event.Type = resourcepb.WatchEvent_MODIFIED
+		event.PreviousRV = latest.ResourceVersion
+		event.PreviousSize = int64(len(latest.Value))
+		return event, nil
+
+	case resourcepb.TransactionRequest_Item_DELETE:
+		latest := s.backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: item.Key})
+		if latest.Error != nil {
+			return nil, latest.Error
+		}
+		if item.ResourceVersion > 0 && latest.ResourceVersion != item.ResourceVersion {
+			return nil, AsErrorResult(ErrOptimisticLockingFailed)
+		}
+		access, err := s.access.Check(ctx, user, claims.CheckRequest{
+			Verb:      "delete",
+			Group:     item.Key.Group,
+			Resource:  item.Key.Resource,
+			Namespace: item.Key.Namespace,
+			Name:      item.Key.Name,
+			Folder:    latest.Folder,
+		})
+		if err != nil {
+			return nil, AsErrorResult(err)
+		}
+		if !access.Allowed {
+			return nil, &resourcepb.ErrorResult{
+				Code: http.StatusForbidden,
+			}
+		}
+		event, err := s.newDeleteEvent(user, item.Key, latest)
+		if err != nil {
+			return nil, AsErrorResult(err)
+		}
+		return event, nil
+	}
+	return nil, NewBadRequestError("unknown transaction action: " + item.Action.String())
+}
@@ -0,0 +1,172 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+
+	authlib "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestServerTransaction(t *testing.T) {
+	ctx := authlib.WithAuthInfo(context.Background(), &identity.StaticRequester{
+		Type:           authlib.TypeUser,
+		Login:          "testuser",
+		UserID:         123,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true, // can do anything
+	})
+
+	server, err := NewResourceServer(ResourceServerOptions{
+		Backend: setupTestStorageBackend(t),
+	})
+	require.NoError(t, err)
+
+	value := func(group, kind, name, folder string) []byte {
+		return []byte(fmt.Sprintf(`{
+			"apiVersion": "%s/v1",
+			"kind": "%s",
+			"metadata": {
+				"name": "%s",
+				"uid": "uid-%s",
+				"namespace": "default",
+				"annotations": {
+					"grafana.app/folder": "%s"
+				}
+			}
+		}`, group, kind, name, name, folder))
+	}
+	dashKey := func(name string) *resourcepb.ResourceKey {
+		return &resourcepb.ResourceKey{Namespace: "default", Group: "dashboard.grafana.app", Resource: "dashboards", Name: name}
+	}
+	folderKey := &resourcepb.ResourceKey{Namespace: "default", Group: "folder.grafana.app", Resource: "folders", Name: "f1"}
+
+	read := func(key *resourcepb.ResourceKey) *resourcepb.ReadResponse {
+		rsp, err := server.Read(ctx, &resourcepb.ReadRequest{Key: key})
+		require.NoError(t, err)
+		return rsp
+	}
+
+	t.Run("create across resource types", func(t *testing.T) {
+		rsp, err := server.Transaction(ctx, &resourcepb.TransactionRequest{
+			Namespace: "default",
+			Items: []*resourcepb.TransactionRequest_Item{
+				{Action: resourcepb.TransactionRequest_Item_CREATE, Key: folderKey, Value: value("folder.grafana.app", "Folder", "f1", "")},
+				{Action: resourcepb.TransactionRequest_Item_CREATE, Key: dashKey("a"), Value: value("dashboard.grafana.app", "Dashboard", "a", "")},
+				{Action: resourcepb.TransactionRequest_Item_CREATE, Key: dashKey("b"), Value: value("dashboard.grafana.app", "Dashboard", "b", "")},
+			},
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Len(t, rsp.ResourceVersions, 3)
+
+		require.Nil(t, read(folderKey).Error)
+		require.Equal(t, rsp.ResourceVersions[1], read(dashKey("a")).ResourceVersion)
+		require.Equal(t, rsp.ResourceVersions[2], read(dashKey("b")).ResourceVersion)
+	})
+
+	t.Run("move and delete", func(t *testing.T) {
+		a := read(dashKey("a"))
+		rsp, err := server.Transaction(ctx, &resourcepb.TransactionRequest{
+			Namespace: "default",
+			Items: []*resourcepb.TransactionRequest_Item{
+				{Action: resourcepb.TransactionRequest_Item_UPDATE, Key: dashKey("a"), ResourceVersion: a.ResourceVersion, Value: value("dashboard.grafana.app", "Dashboard", "a", "f1")},
+				{Action: resourcepb.TransactionRequest_Item_DELETE, Key: dashKey("b")},
+			},
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+
+		require.Contains(t, string(read(dashKey("a")).Value), `"grafana.app/folder": "f1"`)
+		require.Equal(t, int32(http.StatusNotFound), read(dashKey("b")).Error.Code)
+	})
+
+	t.Run("nothing is saved when one item fails", func(t *testing.T) {
+		rsp, err := server.Transaction(ctx, &resourcepb.TransactionRequest{
+			Namespace: "default",
+			Items: []*resourcepb.TransactionRequest_Item{
+				{Action: resourcepb.TransactionRequest_Item_CREATE, Key: dashKey("c"), Value: value("dashboard.grafana.app", "Dashboard", "c", "")},
+				{Action: resourcepb.TransactionRequest_Item_CREATE, Key: folderKey, Value: value("folder.grafana.app", "Folder", "f1", "")}, // already exists
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusConflict), rsp.Error.Code)
+		require.Equal(t, int32(1), rsp.ErrorIndex)
+		require.Equal(t, int32(http.StatusNotFound), read(dashKey("c")).Error.Code)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			req   *resourcepb.TransactionRequest
+			index int32
+		}{
+			{
+				name: "missing namespace",
+				req: &resourcepb.TransactionRequest{Items: []*resourcepb.TransactionRequest_Item{
+					{Action: resourcepb.TransactionRequest_Item_DELETE, Key: dashKey("a")},
+				}},
+				index: -1,
+			},
+			{
+				name: "other namespace",
+				req: &resourcepb.TransactionRequest{Namespace: "other", Items: []*resourcepb.TransactionRequest_Item{
+					{Action: resourcepb.TransactionRequest_Item_DELETE, Key: dashKey("a")},
+				}},
+				index: 0,
+			},
+			{
+				name: "duplicate key",
+				req: &resourcepb.TransactionRequest{Namespace: "default", Items: []*resourcepb.TransactionRequest_Item{
+					{Action: resourcepb.TransactionRequest_Item_UPDATE, Key: dashKey("a"), Value: value("dashboard.grafana.app", "Dashboard", "a", "")},
+					{Action: resourcepb.TransactionRequest_Item_DELETE, Key: dashKey("a")},
+				}},
+				index: 1,
+			},
+			{
+				name: "unknown action",
+				req: &resourcepb.TransactionRequest{Namespace: "default", Items: []*resourcepb.TransactionRequest_Item{
+					{Key: dashKey("a")},
+				}},
+				index: 0,
+			},
+			{
+				name: "stale resource version",
+				req: &resourcepb.TransactionRequest{Namespace: "default", Items: []*resourcepb.TransactionRequest_Item{
+					{Action: resourcepb.TransactionRequest_Item_DELETE, Key: dashKey("a"), ResourceVersion: 1},
+				}},
+				index: 0,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rsp, err := server.Transaction(ctx, tt.req)
+				require.NoError(t, err)
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tt.index, rsp.ErrorIndex)
+			})
+		}
+		require.Nil(t, read(dashKey("a")).Error)
+	})
+
+	t.Run("backend without transactions", func(t *testing.T) {
+		store, err := NewCDKBackend(ctx, CDKBackendOptions{
+			Bucket: memblob.OpenBucket(nil),
+		})
+		require.NoError(t, err)
+		cdkServer, err := NewResourceServer(ResourceServerOptions{Backend: store})
+		require.NoError(t, err)
+
+		rsp, err := cdkServer.Transaction(ctx, &resourcepb.TransactionRequest{Namespace: "default"})
+		require.NoError(t, err)
+		require.Equal(t, int32(http.StatusNotImplemented), rsp.Error.Code)
+	})
+}
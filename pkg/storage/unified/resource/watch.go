@@ -0,0 +1,163 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// WatchReplayBackend is implemented by backends that can replay the events
+// written after a resource version. This allows a watch to resume from the last
+// resource version seen by the client (eg, after a restart) without a full
+// re-list, even when the events are no longer buffered by the broadcaster.
+type WatchReplayBackend interface {
+	// ReplayEvents calls fn for every event matching the key with a resource version
+	// greater than sinceRV, oldest first.
+	// When versions written after sinceRV have been removed by the history retention,
+	// the events can not be replayed and an error from NewResourceVersionTooOldError is returned.
+	ReplayEvents(ctx context.Context, key *resourcepb.ResourceKey, sinceRV int64, fn func(*WrittenEvent) error) error
+}
+
+// NewResourceVersionTooOldError is returned when a watch can not resume from a resource version,
+// the client is expected to list again.
+func NewResourceVersionTooOldError(rv int64) error {
+	return apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d", rv))
+}
+
+// ReplayCheck finds the gaps left by the history retention in the replayed events.
+// Every event must follow the previously replayed version of the same resource when
+// that version is newer than sinceRV, otherwise the versions in between were removed.
+type ReplayCheck struct {
+	sinceRV int64
+	latest  map[string]int64
+}
+
+func NewReplayCheck(sinceRV int64) *ReplayCheck {
+	return &ReplayCheck{sinceRV: sinceRV, latest: make(map[string]int64)}
+}
+
+// Check must be called with the events in resource version order
+func (c *ReplayCheck) Check(event *WrittenEvent) error {
+	id := SearchID(event.Key)
+	if event.PreviousRV > c.sinceRV && c.latest[id] != event.PreviousRV {
+		return NewResourceVersionTooOldError(c.sinceRV)
+	}
+	c.latest[id] = event.ResourceVersion
+	return nil
+}
+
+// watchFilter evaluates the label and field selectors of a watch request
+// before events are sent to the client
+type watchFilter struct {
+	labels labels.Selector
+	fields []fieldRequirement
+}
+
+type fieldRequirement struct {
+	path     []string
+	operator selection.Operator
+	values   []string
+}
+
+func newWatchFilter(opts *resourcepb.ListOptions) (*watchFilter, error) {
+	f := &watchFilter{labels: labels.Everything()}
+	if opts == nil {
+		return f, nil
+	}
+
+	for _, r := range opts.Labels {
+		req, err := labels.NewRequirement(r.Key, selection.Operator(r.Operator), r.Values)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+		f.labels = f.labels.Add(*req)
+	}
+
+	for _, r := range opts.Fields {
+		op := selection.Operator(r.Operator)
+		switch op {
+		case selection.Equals, selection.DoubleEquals, selection.NotEquals:
+			if len(r.Values) > 1 {
+				return nil, fmt.Errorf("invalid field selector: %q expects a single value", r.Key)
+			}
+		case selection.In, selection.NotIn:
+		default:
+			return nil, fmt.Errorf("invalid field selector: unsupported operator %q", r.Operator)
+		}
+		if r.Key == "" {
+			return nil, fmt.Errorf("invalid field selector: missing field")
+		}
+		f.fields = append(f.fields, fieldRequirement{
+			path:     strings.Split(r.Key, "."),
+			operator: op,
+			values:   r.Values,
+		})
+	}
+	return f, nil
+}
+
+func (f *watchFilter) empty() bool {
+	return f.labels.Empty() && len(f.fields) == 0
+}
+
+// matches reports whether the (JSON) value is selected by the filter.
+// Values that can not be decoded, and fields that do not resolve to a scalar
+// value, are not filtered here: the client applies its own predicate as well,
+// and may select on fields that are not stored in the object (eg, computed attributes).
+func (f *watchFilter) matches(value []byte) bool {
+	if f.empty() || len(value) == 0 {
+		return true
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(value, &obj.Object); err != nil {
+		return true
+	}
+
+	if !f.labels.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+
+	for _, r := range f.fields {
+		v, found, err := unstructured.NestedFieldNoCopy(obj.Object, r.path...)
+		if err != nil || !found {
+			continue
+		}
+		switch v.(type) {
+		case string, bool, int64, float64:
+		default:
+			continue
+		}
+		if !r.matches(fmt.Sprint(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r fieldRequirement) matches(v string) bool {
+	expected := ""
+	if len(r.values) > 0 {
+		expected = r.values[0]
+	}
+	switch r.operator {
+	case selection.Equals, selection.DoubleEquals:
+		return v == expected
+	case selection.NotEquals:
+		return v != expected
+	case selection.In:
+		return slices.Contains(r.values, v)
+	case selection.NotIn:
+		return !slices.Contains(r.values, v)
+	}
+	return true
+}
@@ -0,0 +1,281 @@
+package resource
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	authlib "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestWatchFilter(t *testing.T) {
+	value := []byte(`{
+		"apiVersion": "apps/v1",
+		"kind": "resource",
+		"metadata": {
+			"name": "item",
+			"namespace": "default",
+			"labels": {"team": "a", "env": "prod"}
+		},
+		"spec": {"replicas": 3, "enabled": true, "nested": {"value": "x"}}
+	}`)
+
+	tests := []struct {
+		name    string
+		opts    *resourcepb.ListOptions
+		matches bool
+	}{
+		{
+			name:    "no selectors",
+			opts:    &resourcepb.ListOptions{},
+			matches: true,
+		},
+		{
+			name: "label equals",
+			opts: &resourcepb.ListOptions{Labels: []*resourcepb.Requirement{
+				{Key: "team", Operator: "=", Values: []string{"a"}},
+			}},
+			matches: true,
+		},
+		{
+			name: "label not in",
+			opts: &resourcepb.ListOptions{Labels: []*resourcepb.Requirement{
+				{Key: "env", Operator: "notin", Values: []string{"prod", "dev"}},
+			}},
+			matches: false,
+		},
+		{
+			name: "label does not exist",
+			opts: &resourcepb.ListOptions{Labels: []*resourcepb.Requirement{
+				{Key: "owner", Operator: "!"},
+			}},
+			matches: true,
+		},
+		{
+			name: "field equals",
+			opts: &resourcepb.ListOptions{Fields: []*resourcepb.Requirement{
+				{Key: "metadata.name", Operator: "=", Values: []string{"item"}},
+			}},
+			matches: true,
+		},
+		{
+			name: "field not equals",
+			opts: &resourcepb.ListOptions{Fields: []*resourcepb.Requirement{
+				{Key: "spec.nested.value", Operator: "!=", Values: []string{"x"}},
+			}},
+			matches: false,
+		},
+		{
+			name: "field with scalar values",
+			opts: &resourcepb.ListOptions{Fields: []*resourcepb.Requirement{
+				{Key: "spec.replicas", Operator: "==", Values: []string{"3"}},
+				{Key: "spec.enabled", Operator: "in", Values: []string{"true"}},
+			}},
+			matches: true,
+		},
+		{
+			name: "unknown fields are not filtered",
+			opts: &resourcepb.ListOptions{Fields: []*resourcepb.Requirement{
+				{Key: "spec.missing", Operator: "=", Values: []string{"x"}},
+				{Key: "spec.nested", Operator: "=", Values: []string{"x"}},
+			}},
+			matches: true,
+		},
+		{
+			name: "labels and fields",
+			opts: &resourcepb.ListOptions{
+				Labels: []*resourcepb.Requirement{{Key: "team", Operator: "=", Values: []string{"a"}}},
+				Fields: []*resourcepb.Requirement{{Key: "metadata.name", Operator: "=", Values: []string{"other"}}},
+			},
+			matches: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newWatchFilter(tt.opts)
+			require.NoError(t, err)
+			require.Equal(t, tt.matches, filter.matches(value))
+		})
+	}
+
+	t.Run("invalid selectors", func(t *testing.T) {
+		_, err := newWatchFilter(&resourcepb.ListOptions{Labels: []*resourcepb.Requirement{
+			{Key: "team", Operator: "=", Values: []string{"a", "b"}},
+		}})
+		require.Error(t, err)
+
+		_, err = newWatchFilter(&resourcepb.ListOptions{Fields: []*resourcepb.Requirement{
+			{Key: "metadata.name", Operator: "exists"},
+		}})
+		require.Error(t, err)
+	})
+
+	t.Run("values that can not be decoded are not filtered", func(t *testing.T) {
+		filter, err := newWatchFilter(&resourcepb.ListOptions{Labels: []*resourcepb.Requirement{
+			{Key: "team", Operator: "=", Values: []string{"b"}},
+		}})
+		require.NoError(t, err)
+		require.True(t, filter.matches(nil))
+		require.True(t, filter.matches([]byte("not json")))
+	})
+}
+
+func TestWatchResumeFromStoredEvents(t *testing.T) {
+	ctx := authlib.WithAuthInfo(context.Background(), &identity.StaticRequester{
+		Type:           authlib.TypeUser,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true,
+	})
+	backend := setupTestStorageBackend(t)
+
+	write := func(name, team string, eventType resourcepb.WatchEvent_Type, previousRV int64) int64 {
+		obj, err := createTestObjectWithName(name, "apps", team)
+		require.NoError(t, err)
+		obj.SetLabels(map[string]string{"team": team})
+		rv, err := writeObject(t, backend, obj, eventType, previousRV)
+		require.NoError(t, err)
+		return rv
+	}
+	rvA := write("a", "x", resourcepb.WatchEvent_ADDED, 0)
+	write("b", "y", resourcepb.WatchEvent_ADDED, 0)
+	rvA2 := write("a", "y", resourcepb.WatchEvent_MODIFIED, rvA)
+	rvC := write("c", "x", resourcepb.WatchEvent_ADDED, 0)
+
+	// A new server only knows about the stored events
+	server, err := NewResourceServer(ResourceServerOptions{
+		Backend:               backend,
+		WatchBookmarkInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	watch := func(t *testing.T, team string) *fakeWatchServer {
+		watchCtx, cancel := context.WithCancel(ctx)
+		srv := &fakeWatchServer{ctx: watchCtx}
+		done := make(chan error, 1)
+		go func() {
+			done <- server.Watch(&resourcepb.WatchRequest{
+				Since: rvA,
+				Options: &resourcepb.ListOptions{
+					Key: &resourcepb.ResourceKey{Namespace: "default", Group: "apps", Resource: "resources"},
+					Labels: []*resourcepb.Requirement{
+						{Key: "team", Operator: "=", Values: []string{team}},
+					},
+				},
+				AllowWatchBookmarks: true,
+			}, srv)
+		}()
+		t.Cleanup(func() {
+			cancel()
+			require.NoError(t, <-done)
+		})
+		return srv
+	}
+
+	t.Run("replays the selected events", func(t *testing.T) {
+		srv := watch(t, "x")
+		require.Eventually(t, func() bool { return len(srv.received()) >= 2 }, 5*time.Second, 10*time.Millisecond)
+
+		events := srv.received()
+		require.Len(t, events, 2)
+		// "a" moved out of the selection: the previous value is included so the client can handle the transition
+		require.Equal(t, resourcepb.WatchEvent_MODIFIED, events[0].Type)
+		require.Equal(t, rvA2, events[0].Resource.Version)
+		require.Equal(t, rvA, events[0].Previous.Version)
+		require.Equal(t, resourcepb.WatchEvent_ADDED, events[1].Type)
+		require.Equal(t, rvC, events[1].Resource.Version)
+	})
+
+	t.Run("sends bookmarks for events that are not selected", func(t *testing.T) {
+		srv := watch(t, "z")
+		rvD := write("d", "y", resourcepb.WatchEvent_ADDED, 0)
+
+		require.Eventually(t, func() bool {
+			for _, evt := range srv.received() {
+				if evt.Type == resourcepb.WatchEvent_BOOKMARK && evt.Resource.Version == rvD {
+					return true
+				}
+			}
+			return false
+		}, 5*time.Second, 10*time.Millisecond)
+
+		for _, evt := range srv.received() {
+			require.Equal(t, resourcepb.WatchEvent_BOOKMARK, evt.Type)
+		}
+	})
+
+	t.Run("history removed by the retention", func(t *testing.T) {
+		err := backend.dataStore.Delete(ctx, DataKey{
+			Namespace:       "default",
+			Group:           "apps",
+			Resource:        "resources",
+			Name:            "a",
+			ResourceVersion: rvA2,
+			Action:          DataActionUpdated,
+		})
+		require.NoError(t, err)
+
+		srv := watch(t, "x")
+		require.Eventually(t, func() bool { return len(srv.received()) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+		events := srv.received()
+		require.Len(t, events, 1)
+		require.Equal(t, resourcepb.WatchEvent_ERROR, events[0].Type)
+		require.Equal(t, int32(http.StatusGone), events[0].Error.Code)
+		require.Equal(t, string(metav1.StatusReasonExpired), events[0].Error.Reason)
+	})
+}
+
+func TestReplayCheck(t *testing.T) {
+	event := func(name string, rv, previousRV int64) *WrittenEvent {
+		return &WrittenEvent{
+			Key:             &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs", Name: name},
+			ResourceVersion: rv,
+			PreviousRV:      previousRV,
+		}
+	}
+
+	check := NewReplayCheck(100)
+	require.NoError(t, check.Check(event("a", 101, 50)))  // the previous version is older than the replay
+	require.NoError(t, check.Check(event("b", 102, 0)))   // created
+	require.NoError(t, check.Check(event("a", 103, 101))) // follows the replayed version
+	require.NoError(t, check.Check(event("b", 104, 102)))
+
+	err := check.Check(event("a", 106, 105)) // 105 was removed
+	require.True(t, apierrors.IsResourceExpired(err))
+	require.EqualError(t, err, "too old resource version: 100")
+}
+
+type fakeWatchServer struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	mu     sync.Mutex
+	events []*resourcepb.WatchEvent
+}
+
+func (f *fakeWatchServer) Send(evt *resourcepb.WatchEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, evt)
+	return nil
+}
+
+func (f *fakeWatchServer) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchServer) received() []*resourcepb.WatchEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*resourcepb.WatchEvent{}, f.events...)
+}
@@ -19,12 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	ResourceStore_Read_FullMethodName   = "/resource.ResourceStore/Read"
-	ResourceStore_Create_FullMethodName = "/resource.ResourceStore/Create"
-	ResourceStore_Update_FullMethodName = "/resource.ResourceStore/Update"
-	ResourceStore_Delete_FullMethodName = "/resource.ResourceStore/Delete"
-	ResourceStore_List_FullMethodName   = "/resource.ResourceStore/List"
-	ResourceStore_Watch_FullMethodName  = "/resource.ResourceStore/Watch"
+	ResourceStore_Read_FullMethodName        = "/resource.ResourceStore/Read"
+	ResourceStore_Create_FullMethodName      = "/resource.ResourceStore/Create"
+	ResourceStore_Update_FullMethodName      = "/resource.ResourceStore/Update"
+	ResourceStore_Delete_FullMethodName      = "/resource.ResourceStore/Delete"
+	ResourceStore_Transaction_FullMethodName = "/resource.ResourceStore/Transaction"
+	ResourceStore_List_FullMethodName        = "/resource.ResourceStore/List"
+	ResourceStore_Watch_FullMethodName       = "/resource.ResourceStore/Watch"
 )
 
 // ResourceStoreClient is the client API for ResourceStore service.
@@ -40,6 +41,9 @@ type ResourceStoreClient interface {
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Apply multiple creates/updates/deletes within a single namespace atomically
+	// Watch events are only sent after every item has been committed
+	Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
 	// The results *may* include values that should not be returned to the user
 	// This will perform best-effort filtering to increase performace.
 	// NOTE: storage.Interface is ultimatly responsible for the final filtering
@@ -98,6 +102,16 @@ func (c *resourceStoreClient) Delete(ctx context.Context, in *DeleteRequest, opt
 	return out, nil
 }
 
+func (c *resourceStoreClient) Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, ResourceStore_Transaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *resourceStoreClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListResponse)
@@ -154,6 +168,9 @@ type ResourceStoreServer interface {
 	Create(context.Context, *CreateRequest) (*CreateResponse, error)
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Apply multiple creates/updates/deletes within a single namespace atomically
+	// Watch events are only sent after every item has been committed
+	Transaction(context.Context, *TransactionRequest) (*TransactionResponse, error)
 	// The results *may* include values that should not be returned to the user
 	// This will perform best-effort filtering to increase performace.
 	// NOTE: storage.Interface is ultimatly responsible for the final filtering
@@ -180,6 +197,9 @@ func (UnimplementedResourceStoreServer) Update(context.Context, *UpdateRequest)
 func (UnimplementedResourceStoreServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedResourceStoreServer) Transaction(context.Context, *TransactionRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transaction not implemented")
+}
 func (UnimplementedResourceStoreServer) List(context.Context, *ListRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
@@ -270,6 +290,24 @@ func _ResourceStore_Delete_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceStore_Transaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceStoreServer).Transaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceStore_Transaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceStoreServer).Transaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ResourceStore_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListRequest)
 	if err := dec(in); err != nil {
@@ -332,6 +370,10 @@ var ResourceStore_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ResourceStore_Delete_Handler,
 		},
+		{
+			MethodName: "Transaction",
+			Handler:    _ResourceStore_Transaction_Handler,
+		},
 		{
 			MethodName: "List",
 			Handler:    _ResourceStore_List_Handler,
@@ -613,6 +655,360 @@ var ManagedObjectIndex_ServiceDesc = grpc.ServiceDesc{
 	Metadata: "resource.proto",
 }
 
+const (
+	AuditLog_QueryAudit_FullMethodName = "/resource.AuditLog/QueryAudit"
+)
+
+// AuditLogClient is the client API for AuditLog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Query the audit trail of resource changes
+// Results only include resources the user can read
+type AuditLogClient interface {
+	QueryAudit(ctx context.Context, in *AuditQueryRequest, opts ...grpc.CallOption) (*AuditQueryResponse, error)
+}
+
+type auditLogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuditLogClient(cc grpc.ClientConnInterface) AuditLogClient {
+	return &auditLogClient{cc}
+}
+
+func (c *auditLogClient) QueryAudit(ctx context.Context, in *AuditQueryRequest, opts ...grpc.CallOption) (*AuditQueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditQueryResponse)
+	err := c.cc.Invoke(ctx, AuditLog_QueryAudit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuditLogServer is the server API for AuditLog service.
+// All implementations should embed UnimplementedAuditLogServer
+// for forward compatibility
+//
+// Query the audit trail of resource changes
+// Results only include resources the user can read
+type AuditLogServer interface {
+	QueryAudit(context.Context, *AuditQueryRequest) (*AuditQueryResponse, error)
+}
+
+// UnimplementedAuditLogServer should be embedded to have forward compatible implementations.
+type UnimplementedAuditLogServer struct {
+}
+
+func (UnimplementedAuditLogServer) QueryAudit(context.Context, *AuditQueryRequest) (*AuditQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAudit not implemented")
+}
+
+// UnsafeAuditLogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuditLogServer will
+// result in compilation errors.
+type UnsafeAuditLogServer interface {
+	mustEmbedUnimplementedAuditLogServer()
+}
+
+func RegisterAuditLogServer(s grpc.ServiceRegistrar, srv AuditLogServer) {
+	s.RegisterService(&AuditLog_ServiceDesc, srv)
+}
+
+func _AuditLog_QueryAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditLogServer).QueryAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditLog_QueryAudit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditLogServer).QueryAudit(ctx, req.(*AuditQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AuditLog_ServiceDesc is the grpc.ServiceDesc for AuditLog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuditLog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "resource.AuditLog",
+	HandlerType: (*AuditLogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "QueryAudit",
+			Handler:    _AuditLog_QueryAudit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "resource.proto",
+}
+
+const (
+	ResourceQuotas_GetQuotas_FullMethodName = "/resource.ResourceQuotas/GetQuotas"
+	ResourceQuotas_SetQuota_FullMethodName  = "/resource.ResourceQuotas/SetQuota"
+)
+
+// ResourceQuotasClient is the client API for ResourceQuotas service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Manage the per namespace resource quotas
+// Only available to grafana admins and service identities
+type ResourceQuotasClient interface {
+	GetQuotas(ctx context.Context, in *GetQuotasRequest, opts ...grpc.CallOption) (*GetQuotasResponse, error)
+	SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*SetQuotaResponse, error)
+}
+
+type resourceQuotasClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewResourceQuotasClient(cc grpc.ClientConnInterface) ResourceQuotasClient {
+	return &resourceQuotasClient{cc}
+}
+
+func (c *resourceQuotasClient) GetQuotas(ctx context.Context, in *GetQuotasRequest, opts ...grpc.CallOption) (*GetQuotasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQuotasResponse)
+	err := c.cc.Invoke(ctx, ResourceQuotas_GetQuotas_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceQuotasClient) SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*SetQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetQuotaResponse)
+	err := c.cc.Invoke(ctx, ResourceQuotas_SetQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResourceQuotasServer is the server API for ResourceQuotas service.
+// All implementations should embed UnimplementedResourceQuotasServer
+// for forward compatibility
+//
+// Manage the per namespace resource quotas
+// Only available to grafana admins and service identities
+type ResourceQuotasServer interface {
+	GetQuotas(context.Context, *GetQuotasRequest) (*GetQuotasResponse, error)
+	SetQuota(context.Context, *SetQuotaRequest) (*SetQuotaResponse, error)
+}
+
+// UnimplementedResourceQuotasServer should be embedded to have forward compatible implementations.
+type UnimplementedResourceQuotasServer struct {
+}
+
+func (UnimplementedResourceQuotasServer) GetQuotas(context.Context, *GetQuotasRequest) (*GetQuotasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotas not implemented")
+}
+func (UnimplementedResourceQuotasServer) SetQuota(context.Context, *SetQuotaRequest) (*SetQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetQuota not implemented")
+}
+
+// UnsafeResourceQuotasServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ResourceQuotasServer will
+// result in compilation errors.
+type UnsafeResourceQuotasServer interface {
+	mustEmbedUnimplementedResourceQuotasServer()
+}
+
+func RegisterResourceQuotasServer(s grpc.ServiceRegistrar, srv ResourceQuotasServer) {
+	s.RegisterService(&ResourceQuotas_ServiceDesc, srv)
+}
+
+func _ResourceQuotas_GetQuotas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceQuotasServer).GetQuotas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceQuotas_GetQuotas_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceQuotasServer).GetQuotas(ctx, req.(*GetQuotasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceQuotas_SetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceQuotasServer).SetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceQuotas_SetQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceQuotasServer).SetQuota(ctx, req.(*SetQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ResourceQuotas_ServiceDesc is the grpc.ServiceDesc for ResourceQuotas service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ResourceQuotas_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "resource.ResourceQuotas",
+	HandlerType: (*ResourceQuotasServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetQuotas",
+			Handler:    _ResourceQuotas_GetQuotas_Handler,
+		},
+		{
+			MethodName: "SetQuota",
+			Handler:    _ResourceQuotas_SetQuota_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "resource.proto",
+}
+
+const (
+	BackupStore_Backup_FullMethodName = "/resource.BackupStore/Backup"
+)
+
+// BackupStoreClient is the client API for BackupStore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Export the resources of a namespace
+// Only available to grafana admins and service identities
+type BackupStoreClient interface {
+	// Stream a parquet archive in the same format as the backup command
+	// The archive can be restored with BulkProcess
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (BackupStore_BackupClient, error)
+}
+
+type backupStoreClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBackupStoreClient(cc grpc.ClientConnInterface) BackupStoreClient {
+	return &backupStoreClient{cc}
+}
+
+func (c *backupStoreClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (BackupStore_BackupClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BackupStore_ServiceDesc.Streams[0], BackupStore_Backup_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &backupStoreBackupClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BackupStore_BackupClient interface {
+	Recv() (*BackupResponse, error)
+	grpc.ClientStream
+}
+
+type backupStoreBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *backupStoreBackupClient) Recv() (*BackupResponse, error) {
+	m := new(BackupResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BackupStoreServer is the server API for BackupStore service.
+// All implementations should embed UnimplementedBackupStoreServer
+// for forward compatibility
+//
+// Export the resources of a namespace
+// Only available to grafana admins and service identities
+type BackupStoreServer interface {
+	// Stream a parquet archive in the same format as the backup command
+	// The archive can be restored with BulkProcess
+	Backup(*BackupRequest, BackupStore_BackupServer) error
+}
+
+// UnimplementedBackupStoreServer should be embedded to have forward compatible implementations.
+type UnimplementedBackupStoreServer struct {
+}
+
+func (UnimplementedBackupStoreServer) Backup(*BackupRequest, BackupStore_BackupServer) error {
+	return status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+
+// UnsafeBackupStoreServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BackupStoreServer will
+// result in compilation errors.
+type UnsafeBackupStoreServer interface {
+	mustEmbedUnimplementedBackupStoreServer()
+}
+
+func RegisterBackupStoreServer(s grpc.ServiceRegistrar, srv BackupStoreServer) {
+	s.RegisterService(&BackupStore_ServiceDesc, srv)
+}
+
+func _BackupStore_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BackupStoreServer).Backup(m, &backupStoreBackupServer{ServerStream: stream})
+}
+
+type BackupStore_BackupServer interface {
+	Send(*BackupResponse) error
+	grpc.ServerStream
+}
+
+type backupStoreBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *backupStoreBackupServer) Send(m *BackupResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BackupStore_ServiceDesc is the grpc.ServiceDesc for BackupStore service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BackupStore_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "resource.BackupStore",
+	HandlerType: (*BackupStoreServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Backup",
+			Handler:       _BackupStore_Backup_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "resource.proto",
+}
+
 const (
 	Diagnostics_IsHealthy_FullMethodName = "/resource.Diagnostics/IsHealthy"
 )
@@ -922,7 +922,7 @@ func (b *bleveIndex) Search(
 
 	// parse the facet fields
 	for k, v := range res.Facets {
-		f := newResponseFacet(v)
+		f := newResponseFacet(v, req.Facet[k])
 		if response.Facet == nil {
 			response.Facet = make(map[string]*resourcepb.ResourceSearchResponse_Facet)
 		}
@@ -1004,11 +1004,6 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 	ctx, span := b.tracing.Start(ctx, tracingPrexfixBleve+"toBleveSearchRequest")
 	defer span.End()
 
-	facets := bleve.FacetsRequest{}
-	for _, f := range req.Facet {
-		facets[f.Field] = bleve.NewFacetRequest(f.Field, int(f.Limit))
-	}
-
 	// Convert resource-specific fields to bleve fields (just considers dashboard fields for now)
 	fields := make([]string, 0, len(req.Fields))
 	for _, f := range req.Fields {
@@ -1032,7 +1027,6 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 		Size:    size,
 		From:    offset,
 		Explain: req.Explain,
-		Facets:  facets,
 	}
 
 	// Currently everything is within an AND query
@@ -1057,13 +1051,30 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 		}
 	}
 
-	if len(req.Query) > 1 && strings.Contains(req.Query, "*") {
+	if err := validateFuzziness(req.Fuzziness); err != nil {
+		return nil, err
+	}
+	if err := validateBoost(req.Boost); err != nil {
+		return nil, err
+	}
+
+	if req.AdvancedQuery && req.Query != "" {
+		// Parse the operators, phrases and field scoped terms
+		searchrequest.Fields = append(searchrequest.Fields, resource.SEARCH_FIELD_SCORE)
+		searchQuery, err := newAdvancedQuery(req.Query, req.Fuzziness)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, newBoostedQuery(searchQuery, req.Boost))
+	}
+
+	if !req.AdvancedQuery && len(req.Query) > 1 && strings.Contains(req.Query, "*") {
 		// wildcard query is expensive - should be used with caution
 		wildcard := bleve.NewWildcardQuery(req.Query)
 		queries = append(queries, wildcard)
 	}
 
-	if req.Query != "" && !strings.Contains(req.Query, "*") {
+	if !req.AdvancedQuery && req.Query != "" && !strings.Contains(req.Query, "*") {
 		// Add a text query
 		searchrequest.Fields = append(searchrequest.Fields, resource.SEARCH_FIELD_SCORE)
 
@@ -1079,13 +1090,14 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 		queryExact.SetBoost(5.0)
 		queryPhrase.Analyzer = standard.Name
 
-		// Query 3: Match query with standard analyzer
+		// Query 3: Match query with standard analyzer (allowing typos)
 		queryAnalyzed := bleve.NewMatchQuery(req.Query)
 		queryAnalyzed.Analyzer = standard.Name
+		queryAnalyzed.SetFuzziness(textFuzziness(req.Fuzziness, req.Query))
 
 		// At least one of the queries must match
 		searchQuery := bleve.NewDisjunctionQuery(queryExact, queryAnalyzed, queryPhrase)
-		queries = append(queries, searchQuery)
+		queries = append(queries, newBoostedQuery(searchQuery, req.Boost))
 	}
 
 	switch len(queries) {
@@ -1141,7 +1153,7 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 		if searchrequest.Facets == nil {
 			searchrequest.Facets = make(bleve.FacetsRequest)
 		}
-		searchrequest.Facets[k] = bleve.NewFacetRequest(v.Field, int(v.Limit))
+		searchrequest.Facets[k] = newFacetRequest(v)
 	}
 
 	// Add the sort fields
@@ -1347,6 +1359,16 @@ var termFields = []string{
 	resource.SEARCH_FIELD_TITLE,
 }
 
+// OperatorContains matches fields that contain the value as a substring.
+// Only the fields with a trigram index support it, and the value must be at least 3 characters.
+// Every trigram of the value must be in the field, so a match is likely, not certain
+const OperatorContains selection.Operator = "~"
+
+// The trigram index of the fields that support OperatorContains
+var containsFields = map[string]string{
+	resource.SEARCH_FIELD_PREFIX + DASHBOARD_PANEL_QUERIES: resource.SEARCH_FIELD_PREFIX + DASHBOARD_PANEL_QUERIES_TRIGRAMS,
+}
+
 // Convert a "requirement" into a bleve query
 func requirementQuery(req *resourcepb.Requirement, prefix string) (query.Query, *resourcepb.ErrorResult) {
 	switch selection.Operator(req.Operator) {
@@ -1390,6 +1412,30 @@ func requirementQuery(req *resourcepb.Requirement, prefix string) (query.Query,
 
 		return query.NewDisjunctionQuery(disjuncts), nil
 
+	case OperatorContains:
+		field, ok := containsFields[prefix+req.Key]
+		if !ok {
+			return nil, resource.NewBadRequestError(fmt.Sprintf("%s does not support substring matching", req.Key))
+		}
+		if len(req.Values) == 0 {
+			return query.NewMatchAllQuery(), nil
+		}
+		disjuncts := []query.Query{}
+		for _, v := range req.Values {
+			grams := trigrams(v)
+			if len(grams) == 0 {
+				return nil, resource.NewBadRequestError(fmt.Sprintf("%s must match at least %d characters", req.Key, trigramSize))
+			}
+			conjuncts := make([]query.Query, 0, len(grams))
+			for _, gram := range grams {
+				q := bleve.NewTermQuery(gram)
+				q.SetField(field)
+				conjuncts = append(conjuncts, q)
+			}
+			disjuncts = append(disjuncts, query.NewConjunctionQuery(conjuncts))
+		}
+		return query.NewDisjunctionQuery(disjuncts), nil
+
 	case selection.NotIn:
 		boolQuery := bleve.NewBooleanQuery()
 
@@ -1411,6 +1457,19 @@ func requirementQuery(req *resourcepb.Requirement, prefix string) (query.Query,
 	)
 }
 
+// trigrams returns the distinct 3 character substrings of a value, like the TRIGRAM_ANALYZER
+func trigrams(v string) []string {
+	runes := []rune(v)
+	var grams []string
+	for i := 0; i+trigramSize <= len(runes); i++ {
+		gram := string(runes[i : i+trigramSize])
+		if !slices.Contains(grams, gram) {
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
+
 // newQuery will create a query that will match the value or the tokens of the value
 func newQuery(key string, value string, prefix string) query.Query {
 	if value == "*" {
@@ -1599,20 +1658,68 @@ func getAllFields(standard resource.SearchableDocumentFields, custom resource.Se
 	return fields, nil
 }
 
-func newResponseFacet(v *search.FacetResult) *resourcepb.ResourceSearchResponse_Facet {
+// newFacetRequest counts the distinct terms, or the values within each range when ranges are requested
+func newFacetRequest(v *resourcepb.ResourceSearchRequest_Facet) *bleve.FacetRequest {
+	// fields that are specific to the resource are indexed as fields.<fieldName>
+	field := v.Field
+	if slices.Contains(DashboardFields(), field) {
+		field = resource.SEARCH_FIELD_PREFIX + field
+	}
+
+	size := int(v.Limit)
+	if len(v.Ranges) > size {
+		size = len(v.Ranges) // otherwise only the top ranges are returned
+	}
+	facet := bleve.NewFacetRequest(field, size)
+	for _, r := range v.Ranges {
+		var minValue, maxValue *float64
+		if r.Min != nil {
+			m := float64(*r.Min)
+			minValue = &m
+		}
+		if r.Max != nil {
+			m := float64(*r.Max)
+			maxValue = &m
+		}
+		facet.AddNumericRange(r.Name, minValue, maxValue)
+	}
+	return facet
+}
+
+func newResponseFacet(v *search.FacetResult, req *resourcepb.ResourceSearchRequest_Facet) *resourcepb.ResourceSearchResponse_Facet {
 	f := &resourcepb.ResourceSearchResponse_Facet{
 		Field:   v.Field,
 		Total:   int64(v.Total),
 		Missing: int64(v.Missing),
 	}
+	if req != nil {
+		f.Field = req.Field // without the fields. prefix
+	}
 	if v.Terms != nil {
 		for _, t := range v.Terms.Terms() {
+			if t.Term == "" {
+				f.Missing += int64(t.Count) // empty values are indexed, but they are not set
+				continue
+			}
 			f.Terms = append(f.Terms, &resourcepb.ResourceSearchResponse_TermFacet{
 				Term:  t.Term,
 				Count: int64(t.Count),
 			})
 		}
 	}
+	if req != nil && len(req.Ranges) > 0 {
+		// bleve sorts the ranges by count and skips the empty ones
+		counts := make(map[string]int, len(v.NumericRanges))
+		for _, r := range v.NumericRanges {
+			counts[r.Name] = r.Count
+		}
+		for _, r := range req.Ranges {
+			f.Ranges = append(f.Ranges, &resourcepb.ResourceSearchResponse_RangeFacet{
+				Name:  r.Name,
+				Count: int64(counts[r.Name]),
+			})
+		}
+	}
 	return f
 }
 
@@ -83,6 +83,24 @@ func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentM
 	}
 	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_FOLDER, folderMapping)
 
+	// who created, updated or owns the resource (used for faceting)
+	for _, f := range []string{resource.SEARCH_FIELD_CREATED_BY, resource.SEARCH_FIELD_UPDATED_BY, resource.SEARCH_FIELD_OWNERS} {
+		mapper.AddFieldMappingsAt(f, &mapping.FieldMapping{
+			Name:               f,
+			Type:               "text",
+			Analyzer:           keyword.Name,
+			Store:              true,
+			Index:              true,
+			IncludeTermVectors: false,
+			IncludeInAll:       false,
+			DocValues:          true,
+		})
+	}
+
+	// unix milliseconds (used for sorting and time buckets)
+	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_CREATED, mapping.NewNumericFieldMapping())
+	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_UPDATED, mapping.NewNumericFieldMapping())
+
 	// Repositories
 	manager := bleve.NewDocumentStaticMapping()
 	manager.AddFieldMappingsAt("kind", &mapping.FieldMapping{
@@ -147,5 +165,29 @@ func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentM
 	fieldMapper := bleve.NewDocumentMapping()
 	mapper.AddSubDocumentMapping("fields", fieldMapper)
 
+	// query content is matched against the whole value, and the types are faceted by the full name
+	for _, f := range []string{DASHBOARD_METRIC_NAMES, DASHBOARD_PANEL_QUERIES, DASHBOARD_PANEL_TYPES, DASHBOARD_DS_TYPES, DASHBOARD_TRANSFORMATIONS} {
+		fieldMapper.AddFieldMappingsAt(f, &mapping.FieldMapping{
+			Name:               f,
+			Type:               "text",
+			Analyzer:           keyword.Name,
+			Store:              true,
+			Index:              true,
+			IncludeTermVectors: false,
+			IncludeInAll:       false,
+		})
+	}
+
+	// the substrings of the panel queries, see OperatorContains
+	fieldMapper.AddFieldMappingsAt(DASHBOARD_PANEL_QUERIES, &mapping.FieldMapping{
+		Name:               DASHBOARD_PANEL_QUERIES_TRIGRAMS,
+		Type:               "text",
+		Analyzer:           TRIGRAM_ANALYZER,
+		Store:              false,
+		Index:              true,
+		IncludeTermVectors: false,
+		IncludeInAll:       false,
+	})
+
 	return mapper
 }
@@ -0,0 +1,257 @@
+package search
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// The bleve fuzzy queries do not support a larger edit distance
+const maxFuzziness = 2
+
+// The edit distance used when the request does not set one, so a single typo still matches.
+// Shorter words are matched exactly, since one edit often turns them into a different word (eg tag2 and tag3)
+const (
+	defaultFuzziness          = 1
+	defaultFuzzinessMinLength = 5
+)
+
+// The fields that can be used to scope a term in the advanced query (eg tag:prod)
+// Dashboard fields can also be used with their own name (eg panel_types:table)
+var queryFields = map[string]string{
+	"tag":         resource.SEARCH_FIELD_TAGS,
+	"tags":        resource.SEARCH_FIELD_TAGS,
+	"folder":      resource.SEARCH_FIELD_FOLDER,
+	"name":        resource.SEARCH_FIELD_NAME,
+	"title":       resource.SEARCH_FIELD_TITLE,
+	"description": resource.SEARCH_FIELD_DESCRIPTION,
+	"owner":       resource.SEARCH_FIELD_OWNERS,
+	"creator":     resource.SEARCH_FIELD_CREATED_BY,
+	"updater":     resource.SEARCH_FIELD_UPDATED_BY,
+}
+
+// queryClause is a single word, phrase or field scoped term of the advanced query
+type queryClause struct {
+	field   string // empty when matching the text
+	value   string
+	phrase  bool
+	exclude bool
+}
+
+// parseAdvancedQuery splits the query into groups of clauses.
+// The clauses within a group must all match (AND), while any of the groups can match (OR)
+func parseAdvancedQuery(q string) [][]queryClause {
+	groups := [][]queryClause{{}}
+	for _, token := range tokenizeQuery(q) {
+		switch token {
+		case "AND", "&&":
+			continue // the default
+		case "OR", "||":
+			if len(groups[len(groups)-1]) > 0 {
+				groups = append(groups, []queryClause{})
+			}
+			continue
+		}
+
+		c := queryClause{}
+		if len(token) > 1 && token[0] == '-' {
+			c.exclude = true
+			token = token[1:]
+		}
+		if idx := strings.Index(token, ":"); idx > 0 && token[0] != '"' {
+			if _, ok := queryFields[token[:idx]]; ok || slices.Contains(DashboardFields(), token[:idx]) {
+				c.field = token[:idx]
+				token = token[idx+1:]
+			}
+		}
+		if len(token) > 1 && token[0] == '"' {
+			c.phrase = true
+			token = strings.TrimSuffix(token[1:], `"`)
+		}
+		if token == "" {
+			continue
+		}
+		c.value = token
+		groups[len(groups)-1] = append(groups[len(groups)-1], c)
+	}
+	if len(groups) > 1 && len(groups[len(groups)-1]) == 0 {
+		groups = groups[:len(groups)-1] // trailing OR
+	}
+	return groups
+}
+
+// tokenizeQuery splits the query by whitespace, keeping the quoted phrases together
+func tokenizeQuery(q string) []string {
+	tokens := []string{}
+	current := strings.Builder{}
+	quoted := false
+	for _, r := range q {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if unicode.IsSpace(r) && !quoted {
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// newAdvancedQuery converts the parsed query into a bleve query
+func newAdvancedQuery(q string, fuzziness *int64) (query.Query, *resourcepb.ErrorResult) {
+	groups := parseAdvancedQuery(q)
+	disjuncts := make([]query.Query, 0, len(groups))
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		boolQuery := bleve.NewBooleanQuery()
+		for _, c := range group {
+			cq, err := newClauseQuery(c, fuzziness)
+			if err != nil {
+				return nil, err
+			}
+			if c.exclude {
+				boolQuery.AddMustNot(cq)
+			} else {
+				boolQuery.AddMust(cq)
+			}
+		}
+		disjuncts = append(disjuncts, boolQuery)
+	}
+
+	switch len(disjuncts) {
+	case 0:
+		return bleve.NewMatchAllQuery(), nil
+	case 1:
+		return disjuncts[0], nil
+	default:
+		return bleve.NewDisjunctionQuery(disjuncts...), nil // OR
+	}
+}
+
+// newClauseQuery matches a single clause against the text or the scoped field
+func newClauseQuery(c queryClause, fuzziness *int64) (query.Query, *resourcepb.ErrorResult) {
+	if c.field != "" {
+		key, prefix := c.field, ""
+		if f, ok := queryFields[key]; ok {
+			key = f
+		} else {
+			prefix = resource.SEARCH_FIELD_PREFIX
+		}
+		return requirementQuery(&resourcepb.Requirement{
+			Key:      key,
+			Operator: string(selection.Equals),
+			Values:   []string{c.value},
+		}, prefix)
+	}
+
+	if c.phrase {
+		q := bleve.NewMatchPhraseQuery(c.value)
+		q.Analyzer = standard.Name
+		return q, nil
+	}
+	if strings.Contains(c.value, "*") {
+		// wildcard query is expensive - should be used with caution
+		return bleve.NewWildcardQuery(c.value), nil
+	}
+	q := bleve.NewMatchQuery(c.value)
+	q.Analyzer = standard.Name
+	q.SetFuzziness(textFuzziness(fuzziness, c.value))
+	return q, nil
+}
+
+// newBoostedQuery adds the score of the boost fields to the matching documents
+func newBoostedQuery(q query.Query, boost []string) query.Query {
+	if len(boost) == 0 {
+		return q
+	}
+	should := make([]query.Query, 0, len(boost))
+	for _, field := range boost {
+		should = append(should, newBoostQuery(field))
+	}
+	// the documents only need to match the query, the boost fields are optional
+	boolQuery := bleve.NewBooleanQuery()
+	boolQuery.AddMust(q)
+	boolQuery.AddShould(should...)
+	return boolQuery
+}
+
+// newBoostQuery matches each order of magnitude of the numeric field (eg 10, 100 and 1000 views),
+// so the more popular documents match more of the queries and get a higher score
+func newBoostQuery(field string) query.Query {
+	if slices.Contains(DashboardFields(), field) {
+		field = resource.SEARCH_FIELD_PREFIX + field
+	}
+	disjuncts := []query.Query{}
+	for _, v := range []float64{10, 100, 1000, 10000} {
+		minValue := v
+		q := bleve.NewNumericRangeQuery(&minValue, nil)
+		q.SetField(field)
+		disjuncts = append(disjuncts, q)
+	}
+	return bleve.NewDisjunctionQuery(disjuncts...)
+}
+
+// validateFuzziness checks the requested edit distance is supported
+func validateFuzziness(fuzziness *int64) *resourcepb.ErrorResult {
+	if fuzziness != nil && (*fuzziness < 0 || *fuzziness > maxFuzziness) {
+		return resource.NewBadRequestError(fmt.Sprintf("fuzziness must be between 0 and %d", maxFuzziness))
+	}
+	return nil
+}
+
+// textFuzziness returns the requested edit distance, or the default when it is not set
+// and all the words in the text are long enough
+func textFuzziness(fuzziness *int64, text string) int {
+	if fuzziness != nil {
+		return int(*fuzziness)
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+	for _, w := range words {
+		if utf8.RuneCountInString(w) < defaultFuzzinessMinLength {
+			return 0
+		}
+	}
+	return defaultFuzziness
+}
+
+// validateBoost checks the results are only boosted by the numeric dashboard fields
+func validateBoost(fields []string) *resourcepb.ErrorResult {
+	for _, field := range fields {
+		if !isBoostField(field) {
+			return resource.NewBadRequestError(fmt.Sprintf("unsupported boost field: %q", field))
+		}
+	}
+	return nil
+}
+
+func isBoostField(field string) bool {
+	switch field {
+	case DASHBOARD_SCHEMA_VERSION, DASHBOARD_LINK_COUNT:
+		return true
+	}
+	return slices.Contains(UsageInsightsFields(), field)
+}
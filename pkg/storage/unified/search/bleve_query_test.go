@@ -0,0 +1,69 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAdvancedQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected [][]queryClause
+	}{
+		{
+			query:    "",
+			expected: [][]queryClause{{}},
+		},
+		{
+			query: "kubernetes nodes",
+			expected: [][]queryClause{{
+				{value: "kubernetes"},
+				{value: "nodes"},
+			}},
+		},
+		{
+			query: `"kubernetes  nodes" AND -dev`,
+			expected: [][]queryClause{{
+				{value: "kubernetes  nodes", phrase: true},
+				{value: "dev", exclude: true},
+			}},
+		},
+		{
+			query: `tag:prod OR folder:"my folder" OR`,
+			expected: [][]queryClause{
+				{{field: "tag", value: "prod"}},
+				{{field: "folder", value: "my folder", phrase: true}},
+			},
+		},
+		{
+			query: "-panel_types:table http://host -",
+			expected: [][]queryClause{{
+				{field: "panel_types", value: "table", exclude: true},
+				{value: "http://host"}, // not a known field
+				{value: "-"},
+			}},
+		},
+		{
+			query: `"unterminated phrase`,
+			expected: [][]queryClause{{
+				{value: "unterminated phrase", phrase: true},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			require.Equal(t, tt.expected, parseAdvancedQuery(tt.query))
+		})
+	}
+}
+
+func TestTextFuzziness(t *testing.T) {
+	exact := int64(0)
+	require.Equal(t, 1, textFuzziness(nil, "kuberntes"))
+	require.Equal(t, 1, textFuzziness(nil, "kuberntes nodes"))
+	require.Equal(t, 0, textFuzziness(nil, "tag3"))
+	require.Equal(t, 0, textFuzziness(nil, "gen_1"))
+	require.Equal(t, 0, textFuzziness(nil, ""))
+	require.Equal(t, 0, textFuzziness(&exact, "kuberntes"))
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"slices"
 	"testing"
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -491,6 +494,242 @@ func TestCanSearchByTitle(t *testing.T) {
 	})
 }
 
+func TestCanSearchByQueryContent(t *testing.T) {
+	key := &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	doc := func(name string, metrics []string, queries ...string) *resource.BulkIndexItem {
+		return &resource.BulkIndexItem{
+			Action: resource.ActionIndex,
+			Doc: &resource.IndexableDocument{
+				RV:   1,
+				Name: name,
+				Key: &resourcepb.ResourceKey{
+					Name:      name,
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+				},
+				Title: name,
+				Fields: map[string]any{
+					search.DASHBOARD_METRIC_NAMES:  metrics,
+					search.DASHBOARD_PANEL_QUERIES: queries,
+				},
+			},
+		}
+	}
+
+	index := newTestDashboardsIndex(t, threshold, 3, 3, noop)
+	err := index.BulkIndex(&resource.BulkIndexRequest{
+		Items: []*resource.BulkIndexItem{
+			doc("cpu", []string{"node_cpu_seconds_total"},
+				search.PanelQuery(1, `sum(rate(node_cpu_seconds_total{mode!="idle"}[$__rate_interval]))`)),
+			doc("memory", []string{"node_memory_MemFree_bytes", "node_memory_MemTotal_bytes"},
+				search.PanelQuery(2, "node_memory_MemFree_bytes / node_memory_MemTotal_bytes")),
+			doc("logs", nil,
+				search.PanelQuery(3, "{app=\"api\"}\n  |= \"error\"")),
+		},
+	})
+	require.NoError(t, err)
+
+	find := func(req *resourcepb.Requirement) []string {
+		query := newTestQuery("")
+		query.Options.Fields = []*resourcepb.Requirement{req}
+		res, err := index.Search(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		names := []string{}
+		for _, row := range res.Results.Rows {
+			names = append(names, row.Key.Name)
+		}
+		return names
+	}
+
+	t.Run("metric names", func(t *testing.T) {
+		require.Equal(t, []string{"cpu"}, find(&resourcepb.Requirement{
+			Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_METRIC_NAMES,
+			Operator: "in",
+			Values:   []string{"node_cpu_seconds_total"},
+		}))
+		require.Empty(t, find(&resourcepb.Requirement{
+			Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_METRIC_NAMES,
+			Operator: "in",
+			Values:   []string{"node_cpu"},
+		}))
+	})
+
+	t.Run("query contains", func(t *testing.T) {
+		contains := func(v ...string) []string {
+			return find(&resourcepb.Requirement{
+				Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_PANEL_QUERIES,
+				Operator: string(search.OperatorContains),
+				Values:   v,
+			})
+		}
+		require.Equal(t, []string{"cpu"}, contains("node_cpu"))
+		require.Equal(t, []string{"cpu", "memory"}, contains("node_"))
+		require.Equal(t, []string{"cpu"}, contains(`{mode!="idle"}[$__rate_interval]`))
+		require.Equal(t, []string{"logs"}, contains(`} |= "error"`)) // whitespace is collapsed
+		require.Equal(t, []string{"cpu", "logs"}, contains("node_cpu", "error"))
+		require.Empty(t, contains("node_disk"))
+		require.Empty(t, contains("rate(.*)")) // not a regexp
+	})
+
+	t.Run("query contains is validated", func(t *testing.T) {
+		for _, req := range []*resourcepb.Requirement{
+			{
+				Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_PANEL_QUERIES,
+				Operator: string(search.OperatorContains),
+				Values:   []string{"up"},
+			},
+			{
+				Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_METRIC_NAMES,
+				Operator: string(search.OperatorContains),
+				Values:   []string{"node_cpu"},
+			},
+		} {
+			query := newTestQuery("")
+			query.Options.Fields = []*resourcepb.Requirement{req}
+			res, err := index.Search(context.Background(), nil, query, nil)
+			require.NoError(t, err)
+			require.NotNil(t, res.Error)
+			require.Equal(t, int32(http.StatusBadRequest), res.Error.Code)
+		}
+	})
+
+	t.Run("panel query", func(t *testing.T) {
+		id, expr, ok := search.ParsePanelQuery(search.PanelQuery(12, "up\n  == 1"))
+		require.True(t, ok)
+		require.Equal(t, int64(12), id)
+		require.Equal(t, "up == 1", expr)
+
+		_, _, ok = search.ParsePanelQuery("up")
+		require.False(t, ok)
+	})
+}
+
+func TestCanSearchWithAdvancedQuery(t *testing.T) {
+	key := &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	doc := func(name string, title string, folder string, tags []string, views int64) *resource.BulkIndexItem {
+		return &resource.BulkIndexItem{
+			Action: resource.ActionIndex,
+			Doc: &resource.IndexableDocument{
+				RV:   1,
+				Name: name,
+				Key: &resourcepb.ResourceKey{
+					Name:      name,
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+				},
+				Title:  title,
+				Folder: folder,
+				Tags:   tags,
+				Fields: map[string]any{
+					search.DASHBOARD_VIEWS_LAST_7_DAYS: views,
+				},
+			},
+		}
+	}
+
+	index := newTestDashboardsIndex(t, threshold, 4, 4, noop)
+	err := index.BulkIndex(&resource.BulkIndexRequest{
+		Items: []*resource.BulkIndexItem{
+			doc("k8s", "Kubernetes nodes", "platform", []string{"prod"}, 5),
+			doc("k8s-dev", "Kubernetes nodes overview", "sandbox", []string{"dev"}, 500),
+			doc("mysql", "MySQL overview", "platform", []string{"prod", "db"}, 50),
+			doc("nodes", "Node exporter", "platform", []string{"dev"}, 0),
+		},
+	})
+	require.NoError(t, err)
+
+	find := func(req *resourcepb.ResourceSearchRequest) []string {
+		res, err := index.Search(context.Background(), nil, req, nil)
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		names := []string{}
+		for _, row := range res.Results.Rows {
+			names = append(names, row.Key.Name)
+		}
+		return names
+	}
+	advanced := func(q string) []string {
+		req := newTestQuery(q)
+		req.AdvancedQuery = true
+		names := find(req)
+		slices.Sort(names)
+		return names
+	}
+
+	t.Run("fuzzy", func(t *testing.T) {
+		req := newTestQuery("kuberntes")
+		names := find(req) // a single typo is allowed by default
+		slices.Sort(names)
+		require.Equal(t, []string{"k8s", "k8s-dev"}, names)
+
+		req.Fuzziness = proto.Int64(0)
+		require.Empty(t, find(req))
+
+		req = newTestQuery("kubrntes")
+		require.Empty(t, find(req))
+
+		req.Fuzziness = proto.Int64(2)
+		names = find(req)
+		slices.Sort(names)
+		require.Equal(t, []string{"k8s", "k8s-dev"}, names)
+
+		req.Fuzziness = proto.Int64(3)
+		res, err := index.Search(context.Background(), nil, req, nil)
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		require.Equal(t, int32(400), res.Error.Code)
+	})
+
+	t.Run("phrase", func(t *testing.T) {
+		require.Equal(t, []string{"k8s-dev", "mysql"}, advanced("overview"))
+		require.Equal(t, []string{"k8s", "k8s-dev"}, advanced(`"kubernetes nodes"`))
+		require.Equal(t, []string{"k8s-dev"}, advanced(`"nodes overview"`))
+		require.Empty(t, advanced(`"overview nodes"`))
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		require.Equal(t, []string{"k8s-dev"}, advanced("kubernetes AND overview"))
+		require.Equal(t, []string{"k8s-dev"}, advanced("kubernetes overview"))
+		require.Equal(t, []string{"k8s", "k8s-dev", "mysql"}, advanced("kubernetes OR mysql"))
+		require.Equal(t, []string{"k8s"}, advanced("kubernetes -overview"))
+		require.Equal(t, []string{"k8s", "nodes"}, advanced("-overview"))
+	})
+
+	t.Run("field scoped terms", func(t *testing.T) {
+		require.Equal(t, []string{"k8s", "mysql"}, advanced("tag:prod"))
+		require.Equal(t, []string{"k8s", "mysql", "nodes"}, advanced("folder:platform"))
+		require.Equal(t, []string{"mysql"}, advanced("folder:platform overview"))
+		require.Equal(t, []string{"k8s", "nodes"}, advanced("folder:platform -tag:db"))
+		require.Equal(t, []string{"k8s-dev", "mysql"}, advanced("tag:db OR folder:sandbox"))
+	})
+
+	t.Run("boost by views", func(t *testing.T) {
+		req := newTestQuery("kubernetes")
+		req.AdvancedQuery = true
+		require.Equal(t, []string{"k8s", "k8s-dev"}, find(req)) // the shorter title scores higher
+
+		req.Boost = []string{search.DASHBOARD_VIEWS_LAST_7_DAYS}
+		require.Equal(t, []string{"k8s-dev", "k8s"}, find(req))
+
+		req.Boost = []string{resource.SEARCH_FIELD_TITLE}
+		res, err := index.Search(context.Background(), nil, req, nil)
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		require.Equal(t, int32(400), res.Error.Code)
+	})
+}
+
 func newTestQuery(query string) *resourcepb.ResourceSearchRequest {
 	return &resourcepb.ResourceSearchRequest{
 		Options: &resourcepb.ListOptions{
@@ -594,6 +594,100 @@ func TestBleveBackend(t *testing.T) {
 	})
 }
 
+func TestBleveFacets(t *testing.T) {
+	backend, _ := setupBleveBackend(t, 5, time.Hour, "")
+	ctx := identity.WithRequester(context.Background(), &user.SignedInUser{Namespace: "ns"})
+	key := &resourcepb.ResourceKey{
+		Namespace: "ns",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	doc := func(name string, created int64, createdBy string, panelTypes []string) *resource.BulkIndexItem {
+		return &resource.BulkIndexItem{
+			Action: resource.ActionIndex,
+			Doc: &resource.IndexableDocument{
+				RV:   1,
+				Name: name,
+				Key: &resourcepb.ResourceKey{
+					Name:      name,
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+				},
+				Title:     name,
+				Created:   created,
+				CreatedBy: createdBy,
+				Owners:    []string{"Team:" + createdBy},
+				Fields: map[string]any{
+					DASHBOARD_PANEL_TYPES: panelTypes,
+				},
+			},
+		}
+	}
+
+	index, err := backend.BuildIndex(ctx, resource.NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	}, 3, 1, nil, "test", func(index resource.ResourceIndex) (int64, error) {
+		return 1, index.BulkIndex(&resource.BulkIndexRequest{
+			Items: []*resource.BulkIndexItem{
+				doc("aaa", 1609462800000, "user:a", []string{"timeseries", "table"}), // 2021
+				doc("bbb", 1640998800000, "user:a", []string{"timeseries"}),          // 2022
+				doc("ccc", 1640998800000, "user:b", []string{"time series"}),         // 2022
+			},
+		})
+	}, nil, false, false)
+	require.NoError(t, err)
+
+	y2022 := int64(1640995200000)
+	y2030 := int64(1893456000000)
+	rsp, err := index.Search(ctx, nil, &resourcepb.ResourceSearchRequest{
+		Options: &resourcepb.ListOptions{Key: key},
+		Limit:   10,
+		Facet: map[string]*resourcepb.ResourceSearchRequest_Facet{
+			"creator":    {Field: resource.SEARCH_FIELD_CREATED_BY, Limit: 10},
+			"updater":    {Field: resource.SEARCH_FIELD_UPDATED_BY, Limit: 10},
+			"owner":      {Field: resource.SEARCH_FIELD_OWNERS, Limit: 10},
+			"panel_type": {Field: DASHBOARD_PANEL_TYPES, Limit: 10},
+			"created": {Field: resource.SEARCH_FIELD_CREATED, Ranges: []*resourcepb.ResourceSearchRequest_FacetRange{
+				{Name: "all"},
+				{Name: "future", Min: &y2030},
+				{Name: "2022", Min: &y2022, Max: &y2030},
+				{Name: "before", Max: &y2022},
+			}},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+
+	terms := func(f *resourcepb.ResourceSearchResponse_Facet) map[string]int64 {
+		m := map[string]int64{}
+		for _, t := range f.Terms {
+			m[t.Term] = t.Count
+		}
+		return m
+	}
+	require.Equal(t, map[string]int64{"user:a": 2, "user:b": 1}, terms(rsp.Facet["creator"]))
+	require.Equal(t, map[string]int64{"Team:user:a": 2, "Team:user:b": 1}, terms(rsp.Facet["owner"]))
+
+	// empty values are counted as missing
+	require.Empty(t, rsp.Facet["updater"].Terms)
+	require.Equal(t, int64(3), rsp.Facet["updater"].Missing)
+
+	// panel types are not split into words, and the field is returned without the prefix
+	require.Equal(t, DASHBOARD_PANEL_TYPES, rsp.Facet["panel_type"].Field)
+	require.Equal(t, map[string]int64{"timeseries": 2, "table": 1, "time series": 1}, terms(rsp.Facet["panel_type"]))
+
+	// ranges are returned in the requested order (including the empty ones)
+	require.Equal(t, []*resourcepb.ResourceSearchResponse_RangeFacet{
+		{Name: "all", Count: 3},
+		{Name: "future", Count: 0},
+		{Name: "2022", Count: 2},
+		{Name: "before", Count: 1},
+	}, rsp.Facet["created"].Ranges)
+}
+
 func TestGetSortFields(t *testing.T) {
 	t.Run("will prepend 'fields.' to sort fields when they are dashboard fields", func(t *testing.T) {
 		searchReq := &resourcepb.ResourceSearchRequest{
@@ -4,15 +4,24 @@ import (
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
 	"github.com/blevesearch/bleve/v2/analysis/token/edgengram"
 	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/token/ngram"
 	"github.com/blevesearch/bleve/v2/analysis/token/unique"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
 	"github.com/blevesearch/bleve/v2/analysis/tokenizer/whitespace"
 	"github.com/blevesearch/bleve/v2/mapping"
 )
 
 const TITLE_ANALYZER = "title_analyzer"
+const TRIGRAM_ANALYZER = "trigram_analyzer"
+
+// Length of the ngrams indexed by the TRIGRAM_ANALYZER
+const trigramSize = 3
 
 func RegisterCustomAnalyzers(mapper *mapping.IndexMappingImpl) error {
-	return registerTitleAnalyzer(mapper)
+	if err := registerTitleAnalyzer(mapper); err != nil {
+		return err
+	}
+	return registerTrigramAnalyzer(mapper)
 }
 
 // The registerTitleAnalyzer function defines a custom analyzer for the title field.
@@ -46,3 +55,24 @@ func registerTitleAnalyzer(mapper *mapping.IndexMappingImpl) error {
 
 	return nil
 }
+
+// The registerTrigramAnalyzer function defines a custom analyzer for substring matching.
+// The whole value is a single token, split into every 3 character substring.
+// For example, the value "rate(x)" will be tokenized into "rat", "ate", "te(", "e(x", "(x)".
+func registerTrigramAnalyzer(mapper *mapping.IndexMappingImpl) error {
+	trigramTokenFilter := map[string]interface{}{
+		"type": ngram.Name,
+		"min":  float64(trigramSize),
+		"max":  float64(trigramSize),
+	}
+	err := mapper.AddCustomTokenFilter("trigram_filter", trigramTokenFilter)
+	if err != nil {
+		return err
+	}
+
+	return mapper.AddCustomAnalyzer(TRIGRAM_ANALYZER, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []string{"trigram_filter", unique.Name},
+	})
+}
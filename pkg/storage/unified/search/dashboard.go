@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -25,6 +28,10 @@ const DASHBOARD_PANEL_TYPES = "panel_types"
 const DASHBOARD_DS_TYPES = "ds_types"
 const DASHBOARD_TRANSFORMATIONS = "transformation"
 const DASHBOARD_LIBRARY_PANEL_REFERENCE = "reference.LibraryPanel"
+const DASHBOARD_DATASOURCE_REFERENCE = "reference.DataSource"
+const DASHBOARD_METRIC_NAMES = "metric_names"
+const DASHBOARD_PANEL_QUERIES = "panel_queries"
+const DASHBOARD_PANEL_QUERIES_TRIGRAMS = "panel_queries_trigrams"
 
 //------------------------------------------------------------
 // The following fields are added in enterprise
@@ -70,6 +77,24 @@ func DashboardBuilder(namespaced resource.NamespacedDocumentSupplier) (resource.
 				Filterable: true,
 			},
 		},
+		{
+			Name:        DASHBOARD_METRIC_NAMES,
+			Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "Metric names referenced by the panel queries",
+			Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+				Filterable: true,
+			},
+		},
+		{
+			Name:        DASHBOARD_PANEL_QUERIES,
+			Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "Query expressions prefixed with the panel id, eg 4:rate(http_requests_total[5m])",
+			Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+				Filterable: true,
+			},
+		},
 		{
 			Name:        DASHBOARD_ERRORS_TODAY,
 			Type:        resourcepb.ResourceTableColumnDefinition_INT64,
@@ -278,6 +303,8 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 	panelTypes := []string{}
 	transformations := []string{}
 	dsTypes := []string{}
+	metrics := []string{}
+	queries := []string{}
 
 	for _, p := range summary.Panels {
 		if p.Type != "" {
@@ -296,6 +323,17 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 		}
 	}
 
+	for _, p := range allPanels(summary.Panels) {
+		for _, m := range p.Metrics {
+			if !slices.Contains(metrics, m) {
+				metrics = append(metrics, m)
+			}
+		}
+		for _, q := range p.Queries {
+			queries = append(queries, PanelQuery(p.ID, q))
+		}
+	}
+
 	for _, ds := range summary.Datasource {
 		dsTypes = append(dsTypes, ds.Type)
 		doc.References = append(doc.References, resource.ResourceReference{
@@ -327,6 +365,13 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 		sort.Strings(transformations)
 		doc.Fields[DASHBOARD_TRANSFORMATIONS] = transformations
 	}
+	if len(metrics) > 0 {
+		sort.Strings(metrics)
+		doc.Fields[DASHBOARD_METRIC_NAMES] = metrics
+	}
+	if len(queries) > 0 {
+		doc.Fields[DASHBOARD_PANEL_QUERIES] = queries
+	}
 
 	// Add the stats fields
 	for k, v := range s.Stats[summary.UID] {
@@ -336,6 +381,35 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 	return doc, nil
 }
 
+// allPanels includes the panels nested in collapsed rows
+func allPanels(panels []dashboard.PanelSummaryInfo) []dashboard.PanelSummaryInfo {
+	all := make([]dashboard.PanelSummaryInfo, 0, len(panels))
+	for _, p := range panels {
+		all = append(all, p)
+		all = append(all, allPanels(p.Collapsed)...)
+	}
+	return all
+}
+
+// PanelQuery is the indexed value for a panel query.  Whitespace is collapsed so
+// the expression can be matched as a single line
+func PanelQuery(panelID int64, query string) string {
+	return fmt.Sprintf("%d:%s", panelID, strings.Join(strings.Fields(query), " "))
+}
+
+// ParsePanelQuery splits an indexed panel query into the panel id and expression
+func ParsePanelQuery(v string) (int64, string, bool) {
+	id, query, ok := strings.Cut(v, ":")
+	if !ok {
+		return 0, "", false
+	}
+	panelID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return panelID, query, true
+}
+
 func DashboardFields() []string {
 	baseFields := []string{
 		DASHBOARD_SCHEMA_VERSION,
@@ -343,6 +417,8 @@ func DashboardFields() []string {
 		DASHBOARD_PANEL_TYPES,
 		DASHBOARD_DS_TYPES,
 		DASHBOARD_TRANSFORMATIONS,
+		DASHBOARD_METRIC_NAMES,
+		DASHBOARD_PANEL_QUERIES,
 	}
 
 	return append(baseFields, UsageInsightsFields()...)
@@ -79,6 +79,7 @@ func TestDashboardDocumentBuilder(t *testing.T) {
 	// Dashboards (custom)
 	doSnapshotTests(t, builder, "dashboard", key, []string{
 		"aaa",
+		"bbb",
 	})
 
 	// Standard
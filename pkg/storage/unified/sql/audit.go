@@ -0,0 +1,108 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+var (
+	_ resource.TransactionalAuditSink = (*backend)(nil)
+	_ resource.AuditQuerier           = (*backend)(nil)
+)
+
+// AuditsWrites implements resource.TransactionalAuditSink.
+func (b *backend) AuditsWrites() bool {
+	return b.audit
+}
+
+// auditInTx saves the audit event of a write in the transaction of the write.
+// The resource version is set with the resource version of the write.
+func (b *backend) auditInTx(ctx context.Context, tx db.Tx, event resource.WriteEvent) error {
+	if !b.audit || event.Audit == nil {
+		return nil
+	}
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceAuditInsert, sqlResourceAuditInsertRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Event:       event.Audit,
+	}); err != nil {
+		return fmt.Errorf("insert audit event: %w", err)
+	}
+	return nil
+}
+
+// WriteAuditEvent saves the event in the resource_audit table.
+func (b *backend) WriteAuditEvent(ctx context.Context, event *resourcepb.AuditEvent) error {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"WriteAuditEvent")
+	defer span.End()
+
+	if _, err := dbutil.Exec(ctx, b.db, sqlResourceAuditInsert, sqlResourceAuditInsertRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Event:       event,
+	}); err != nil {
+		return fmt.Errorf("insert audit event: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns the saved events matching the request, newest first.
+func (b *backend) ListAuditEvents(ctx context.Context, req *resourcepb.AuditQueryRequest, after *resource.AuditContinueToken) ([]*resourcepb.AuditEvent, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ListAuditEvents")
+	defer span.End()
+
+	events, err := dbutil.Query(ctx, b.db, sqlResourceAuditList, &sqlResourceAuditListRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Request:     req,
+		After:       after,
+		Response:    new(auditListResponse),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	return events, nil
+}
+
+// AuditSinks creates the sinks configured with `audit_sinks` in the [unified_storage] section.
+// The sql sink saves the events in the same database as the backend, and can be queried.
+func AuditSinks(cfg *setting.Cfg, store Backend) ([]resource.AuditSink, error) {
+	sinks := make([]resource.AuditSink, 0, len(cfg.AuditSinks))
+	for _, name := range cfg.AuditSinks {
+		switch name {
+		case "sql":
+			sink, ok := store.(resource.AuditSink)
+			if !ok {
+				return nil, fmt.Errorf("the storage backend does not support the sql audit sink")
+			}
+			sinks = append(sinks, sink)
+		case "file":
+			path := cfg.AuditFilePath
+			if path == "" {
+				path = filepath.Join(cfg.DataPath, "unified-storage-audit.log")
+			}
+			sink, err := resource.NewFileAuditSink(path)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "syslog":
+			if cfg.AuditSyslogAddress == "" {
+				return nil, fmt.Errorf("audit_syslog_address is required for the syslog audit sink")
+			}
+			sink, err := resource.NewSyslogAuditSink(cfg.AuditSyslogNetwork, cfg.AuditSyslogAddress)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, fmt.Errorf("unknown audit sink: %s", name)
+		}
+	}
+	return sinks, nil
+}
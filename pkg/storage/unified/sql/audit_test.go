@@ -0,0 +1,148 @@
+package sql
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestBackend_Audit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("write event", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.ExecWithResult("insert resource_audit", 0, 1)
+
+		err := b.WriteAuditEvent(ctx, &resourcepb.AuditEvent{
+			Guid: "abc",
+			Key:  &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs", Name: "nm"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("list events", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource_audit", 14, Rows{
+			{"abc", 1000, "gr", "rs", "ns", "nm", "folder", 2, "user:u123", 1, 2, "ui", "", `[]`},
+		})
+
+		events, err := b.ListAuditEvents(ctx, &resourcepb.AuditQueryRequest{
+			Key:   &resourcepb.ResourceKey{Namespace: "ns"},
+			Limit: 10,
+		}, &resource.AuditContinueToken{Timestamp: 2000, GUID: "def"})
+		require.NoError(t, err)
+		require.Equal(t, []*resourcepb.AuditEvent{{
+			Guid:                    "abc",
+			Timestamp:               1000,
+			Action:                  resourcepb.WatchEvent_MODIFIED,
+			Key:                     &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs", Name: "nm"},
+			Folder:                  "folder",
+			Identity:                "user:u123",
+			PreviousResourceVersion: 1,
+			ResourceVersion:         2,
+			Origin:                  "ui",
+			Patch:                   "[]",
+		}}, events)
+	})
+
+	t.Run("error listing events", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithErr("select resource_audit", errTest)
+
+		_, err := b.ListAuditEvents(ctx, &resourcepb.AuditQueryRequest{
+			Key:   &resourcepb.ResourceKey{Namespace: "ns"},
+			Limit: 10,
+		}, nil)
+		require.ErrorIs(t, err, errTest)
+	})
+
+	t.Run("write event with the change", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+		b.audit = true
+		b.rvManager.auditRV = true
+
+		meta, err := utils.MetaAccessor(&unstructured.Unstructured{Object: map[string]any{}})
+		require.NoError(t, err)
+		event := resource.WriteEvent{
+			Type:   resourcepb.WatchEvent_ADDED,
+			Key:    resKey,
+			Object: meta,
+			GUID:   "abc",
+			Audit:  &resourcepb.AuditEvent{Guid: "abc", Key: resKey},
+		}
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("insert resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		b.ExecWithResult("insert resource_audit", 0, 1)
+		expectSuccessfulResourceVersionLock(t, b.TestDBProvider, 100, 200)
+		b.ExecWithResult("update resource set resource_version", 0, 1)
+		b.ExecWithResult("update resource_history set resource_version", 0, 1)
+		b.ExecWithResult("update resource_audit set resource_version", 0, 1)
+		b.ExecWithResult("update resource_version set resource_version", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		rv, err := b.create(ctx, event)
+		require.NoError(t, err)
+		require.Equal(t, int64(200), rv)
+		require.True(t, b.AuditsWrites())
+	})
+
+	t.Run("failing to save the event fails the change", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+		b.audit = true
+
+		meta, err := utils.MetaAccessor(&unstructured.Unstructured{Object: map[string]any{}})
+		require.NoError(t, err)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("insert resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		b.ExecWithErr("insert resource_audit", errTest)
+		b.SQLMock.ExpectRollback()
+
+		_, err = b.create(ctx, resource.WriteEvent{
+			Type:   resourcepb.WatchEvent_ADDED,
+			Key:    resKey,
+			Object: meta,
+			Audit:  &resourcepb.AuditEvent{Key: resKey},
+		})
+		require.ErrorIs(t, err, errTest)
+	})
+}
+
+func TestAuditSinks(t *testing.T) {
+	t.Parallel()
+	b, _ := setupBackendTest(t)
+
+	cfg := setting.NewCfg()
+	cfg.AuditSinks = []string{"sql", "file"}
+	cfg.AuditFilePath = filepath.Join(t.TempDir(), "audit.log")
+	sinks, err := AuditSinks(cfg, b.backend)
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	require.Equal(t, b.backend, sinks[0])
+
+	cfg.AuditSinks = []string{"syslog"}
+	_, err = AuditSinks(cfg, b.backend)
+	require.ErrorContains(t, err, "audit_syslog_address")
+
+	cfg.AuditSinks = []string{"unknown"}
+	_, err = AuditSinks(cfg, b.backend)
+	require.ErrorContains(t, err, "unknown audit sink")
+}
@@ -42,6 +42,8 @@ type Backend interface {
 	resource.LifecycleHooks
 }
 
+var _ resource.TransactionalBackend = (*backend)(nil)
+
 type BackendOptions struct {
 	DBProvider      db.DBProvider
 	Tracer          trace.Tracer
@@ -55,6 +57,9 @@ type BackendOptions struct {
 	// Will be removed once fully rolled out.
 	withPruner bool
 
+	// If true, the audit events of the writes are saved in the same transaction as the changes
+	Audit bool
+
 	// testing
 	SimulatedNetworkLatency time.Duration // slows down the create transactions by a fixed amount
 }
@@ -88,6 +93,7 @@ func NewBackend(opts BackendOptions) (Backend, error) {
 		bulkLock:                &bulkLock{running: make(map[string]bool)},
 		simulatedNetworkLatency: opts.SimulatedNetworkLatency,
 		withPruner:              opts.withPruner,
+		audit:                   opts.Audit,
 	}, nil
 }
 
@@ -150,6 +156,9 @@ type backend struct {
 
 	historyPruner pruner
 	withPruner    bool
+
+	// save the audit events of the writes with the changes
+	audit bool
 }
 
 func (b *backend) Init(ctx context.Context) error {
@@ -179,9 +188,10 @@ func (b *backend) initLocked(ctx context.Context) error {
 
 	// Initialize ResourceVersionManager
 	rvManager, err := NewResourceVersionManager(ResourceManagerOptions{
-		Dialect: b.dialect,
-		DB:      b.db,
-		Tracer:  b.tracer,
+		Dialect:       b.dialect,
+		DB:            b.db,
+		Tracer:        b.tracer,
+		UpdateAuditRV: b.audit,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create resource version manager: %w", err)
@@ -338,35 +348,9 @@ func (b *backend) create(ctx context.Context, event resource.WriteEvent) (int64,
 	}
 
 	rv, err := b.rvManager.ExecWithRV(ctx, event.Key, func(tx db.Tx) (string, error) {
-		// 1. Insert into resource
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        event.GUID,
-		}); err != nil {
-			if IsRowAlreadyExistsError(err) {
-				return event.GUID, resource.ErrResourceAlreadyExists
-			}
-			return event.GUID, fmt.Errorf("insert into resource: %w", err)
+		if err := b.createInTx(ctx, tx, event, folder); err != nil {
+			return event.GUID, err
 		}
-
-		// 2. Insert into resource history
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			Generation:  event.Object.GetGeneration(),
-			GUID:        event.GUID,
-		}); err != nil {
-			return event.GUID, fmt.Errorf("insert into resource history: %w", err)
-		}
-		_ = b.historyPruner.Add(pruningKey{
-			namespace: event.Key.Namespace,
-			group:     event.Key.Group,
-			resource:  event.Key.Resource,
-			name:      event.Key.Name,
-		})
 		if b.simulatedNetworkLatency > 0 {
 			time.Sleep(b.simulatedNetworkLatency)
 		}
@@ -427,34 +411,7 @@ func (b *backend) update(ctx context.Context, event resource.WriteEvent) (int64,
 
 	// Use rvManager.ExecWithRV instead of direct transaction
 	rv, err := b.rvManager.ExecWithRV(ctx, event.Key, func(tx db.Tx) (string, error) {
-		// 1. Update resource
-		_, err := dbutil.Exec(ctx, tx, sqlResourceUpdate, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        event.GUID,
-		})
-		if err != nil {
-			return event.GUID, fmt.Errorf("resource update: %w", err)
-		}
-
-		// 2. Insert into resource history
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        event.GUID,
-			Generation:  event.Object.GetGeneration(),
-		}); err != nil {
-			return event.GUID, fmt.Errorf("insert into resource history: %w", err)
-		}
-		_ = b.historyPruner.Add(pruningKey{
-			namespace: event.Key.Namespace,
-			group:     event.Key.Group,
-			resource:  event.Key.Resource,
-			name:      event.Key.Name,
-		})
-		return event.GUID, nil
+		return event.GUID, b.updateInTx(ctx, tx, event, folder)
 	})
 
 	if err != nil {
@@ -482,33 +439,7 @@ func (b *backend) delete(ctx context.Context, event resource.WriteEvent) (int64,
 		folder = event.Object.GetFolder()
 	}
 	rv, err := b.rvManager.ExecWithRV(ctx, event.Key, func(tx db.Tx) (string, error) {
-		// 1. delete from resource
-		_, err := dbutil.Exec(ctx, tx, sqlResourceDelete, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			GUID:        event.GUID,
-		})
-		if err != nil {
-			return event.GUID, fmt.Errorf("delete resource: %w", err)
-		}
-
-		// 2. Add event to resource history
-		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
-			SQLTemplate: sqltemplate.New(b.dialect),
-			WriteEvent:  event,
-			Folder:      folder,
-			GUID:        event.GUID,
-			Generation:  0, // object does not exist
-		}); err != nil {
-			return event.GUID, fmt.Errorf("insert into resource history: %w", err)
-		}
-		_ = b.historyPruner.Add(pruningKey{
-			namespace: event.Key.Namespace,
-			group:     event.Key.Group,
-			resource:  event.Key.Resource,
-			name:      event.Key.Name,
-		})
-		return event.GUID, nil
+		return event.GUID, b.deleteInTx(ctx, tx, event, folder)
 	})
 
 	if err != nil {
@@ -527,6 +458,160 @@ func (b *backend) delete(ctx context.Context, event resource.WriteEvent) (int64,
 	return rv, nil
 }
 
+// WriteEvents saves all events within a single transaction.
+// Watch events are only sent once everything has been committed.
+func (b *backend) WriteEvents(ctx context.Context, events []resource.WriteEvent) ([]int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"WriteEvents")
+	defer span.End()
+
+	folders := make([]string, len(events))
+	ops := make([]TransactionOp, len(events))
+	for i, event := range events {
+		if event.Object != nil {
+			folders[i] = event.Object.GetFolder()
+		}
+
+		var write func(context.Context, db.Tx, resource.WriteEvent, string) error
+		switch event.Type {
+		case resourcepb.WatchEvent_ADDED:
+			write = b.createInTx
+		case resourcepb.WatchEvent_MODIFIED:
+			write = b.updateInTx
+		case resourcepb.WatchEvent_DELETED:
+			write = b.deleteInTx
+		default:
+			return nil, &resource.TransactionItemError{Index: i, Err: fmt.Errorf("unsupported event type")}
+		}
+
+		ops[i] = TransactionOp{
+			Key: event.Key,
+			Fn: func(tx db.Tx) (string, error) {
+				if err := write(ctx, tx, event, folders[i]); err != nil {
+					return event.GUID, &resource.TransactionItemError{Index: i, Err: err}
+				}
+				return event.GUID, nil
+			},
+		}
+	}
+
+	rvs, err := b.rvManager.ExecTransactionWithRV(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, event := range events {
+		b.notifier.send(ctx, &resource.WrittenEvent{
+			Type:            event.Type,
+			Key:             event.Key,
+			PreviousRV:      event.PreviousRV,
+			Value:           event.Value,
+			ResourceVersion: rvs[i],
+			Folder:          folders[i],
+		})
+	}
+	return rvs, nil
+}
+
+func (b *backend) createInTx(ctx context.Context, tx db.Tx, event resource.WriteEvent, folder string) error {
+	// 1. Insert into resource
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        event.GUID,
+	}); err != nil {
+		if IsRowAlreadyExistsError(err) {
+			return resource.ErrResourceAlreadyExists
+		}
+		return fmt.Errorf("insert into resource: %w", err)
+	}
+
+	// 2. Insert into resource history
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		Generation:  event.Object.GetGeneration(),
+		GUID:        event.GUID,
+		BlobUID:     blobUID(event.Object),
+	}); err != nil {
+		return fmt.Errorf("insert into resource history: %w", err)
+	}
+	if err := b.auditInTx(ctx, tx, event); err != nil {
+		return err
+	}
+	b.addPruningKey(event.Key)
+	return nil
+}
+
+func (b *backend) updateInTx(ctx context.Context, tx db.Tx, event resource.WriteEvent, folder string) error {
+	// 1. Update resource
+	_, err := dbutil.Exec(ctx, tx, sqlResourceUpdate, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        event.GUID,
+	})
+	if err != nil {
+		return fmt.Errorf("resource update: %w", err)
+	}
+
+	// 2. Insert into resource history
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        event.GUID,
+		BlobUID:     blobUID(event.Object),
+		Generation:  event.Object.GetGeneration(),
+	}); err != nil {
+		return fmt.Errorf("insert into resource history: %w", err)
+	}
+	if err := b.auditInTx(ctx, tx, event); err != nil {
+		return err
+	}
+	b.addPruningKey(event.Key)
+	return nil
+}
+
+func (b *backend) deleteInTx(ctx context.Context, tx db.Tx, event resource.WriteEvent, folder string) error {
+	// 1. delete from resource
+	_, err := dbutil.Exec(ctx, tx, sqlResourceDelete, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		GUID:        event.GUID,
+	})
+	if err != nil {
+		return fmt.Errorf("delete resource: %w", err)
+	}
+
+	// 2. Add event to resource history
+	if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		WriteEvent:  event,
+		Folder:      folder,
+		GUID:        event.GUID,
+		BlobUID:     blobUID(event.Object),
+		Generation:  0, // object does not exist
+	}); err != nil {
+		return fmt.Errorf("insert into resource history: %w", err)
+	}
+	if err := b.auditInTx(ctx, tx, event); err != nil {
+		return err
+	}
+	b.addPruningKey(event.Key)
+	return nil
+}
+
+func (b *backend) addPruningKey(key *resourcepb.ResourceKey) {
+	_ = b.historyPruner.Add(pruningKey{
+		namespace: key.Namespace,
+		group:     key.Group,
+		resource:  key.Resource,
+		name:      key.Name,
+	})
+}
+
 func (b *backend) ReadResource(ctx context.Context, req *resourcepb.ReadRequest) *resource.BackendReadResponse {
 	_, span := b.tracer.Start(ctx, tracePrefix+".Read")
 	defer span.End()
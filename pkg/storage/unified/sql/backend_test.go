@@ -413,6 +413,72 @@ type readHistoryRow struct {
 	value            string
 }
 
+func TestBackend_WriteEvents(t *testing.T) {
+	t.Parallel()
+	meta, err := utils.MetaAccessor(&unstructured.Unstructured{
+		Object: map[string]any{},
+	})
+	require.NoError(t, err)
+	otherKey := &resourcepb.ResourceKey{
+		Namespace: "ns",
+		Group:     "gr2",
+		Resource:  "rs",
+		Name:      "nm",
+	}
+	events := []resource.WriteEvent{
+		{Type: resourcepb.WatchEvent_ADDED, Key: otherKey, Object: meta},
+		{Type: resourcepb.WatchEvent_MODIFIED, Key: resKey, Object: meta},
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("insert resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		b.ExecWithResult("update resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		// resource versions are allocated per group/resource in a stable order
+		expectSuccessfulResourceVersionLock(t, b.TestDBProvider, 100, 200) // gr/rs
+		expectSuccessfulResourceVersionSaveRV(t, b.TestDBProvider)
+		expectSuccessfulResourceVersionLock(t, b.TestDBProvider, 300, 0) // gr2/rs
+		expectSuccessfulResourceVersionSaveRV(t, b.TestDBProvider)
+		b.SQLMock.ExpectCommit()
+
+		rvs, err := b.WriteEvents(ctx, events)
+		require.NoError(t, err)
+		require.Equal(t, []int64{301, 200}, rvs)
+	})
+
+	t.Run("error rolls back every event", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("insert resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		b.ExecWithErr("update resource", errTest)
+		b.SQLMock.ExpectRollback()
+
+		rvs, err := b.WriteEvents(ctx, events)
+		require.Nil(t, rvs)
+		require.ErrorContains(t, err, "resource update")
+
+		var itemErr *resource.TransactionItemError
+		require.ErrorAs(t, err, &itemErr)
+		require.Equal(t, 1, itemErr.Index)
+	})
+
+	t.Run("unsupported event type", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		_, err := b.WriteEvents(ctx, []resource.WriteEvent{{Key: resKey, Object: meta}})
+		require.ErrorContains(t, err, "unsupported event type")
+	})
+}
+
 func TestBackend_ReadResource(t *testing.T) {
 	t.Parallel()
 
@@ -8,7 +8,7 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
@@ -19,7 +19,9 @@ import (
 )
 
 var (
-	_ resource.BlobSupport = (*backend)(nil)
+	_ resource.BlobSupport          = (*backend)(nil)
+	_ resource.BlobGarbageCollector = (*backend)(nil)
+	_ resource.BlobReferenceCounter = (*backend)(nil)
 )
 
 func (b *backend) SupportsSignedURLs() bool {
@@ -43,7 +45,7 @@ func (b *backend) PutResourceBlob(ctx context.Context, req *resourcepb.PutBlobRe
 	}
 
 	info := &utils.BlobInfo{
-		UID:  uuid.New().String(),
+		UID:  resource.ContentAddressedBlobUID(req.Resource, req.Value),
 		Size: int64(len(req.Value)),
 		Hash: hex.EncodeToString(hasher.Sum(nil)),
 	}
@@ -55,11 +57,24 @@ func (b *backend) PutResourceBlob(ctx context.Context, req *resourcepb.PutBlobRe
 		}, nil
 	}
 
-	// Insert the value
+	// Insert the value, unless the same value was already saved for this resource
+	now := time.Now()
 	err = b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
-		_, err := dbutil.Exec(ctx, tx, sqlResourceBlobInsert, sqlResourceBlobInsertRequest{
+		res, err := dbutil.Exec(ctx, tx, sqlResourceBlobTouch, sqlResourceBlobTouchRequest{
 			SQLTemplate: sqltemplate.New(b.dialect),
-			Now:         time.Now(),
+			Now:         now,
+			Key:         req.Resource,
+			UID:         info.UID,
+		})
+		if err != nil {
+			return err
+		}
+		if count, err := res.RowsAffected(); err != nil || count > 0 {
+			return err
+		}
+		_, err = dbutil.Exec(ctx, tx, sqlResourceBlobInsert, sqlResourceBlobInsertRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			Now:         now,
 			Info:        info,
 			Key:         req.Resource,
 			ContentType: req.ContentType,
@@ -67,6 +82,10 @@ func (b *backend) PutResourceBlob(ctx context.Context, req *resourcepb.PutBlobRe
 		})
 		return err
 	})
+	if err != nil && b.blobExists(ctx, req.Resource, info.UID) {
+		// The same value was saved concurrently, or touched within the (datetime) precision of the database
+		err = nil
+	}
 
 	if err != nil {
 		return &resourcepb.PutBlobResponse{
@@ -120,3 +139,97 @@ func (b *backend) GetResourceBlob(ctx context.Context, key *resourcepb.ResourceK
 	}
 	return rsp, nil
 }
+
+func (b *backend) blobExists(ctx context.Context, key *resourcepb.ResourceKey, uid string) bool {
+	rsp, err := b.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: uid}, true)
+	return err == nil && rsp.Error == nil
+}
+
+// GarbageCollectBlobs removes the blobs of a group/resource that are not referenced by any saved version of the resource
+// they were written for.  References are counted from the blob uid saved with every history row, so blobs are only
+// removed once the history that referenced them has been compacted.
+func (b *backend) GarbageCollectBlobs(ctx context.Context, group, resourceType string, before time.Time, dryRun bool) (resource.BlobGCResult, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"GarbageCollectBlobs")
+	defer span.End()
+
+	result := resource.BlobGCResult{}
+	blobs, err := dbutil.Query(ctx, b.db, sqlResourceBlobList, &sqlResourceBlobListRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Group:       group,
+		Resource:    resourceType,
+		Response:    new(blobListResponse),
+	})
+	if err != nil {
+		return result, fmt.Errorf("list blobs: %w", err)
+	}
+	result.Blobs = int64(len(blobs))
+	if len(blobs) == 0 {
+		return result, nil
+	}
+
+	refs, err := b.CountBlobReferences(ctx, group, resourceType)
+	if err != nil {
+		return result, fmt.Errorf("count blob references: %w", err)
+	}
+	for _, blob := range blobs {
+		count := refs[resource.BlobReference{Namespace: blob.Namespace, Name: blob.Name, UID: blob.UID}]
+		result.References += count
+		if count > 1 {
+			result.Shared++
+		}
+		if count > 0 || !blob.Created.Before(before) {
+			continue
+		}
+		if dryRun {
+			result.Removed++
+			continue
+		}
+		// The created time is checked again, since the same value may have been saved after the blob was listed
+		res, err := dbutil.Exec(ctx, b.db, sqlResourceBlobDelete, sqlResourceBlobDeleteRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			UID:         blob.UID,
+			Before:      before,
+		})
+		if err != nil {
+			return result, fmt.Errorf("delete blob: %w", err)
+		}
+		if removed, err := res.RowsAffected(); err == nil {
+			result.Removed += removed
+		}
+	}
+	return result, nil
+}
+
+// CountBlobReferences counts how many history rows of a group/resource reference each blob
+func (b *backend) CountBlobReferences(ctx context.Context, grp, res string) (map[resource.BlobReference]int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"CountBlobReferences")
+	defer span.End()
+
+	rows, err := dbutil.Query(ctx, b.db, sqlResourceHistoryBlobRefs, &sqlHistoryBlobRefsRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Group:       grp,
+		Resource:    res,
+		Response:    new(historyBlobRefsResponse),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[resource.BlobReference]int64, len(rows))
+	for _, row := range rows {
+		refs[resource.BlobReference{Namespace: row.Namespace, Name: row.Name, UID: row.UID}] += row.Count
+	}
+	return refs, nil
+}
+
+// blobUID returns the uid of the blob referenced by a saved value
+func blobUID(obj metav1.Object) string {
+	if obj == nil {
+		return ""
+	}
+	info := utils.ParseBlobInfo(obj.GetAnnotations()[utils.AnnoKeyBlob])
+	if info == nil {
+		return ""
+	}
+	return info.UID
+}
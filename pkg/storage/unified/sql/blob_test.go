@@ -0,0 +1,150 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestBackend_PutResourceBlob(t *testing.T) {
+	t.Parallel()
+
+	key := &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs", Name: "nm"}
+	req := &resourcepb.PutBlobRequest{
+		Resource:    key,
+		ContentType: "application/json",
+		Value:       []byte(`{"panels": []}`),
+	}
+
+	t.Run("new value", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("update resource_blob", 0, 0)
+		b.ExecWithResult("insert resource_blob", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		rsp, err := b.PutResourceBlob(ctx, req)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Equal(t, resource.ContentAddressedBlobUID(key, req.Value), rsp.Uid)
+		require.Equal(t, int64(len(req.Value)), rsp.Size)
+	})
+
+	t.Run("existing value", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("update resource_blob", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		rsp, err := b.PutResourceBlob(ctx, req)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Equal(t, resource.ContentAddressedBlobUID(key, req.Value), rsp.Uid)
+	})
+
+	t.Run("error saving", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("update resource_blob", 0, 0)
+		b.ExecWithErr("insert resource_blob", errTest)
+		b.SQLMock.ExpectRollback()
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_blob", 3, nil)
+		b.SQLMock.ExpectCommit()
+
+		rsp, err := b.PutResourceBlob(ctx, req)
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+	})
+}
+
+func TestBackend_GarbageCollectBlobs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	blobs := Rows{
+		{"shared", "ns", "a", now.Add(-48 * time.Hour)},
+		{"unused", "ns", "a", now.Add(-48 * time.Hour)},
+		{"recent", "ns", "a", now.Add(-time.Minute)},
+		{"other", "ns", "b", now.Add(-48 * time.Hour)},
+	}
+	history := Rows{
+		{"ns", "a", "shared", 2},
+		// the same uid saved for a different resource is not a reference
+		{"ns", "c", "other", 1},
+	}
+	before := now.Add(-time.Hour)
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource_blob", 4, blobs)
+		b.QueryWithResult("select resource_history", 4, history)
+
+		res, err := b.GarbageCollectBlobs(ctx, "gr", "rs", before, true)
+		require.NoError(t, err)
+		require.Equal(t, resource.BlobGCResult{
+			Blobs:      4,
+			References: 2,
+			Shared:     1,
+			Removed:    2,
+		}, res)
+	})
+
+	t.Run("removes unreferenced blobs", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource_blob", 4, blobs)
+		b.QueryWithResult("select resource_history", 4, history)
+		b.ExecWithResult("delete resource_blob", 0, 1)
+		b.ExecWithResult("delete resource_blob", 0, 0) // saved again after listing
+
+		res, err := b.GarbageCollectBlobs(ctx, "gr", "rs", before, false)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), res.Removed)
+	})
+
+	t.Run("keeps everything when history can not be read", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource_blob", 4, blobs)
+		b.QueryWithErr("select resource_history", errTest)
+
+		_, err := b.GarbageCollectBlobs(ctx, "gr", "rs", before, false)
+		require.ErrorContains(t, err, "count blob references")
+	})
+
+	t.Run("error listing blobs", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithErr("select resource_blob", errTest)
+
+		_, err := b.GarbageCollectBlobs(ctx, "gr", "rs", before, false)
+		require.ErrorContains(t, err, "list blobs")
+	})
+}
+
+func TestBlobUID(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	require.Empty(t, blobUID(obj))
+	require.Empty(t, blobUID(nil))
+
+	obj.SetAnnotations(map[string]string{utils.AnnoKeyBlob: "abc; size=10"})
+	require.Equal(t, "abc", blobUID(obj))
+}
@@ -227,6 +227,7 @@ func (b *backend) processBulk(ctx context.Context, setting resource.BulkSettings
 				},
 				Folder:          req.Folder,
 				GUID:            uuid.New().String(),
+				BlobUID:         blobUID(obj),
 				ResourceVersion: rv.next(obj),
 			}); err != nil {
 				return rollbackWithError(fmt.Errorf("insert into resource history: %w", err))
@@ -0,0 +1,35 @@
+INSERT INTO {{ .Ident "resource_audit" }}
+    (
+        {{ .Ident "guid" }},
+        {{ .Ident "created" }},
+        {{ .Ident "group" }},
+        {{ .Ident "resource" }},
+        {{ .Ident "namespace" }},
+        {{ .Ident "name" }},
+        {{ .Ident "folder" }},
+        {{ .Ident "action" }},
+        {{ .Ident "identity" }},
+        {{ .Ident "previous_resource_version" }},
+        {{ .Ident "resource_version" }},
+        {{ .Ident "origin" }},
+        {{ .Ident "origin_identity" }},
+        {{ .Ident "patch" }}
+    )
+
+    VALUES (
+        {{ .Arg .Event.Guid }},
+        {{ .Arg .Event.Timestamp }},
+        {{ .Arg .Event.Key.Group }},
+        {{ .Arg .Event.Key.Resource }},
+        {{ .Arg .Event.Key.Namespace }},
+        {{ .Arg .Event.Key.Name }},
+        {{ .Arg .Event.Folder }},
+        {{ .Arg .Event.Action }},
+        {{ .Arg .Event.Identity }},
+        {{ .Arg .Event.PreviousResourceVersion }},
+        {{ .Arg .Event.ResourceVersion }},
+        {{ .Arg .Event.Origin }},
+        {{ .Arg .Event.OriginIdentity }},
+        {{ .Arg .Event.Patch }}
+    )
+;
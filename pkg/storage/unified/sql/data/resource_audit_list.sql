@@ -0,0 +1,45 @@
+SELECT
+    {{ .Ident "guid" | .Into .Response.Guid }},
+    {{ .Ident "created" | .Into .Response.Timestamp }},
+    {{ .Ident "group" | .Into .Response.Group }},
+    {{ .Ident "resource" | .Into .Response.Resource }},
+    {{ .Ident "namespace" | .Into .Response.Namespace }},
+    {{ .Ident "name" | .Into .Response.Name }},
+    {{ .Ident "folder" | .Into .Response.Folder }},
+    {{ .Ident "action" | .Into .Response.Action }},
+    {{ .Ident "identity" | .Into .Response.Identity }},
+    {{ .Ident "previous_resource_version" | .Into .Response.PreviousResourceVersion }},
+    {{ .Ident "resource_version" | .Into .Response.ResourceVersion }},
+    {{ .Ident "origin" | .Into .Response.Origin }},
+    {{ .Ident "origin_identity" | .Into .Response.OriginIdentity }},
+    {{ .Ident "patch" | .Into .Response.Patch }}
+    FROM {{ .Ident "resource_audit" }}
+    WHERE 1 = 1
+    AND {{ .Ident "namespace" }} = {{ .Arg .Request.Key.Namespace }}
+    {{ if .Request.Key.Group }}
+    AND {{ .Ident "group" }} = {{ .Arg .Request.Key.Group }}
+    {{ end }}
+    {{ if .Request.Key.Resource }}
+    AND {{ .Ident "resource" }} = {{ .Arg .Request.Key.Resource }}
+    {{ end }}
+    {{ if .Request.Key.Name }}
+    AND {{ .Ident "name" }} = {{ .Arg .Request.Key.Name }}
+    {{ end }}
+    {{ if .Request.Identity }}
+    AND {{ .Ident "identity" }} = {{ .Arg .Request.Identity }}
+    {{ end }}
+    {{ if gt .Request.Since 0 }}
+    AND {{ .Ident "created" }} >= {{ .Arg .Request.Since }}
+    {{ end }}
+    {{ if gt .Request.Until 0 }}
+    AND {{ .Ident "created" }} < {{ .Arg .Request.Until }}
+    {{ end }}
+    {{ if .After }}
+    AND (
+        {{ .Ident "created" }} < {{ .Arg .After.Timestamp }}
+        OR ({{ .Ident "created" }} = {{ .Arg .After.Timestamp }} AND {{ .Ident "guid" }} < {{ .Arg .After.GUID }})
+    )
+    {{ end }}
+    ORDER BY {{ .Ident "created" }} DESC, {{ .Ident "guid" }} DESC
+    LIMIT {{ .Arg .Request.Limit }}
+;
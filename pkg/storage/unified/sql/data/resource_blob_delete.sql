@@ -0,0 +1,5 @@
+DELETE FROM {{ .Ident "resource_blob" }}
+  WHERE 1 = 1
+    AND {{ .Ident "uuid" }}    = {{ .Arg .UID }}
+    AND {{ .Ident "created" }} < {{ .Arg .Before }}
+;
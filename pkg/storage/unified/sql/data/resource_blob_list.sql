@@ -0,0 +1,10 @@
+SELECT
+    {{ .Ident "uuid" | .Into .Response.UID }},
+    {{ .Ident "namespace" | .Into .Response.Namespace }},
+    {{ .Ident "name" | .Into .Response.Name }},
+    {{ .Ident "created" | .Into .Response.Created }}
+  FROM {{ .Ident "resource_blob" }}
+  WHERE 1 = 1
+    AND {{ .Ident "group" }}    = {{ .Arg .Group }}
+    AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+;
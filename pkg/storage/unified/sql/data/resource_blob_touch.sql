@@ -0,0 +1,6 @@
+UPDATE {{ .Ident "resource_blob" }}
+  SET {{ .Ident "created" }} = {{ .Arg .Now }}
+  WHERE 1 = 1
+    AND {{ .Ident "uuid" }}      = {{ .Arg .UID }}
+    AND {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
+;
@@ -0,0 +1,12 @@
+SELECT
+    {{ .Ident "namespace" | .Into .Response.Namespace }},
+    {{ .Ident "name" | .Into .Response.Name }},
+    {{ .Ident "blob_uid" | .Into .Response.UID }},
+    {{ "COUNT(*)" | .Into .Response.Count }}
+  FROM {{ .Ident "resource_history" }}
+  WHERE 1 = 1
+    AND {{ .Ident "group" }}    = {{ .Arg .Group }}
+    AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+    AND {{ .Ident "blob_uid" }} <> ''
+  GROUP BY {{ .Ident "namespace" }}, {{ .Ident "name" }}, {{ .Ident "blob_uid" }}
+;
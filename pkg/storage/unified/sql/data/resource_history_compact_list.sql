@@ -0,0 +1,21 @@
+SELECT
+    {{ .Ident "guid" | .Into .Response.GUID }},
+    {{ .Ident "namespace" | .Into .Response.Namespace }},
+    {{ .Ident "name" | .Into .Response.Name }},
+    {{ .Ident "resource_version" | .Into .Response.ResourceVersion }},
+    {{ .Ident "action" | .Into .Response.Action }},
+    {{ .Ident "value" | .Into .Response.Value }}
+  FROM {{ .Ident "resource_history" }}
+  WHERE 1 = 1
+    AND {{ .Ident "group" }}    = {{ .Arg .Group }}
+    AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+    {{ if .After }}
+    AND (
+      {{ .Ident "namespace" }} > {{ .Arg .After.Namespace }}
+      OR ({{ .Ident "namespace" }} = {{ .Arg .After.Namespace }} AND {{ .Ident "name" }} > {{ .Arg .After.Name }})
+      OR ({{ .Ident "namespace" }} = {{ .Arg .After.Namespace }} AND {{ .Ident "name" }} = {{ .Arg .After.Name }} AND {{ .Ident "resource_version" }} < {{ .Arg .After.ResourceVersion }})
+    )
+    {{ end }}
+  ORDER BY {{ .Ident "namespace" }} ASC, {{ .Ident "name" }} ASC, {{ .Ident "resource_version" }} DESC
+  LIMIT {{ .Arg .Limit }}
+;
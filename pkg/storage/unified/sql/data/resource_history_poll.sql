@@ -16,4 +16,7 @@ SELECT
     AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
     AND {{ .Ident "resource_version" }} > {{ .Arg .SinceResourceVersion }}
     ORDER BY {{ .Ident "resource_version" }} ASC
+    {{ if .Limit }}
+    LIMIT {{ .Arg .Limit }}
+    {{ end }}
 ;
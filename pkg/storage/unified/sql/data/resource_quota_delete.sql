@@ -0,0 +1,6 @@
+DELETE FROM {{ .Ident "resource_quota" }}
+  WHERE 1 = 1
+    AND {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+    AND {{ .Ident "group" }}     = {{ .Arg .Group }}
+    AND {{ .Ident "resource" }}  = {{ .Arg .Resource }}
+;
@@ -0,0 +1,16 @@
+INSERT INTO {{ .Ident "resource_quota" }}
+  (
+    {{ .Ident "namespace" }},
+    {{ .Ident "group" }},
+    {{ .Ident "resource" }},
+    {{ .Ident "max_objects" }},
+    {{ .Ident "max_bytes" }}
+  )
+  VALUES (
+    {{ .Arg .Namespace }},
+    {{ .Arg .Limit.Group }},
+    {{ .Arg .Limit.Resource }},
+    {{ .Arg .Limit.MaxObjects }},
+    {{ .Arg .Limit.MaxBytes }}
+  )
+;
@@ -0,0 +1,9 @@
+SELECT
+    {{ .Ident "group" | .Into .Response.Group }},
+    {{ .Ident "resource" | .Into .Response.Resource }},
+    {{ .Ident "max_objects" | .Into .Response.MaxObjects }},
+    {{ .Ident "max_bytes" | .Into .Response.MaxBytes }}
+  FROM {{ .Ident "resource_quota" }}
+  WHERE {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+  ORDER BY {{ .Ident "group" }} ASC, {{ .Ident "resource" }} ASC
+;
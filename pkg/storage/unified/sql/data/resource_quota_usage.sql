@@ -0,0 +1,15 @@
+{{ $size := printf "OCTET_LENGTH(%s)" (.Ident "value") }}
+{{ if eq .DialectName "sqlite" }}{{ $size = printf "LENGTH(CAST(%s AS BLOB))" (.Ident "value") }}{{ end }}
+SELECT
+    {{ "COUNT(*)" | .Into .Response.Objects }},
+    {{ if .WithBytes }}
+    {{ printf "COALESCE(SUM(%s), 0)" $size | .Into .Response.Bytes }}
+    {{ else }}
+    {{ "0" | .Into .Response.Bytes }}
+    {{ end }}
+  FROM {{ .Ident "resource" }}
+  WHERE 1 = 1
+    AND {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+    AND {{ .Ident "group" }}     = {{ .Arg .Group }}
+    AND {{ .Ident "resource" }}  = {{ .Arg .Resource }}
+;
@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/util/xorm"
+)
+
+// The number of history rows read at once
+const blobReferenceBatchSize = 100
+
+// blobReferenceMigrator sets the blob_uid of the history rows saved before the column was added
+type blobReferenceMigrator struct {
+	migrator.MigrationBase
+}
+
+func (m *blobReferenceMigrator) SQL(dialect migrator.Dialect) string {
+	return `Set blob_uid in resource_history where value LIKE %"grafana.app/blob"%`
+}
+
+func (m *blobReferenceMigrator) Exec(sess *xorm.Session, mg *migrator.Migrator) error {
+	logger := log.New("blob-reference-migrator")
+	type model struct {
+		GUID  string `xorm:"guid"`
+		Value string `xorm:"value"`
+	}
+
+	updated := 0
+	last := ""
+	for {
+		var models []model
+		err := sess.Table("resource_history").
+			Cols("guid", "value").
+			Where("guid > ?", last).
+			And("value LIKE ?", `%"`+utils.AnnoKeyBlob+`"%`).
+			OrderBy("guid").
+			Limit(blobReferenceBatchSize).
+			Find(&models)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range models {
+			last = row.GUID
+			partial := &metav1.PartialObjectMetadata{}
+			if err := json.Unmarshal([]byte(row.Value), partial); err != nil {
+				return fmt.Errorf("unable to read history row %s: %w", row.GUID, err)
+			}
+			info := utils.ParseBlobInfo(partial.GetAnnotations()[utils.AnnoKeyBlob])
+			if info == nil || info.UID == "" {
+				continue
+			}
+			if _, err := sess.Exec("UPDATE "+mg.Dialect.Quote("resource_history")+" SET "+mg.Dialect.Quote("blob_uid")+" = ? WHERE "+mg.Dialect.Quote("guid")+" = ?", info.UID, row.GUID); err != nil {
+				return err
+			}
+			updated++
+		}
+		if len(models) < blobReferenceBatchSize {
+			break
+		}
+	}
+	logger.Info("set blob references", "count", updated)
+	return nil
+}
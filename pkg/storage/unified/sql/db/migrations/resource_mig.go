@@ -167,5 +167,77 @@ func initResourceTables(mg *migrator.Migrator) string {
 		Name: "IDX_resource_history_namespace_group_resource_name_generation",
 	}))
 
+	// Audit trail for every create/update/delete
+	resource_audit_table := migrator.Table{
+		Name: "resource_audit",
+		Columns: []*migrator.Column{
+			{Name: "guid", Type: migrator.DB_NVarchar, Length: 36, Nullable: false, IsPrimaryKey: true},
+			// unix milliseconds
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+
+			{Name: "group", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "resource", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "namespace", Type: migrator.DB_NVarchar, Length: 63, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 253, Nullable: false},
+			{Name: "folder", Type: migrator.DB_NVarchar, Length: 253, Nullable: false, Default: "''"},
+
+			// Type of the watch event (1: added, 2: modified, 3: deleted)
+			{Name: "action", Type: migrator.DB_Int, Nullable: false},
+			{Name: "identity", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "previous_resource_version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "resource_version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "origin", Type: migrator.DB_NVarchar, Length: 63, Nullable: false},
+			{Name: "origin_identity", Type: migrator.DB_NVarchar, Length: 253, Nullable: false, Default: "''"},
+
+			// RFC 6902 JSON patch
+			{Name: "patch", Type: migrator.DB_LongText, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"namespace", "created"}, Type: migrator.IndexType},
+			{Cols: []string{"namespace", "identity", "created"}, Type: migrator.IndexType},
+			{
+				Cols: []string{"namespace", "group", "resource", "name", "created"},
+				Type: migrator.IndexType,
+				Name: "IDX_resource_audit_namespace_group_resource_name_created",
+			},
+		},
+	}
+	mg.AddMigration("create table "+resource_audit_table.Name, migrator.NewAddTableMigration(resource_audit_table))
+	for i := range resource_audit_table.Indices {
+		mg.AddMigration(fmt.Sprintf("create table %s, index: %d", resource_audit_table.Name, i), migrator.NewAddIndexMigration(resource_audit_table, resource_audit_table.Indices[i]))
+	}
+
+	// Quota limits set for a namespace, overriding the configured defaults
+	resource_quota_table := migrator.Table{
+		Name: "resource_quota",
+		Columns: []*migrator.Column{
+			{Name: "namespace", Type: migrator.DB_NVarchar, Length: 63, Nullable: false},
+			{Name: "group", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "resource", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+
+			// Zero is unlimited
+			{Name: "max_objects", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "max_bytes", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"namespace", "group", "resource"}, Type: migrator.UniqueIndex},
+		},
+	}
+	mg.AddMigration("create table "+resource_quota_table.Name, migrator.NewAddTableMigration(resource_quota_table))
+	for i := range resource_quota_table.Indices {
+		mg.AddMigration(fmt.Sprintf("create table %s, index: %d", resource_quota_table.Name, i), migrator.NewAddIndexMigration(resource_quota_table, resource_quota_table.Indices[i]))
+	}
+
+	// Save the blob referenced by each version, so the blob garbage collection can count references
+	mg.AddMigration("Add column blob_uid in resource_history", migrator.NewAddColumnMigration(resource_history_table, &migrator.Column{
+		Name: "blob_uid", Type: migrator.DB_NVarchar, Length: 36, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("Add blob_uid index to resource history", migrator.NewAddIndexMigration(resource_history_table, &migrator.Index{
+		Cols: []string{"group", "resource", "blob_uid"},
+		Type: migrator.IndexType,
+		Name: "IDX_resource_history_group_resource_blob_uid",
+	}))
+	mg.AddMigration("Set blob_uid in resource_history", &blobReferenceMigrator{})
+
 	return marker
 }
@@ -47,6 +47,7 @@ var (
 	sqlResourceHistoryGet               = mustTemplate("resource_history_get.sql")
 	sqlResourceHistoryDelete            = mustTemplate("resource_history_delete.sql")
 	sqlResourceHistoryPrune             = mustTemplate("resource_history_prune.sql")
+	sqlResourceHistoryCompactList       = mustTemplate("resource_history_compact_list.sql")
 	sqlResourceTrash                    = mustTemplate("resource_trash.sql")
 	sqlResourceInsertFromHistory        = mustTemplate("resource_insert_from_history.sql")
 
@@ -56,8 +57,21 @@ var (
 	sqlResourceVersionInsert = mustTemplate("resource_version_insert.sql")
 	sqlResourceVersionList   = mustTemplate("resource_version_list.sql")
 
-	sqlResourceBlobInsert = mustTemplate("resource_blob_insert.sql")
-	sqlResourceBlobQuery  = mustTemplate("resource_blob_query.sql")
+	sqlResourceBlobInsert      = mustTemplate("resource_blob_insert.sql")
+	sqlResourceBlobQuery       = mustTemplate("resource_blob_query.sql")
+	sqlResourceBlobTouch       = mustTemplate("resource_blob_touch.sql")
+	sqlResourceBlobList        = mustTemplate("resource_blob_list.sql")
+	sqlResourceBlobDelete      = mustTemplate("resource_blob_delete.sql")
+	sqlResourceHistoryBlobRefs = mustTemplate("resource_history_blob_refs.sql")
+
+	sqlResourceAuditInsert   = mustTemplate("resource_audit_insert.sql")
+	sqlResourceAuditList     = mustTemplate("resource_audit_list.sql")
+	sqlResourceAuditUpdateRV = mustTemplate("resource_audit_update_rv.sql")
+
+	sqlResourceQuotaUsage  = mustTemplate("resource_quota_usage.sql")
+	sqlResourceQuotaList   = mustTemplate("resource_quota_list.sql")
+	sqlResourceQuotaInsert = mustTemplate("resource_quota_insert.sql")
+	sqlResourceQuotaDelete = mustTemplate("resource_quota_delete.sql")
 )
 
 // TxOptions.
@@ -81,6 +95,9 @@ type sqlResourceRequest struct {
 	Generation int64
 	Folder     string
 
+	// The blob referenced by the value, saved in the history so references can be counted
+	BlobUID string
+
 	// Useful when batch writing
 	ResourceVersion int64
 }
@@ -138,6 +155,7 @@ type sqlResourceHistoryPollRequest struct {
 	Resource             string
 	Group                string
 	SinceResourceVersion int64
+	Limit                int64 // optional
 	Response             *historyPollResponse
 }
 
@@ -334,6 +352,190 @@ func (r *sqlPruneHistoryRequest) Validate() error {
 	return nil
 }
 
+// list the history metadata for applying retention policies
+type sqlHistoryCompactListRequest struct {
+	sqltemplate.SQLTemplate
+	Group    string
+	Resource string
+
+	// Continue after this row, the rows are sorted by namespace/name, newest version first
+	After *historyCompactListResponse
+	Limit int64
+
+	Response *historyCompactListResponse
+}
+
+type historyCompactListResponse struct {
+	GUID            string
+	Namespace       string
+	Name            string
+	ResourceVersion int64
+	Action          int
+	Value           []byte
+}
+
+func (r *sqlHistoryCompactListRequest) Validate() error {
+	if r.Group == "" {
+		return fmt.Errorf("missing group")
+	}
+	if r.Resource == "" {
+		return fmt.Errorf("missing resource")
+	}
+	if r.Limit < 1 {
+		return fmt.Errorf("missing limit")
+	}
+	return nil
+}
+
+func (r *sqlHistoryCompactListRequest) Results() (*historyCompactListResponse, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlResourceAuditInsertRequest struct {
+	sqltemplate.SQLTemplate
+	Event *resourcepb.AuditEvent
+}
+
+func (r sqlResourceAuditInsertRequest) Validate() error {
+	if r.Event == nil || r.Event.Key == nil {
+		return fmt.Errorf("missing event key")
+	}
+	return nil
+}
+
+type sqlResourceAuditListRequest struct {
+	sqltemplate.SQLTemplate
+	Request  *resourcepb.AuditQueryRequest
+	After    *resource.AuditContinueToken // optional, only list the events older than this one
+	Response *auditListResponse
+}
+
+// The protobuf event nests the key, so the row is read into a flat struct
+type auditListResponse struct {
+	Guid                    string
+	Timestamp               int64
+	Group                   string
+	Resource                string
+	Namespace               string
+	Name                    string
+	Folder                  string
+	Action                  int
+	Identity                string
+	PreviousResourceVersion int64
+	ResourceVersion         int64
+	Origin                  string
+	OriginIdentity          string
+	Patch                   sql.NullString
+}
+
+func (r *sqlResourceAuditListRequest) Validate() error {
+	if r.Request == nil || r.Request.Key == nil || r.Request.Key.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	if r.Request.Limit < 1 {
+		return fmt.Errorf("missing limit")
+	}
+	return nil
+}
+
+func (r *sqlResourceAuditListRequest) Results() (*resourcepb.AuditEvent, error) {
+	return &resourcepb.AuditEvent{
+		Guid:      r.Response.Guid,
+		Timestamp: r.Response.Timestamp,
+		Action:    resourcepb.WatchEvent_Type(r.Response.Action),
+		Key: &resourcepb.ResourceKey{
+			Namespace: r.Response.Namespace,
+			Group:     r.Response.Group,
+			Resource:  r.Response.Resource,
+			Name:      r.Response.Name,
+		},
+		Folder:                  r.Response.Folder,
+		Identity:                r.Response.Identity,
+		PreviousResourceVersion: r.Response.PreviousResourceVersion,
+		ResourceVersion:         r.Response.ResourceVersion,
+		Origin:                  r.Response.Origin,
+		OriginIdentity:          r.Response.OriginIdentity,
+		Patch:                   r.Response.Patch.String,
+	}, nil
+}
+
+// the number and total size of the current values in a namespace
+type sqlResourceQuotaUsageRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Group     string
+	Resource  string
+	WithBytes bool // sum the size of the values
+	Response  *resource.QuotaUsage
+}
+
+func (r *sqlResourceQuotaUsageRequest) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	if r.Group == "" || r.Resource == "" {
+		return fmt.Errorf("missing group or resource")
+	}
+	return nil
+}
+
+func (r *sqlResourceQuotaUsageRequest) Results() (*resource.QuotaUsage, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlResourceQuotaListRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Response  *resource.QuotaLimit
+}
+
+func (r *sqlResourceQuotaListRequest) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	return nil
+}
+
+func (r *sqlResourceQuotaListRequest) Results() (*resource.QuotaLimit, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlResourceQuotaInsertRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Limit     resource.QuotaLimit
+}
+
+func (r sqlResourceQuotaInsertRequest) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	if r.Limit.Group == "" || r.Limit.Resource == "" {
+		return fmt.Errorf("missing group or resource")
+	}
+	return nil
+}
+
+type sqlResourceQuotaDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Group     string
+	Resource  string
+}
+
+func (r sqlResourceQuotaDeleteRequest) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	if r.Group == "" || r.Resource == "" {
+		return fmt.Errorf("missing group or resource")
+	}
+	return nil
+}
+
 type sqlResourceBlobInsertRequest struct {
 	sqltemplate.SQLTemplate
 	Now         time.Time
@@ -360,6 +562,95 @@ func (r sqlResourceBlobQueryRequest) Validate() error {
 	return nil
 }
 
+// refresh the created time of an existing blob, so it is not garbage collected
+// before the resource that references it again is saved
+type sqlResourceBlobTouchRequest struct {
+	sqltemplate.SQLTemplate
+	Now time.Time
+	Key *resourcepb.ResourceKey
+	UID string
+}
+
+func (r sqlResourceBlobTouchRequest) Validate() error {
+	if r.UID == "" {
+		return fmt.Errorf("missing uid")
+	}
+	return nil
+}
+
+// list the blob metadata for garbage collection
+type sqlResourceBlobListRequest struct {
+	sqltemplate.SQLTemplate
+	Group    string
+	Resource string
+	Response *blobListResponse
+}
+
+type blobListResponse struct {
+	UID       string
+	Namespace string
+	Name      string
+	Created   time.Time
+}
+
+func (r *sqlResourceBlobListRequest) Validate() error {
+	if r.Group == "" {
+		return fmt.Errorf("missing group")
+	}
+	if r.Resource == "" {
+		return fmt.Errorf("missing resource")
+	}
+	return nil
+}
+
+func (r *sqlResourceBlobListRequest) Results() (*blobListResponse, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlResourceBlobDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	UID    string
+	Before time.Time
+}
+
+func (r sqlResourceBlobDeleteRequest) Validate() error {
+	if r.UID == "" {
+		return fmt.Errorf("missing uid")
+	}
+	return nil
+}
+
+// count the history rows referencing each blob
+type sqlHistoryBlobRefsRequest struct {
+	sqltemplate.SQLTemplate
+	Group    string
+	Resource string
+	Response *historyBlobRefsResponse
+}
+
+type historyBlobRefsResponse struct {
+	Namespace string
+	Name      string
+	UID       string
+	Count     int64
+}
+
+func (r *sqlHistoryBlobRefsRequest) Validate() error {
+	if r.Group == "" {
+		return fmt.Errorf("missing group")
+	}
+	if r.Resource == "" {
+		return fmt.Errorf("missing resource")
+	}
+	return nil
+}
+
+func (r *sqlHistoryBlobRefsRequest) Results() (*historyBlobRefsResponse, error) {
+	x := *r.Response
+	return &x, nil
+}
+
 // update RV
 
 type sqlResourceUpdateRVRequest struct {
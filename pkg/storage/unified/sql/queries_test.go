@@ -144,6 +144,17 @@ func TestUnifiedStorageQueries(t *testing.T) {
 						Response:             new(historyPollResponse),
 					},
 				},
+				{
+					Name: "limit",
+					Data: &sqlResourceHistoryPollRequest{
+						SQLTemplate:          mocks.NewTestingSQLTemplate(),
+						Resource:             "res",
+						Group:                "group",
+						SinceResourceVersion: 1234,
+						Limit:                500,
+						Response:             new(historyPollResponse),
+					},
+				},
 			},
 
 			sqlResourceUpdateRV: {
@@ -231,6 +242,7 @@ func TestUnifiedStorageQueries(t *testing.T) {
 					Data: &sqlResourceRequest{
 						SQLTemplate: mocks.NewTestingSQLTemplate(),
 						Generation:  789,
+						BlobUID:     "blob-uid",
 						WriteEvent: resource.WriteEvent{
 							Key: &resourcepb.ResourceKey{
 								Namespace: "nn",
@@ -318,6 +330,129 @@ func TestUnifiedStorageQueries(t *testing.T) {
 				},
 			},
 
+			sqlResourceHistoryCompactList: {
+				{
+					Name: "simple",
+					Data: &sqlHistoryCompactListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Group:       "dashboard.grafana.app",
+						Resource:    "dashboards",
+						Limit:       500,
+						Response:    new(historyCompactListResponse),
+					},
+				},
+				{
+					Name: "continue",
+					Data: &sqlHistoryCompactListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Group:       "dashboard.grafana.app",
+						Resource:    "dashboards",
+						After: &historyCompactListResponse{
+							Namespace:       "default",
+							Name:            "dash",
+							ResourceVersion: 1234,
+						},
+						Limit:    500,
+						Response: new(historyCompactListResponse),
+					},
+				},
+			},
+
+			sqlResourceHistoryBlobRefs: {
+				{
+					Name: "simple",
+					Data: &sqlHistoryBlobRefsRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Group:       "dashboard.grafana.app",
+						Resource:    "dashboards",
+						Response:    new(historyBlobRefsResponse),
+					},
+				},
+			},
+
+			sqlResourceAuditInsert: {
+				{
+					Name: "simple",
+					Data: sqlResourceAuditInsertRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Event: &resourcepb.AuditEvent{
+							Guid:      "abc",
+							Timestamp: 1000,
+							Action:    resourcepb.WatchEvent_MODIFIED,
+							Key: &resourcepb.ResourceKey{
+								Namespace: "nn",
+								Group:     "gg",
+								Resource:  "rr",
+								Name:      "name",
+							},
+							Identity:                "user:u123",
+							PreviousResourceVersion: 1,
+							ResourceVersion:         2,
+							Origin:                  "ui",
+							Patch:                   `[{"op":"replace","path":"/spec/title","value":"new"}]`,
+						},
+					},
+				},
+			},
+
+			sqlResourceAuditList: {
+				{
+					Name: "namespace",
+					Data: &sqlResourceAuditListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Request: &resourcepb.AuditQueryRequest{
+							Key:   &resourcepb.ResourceKey{Namespace: "nn"},
+							Limit: 100,
+						},
+						Response: new(auditListResponse),
+					},
+				},
+				{
+					Name: "filtered",
+					Data: &sqlResourceAuditListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Request: &resourcepb.AuditQueryRequest{
+							Key: &resourcepb.ResourceKey{
+								Namespace: "nn",
+								Group:     "gg",
+								Resource:  "rr",
+								Name:      "name",
+							},
+							Identity: "user:u123",
+							Since:    1000,
+							Until:    2000,
+							Limit:    10,
+						},
+						Response: new(auditListResponse),
+					},
+				},
+				{
+					Name: "after",
+					Data: &sqlResourceAuditListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Request: &resourcepb.AuditQueryRequest{
+							Key:   &resourcepb.ResourceKey{Namespace: "nn"},
+							Limit: 100,
+						},
+						After:    &resource.AuditContinueToken{Timestamp: 1500, GUID: "guid1"},
+						Response: new(auditListResponse),
+					},
+				},
+			},
+
+			sqlResourceAuditUpdateRV: {
+				{
+					Name: "single path",
+					Data: &sqlResourceUpdateRVRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						GUIDToRV: map[string]int64{
+							"guid1": 123,
+							"guid2": 456,
+						},
+					},
+				},
+			},
+
 			sqlResourceVersionGet: {
 				{
 					Name: "single path",
@@ -447,6 +582,108 @@ func TestUnifiedStorageQueries(t *testing.T) {
 					},
 				},
 			},
+			sqlResourceBlobTouch: {
+				{
+					Name: "basic",
+					Data: &sqlResourceBlobTouchRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Now:         time.UnixMilli(1704056400000).UTC(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "x",
+							Group:     "g",
+							Resource:  "r",
+							Name:      "name",
+						},
+						UID: "abc",
+					},
+				},
+			},
+
+			sqlResourceBlobList: {
+				{
+					Name: "basic",
+					Data: &sqlResourceBlobListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Group:       "g",
+						Resource:    "r",
+						Response:    new(blobListResponse),
+					},
+				},
+			},
+
+			sqlResourceBlobDelete: {
+				{
+					Name: "basic",
+					Data: &sqlResourceBlobDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						UID:         "abc",
+						Before:      time.UnixMilli(1704056400000).UTC(),
+					},
+				},
+			},
+			sqlResourceQuotaUsage: {
+				{
+					Name: "basic",
+					Data: &sqlResourceQuotaUsageRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Group:       "g",
+						Resource:    "r",
+						WithBytes:   true,
+						Response:    new(resource.QuotaUsage),
+					},
+				},
+				{
+					Name: "objects",
+					Data: &sqlResourceQuotaUsageRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Group:       "g",
+						Resource:    "r",
+						Response:    new(resource.QuotaUsage),
+					},
+				},
+			},
+
+			sqlResourceQuotaList: {
+				{
+					Name: "basic",
+					Data: &sqlResourceQuotaListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Response:    new(resource.QuotaLimit),
+					},
+				},
+			},
+
+			sqlResourceQuotaInsert: {
+				{
+					Name: "basic",
+					Data: &sqlResourceQuotaInsertRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Limit: resource.QuotaLimit{
+							Group:      "g",
+							Resource:   "r",
+							MaxObjects: 100,
+							MaxBytes:   1024,
+						},
+					},
+				},
+			},
+
+			sqlResourceQuotaDelete: {
+				{
+					Name: "basic",
+					Data: &sqlResourceQuotaDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Group:       "g",
+						Resource:    "r",
+					},
+				},
+			},
+
 			sqlResourceHistoryDelete: {
 				{
 					Name: "guid",
@@ -0,0 +1,109 @@
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+var _ resource.QuotaBackend = (*backend)(nil)
+
+// GetQuotaUsage implements resource.QuotaBackend.
+func (b *backend) GetQuotaUsage(ctx context.Context, namespace, group, res string, withBytes bool) (resource.QuotaUsage, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"GetQuotaUsage")
+	defer span.End()
+
+	usage, err := dbutil.QueryRow(ctx, b.db, sqlResourceQuotaUsage, &sqlResourceQuotaUsageRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Namespace:   namespace,
+		Group:       group,
+		Resource:    res,
+		WithBytes:   withBytes,
+		Response:    new(resource.QuotaUsage),
+	})
+	if err != nil {
+		return resource.QuotaUsage{}, err
+	}
+	return *usage, nil
+}
+
+// ListQuotaOverrides implements resource.QuotaBackend.
+func (b *backend) ListQuotaOverrides(ctx context.Context, namespace string) ([]resource.QuotaLimit, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ListQuotaOverrides")
+	defer span.End()
+
+	rows, err := dbutil.Query(ctx, b.db, sqlResourceQuotaList, &sqlResourceQuotaListRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Namespace:   namespace,
+		Response:    new(resource.QuotaLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	limits := make([]resource.QuotaLimit, len(rows))
+	for i, row := range rows {
+		limits[i] = *row
+	}
+	return limits, nil
+}
+
+// SetQuotaOverride implements resource.QuotaBackend.
+func (b *backend) SetQuotaOverride(ctx context.Context, namespace string, limit resource.QuotaLimit) error {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"SetQuotaOverride")
+	defer span.End()
+
+	return b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		if _, err := dbutil.Exec(ctx, tx, sqlResourceQuotaDelete, sqlResourceQuotaDeleteRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			Namespace:   namespace,
+			Group:       limit.Group,
+			Resource:    limit.Resource,
+		}); err != nil {
+			return err
+		}
+		_, err := dbutil.Exec(ctx, tx, sqlResourceQuotaInsert, sqlResourceQuotaInsertRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			Namespace:   namespace,
+			Limit:       limit,
+		})
+		return err
+	})
+}
+
+// DeleteQuotaOverride implements resource.QuotaBackend.
+func (b *backend) DeleteQuotaOverride(ctx context.Context, namespace, group, res string) error {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"DeleteQuotaOverride")
+	defer span.End()
+
+	_, err := dbutil.Exec(ctx, b.db, sqlResourceQuotaDelete, sqlResourceQuotaDeleteRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+		Namespace:   namespace,
+		Group:       group,
+		Resource:    res,
+	})
+	return err
+}
+
+// Quotas reads the default quota limits from the per resource config sections,
+// eg [unified_storage.dashboards.dashboard.grafana.app]
+func Quotas(cfg map[string]setting.UnifiedStorageConfig) []resource.QuotaLimit {
+	var limits []resource.QuotaLimit
+	for key, c := range cfg {
+		gr := strings.SplitN(key, ".", 2)
+		if len(gr) != 2 || (c.QuotaMaxObjects <= 0 && c.QuotaMaxBytes <= 0) {
+			continue
+		}
+		limits = append(limits, resource.QuotaLimit{
+			Resource:   gr[0],
+			Group:      gr[1],
+			MaxObjects: c.QuotaMaxObjects,
+			MaxBytes:   c.QuotaMaxBytes,
+		})
+	}
+	return limits
+}
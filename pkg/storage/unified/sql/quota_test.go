@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+func TestBackend_Quotas(t *testing.T) {
+	t.Parallel()
+
+	t.Run("usage", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource", 2, Rows{{int64(3), int64(1024)}})
+
+		usage, err := b.GetQuotaUsage(ctx, "ns", "gr", "rs", true)
+		require.NoError(t, err)
+		require.Equal(t, resource.QuotaUsage{Objects: 3, Bytes: 1024}, usage)
+	})
+
+	t.Run("list overrides", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource_quota", 4, Rows{
+			{"gr", "a", int64(10), int64(0)},
+			{"gr", "b", int64(0), int64(2048)},
+		})
+
+		limits, err := b.ListQuotaOverrides(ctx, "ns")
+		require.NoError(t, err)
+		require.Equal(t, []resource.QuotaLimit{
+			{Group: "gr", Resource: "a", MaxObjects: 10},
+			{Group: "gr", Resource: "b", MaxBytes: 2048},
+		}, limits)
+	})
+
+	t.Run("set override", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource_quota", 0, 1)
+		b.ExecWithResult("insert resource_quota", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		err := b.SetQuotaOverride(ctx, "ns", resource.QuotaLimit{Group: "gr", Resource: "rs", MaxObjects: 5})
+		require.NoError(t, err)
+	})
+
+	t.Run("error setting override", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource_quota", 0, 0)
+		b.ExecWithErr("insert resource_quota", errTest)
+		b.SQLMock.ExpectRollback()
+
+		err := b.SetQuotaOverride(ctx, "ns", resource.QuotaLimit{Group: "gr", Resource: "rs", MaxObjects: 5})
+		require.Error(t, err)
+	})
+
+	t.Run("delete override", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.ExecWithResult("delete resource_quota", 0, 1)
+
+		require.NoError(t, b.DeleteQuotaOverride(ctx, "ns", "gr", "rs"))
+	})
+}
+
+func TestQuotas(t *testing.T) {
+	limits := Quotas(map[string]setting.UnifiedStorageConfig{
+		"dashboards.dashboard.grafana.app": {QuotaMaxObjects: 1000, QuotaMaxBytes: 1 << 20},
+		"folders.folder.grafana.app":       {DualWriterMode: 2},
+		"invalid":                          {QuotaMaxObjects: 1},
+	})
+	require.Equal(t, []resource.QuotaLimit{{
+		Group:      "dashboard.grafana.app",
+		Resource:   "dashboards",
+		MaxObjects: 1000,
+		MaxBytes:   1 << 20,
+	}}, limits)
+}
@@ -0,0 +1,180 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+var _ resource.HistoryCompactionBackend = (*backend)(nil)
+
+// Number of history rows read at once while compacting
+var compactPageSize int64 = 500
+
+// CompactHistory removes the history rows that are not kept by the retention policy.
+// The history is read in pages, and each resource is compacted once all its versions are read.
+func (b *backend) CompactHistory(ctx context.Context, policy resource.RetentionPolicy, now time.Time, dryRun bool) (resource.CompactionResult, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"CompactHistory")
+	defer span.End()
+
+	result := resource.CompactionResult{Group: policy.Group, Resource: policy.Resource}
+
+	// The versions of the resource being read, newest first
+	var current []compactVersion
+	var after *historyCompactListResponse
+	for {
+		rows, err := dbutil.Query(ctx, b.db, sqlResourceHistoryCompactList, &sqlHistoryCompactListRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			Group:       policy.Group,
+			Resource:    policy.Resource,
+			After:       after,
+			Limit:       compactPageSize,
+			Response:    new(historyCompactListResponse),
+		})
+		if err != nil {
+			return result, fmt.Errorf("list history: %w", err)
+		}
+
+		for _, row := range rows {
+			if len(current) > 0 && (row.Namespace != current[0].Namespace || row.Name != current[0].Name) {
+				if err = b.compactVersions(ctx, policy, current, now, dryRun, &result); err != nil {
+					return result, err
+				}
+				current = current[:0]
+			}
+			// only the timestamp is kept from the value
+			current = append(current, compactVersion{
+				GUID:      row.GUID,
+				Namespace: row.Namespace,
+				Name:      row.Name,
+				HistoryVersion: resource.HistoryVersion{
+					ResourceVersion: row.ResourceVersion,
+					Created:         versionTimestamp(row.Value, now),
+					Deleted:         resourcepb.WatchEvent_Type(row.Action) == resourcepb.WatchEvent_DELETED,
+				},
+			})
+		}
+
+		if int64(len(rows)) < compactPageSize {
+			break
+		}
+		after = rows[len(rows)-1]
+	}
+
+	if len(current) > 0 {
+		if err := b.compactVersions(ctx, policy, current, now, dryRun, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+type compactVersion struct {
+	resource.HistoryVersion
+
+	GUID      string
+	Namespace string
+	Name      string
+}
+
+// compactVersions applies the policy to every version of a single resource, newest first
+func (b *backend) compactVersions(ctx context.Context, policy resource.RetentionPolicy, rows []compactVersion, now time.Time, dryRun bool, result *resource.CompactionResult) error {
+	versions := make([]resource.HistoryVersion, len(rows))
+	guids := make(map[int64]string, len(rows))
+	for i, row := range rows {
+		versions[i] = row.HistoryVersion
+		guids[row.ResourceVersion] = row.GUID
+	}
+	result.Resources++
+	result.Versions += int64(len(rows))
+
+	remove, trash := policy.Prune(versions, now)
+	if len(remove) == 0 {
+		return nil
+	}
+	if trash {
+		result.PurgedTrash++
+	} else {
+		result.RemovedVersions += int64(len(remove))
+	}
+	if dryRun {
+		return nil
+	}
+
+	namespace := rows[0].Namespace
+	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		for _, v := range remove {
+			if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryDelete, &sqlResourceHistoryDeleteRequest{
+				SQLTemplate: sqltemplate.New(b.dialect),
+				Namespace:   namespace,
+				GUID:        guids[v.ResourceVersion],
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("delete history: %w", err)
+	}
+	return nil
+}
+
+// versionTimestamp returns when a version was saved, using the timestamps stored in the object:
+// deletes set the deletion timestamp, updates the updated timestamp and creates the creation timestamp.
+// Versions without a readable timestamp are treated as new, so they are never removed because of their age
+func versionTimestamp(value []byte, now time.Time) time.Time {
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(value, obj); err != nil {
+		return now
+	}
+	if obj.DeletionTimestamp != nil {
+		return obj.DeletionTimestamp.Time
+	}
+	if v := obj.Annotations[utils.AnnoKeyUpdatedTimestamp]; v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+	if !obj.CreationTimestamp.IsZero() {
+		return obj.CreationTimestamp.Time
+	}
+	return now
+}
+
+// RetentionPolicies reads the history retention and blob garbage collection policies from the
+// per resource config sections, eg [unified_storage.dashboards.dashboard.grafana.app]
+func RetentionPolicies(cfg map[string]setting.UnifiedStorageConfig) []resource.RetentionPolicy {
+	var policies []resource.RetentionPolicy
+	for key, c := range cfg {
+		gr := strings.SplitN(key, ".", 2)
+		if len(gr) != 2 {
+			continue
+		}
+		p := resource.RetentionPolicy{
+			Resource:        gr[0],
+			Group:           gr[1],
+			KeepVersions:    c.HistoryKeepVersions,
+			KeepNewerThan:   c.HistoryKeepNewerThan,
+			ThinToDaily:     c.HistoryThinToDaily,
+			PurgeTrashAfter: c.TrashPurgeAfter,
+			BlobGracePeriod: c.BlobGCGracePeriod,
+		}
+		if p.Enabled() {
+			policies = append(policies, p)
+		}
+	}
+	return policies
+}
@@ -0,0 +1,119 @@
+package sql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+func TestBackend_CompactHistory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	rv := int64(100)
+	// the saved object, with the timestamps set when it was written
+	value := func(ago time.Duration, action int) []byte {
+		rv--
+		ts := now.Add(-ago).Format(time.RFC3339)
+		switch action {
+		case 1:
+			return []byte(fmt.Sprintf(`{"metadata":{"creationTimestamp":%q}}`, ts))
+		case 3:
+			return []byte(fmt.Sprintf(`{"metadata":{"deletionTimestamp":%q}}`, ts))
+		}
+		return []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, utils.AnnoKeyUpdatedTimestamp, ts))
+	}
+	policy := resource.RetentionPolicy{
+		Group:           "gr",
+		Resource:        "rs",
+		KeepVersions:    2,
+		PurgeTrashAfter: 24 * time.Hour,
+	}
+	rows := Rows{
+		// a: 3 versions, the oldest is removed
+		{"a3", "ns", "a", rv, 2, value(time.Hour, 2)},
+		{"a2", "ns", "a", rv, 2, value(2*time.Hour, 2)},
+		{"a1", "ns", "a", rv, 1, value(3*time.Hour, 1)},
+		// b: deleted two days ago, removed from the trash
+		{"b2", "ns", "b", rv, 3, value(48*time.Hour, 3)},
+		{"b1", "ns", "b", rv, 1, value(72*time.Hour, 1)},
+		// c: deleted recently, without readable timestamps the versions are kept
+		{"c2", "ns", "c", rv, 3, []byte(`{}`)},
+		{"c1", "ns", "c", rv, 1, []byte(`{}`)},
+	}
+
+	t.Run("reads the history in pages", func(t *testing.T) {
+		// not parallel, the page size is shared
+		pageSize := compactPageSize
+		compactPageSize = 3
+		defer func() { compactPageSize = pageSize }()
+
+		b, ctx := setupBackendTest(t)
+		b.QueryWithResult("select resource_history", 6, rows[:3])
+		b.QueryWithResult("select resource_history", 6, rows[3:6])
+		b.QueryWithResult("select resource_history", 6, rows[6:])
+
+		res, err := b.CompactHistory(ctx, policy, now, true)
+		require.NoError(t, err)
+		require.Equal(t, resource.CompactionResult{
+			Group:           "gr",
+			Resource:        "rs",
+			Resources:       3,
+			Versions:        7,
+			RemovedVersions: 1,
+			PurgedTrash:     1,
+		}, res)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource_history", 6, rows)
+
+		res, err := b.CompactHistory(ctx, policy, now, true)
+		require.NoError(t, err)
+		require.Equal(t, resource.CompactionResult{
+			Group:           "gr",
+			Resource:        "rs",
+			Resources:       3,
+			Versions:        7,
+			RemovedVersions: 1,
+			PurgedTrash:     1,
+		}, res)
+	})
+
+	t.Run("removes history", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithResult("select resource_history", 6, rows)
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource_history", 0, 1)
+		b.SQLMock.ExpectCommit()
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource_history", 0, 1)
+		b.ExecWithResult("delete resource_history", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		res, err := b.CompactHistory(ctx, policy, now, false)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), res.RemovedVersions)
+		require.Equal(t, int64(1), res.PurgedTrash)
+	})
+
+	t.Run("error listing history", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.QueryWithErr("select resource_history", errTest)
+
+		_, err := b.CompactHistory(ctx, policy, now, false)
+		require.ErrorContains(t, err, "list history")
+	})
+}
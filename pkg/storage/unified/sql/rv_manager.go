@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -72,6 +73,7 @@ type resourceVersionManager struct {
 
 	maxBatchSize     int           // The maximum number of operations to batch together
 	maxBatchWaitTime time.Duration // The maximum time to wait for a batch to be ready
+	auditRV          bool          // Also update the resource version in the audit events
 }
 
 type writeOpResult struct {
@@ -99,6 +101,7 @@ type ResourceManagerOptions struct {
 	MaxBatchSize     int                 // The maximum number of operations to batch together
 	MaxBatchWaitTime time.Duration       // The maximum time to wait for a batch to be ready
 	Tracer           trace.Tracer        // The tracer to use for tracing
+	UpdateAuditRV    bool                // Also update the resource version of the audit events saved with the writes
 }
 
 // NewResourceVersionManager creates a new ResourceVersionManager
@@ -125,6 +128,7 @@ func NewResourceVersionManager(opts ResourceManagerOptions) (*resourceVersionMan
 		batchChMap:       make(map[string]chan *writeOp),
 		maxBatchSize:     opts.MaxBatchSize,
 		maxBatchWaitTime: opts.MaxBatchWaitTime,
+		auditRV:          opts.UpdateAuditRV,
 	}, nil
 }
 
@@ -318,6 +322,13 @@ func (m *resourceVersionManager) execBatch(ctx context.Context, group, resource
 		}
 		span.AddEvent("resource_history_versions_updated")
 
+		if err := m.updateAuditRV(ctx, tx, guidToRV); err != nil {
+			span.AddEvent("resource_audit_update_rv_failed", trace.WithAttributes(
+				attribute.String("error", err.Error()),
+			))
+			return err
+		}
+
 		// Record the latest RV in the resource version table
 		err = m.saveRV(ctx, tx, group, resource, rv)
 		if err != nil {
@@ -347,6 +358,88 @@ func (m *resourceVersionManager) execBatch(ctx context.Context, group, resource
 	}
 }
 
+// TransactionOp is a single write within ExecTransactionWithRV
+type TransactionOp struct {
+	Key *resourcepb.ResourceKey // The key of the resource
+	Fn  WriteEventFunc          // The function to execute to write the event
+}
+
+// ExecTransactionWithRV executes all operations in a single transaction, and allocates incremented
+// resource versions for each group/resource before committing.  Either every operation is saved or none.
+// The returned resource versions are in the same order as the operations
+func (m *resourceVersionManager) ExecTransactionWithRV(ctx context.Context, ops []TransactionOp) (rvs []int64, err error) {
+	ctx, span := m.tracer.Start(ctx, "sql.rvmanager.ExecTransactionWithRV")
+	defer span.End()
+	span.SetAttributes(attribute.Int("transaction_size", len(ops)))
+
+	ctx, cancel := context.WithTimeout(ctx, defaultBatchTimeout)
+	defer cancel()
+
+	// Group the operations by group/resource, and lock them in a stable order
+	byResource := make(map[string][]int)
+	keys := make([]string, 0)
+	for i, op := range ops {
+		k := op.Key.Group + "/" + op.Key.Resource
+		if _, ok := byResource[k]; !ok {
+			keys = append(keys, k)
+		}
+		byResource[k] = append(byResource[k], i)
+	}
+	sort.Strings(keys)
+
+	rvs = make([]int64, len(ops))
+	err = m.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		guids := make([]string, len(ops))
+		for i, op := range ops {
+			guid, err := op.Fn(tx)
+			if err != nil {
+				return err
+			}
+			guids[i] = guid
+		}
+
+		for _, k := range keys {
+			idx := byResource[k]
+			group, resource := ops[idx[0]].Key.Group, ops[idx[0]].Key.Resource
+			rv, err := m.lock(ctx, tx, group, resource)
+			if err != nil {
+				return fmt.Errorf("failed to increment resource version: %w", err)
+			}
+
+			guidToRV := make(map[string]int64, len(idx))
+			for _, i := range idx {
+				guidToRV[guids[i]] = rv
+				rvs[i] = rv
+				rv++
+			}
+			if _, err := dbutil.Exec(ctx, tx, sqlResourceUpdateRV, sqlResourceUpdateRVRequest{
+				SQLTemplate: sqltemplate.New(m.dialect),
+				GUIDToRV:    guidToRV,
+			}); err != nil {
+				return fmt.Errorf("update resource version: %w", err)
+			}
+			if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryUpdateRV, sqlResourceUpdateRVRequest{
+				SQLTemplate: sqltemplate.New(m.dialect),
+				GUIDToRV:    guidToRV,
+			}); err != nil {
+				return fmt.Errorf("update resource history version: %w", err)
+			}
+			if err := m.updateAuditRV(ctx, tx, guidToRV); err != nil {
+				return err
+			}
+			if err := m.saveRV(ctx, tx, group, resource, rv); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return rvs, nil
+}
+
 // lock locks the resource version for the given key
 func (m *resourceVersionManager) lock(ctx context.Context, x db.ContextExecer, group, resource string) (nextRV int64, err error) {
 	// 1. Lock the row and prevent concurrent updates until the transaction is committed
@@ -387,6 +480,20 @@ func (m *resourceVersionManager) lock(ctx context.Context, x db.ContextExecer, g
 	return max(res.CurrentEpoch, res.ResourceVersion+1), nil
 }
 
+// updateAuditRV sets the resource version of the audit events saved with the writes
+func (m *resourceVersionManager) updateAuditRV(ctx context.Context, x db.ContextExecer, guidToRV map[string]int64) error {
+	if !m.auditRV {
+		return nil
+	}
+	if _, err := dbutil.Exec(ctx, x, sqlResourceAuditUpdateRV, sqlResourceUpdateRVRequest{
+		SQLTemplate: sqltemplate.New(m.dialect),
+		GUIDToRV:    guidToRV,
+	}); err != nil {
+		return fmt.Errorf("update resource audit version: %w", err)
+	}
+	return nil
+}
+
 func (m *resourceVersionManager) saveRV(ctx context.Context, x db.ContextExecer, group, resource string, rv int64) error {
 	_, err := dbutil.Exec(ctx, x, sqlResourceVersionUpdate, sqlResourceVersionUpsertRequest{
 		SQLTemplate:     sqltemplate.New(m.dialect),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/parquet"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 	"github.com/grafana/grafana/pkg/storage/unified/sql/db/dbimpl"
 )
@@ -107,6 +109,7 @@ func NewResourceServer(
 		IsHA:           isHA,
 		withPruner:     withPruner,
 		storageMetrics: opts.StorageMetrics,
+		Audit:          slices.Contains(opts.Cfg.AuditSinks, "sql"),
 	})
 	if err != nil {
 		return nil, err
@@ -120,6 +123,17 @@ func NewResourceServer(
 	serverOptions.Ring = opts.Ring
 	serverOptions.RingLifecycler = opts.RingLifecycler
 	serverOptions.SearchAfterWrite = opts.Features.IsEnabledGlobally(featuremgmt.FlagUnifiedStorageSearchAfterWriteExperimentalAPI)
+	serverOptions.Retention = resource.RetentionOptions{
+		Policies: RetentionPolicies(opts.Cfg.UnifiedStorage),
+		Interval: opts.Cfg.HistoryCompactionInterval,
+		DryRun:   opts.Cfg.HistoryCompactionDryRun,
+	}
+	serverOptions.Quotas = Quotas(opts.Cfg.UnifiedStorage)
+	serverOptions.Backup = parquet.WriteBackup
+	serverOptions.AuditSinks, err = AuditSinks(opts.Cfg, store)
+	if err != nil {
+		return nil, err
+	}
 
 	return resource.NewResourceServer(serverOptions)
 }
@@ -246,6 +246,9 @@ func (s *service) starting(ctx context.Context) error {
 	resourcepb.RegisterManagedObjectIndexServer(srv, server)
 	resourcepb.RegisterBlobStoreServer(srv, server)
 	resourcepb.RegisterDiagnosticsServer(srv, server)
+	resourcepb.RegisterAuditLogServer(srv, server)
+	resourcepb.RegisterResourceQuotasServer(srv, server)
+	resourcepb.RegisterBackupStoreServer(srv, server)
 	grpc_health_v1.RegisterHealthServer(srv, healthService)
 
 	// register reflection service
@@ -0,0 +1,34 @@
+INSERT INTO `resource_audit`
+    (
+        `guid`,
+        `created`,
+        `group`,
+        `resource`,
+        `namespace`,
+        `name`,
+        `folder`,
+        `action`,
+        `identity`,
+        `previous_resource_version`,
+        `resource_version`,
+        `origin`,
+        `origin_identity`,
+        `patch`
+    )
+    VALUES (
+        'abc',
+        1000,
+        'gg',
+        'rr',
+        'nn',
+        'name',
+        '',
+        'MODIFIED',
+        'user:u123',
+        1,
+        2,
+        'ui',
+        '',
+        '[{"op":"replace","path":"/spec/title","value":"new"}]'
+    )
+;
@@ -0,0 +1,21 @@
+SELECT
+    `guid`,
+    `created`,
+    `group`,
+    `resource`,
+    `namespace`,
+    `name`,
+    `folder`,
+    `action`,
+    `identity`,
+    `previous_resource_version`,
+    `resource_version`,
+    `origin`,
+    `origin_identity`,
+    `patch`
+    FROM `resource_audit`
+    WHERE 1 = 1
+    AND `namespace` = 'nn'
+    ORDER BY `created` DESC, `guid` DESC
+    LIMIT 100
+;
@@ -0,0 +1,6 @@
+UPDATE `resource_blob`
+  SET `created` = '2023-12-31 21:00:00 +0000 UTC'
+  WHERE 1 = 1
+    AND `uuid`      = 'abc'
+    AND `namespace` = 'x'
+;
@@ -0,0 +1,12 @@
+SELECT
+    `namespace`,
+    `name`,
+    `blob_uid`,
+    COUNT(*)
+  FROM `resource_history`
+  WHERE 1 = 1
+    AND `group`    = 'dashboard.grafana.app'
+    AND `resource` = 'dashboards'
+    AND `blob_uid` <> ''
+  GROUP BY `namespace`, `name`, `blob_uid`
+;
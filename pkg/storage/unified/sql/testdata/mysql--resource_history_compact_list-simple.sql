@@ -0,0 +1,14 @@
+SELECT
+    `guid`,
+    `namespace`,
+    `name`,
+    `resource_version`,
+    `action`,
+    `value`
+  FROM `resource_history`
+  WHERE 1 = 1
+    AND `group`    = 'dashboard.grafana.app'
+    AND `resource` = 'dashboards'
+  ORDER BY `namespace` ASC, `name` ASC, `resource_version` DESC
+  LIMIT 500
+;
@@ -0,0 +1,6 @@
+DELETE FROM `resource_quota`
+  WHERE 1 = 1
+    AND `namespace` = 'ns'
+    AND `group`     = 'g'
+    AND `resource`  = 'r'
+;
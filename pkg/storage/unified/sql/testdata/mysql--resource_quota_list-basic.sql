@@ -0,0 +1,9 @@
+SELECT
+    `group`,
+    `resource`,
+    `max_objects`,
+    `max_bytes`
+  FROM `resource_quota`
+  WHERE `namespace` = 'ns'
+  ORDER BY `group` ASC, `resource` ASC
+;
@@ -0,0 +1,25 @@
+SELECT
+    "guid",
+    "created",
+    "group",
+    "resource",
+    "namespace",
+    "name",
+    "folder",
+    "action",
+    "identity",
+    "previous_resource_version",
+    "resource_version",
+    "origin",
+    "origin_identity",
+    "patch"
+    FROM "resource_audit"
+    WHERE 1 = 1
+    AND "namespace" = 'nn'
+    AND (
+        "created" < 1500
+        OR ("created" = 1500 AND "guid" < 'guid1')
+    )
+    ORDER BY "created" DESC, "guid" DESC
+    LIMIT 100
+;
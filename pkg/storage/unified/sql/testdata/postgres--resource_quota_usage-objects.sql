@@ -0,0 +1,9 @@
+SELECT
+    COUNT(*),
+    0
+  FROM "resource"
+  WHERE 1 = 1
+    AND "namespace" = 'ns'
+    AND "group"     = 'g'
+    AND "resource"  = 'r'
+;
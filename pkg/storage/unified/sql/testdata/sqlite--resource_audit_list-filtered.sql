@@ -0,0 +1,27 @@
+SELECT
+    "guid",
+    "created",
+    "group",
+    "resource",
+    "namespace",
+    "name",
+    "folder",
+    "action",
+    "identity",
+    "previous_resource_version",
+    "resource_version",
+    "origin",
+    "origin_identity",
+    "patch"
+    FROM "resource_audit"
+    WHERE 1 = 1
+    AND "namespace" = 'nn'
+    AND "group" = 'gg'
+    AND "resource" = 'rr'
+    AND "name" = 'name'
+    AND "identity" = 'user:u123'
+    AND "created" >= 1000
+    AND "created" < 2000
+    ORDER BY "created" DESC, "guid" DESC
+    LIMIT 10
+;
@@ -0,0 +1,5 @@
+DELETE FROM "resource_blob"
+  WHERE 1 = 1
+    AND "uuid"    = 'abc'
+    AND "created" < '2023-12-31 21:00:00 +0000 UTC'
+;
@@ -0,0 +1,10 @@
+SELECT
+    "uuid",
+    "namespace",
+    "name",
+    "created"
+  FROM "resource_blob"
+  WHERE 1 = 1
+    AND "group"    = 'g'
+    AND "resource" = 'r'
+;
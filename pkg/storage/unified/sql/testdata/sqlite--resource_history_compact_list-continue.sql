@@ -0,0 +1,19 @@
+SELECT
+    "guid",
+    "namespace",
+    "name",
+    "resource_version",
+    "action",
+    "value"
+  FROM "resource_history"
+  WHERE 1 = 1
+    AND "group"    = 'dashboard.grafana.app'
+    AND "resource" = 'dashboards'
+    AND (
+      "namespace" > 'default'
+      OR ("namespace" = 'default' AND "name" > 'dash')
+      OR ("namespace" = 'default' AND "name" = 'dash' AND "resource_version" < 1234)
+    )
+  ORDER BY "namespace" ASC, "name" ASC, "resource_version" DESC
+  LIMIT 500
+;
@@ -0,0 +1,16 @@
+INSERT INTO "resource_quota"
+  (
+    "namespace",
+    "group",
+    "resource",
+    "max_objects",
+    "max_bytes"
+  )
+  VALUES (
+    'ns',
+    'g',
+    'r',
+    100,
+    1024
+  )
+;
@@ -0,0 +1,9 @@
+SELECT
+    COUNT(*),
+    COALESCE(SUM(LENGTH(CAST("value" AS BLOB))), 0)
+  FROM "resource"
+  WHERE 1 = 1
+    AND "namespace" = 'ns'
+    AND "group"     = 'g'
+    AND "resource"  = 'r'
+;
@@ -0,0 +1,87 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+var _ resource.WatchReplayBackend = (*backend)(nil)
+
+// The number of history rows read by each replay query
+var replayPageSize int64 = 500
+
+// ReplayEvents replays the history rows written after sinceRV that match the key, oldest first.
+// When the retention removed versions written after sinceRV, the events can not be replayed.
+func (b *backend) ReplayEvents(ctx context.Context, key *resourcepb.ResourceKey, sinceRV int64, fn func(*resource.WrittenEvent) error) error {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ReplayEvents")
+	defer span.End()
+
+	check := resource.NewReplayCheck(sinceRV)
+	for since := sinceRV; ; {
+		records, err := b.historyPage(ctx, key.Group, key.Resource, since, replayPageSize)
+		if err != nil {
+			return err
+		}
+		for _, rec := range records {
+			since = rec.ResourceVersion
+			if (key.Namespace != "" && rec.Key.Namespace != key.Namespace) || (key.Name != "" && rec.Key.Name != key.Name) {
+				continue
+			}
+			prevRV := rec.PreviousRV
+			if prevRV == nil {
+				prevRV = new(int64)
+			}
+			event := &resource.WrittenEvent{
+				Value: rec.Value,
+				Key: &resourcepb.ResourceKey{
+					Namespace: rec.Key.Namespace,
+					Group:     rec.Key.Group,
+					Resource:  rec.Key.Resource,
+					Name:      rec.Key.Name,
+				},
+				Type:            resourcepb.WatchEvent_Type(rec.Action),
+				PreviousRV:      *prevRV,
+				Folder:          rec.Folder,
+				ResourceVersion: rec.ResourceVersion,
+			}
+			if err := check.Check(event); err != nil {
+				return err
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if int64(len(records)) < replayPageSize {
+			return nil
+		}
+	}
+}
+
+// historyPoll lists the history rows of a resource written after since, oldest first.
+func (b *backend) historyPoll(ctx context.Context, grp string, res string, since int64) ([]*historyPollResponse, error) {
+	return b.historyPage(ctx, grp, res, since, 0)
+}
+
+// historyPage lists up to limit history rows of a resource written after since, oldest first.
+// All the rows are listed when the limit is 0.
+func (b *backend) historyPage(ctx context.Context, grp string, res string, since int64, limit int64) ([]*historyPollResponse, error) {
+	var records []*historyPollResponse
+	err := b.db.WithTx(ctx, ReadCommittedRO, func(ctx context.Context, tx db.Tx) error {
+		var err error
+		records, err = dbutil.Query(ctx, tx, sqlResourceHistoryPoll, &sqlResourceHistoryPollRequest{
+			SQLTemplate:          sqltemplate.New(b.dialect),
+			Resource:             res,
+			Group:                grp,
+			SinceResourceVersion: since,
+			Limit:                limit,
+			Response:             &historyPollResponse{},
+		})
+		return err
+	})
+	return records, err
+}
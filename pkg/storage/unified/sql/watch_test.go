@@ -0,0 +1,107 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestBackend_ReplayEvents(t *testing.T) {
+	t.Parallel()
+
+	key := &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs"}
+
+	t.Run("replays the events of the namespace", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 10, Rows{
+			{"guid1", 101, "ns", "gr", "rs", "a", "", []byte("{}"), 1, nil},
+			{"guid2", 102, "other", "gr", "rs", "b", "", []byte("{}"), 1, nil},
+			{"guid3", 103, "ns", "gr", "rs", "a", "folder", []byte("{}"), 2, 101},
+		})
+		b.SQLMock.ExpectCommit()
+
+		var events []*resource.WrittenEvent
+		err := b.ReplayEvents(ctx, key, 100, func(evt *resource.WrittenEvent) error {
+			events = append(events, evt)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, int64(101), events[0].ResourceVersion)
+		require.Equal(t, resourcepb.WatchEvent_ADDED, events[0].Type)
+		require.Equal(t, int64(103), events[1].ResourceVersion)
+		require.Equal(t, resourcepb.WatchEvent_MODIFIED, events[1].Type)
+		require.Equal(t, int64(101), events[1].PreviousRV)
+		require.Equal(t, "folder", events[1].Folder)
+	})
+
+	t.Run("versions removed from the history", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.QueryWithResult("select resource_history", 10, Rows{
+			{"guid1", 101, "ns", "gr", "rs", "a", "", []byte("{}"), 2, 90}, // the previous version is older than since
+			{"guid3", 103, "ns", "gr", "rs", "a", "", []byte("{}"), 2, 102},
+		})
+		b.SQLMock.ExpectCommit()
+
+		var events []*resource.WrittenEvent
+		err := b.ReplayEvents(ctx, key, 100, func(evt *resource.WrittenEvent) error {
+			events = append(events, evt)
+			return nil
+		})
+		require.True(t, apierrors.IsResourceExpired(err), "expected expired error, got %v", err)
+		require.Len(t, events, 1)
+	})
+
+	t.Run("error listing history", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		b.QueryWithErr("select resource_history", errTest)
+		b.SQLMock.ExpectRollback()
+
+		err := b.ReplayEvents(ctx, key, 100, func(*resource.WrittenEvent) error { return nil })
+		require.ErrorIs(t, err, errTest)
+	})
+}
+
+func TestBackend_ReplayEventsPages(t *testing.T) {
+	pageSize := replayPageSize
+	replayPageSize = 2
+	t.Cleanup(func() { replayPageSize = pageSize })
+
+	b, ctx := setupBackendTest(t)
+	key := &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs"}
+
+	b.SQLMock.ExpectBegin()
+	b.QueryWithResult("select resource_history", 10, Rows{
+		{"guid1", 101, "ns", "gr", "rs", "a", "", []byte("{}"), 1, nil},
+		{"guid2", 102, "ns", "gr", "rs", "a", "", []byte("{}"), 2, 101},
+	})
+	b.SQLMock.ExpectCommit()
+	b.SQLMock.ExpectBegin()
+	b.SQLMock.ExpectQuery("select resource_history").WithArgs("gr", "rs", int64(102), int64(2)).
+		WillReturnRows(b.SQLMock.NewRows(make([]string, 10)).AddRows(
+			[]driver.Value{"guid3", 103, "ns", "gr", "rs", "a", "", []byte("{}"), 3, 102},
+		))
+	b.SQLMock.ExpectCommit()
+
+	var rvs []int64
+	err := b.ReplayEvents(ctx, key, 100, func(evt *resource.WrittenEvent) error {
+		rvs = append(rvs, evt.ResourceVersion)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{101, 102, 103}, rvs)
+}
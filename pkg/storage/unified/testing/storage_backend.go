@@ -307,6 +307,45 @@ func runTestIntegrationBackendGetResourceStats(t *testing.T, backend resource.St
 		require.NoError(t, err)
 		require.Empty(t, stats)
 	})
+
+	quotas, ok := backend.(resource.QuotaBackend)
+	if !ok {
+		return
+	}
+
+	t.Run("Get quota usage", func(t *testing.T) {
+		usage, err := quotas.GetQuotaUsage(ctx, nsPrefix+"-stats-ns1", "group", "resource1", true)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), usage.Objects)
+		require.Greater(t, usage.Bytes, int64(0))
+
+		usage, err = quotas.GetQuotaUsage(ctx, nsPrefix+"-stats-ns1", "group", "resource1", false)
+		require.NoError(t, err)
+		require.Equal(t, resource.QuotaUsage{Objects: 2}, usage)
+
+		usage, err = quotas.GetQuotaUsage(ctx, "non-existent", "group", "resource1", true)
+		require.NoError(t, err)
+		require.Equal(t, resource.QuotaUsage{}, usage)
+	})
+
+	t.Run("Set quota overrides", func(t *testing.T) {
+		ns := nsPrefix + "-stats-ns1"
+		require.NoError(t, quotas.SetQuotaOverride(ctx, ns, resource.QuotaLimit{Group: "group", Resource: "resource1", MaxObjects: 5}))
+		require.NoError(t, quotas.SetQuotaOverride(ctx, ns, resource.QuotaLimit{Group: "group", Resource: "resource1", MaxObjects: 10}))
+		require.NoError(t, quotas.SetQuotaOverride(ctx, ns, resource.QuotaLimit{Group: "group", Resource: "resource2", MaxBytes: 1024}))
+
+		limits, err := quotas.ListQuotaOverrides(ctx, ns)
+		require.NoError(t, err)
+		require.Equal(t, []resource.QuotaLimit{
+			{Group: "group", Resource: "resource1", MaxObjects: 10},
+			{Group: "group", Resource: "resource2", MaxBytes: 1024},
+		}, limits)
+
+		require.NoError(t, quotas.DeleteQuotaOverride(ctx, ns, "group", "resource1"))
+		limits, err = quotas.ListQuotaOverrides(ctx, ns)
+		require.NoError(t, err)
+		require.Len(t, limits, 1)
+	})
 }
 
 func runTestIntegrationBackendWatchWriteEvents(t *testing.T, backend resource.StorageBackend, nsPrefix string) {
@@ -1053,6 +1092,18 @@ func runTestIntegrationBlobSupport(t *testing.T, backend resource.StorageBackend
 		require.Nil(t, b2.Error)
 		require.Equal(t, "b0da48de4ff92e0ad0d836de4d746937", b2.Hash)
 
+		// Saving the same value again returns the existing blob
+		b3, err := server.PutBlob(ctx, &resourcepb.PutBlobRequest{
+			Resource:    key,
+			Method:      resourcepb.PutBlobRequest_GRPC,
+			ContentType: "plain/text",
+			Value:       []byte("hello 11111"),
+		})
+		require.NoError(t, err)
+		require.Nil(t, b3.Error)
+		require.Equal(t, b1.Uid, b3.Uid)
+		require.NotEqual(t, b1.Uid, b2.Uid)
+
 		// Check that we can still access both values
 		found, err := store.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: b1.Uid}, true)
 		require.NoError(t, err)
@@ -1089,6 +1140,31 @@ func runTestIntegrationBlobSupport(t *testing.T, backend resource.StorageBackend
 		require.NoError(t, err)
 		require.Nil(t, out.Error)
 		require.Contains(t, string(res.Value), "hello 11111")
+
+		gc, ok := backend.(resource.BlobGarbageCollector)
+		if !ok {
+			return
+		}
+
+		// Only blobs older than the cutoff are removed
+		result, err := gc.GarbageCollectBlobs(ctx, key.Group, key.Resource, time.Now().Add(-time.Hour), false)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), result.Removed)
+
+		// The blob referenced by the saved resource is kept
+		result, err = gc.GarbageCollectBlobs(ctx, key.Group, key.Resource, time.Now().Add(time.Hour), false)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.Removed)
+		require.Equal(t, int64(1), result.References)
+
+		res, err = server.GetBlob(ctx, &resourcepb.GetBlobRequest{Resource: key})
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		require.Contains(t, string(res.Value), "hello 22222")
+
+		res, err = server.GetBlob(ctx, &resourcepb.GetBlobRequest{Resource: key, Uid: b1.Uid})
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
 	})
 }
 
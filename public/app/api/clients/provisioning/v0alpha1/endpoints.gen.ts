@@ -938,6 +938,10 @@ export type DeleteJobOptions = {
   /** Resources to delete This option has been created because currently the frontend does not use standarized app platform APIs. For performance and API consistency reasons, the preferred option is it to use the paths. */
   resources?: ResourceRef[];
 };
+export type DriftJobOptions = {
+  /** Ref to the branch or commit hash to compare (defaults to the configured branch) */
+  ref?: string;
+};
 export type MigrateJobOptions = {
   /** Preserve history (if possible) */
   history?: boolean;
@@ -955,6 +959,8 @@ export type MoveJobOptions = {
   targetPath?: string;
 };
 export type PullRequestJobOptions = {
+  /** The pull request was merged or closed, so its preview environment is removed */
+  closed?: boolean;
   /** The specific commit hash that triggered this notice */
   hash?: string;
   /** Pull request number (when appropriate) */
@@ -981,14 +987,17 @@ export type ExportJobOptions = {
 export type JobSpec = {
   /** Possible enum values:
      - `"delete"` deletes files in the remote repository
+     - `"drift"` compares the repository with the resources in grafana, without applying any change.
      - `"migrate"` acts like JobActionExport, then JobActionPull. It also tries to preserve the history.
      - `"move"` moves files in the remote repository
      - `"pr"` adds additional useful information to a PR, such as comments with preview links and rendered images.
      - `"pull"` replicates the remote branch in the local copy of the repository.
      - `"push"` replicates the local copy of the repository in the remote branch. */
-  action?: 'delete' | 'migrate' | 'move' | 'pr' | 'pull' | 'push';
+  action?: 'delete' | 'drift' | 'migrate' | 'move' | 'pr' | 'pull' | 'push';
   /** Delete when the action is `delete` */
   delete?: DeleteJobOptions;
+  /** Drift when the action is `drift` */
+  drift?: DriftJobOptions;
   /** Required when the action is `migrate` */
   migrate?: MigrateJobOptions;
   /** Move when the action is `move` */
@@ -1160,6 +1169,14 @@ export type BitbucketRepositoryConfig = {
   /** The repository URL (e.g. `https://bitbucket.org/example/test`). */
   url?: string;
 };
+export type BucketRepositoryConfig = {
+  /** The access key ID used to connect to S3. The secret access key is saved in the secure token. */
+  accessKeyId?: string;
+  /** Path is the key prefix for the Grafana data. If specified, Grafana will ignore any object outside this prefix in the bucket. Trailing and leading slash are not required. They are always added when needed. */
+  path?: string;
+  /** The bucket URL, using the gocloud blob URL format (e.g. `s3://bucket?region=us-east-1`). S3 URLs require the `region`, and only accept the `endpoint` and `use_path_style` query parameters besides it. The `endpoint` of S3-compatible stores must be one of the permitted bucket endpoints of the server. Local buckets (`file:///path/to/bucket`) must be inside the permitted provisioning paths. */
+  url: string;
+};
 export type GitRepositoryConfig = {
   /** The branch to use in the repository. */
   branch: string;
@@ -1177,6 +1194,8 @@ export type GitHubRepositoryConfig = {
   branch: string;
   /** Whether we should show dashboard previews for pull requests. By default, this is false (i.e. we will not create previews). */
   generateDashboardPreviews?: boolean;
+  /** Whether we should create a read-only preview folder with the dashboards of each pull request. The folder is removed when the pull request is merged or closed, when this is disabled or when the repository is deleted. By default, this is false (i.e. we will not create preview environments). */
+  generatePreviewEnvironments?: boolean;
   /** Path is the subdirectory for the Grafana data. If specified, Grafana will ignore anything that is outside this directory in the repository. This is usually something like `grafana/`. Trailing and leading slash are not required. They are always added when needed. The path is relative to the root of the repository, regardless of the leading slash.
     
     When specifying something like `grafana-`, we will not look for `grafana-*`; we will only look for files under the directory `/grafana-/`. That means `/grafana-example.json` would not be found. */
@@ -1209,9 +1228,31 @@ export type SyncOptions = {
      - `"instance"` Resources are saved in the global context Only one repository may specify the `instance` target When this exists, the UI will promote writing to the instance repo rather than the grafana database (where possible) */
   target: 'folder' | 'instance';
 };
+export type ValidationPolicies = {
+  /** The datasources (by uid or by type) the dashboards may query. When empty, any datasource is allowed. */
+  allowedDatasources?: string[];
+  /** Panel types (plugin ids) the dashboards may not use */
+  forbiddenPanelTypes?: string[];
+  /** The maximum number of panels in a dashboard, including the panels in collapsed rows */
+  maxPanels?: number;
+  /** The oldest dashboard schema version accepted */
+  minSchemaVersion?: number;
+  /** Regular expression the resource names must match (e.g. `^team-[a-z0-9-]+$`) */
+  namePattern?: string;
+  /** Tags that every dashboard must have */
+  requiredTags?: string[];
+};
+export type CommitVerificationConfig = {
+  /** Refuse to apply the changes of the commits without a verified GPG, SSH or S/MIME signature */
+  requireSignedCommits: boolean;
+  /** When not empty, the commits must also be committed by one of these GitHub users. GitHub checks the signature against the keys of the committer account, so any key added to the account is trusted */
+  trustedSigners?: string[];
+};
 export type RepositorySpec = {
   /** The repository on Bitbucket. Mutually exclusive with local | github | git. */
   bitbucket?: BitbucketRepositoryConfig;
+  /** The repository in an object storage bucket (e.g. S3). Mutually exclusive with local | github | git. */
+  bucket?: BucketRepositoryConfig;
   /** Repository description */
   description?: string;
   /** The repository on Git. Mutually exclusive with local | github | git. */
@@ -1222,6 +1263,8 @@ export type RepositorySpec = {
   gitlab?: GitLabRepositoryConfig;
   /** The repository on the local file system. Mutually exclusive with local | github. */
   local?: LocalRepositoryConfig;
+  /** Validation policies checked before the resources are written. The files that break a policy are not applied. */
+  policies?: ValidationPolicies;
   /** Sync settings -- how values are pulled from the repository into grafana */
   sync: SyncOptions;
   /** The repository display name (shown in the UI) */
@@ -1230,14 +1273,61 @@ export type RepositorySpec = {
     
     Possible enum values:
      - `"bitbucket"`
+     - `"bucket"`
      - `"git"`
      - `"github"`
      - `"gitlab"`
      - `"local"` */
-  type: 'bitbucket' | 'git' | 'github' | 'gitlab' | 'local';
+  type: 'bitbucket' | 'bucket' | 'git' | 'github' | 'gitlab' | 'local';
+  /** Verification of the commit signatures before the changes are applied. Only supported by github repositories. */
+  verification?: CommitVerificationConfig;
   /** UI driven Workflow that allow changes to the contends of the repository. The order is relevant for defining the precedence of the workflows. When empty, the repository does not support any edits (eg, readonly) */
   workflows: ('branch' | 'write')[];
 };
+export type DriftItem = {
+  /** The resource in grafana (not set when missing) */
+  group?: string;
+  name?: string;
+  /** Path to the file in the repository */
+  path: string;
+  /** Why the resource is considered modified */
+  reason?: string;
+  resource?: string;
+  /** The kind of difference
+    
+    Possible enum values:
+     - `"missing"` The file exists in the repository, but the resource is not in grafana
+     - `"modified"` The file and the resource in grafana are different
+     - `"orphaned"` The resource is managed by the repository in grafana, but the file does not exist */
+  type: 'missing' | 'modified' | 'orphaned';
+};
+export type DriftStatus = {
+  /** When the repository was compared */
+  checked?: number;
+  /** The differences found (this may be truncated, the counts are always complete) */
+  items?: DriftItem[];
+  /** The ID for the job that produced this report */
+  job?: string;
+  /** Summary messages (will be shown to users) */
+  message?: string[];
+  /** The number of missing resources */
+  missing: number;
+  /** The number of modified resources */
+  modified: number;
+  /** The number of orphaned resources */
+  orphaned: number;
+  /** The repository ref that was compared */
+  ref?: string;
+  /** The state of the last drift job
+    
+    Possible enum values:
+     - `"error"` Finished with errors
+     - `"pending"` Job has been submitted, but not processed yet
+     - `"success"` Finished with success
+     - `"warning"` Finished with some non-critical errors
+     - `"working"` The job is running */
+  state: 'error' | 'pending' | 'success' | 'warning' | 'working';
+};
 export type HealthStatus = {
   /** When the health was checked last time */
   checked?: number;
@@ -1257,6 +1347,16 @@ export type ResourceCount = {
   group: string;
   resource: string;
 };
+export type SignatureVerification = {
+  /** Why the signature is not verified (e.g. unsigned, unknown_key, untrusted_signer) */
+  reason?: string;
+  /** The verified commit */
+  ref?: string;
+  /** The user who signed the commit */
+  signer?: string;
+  /** The commit has a valid signature, from a trusted signer when the signers are restricted */
+  verified: boolean;
+};
 export type SyncStatus = {
   /** When the sync job finished */
   finished?: number;
@@ -1281,6 +1381,8 @@ export type SyncStatus = {
      - `"warning"` Finished with some non-critical errors
      - `"working"` The job is running */
   state: 'error' | 'pending' | 'success' | 'warning' | 'working';
+  /** The signature verification of the synced commits, when the repository requires signed commits */
+  verification?: SignatureVerification;
 };
 export type WebhookStatus = {
   id?: number;
@@ -1289,6 +1391,8 @@ export type WebhookStatus = {
   url?: string;
 };
 export type RepositoryStatus = {
+  /** The differences found when the drift job last ran */
+  drift?: DriftStatus;
   /** This will get updated with the current health status (and updated periodically) */
   health: HealthStatus;
   /** The generation of the spec last time reconciliation ran */
@@ -1329,11 +1433,12 @@ export type ResourceRepositoryInfo = {
     
     Possible enum values:
      - `"bitbucket"`
+     - `"bucket"`
      - `"git"`
      - `"github"`
      - `"gitlab"`
      - `"local"` */
-  type: 'bitbucket' | 'git' | 'github' | 'gitlab' | 'local';
+  type: 'bitbucket' | 'bucket' | 'git' | 'github' | 'gitlab' | 'local';
 };
 export type Unstructured = {
   [key: string]: any;
@@ -1459,11 +1564,12 @@ export type RepositoryView = {
     
     Possible enum values:
      - `"bitbucket"`
+     - `"bucket"`
      - `"git"`
      - `"github"`
      - `"gitlab"`
      - `"local"` */
-  type: 'bitbucket' | 'git' | 'github' | 'gitlab' | 'local';
+  type: 'bitbucket' | 'bucket' | 'git' | 'github' | 'gitlab' | 'local';
   /** The supported workflows */
   workflows: ('branch' | 'write')[];
 };
@@ -1471,7 +1577,7 @@ export type RepositoryViewList = {
   /** APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources */
   apiVersion?: string;
   /** AvailableRepositoryTypes is the list of repository types supported in this instance (e.g. git, bitbucket, github, etc) */
-  availableRepositoryTypes?: ('bitbucket' | 'git' | 'github' | 'gitlab' | 'local')[];
+  availableRepositoryTypes?: ('bitbucket' | 'bucket' | 'git' | 'github' | 'gitlab' | 'local')[];
   items: RepositoryView[];
   /** Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds */
   kind?: string;
@@ -48,6 +48,23 @@ export function ConfigFormGithubCollapse({ register }: ConfigFormGithubCollapseP
         />
       </Field>
 
+      <Field>
+        <Checkbox
+          disabled={!isPublic}
+          label={t(
+            'provisioning.config-form-github-collapse.label-enable-preview-environments',
+            'Enable preview environments for pull requests'
+          )}
+          description={
+            <Trans i18nKey="provisioning.config-form-github-collapse.description-enable-preview-environments">
+              Creates a read-only folder with the dashboards changed in each pull request, so reviewers can explore them
+              with live data. The folder is removed when the pull request is merged or closed.
+            </Trans>
+          }
+          {...register('generatePreviewEnvironments')}
+        />
+      </Field>
+
       {!isPublic && (
         <Field label={t('provisioning.config-form-github-collapse.label-realtime-feedback', 'Realtime feedback')}>
           <Text variant="bodySmall" color={'secondary'}>
@@ -13,6 +13,7 @@ export function getDefaultValues(repository?: RepositorySpec): RepositoryFormDat
       url: '',
       branch: 'main',
       generateDashboardPreviews: false,
+      generatePreviewEnvironments: false,
       readOnly: false,
       prWorkflow: true,
       path: 'grafana/',
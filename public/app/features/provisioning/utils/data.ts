@@ -21,6 +21,9 @@ export const dataToSpec = (data: RepositoryFormData): RepositorySpec => {
     sync: data.sync,
     title: data.title || '',
     workflows: getWorkflows(data),
+    // Policies and commit verification are not edited in the form, keep the configured ones
+    policies: data.policies,
+    verification: data.verification,
   };
 
   const baseConfig = {
@@ -34,6 +37,7 @@ export const dataToSpec = (data: RepositoryFormData): RepositorySpec => {
       spec.github = {
         ...baseConfig,
         generateDashboardPreviews: data.generateDashboardPreviews,
+        generatePreviewEnvironments: data.generatePreviewEnvironments,
       };
       break;
     case 'gitlab':
@@ -67,6 +71,7 @@ export const specToData = (spec: RepositorySpec): RepositoryFormData => {
     branch: remoteConfig?.branch || '',
     url: remoteConfig?.url || '',
     generateDashboardPreviews: spec.github?.generateDashboardPreviews || false,
+    generatePreviewEnvironments: spec.github?.generatePreviewEnvironments || false,
     readOnly: !spec.workflows.length,
     prWorkflow: spec.workflows.includes('branch'),
   });